@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+var (
+	// Bigfiles command flags
+	bigFilesTop     int
+	bigFilesMinSize string
+)
+
+// newBigFilesCmd creates the bigfiles command
+func newBigFilesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bigfiles [dir...]",
+		Short: "List the largest files in the project folders",
+		Long: `List the largest individual files in the given directories, or in the
+project folders scanned by the cleaners (~/Projects, ~/Code, ~/Developer,
+~/Documents, ...) when none is given. Many space problems are a few huge files
+rather than caches: disk images, logs, archives or videos left in projects.
+Nothing is deleted.`,
+		RunE: runBigFiles,
+	}
+
+	cmd.Flags().IntVar(&bigFilesTop, "top", 50, "Number of files to list")
+	cmd.Flags().StringVar(&bigFilesMinSize, "min-size", "500MB", "Only list files of at least this size")
+	cmd.Flags().IntVar(&scanMaxDepth, "max-depth", scanner.DefaultMaxDepth, "Directory levels to scan below each folder (0 = no limit)")
+	cmd.Flags().StringSliceVar(&scanExcludes, "exclude", []string{}, "Paths or directory names to skip (comma-separated)")
+
+	return cmd
+}
+
+// runBigFiles executes the bigfiles command
+func runBigFiles(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	rep := newReporter()
+
+	rep.PrintHeader()
+
+	minSize, err := humanize.ParseBytes(bigFilesMinSize)
+	if err != nil {
+		err = fmt.Errorf("invalid --min-size value: %s", bigFilesMinSize)
+		rep.PrintError(err.Error())
+		return err
+	}
+	if bigFilesTop < 1 {
+		err := fmt.Errorf("invalid --top value: %d (must be at least 1)", bigFilesTop)
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	cfg.ScanMaxDepth = scanMaxDepth
+	cfg.ScanExcludes = append(cfg.ScanExcludes, scanExcludes...)
+
+	dirs := []string{}
+	for _, arg := range args {
+		dir, err := utils.ExpandHome(arg)
+		if err != nil {
+			rep.PrintError(err.Error())
+			return err
+		}
+		dirs = append(dirs, dir)
+	}
+
+	rep.PrintInfo(fmt.Sprintf("Looking for files of %s or more...", utils.FormatBytes(int64(minSize))))
+	files, err := cleaner.FindLargestFiles(ctx, cfg, dirs, int64(minSize), bigFilesTop)
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	rep.PrintLargestFiles(files, int64(minSize))
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+// discoverTimeout bounds the time spent sampling the home directory
+const discoverTimeout = 15 * time.Second
+
+// newDiscoverCmd creates the discover command
+func newDiscoverCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Find the folders of your projects to scan",
+		Long: fmt.Sprintf(`Sample the home directory for folders holding at least %d git repositories
+or projects (up to %d levels down) that are not scanned yet, beyond the
+default ones (Projects, Code, Development, Developer, Documents, Desktop).
+Each folder you approve is saved to search_dirs in the config file and
+scanned by every command from then on.`, scanner.DiscoveryMinProjects, scanner.DiscoveryMaxDepth),
+		Args: cobra.NoArgs,
+		RunE: runDiscover,
+	}
+
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", true, "Ask before adding each folder")
+	cmd.Flags().StringSliceVar(&scanExcludes, "exclude", []string{}, "Paths or directory names to skip when scanning projects (comma-separated)")
+
+	return cmd
+}
+
+// runDiscover executes the discover command
+func runDiscover(cmd *cobra.Command, args []string) error {
+	rep := newReporter()
+
+	rep.PrintHeader()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	path, err := config.FilePath()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	s, err := scanner.NewScanner()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	for _, dir := range cfg.SearchDirs {
+		s.AddSearchDir(dir)
+	}
+	s.SetExcludes(append(cfg.ScanExcludes, scanExcludes...))
+
+	rep.PrintInfo("Looking for folders of projects...")
+	ctx, cancel := context.WithTimeout(context.Background(), discoverTimeout)
+	candidates := s.DiscoverSearchDirs(ctx)
+	cancel()
+
+	if len(candidates) == 0 {
+		rep.PrintSuccess("No other folder of projects found")
+		return nil
+	}
+
+	approved := cfg.SearchDirs
+	added := 0
+	for _, candidate := range candidates {
+		message := fmt.Sprintf("%s holds %d projects. Scan it?", candidate.Path, candidate.Projects)
+		if interactive && !rep.AskConfirmation(message) {
+			continue
+		}
+		approved = append(approved, candidate.Path)
+		added++
+	}
+
+	if added == 0 {
+		rep.PrintInfo(lang.T("prompt.cancelled"))
+		return nil
+	}
+	if err := config.SaveSearchDirs(path, approved); err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	rep.PrintSuccess(fmt.Sprintf("%d folders added to search_dirs in %s", added, path))
+	return nil
+}
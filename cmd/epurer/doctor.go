@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/diagnostics"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// newDoctorCmd creates the doctor command
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that epurer can clean everything on this Mac",
+		Long: `Check the environment epurer runs in: Full Disk Access for the terminal,
+the external commands cleaners rely on (brew, docker, xcrun, tmutil), that
+the main cache folders are writable, that the config file is valid, and that
+no interrupted clean was left behind. Each problem comes with a fix. Exits
+with an error if a check fails.`,
+		Args: cobra.NoArgs,
+		RunE: runDoctor,
+	}
+}
+
+// runDoctor executes the doctor command
+func runDoctor(cmd *cobra.Command, args []string) error {
+	rep := newReporter()
+
+	rep.PrintHeader()
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	stateDir, err := config.StateDir()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	checks := diagnostics.Doctor{
+		Home:         home,
+		StateDir:     stateDir,
+		PolicyPath:   config.PolicyFilePath(),
+		GOOS:         runtime.GOOS,
+		CommandFound: utils.CommandExists,
+		Now:          time.Now(),
+	}.Run()
+	rep.PrintDoctorChecks(checks)
+
+	failed := 0
+	for _, check := range checks {
+		if check.Status == diagnostics.Fail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d doctor checks failed", failed)
+	}
+	return nil
+}
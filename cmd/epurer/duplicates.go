@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/platform"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+var (
+	// Duplicates command flags
+	duplicatesMinSize string
+	cloneDuplicates   bool
+)
+
+// newDuplicatesCmd creates the duplicates command
+func newDuplicatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "duplicates [dir...]",
+		Short: "Find identical large files in the project folders",
+		Long: `Find files with identical contents in the given directories, or in the
+project folders scanned by the cleaners when none is given. Files of the same
+size are compared by a hash of their first and last 64 KiB, then by a hash of
+their whole contents. Nothing is deleted: with --clone, every copy but one is
+replaced by an APFS clone of it, which shares its disk blocks until one of
+them is modified.`,
+		RunE: runDuplicates,
+	}
+
+	cmd.Flags().StringVar(&duplicatesMinSize, "min-size", "10MB", "Only compare files of at least this size")
+	cmd.Flags().BoolVar(&cloneDuplicates, "clone", false, "Replace duplicates with APFS clones of the first copy (macOS only)")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", true, "Ask for confirmation before replacing files")
+	cmd.Flags().IntVar(&scanMaxDepth, "max-depth", scanner.DefaultMaxDepth, "Directory levels to scan below each folder (0 = no limit)")
+	cmd.Flags().StringSliceVar(&scanExcludes, "exclude", []string{}, "Paths or directory names to skip (comma-separated)")
+
+	return cmd
+}
+
+// runDuplicates executes the duplicates command
+func runDuplicates(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	rep := newReporter()
+
+	rep.PrintHeader()
+
+	// cp -c makes the clones, a flag of the macOS cp only
+	if cloneDuplicates && !platform.IsMacOS() {
+		err := fmt.Errorf("--clone is only available on macOS, where APFS clones files")
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	minSize, err := humanize.ParseBytes(duplicatesMinSize)
+	if err != nil {
+		err = fmt.Errorf("invalid --min-size value: %s", duplicatesMinSize)
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	cfg.ScanMaxDepth = scanMaxDepth
+	cfg.ScanExcludes = append(cfg.ScanExcludes, scanExcludes...)
+
+	dirs := []string{}
+	for _, arg := range args {
+		dir, err := utils.ExpandHome(arg)
+		if err != nil {
+			rep.PrintError(err.Error())
+			return err
+		}
+		dirs = append(dirs, dir)
+	}
+
+	rep.PrintInfo(fmt.Sprintf("Comparing files of %s or more...", utils.FormatBytes(int64(minSize))))
+	groups, err := cleaner.FindDuplicateFiles(ctx, cfg, dirs, int64(minSize))
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	rep.PrintDuplicateFiles(groups)
+	if !cloneDuplicates || len(groups) == 0 {
+		return nil
+	}
+
+	var savings int64
+	for _, group := range groups {
+		savings += group.Savings()
+	}
+	if interactive && !rep.AskConfirmation(fmt.Sprintf("Replace the duplicates with APFS clones to free %s?", utils.FormatBytes(savings))) {
+		rep.PrintInfo(lang.T("prompt.cancelled"))
+		return nil
+	}
+
+	var freed int64
+	for _, group := range groups {
+		groupFreed, err := cleaner.CloneDuplicates(cleaner.ExecRunner{}, group)
+		freed += groupFreed
+		if err != nil {
+			rep.PrintWarning(err.Error())
+		}
+	}
+
+	rep.PrintSuccess(fmt.Sprintf("Freed %s by cloning duplicates", utils.FormatBytes(freed)))
+	return nil
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/disk"
+	"github.com/0SansNom/epurer/internal/history"
+	"github.com/0SansNom/epurer/internal/hooks"
+	"github.com/0SansNom/epurer/internal/plan"
+	"github.com/0SansNom/epurer/internal/quarantine"
+	"github.com/0SansNom/epurer/internal/reporter"
+	"github.com/0SansNom/epurer/internal/webhook"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// exitCodeInterrupted is the exit code of a run stopped with Ctrl+C (128 + SIGINT)
+const exitCodeInterrupted = 130
+
+// errInterrupted is returned by commands whose cleanup was interrupted
+var errInterrupted = errors.New("interrupted")
+
+// executeClean cleans the pending items of a plan with up to workers
+// cleaners running at once (each cleaner's targets one at a time), showing
+// live progress, down to the files of large targets being deleted, and the
+// time left at the pace of the bytes cleaned so far. Each outcome is
+// recorded in the plan as it comes in and save (if set) is called after
+// every target. Ctrl+C (or SIGTERM) cancels the run: deletions in flight are
+// allowed to finish and the remaining items stay pending. Results and errors
+// are reported in plan order.
+func executeClean(ctx context.Context, rep *reporter.Reporter, cleaners []cleaner.Cleaner, p *plan.Plan, dryRun bool, workers int, save func(*plan.Plan) error) ([]cleaner.CleanResult, []history.Result, bool) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cleanersByName := make(map[string]cleaner.Cleaner, len(cleaners))
+	for _, c := range cleaners {
+		cleanersByName[c.Name()] = c
+	}
+
+	pending := p.Pending()
+	outcomes := make(map[int]cleaner.CleanResult, len(pending))
+	saveFailed := false
+	record := func(i int, result cleaner.CleanResult) {
+		p.Record(i, result)
+		outcomes[i] = result
+
+		if save != nil && !saveFailed {
+			if err := save(p); err != nil {
+				rep.PrintWarning(fmt.Sprintf("Failed to save run progress: %v", err))
+				saveFailed = true
+			}
+		}
+	}
+
+	// Items of cleaners that are not available fail without being cleaned
+	jobs := []cleaner.CleanJob{}
+	jobItems := []int{}
+	for _, i := range pending {
+		item := p.Items[i]
+		c, ok := cleanersByName[item.Cleaner]
+		if !ok {
+			record(i, cleaner.CleanResult{Target: item.Target(), Error: fmt.Errorf("unknown cleaner %q", item.Cleaner)})
+			continue
+		}
+		jobs = append(jobs, cleaner.CleanJob{Cleaner: c, Target: item.Target()})
+		jobItems = append(jobItems, i)
+	}
+
+	if len(jobs) > 0 {
+		rep.PrintProgress(0, len(jobs), "Cleaning")
+		done := 0
+		// The time left is estimated from the bytes cleaned per second, large
+		// targets counting for the share of their files already deleted
+		var totalBytes, doneBytes int64
+		for _, job := range jobs {
+			totalBytes += job.Target.SizeBytes
+		}
+		removing := make(map[string]int64)
+		started := time.Now()
+		remaining := func() time.Duration {
+			cleaned := doneBytes
+			for _, bytes := range removing {
+				cleaned += bytes
+			}
+			eta, _ := utils.EstimateRemaining(time.Since(started), cleaned, totalBytes)
+			return eta
+		}
+
+		// Large targets show their removal on the progress line until the
+		// next target is done
+		var mu sync.Mutex
+		deleting := false
+		removeCtx := cleaner.WithRemoveProgress(ctx, func(progress cleaner.RemoveProgress) {
+			mu.Lock()
+			defer mu.Unlock()
+			removing[progress.Target.Path] = int64(progress.Fraction() * float64(progress.Target.SizeBytes))
+			eta, _ := progress.Remaining()
+			rep.PrintProgressETA(progress.Files, progress.Total, "Deleting "+filepath.Base(progress.Target.Path), eta)
+			deleting = progress.Files < progress.Total
+		})
+		cleaner.CleanConcurrently(removeCtx, jobs, workers, dryRun, func(r cleaner.JobResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			if deleting {
+				rep.EndProgress()
+				deleting = false
+			}
+			record(jobItems[r.Index], r.Result)
+			done++
+			delete(removing, jobs[r.Index].Target.Path)
+			doneBytes += jobs[r.Index].Target.SizeBytes
+			rep.PrintProgressETA(done, len(jobs), "Cleaning", remaining())
+		})
+		if done < len(jobs) {
+			rep.EndProgress()
+		}
+	}
+
+	allResults := []cleaner.CleanResult{}
+	records := []history.Result{}
+	for _, i := range pending {
+		result, ok := outcomes[i]
+		if !ok {
+			continue
+		}
+		if !result.Success && result.Error != nil && ctx.Err() == nil {
+			rep.PrintWarning(fmt.Sprintf("Error cleaning %s: %v", result.Target.Path, result.Error))
+		}
+		allResults = append(allResults, result)
+		records = append(records, history.NewResult(p.Items[i].Cleaner, result))
+	}
+
+	return allResults, records, ctx.Err() != nil
+}
+
+// cleanPlan executes a plan and prints the results. Unless dryRun is set, the
+// plan is saved to manifestPath before the first deletion and after each
+// target, so an interrupted run can be finished with `clean --resume`. The
+// manifest is removed once every target has been processed. Real runs also
+// print the disk usage before and after cleaning, then review the local Time
+// Machine snapshots still holding the freed space. The configured hooks run
+// before and after cleaning, and a failing pre_clean hook that aborts on
+// failure stops the run before anything is touched. With cfg.Quarantine set,
+// targets are moved to the quarantine instead of deleted.
+func cleanPlan(ctx context.Context, cmd *cobra.Command, rep *reporter.Reporter, cleaners []cleaner.Cleaner, p *plan.Plan, command, manifestPath string, dryRun bool, workers int, cfg *config.Config) error {
+	startedAt := time.Now()
+	h := &hookRunner{hooks: cfg.Hooks, rep: rep, dryRun: dryRun}
+	if !h.run(ctx, "pre_clean", cfg.Hooks.PreClean) {
+		run := recordRun(rep, command, startedAt, nil, h.results, false, dryRun)
+		sendSummary(ctx, rep, cfg.Webhook, run, dryRun)
+		err := errors.New("the pre_clean hook failed, nothing was cleaned")
+		rep.PrintError(err.Error())
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return err
+	}
+	cleanerNames := pendingCleaners(p)
+	cleaners = h.before(ctx, cleaners, p)
+
+	var store *quarantine.Store
+	if cfg.Quarantine && !dryRun {
+		dir, err := quarantine.DefaultDir()
+		if err != nil {
+			rep.PrintError(err.Error())
+			return err
+		}
+		store = quarantine.New(dir)
+	}
+
+	var save func(*plan.Plan) error
+	if !dryRun && manifestPath != "" {
+		save = func(p *plan.Plan) error {
+			return plan.Save(manifestPath, p)
+		}
+		if err := save(p); err != nil {
+			rep.PrintWarning(fmt.Sprintf("Failed to write run manifest, this run can't be resumed: %v", err))
+			save = nil
+		}
+	}
+
+	var before *disk.Usage
+	var volumes map[string]utils.Volume
+	if !dryRun {
+		before = volumeUsage(ctx)
+		if before != nil {
+			rep.PrintDiskSummary(*before, nil, 0)
+		}
+		volumes = volumesBefore(cfg)
+	}
+
+	cleanCtx := ctx
+	if store != nil {
+		cleanCtx = cleaner.WithQuarantine(ctx, store)
+	}
+	allResults, records, interrupted := executeClean(cleanCtx, rep, cleaners, p, dryRun, workers, save)
+
+	// Print results, partial if interrupted, and what is left of them on disk
+	rep.PrintCleanResults(allResults, dryRun)
+	if !dryRun {
+		rep.PrintVerification(cleaner.Verify(allResults))
+	}
+	if before != nil {
+		if after := volumeUsage(ctx); after != nil {
+			rep.PrintDiskSummary(*before, after, bytesFreed(allResults))
+		}
+	}
+	if !dryRun {
+		printVolumesAfter(rep, volumes)
+	}
+	if store != nil {
+		rep.PrintInfo("Deleted targets were moved to the quarantine: run `epurer restore` to bring them back, `epurer quarantine purge --older-than 7d` to reclaim the space")
+		expireQuarantine(rep, store, cfg)
+	}
+	h.after(ctx, cleanerNames, bytesFreed(allResults))
+	run := recordRun(rep, command, startedAt, records, h.results, interrupted, dryRun)
+	sendSummary(ctx, rep, cfg.Webhook, run, dryRun)
+
+	if interrupted {
+		if save != nil {
+			rep.PrintInfo("Run `epurer clean --resume` to clean the remaining targets")
+		}
+		return interruptedError(cmd, rep)
+	}
+
+	if save != nil {
+		os.Remove(manifestPath)
+	}
+
+	if !dryRun && anySucceeded(allResults) {
+		reviewSnapshots(ctx, rep)
+	}
+
+	return nil
+}
+
+// volumeUsage returns the space usage of the startup volume, or nil if
+// diskutil can't report it
+func volumeUsage(ctx context.Context) *disk.Usage {
+	if !disk.DiskutilAvailable() {
+		return nil
+	}
+
+	usage, err := disk.VolumeUsage(ctx, startupVolume)
+	if err != nil {
+		return nil
+	}
+	return &usage
+}
+
+// bytesFreed returns the space freed by the results, including what targets
+// that failed part-way freed
+func bytesFreed(results []cleaner.CleanResult) int64 {
+	var total int64
+	for _, result := range results {
+		total += result.BytesFreed
+	}
+	return total
+}
+
+// anySucceeded reports whether at least one target was cleaned
+func anySucceeded(results []cleaner.CleanResult) bool {
+	for _, result := range results {
+		if result.Success {
+			return true
+		}
+	}
+	return false
+}
+
+// resumeClean finishes the run recorded in the run manifest without scanning
+// again, running the configured hooks around it
+func resumeClean(ctx context.Context, cmd *cobra.Command, rep *reporter.Reporter, cleaners []cleaner.Cleaner, cfg *config.Config) error {
+	manifestPath, err := plan.ManifestPath()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	p, err := plan.Load(manifestPath)
+	if os.IsNotExist(err) {
+		rep.PrintInfo("No interrupted run to resume")
+		return nil
+	}
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	pending := len(p.Pending())
+	rep.PrintInfo(fmt.Sprintf("Resuming run from %s: %d of %d targets left", p.CreatedAt.Format("2006-01-02 15:04"), pending, len(p.Items)))
+
+	if pending == 0 {
+		os.Remove(manifestPath)
+		rep.PrintInfo("Nothing left to clean!")
+		return nil
+	}
+
+	if interactive && !dryRun {
+		if !rep.AskConfirmation(lang.T("prompt.proceed_remaining", pending)) {
+			rep.PrintInfo(lang.T("prompt.cancelled"))
+			return nil
+		}
+	}
+
+	if dryRun {
+		rep.PrintInfo("DRY RUN - No files will be deleted")
+	}
+
+	return cleanPlan(ctx, cmd, rep, cleaners, p, "clean", manifestPath, dryRun, jobs, cfg)
+}
+
+// recordRun appends a finished or interrupted run to the history file, with
+// the results of its hooks, and returns it. Dry runs remove nothing and are
+// not recorded.
+func recordRun(rep *reporter.Reporter, command string, startedAt time.Time, records []history.Result, hookResults []hooks.Result, interrupted, dryRun bool) history.Run {
+	run := history.Run{
+		StartedAt:   startedAt,
+		Duration:    time.Since(startedAt),
+		Command:     command,
+		Interrupted: interrupted,
+		Results:     records,
+		Hooks:       hookResults,
+	}
+	if dryRun {
+		return run
+	}
+
+	path, err := history.DefaultPath()
+	if err == nil {
+		err = history.Append(path, run)
+	}
+
+	if err != nil {
+		rep.PrintWarning(fmt.Sprintf("Failed to record run history: %v", err))
+	}
+	return run
+}
+
+// sendSummary posts the summary of a run to the configured webhook, if any.
+// Dry runs are not sent, and failing to send only warns.
+func sendSummary(ctx context.Context, rep *reporter.Reporter, hook config.Webhook, run history.Run, dryRun bool) {
+	if dryRun || hook.URL == "" {
+		return
+	}
+
+	host, _ := os.Hostname()
+	user := os.Getenv("USER")
+	summary := webhook.NewSummary(run, host, user, version)
+	if err := webhook.Post(ctx, http.DefaultClient, hook.URL, hook.Format, summary); err != nil {
+		rep.PrintWarning(fmt.Sprintf("Failed to send the run summary: %v", err))
+	}
+}
+
+// interruptedError reports an interrupted run and returns the error that
+// makes main exit with exitCodeInterrupted
+func interruptedError(cmd *cobra.Command, rep *reporter.Reporter) error {
+	rep.PrintWarning("Interrupted - remaining targets were skipped")
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return errInterrupted
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/hooks"
+	"github.com/0SansNom/epurer/internal/plan"
+	"github.com/0SansNom/epurer/internal/reporter"
+)
+
+// hookRunner runs the hooks of a clean run and collects their results for
+// the run history. Dry runs only print the hooks they would run.
+type hookRunner struct {
+	hooks   config.Hooks
+	rep     *reporter.Reporter
+	dryRun  bool
+	results []hooks.Result
+}
+
+// run runs a hook, if set, and reports whether the run may go on: false if
+// it failed and aborts on failure
+func (h *hookRunner) run(ctx context.Context, name string, hook config.Hook, env ...string) bool {
+	if hook.Command == "" {
+		return true
+	}
+	if h.dryRun {
+		h.rep.PrintInfo(fmt.Sprintf("Would run the %s hook: %s", name, hook.Command))
+		return true
+	}
+
+	h.rep.PrintInfo(fmt.Sprintf("Running the %s hook...", name))
+	result := hooks.Run(ctx, name, hook, env...)
+	h.results = append(h.results, result)
+	if !result.Failed() {
+		return true
+	}
+
+	h.rep.PrintWarning(fmt.Sprintf("The %s hook failed: %s", name, result.Error))
+	return !hook.AbortOnFailure
+}
+
+// before runs the pre hook of each cleaner with targets left in the plan.
+// It returns the cleaners to clean with, in which those whose pre hook
+// failed and aborts fail their targets instead.
+func (h *hookRunner) before(ctx context.Context, cleaners []cleaner.Cleaner, p *plan.Plan) []cleaner.Cleaner {
+	skipped := make(map[string]bool)
+	for _, name := range pendingCleaners(p) {
+		if !h.run(ctx, "pre "+name, h.hooks.Cleaners[name].Pre, "EPURER_CLEANER="+name) {
+			skipped[name] = true
+		}
+	}
+	if len(skipped) == 0 {
+		return cleaners
+	}
+
+	wrapped := make([]cleaner.Cleaner, 0, len(cleaners))
+	for _, c := range cleaners {
+		if skipped[c.Name()] {
+			c = skippedCleaner{Cleaner: c, err: fmt.Errorf("skipped: the pre %s hook failed", c.Name())}
+		}
+		wrapped = append(wrapped, c)
+	}
+	return wrapped
+}
+
+// after runs the post hook of each cleaner of the plan, then the post_clean
+// hook with the bytes freed in EPURER_BYTES_FREED
+func (h *hookRunner) after(ctx context.Context, cleanerNames []string, freed int64) {
+	for _, name := range cleanerNames {
+		h.run(ctx, "post "+name, h.hooks.Cleaners[name].Post, "EPURER_CLEANER="+name)
+	}
+	h.run(ctx, "post_clean", h.hooks.PostClean, "EPURER_BYTES_FREED="+strconv.FormatInt(freed, 10))
+}
+
+// pendingCleaners returns the names of the cleaners with targets left in a
+// plan, in plan order
+func pendingCleaners(p *plan.Plan) []string {
+	names := []string{}
+	seen := make(map[string]bool)
+	for _, i := range p.Pending() {
+		name := p.Items[i].Cleaner
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// skippedCleaner fails every target of a cleaner without touching it
+type skippedCleaner struct {
+	cleaner.Cleaner
+	err error
+}
+
+func (s skippedCleaner) Clean(ctx context.Context, targets []cleaner.CleanTarget, dryRun bool) ([]cleaner.CleanResult, error) {
+	results := make([]cleaner.CleanResult, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, cleaner.CleanResult{Target: target, Error: s.err})
+	}
+	return results, s.err
+}
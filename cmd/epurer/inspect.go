@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/history"
+	"github.com/0SansNom/epurer/internal/reporter"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// newInspectCmd creates the inspect command
+func newInspectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect <path>",
+		Short: "Tell what epurer knows about a path",
+		Long: `Scan like report --level aggressive, then tell which cleaners claim the path
+(or targets inside it) with their safety level, what the path is and what
+deleting it costs, its size by age, whether scans skip it or cleaning leaves
+it alone, and when it was cleaned before according to the history.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runInspect,
+	}
+
+	cmd.Flags().StringSliceVarP(&domains, "domain", "d", []string{}, "Domains to scan (comma-separated, empty = all)")
+	cmd.Flags().DurationVar(&scanTimeout, "scan-timeout", 60*time.Second, "Time budget for each cleaner's scan (0 = no limit)")
+	cmd.Flags().IntVar(&scanMaxDepth, "max-depth", scanner.DefaultMaxDepth, "Directory levels to scan below each project folder (0 = no limit)")
+	cmd.Flags().StringSliceVar(&scanExcludes, "exclude", []string{}, "Paths or directory names to skip when scanning projects (comma-separated)")
+
+	return cmd
+}
+
+// runInspect executes the inspect command
+func runInspect(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	rep := newReporter()
+
+	rep.PrintHeader()
+
+	path, err := utils.ExpandHome(args[0])
+	if err == nil {
+		path, err = filepath.Abs(path)
+	}
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	cfg.DryRun = true
+	cfg.Verbose = verbose
+	cfg.CleanLevel = config.Aggressive
+	applyPolicy(rep, cfg)
+	cfg.ScanTimeout = scanTimeout
+	cfg.ScanMaxDepth = scanMaxDepth
+	cfg.ScanExcludes = append(cfg.ScanExcludes, scanExcludes...)
+
+	inspection := reporter.Inspection{Path: path, Claims: []cleaner.Claim{}}
+	if _, err := os.Lstat(path); err == nil {
+		cleaners, err := initAllCleaners()
+		if err != nil {
+			rep.PrintError(fmt.Sprintf("Failed to initialize cleaners: %v", err))
+			return err
+		}
+		if len(domains) > 0 {
+			cleaners = filterCleanersByDomain(cleaners, domains)
+		}
+
+		rep.PrintInfo("Scanning system (this may take a while)...")
+		targetsByDomain, _ := scanTimed(ctx, cfg, cleaners, rep)
+		inspection.Claims = cleaner.FindClaims(targetsByDomain, path)
+
+		inspection.SizeBytes, _ = utils.GetDirSize(path)
+		inspection.Ages, _ = cleaner.AgeHistogram(path, time.Now())
+	} else {
+		rep.PrintWarning(fmt.Sprintf("%s does not exist, showing its past cleans only", path))
+	}
+
+	inspection.Excluded = excludedBy(cfg, path)
+	inspection.Protected = protectedBy(cfg, path)
+	if historyPath, err := history.DefaultPath(); err == nil {
+		if runs, err := history.Load(historyPath); err == nil {
+			inspection.History = history.ForPath(runs, path)
+		}
+	}
+
+	rep.PrintInspection(inspection)
+	return nil
+}
+
+// excludedBy returns why project scans skip path, or "" if they don't: the
+// admin policy, or an exclude of the config file or --exclude
+func excludedBy(cfg *config.Config, path string) string {
+	if cfg.Policy.ExcludesPath(path) {
+		return "the admin policy excludes it, it is never scanned nor cleaned"
+	}
+
+	for _, exclude := range cfg.ScanExcludes {
+		if strings.HasPrefix(exclude, "/") || strings.HasPrefix(exclude, "~") {
+			dir, err := utils.ExpandHome(exclude)
+			if err == nil && utils.HasPathPrefix(path, filepath.Clean(dir)) {
+				return fmt.Sprintf("inside %s, which project scans skip", exclude)
+			}
+			continue
+		}
+		// Excluded names are matched themselves but never descended into
+		for dir := filepath.Dir(path); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+			if matched, _ := filepath.Match(exclude, filepath.Base(dir)); matched {
+				return fmt.Sprintf("inside %s, project scans don't descend into %s folders", dir, exclude)
+			}
+		}
+	}
+	return ""
+}
+
+// protectedBy returns why cleaning leaves path alone, or "" if it doesn't:
+// a keep marker in it or above it, or a project in use
+func protectedBy(cfg *config.Config, path string) string {
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if utils.HasKeepMarker(dir) {
+			return fmt.Sprintf("marked with %s, it is never cleaned", filepath.Join(dir, utils.KeepMarker))
+		}
+		if dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	for root, reason := range cfg.OpenProjects {
+		if utils.HasPathPrefix(path, root) {
+			return fmt.Sprintf("%s is in use (%s), its build output is only cleaned at --level aggressive", root, reason)
+		}
+	}
+	return ""
+}
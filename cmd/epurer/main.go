@@ -1,18 +1,36 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
 	"github.com/0SansNom/epurer/internal/cleaner"
 	"github.com/0SansNom/epurer/internal/config"
 	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/history"
+	"github.com/0SansNom/epurer/internal/i18n"
+	"github.com/0SansNom/epurer/internal/metrics"
+	"github.com/0SansNom/epurer/internal/plan"
+	"github.com/0SansNom/epurer/internal/quarantine"
 	"github.com/0SansNom/epurer/internal/reporter"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/internal/schedule"
+	"github.com/0SansNom/epurer/internal/theme"
 	"github.com/0SansNom/epurer/internal/tui"
+	"github.com/0SansNom/epurer/pkg/utils"
 )
 
 var (
@@ -20,12 +38,53 @@ var (
 	dryRun      bool
 	verbose     bool
 	interactive bool
+	langFlag    string
+	lang        i18n.Lang // Resolved from --lang or the locale
+	themeFlag   string
+	outputTheme theme.Theme // Resolved from --theme, the config file or NO_COLOR
+	lowPriority bool
+
+	// Config loaded once before the command runs, and why it didn't load
+	loadedConfig    *config.Config
+	loadedConfigErr error
 
 	// Clean command flags
-	cleanLevel string
-	domains    []string
+	cleanLevel     string
+	domains        []string
+	only           []string
+	cargoSweepDays int
+	installerAge   int
+	olderThanDays  int
+	largerThan     string
+	scanTimeout    time.Duration
+	scanMaxDepth   int
+	scanExcludes   []string
+	resume         bool
+	askEach        string
+	jobs           int
+	webhookURL     string
+	quarantineMode bool
+	scheduled      bool
+	print0         bool
+	pathsFrom      string
+
+	// Smart command flags
+	skipRecentDays int
+
+	// Report command flags
+	profileScan    bool
+	pprofPath      string
+	ageReport      bool
+	nodeDuplicates bool
+	explain        bool
 )
 
+// version is the release reported by --version and self-report
+const version = "1.1.0"
+
+// ageMinSize is the size from which report --age analyzes a target
+const ageMinSize = 100 * 1000 * 1000
+
 func main() {
 	rootCmd := &cobra.Command{
 		Use:   "epurer",
@@ -35,11 +94,34 @@ build artifacts, and temporary files on macOS.
 
 Supports: Node.js, Python, Java, Go, Rust, PHP, Ruby, Docker, Kubernetes,
 Xcode, Android, Flutter, TensorFlow, PyTorch, and more.`,
-		Version: "1.1.0",
+		Version: version,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			lang, err = i18n.Resolve(langFlag)
+			if err != nil {
+				return err
+			}
+			loadedConfig, loadedConfigErr = config.Load()
+			// One detector for the run: the cleaners checking for their
+			// tools and detect or smart share what it finds
+			if det, err := detector.NewDetector(); err == nil {
+				cmd.SetContext(detector.WithDetector(cmd.Context(), det))
+			}
+			if outputTheme, err = resolveTheme(); err != nil {
+				return err
+			}
+			if lowPriority {
+				setLowPriority(cmd)
+			}
+			return nil
+		},
 	}
 
 	// Global flags
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().StringVar(&langFlag, "lang", "", "Output language (en|fr, default from LANG)")
+	rootCmd.PersistentFlags().StringVar(&themeFlag, "theme", "", "Output theme (default|colorblind|monochrome|ascii, default from the config file or NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVar(&lowPriority, "low-priority", false, "Scan and delete slowly, at the lowest CPU and disk priority (for background runs)")
 
 	// Commands
 	rootCmd.AddCommand(
@@ -48,9 +130,24 @@ Xcode, Android, Flutter, TensorFlow, PyTorch, and more.`,
 		newReportCmd(),
 		newSmartCmd(),
 		newTUICmd(),
+		newPlanCmd(),
+		newApplyCmd(),
+		newSnapshotsCmd(),
+		newTerraformCmd(),
+		newBigFilesCmd(),
+		newDuplicatesCmd(),
+		newDiscoverCmd(),
+		newDoctorCmd(),
+		newSelfReportCmd(),
+		newRestoreCmd(),
+		newQuarantineCmd(),
+		newInspectCmd(),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
+		if errors.Is(err, errInterrupted) {
+			os.Exit(exitCodeInterrupted)
+		}
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -69,19 +166,39 @@ Safety Levels:
   aggressive   - All items including dangerous ones (backups, data)
 
 Domains:
-  frontend - Node.js, npm, yarn, pnpm
+  frontend - Node.js, npm, yarn, pnpm, Electron, Tauri
   backend  - Python, Java, Go, Rust, PHP, Ruby
   mobile   - Xcode, Android, Flutter
   devops   - Docker, Kubernetes, Terraform
-  dataml   - Conda, Jupyter, TensorFlow, PyTorch
+  dataml   - Conda, Jupyter, TensorFlow, PyTorch, Ollama, LM Studio
+  gamedev  - Unity, Unreal Engine
   system   - System caches, logs, Homebrew`,
 		RunE: runClean,
 	}
 
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be cleaned without actually deleting")
 	cmd.Flags().BoolVarP(&interactive, "interactive", "i", true, "Ask for confirmation before cleaning")
+	cmd.Flags().BoolVar(&resume, "resume", false, "Finish an interrupted clean run without scanning again")
+	cmd.Flags().BoolVar(&print0, "print0", false, "With --dry-run, print the paths of the targets to stdout, separated by NUL characters, and nothing else")
+	cmd.Flags().StringVar(&pathsFrom, "paths-from", "", "Only clean the targets whose paths are listed in this file (NUL or newline separated, - for stdin)")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 4, "Number of cleaners deleting at once")
 	cmd.Flags().StringVarP(&cleanLevel, "level", "l", "standard", "Clean level (conservative|standard|aggressive)")
-	cmd.Flags().StringSliceVarP(&domains, "domain", "d", []string{}, "Domains to clean (comma-separated, empty = all)")
+	cmd.Flags().StringSliceVarP(&domains, "domain", "d", []string{}, "Domains to clean, or domain.category entries such as frontend.npm_cache (comma-separated, empty = all)")
+	cmd.Flags().StringSliceVar(&only, "only", []string{}, "Target categories to keep, e.g. node_modules,gradle-cache (comma-separated, see report --verbose)")
+	cmd.Flags().StringVar(&webhookURL, "webhook", "", "POST the run summary to this URL after cleaning (JSON, or a Slack message for Slack webhooks)")
+	cmd.Flags().BoolVar(&quarantineMode, "quarantine", false, "Move targets to the quarantine instead of deleting them, to restore them with epurer restore")
+	cmd.Flags().BoolVar(&scheduled, "scheduled", false, "Run as a scheduled clean: without prompts, and only when the schedule conditions of the config file are met")
+	cmd.Flags().IntVar(&cargoSweepDays, "cargo-sweep", 0, "Only remove Rust build artifacts older than N days instead of whole target/ folders")
+	cmd.Flags().IntVar(&installerAge, "installer-age", 30, "Only remove installers and disk images from Downloads and Desktop unused for N days")
+	addPruneFlags(cmd)
+	cmd.Flags().DurationVar(&scanTimeout, "scan-timeout", 60*time.Second, "Time budget for each cleaner's scan (0 = no limit)")
+	cmd.Flags().IntVar(&scanMaxDepth, "max-depth", scanner.DefaultMaxDepth, "Directory levels to scan below each project folder (0 = no limit)")
+	cmd.Flags().StringSliceVar(&scanExcludes, "exclude", []string{}, "Paths or directory names to skip when scanning projects (comma-separated)")
+	cmd.Flags().StringSliceVar(&volumePaths, "volume", []string{}, "Folders of external volumes to scan for projects too, e.g. /Volumes/Work (comma-separated)")
+	cmd.Flags().BoolVar(&allowNetworkVolumes, "allow-network", false, "Allow --volume folders on network mounts")
+	cmd.Flags().BoolVar(&scanWSL, "wsl", false, "Also scan the Windows side of WSL, or the WSL distributions from Windows, for tool caches")
+	cmd.Flags().StringVar(&askEach, "ask-each", "", "Confirm each target individually from this safety level up (dangerous|moderate|all)")
+	cmd.Flags().Lookup("ask-each").NoOptDefVal = "dangerous"
 
 	return cmd
 }
@@ -108,7 +225,25 @@ func newReportCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&cleanLevel, "level", "l", "standard", "Clean level (conservative|standard|aggressive)")
-	cmd.Flags().StringSliceVarP(&domains, "domain", "d", []string{}, "Domains to scan (comma-separated, empty = all)")
+	cmd.Flags().StringSliceVarP(&domains, "domain", "d", []string{}, "Domains to scan, or domain.category entries such as frontend.npm_cache (comma-separated, empty = all)")
+	cmd.Flags().StringSliceVar(&only, "only", []string{}, "Target categories to keep, e.g. node_modules,gradle-cache (comma-separated, see report --verbose)")
+	cmd.Flags().IntVar(&cargoSweepDays, "cargo-sweep", 0, "Only count Rust build artifacts older than N days instead of whole target/ folders")
+	cmd.Flags().IntVar(&installerAge, "installer-age", 30, "Only count installers and disk images from Downloads and Desktop unused for N days")
+	addPruneFlags(cmd)
+	cmd.Flags().DurationVar(&scanTimeout, "scan-timeout", 60*time.Second, "Time budget for each cleaner's scan (0 = no limit)")
+	cmd.Flags().IntVar(&scanMaxDepth, "max-depth", scanner.DefaultMaxDepth, "Directory levels to scan below each project folder (0 = no limit)")
+	cmd.Flags().StringSliceVar(&scanExcludes, "exclude", []string{}, "Paths or directory names to skip when scanning projects (comma-separated)")
+	cmd.Flags().StringSliceVar(&volumePaths, "volume", []string{}, "Folders of external volumes to scan for projects too, e.g. /Volumes/Work (comma-separated)")
+	cmd.Flags().BoolVar(&allowNetworkVolumes, "allow-network", false, "Allow --volume folders on network mounts")
+	cmd.Flags().BoolVar(&scanWSL, "wsl", false, "Also scan the Windows side of WSL, or the WSL distributions from Windows, for tool caches")
+	cmd.Flags().BoolVar(&profileScan, "profile", false, "Print per-cleaner scan timings and the slowest directories")
+	cmd.Flags().StringVar(&pprofPath, "pprof", "", "Write a CPU profile of the scan to this file (go tool pprof format)")
+	cmd.Flags().BoolVar(&ageReport, "age", false, "Show how much space old entries take in large cache directories (by modification time)")
+	cmd.Flags().BoolVar(&nodeDuplicates, "node-duplicates", false, "List npm package releases installed in several node_modules folders")
+	cmd.Flags().BoolVar(&explain, "explain", false, "Explain each kind of item found: what it is, what brings it back and what deleting it costs")
+	cmd.Flags().StringVar(&metricsOut, "metrics-out", "", "Write Prometheus metrics of the scan to this file (node exporter textfile format)")
+	cmd.Flags().StringVar(&metricsListen, "metrics-listen", "", "Serve Prometheus metrics on this address (e.g. localhost:9184), scanning again periodically")
+	cmd.Flags().DurationVar(&metricsInterval, "metrics-interval", time.Hour, "Time between scans with --metrics-listen")
 
 	return cmd
 }
@@ -124,6 +259,11 @@ using conservative settings. Perfect for quick, safe cleanup.`,
 	}
 
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be cleaned without actually deleting")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 4, "Number of cleaners deleting at once")
+	cmd.Flags().StringVar(&webhookURL, "webhook", "", "POST the run summary to this URL after cleaning (JSON, or a Slack message for Slack webhooks)")
+	cmd.Flags().BoolVar(&quarantineMode, "quarantine", false, "Move targets to the quarantine instead of deleting them, to restore them with epurer restore")
+	cmd.Flags().BoolVar(&scheduled, "scheduled", false, "Run as a scheduled clean: without prompts, and only when the schedule conditions of the config file are met")
+	cmd.Flags().IntVar(&skipRecentDays, "skip-recent", 7, "Skip targets cleaned in the last N days that have barely grown back since (0 = clean everything)")
 
 	return cmd
 }
@@ -140,17 +280,42 @@ and confirm with enter.`,
 	}
 
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be cleaned without actually deleting")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 4, "Number of cleaners deleting at once")
+	cmd.Flags().BoolVar(&quarantineMode, "quarantine", false, "Move targets to the quarantine instead of deleting them, to restore them with epurer restore")
 
 	return cmd
 }
 
 // runClean executes the clean command
 func runClean(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	rep := reporter.NewReporter(verbose)
+	ctx := cmd.Context()
+	rep := newReporter()
+	if print0 {
+		// Keep stdout for the paths
+		rep.SetOutput(os.Stderr)
+	}
 
 	rep.PrintHeader()
 
+	if print0 && !dryRun {
+		err := fmt.Errorf("--print0 lists the targets of a dry run, add --dry-run")
+		rep.PrintError(err.Error())
+		return err
+	}
+	var paths []string
+	if pathsFrom != "" {
+		if pathsFrom == "-" && interactive && !scheduled && !dryRun {
+			err := fmt.Errorf("--paths-from - reads the paths from stdin, which confirmations need: add --interactive=false")
+			rep.PrintError(err.Error())
+			return err
+		}
+		var err error
+		if paths, err = readPathList(pathsFrom); err != nil {
+			rep.PrintError(err.Error())
+			return err
+		}
+	}
+
 	// Parse clean level
 	level, err := config.ParseCleanLevel(cleanLevel)
 	if err != nil {
@@ -158,12 +323,48 @@ func runClean(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	var askFrom config.SafetyLevel
+	if askEach != "" {
+		askFrom, err = parseAskEach(askEach)
+		if err != nil {
+			rep.PrintError(err.Error())
+			return err
+		}
+	}
+
 	// Create config
-	cfg := config.NewDefaultConfig()
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
 	cfg.DryRun = dryRun
 	cfg.Verbose = verbose
 	cfg.CleanLevel = level
-	cfg.Interactive = interactive
+	applyPolicy(rep, cfg)
+	cfg.MaxConcurrent = jobs
+	cfg.Interactive = interactive && !scheduled
+	applyWebhookFlag(cfg)
+	cfg.Quarantine = cfg.Quarantine || quarantineMode
+	if deferScheduled(ctx, rep, cfg) {
+		return nil
+	}
+	cfg.CargoSweepDays = cargoSweepDays
+	cfg.InstallerMaxAgeDays = installerAge
+	if err := applyPruneFlags(cfg); err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	cfg.ScanTimeout = scanTimeout
+	cfg.ScanMaxDepth = scanMaxDepth
+	cfg.ScanExcludes = append(cfg.ScanExcludes, scanExcludes...)
+	if err := applySelection(cfg); err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	if err := applyVolumes(rep, cfg); err != nil {
+		return err
+	}
 
 	// Initialize cleaners
 	cleaners, err := initAllCleaners()
@@ -172,6 +373,11 @@ func runClean(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Finish an interrupted run instead of scanning again
+	if resume {
+		return resumeClean(ctx, cmd, rep, cleaners, cfg)
+	}
+
 	// Filter by domain if specified
 	if len(domains) > 0 {
 		cleaners = filterCleanersByDomain(cleaners, domains)
@@ -180,10 +386,13 @@ func runClean(cmd *cobra.Command, args []string) error {
 	// Detect and scan
 	rep.PrintInfo("Scanning system...")
 
+	prepareScan(ctx, cfg, cleaners, rep)
 	targetsByDomain := make(map[string][]cleaner.CleanTarget)
 	detected := make(map[string]bool)
 
-	for _, c := range cleaners {
+	progress := newScanProgress(rep, cleaners)
+	for i, c := range cleaners {
+		progress.update(i)
 		isDetected, err := c.Detect(ctx)
 		if err != nil {
 			if verbose {
@@ -198,7 +407,10 @@ func runClean(cmd *cobra.Command, args []string) error {
 
 		detected[c.Name()] = true
 
-		targets, err := c.Scan(ctx, cfg)
+		targets, timedOut, err := cleaner.ScanWithTimeout(ctx, c, cfg)
+		if timedOut {
+			rep.MarkPartial(c.Name())
+		}
 		if err != nil {
 			if verbose {
 				rep.PrintWarning(fmt.Sprintf("Scan error for %s: %v", c.Name(), err))
@@ -210,6 +422,21 @@ func runClean(cmd *cobra.Command, args []string) error {
 			targetsByDomain[c.Name()] = targets
 		}
 	}
+	progress.update(len(cleaners))
+	cleaner.SubtractOverlaps(targetsByDomain)
+	warnUnselected(rep, cfg, cleaners, targetsByDomain)
+
+	if pathsFrom != "" {
+		var unmatched []string
+		targetsByDomain, unmatched = cleaner.SelectPaths(targetsByDomain, paths)
+		for _, path := range unmatched {
+			rep.PrintWarning(fmt.Sprintf("%s is not a target of this scan, skipped", path))
+		}
+	}
+	if print0 {
+		printTargetPaths(os.Stdout, cleaners, targetsByDomain)
+		return nil
+	}
 
 	// Print estimation
 	rep.PrintEstimation(targetsByDomain)
@@ -222,14 +449,41 @@ func runClean(cmd *cobra.Command, args []string) error {
 	}
 
 	if totalTargets == 0 {
-		rep.PrintInfo("Nothing to clean!")
+		rep.PrintInfo(lang.T("results.nothing"))
 		return nil
 	}
 
-	// Ask for confirmation if interactive
-	if interactive && !dryRun {
-		if !rep.AskConfirmation(fmt.Sprintf("Proceed with cleaning %d items?", totalTargets)) {
-			rep.PrintInfo("Cancelled")
+	// Let the user pick targets one by one before the overall confirmation
+	if askEach != "" && !dryRun {
+		var cancelled bool
+		targetsByDomain, cancelled = confirmEachTarget(rep, targetsByDomain, askFrom)
+		if cancelled {
+			rep.PrintInfo(lang.T("prompt.cancelled"))
+			return nil
+		}
+
+		totalTargets = 0
+		for _, targets := range targetsByDomain {
+			totalTargets += len(targets)
+		}
+		if totalTargets == 0 {
+			rep.PrintInfo(lang.T("results.nothing"))
+			return nil
+		}
+	}
+
+	// Ask for confirmation if interactive, once what cleaning leaves to do
+	// is known
+	if cfg.Interactive || dryRun {
+		targets := []cleaner.CleanTarget{}
+		for _, domainTargets := range targetsByDomain {
+			targets = append(targets, domainTargets...)
+		}
+		rep.PrintConsequences(cleaner.Consequences(targets))
+	}
+	if cfg.Interactive && !dryRun {
+		if !rep.AskConfirmation(lang.T("prompt.proceed", totalTargets)) {
+			rep.PrintInfo(lang.T("prompt.cancelled"))
 			return nil
 		}
 	}
@@ -239,63 +493,197 @@ func runClean(cmd *cobra.Command, args []string) error {
 		rep.PrintInfo("DRY RUN - No files will be deleted")
 	}
 
-	allResults := []cleaner.CleanResult{}
+	manifestPath, err := plan.ManifestPath()
+	if err != nil {
+		rep.PrintWarning(fmt.Sprintf("This run can't be resumed: %v", err))
+	}
 
-	for domain, targets := range targetsByDomain {
-		rep.PrintInfo(fmt.Sprintf("Cleaning %s...", domain))
+	return cleanPlan(ctx, cmd, rep, cleaners, plan.New(targetsByDomain, level), "clean", manifestPath, dryRun, cfg.MaxConcurrent, cfg)
+}
 
-		// Find the cleaner for this domain
-		var domainCleaner cleaner.Cleaner
-		for _, c := range cleaners {
-			if c.Name() == domain {
-				domainCleaner = c
-				break
+// applySelection narrows the run to the categories of --only and of the
+// domain.category entries of --domain. The other --domain entries only
+// filter cleaners, unless categories are selected too: then those naming a
+// domain select all of its categories.
+func applySelection(cfg *config.Config) error {
+	var selection config.Selection
+	categories := len(only) > 0
+	for _, d := range domains {
+		if domain, _, ok := strings.Cut(d, "."); ok && domain != "" {
+			entry, err := config.ParseSelectionEntry(d)
+			if err != nil {
+				return err
 			}
+			selection = append(selection, entry)
+			categories = true
+		} else if entry, err := config.ParseSelectionEntry(d); err == nil && entry.Category == "" {
+			selection = append(selection, entry)
 		}
+	}
+	if !categories {
+		return nil
+	}
 
-		if domainCleaner == nil {
-			continue
+	for _, category := range only {
+		entry, err := config.ParseSelectionEntry(category)
+		if err != nil {
+			return err
 		}
+		selection = append(selection, entry)
+	}
+	cfg.Selection = selection
+	return nil
+}
 
-		results, err := domainCleaner.Clean(ctx, targets, dryRun)
-		if err != nil {
-			rep.PrintWarning(fmt.Sprintf("Error cleaning %s: %v", domain, err))
+// warnUnselected warns about the categories of the selection no target was
+// found for, often a misspelled category
+func warnUnselected(rep *reporter.Reporter, cfg *config.Config, cleaners []cleaner.Cleaner, targetsByDomain map[string][]cleaner.CleanTarget) {
+	for _, entry := range cfg.Selection {
+		if entry.Category == "" {
 			continue
 		}
+		found := false
+		for _, c := range cleaners {
+			for _, target := range targetsByDomain[c.Name()] {
+				found = found || config.Selection{entry}.Selects(c.Domain(), target.Category)
+			}
+		}
+		if !found {
+			rep.PrintWarning(fmt.Sprintf("No %s target found (report --verbose lists the categories in brackets)", entry))
+		}
+	}
+}
 
-		allResults = append(allResults, results...)
+// readPathList reads the paths of --paths-from, from stdin for "-". Paths
+// must be absolute, "~" standing for the home directory.
+func readPathList(name string) ([]string, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the paths to clean: %w", err)
 	}
 
-	// Print results
-	rep.PrintCleanResults(allResults, dryRun)
+	paths := cleaner.ParsePathList(data)
+	for i, path := range paths {
+		if paths[i], err = utils.ExpandHome(path); err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(paths[i]) {
+			return nil, fmt.Errorf("invalid path %q in %s: paths to clean must be absolute", path, name)
+		}
+	}
+	return paths, nil
+}
+
+// printTargetPaths writes the paths the targets remove to w, each followed
+// by a NUL character, in the order of the cleaners
+func printTargetPaths(w io.Writer, cleaners []cleaner.Cleaner, targetsByDomain map[string][]cleaner.CleanTarget) {
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+
+	for _, c := range cleaners {
+		for _, target := range targetsByDomain[c.Name()] {
+			for _, path := range target.RemovedPaths() {
+				out.WriteString(path)
+				out.WriteByte(0)
+			}
+		}
+	}
+}
+
+// addPruneFlags adds the partial clean flags of the system, pip and Gradle
+// caches to a command
+func addPruneFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&olderThanDays, "older-than", 0, "Only remove cache entries not modified for N days from the system, pip and Gradle caches")
+	cmd.Flags().StringVar(&largerThan, "larger-than", "", "Only remove cache entries of at least this size (e.g. 50MB) from the system, pip and Gradle caches")
+}
 
+// applyPruneFlags sets the partial clean criteria from the flags
+func applyPruneFlags(cfg *config.Config) error {
+	cfg.CacheMaxAgeDays = olderThanDays
+	if largerThan != "" {
+		size, err := humanize.ParseBytes(largerThan)
+		if err != nil {
+			return fmt.Errorf("invalid --larger-than value: %s", largerThan)
+		}
+		cfg.CacheMinEntrySize = int64(size)
+	}
 	return nil
 }
 
+// parseAskEach converts the --ask-each value to the lowest safety level
+// whose targets are confirmed individually
+func parseAskEach(value string) (config.SafetyLevel, error) {
+	if value == "all" {
+		return config.Safe, nil
+	}
+
+	level, err := config.ParseSafetyLevel(value)
+	if err != nil {
+		return config.Safe, fmt.Errorf("invalid --ask-each value: %s (must be dangerous, moderate or all)", value)
+	}
+	return level, nil
+}
+
+// confirmEachTarget asks about every target at or above the given safety
+// level, one at a time, and returns the targets to clean. Lower targets are
+// kept without asking. The second result is true if the user cancelled the
+// run.
+func confirmEachTarget(rep *reporter.Reporter, targetsByDomain map[string][]cleaner.CleanTarget, from config.SafetyLevel) (map[string][]cleaner.CleanTarget, bool) {
+	names := make([]string, 0, len(targetsByDomain))
+	for name := range targetsByDomain {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rep.PrintInfo("Confirm each target: y = clean, n = skip, a = clean this and all the rest, q = cancel")
+
+	kept := make(map[string][]cleaner.CleanTarget)
+	asking := true
+
+	for _, name := range names {
+		for _, target := range targetsByDomain[name] {
+			if asking && target.Safety >= from {
+				switch rep.AskTarget(target) {
+				case reporter.AnswerNo:
+					continue
+				case reporter.AnswerAll:
+					asking = false
+				case reporter.AnswerQuit:
+					return nil, true
+				}
+			}
+			kept[name] = append(kept[name], target)
+		}
+	}
+
+	return kept, false
+}
+
 // runDetect executes the detect command
 func runDetect(cmd *cobra.Command, args []string) error {
-	rep := reporter.NewReporter(verbose)
+	rep := newReporter()
 	rep.PrintHeader()
 
-	det, err := detector.NewDetector()
-	if err != nil {
-		rep.PrintError(fmt.Sprintf("Failed to create detector: %v", err))
-		return err
-	}
+	det := detector.FromContext(cmd.Context())
 
-	rep.PrintInfo("Detecting development tools...")
+	rep.PrintInfo(lang.T("detect.title"))
 	fmt.Println()
 
-	summary := det.GetSummary()
-	fmt.Println(summary)
+	rep.PrintTools(det.DetectDetailed())
 
 	return nil
 }
 
 // runReport executes the report command
 func runReport(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	rep := reporter.NewReporter(verbose)
+	ctx := cmd.Context()
+	rep := newReporter()
 
 	rep.PrintHeader()
 
@@ -305,11 +693,37 @@ func runReport(cmd *cobra.Command, args []string) error {
 		rep.PrintError(err.Error())
 		return err
 	}
+	if metricsListen != "" && metricsInterval <= 0 {
+		err := fmt.Errorf("invalid --metrics-interval value: %v (must be positive)", metricsInterval)
+		rep.PrintError(err.Error())
+		return err
+	}
 
 	// Create config
-	cfg := config.NewDefaultConfig()
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
 	cfg.Verbose = verbose
 	cfg.CleanLevel = level
+	applyPolicy(rep, cfg)
+	cfg.CargoSweepDays = cargoSweepDays
+	cfg.InstallerMaxAgeDays = installerAge
+	if err := applyPruneFlags(cfg); err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	cfg.ScanTimeout = scanTimeout
+	cfg.ScanMaxDepth = scanMaxDepth
+	cfg.ScanExcludes = append(cfg.ScanExcludes, scanExcludes...)
+	if err := applySelection(cfg); err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	if err := applyVolumes(rep, cfg); err != nil {
+		return err
+	}
 
 	// Initialize cleaners
 	cleaners, err := initAllCleaners()
@@ -323,31 +737,26 @@ func runReport(cmd *cobra.Command, args []string) error {
 		cleaners = filterCleanersByDomain(cleaners, domains)
 	}
 
-	// Scan
-	rep.PrintInfo("Scanning system (this may take a while)...")
-	startTime := time.Now()
-
-	targetsByDomain := make(map[string][]cleaner.CleanTarget)
-
-	for _, c := range cleaners {
-		isDetected, err := c.Detect(ctx)
-		if err != nil || !isDetected {
-			continue
-		}
-
-		targets, err := c.Scan(ctx, cfg)
+	if pprofPath != "" {
+		stop, err := startCPUProfile(pprofPath)
 		if err != nil {
-			if verbose {
-				rep.PrintWarning(fmt.Sprintf("Scan error for %s: %v", c.Name(), err))
-			}
-			continue
+			rep.PrintError(err.Error())
+			return err
 		}
+		defer stop()
+	}
 
-		if len(targets) > 0 {
-			targetsByDomain[c.Name()] = targets
-		}
+	if profileScan {
+		cfg.ScanProfile = scanner.NewProfile()
 	}
 
+	// Scan
+	rep.PrintInfo("Scanning system (this may take a while)...")
+	startTime := time.Now()
+
+	targetsByDomain, timings := scanTimed(ctx, cfg, cleaners, rep)
+	warnUnselected(rep, cfg, cleaners, targetsByDomain)
+
 	scanDuration := time.Since(startTime)
 
 	// Print report
@@ -362,34 +771,91 @@ func runReport(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if ageReport {
+		targets := []cleaner.CleanTarget{}
+		for _, domainTargets := range targetsByDomain {
+			targets = append(targets, domainTargets...)
+		}
+		rep.PrintAgeHistograms(cleaner.AnalyzeAges(targets, ageMinSize, time.Now()))
+	}
+
+	if explain {
+		targets := []cleaner.CleanTarget{}
+		for _, domainTargets := range targetsByDomain {
+			targets = append(targets, domainTargets...)
+		}
+		rep.PrintExplanations(cleaner.ExplainCategories(targets))
+	}
+
+	if nodeDuplicates {
+		dirs, err := cleaner.FindNodeModulesDirs(ctx, cfg)
+		if err != nil {
+			rep.PrintError(err.Error())
+			return err
+		}
+		duplicates := cleaner.NodeDuplicates(dirs)
+		rep.PrintNodeDuplicates(duplicates, cleaner.NodeWorkspaceCandidates(duplicates))
+	}
+
+	if profileScan {
+		rep.PrintScanProfile(timings, cfg.ScanProfile.Dirs())
+	}
+
 	rep.PrintInfo(fmt.Sprintf("Scan completed in %v", scanDuration.Round(time.Second)))
+	if pprofPath != "" {
+		rep.PrintInfo(fmt.Sprintf("CPU profile written to %s (inspect with `go tool pprof %s`)", pprofPath, pprofPath))
+	}
+
+	snapshot := scanMetrics(cleaners, targetsByDomain, startTime, scanDuration)
+	if metricsOut != "" {
+		if err := metrics.WriteFile(metricsOut, snapshot); err != nil {
+			rep.PrintError(fmt.Sprintf("Failed to write metrics: %v", err))
+			return err
+		}
+		rep.PrintInfo(fmt.Sprintf("Metrics written to %s", metricsOut))
+	}
+	if metricsListen != "" {
+		return serveMetrics(rep, metricsListen, metricsInterval, snapshot, func() metrics.Snapshot {
+			scannedAt := time.Now()
+			targetsByDomain, _ := scanTimed(ctx, cfg, cleaners, rep)
+			snapshot := scanMetrics(cleaners, targetsByDomain, scannedAt, time.Since(scannedAt))
+			if metricsOut != "" {
+				metrics.WriteFile(metricsOut, snapshot)
+			}
+			return snapshot
+		})
+	}
 
 	return nil
 }
 
 // runSmart executes the smart command
 func runSmart(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	rep := reporter.NewReporter(verbose)
+	ctx := cmd.Context()
+	rep := newReporter()
 
 	rep.PrintHeader()
 	rep.PrintInfo("Running smart cleanup with conservative settings...")
 
 	// Use conservative level for smart mode
-	cfg := config.NewDefaultConfig()
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
 	cfg.DryRun = dryRun
 	cfg.Verbose = verbose
 	cfg.CleanLevel = config.Conservative
+	cfg.MaxConcurrent = jobs
 	cfg.Interactive = false // Smart mode is automatic
-
-	// Detect tools first
-	det, err := detector.NewDetector()
-	if err != nil {
-		rep.PrintError(fmt.Sprintf("Failed to create detector: %v", err))
-		return err
+	applyWebhookFlag(cfg)
+	cfg.Quarantine = cfg.Quarantine || quarantineMode
+	if deferScheduled(ctx, rep, cfg) {
+		return nil
 	}
 
-	detection := det.DetectAll()
+	// Detect tools first, with the detector the cleaners use
+	detection := detector.FromContext(ctx).DetectAll()
 
 	// Initialize only relevant cleaners
 	cleaners := []cleaner.Cleaner{}
@@ -429,23 +895,40 @@ func runSmart(cmd *cobra.Command, args []string) error {
 	)
 
 	// Scan and clean
+	prepareScan(ctx, cfg, cleaners, rep)
 	targetsByDomain := make(map[string][]cleaner.CleanTarget)
 
-	for _, c := range cleaners {
+	progress := newScanProgress(rep, cleaners)
+	for i, c := range cleaners {
+		progress.update(i)
 		isDetected, err := c.Detect(ctx)
 		if err != nil || !isDetected {
 			continue
 		}
 
-		targets, err := c.Scan(ctx, cfg)
+		targets, timedOut, err := cleaner.ScanWithTimeout(ctx, c, cfg)
+		if timedOut {
+			rep.MarkPartial(c.Name())
+		}
 		if err != nil {
 			continue
 		}
 
+		// Smart runs only delete files: actions restart parts of the
+		// system (the Dock, Launch Services) and are left to clean
+		targets = slices.DeleteFunc(targets, func(target cleaner.CleanTarget) bool {
+			return target.Action == cleaner.ActionRun
+		})
 		if len(targets) > 0 {
 			targetsByDomain[c.Name()] = targets
 		}
 	}
+	progress.update(len(cleaners))
+	cleaner.SubtractOverlaps(targetsByDomain)
+
+	if skipRecentDays > 0 {
+		targetsByDomain = selectSmart(rep, targetsByDomain, skipRecentDays)
+	}
 
 	// Print estimation
 	rep.PrintEstimation(targetsByDomain)
@@ -456,38 +939,81 @@ func runSmart(cmd *cobra.Command, args []string) error {
 	}
 
 	// Execute cleanup
-	allResults := []cleaner.CleanResult{}
+	manifestPath, err := plan.ManifestPath()
+	if err != nil {
+		rep.PrintWarning(fmt.Sprintf("This run can't be resumed: %v", err))
+	}
 
-	for domain, targets := range targetsByDomain {
-		for _, c := range cleaners {
-			if c.Name() == domain {
-				results, err := c.Clean(ctx, targets, dryRun)
-				if err == nil {
-					allResults = append(allResults, results...)
-				}
-				break
-			}
-		}
+	return cleanPlan(ctx, cmd, rep, cleaners, plan.New(targetsByDomain, cfg.CleanLevel), "smart", manifestPath, dryRun, cfg.MaxConcurrent, cfg)
+}
+
+// selectSmart goes by the history of past runs to skip the targets cleaned
+// in the last recentDays days that have barely grown back since, and to
+// have each cleaner clean its chronic regrowers first. Cleaners still run
+// in the order of the plan.
+func selectSmart(rep *reporter.Reporter, targetsByDomain map[string][]cleaner.CleanTarget, recentDays int) map[string][]cleaner.CleanTarget {
+	path, err := history.DefaultPath()
+	if err != nil {
+		return targetsByDomain
+	}
+	runs, err := history.Load(path)
+	if err != nil {
+		rep.PrintWarning(fmt.Sprintf("Failed to read the run history, cleaning everything: %v", err))
+		return targetsByDomain
 	}
 
-	// Print results
-	rep.PrintCleanResults(allResults, dryRun)
+	names := make([]string, 0, len(targetsByDomain))
+	for name := range targetsByDomain {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	regrowth := history.RegrowthByPath(runs)
+	now := time.Now()
+	selected := make(map[string][]cleaner.CleanTarget)
+	var skipped, chronic []cleaner.CleanTarget
+	for _, domain := range names {
+		selection := history.SelectSmart(targetsByDomain[domain], regrowth, time.Duration(recentDays)*24*time.Hour, now)
+		if len(selection.Targets) > 0 {
+			selected[domain] = selection.Targets
+		}
+		skipped = append(skipped, selection.Skipped...)
+		chronic = append(chronic, selection.Chronic...)
+	}
 
-	return nil
+	rep.PrintSmartSelection(skipped, chronic, regrowth, recentDays)
+	return selected
 }
 
 // runTUI executes the interactive TUI command
 func runTUI(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx := cmd.Context()
 
 	// Show loading message
 	fmt.Print("\033[?25l") // Hide cursor
-	fmt.Print("🔍 Scanning system for cleanable items...")
+	fmt.Print(outputTheme.Text("🔍 Scanning system for cleanable items..."))
 
 	// Use conservative level for TUI mode
-	cfg := config.NewDefaultConfig()
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Print("\033[?25h") // Show cursor
+		return err
+	}
 	cfg.CleanLevel = config.Standard
+	cfg.ApplyPolicy()
 	cfg.Verbose = verbose
+	cfg.MaxConcurrent = jobs
+	cfg.Quarantine = cfg.Quarantine || quarantineMode
+
+	var store *quarantine.Store
+	if cfg.Quarantine && !dryRun {
+		dir, err := quarantine.DefaultDir()
+		if err != nil {
+			fmt.Print("\033[?25h") // Show cursor
+			return err
+		}
+		store = quarantine.New(dir)
+	}
 
 	// Initialize cleaners
 	cleaners, err := initAllCleaners()
@@ -498,13 +1024,20 @@ func runTUI(cmd *cobra.Command, args []string) error {
 	}
 
 	// Scan all domains with progress indicator
+	prepareScan(ctx, cfg, cleaners, nil)
 	targetsByDomain := make(map[string][]cleaner.CleanTarget)
 	spinChars := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 	spinIdx := 0
-
-	for _, c := range cleaners {
-		// Update spinner
-		fmt.Printf("\r%s Scanning %s...   ", spinChars[spinIdx%len(spinChars)], c.Name())
+	progress := newScanProgress(nil, cleaners)
+
+	for i, c := range cleaners {
+		// Update spinner, with the time left once the pace is known
+		progress.update(i)
+		eta := ""
+		if remaining, ok := progress.remaining(); ok {
+			eta = " (" + lang.T("progress.eta", utils.FormatDuration(remaining.Round(time.Second))) + ")"
+		}
+		fmt.Printf("\r\033[K%s Scanning %s...%s", outputTheme.Text(spinChars[spinIdx%len(spinChars)]), c.Name(), eta)
 		spinIdx++
 
 		isDetected, err := c.Detect(ctx)
@@ -512,7 +1045,7 @@ func runTUI(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		targets, err := c.Scan(ctx, cfg)
+		targets, _, err := cleaner.ScanWithTimeout(ctx, c, cfg)
 		if err != nil {
 			continue
 		}
@@ -521,22 +1054,104 @@ func runTUI(cmd *cobra.Command, args []string) error {
 			targetsByDomain[c.Name()] = targets
 		}
 	}
+	progress.update(len(cleaners))
+	cleaner.SubtractOverlaps(targetsByDomain)
 
 	// Clear loading line and show cursor
-	fmt.Print("\r\033[K") // Clear line
+	fmt.Print("\r\033[K")  // Clear line
 	fmt.Print("\033[?25h") // Show cursor
 
 	if len(targetsByDomain) == 0 {
-		fmt.Println("✅ Nothing to clean!")
+		fmt.Println(outputTheme.Text("✅ " + lang.T("results.nothing")))
 		return nil
 	}
 
 	// Launch TUI
-	return tui.Run(targetsByDomain, dryRun)
+	if err := tui.Run(targetsByDomain, cleaners, jobs, dryRun, store, lang, outputTheme); err != nil {
+		return err
+	}
+	if store != nil {
+		expireQuarantine(newReporter(), store, cfg)
+	}
+	return nil
 }
 
 // Helper functions
 
+// setLowPriority lowers the priority of the process and throttles its disk
+// access. Cleaners delete one at a time unless --jobs says otherwise.
+func setLowPriority(cmd *cobra.Command) {
+	if err := utils.SetLowPriority(); err != nil {
+		newReporter().PrintWarning(fmt.Sprintf("Could not lower the process priority, throttling only: %v", err))
+	}
+	if !cmd.Flags().Changed("jobs") {
+		jobs = 1
+	}
+}
+
+// applyPolicy lowers the clean level to the maximum the admin policy allows,
+// telling the user
+func applyPolicy(rep *reporter.Reporter, cfg *config.Config) {
+	if cfg.ApplyPolicy() {
+		rep.PrintWarning(fmt.Sprintf("Clean level lowered to %s by the policy at %s", cfg.CleanLevel, cfg.Policy.Path))
+	}
+}
+
+// applyWebhookFlag makes --webhook replace the webhook of the config file
+func applyWebhookFlag(cfg *config.Config) {
+	if webhookURL != "" {
+		cfg.Webhook = config.Webhook{URL: webhookURL}
+	}
+}
+
+// deferScheduled reports whether a scheduled run (--scheduled) has to wait
+// for the next one, saying why: the Mac is on battery, in use, or within
+// work hours. Other runs are never deferred.
+func deferScheduled(ctx context.Context, rep *reporter.Reporter, cfg *config.Config) bool {
+	if !scheduled {
+		return false
+	}
+	reason := schedule.Check(ctx, cfg.Schedule, time.Now())
+	if reason == "" {
+		return false
+	}
+	rep.PrintInfo(fmt.Sprintf("Scheduled clean deferred: %s", reason))
+	return true
+}
+
+// newReporter creates a reporter using the global verbose and language flags
+func newReporter() *reporter.Reporter {
+	rep := reporter.NewReporter(verbose)
+	rep.SetLang(lang)
+	rep.SetTheme(outputTheme)
+	return rep
+}
+
+// loadConfig returns the config loaded before the command ran, so that the
+// config file is read once per run
+func loadConfig() (*config.Config, error) {
+	return loadedConfig, loadedConfigErr
+}
+
+// resolveTheme returns the theme named by --theme or, without it, by the
+// config file. A config file that doesn't load is left for the command to
+// report.
+func resolveTheme() (theme.Theme, error) {
+	if themeFlag != "" {
+		return theme.Resolve(themeFlag)
+	}
+	name := ""
+	if loadedConfig != nil {
+		name = loadedConfig.Theme
+	}
+	t, err := theme.Resolve(name)
+	if err != nil {
+		path, _ := config.FilePath()
+		return t, fmt.Errorf("invalid config %s: theme: %w", path, err)
+	}
+	return t, nil
+}
+
 func initAllCleaners() ([]cleaner.Cleaner, error) {
 	cleaners := []cleaner.Cleaner{
 		cleaner.NewTrashCleaner(),
@@ -548,12 +1163,21 @@ func initAllCleaners() ([]cleaner.Cleaner, error) {
 		cleaner.NewXcodeCleaner(),
 		cleaner.NewLaunchpadCleaner(),
 		cleaner.NewIOSBackupCleaner(),
+		cleaner.NewMediaCacheCleaner(),
+		cleaner.NewLeftoversCleaner(),
+		cleaner.NewInstallersCleaner(),
+		cleaner.NewScreenshotsCleaner(),
+		cleaner.NewLocalLLMCleaner(),
+		cleaner.NewWSLCleaner(),
 	}
 
 	// Add cleaners that can return errors
 	if c, err := cleaner.NewFrontendCleaner(); err == nil {
 		cleaners = append(cleaners, c)
 	}
+	if c, err := cleaner.NewElectronCleaner(); err == nil {
+		cleaners = append(cleaners, c)
+	}
 	if c, err := cleaner.NewBackendCleaner(); err == nil {
 		cleaners = append(cleaners, c)
 	}
@@ -566,10 +1190,196 @@ func initAllCleaners() ([]cleaner.Cleaner, error) {
 	if c, err := cleaner.NewDataMLCleaner(); err == nil {
 		cleaners = append(cleaners, c)
 	}
+	if c, err := cleaner.NewVersionManagerCleaner(); err == nil {
+		cleaners = append(cleaners, c)
+	}
+	if c, err := cleaner.NewDotNetCleaner(); err == nil {
+		cleaners = append(cleaners, c)
+	}
+	if c, err := cleaner.NewNativeCleaner(); err == nil {
+		cleaners = append(cleaners, c)
+	}
+	if c, err := cleaner.NewGameDevCleaner(); err == nil {
+		cleaners = append(cleaners, c)
+	}
 
 	return cleaners, nil
 }
 
+// prepareScan makes the cleaners match all their project patterns in one
+// walk of the project folders instead of one walk per pattern, and finds
+// the projects in use so their targets are escalated. The projects are
+// listed on rep, if set.
+func prepareScan(ctx context.Context, cfg *config.Config, cleaners []cleaner.Cleaner, rep *reporter.Reporter) {
+	if walk, err := cleaner.NewSharedWalk(ctx, cfg, cleaners); err == nil {
+		cfg.SharedWalk = walk
+	}
+
+	home, err := cfg.HomeDir()
+	if err != nil {
+		return
+	}
+	cfg.OpenProjects = cleaner.FindOpenProjects(cleaner.ExecRunner{}, home)
+	if rep == nil || len(cfg.OpenProjects) == 0 {
+		return
+	}
+
+	projects := make([]string, 0, len(cfg.OpenProjects))
+	for root, reason := range cfg.OpenProjects {
+		projects = append(projects, fmt.Sprintf("%s (%s)", root, reason))
+	}
+	sort.Strings(projects)
+	treatment := "left alone below --level aggressive"
+	if cfg.CleanLevel.AllowsSafety(config.Dangerous) {
+		treatment = "marked Dangerous"
+	}
+	rep.PrintWarning(fmt.Sprintf("Projects in use, their build output is %s: %s", treatment, strings.Join(projects, ", ")))
+}
+
+// scanProgress shows how far a scan has gone through its cleaners, with the
+// time the whole scan has left. Each cleaner weighs what it took to scan last
+// time, so that the pace of the quick cleaners done first doesn't stand for
+// the slow ones still to come. Verbose runs print warnings along the way and
+// show none; without rep, the progress is only measured.
+type scanProgress struct {
+	rep      *reporter.Reporter
+	cleaners []cleaner.Cleaner
+	expected []time.Duration   // What each cleaner took to scan last time
+	total    time.Duration     // Sum of expected
+	times    history.ScanTimes // What the cleaners done took this time
+	done     int
+	started  time.Time
+	last     time.Time // When the last cleaner done finished
+}
+
+// newScanProgress starts showing the progress of a scan with cleaners
+func newScanProgress(rep *reporter.Reporter, cleaners []cleaner.Cleaner) *scanProgress {
+	names := make([]string, len(cleaners))
+	for i, c := range cleaners {
+		names[i] = c.Name()
+	}
+	var past history.ScanTimes
+	if path, err := history.DefaultScanTimesPath(); err == nil {
+		past = history.LoadScanTimes(path)
+	}
+
+	p := &scanProgress{rep: rep, cleaners: cleaners, expected: past.Expected(names), times: history.ScanTimes{}, started: time.Now()}
+	p.last = p.started
+	for _, d := range p.expected {
+		p.total += d
+	}
+	return p
+}
+
+// update shows that done cleaners have finished scanning. Once they all
+// have, their times are kept for the next scans.
+func (p *scanProgress) update(done int) {
+	now := time.Now()
+	for ; p.done < done && p.done < len(p.cleaners); p.done++ {
+		p.times[p.cleaners[p.done].Name()] = now.Sub(p.last)
+		p.last = now
+	}
+	if done == len(p.cleaners) {
+		// The times only make estimates better, losing them is harmless
+		if path, err := history.DefaultScanTimesPath(); err == nil {
+			_ = p.times.Save(path)
+		}
+	}
+
+	if p.rep == nil || verbose || len(p.cleaners) == 0 {
+		return
+	}
+	eta, _ := p.remaining()
+	p.rep.PrintProgressETA(done, len(p.cleaners), "Scanning", eta)
+}
+
+// remaining estimates the time the scan has left, from the pace of the
+// cleaners done so far against what they took last time
+func (p *scanProgress) remaining() (time.Duration, bool) {
+	var expectedDone time.Duration
+	for _, d := range p.expected[:p.done] {
+		expectedDone += d
+	}
+	return utils.EstimateRemaining(time.Since(p.started), int64(expectedDone), int64(p.total))
+}
+
+// scanTimed scans with every detected cleaner and returns the targets found
+// by cleaner, with how long each cleaner took. Cleaners that run out of time
+// are marked partial on rep.
+func scanTimed(ctx context.Context, cfg *config.Config, cleaners []cleaner.Cleaner, rep *reporter.Reporter) (map[string][]cleaner.CleanTarget, []reporter.CleanerTiming) {
+	prepareScan(ctx, cfg, cleaners, rep)
+	targetsByDomain := make(map[string][]cleaner.CleanTarget)
+	timings := []reporter.CleanerTiming{}
+
+	progress := newScanProgress(rep, cleaners)
+	for i, c := range cleaners {
+		progress.update(i)
+		cleanerStart := time.Now()
+		filesBefore, dirsBefore := profileTotals(cfg.ScanProfile)
+
+		isDetected, err := c.Detect(ctx)
+		if err != nil || !isDetected {
+			continue
+		}
+
+		targets, timedOut, err := cleaner.ScanWithTimeout(ctx, c, cfg)
+		if timedOut {
+			rep.MarkPartial(c.Name())
+		}
+
+		files, dirs := profileTotals(cfg.ScanProfile)
+		timings = append(timings, reporter.CleanerTiming{
+			Name:     c.Name(),
+			Duration: time.Since(cleanerStart),
+			Targets:  len(targets),
+			Files:    files - filesBefore,
+			Dirs:     dirs - dirsBefore,
+			TimedOut: timedOut,
+		})
+		if err != nil {
+			if verbose {
+				rep.PrintWarning(fmt.Sprintf("Scan error for %s: %v", c.Name(), err))
+			}
+			continue
+		}
+
+		if len(targets) > 0 {
+			targetsByDomain[c.Name()] = targets
+		}
+	}
+	progress.update(len(cleaners))
+	cleaner.SubtractOverlaps(targetsByDomain)
+
+	return targetsByDomain, timings
+}
+
+// startCPUProfile starts writing a CPU profile to path and returns the
+// function that stops it
+func startCPUProfile(path string) (func(), error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+	}
+
+	if err := pprof.StartCPUProfile(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+
+	return func() {
+		pprof.StopCPUProfile()
+		file.Close()
+	}, nil
+}
+
+// profileTotals returns the entries visited so far, or zeros without a profile
+func profileTotals(p *scanner.Profile) (files, dirs int) {
+	if p == nil {
+		return 0, 0
+	}
+	return p.Totals()
+}
+
 func filterCleanersByDomain(cleaners []cleaner.Cleaner, domains []string) []cleaner.Cleaner {
 	if len(domains) == 0 {
 		return cleaners
@@ -580,6 +1390,10 @@ func filterCleanersByDomain(cleaners []cleaner.Cleaner, domains []string) []clea
 	// Normalize domains to lowercase
 	domainMap := make(map[string]bool)
 	for _, d := range domains {
+		// Categories (frontend.npm_cache) are selected by applySelection
+		if domain, _, ok := strings.Cut(d, "."); ok && domain != "" {
+			d = domain
+		}
 		domainMap[toLower(d)] = true
 	}
 
@@ -614,13 +1428,14 @@ func toLower(s string) string {
 func matchesDomain(cleanerName, requestedDomain string) bool {
 	// Map cleaner names to domain keywords
 	domainMapping := map[string][]string{
-		"frontend":    {"frontend"},
-		"backend":     {"backend"},
-		"mobile":      {"mobile"},
-		"devops":      {"devops"},
-		"dataml":      {"data/ml", "dataml"},
-		"data/ml":     {"data/ml", "dataml"},
-		"system":      {"trash", "cache", "log", "temp", "dns", "homebrew", "xcode", "launchpad", "ios"},
+		"frontend": {"frontend", "electron"},
+		"backend":  {"backend", "version managers", ".net"},
+		"mobile":   {"mobile"},
+		"devops":   {"devops"},
+		"dataml":   {"data/ml", "dataml", "local llm"},
+		"data/ml":  {"data/ml", "dataml", "local llm"},
+		"gamedev":  {"game dev", "gamedev"},
+		"system":   {"trash", "cache", "log", "temp", "dns", "homebrew", "xcode", "launchpad", "ios"},
 	}
 
 	// Check direct match
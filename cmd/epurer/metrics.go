@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/history"
+	"github.com/0SansNom/epurer/internal/metrics"
+	"github.com/0SansNom/epurer/internal/reporter"
+)
+
+var (
+	// Report command metrics flags
+	metricsOut      string
+	metricsListen   string
+	metricsInterval time.Duration
+)
+
+// scanMetrics builds the metrics of a scan, with the last clean run read
+// from the history file
+func scanMetrics(cleaners []cleaner.Cleaner, targetsByDomain map[string][]cleaner.CleanTarget, scannedAt time.Time, scanDuration time.Duration) metrics.Snapshot {
+	snapshot := metrics.Snapshot{
+		ScannedAt:    scannedAt,
+		ScanDuration: scanDuration,
+		Reclaimable:  make(map[string]int64),
+	}
+
+	// Targets are grouped by cleaner, metrics by domain
+	for _, c := range cleaners {
+		for _, target := range targetsByDomain[c.Name()] {
+			snapshot.Reclaimable[c.Domain().Key()] += target.SizeBytes
+		}
+	}
+
+	if path, err := history.DefaultPath(); err == nil {
+		if runs, err := history.Load(path); err == nil && len(runs) > 0 {
+			snapshot.LastRun = &runs[len(runs)-1]
+		}
+	}
+	return snapshot
+}
+
+// serveMetrics serves the metrics on addr at /metrics, scanning again every
+// interval, until the server stops
+func serveMetrics(rep *reporter.Reporter, addr string, interval time.Duration, first metrics.Snapshot, scan func() metrics.Snapshot) error {
+	exporter := &metrics.Exporter{}
+	exporter.Set(first)
+
+	go func() {
+		for range time.Tick(interval) {
+			exporter.Set(scan())
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter)
+
+	rep.PrintInfo(fmt.Sprintf("Serving metrics on http://%s/metrics, scanning every %v", addr, interval))
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/plan"
+	"github.com/0SansNom/epurer/internal/reporter"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+var (
+	// Plan command flags
+	planOut string
+
+	// Apply command flags
+	sizeTolerance  float64
+	mtimeTolerance time.Duration
+)
+
+// newPlanCmd creates the plan command
+func newPlanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Save the cleanup targets to a plan file for review",
+		Long: `Scan the system like clean would and save every target to a JSON plan file
+instead of deleting anything. Review or edit the plan, then run
+"epurer apply <file>" to clean exactly those targets.`,
+		Args: cobra.NoArgs,
+		RunE: runPlan,
+	}
+
+	cmd.Flags().StringVarP(&planOut, "out", "o", "plan.json", "File to write the plan to")
+	cmd.Flags().StringVarP(&cleanLevel, "level", "l", "standard", "Clean level (conservative|standard|aggressive)")
+	cmd.Flags().StringSliceVarP(&domains, "domain", "d", []string{}, "Domains to scan, or domain.category entries such as frontend.npm_cache (comma-separated, empty = all)")
+	cmd.Flags().StringSliceVar(&only, "only", []string{}, "Target categories to keep, e.g. node_modules,gradle-cache (comma-separated, see report --verbose)")
+	cmd.Flags().IntVar(&cargoSweepDays, "cargo-sweep", 0, "Only plan Rust build artifacts older than N days instead of whole target/ folders")
+	cmd.Flags().IntVar(&installerAge, "installer-age", 30, "Only plan installers and disk images from Downloads and Desktop unused for N days")
+	addPruneFlags(cmd)
+	cmd.Flags().DurationVar(&scanTimeout, "scan-timeout", 60*time.Second, "Time budget for each cleaner's scan (0 = no limit)")
+	cmd.Flags().IntVar(&scanMaxDepth, "max-depth", scanner.DefaultMaxDepth, "Directory levels to scan below each project folder (0 = no limit)")
+	cmd.Flags().StringSliceVar(&scanExcludes, "exclude", []string{}, "Paths or directory names to skip when scanning projects (comma-separated)")
+	cmd.Flags().StringSliceVar(&volumePaths, "volume", []string{}, "Folders of external volumes to scan for projects too, e.g. /Volumes/Work (comma-separated)")
+	cmd.Flags().BoolVar(&allowNetworkVolumes, "allow-network", false, "Allow --volume folders on network mounts")
+	cmd.Flags().BoolVar(&scanWSL, "wsl", false, "Also scan the Windows side of WSL, or the WSL distributions from Windows, for tool caches")
+
+	return cmd
+}
+
+// newApplyCmd creates the apply command
+func newApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply <plan.json>",
+		Short: "Clean exactly the targets of a saved plan",
+		Long: `Clean the targets listed in a plan file written by "epurer plan", without
+scanning again. Before deleting anything every target is compared with the
+plan: if one changed size or modification time beyond the tolerance, the
+whole plan is refused.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runApply,
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be cleaned without actually deleting")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", true, "Ask for confirmation before cleaning")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 4, "Number of cleaners deleting at once")
+	cmd.Flags().StringVar(&webhookURL, "webhook", "", "POST the run summary to this URL after cleaning (JSON, or a Slack message for Slack webhooks)")
+	cmd.Flags().BoolVar(&quarantineMode, "quarantine", false, "Move targets to the quarantine instead of deleting them, to restore them with epurer restore")
+	cmd.Flags().Float64Var(&sizeTolerance, "size-tolerance", plan.DefaultTolerance.SizePercent, "Allowed size change of a target since planning, in percent")
+	cmd.Flags().DurationVar(&mtimeTolerance, "mtime-tolerance", plan.DefaultTolerance.ModTime, "Allowed modification time change of a target since planning")
+
+	return cmd
+}
+
+// runPlan executes the plan command
+func runPlan(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	rep := newReporter()
+
+	rep.PrintHeader()
+
+	// Parse clean level
+	level, err := config.ParseCleanLevel(cleanLevel)
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	// Create config
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	cfg.Verbose = verbose
+	cfg.CleanLevel = level
+	applyPolicy(rep, cfg)
+	cfg.CargoSweepDays = cargoSweepDays
+	cfg.InstallerMaxAgeDays = installerAge
+	if err := applyPruneFlags(cfg); err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	cfg.ScanTimeout = scanTimeout
+	cfg.ScanMaxDepth = scanMaxDepth
+	cfg.ScanExcludes = append(cfg.ScanExcludes, scanExcludes...)
+	if err := applySelection(cfg); err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	if err := applyVolumes(rep, cfg); err != nil {
+		return err
+	}
+
+	// Initialize cleaners
+	cleaners, err := initAllCleaners()
+	if err != nil {
+		rep.PrintError(fmt.Sprintf("Failed to initialize cleaners: %v", err))
+		return err
+	}
+
+	// Filter by domain if specified
+	if len(domains) > 0 {
+		cleaners = filterCleanersByDomain(cleaners, domains)
+	}
+
+	// Scan
+	rep.PrintInfo("Scanning system...")
+
+	prepareScan(ctx, cfg, cleaners, rep)
+	targetsByDomain := make(map[string][]cleaner.CleanTarget)
+
+	progress := newScanProgress(rep, cleaners)
+	for i, c := range cleaners {
+		progress.update(i)
+		isDetected, err := c.Detect(ctx)
+		if err != nil || !isDetected {
+			continue
+		}
+
+		targets, timedOut, err := cleaner.ScanWithTimeout(ctx, c, cfg)
+		if timedOut {
+			rep.MarkPartial(c.Name())
+		}
+		if err != nil {
+			if verbose {
+				rep.PrintWarning(fmt.Sprintf("Scan error for %s: %v", c.Name(), err))
+			}
+			continue
+		}
+
+		if len(targets) > 0 {
+			targetsByDomain[c.Name()] = targets
+		}
+	}
+	progress.update(len(cleaners))
+	cleaner.SubtractOverlaps(targetsByDomain)
+	warnUnselected(rep, cfg, cleaners, targetsByDomain)
+
+	rep.PrintEstimation(targetsByDomain)
+	rep.PrintSafetyLegend()
+
+	p := plan.New(targetsByDomain, level)
+	if err := plan.Save(planOut, p); err != nil {
+		rep.PrintError(fmt.Sprintf("Failed to write plan: %v", err))
+		return err
+	}
+
+	rep.PrintSuccess(fmt.Sprintf("Plan with %d targets saved to %s", len(p.Items), planOut))
+	rep.PrintInfo(fmt.Sprintf("Review it, then run `epurer apply %s`", planOut))
+
+	return nil
+}
+
+// runApply executes the apply command
+func runApply(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	rep := newReporter()
+
+	rep.PrintHeader()
+
+	p, err := plan.Load(args[0])
+	if err != nil {
+		rep.PrintError(fmt.Sprintf("Failed to read plan: %v", err))
+		return err
+	}
+
+	// Hooks, the webhook and the admin policy come from the config file
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	applyWebhookFlag(cfg)
+	cfg.Quarantine = cfg.Quarantine || quarantineMode
+
+	// Initialize cleaners
+	cleaners, err := initAllCleaners()
+	if err != nil {
+		rep.PrintError(fmt.Sprintf("Failed to initialize cleaners: %v", err))
+		return err
+	}
+	enforcePlanPolicy(rep, p, cfg, cleaners)
+
+	targetsByDomain := p.Targets()
+	if len(targetsByDomain) == 0 {
+		rep.PrintInfo(lang.T("results.nothing"))
+		return nil
+	}
+
+	rep.PrintInfo(fmt.Sprintf("Plan created %s at %s level", p.CreatedAt.Format("2006-01-02 15:04"), p.Level))
+	rep.PrintEstimation(targetsByDomain)
+	rep.PrintSafetyLegend()
+
+	// Refuse the whole plan if the disk no longer matches it
+	drifts := p.Verify(plan.Tolerance{SizePercent: sizeTolerance, ModTime: mtimeTolerance})
+	if len(drifts) > 0 {
+		for _, drift := range drifts {
+			rep.PrintWarning(fmt.Sprintf("%s: %s", drift.Item.Path, drift.Reason))
+		}
+		err := fmt.Errorf("%d targets changed since the plan was created, run `epurer plan` again", len(drifts))
+		rep.PrintError(err.Error())
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	pending := len(p.Pending())
+	if interactive || dryRun {
+		targets := []cleaner.CleanTarget{}
+		for _, i := range p.Pending() {
+			targets = append(targets, p.Items[i].Target())
+		}
+		rep.PrintConsequences(cleaner.Consequences(targets))
+	}
+	if interactive && !dryRun {
+		if !rep.AskConfirmation(lang.T("prompt.proceed", pending)) {
+			rep.PrintInfo(lang.T("prompt.cancelled"))
+			return nil
+		}
+	}
+
+	if dryRun {
+		rep.PrintInfo("DRY RUN - No files will be deleted")
+	}
+
+	manifestPath, err := plan.ManifestPath()
+	if err != nil {
+		rep.PrintWarning(fmt.Sprintf("This run can't be resumed: %v", err))
+	}
+
+	return cleanPlan(ctx, cmd, rep, cleaners, p, "apply", manifestPath, dryRun, jobs, cfg)
+}
+
+// enforcePlanPolicy drops the pending items of p the admin policy does not
+// allow, and the entries it excludes from the others. A plan may have been
+// written before the policy changed, on another machine or by hand.
+func enforcePlanPolicy(rep *reporter.Reporter, p *plan.Plan, cfg *config.Config, cleaners []cleaner.Cleaner) {
+	if cfg.Policy == nil {
+		return
+	}
+	domains := make(map[string]config.Domain, len(cleaners))
+	for _, c := range cleaners {
+		domains[c.Name()] = c.Domain()
+	}
+
+	items := make([]plan.Item, 0, len(p.Items))
+	for _, item := range p.Items {
+		domain, ok := domains[item.Cleaner]
+		if item.Status != plan.StatusPending || !ok {
+			items = append(items, item)
+			continue
+		}
+		kept := cleaner.EnforcePolicy(cfg, domain, []cleaner.CleanTarget{item.Target()})
+		if len(kept) == 0 {
+			rep.PrintWarning(fmt.Sprintf("%s: not allowed by the policy in %s, skipped", item.Path, cfg.Policy.Path))
+			continue
+		}
+		item.Entries, item.SizeBytes = kept[0].Entries, kept[0].SizeBytes
+		items = append(items, item)
+	}
+	p.Items = items
+}
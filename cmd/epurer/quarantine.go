@@ -0,0 +1,315 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/quarantine"
+	"github.com/0SansNom/epurer/internal/reporter"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+var (
+	// Restore and quarantine command flags
+	restoreAll     bool
+	purgeOlderThan string
+)
+
+// newRestoreCmd creates the restore command
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [paths...]",
+		Short: "Move quarantined targets back to where they were",
+		Long: `Move the targets a clean run put in the quarantine (with --quarantine, or
+"quarantine": true in the config file) back to their original paths. Naming
+a folder restores every target quarantined from inside it. Each target is
+checked against the checksum recorded when it was quarantined first: targets
+whose copy changed, or whose original path is taken again, are left in the
+quarantine.`,
+		RunE: runRestore,
+	}
+
+	cmd.Flags().BoolVar(&restoreAll, "all", false, "Restore every target in the quarantine")
+
+	return cmd
+}
+
+// newQuarantineCmd creates the quarantine command
+func newQuarantineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quarantine",
+		Short: "List, inspect and purge the targets kept in the quarantine",
+		Args:  cobra.NoArgs,
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the targets kept in the quarantine",
+		Args:  cobra.NoArgs,
+		RunE:  runQuarantineList,
+	}
+
+	purge := &cobra.Command{
+		Use:   "purge",
+		Short: "Delete quarantined targets for good to reclaim their space",
+		Long: `Delete the targets kept in the quarantine for good. With --older-than, only
+the targets quarantined before that age are deleted, e.g. --older-than 7d.
+Only the targets recorded in the quarantine manifest are deleted.`,
+		Args: cobra.NoArgs,
+		RunE: runQuarantinePurge,
+	}
+	purge.Flags().StringVar(&purgeOlderThan, "older-than", "", "Only delete targets quarantined before this age (e.g. 7d, 12h)")
+	purge.Flags().BoolVarP(&interactive, "interactive", "i", true, "Ask for confirmation before deleting")
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Show what the quarantine holds and its limits",
+		Args:  cobra.NoArgs,
+		RunE:  runQuarantineStatus,
+	}
+
+	cmd.AddCommand(list, status, purge)
+	return cmd
+}
+
+// runRestore executes the restore command
+func runRestore(cmd *cobra.Command, args []string) error {
+	rep := newReporter()
+
+	rep.PrintHeader()
+
+	if len(args) == 0 && !restoreAll {
+		err := errors.New("name the paths to restore, or pass --all")
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	store, err := openQuarantine(rep)
+	if err != nil {
+		return err
+	}
+	entries, err := store.Entries()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	var prefixes []string
+	for _, arg := range args {
+		path, err := utils.ExpandHome(arg)
+		if err == nil {
+			path, err = filepath.Abs(path)
+		}
+		if err != nil {
+			rep.PrintError(err.Error())
+			return err
+		}
+		prefixes = append(prefixes, path)
+	}
+
+	// Newest first: a path quarantined twice gets its latest copy back, and
+	// the older one collides with it
+	restored, failed := 0, 0
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if !restoreAll && !matchesAny(entry.OriginalPath, prefixes) {
+			continue
+		}
+
+		if err := store.Restore(entry); err != nil {
+			failed++
+			switch {
+			case errors.Is(err, quarantine.ErrCollision):
+				rep.PrintWarning(fmt.Sprintf("%s exists again, its quarantined copy was kept in %s", entry.OriginalPath, store.Path(entry)))
+			case errors.Is(err, quarantine.ErrModified):
+				rep.PrintWarning(fmt.Sprintf("The quarantined copy of %s changed since it was quarantined, it was kept in %s", entry.OriginalPath, store.Path(entry)))
+			default:
+				rep.PrintWarning(fmt.Sprintf("Error restoring %s: %v", entry.OriginalPath, err))
+			}
+			continue
+		}
+		restored++
+		if verbose {
+			rep.PrintInfo("Restored " + entry.OriginalPath)
+		}
+	}
+
+	if restored == 0 && failed == 0 {
+		rep.PrintInfo("Nothing in the quarantine matches")
+		return nil
+	}
+	rep.PrintSuccess(fmt.Sprintf("%d targets restored", restored))
+	if failed > 0 {
+		return fmt.Errorf("%d targets could not be restored", failed)
+	}
+	return nil
+}
+
+// runQuarantineList executes the quarantine list command
+func runQuarantineList(cmd *cobra.Command, args []string) error {
+	rep := newReporter()
+
+	store, err := openQuarantine(rep)
+	if err != nil {
+		return err
+	}
+	entries, err := store.Entries()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	rep.PrintQuarantine(entries)
+	return nil
+}
+
+// runQuarantineStatus executes the quarantine status command
+func runQuarantineStatus(cmd *cobra.Command, args []string) error {
+	rep := newReporter()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	store, err := openQuarantine(rep)
+	if err != nil {
+		return err
+	}
+	status, err := store.Status()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	rep.PrintQuarantineStatus(store.Dir, status, quarantineLimits(cfg), cfg.Quarantine)
+	return nil
+}
+
+// runQuarantinePurge executes the quarantine purge command
+func runQuarantinePurge(cmd *cobra.Command, args []string) error {
+	rep := newReporter()
+
+	rep.PrintHeader()
+
+	cutoff := time.Now()
+	if purgeOlderThan != "" {
+		age, err := parseAge(purgeOlderThan)
+		if err != nil {
+			rep.PrintError(err.Error())
+			return err
+		}
+		cutoff = cutoff.Add(-age)
+	}
+
+	store, err := openQuarantine(rep)
+	if err != nil {
+		return err
+	}
+	entries, err := store.Entries()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	count, size := 0, int64(0)
+	for _, entry := range entries {
+		if entry.QuarantinedAt.Before(cutoff) {
+			count++
+			size += entry.SizeBytes
+		}
+	}
+	if count == 0 {
+		rep.PrintInfo("Nothing to purge")
+		return nil
+	}
+
+	if interactive && !rep.AskConfirmation(fmt.Sprintf("Delete %d quarantined targets (%s) for good?", count, utils.FormatBytes(size))) {
+		rep.PrintInfo(lang.T("prompt.cancelled"))
+		return nil
+	}
+
+	report, err := store.Purge(cutoff)
+	for _, purgeErr := range report.Errors {
+		rep.PrintWarning(purgeErr.Error())
+	}
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	rep.PrintSuccess(fmt.Sprintf("%d targets purged, %s reclaimed", report.Entries, utils.FormatBytes(report.Bytes)))
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("%d targets could not be purged", len(report.Errors))
+	}
+	return nil
+}
+
+// quarantineLimits returns the retention period and size cap of the
+// quarantine set in the config
+func quarantineLimits(cfg *config.Config) quarantine.Limits {
+	return quarantine.Limits{
+		MaxAge:   time.Duration(cfg.QuarantineMaxAgeDays) * 24 * time.Hour,
+		MaxBytes: cfg.QuarantineMaxBytes,
+	}
+}
+
+// expireQuarantine purges the quarantined targets past the retention period,
+// then the oldest ones while the quarantine is over its size cap
+func expireQuarantine(rep *reporter.Reporter, store *quarantine.Store, cfg *config.Config) {
+	report, err := store.Expire(quarantineLimits(cfg))
+	if err != nil {
+		rep.PrintWarning(fmt.Sprintf("Failed to expire quarantined targets: %v", err))
+		return
+	}
+	for _, purgeErr := range report.Errors {
+		rep.PrintWarning(purgeErr.Error())
+	}
+	if report.Entries > 0 {
+		rep.PrintInfo(fmt.Sprintf("%d expired quarantined targets purged, %s reclaimed", report.Entries, utils.FormatBytes(report.Bytes)))
+	}
+}
+
+// openQuarantine returns the quarantine of the state directory
+func openQuarantine(rep *reporter.Reporter) (*quarantine.Store, error) {
+	dir, err := quarantine.DefaultDir()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return nil, err
+	}
+	return quarantine.New(dir), nil
+}
+
+// matchesAny reports whether path is or lies inside one of dirs
+func matchesAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if utils.HasPathPrefix(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAge parses an age in days ("7d") or as a Go duration ("12h")
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q, expected e.g. 7d or 12h", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	age, err := time.ParseDuration(s)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q, expected e.g. 7d or 12h", s)
+	}
+	return age, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/diagnostics"
+	"github.com/0SansNom/epurer/internal/history"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+var (
+	// Self-report command flags
+	selfReportOut string
+	skipScan      bool
+)
+
+// newSelfReportCmd creates the self-report command
+func newSelfReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "self-report",
+		Short: "Bundle diagnostics into a zip to attach to a bug report",
+		Long: `Gather the version, the configuration, the last clean run, the detected
+tools and how long each cleaner takes to scan into a zip archive to attach to
+a bug report. Nothing is deleted.
+
+The user name is replaced everywhere, and every path outside a known cache
+location (such as ~/Library/Caches, ~/.npm or ~/.gradle) is replaced by
+<redacted>, so the archive does not reveal your projects. Review it before
+sharing it anyway.`,
+		Args: cobra.NoArgs,
+		RunE: runSelfReport,
+	}
+
+	cmd.Flags().StringVarP(&selfReportOut, "out", "o", "", "Archive to write (default epurer-report-<date>.zip)")
+	cmd.Flags().BoolVar(&skipScan, "skip-scan", false, "Leave out the scan timings instead of running a scan")
+
+	return cmd
+}
+
+// runSelfReport executes the self-report command
+func runSelfReport(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	rep := newReporter()
+	now := time.Now()
+
+	rep.PrintHeader()
+
+	// A broken config file is worth reporting rather than a reason to stop
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintWarning(err.Error())
+		cfg = config.NewDefaultConfig()
+	}
+
+	bundle := diagnostics.Bundle{Version: version, Config: cfg}
+
+	bundle.Detected = detector.FromContext(ctx).DetectAll()
+
+	if path, err := history.DefaultPath(); err == nil {
+		if runs, err := history.Load(path); err == nil && len(runs) > 0 {
+			bundle.LastRun = &runs[len(runs)-1]
+		}
+	}
+
+	if !skipScan {
+		cleaners, err := initAllCleaners()
+		if err != nil {
+			rep.PrintError(fmt.Sprintf("Failed to initialize cleaners: %v", err))
+			return err
+		}
+
+		rep.PrintInfo("Timing a scan (this may take a while)...")
+		cfg.ScanProfile = scanner.NewProfile()
+		_, timings := scanTimed(ctx, cfg, cleaners, rep)
+		for _, timing := range timings {
+			bundle.Timings = append(bundle.Timings, diagnostics.Timing{
+				Cleaner:  timing.Name,
+				Duration: timing.Duration,
+				Targets:  timing.Targets,
+				Files:    timing.Files,
+				Dirs:     timing.Dirs,
+				TimedOut: timing.TimedOut,
+			})
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	username := filepath.Base(home)
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+
+	out := selfReportOut
+	if out == "" {
+		out = fmt.Sprintf("epurer-report-%s.zip", now.Format("20060102-150405"))
+	}
+
+	file, err := os.Create(out)
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	if err := bundle.Write(file, diagnostics.NewRedactor(home, username), now); err != nil {
+		file.Close()
+		rep.PrintError(err.Error())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	rep.PrintSuccess(fmt.Sprintf("Diagnostic report written to %s", out))
+	rep.PrintInfo("Check its contents before attaching it to a bug report")
+	return nil
+}
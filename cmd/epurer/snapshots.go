@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0SansNom/epurer/internal/disk"
+	"github.com/0SansNom/epurer/internal/reporter"
+)
+
+// startupVolume is the volume whose snapshots are listed and thinned
+const startupVolume = "/"
+
+var (
+	// Snapshots command flags
+	thinSnapshots bool
+)
+
+// newSnapshotsCmd creates the snapshots command
+func newSnapshotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshots",
+		Short: "List and thin local Time Machine snapshots",
+		Long: `List the local Time Machine snapshots of the startup volume and the purgeable
+space they hold. APFS keeps deleted files in these snapshots, so space freed
+by cleaning may not show up in Finder until they are thinned. Thinning never
+touches the backups on your Time Machine disk.`,
+		Args: cobra.NoArgs,
+		RunE: runSnapshots,
+	}
+
+	cmd.Flags().BoolVar(&thinSnapshots, "thin", false, "Thin local snapshots to release their purgeable space")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", true, "Ask for confirmation before thinning")
+
+	return cmd
+}
+
+// runSnapshots executes the snapshots command
+func runSnapshots(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	rep := newReporter()
+
+	rep.PrintHeader()
+
+	if !disk.TimeMachineAvailable() {
+		rep.PrintInfo("Time Machine is not available on this system")
+		return nil
+	}
+
+	snapshots, err := disk.ListSnapshots(ctx, startupVolume)
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	purgeable, _ := disk.PurgeableBytes(ctx, startupVolume)
+	rep.PrintSnapshots(snapshots, purgeable)
+
+	if !thinSnapshots || len(snapshots) == 0 {
+		return nil
+	}
+
+	if interactive && !rep.AskConfirmation(fmt.Sprintf("Thin %d local snapshots?", len(snapshots))) {
+		rep.PrintInfo(lang.T("prompt.cancelled"))
+		return nil
+	}
+
+	return thinLocalSnapshots(ctx, rep)
+}
+
+// reviewSnapshots runs after a clean: if local snapshots still hold the
+// freed space, it lists them and offers to thin them (or explains how to,
+// when not interactive)
+func reviewSnapshots(ctx context.Context, rep *reporter.Reporter) {
+	if !disk.TimeMachineAvailable() {
+		return
+	}
+
+	snapshots, err := disk.ListSnapshots(ctx, startupVolume)
+	if err != nil || len(snapshots) == 0 {
+		return
+	}
+
+	purgeable, _ := disk.PurgeableBytes(ctx, startupVolume)
+	rep.PrintSnapshots(snapshots, purgeable)
+
+	if !interactive {
+		rep.PrintInfo("Run `epurer snapshots --thin` to release the space they hold")
+		return
+	}
+
+	if rep.AskConfirmation("Thin local Time Machine snapshots now?") {
+		thinLocalSnapshots(ctx, rep)
+	}
+}
+
+// thinLocalSnapshots thins the local snapshots of the startup volume and reports how many
+// are left
+func thinLocalSnapshots(ctx context.Context, rep *reporter.Reporter) error {
+	if err := disk.ThinSnapshots(ctx, startupVolume); err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	remaining, err := disk.ListSnapshots(ctx, startupVolume)
+	if err != nil {
+		rep.PrintSuccess("Local snapshots thinned")
+		return nil
+	}
+
+	rep.PrintSuccess(fmt.Sprintf("Local snapshots thinned, %d left", len(remaining)))
+	return nil
+}
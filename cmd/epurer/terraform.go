@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+var (
+	// Terraform command flags
+	configurePluginCache bool
+)
+
+// newTerraformCmd creates the terraform command
+func newTerraformCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "terraform",
+		Short: "Find Terraform providers duplicated across projects",
+		Long: `List the Terraform provider releases installed in more than one .terraform
+folder and the space a shared plugin cache would save. With --configure-cache,
+plugin_cache_dir is added to ~/.terraformrc so that terraform init installs
+each provider once and links it into the projects. Deleting stale .terraform
+folders is still done by clean (DevOps domain).`,
+		Args: cobra.NoArgs,
+		RunE: runTerraform,
+	}
+
+	cmd.Flags().BoolVar(&configurePluginCache, "configure-cache", false, "Set up a shared plugin cache in ~/.terraformrc")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", true, "Ask for confirmation before changing ~/.terraformrc")
+	cmd.Flags().IntVar(&scanMaxDepth, "max-depth", scanner.DefaultMaxDepth, "Directory levels to scan below each project folder (0 = no limit)")
+	cmd.Flags().StringSliceVar(&scanExcludes, "exclude", []string{}, "Paths or directory names to skip when scanning projects (comma-separated)")
+
+	return cmd
+}
+
+// runTerraform executes the terraform command
+func runTerraform(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	rep := newReporter()
+
+	rep.PrintHeader()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+	cfg.ScanMaxDepth = scanMaxDepth
+	cfg.ScanExcludes = append(cfg.ScanExcludes, scanExcludes...)
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	rep.PrintInfo("Looking for .terraform folders...")
+	dirs, err := cleaner.FindTerraformDirs(ctx, cfg)
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	duplicates := cleaner.TerraformProviderDuplicates(dirs)
+	cacheDir := cleaner.TerraformPluginCacheDir(home)
+	rep.PrintTerraformDuplicates(duplicates, cacheDir)
+
+	if !configurePluginCache {
+		if cacheDir == "" && len(duplicates) > 0 {
+			rep.PrintInfo("Run `epurer terraform --configure-cache` to share providers between projects")
+		}
+		return nil
+	}
+
+	if cacheDir != "" {
+		rep.PrintInfo(fmt.Sprintf("Terraform already uses the plugin cache %s", cacheDir))
+		return nil
+	}
+
+	if interactive && !rep.AskConfirmation(fmt.Sprintf("Add a shared plugin cache to %s?", cleaner.TerraformRCPath(home))) {
+		rep.PrintInfo(lang.T("prompt.cancelled"))
+		return nil
+	}
+
+	cacheDir, err = cleaner.ConfigureTerraformPluginCache(home)
+	if err != nil {
+		rep.PrintError(err.Error())
+		return err
+	}
+
+	rep.PrintSuccess(fmt.Sprintf("Plugin cache set to %s; run terraform init in each project, then clean their .terraform folders", cacheDir))
+	return nil
+}
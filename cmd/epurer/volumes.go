@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/reporter"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+var (
+	// Volume flags of clean, report and plan
+	volumePaths         []string
+	allowNetworkVolumes bool
+	scanWSL             bool
+)
+
+// applyVolumes checks the --volume folders and adds them to the folders
+// scanned for projects. Time Machine disks are refused, and so are network
+// mounts unless --allow-network is set. With --wsl, the other side of WSL is
+// scanned too.
+func applyVolumes(rep *reporter.Reporter, cfg *config.Config) error {
+	cfg.ScanWSL = scanWSL
+	for _, path := range volumePaths {
+		v, err := checkVolume(path)
+		if err != nil {
+			rep.PrintError(err.Error())
+			return err
+		}
+		cfg.Volumes = append(cfg.Volumes, path)
+		rep.PrintVolumeSpace(path, v, nil)
+	}
+	return nil
+}
+
+// checkVolume returns the volume a --volume folder is on, if it can be
+// scanned
+func checkVolume(path string) (utils.Volume, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return utils.Volume{}, fmt.Errorf("invalid --volume %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return utils.Volume{}, fmt.Errorf("invalid --volume %s: not a folder", path)
+	}
+
+	v, err := utils.VolumeOf(path)
+	if err != nil {
+		return utils.Volume{}, fmt.Errorf("invalid --volume %s: %w", path, err)
+	}
+	if utils.IsTimeMachineVolume(v.MountPoint) {
+		return utils.Volume{}, fmt.Errorf("refusing --volume %s: %s is a Time Machine backup disk", path, v.MountPoint)
+	}
+	if v.IsNetwork() && !allowNetworkVolumes {
+		return utils.Volume{}, fmt.Errorf("refusing --volume %s: %s is a network mount (%s), pass --allow-network to scan it anyway", path, v.MountPoint, v.FSType)
+	}
+	return v, nil
+}
+
+// volumesBefore returns the volumes of the --volume folders, before cleaning
+func volumesBefore(cfg *config.Config) map[string]utils.Volume {
+	before := make(map[string]utils.Volume)
+	for _, path := range cfg.Volumes {
+		if v, err := utils.VolumeOf(path); err == nil {
+			before[path] = v
+		}
+	}
+	return before
+}
+
+// printVolumesAfter prints the free space of the --volume folders after
+// cleaning, next to what it was before
+func printVolumesAfter(rep *reporter.Reporter, before map[string]utils.Volume) {
+	for path, v := range before {
+		after, err := utils.VolumeOf(path)
+		if err != nil {
+			continue
+		}
+		rep.PrintVolumeSpace(path, v, &after)
+	}
+}
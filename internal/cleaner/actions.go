@@ -0,0 +1,161 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// lsregister rebuilds the Launch Services database
+const lsregister = "/System/Library/Frameworks/CoreServices.framework/Frameworks/LaunchServices.framework/Support/lsregister"
+
+// actionCommand is a command run by a maintenance action
+type actionCommand struct {
+	args     []string
+	optional bool // Its failure doesn't fail the action (e.g. it needs sudo)
+}
+
+// maintenanceAction resets a macOS database or cache by running commands
+// instead of deleting files; macOS rebuilds it afterwards. Targets for an
+// action have ActionRun and the action's category. The commands are looked
+// up by category when cleaning, so a hand-edited plan cannot run others.
+type maintenanceAction struct {
+	description string
+	safety      config.SafetyLevel
+	remove      bool // Delete the target's path before running the commands
+	commands    []actionCommand
+}
+
+// maintenanceActions are the known actions by category
+var maintenanceActions = map[string]maintenanceAction{
+	"dns_cache": {
+		description: "Flush the DNS cache",
+		safety:      config.Safe,
+		commands: []actionCommand{
+			{args: []string{"dscacheutil", "-flushcache"}},
+			{args: []string{"killall", "-HUP", "mDNSResponder"}, optional: true},
+		},
+	},
+	"launchpad_layout": {
+		description: "Reset the Launchpad layout (apps go back to the default order)",
+		safety:      config.Moderate,
+		commands: []actionCommand{
+			{args: []string{"defaults", "write", "com.apple.dock", "ResetLaunchPad", "-bool", "true"}},
+			{args: []string{"killall", "Dock"}},
+		},
+	},
+	"launch_services": {
+		description: "Rebuild the Launch Services database (duplicate \"Open With\" entries)",
+		safety:      config.Moderate,
+		commands: []actionCommand{
+			{args: []string{lsregister, "-kill", "-r", "-domain", "local", "-domain", "system", "-domain", "user"}},
+		},
+	},
+	"dock_icon_cache": {
+		description: "Rebuild the Dock icon cache (wrong or blank Dock icons)",
+		safety:      config.Moderate,
+		remove:      true,
+		commands: []actionCommand{
+			{args: []string{"killall", "Dock"}},
+		},
+	},
+}
+
+// ActionCommands returns the command lines a target with ActionRun runs, or
+// nil for other targets
+func (t CleanTarget) ActionCommands() []string {
+	action, ok := maintenanceActions[t.Category]
+	if t.Action != ActionRun || !ok {
+		return nil
+	}
+
+	lines := make([]string, 0, len(action.commands))
+	for _, command := range action.commands {
+		lines = append(lines, commandLine(command.args[0], command.args[1:]...))
+	}
+	return lines
+}
+
+// actionTarget returns the target of the action of a category, found at path
+func actionTarget(category, path string, size int64) CleanTarget {
+	action := maintenanceActions[category]
+	return CleanTarget{
+		Path:        path,
+		Category:    category,
+		Description: action.description,
+		SizeBytes:   size,
+		Safety:      action.safety,
+		Action:      ActionRun,
+	}
+}
+
+// runAction performs the maintenance action of a target with runner, which
+// records the commands in dry runs. The target's path is only deleted for
+// actions that remove it, and never in dry runs.
+func runAction(runner CommandRunner, target CleanTarget, dryRun bool) CleanResult {
+	action, ok := maintenanceActions[target.Category]
+	if !ok {
+		return CleanResult{Target: target, Error: fmt.Errorf("unknown action %q", target.Category)}
+	}
+
+	result := CleanResult{Target: target, Success: true}
+	if action.remove {
+		if dryRun {
+			result.BytesFreed = target.SizeBytes
+		} else if result = removeMeasured(context.Background(), target); !result.Success {
+			return result
+		}
+	}
+
+	for _, command := range action.commands {
+		if err := runner.Run(command.args[0], command.args[1:]...); err != nil && !command.optional {
+			result.Success = false
+			result.Error = fmt.Errorf("%s: %w", commandLine(command.args[0], command.args[1:]...), err)
+			return result
+		}
+	}
+
+	return result
+}
+
+// scanActions returns the maintenance actions that apply on this Mac.
+// tmpDir is the per-user temporary directory the Dock icon cache sits next
+// to.
+func scanActions(cfg *config.Config, home, tmpDir string) []CleanTarget {
+	targets := []CleanTarget{}
+	add := func(category, path string, size int64) {
+		if cfg.Allows(config.DomainSystem, category, maintenanceActions[category].safety) {
+			targets = append(targets, actionTarget(category, path, size))
+		}
+	}
+
+	if dock := filepath.Join(home, "Library", "Application Support", "Dock"); utils.PathExists(dock) {
+		add("launchpad_layout", dock, 0)
+	}
+	if utils.PathExists(lsregister) {
+		add("launch_services", lsregister, 0)
+	}
+	if cacheDir, ok := darwinUserCacheDir(tmpDir); ok {
+		iconCache := filepath.Join(cacheDir, "com.apple.dock.iconcache")
+		if info, err := os.Stat(iconCache); err == nil {
+			add("dock_icon_cache", iconCache, info.Size())
+		}
+	}
+
+	return targets
+}
+
+// darwinUserCacheDir returns the per-user cache folder of macOS from the
+// per-user temporary directory: $TMPDIR is /var/folders/xx/yyy/T, the cache
+// folder is .../C. It reports false for other temporary directories.
+func darwinUserCacheDir(tmpDir string) (string, bool) {
+	tmpDir = filepath.Clean(tmpDir)
+	if filepath.Base(tmpDir) != "T" {
+		return "", false
+	}
+	return filepath.Join(filepath.Dir(tmpDir), "C"), true
+}
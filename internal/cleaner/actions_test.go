@@ -0,0 +1,111 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestScanActions(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	tmpDir := filepath.Join(home, "folders", "ab", "xyz", "T")
+	createTestFile(t, home, "Library/Application Support/Dock/desktoppicture.db", "db")
+	iconCache := createTestFile(t, filepath.Dir(tmpDir), "C/com.apple.dock.iconcache", "icons")
+
+	cfg := config.NewDefaultConfig()
+	targets := scanActions(cfg, home, tmpDir)
+
+	byCategory := make(map[string]CleanTarget)
+	for _, target := range targets {
+		if target.Action != ActionRun {
+			t.Errorf("Expected %s to be an action, got %s", target.Category, target.Action)
+		}
+		byCategory[target.Category] = target
+	}
+	if _, ok := byCategory["launchpad_layout"]; !ok {
+		t.Errorf("Expected the Launchpad reset, got %v", targets)
+	}
+	if icons := byCategory["dock_icon_cache"]; icons.Path != iconCache || icons.SizeBytes != 5 {
+		t.Errorf("Expected the Dock icon cache with its size, got %+v", icons)
+	}
+
+	// The Launchpad reset loses the layout, the rebuilds restart the Dock
+	// or Launch Services: none is offered in conservative mode
+	cfg.CleanLevel = config.Conservative
+	if targets := scanActions(cfg, home, tmpDir); len(targets) != 0 {
+		t.Errorf("Expected no actions in conservative mode, got %+v", targets)
+	}
+}
+
+func TestRunAction(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	iconCache := createTestFile(t, tmpDir, "com.apple.dock.iconcache", "icons")
+	target := actionTarget("dock_icon_cache", iconCache, 5)
+
+	// Dry run: the commands are recorded, nothing is deleted
+	runner := &RecordingRunner{}
+	result := runAction(runner, target, true)
+	if !result.Success || result.BytesFreed != 5 {
+		t.Errorf("Unexpected dry run result %+v", result)
+	}
+	if _, err := os.Stat(iconCache); err != nil {
+		t.Error("Dry run deleted the icon cache")
+	}
+	if commands := runner.Commands(); !slices.Equal(commands, target.ActionCommands()) {
+		t.Errorf("Expected %v to run, got %v", target.ActionCommands(), commands)
+	}
+
+	result = runAction(runner, target, false)
+	if !result.Success || result.BytesFreed != 5 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if _, err := os.Stat(iconCache); !os.IsNotExist(err) {
+		t.Error("Expected the icon cache to be deleted")
+	}
+
+	// Optional commands may fail, others fail the action
+	runner = &RecordingRunner{Errors: map[string]error{"killall -HUP mDNSResponder": errors.New("not permitted")}}
+	if result := runAction(runner, actionTarget("dns_cache", "system:dns_cache", 0), false); !result.Success {
+		t.Errorf("Expected the DNS flush to succeed without sudo, got %v", result.Error)
+	}
+	runner = &RecordingRunner{Errors: map[string]error{"killall Dock": errors.New("no Dock")}}
+	if result := runAction(runner, actionTarget("launchpad_layout", "/dock", 0), false); result.Success || result.Error == nil {
+		t.Error("Expected the Launchpad reset to fail when the Dock cannot be restarted")
+	}
+
+	// A category without an action never runs anything
+	runner = &RecordingRunner{}
+	result = runAction(runner, CleanTarget{Path: "/x", Category: "bogus", Action: ActionRun}, false)
+	if result.Success || len(runner.Commands()) != 0 {
+		t.Errorf("Expected an unknown action to fail without running commands, got %+v", result)
+	}
+}
+
+func TestSystemCleaner_DNS_Action(t *testing.T) {
+	runner := &RecordingRunner{}
+	s := NewDNSCacheCleaner().(*SystemCleaner)
+	s.SetRunner(runner)
+
+	targets, err := s.Scan(context.Background(), config.NewDefaultConfig())
+	if err != nil || len(targets) != 1 || targets[0].Action != ActionRun {
+		t.Fatalf("Expected a DNS flush action, got %v, %v", targets, err)
+	}
+
+	results, _ := s.Clean(context.Background(), targets, true)
+	expected := []string{"dscacheutil -flushcache", "killall -HUP mDNSResponder"}
+	if !slices.Equal(results[0].Commands, expected) {
+		t.Errorf("Expected dry run to list %v, got %v", expected, results[0].Commands)
+	}
+	if len(runner.Commands()) != 0 {
+		t.Error("Dry run ran commands")
+	}
+}
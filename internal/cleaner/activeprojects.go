@@ -0,0 +1,116 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+// recentActivity is how long after its project last changed a dependency
+// folder is still escalated: the project is being worked on and needs it
+const recentActivity = 7 * day
+
+// activityCategories are the Moderate categories escalated while their
+// project is active
+var activityCategories = map[string]bool{
+	"node_modules": true,
+}
+
+// lastActivity returns when the project in dir last changed: when the HEAD
+// of its git repository last moved (a commit, checkout or pull), or else
+// the newest modification time of its entries other than skip. It returns
+// the zero time if neither is known.
+func lastActivity(dir, skip, home string) time.Time {
+	if changed, ok := gitHeadChanged(dir, home); ok {
+		return changed
+	}
+
+	var newest time.Time
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if entry.Name() == skip {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	return newest
+}
+
+// gitHeadChanged returns when the HEAD of the git repository holding dir
+// last moved, from its reflog (or HEAD itself without one). Repositories at
+// or above home are not projects.
+func gitHeadChanged(dir, home string) (time.Time, bool) {
+	for ; dir != home; dir = filepath.Dir(dir) {
+		gitDir := filepath.Join(dir, ".git")
+		if info, err := os.Stat(gitDir); err == nil {
+			if !info.IsDir() {
+				// Worktrees and submodules: .git holds "gitdir: <path>"
+				data, err := os.ReadFile(gitDir)
+				if err != nil {
+					return time.Time{}, false
+				}
+				gitDir = strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+				if !filepath.IsAbs(gitDir) {
+					gitDir = filepath.Join(dir, gitDir)
+				}
+			}
+			for _, path := range []string{filepath.Join(gitDir, "logs", "HEAD"), filepath.Join(gitDir, "HEAD")} {
+				if info, err := os.Stat(path); err == nil {
+					return info.ModTime(), true
+				}
+			}
+			return time.Time{}, false
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return time.Time{}, false
+}
+
+// guardActiveProjects escalates the dependency folders of projects changed
+// in the last week from Moderate to Dangerous, noting when, and drops them
+// if the clean level does not allow Dangerous targets: the next build of a
+// project being worked on would need a full reinstall. Projects untouched
+// for longer keep their safety.
+func guardActiveProjects(cfg *config.Config, targets []CleanTarget, now time.Time) []CleanTarget {
+	home, _ := cfg.HomeDir()
+	kept := make([]CleanTarget, 0, len(targets))
+	for _, target := range targets {
+		if !activityCategories[target.Category] || target.Safety != config.Moderate {
+			kept = append(kept, target)
+			continue
+		}
+
+		changed := lastActivity(filepath.Dir(target.Path), filepath.Base(target.Path), home)
+		if changed.IsZero() || now.Sub(changed) >= recentActivity {
+			kept = append(kept, target)
+			continue
+		}
+		if !cfg.CleanLevel.AllowsSafety(config.Dangerous) {
+			continue
+		}
+		target.Safety = config.Dangerous
+		target.Description += " (project active " + daysAgo(now.Sub(changed)) + ")"
+		kept = append(kept, target)
+	}
+	return kept
+}
+
+// daysAgo says how long ago age was, in days
+func daysAgo(age time.Duration) string {
+	switch days := int(age / day); days {
+	case 0:
+		return "today"
+	case 1:
+		return "yesterday"
+	default:
+		return strconv.Itoa(days) + " days ago"
+	}
+}
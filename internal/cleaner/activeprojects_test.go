@@ -0,0 +1,124 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestLastActivity_GitHead(t *testing.T) {
+	now := time.Now()
+	repo := t.TempDir()
+	project := filepath.Join(repo, "packages", "web")
+	os.MkdirAll(filepath.Join(repo, ".git", "logs"), 0755)
+	os.MkdirAll(project, 0755)
+	reflog := filepath.Join(repo, ".git", "logs", "HEAD")
+	os.WriteFile(reflog, []byte("checkout\n"), 0644)
+	os.Chtimes(reflog, now.Add(-3*day), now.Add(-3*day))
+
+	// The reflog wins over recently modified files
+	os.WriteFile(filepath.Join(project, "package.json"), []byte("{}"), 0644)
+	if got := lastActivity(project, "node_modules", ""); !got.Equal(now.Add(-3 * day)) {
+		t.Errorf("Expected the reflog time, got %v", got)
+	}
+}
+
+func TestLastActivity_Worktree(t *testing.T) {
+	now := time.Now()
+	gitDir := t.TempDir()
+	project := t.TempDir()
+	os.WriteFile(filepath.Join(project, ".git"), []byte("gitdir: "+gitDir+"\n"), 0644)
+	head := filepath.Join(gitDir, "HEAD")
+	os.WriteFile(head, []byte("ref: refs/heads/main\n"), 0644)
+	os.Chtimes(head, now.Add(-40*day), now.Add(-40*day))
+
+	if got := lastActivity(project, "node_modules", ""); !got.Equal(now.Add(-40 * day)) {
+		t.Errorf("Expected the worktree HEAD time, got %v", got)
+	}
+}
+
+func TestLastActivity_HomeRepo(t *testing.T) {
+	now := time.Now()
+	home := t.TempDir()
+	project := filepath.Join(home, "web")
+	os.MkdirAll(filepath.Join(home, ".git", "logs"), 0755)
+	os.WriteFile(filepath.Join(home, ".git", "logs", "HEAD"), []byte("commit\n"), 0644)
+	os.MkdirAll(project, 0755)
+	manifest := filepath.Join(project, "package.json")
+	os.WriteFile(manifest, []byte("{}"), 0644)
+	os.Chtimes(manifest, now.Add(-90*day), now.Add(-90*day))
+
+	// A dotfiles repository in the home directory is not the project's
+	if got := lastActivity(project, "node_modules", home); !got.Equal(now.Add(-90 * day)) {
+		t.Errorf("Expected the manifest time, got %v", got)
+	}
+}
+
+func TestLastActivity_Entries(t *testing.T) {
+	now := time.Now()
+	project := t.TempDir()
+	manifest := filepath.Join(project, "package.json")
+	modules := filepath.Join(project, "node_modules")
+	os.WriteFile(manifest, []byte("{}"), 0644)
+	os.Mkdir(modules, 0755)
+	os.Chtimes(manifest, now.Add(-200*day), now.Add(-200*day))
+
+	// node_modules itself was just installed and doesn't count
+	if got := lastActivity(project, "node_modules", ""); !got.Equal(now.Add(-200 * day)) {
+		t.Errorf("Expected the manifest time, got %v", got)
+	}
+}
+
+func TestGuardActiveProjects(t *testing.T) {
+	now := time.Now()
+	active, idle := t.TempDir(), t.TempDir()
+	for dir, age := range map[string]time.Duration{active: 2 * day, idle: 180 * day} {
+		manifest := filepath.Join(dir, "package.json")
+		os.WriteFile(manifest, []byte("{}"), 0644)
+		os.Chtimes(manifest, now.Add(-age), now.Add(-age))
+	}
+
+	targets := []CleanTarget{
+		{Path: filepath.Join(active, "node_modules"), Category: "node_modules", Description: "node_modules dependencies", Safety: config.Moderate},
+		{Path: filepath.Join(idle, "node_modules"), Category: "node_modules", Description: "node_modules dependencies", Safety: config.Moderate},
+		{Path: filepath.Join(active, "dist"), Category: "dist", Description: "Build output (dist)", Safety: config.Safe},
+	}
+
+	cfg := config.NewDefaultConfig()
+	kept := guardActiveProjects(cfg, targets, now)
+	if len(kept) != 2 || kept[0].Path != targets[1].Path || kept[0].Safety != config.Moderate {
+		t.Errorf("Expected the active project's node_modules to be left out below aggressive, got %+v", kept)
+	}
+
+	cfg.CleanLevel = config.Aggressive
+	kept = guardActiveProjects(cfg, targets, now)
+	if len(kept) != 3 || kept[0].Safety != config.Dangerous {
+		t.Fatalf("Expected the active project's node_modules to be Dangerous, got %+v", kept)
+	}
+	if kept[0].Description != "node_modules dependencies (project active 2 days ago)" {
+		t.Errorf("Unexpected description %q", kept[0].Description)
+	}
+	if kept[1].Safety != config.Moderate || kept[2].Safety != config.Safe {
+		t.Error("Expected other targets to keep their safety")
+	}
+}
+
+func TestDaysAgo(t *testing.T) {
+	tests := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{3 * time.Hour, "today"},
+		{30 * time.Hour, "yesterday"},
+		{5*day + time.Hour, "5 days ago"},
+	}
+
+	for _, tt := range tests {
+		if got := daysAgo(tt.age); got != tt.expected {
+			t.Errorf("daysAgo(%v) = %q, want %q", tt.age, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// AgeBucket is one range of a size-by-age histogram: the files last
+// modified between MinAge and MaxAge ago
+type AgeBucket struct {
+	Label  string
+	MinAge time.Duration
+	MaxAge time.Duration // 0 for the oldest bucket, which has no upper bound
+	Size   int64
+	Files  int
+}
+
+const day = 24 * time.Hour
+
+// newAgeBuckets returns the empty buckets of a histogram: less than a week,
+// up to a month, up to three months and older
+func newAgeBuckets() []AgeBucket {
+	return []AgeBucket{
+		{Label: "< 7d", MaxAge: 7 * day},
+		{Label: "7-30d", MinAge: 7 * day, MaxAge: 30 * day},
+		{Label: "30-90d", MinAge: 30 * day, MaxAge: 90 * day},
+		{Label: "> 90d", MinAge: 90 * day},
+	}
+}
+
+// TargetAge is the size-by-age histogram of a target's contents
+type TargetAge struct {
+	Target  CleanTarget
+	Buckets []AgeBucket
+}
+
+// Total returns the size of all buckets
+func (a TargetAge) Total() int64 {
+	var total int64
+	for _, bucket := range a.Buckets {
+		total += bucket.Size
+	}
+	return total
+}
+
+// OlderThan returns the size of the files at least age old, counting the
+// buckets that start at or after age
+func (a TargetAge) OlderThan(age time.Duration) int64 {
+	var size int64
+	for _, bucket := range a.Buckets {
+		if bucket.MinAge >= age {
+			size += bucket.Size
+		}
+	}
+	return size
+}
+
+// AgeHistogram buckets the files under path by modification time, relative
+// to now. Dataless (cloud-only) files are skipped, like when sizing targets.
+func AgeHistogram(path string, now time.Time) ([]AgeBucket, error) {
+	buckets := newAgeBuckets()
+
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		utils.Throttle()
+		if err != nil {
+			// Continue on permission errors
+			return nil
+		}
+		if utils.IsDataless(info) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		age := now.Sub(info.ModTime())
+		for i := range buckets {
+			if buckets[i].MaxAge == 0 || age < buckets[i].MaxAge {
+				buckets[i].Size += info.Size()
+				buckets[i].Files++
+				break
+			}
+		}
+		return nil
+	})
+
+	return buckets, err
+}
+
+// AnalyzeAges returns the size-by-age histograms of the targets that are
+// directories of at least minSize, largest first
+func AnalyzeAges(targets []CleanTarget, minSize int64, now time.Time) []TargetAge {
+	ages := []TargetAge{}
+
+	for _, target := range targets {
+		if target.SizeBytes < minSize {
+			continue
+		}
+		if info, err := os.Stat(target.Path); err != nil || !info.IsDir() {
+			continue
+		}
+		buckets, err := AgeHistogram(target.Path, now)
+		if err != nil {
+			continue
+		}
+		ages = append(ages, TargetAge{Target: target, Buckets: buckets})
+	}
+
+	sort.SliceStable(ages, func(i, j int) bool {
+		return ages[i].Target.SizeBytes > ages[j].Target.SizeBytes
+	})
+	return ages
+}
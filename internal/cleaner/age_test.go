@@ -0,0 +1,91 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAgeHistogram(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	now := time.Now()
+	files := []struct {
+		path string
+		size int
+		age  time.Duration
+	}{
+		{"recent/a", 100, 2 * day},
+		{"recent/b", 50, 6 * day},
+		{"month/c", 200, 20 * day},
+		{"quarter/d", 300, 60 * day},
+		{"old/e", 400, 200 * day},
+		{"old/f", 10, 91 * day},
+	}
+	for _, f := range files {
+		path := createTestFile(t, tmpDir, f.path, strings.Repeat("x", f.size))
+		mtime := now.Add(-f.age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buckets, err := AgeHistogram(tmpDir, now)
+	if err != nil {
+		t.Fatalf("AgeHistogram() error = %v", err)
+	}
+
+	expected := []struct {
+		size  int64
+		files int
+	}{{150, 2}, {200, 1}, {300, 1}, {410, 2}}
+	if len(buckets) != len(expected) {
+		t.Fatalf("Expected %d buckets, got %d", len(expected), len(buckets))
+	}
+	for i, want := range expected {
+		if buckets[i].Size != want.size || buckets[i].Files != want.files {
+			t.Errorf("Bucket %s = %d bytes in %d files, want %d in %d",
+				buckets[i].Label, buckets[i].Size, buckets[i].Files, want.size, want.files)
+		}
+	}
+
+	age := TargetAge{Buckets: buckets}
+	if age.Total() != 1060 {
+		t.Errorf("Total() = %d, want 1060", age.Total())
+	}
+	if got := age.OlderThan(30 * day); got != 710 {
+		t.Errorf("OlderThan(30d) = %d, want 710", got)
+	}
+}
+
+func TestAnalyzeAges(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "small/a", "x")
+	createTestFile(t, tmpDir, "large/a", "xxx")
+	createTestFile(t, tmpDir, "larger/a", "xxxx")
+	file := createTestFile(t, tmpDir, "file.log", "xxxxx")
+
+	targets := []CleanTarget{
+		{Path: filepath.Join(tmpDir, "small"), SizeBytes: 1},
+		{Path: filepath.Join(tmpDir, "large"), SizeBytes: 3},
+		{Path: file, SizeBytes: 5},
+		{Path: filepath.Join(tmpDir, "larger"), SizeBytes: 4},
+		{Path: "docker:buildcache", SizeBytes: 10},
+	}
+
+	ages := AnalyzeAges(targets, 2, time.Now())
+	if len(ages) != 2 {
+		t.Fatalf("Expected the 2 large directories, got %d", len(ages))
+	}
+	if filepath.Base(ages[0].Target.Path) != "larger" {
+		t.Errorf("Expected the largest directory first, got %s", ages[0].Target.Path)
+	}
+	if ages[1].Total() != 3 {
+		t.Errorf("Total() = %d, want 3", ages[1].Total())
+	}
+}
@@ -0,0 +1,271 @@
+package cleaner
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// androidSDKDir returns the Android SDK folder: ANDROID_HOME,
+// ANDROID_SDK_ROOT or where Android Studio installs it
+func androidSDKDir(home string) string {
+	for _, env := range []string{"ANDROID_HOME", "ANDROID_SDK_ROOT"} {
+		if dir := os.Getenv(env); dir != "" {
+			return dir
+		}
+	}
+	return filepath.Join(home, "Library", "Android", "sdk")
+}
+
+// androidReferences are the SDK components local projects ask for
+type androidReferences struct {
+	projects int          // Gradle build files read
+	apis     map[int]bool // API levels of compileSdk, targetSdk and minSdk
+	ndks     map[string]bool
+	// A build file names an API level or NDK through something that can't
+	// be read here (flutter.ndkVersion, an ext property, AGP's default NDK
+	// for native builds): every platform or NDK is kept
+	apisUnknown bool
+	ndksUnknown bool
+}
+
+var (
+	gradleSdkRe    = regexp.MustCompile(`\b(?:compileSdk|compileSdkVersion|targetSdk|targetSdkVersion|minSdk|minSdkVersion)\b\s*[=(]?\s*([^\s)]+)`)
+	gradleNdkRe    = regexp.MustCompile(`\bndkVersion\b\s*[=(]?\s*([^\s)]+)`)
+	sdkLiteralRe   = regexp.MustCompile(`^["']?(?:android-)?(\d+)`)
+	ndkLiteralRe   = regexp.MustCompile(`^["']([\d.]+)["']`)
+	catalogEntryRe = regexp.MustCompile(`^\s*([\w.-]+)\s*=\s*"([^"]+)"`)
+)
+
+// parseGradleReferences adds the API levels and NDK versions a Gradle build
+// file names to refs, looking up those taken from the version catalog in
+// catalog (see readVersionCatalog)
+func parseGradleReferences(data string, catalog map[string]string, refs *androidReferences) {
+	for _, match := range gradleSdkRe.FindAllStringSubmatch(data, -1) {
+		value := catalogValue(match[1], catalog)
+		if literal := sdkLiteralRe.FindStringSubmatch(value); literal != nil {
+			level, _ := strconv.Atoi(literal[1])
+			refs.apis[level] = true
+		} else {
+			refs.apisUnknown = true
+		}
+	}
+
+	ndks := gradleNdkRe.FindAllStringSubmatch(data, -1)
+	for _, match := range ndks {
+		value := catalogValue(match[1], catalog)
+		if literal := ndkLiteralRe.FindStringSubmatch(value); literal != nil {
+			refs.ndks[literal[1]] = true
+		} else {
+			refs.ndksUnknown = true
+		}
+	}
+
+	// Native builds without ndkVersion get the default NDK of their AGP
+	// release, Flutter apps the one of their Flutter SDK
+	if len(ndks) == 0 && strings.Contains(data, "externalNativeBuild") {
+		refs.ndksUnknown = true
+	}
+	if strings.Contains(data, "flutter.gradle") || strings.Contains(data, "dev.flutter.flutter-gradle-plugin") {
+		refs.apisUnknown, refs.ndksUnknown = true, true
+	}
+}
+
+// catalogValue returns the version catalog entry a libs.versions.<name>
+// reference points to, quoted as a literal would be, or value itself
+func catalogValue(value string, catalog map[string]string) string {
+	name, ok := strings.CutPrefix(value, "libs.versions.")
+	if !ok {
+		return value
+	}
+	name, _, _ = strings.Cut(name, ".get(")
+	if version, ok := catalog[catalogKey(name)]; ok {
+		return `"` + version + `"`
+	}
+	return value
+}
+
+// catalogKey normalizes a version catalog name: "android-compileSdk" in
+// the file is libs.versions.android.compileSdk in build files
+func catalogKey(name string) string {
+	return strings.ToLower(strings.NewReplacer("-", ".", "_", ".").Replace(name))
+}
+
+// readVersionCatalog reads the [versions] of the Gradle version catalog
+// (gradle/libs.versions.toml) of the project a build file in dir belongs
+// to, looking in dir and its parent for module build files
+func readVersionCatalog(dir string) map[string]string {
+	catalog := make(map[string]string)
+	for _, root := range []string{dir, filepath.Dir(dir)} {
+		file, err := os.Open(filepath.Join(root, "gradle", "libs.versions.toml"))
+		if err != nil {
+			continue
+		}
+		section := ""
+		lines := bufio.NewScanner(file)
+		for lines.Scan() {
+			line := strings.TrimSpace(lines.Text())
+			if strings.HasPrefix(line, "[") {
+				section = line
+				continue
+			}
+			if match := catalogEntryRe.FindStringSubmatch(line); match != nil && section == "[versions]" {
+				catalog[catalogKey(match[1])] = match[2]
+			}
+		}
+		file.Close()
+		break
+	}
+	return catalog
+}
+
+// scanAndroidReferences reads the Gradle build files of the projects in the
+// search directories
+func scanAndroidReferences(ctx context.Context, s *scanner.Scanner) androidReferences {
+	refs := androidReferences{apis: map[int]bool{}, ndks: map[string]bool{}}
+	for result := range s.FindByPattern(ctx, "build.gradle*") {
+		if result.Err != nil {
+			continue
+		}
+		data, err := os.ReadFile(result.Path)
+		if err != nil {
+			continue
+		}
+		refs.projects++
+		parseGradleReferences(string(data), readVersionCatalog(filepath.Dir(result.Path)), &refs)
+	}
+	return refs
+}
+
+// avdSystemImages returns the API levels of the system images the virtual
+// devices in avdDir run
+func avdSystemImages(avdDir string) map[int]bool {
+	levels := make(map[int]bool)
+	configs, _ := filepath.Glob(filepath.Join(avdDir, "*.avd", "config.ini"))
+	for _, path := range configs {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		lines := bufio.NewScanner(file)
+		for lines.Scan() {
+			key, value, ok := strings.Cut(lines.Text(), "=")
+			if ok && strings.HasPrefix(strings.TrimSpace(key), "image.sysdir") {
+				for _, part := range strings.Split(filepath.ToSlash(strings.TrimSpace(value)), "/") {
+					if level := androidAPILevel(part); level > 0 {
+						levels[level] = true
+					}
+				}
+			}
+		}
+		file.Close()
+	}
+	return levels
+}
+
+// androidAPILevel returns the API level of an android-<level> folder name,
+// or 0 if it has none (e.g. preview platforms named after a codename)
+func androidAPILevel(name string) int {
+	rest, ok := strings.CutPrefix(name, "android-")
+	if !ok {
+		return 0
+	}
+	end := 0
+	for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+		end++
+	}
+	level, _ := strconv.Atoi(rest[:end])
+	return level
+}
+
+// androidSDKTargets returns the SDK components no local project uses, each
+// with its size: platforms and system images of API levels no Gradle build
+// file names (Moderate), NDK versions no build file pins (Moderate), and the
+// emulator copies left by interrupted updates (Safe). The newest platform,
+// system images and NDK are kept, as are the images of virtual devices.
+// Nothing but the emulator copies is offered when no build file was found,
+// and no platform or NDK when a build file names one that can't be read.
+func androidSDKTargets(sdk string, refs androidReferences, avdLevels map[int]bool) []CleanTarget {
+	targets := []CleanTarget{}
+
+	add := func(path, category, description string, safety config.SafetyLevel) {
+		if size, _ := utils.GetDirSize(path); size > 0 {
+			targets = append(targets, CleanTarget{
+				Path:        path,
+				Category:    category,
+				Description: description,
+				SizeBytes:   size,
+				Safety:      safety,
+			})
+		}
+	}
+
+	if refs.projects > 0 && !refs.apisUnknown {
+		for _, component := range []struct{ dir, category, name string }{
+			{"platforms", "android_platform", "Android SDK Platform"},
+			{"system-images", "android_system_image", "Android system images"},
+		} {
+			levels := make(map[int]string)
+			newest := 0
+			entries, _ := os.ReadDir(filepath.Join(sdk, component.dir))
+			for _, entry := range entries {
+				if level := androidAPILevel(entry.Name()); level > 0 && entry.IsDir() {
+					levels[level] = filepath.Join(sdk, component.dir, entry.Name())
+					newest = max(newest, level)
+				}
+			}
+
+			sorted := make([]int, 0, len(levels))
+			for level := range levels {
+				sorted = append(sorted, level)
+			}
+			sort.Ints(sorted)
+			for _, level := range sorted {
+				if level == newest || refs.apis[level] || (component.dir == "system-images" && avdLevels[level]) {
+					continue
+				}
+				add(levels[level], component.category,
+					component.name+" "+strconv.Itoa(level)+" (no project uses API "+strconv.Itoa(level)+")", config.Moderate)
+			}
+		}
+	}
+
+	if refs.projects > 0 && !refs.ndksUnknown {
+		versions := []string{}
+		entries, _ := os.ReadDir(filepath.Join(sdk, "ndk"))
+		for _, entry := range entries {
+			if entry.IsDir() {
+				versions = append(versions, entry.Name())
+			}
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return compareVersions(versions[i], versions[j]) < 0
+		})
+		// The newest is kept, as is the newest platform
+		for i, version := range versions {
+			if i == len(versions)-1 || refs.ndks[version] {
+				continue
+			}
+			add(filepath.Join(sdk, "ndk", version), "android_ndk",
+				"Android NDK "+version+" (not pinned by any project)", config.Moderate)
+		}
+	}
+
+	// sdkmanager installs updates next to the emulator in use, in
+	// emulator-2 and so on, and leaves them when interrupted
+	leftovers, _ := filepath.Glob(filepath.Join(sdk, "emulator-*"))
+	for _, path := range leftovers {
+		add(path, "android_emulator_leftover", "Android emulator left by an update ("+filepath.Base(path)+")", config.Safe)
+	}
+
+	return targets
+}
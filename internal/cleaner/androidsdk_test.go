@@ -0,0 +1,137 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+func TestParseGradleReferences(t *testing.T) {
+	refs := androidReferences{apis: map[int]bool{}, ndks: map[string]bool{}}
+	parseGradleReferences(`android {
+    compileSdkVersion 33
+    ndkVersion "25.1.8937393"
+    defaultConfig {
+        minSdkVersion 24
+        targetSdkVersion flutter.targetSdkVersion
+    }
+}`, nil, &refs)
+	parseGradleReferences(`android {
+    compileSdk = 34
+    ndkVersion = "26.1.10909125"
+    defaultConfig { minSdk = 26 }
+}`, nil, &refs)
+
+	for _, level := range []int{24, 26, 33, 34} {
+		if !refs.apis[level] {
+			t.Errorf("Expected API %d, got %v", level, refs.apis)
+		}
+	}
+	if len(refs.apis) != 4 {
+		t.Errorf("Unexpected API levels %v", refs.apis)
+	}
+	if !refs.ndks["25.1.8937393"] || !refs.ndks["26.1.10909125"] {
+		t.Errorf("Expected both NDK versions, got %v", refs.ndks)
+	}
+	// flutter.targetSdkVersion can't be read here
+	if !refs.apisUnknown || refs.ndksUnknown {
+		t.Errorf("Expected only the API levels unknown, got %+v", refs)
+	}
+}
+
+func TestParseGradleReferences_Catalog(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "gradle/libs.versions.toml", `[versions]
+agp = "8.5.0"
+android-compileSdk = "34"
+ndk = "26.3.11579264"
+
+[libraries]
+core = { module = "androidx.core:core-ktx", version = "1.13.1" }
+`)
+
+	// A module build file reads the catalog of its project
+	refs := androidReferences{apis: map[int]bool{}, ndks: map[string]bool{}}
+	parseGradleReferences(`android {
+    compileSdk = libs.versions.android.compileSdk.get().toInt()
+    ndkVersion = libs.versions.ndk.get()
+}`, readVersionCatalog(filepath.Join(tmpDir, "app")), &refs)
+	if !refs.apis[34] || !refs.ndks["26.3.11579264"] || refs.apisUnknown || refs.ndksUnknown {
+		t.Errorf("Expected the catalog versions, got %+v", refs)
+	}
+
+	// Names missing from the catalog, Flutter's and native builds left to
+	// AGP's default NDK are unknown
+	for _, data := range []string{
+		"android {\n    ndkVersion = libs.versions.missing.get()\n}",
+		"android {\n    ndkVersion = flutter.ndkVersion\n}",
+		"android {\n    externalNativeBuild { cmake { path \"CMakeLists.txt\" } }\n}",
+	} {
+		refs := androidReferences{apis: map[int]bool{}, ndks: map[string]bool{}}
+		parseGradleReferences(data, nil, &refs)
+		if !refs.ndksUnknown {
+			t.Errorf("Expected the NDK unknown in %q", data)
+		}
+	}
+}
+
+func TestAndroidSDKTargets(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	sdk := filepath.Join(tmpDir, "sdk")
+	for _, component := range []string{
+		"platforms/android-30", "platforms/android-33", "platforms/android-34", "platforms/android-35",
+		"system-images/android-29/google_apis/arm64-v8a", "system-images/android-31/google_apis/arm64-v8a",
+		"system-images/android-33/google_apis/arm64-v8a", "system-images/android-35/google_apis/arm64-v8a",
+		"ndk/25.1.8937393", "ndk/26.1.10909125", "ndk/27.0.12077973", "emulator", "emulator-2",
+	} {
+		createTestFile(t, sdk, component+"/data.bin", "component")
+	}
+	avd := filepath.Join(tmpDir, "avd")
+	createTestFile(t, avd, "Pixel_7.avd/config.ini", "hw.ramSize=2048\nimage.sysdir.1=system-images/android-31/google_apis/arm64-v8a/\n")
+
+	projects := filepath.Join(tmpDir, "Projects")
+	createTestFile(t, projects, "app/app/build.gradle.kts", "android {\n    compileSdk = 33\n    ndkVersion = \"26.1.10909125\"\n}\n")
+
+	s, _ := scanner.NewScannerWithDirs([]string{projects})
+	refs := scanAndroidReferences(context.Background(), s)
+	targets := androidSDKTargets(sdk, refs, avdSystemImages(avd))
+
+	// API 33 and NDK 26 are used, API 35 and NDK 27 are the newest, the API
+	// 31 image runs a device
+	expected := map[string]config.SafetyLevel{
+		filepath.Join(sdk, "platforms", "android-30"):     config.Moderate,
+		filepath.Join(sdk, "platforms", "android-34"):     config.Moderate,
+		filepath.Join(sdk, "system-images", "android-29"): config.Moderate,
+		filepath.Join(sdk, "ndk", "25.1.8937393"):         config.Moderate,
+		filepath.Join(sdk, "emulator-2"):                  config.Safe,
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %d targets, got %d: %+v", len(expected), len(targets), targets)
+	}
+	for _, target := range targets {
+		safety, ok := expected[target.Path]
+		if !ok || safety != target.Safety || target.SizeBytes != int64(len("component")) {
+			t.Errorf("Unexpected target %+v", target)
+		}
+	}
+
+	// Without any project found, only the emulator copy is offered
+	empty := androidReferences{apis: map[int]bool{}, ndks: map[string]bool{}}
+	if targets := androidSDKTargets(sdk, empty, nil); len(targets) != 1 {
+		t.Errorf("Expected the emulator copy only, got %+v", targets)
+	}
+
+	// Nor when a project names versions that can't be read
+	refs.apisUnknown, refs.ndksUnknown = true, true
+	if targets := androidSDKTargets(sdk, refs, nil); len(targets) != 1 {
+		t.Errorf("Expected the emulator copy only, got %+v", targets)
+	}
+}
@@ -4,15 +4,20 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/platform"
 	"github.com/0SansNom/epurer/internal/scanner"
 	"github.com/0SansNom/epurer/pkg/utils"
 )
 
-// BackendCleaner handles backend development cleanup (Python, Java, Go, Rust, PHP, Ruby)
+// BackendCleaner handles backend development cleanup (Python, Java, Go, Rust,
+// PHP, Ruby, and Haskell, Elixir, Erlang and Zig where installed)
 type BackendCleaner struct {
 	scanner *scanner.Scanner
+	runner  CommandRunner // Lists the running JVM builds and runs gradle --stop before Gradle and Maven caches are deleted, ExecRunner if nil
 }
 
 // NewBackendCleaner creates a new BackendCleaner
@@ -27,92 +32,130 @@ func NewBackendCleaner() (Cleaner, error) {
 	}, nil
 }
 
+// SetRunner replaces the runner of the cleaner's external commands
+func (b *BackendCleaner) SetRunner(runner CommandRunner) {
+	b.runner = runner
+}
+
+// commands returns the runner of the cleaner's external commands
+func (b *BackendCleaner) commands() CommandRunner {
+	if b.runner == nil {
+		return ExecRunner{}
+	}
+	return b.runner
+}
+
 func (b *BackendCleaner) Name() string {
 	return "Backend"
 }
 
 func (b *BackendCleaner) Domain() config.Domain {
-	return config.DomainFrontend // TODO: Add DomainBackend to config
+	return config.DomainBackend
 }
 
 func (b *BackendCleaner) Detect(ctx context.Context) (bool, error) {
 	// Check for common backend tools
-	return utils.CommandExists("python3") ||
-		utils.CommandExists("python") ||
-		utils.CommandExists("java") ||
-		utils.CommandExists("go") ||
-		utils.CommandExists("cargo") ||
-		utils.CommandExists("php") ||
-		utils.CommandExists("ruby"), nil
+	return detector.FromContext(ctx).HasCommand("python3", "python", "java", "go", "cargo", "php", "ruby", "stack", "cabal", "mix", "rebar3", "zig"), nil
 }
 
 func (b *BackendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	configureScanner(b.scanner, cfg)
+
 	targets := []CleanTarget{}
-	home, err := os.UserHomeDir()
+	home, err := cfg.HomeDir()
 	if err != nil {
 		return nil, err
 	}
 
 	// === Python ===
 
-	// __pycache__ (Safe - automatically rebuilt)
-	pycacheTargets := b.scanPattern(ctx, "__pycache__")
-	targets = append(targets, pycacheTargets...)
+	// __pycache__, .pyc files, pytest/mypy caches, tox environments (Safe)
+	// and the other project folders of the pattern registry: Java/Scala
+	// target folders (Safe) and PHP vendor folders (Moderate)
+	projectTargets := scanProjectPatterns(ctx, b.scanner, cfg, config.DomainBackend)
+	targets = append(targets, projectTargets...)
 
-	// .pyc files (Safe)
-	pycTargets := b.scanPattern(ctx, "*.pyc")
-	targets = append(targets, pycTargets...)
+	// With --older-than / --larger-than, only the matching pip and Gradle
+	// cache entries are removed instead of the whole caches
+	criteria := pruneCriteria(cfg)
 
-	// .pytest_cache (Safe)
-	pytestTargets := b.scanPattern(ctx, ".pytest_cache")
-	targets = append(targets, pytestTargets...)
-
-	// .mypy_cache (Safe)
-	mypyTargets := b.scanPattern(ctx, ".mypy_cache")
-	targets = append(targets, mypyTargets...)
-
-	// .tox (Safe - test environments)
-	toxTargets := b.scanPattern(ctx, ".tox")
-	targets = append(targets, toxTargets...)
-
-	// pip cache (Safe)
-	pipCachePath := filepath.Join(home, "Library", "Caches", "pip")
+	// pip cache (Safe), pruned file by file: its HTTP and wheel caches hold
+	// one file per download
+	pipCachePath := pipCacheDir(platform.Current(), home)
 	if utils.PathExists(pipCachePath) {
-		size, _ := utils.GetDirSize(pipCachePath)
-		if size > 0 {
-			targets = append(targets, CleanTarget{
-				Path:        pipCachePath,
-				Description: "pip cache",
-				SizeBytes:   size,
-				Safety:      config.Safe,
-			})
+		if target, ok := cacheTarget(pipCachePath, 0, criteria, "pip_cache", "pip cache", config.Safe); ok {
+			targets = append(targets, target)
 		}
 	}
 
-	// Poetry cache (Safe)
-	poetryCachePath := filepath.Join(home, "Library", "Caches", "pypoetry")
-	if utils.PathExists(poetryCachePath) {
-		size, _ := utils.GetDirSize(poetryCachePath)
-		if size > 0 {
-			targets = append(targets, CleanTarget{
-				Path:        poetryCachePath,
-				Description: "Poetry cache",
-				SizeBytes:   size,
-				Safety:      config.Safe,
-			})
+	// Poetry cache (Safe), without the virtualenvs it keeps
+	poetryCachePath := poetryCacheDir(platform.Current(), home)
+	poetryVenvsPath := filepath.Join(poetryCachePath, "virtualenvs")
+	if children, err := os.ReadDir(poetryCachePath); err == nil {
+		entries := []string{}
+		for _, child := range children {
+			if child.Name() != "virtualenvs" {
+				entries = append(entries, filepath.Join(poetryCachePath, child.Name()))
+			}
+		}
+		if target, ok := entriesTarget(poetryCachePath, entries, "poetry_cache", "Poetry cache", config.Safe); ok {
+			targets = append(targets, target)
+		}
+	}
+
+	// Poetry virtualenvs, named after their project (Safe if it was
+	// deleted, Moderate otherwise)
+	if utils.PathExists(poetryVenvsPath) {
+		for _, target := range poetryVenvs(poetryVenvsPath, poetryProjects(ctx, b.scanner)) {
+			if cfg.Allows(config.DomainBackend, target.Category, target.Safety) {
+				targets = append(targets, target)
+			}
 		}
 	}
 
+	// uv cache (Safe)
+	uvCachePath := uvCache(home)
+	if size, _ := utils.GetDirSize(uvCachePath); size > 0 {
+		targets = append(targets, CleanTarget{
+			Path:        uvCachePath,
+			Category:    "uv_cache",
+			Description: "uv cache",
+			SizeBytes:   size,
+			Safety:      config.Safe,
+		})
+	}
+
+	// Apps installed with pipx (Moderate - their commands need a reinstall)
+	if cfg.Allows(config.DomainBackend, "pipx_app", config.Moderate) {
+		venvs, bin := pipxDirs(home)
+		targets = append(targets, pipxApps(venvs, bin, time.Now())...)
+	}
+
 	// === Java / Maven / Gradle ===
 
 	// Maven local repository (Moderate - can be large)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
+	if cfg.Allows(config.DomainBackend, "maven_repository", config.Moderate) {
 		mavenRepoPath := filepath.Join(home, ".m2", "repository")
-		if utils.PathExists(mavenRepoPath) {
+		if cfg.MavenMaxAgeDays > 0 && utils.PathExists(mavenRepoPath) {
+			// Only prune artifact versions that are no longer used
+			cutoff := time.Now().AddDate(0, 0, -cfg.MavenMaxAgeDays)
+			prune := pruneMavenRepository(mavenRepoPath, cutoff)
+			if prune.size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        mavenRepoPath,
+					Category:    "maven_repository",
+					Description: prune.describe(cfg.MavenMaxAgeDays),
+					SizeBytes:   prune.size,
+					Safety:      config.Moderate,
+					Entries:     prune.entries,
+				})
+			}
+		} else if utils.PathExists(mavenRepoPath) {
 			size, _ := utils.GetDirSize(mavenRepoPath)
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        mavenRepoPath,
+					Category:    "maven_repository",
 					Description: "Maven local repository",
 					SizeBytes:   size,
 					Safety:      config.Moderate,
@@ -121,33 +164,25 @@ func (b *BackendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanT
 		}
 	}
 
-	// Gradle cache (Safe)
-	gradleCachePath := filepath.Join(home, ".gradle", "caches")
-	if utils.PathExists(gradleCachePath) {
-		size, _ := utils.GetDirSize(gradleCachePath)
-		if size > 0 {
-			targets = append(targets, CleanTarget{
-				Path:        gradleCachePath,
-				Description: "Gradle cache",
-				SizeBytes:   size,
-				Safety:      config.Safe,
-			})
-		}
+	// Gradle cache (Safe), but its local build cache
+	if target, ok := gradleCacheTarget(filepath.Join(home, ".gradle", "caches"), criteria); ok {
+		targets = append(targets, target)
 	}
 
-	// target folders (Java/Scala build output - Safe)
-	targetTargets := b.scanPattern(ctx, "target")
-	targets = append(targets, targetTargets...)
+	// Gradle daemon logs, unused wrapper distributions, stale build cache
+	gradleExtraTargets := scanGradleExtras(ctx, b.scanner, home)
+	targets = append(targets, gradleExtraTargets...)
 
 	// === Go ===
 
 	// Go build cache (Safe)
-	goCachePath := filepath.Join(home, "Library", "Caches", "go-build")
+	goCachePath := goBuildCacheDir(platform.Current(), home)
 	if utils.PathExists(goCachePath) {
 		size, _ := utils.GetDirSize(goCachePath)
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        goCachePath,
+				Category:    "go_build_cache",
 				Description: "Go build cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -156,13 +191,14 @@ func (b *BackendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanT
 	}
 
 	// Go module cache (Moderate - can be large)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
+	if cfg.Allows(config.DomainBackend, "go_mod_cache", config.Moderate) {
 		goModCachePath := filepath.Join(home, "go", "pkg", "mod")
 		if utils.PathExists(goModCachePath) {
 			size, _ := utils.GetDirSize(goModCachePath)
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        goModCachePath,
+					Category:    "go_mod_cache",
 					Description: "Go module cache",
 					SizeBytes:   size,
 					Safety:      config.Moderate,
@@ -180,6 +216,7 @@ func (b *BackendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanT
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        cargoCachePath,
+				Category:    "cargo_registry",
 				Description: "Cargo registry cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -187,8 +224,13 @@ func (b *BackendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanT
 		}
 	}
 
-	// Rust target folders (Moderate - build artifacts)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
+	// Rust target folders
+	if cfg.CargoSweepDays > 0 {
+		// cargo-sweep mode: only stale artifacts (Safe - recent builds are kept)
+		rustSweepTargets := b.scanRustSweep(ctx, home, cfg.CargoSweepDays)
+		targets = append(targets, rustSweepTargets...)
+	} else if cfg.Allows(config.DomainBackend, "rust_target", config.Moderate) {
+		// Whole target folders (Moderate - build artifacts)
 		rustTargetTargets := b.scanRustTargets(ctx)
 		targets = append(targets, rustTargetTargets...)
 	}
@@ -202,6 +244,7 @@ func (b *BackendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanT
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        composerCachePath,
+				Category:    "composer_cache",
 				Description: "Composer cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -209,11 +252,9 @@ func (b *BackendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanT
 		}
 	}
 
-	// vendor folders (Moderate - PHP dependencies)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
-		vendorTargets := b.scanPHPVendor(ctx)
-		targets = append(targets, vendorTargets...)
-	}
+	// Laravel, Symfony and PHPUnit caches in Composer projects (Safe)
+	phpCacheTargets := scanPHPProjectCaches(ctx, b.scanner)
+	targets = append(targets, phpCacheTargets...)
 
 	// === Ruby ===
 
@@ -226,6 +267,7 @@ func (b *BackendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanT
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        cachePath,
+					Category:    "gem_cache",
 					Description: "Ruby gem cache",
 					SizeBytes:   size,
 					Safety:      config.Safe,
@@ -241,6 +283,7 @@ func (b *BackendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanT
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        bundlerCachePath,
+				Category:    "bundler_cache",
 				Description: "Bundler cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -248,63 +291,42 @@ func (b *BackendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanT
 		}
 	}
 
-	return targets, nil
-}
+	// === Haskell, Elixir, Erlang, Zig ===
 
-func (b *BackendCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
-	results := make([]CleanResult, 0, len(targets))
+	// Global caches and project build folders of the toolchains installed
+	targets = append(targets, scanToolchains(ctx, b.scanner, cfg, home)...)
 
-	for _, target := range targets {
-		result := CleanResult{
-			Target:  target,
-			Success: true,
-		}
+	// === Language servers ===
 
-		if !dryRun {
-			err := utils.SafeRemove(target.Path, false)
-			if err != nil {
-				result.Success = false
-				result.Error = err
-			} else {
-				result.BytesFreed = target.SizeBytes
-			}
-		} else {
-			result.BytesFreed = target.SizeBytes
+	// gopls, rust-analyzer and clangd caches (Safe - rebuilt when a project
+	// is opened again). Metals and Bloop folders in Scala projects come from
+	// the pattern registry above.
+	for _, cache := range languageServerCaches(home) {
+		if !cfg.Allows(config.DomainBackend, cache.category, config.Safe) {
+			continue
 		}
-
-		results = append(results, result)
-
-		select {
-		case <-ctx.Done():
-			return results, ctx.Err()
-		default:
+		size, _ := utils.GetDirSize(cache.path)
+		if size > 0 {
+			targets = append(targets, CleanTarget{
+				Path:        cache.path,
+				Category:    cache.category,
+				Description: cache.description,
+				SizeBytes:   size,
+				Safety:      config.Safe,
+			})
 		}
 	}
 
-	return results, nil
+	return targets, nil
 }
 
-// scanPattern scans for a specific pattern
-func (b *BackendCleaner) scanPattern(ctx context.Context, pattern string) []CleanTarget {
-	targets := []CleanTarget{}
-
-	resultChan := b.scanner.FindByPattern(ctx, pattern)
-	for result := range resultChan {
-		if result.Err != nil {
-			continue
-		}
-
-		desc := b.getDescriptionForPattern(pattern)
-
-		targets = append(targets, CleanTarget{
-			Path:        result.Path,
-			Description: desc,
-			SizeBytes:   result.Size,
-			Safety:      config.Safe,
-		})
-	}
+func (b *BackendCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanJVMTargets(ctx, b.commands(), targets, dryRun)
+}
 
-	return targets
+func (b *BackendCleaner) Patterns() []string {
+	patterns := append(projectPatternNames(config.DomainBackend), "composer.json", "gradle-wrapper.properties", "pyproject.toml")
+	return append(patterns, toolchainPatterns()...)
 }
 
 // scanRustTargets scans for Rust target folders (build output)
@@ -318,13 +340,15 @@ func (b *BackendCleaner) scanRustTargets(ctx context.Context) []CleanTarget {
 		}
 
 		// Check if this is a Rust project by looking for Cargo.toml
-		parent := filepath.Dir(result.Path)
-		cargoTomlPath := filepath.Join(parent, "Cargo.toml")
-		if utils.PathExists(cargoTomlPath) {
+		// (Tauri apps are handled by the Electron/Tauri cleaner)
+		if isCargoTarget(result.Path) && !isTauriTarget(result.Path) {
 			targets = append(targets, CleanTarget{
 				Path:        result.Path,
+				Category:    "rust_target",
 				Description: "Rust build output (target)",
 				SizeBytes:   result.Size,
+				Files:       result.Files,
+				Dirs:        result.Dirs,
 				Safety:      config.Moderate,
 			})
 		}
@@ -333,46 +357,51 @@ func (b *BackendCleaner) scanRustTargets(ctx context.Context) []CleanTarget {
 	return targets
 }
 
-// scanPHPVendor scans for PHP vendor folders
-func (b *BackendCleaner) scanPHPVendor(ctx context.Context) []CleanTarget {
+// scanRustSweep scans Rust target folders for stale artifacts only, leaving
+// recent builds in place (cargo-sweep semantics)
+func (b *BackendCleaner) scanRustSweep(ctx context.Context, home string, days int) []CleanTarget {
 	targets := []CleanTarget{}
+	cutoff := time.Now().AddDate(0, 0, -days)
 
-	resultChan := b.scanner.FindByPattern(ctx, "vendor")
+	resultChan := b.scanner.FindByPattern(ctx, "target")
 	for result := range resultChan {
-		if result.Err != nil {
+		if result.Err != nil || !isCargoTarget(result.Path) || isTauriTarget(result.Path) {
 			continue
 		}
 
-		// Check if this is a PHP project by looking for composer.json
-		parent := filepath.Dir(result.Path)
-		composerJsonPath := filepath.Join(parent, "composer.json")
-		if utils.PathExists(composerJsonPath) {
-			targets = append(targets, CleanTarget{
-				Path:        result.Path,
-				Description: "PHP vendor dependencies",
-				SizeBytes:   result.Size,
-				Safety:      config.Moderate,
-			})
+		sweep := sweepCargoTarget(result.Path, cutoff, pinsInstalledToolchain(filepath.Dir(result.Path), home))
+		if len(sweep.entries) == 0 {
+			continue
 		}
+
+		targets = append(targets, CleanTarget{
+			Path:        result.Path,
+			Category:    "rust_target",
+			Description: sweep.describe(days),
+			SizeBytes:   sweep.size,
+			Safety:      config.Safe,
+			Entries:     sweep.entries,
+		})
 	}
 
 	return targets
 }
 
-// getDescriptionForPattern returns a human-readable description
-func (b *BackendCleaner) getDescriptionForPattern(pattern string) string {
-	descriptions := map[string]string{
-		"__pycache__":    "Python bytecode cache",
-		"*.pyc":          "Python compiled files",
-		".pytest_cache":  "pytest cache",
-		".mypy_cache":    "mypy type checker cache",
-		".tox":           "tox test environments",
-		"target":         "Build output (target)",
-	}
+// toolCache is a global cache directory of a developer tool
+type toolCache struct {
+	path        string
+	category    string
+	description string
+}
 
-	if desc, ok := descriptions[pattern]; ok {
-		return desc
-	}
+// languageServerCaches returns the index caches of the gopls, rust-analyzer
+// and clangd language servers
+func languageServerCaches(home string) []toolCache {
+	caches := filepath.Join(home, "Library", "Caches")
 
-	return pattern
+	return []toolCache{
+		{path: filepath.Join(caches, "gopls"), category: "gopls_cache", description: "gopls cache"},
+		{path: filepath.Join(caches, "rust-analyzer"), category: "rust_analyzer_cache", description: "rust-analyzer cache"},
+		{path: filepath.Join(caches, "clangd"), category: "clangd_cache", description: "clangd index cache"},
+	}
 }
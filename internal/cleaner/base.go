@@ -2,24 +2,89 @@ package cleaner
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+	"unicode"
 
 	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/quarantine"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
 )
 
+// Action is what cleaning a target does to it
+type Action int
+
+const (
+	ActionDelete  Action = iota // Remove the target from disk
+	ActionEvict                 // Evict cloud-synced files from the local disk, keeping them in the cloud
+	ActionArchive               // Move the target into an archive folder
+	ActionRun                   // Run the commands of a maintenance action (reset or rebuild)
+)
+
+// String returns the name used for the action in plan files
+func (a Action) String() string {
+	switch a {
+	case ActionDelete:
+		return "delete"
+	case ActionEvict:
+		return "evict"
+	case ActionArchive:
+		return "archive"
+	case ActionRun:
+		return "run"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseAction converts an action name to an Action
+func ParseAction(s string) (Action, error) {
+	switch s {
+	case "", "delete":
+		return ActionDelete, nil
+	case "evict":
+		return ActionEvict, nil
+	case "archive":
+		return ActionArchive, nil
+	case "run":
+		return ActionRun, nil
+	default:
+		return ActionDelete, fmt.Errorf("invalid action: %s (must be delete, evict, archive or run)", s)
+	}
+}
+
 // CleanTarget represents a single item that can be cleaned
 type CleanTarget struct {
-	Path        string              // Absolute path to the item
-	Description string              // Human-readable description
-	SizeBytes   int64               // Size in bytes
-	Safety      config.SafetyLevel  // Safety level of this operation
+	Path        string             // Absolute path to the item
+	Category    string             // Kind of item in snake_case (e.g. "node_modules"), used by config overrides
+	Description string             // Human-readable description
+	SizeBytes   int64              // Size in bytes
+	Files       int                // Files inside, if counted (0 when unknown)
+	Dirs        int                // Folders inside, if counted
+	Safety      config.SafetyLevel // Safety level of this operation
+	Entries     []string           // If set, only these paths inside Path are removed
+	Action      Action             // What cleaning does (delete unless set)
+	ArchiveDir  string             // Folder ActionArchive moves the target into
 }
 
 // CleanResult represents the outcome of a clean operation
 type CleanResult struct {
-	Target     CleanTarget // The target that was cleaned
-	Success    bool        // Whether the operation succeeded
-	BytesFreed int64       // Actual bytes freed (may differ from target size)
-	Error      error       // Error if operation failed
+	Target           CleanTarget // The target that was cleaned
+	Success          bool        // Whether the operation succeeded
+	BytesFreed       int64       // Bytes freed as measured on disk (the estimate in dry runs)
+	BytesQuarantined int64       // Bytes moved to the quarantine instead, freed once it is purged
+	BytesArchived    int64       // Bytes moved into the archive folder of ActionArchive
+	Files            int         // Files deleted from disk (0 in dry runs and for evictions)
+	Error            error       // Error if operation failed: the first entry left behind, and how many there were
+	Commands         []string    // Commands that would clean the target (dry runs of command-based targets)
 }
 
 // Cleaner is the interface that all domain cleaners must implement
@@ -40,3 +105,459 @@ type Cleaner interface {
 	// Clean executes the actual cleanup operation on the given targets
 	Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error)
 }
+
+// PatternProvider is implemented by cleaners that search project folders for
+// name patterns. Their patterns are matched in one walk shared by all
+// cleaners instead of one walk per pattern.
+type PatternProvider interface {
+	// Patterns returns every pattern Scan passes to FindByPattern
+	Patterns() []string
+}
+
+// NewSharedWalk registers the patterns of every cleaner in one walk of the
+// project folders, limited like the cleaners' own scans. Store it in
+// cfg.SharedWalk so their scanners subscribe to it.
+func NewSharedWalk(ctx context.Context, cfg *config.Config, cleaners []Cleaner) (*scanner.SharedWalk, error) {
+	s, err := scanner.NewScanner()
+	if err != nil {
+		return nil, err
+	}
+	addSearchDirs(s, cfg)
+	s.SetMaxDepth(cfg.ScanMaxDepth)
+	s.SetExcludes(cfg.ScanExcludes)
+	s.SetProfile(cfg.ScanProfile)
+
+	walk := s.NewSharedWalk(ctx)
+	for _, c := range cleaners {
+		if provider, ok := c.(PatternProvider); ok {
+			walk.Register(provider.Patterns()...)
+		}
+	}
+
+	return walk, nil
+}
+
+// addSearchDirs adds the project folders of the config file and the volumes
+// to scan to a scanner. Those missing, such as an unplugged drive, are
+// skipped.
+func addSearchDirs(s *scanner.Scanner, cfg *config.Config) {
+	for _, dir := range append(cfg.SearchDirs, cfg.Volumes...) {
+		s.AddSearchDir(dir)
+	}
+}
+
+// configureScanner applies the search directories, scan limits, profile and
+// shared walk from cfg to a cleaner's scanner
+func configureScanner(s *scanner.Scanner, cfg *config.Config) {
+	if cfg.Home != "" {
+		s.SetHome(cfg.Home)
+	}
+	addSearchDirs(s, cfg)
+	s.SetMaxDepth(cfg.ScanMaxDepth)
+	s.SetExcludes(cfg.ScanExcludes)
+	s.SetProfile(cfg.ScanProfile)
+	s.Subscribe(cfg.SharedWalk)
+}
+
+// cleanTargets removes file-based targets one by one, stopping early if the
+// context is cancelled. It is shared by the cleaners whose targets are plain
+// files and directories.
+func cleanTargets(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	results := make([]CleanResult, 0, len(targets))
+
+	for _, target := range targets {
+		result := CleanResult{
+			Target:  target,
+			Success: true,
+		}
+
+		if !dryRun {
+			result = removeMeasured(ctx, target)
+		} else {
+			// In dry-run, just report what would be freed
+			result.BytesFreed = target.SizeBytes
+		}
+
+		results = append(results, result)
+
+		// Check for cancellation
+		select {
+		case <-ctx.Done():
+			return results, ctx.Err()
+		default:
+		}
+	}
+
+	return results, nil
+}
+
+// removeTarget deletes a target from disk and returns the number of files
+// deleted. It moves the target into store instead if set, and evicts or
+// archives it for ActionEvict and ActionArchive.
+//
+// Targets that list Entries keep Path itself and only have the listed
+// entries removed. Paths protected by a keep marker are never removed. An
+// entry that fails to delete doesn't stop the others; the error counts them
+// and gives the first failure. progress, if set, is called with the number
+// of files deleted so far as the removal goes along.
+func removeTarget(target CleanTarget, store *quarantine.Store, progress func(files int)) (int, error) {
+	// Last line of defence for targets planned before the marker was added
+	if utils.IsProtected(target.Path) {
+		return 0, fmt.Errorf("%s is protected by %s", target.Path, utils.KeepMarker)
+	}
+
+	paths := target.Entries
+	if len(paths) == 0 {
+		paths = []string{target.Path}
+	}
+
+	var report utils.RemoveReport
+	for _, path := range paths {
+		if utils.IsProtected(path) {
+			continue
+		}
+
+		if target.Action == ActionEvict {
+			if err := evict(path); err != nil {
+				report.Errors = append(report.Errors, err)
+			}
+			continue
+		}
+		if target.Action == ActionArchive {
+			if err := archive(path, target.ArchiveDir); err != nil {
+				report.Errors = append(report.Errors, err)
+			}
+			continue
+		}
+
+		if store != nil {
+			entry, err := store.Move(path)
+			if err != nil {
+				report.Errors = append(report.Errors, err)
+			}
+			report.Files += entry.Files
+			continue
+		}
+
+		var onRemove func(int)
+		if progress != nil {
+			deleted := report.Files
+			onRemove = func(files int) {
+				progress(deleted + files)
+			}
+		}
+		removed := utils.RemoveTreeProgress(path, onRemove)
+		report.Files += removed.Files
+		report.Errors = append(report.Errors, removed.Errors...)
+	}
+
+	return report.Files, report.Err()
+}
+
+// removeMeasured removes a target and returns the result with the bytes
+// actually freed: its size on disk before removal minus whatever is left of
+// it afterwards. A target that fails part-way still reports the space and
+// files its removed entries freed. The removal is followed and quarantined
+// as set on ctx by WithRemoveProgress and WithQuarantine. Quarantined and
+// archived bytes are reported as BytesQuarantined and BytesArchived, not
+// freed.
+func removeMeasured(ctx context.Context, target CleanTarget) CleanResult {
+	result := CleanResult{Target: target}
+	progress := removeProgress(ctx)
+	store := quarantineStore(ctx)
+
+	before, total := targetUsage(target)
+	var onRemove func(int)
+	if progress != nil {
+		started := time.Now()
+		onRemove = func(files int) {
+			// Files may have been added since the target was measured
+			progress(RemoveProgress{Target: target, Files: files, Total: max(total, files), Started: started})
+		}
+	}
+	result.Files, result.Error = removeTarget(target, store, onRemove)
+	result.Success = result.Error == nil
+
+	if target.Action == ActionEvict {
+		// Evicted files keep their size on disk, the data is what's gone
+		if result.Success {
+			result.BytesFreed = before
+		}
+		return result
+	}
+
+	// Whatever is left was not freed: files that failed to delete, or
+	// protected entries that were skipped
+	result.BytesFreed = before - targetSize(target)
+	if result.BytesFreed < 0 {
+		// The target grew while it was being removed
+		result.BytesFreed = 0
+	}
+	switch {
+	case target.Action == ActionArchive:
+		// Moved, not freed: the archive folder may be on the same volume
+		result.BytesArchived, result.BytesFreed = result.BytesFreed, 0
+	case store != nil:
+		// Moved, not freed: the space comes back when the quarantine is purged
+		result.BytesQuarantined, result.BytesFreed = result.BytesFreed, 0
+	}
+
+	return result
+}
+
+// targetSize measures a target on disk, counting only its entries if set
+func targetSize(target CleanTarget) int64 {
+	size, _ := targetUsage(target)
+	return size
+}
+
+// targetUsage measures a target on disk and counts its files, counting only
+// its entries if set
+func targetUsage(target CleanTarget) (int64, int) {
+	paths := target.Entries
+	if len(paths) == 0 {
+		paths = []string{target.Path}
+	}
+
+	var total int64
+	files := 0
+	for _, path := range paths {
+		size, count, _ := utils.GetDirUsage(path)
+		total += size
+		files += count
+	}
+	return total, files
+}
+
+// evict removes the local copy of an iCloud Drive file or folder, leaving a
+// placeholder that is downloaded again on demand
+func evict(path string) error {
+	if !utils.CommandExists("brctl") {
+		return fmt.Errorf("cannot evict %s: brctl not found", path)
+	}
+
+	output, err := exec.Command("brctl", "evict", path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("brctl evict %s: %v: %s", path, err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// archive moves a file or folder into dir, which is created if needed. A
+// name already taken in dir gets a number added. Files on another volume
+// are copied, then deleted.
+func archive(path, dir string) error {
+	if dir == "" {
+		return fmt.Errorf("cannot archive %s: no archive folder", path)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	name := filepath.Base(path)
+	ext := filepath.Ext(name)
+	dest := filepath.Join(dir, name)
+	for n := 2; ; n++ {
+		if _, err := os.Lstat(dest); os.IsNotExist(err) {
+			break
+		}
+		dest = filepath.Join(dir, fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(name, ext), n, ext))
+	}
+
+	err := os.Rename(path, dest)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyFile(path, dest); err != nil {
+		os.Remove(dest)
+		return fmt.Errorf("archive %s: %w", path, err)
+	}
+	return os.Remove(path)
+}
+
+// copyFile copies a regular file with its permissions and modification time
+func copyFile(src, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dest, info.ModTime(), info.ModTime())
+}
+
+// ApplyOverrides applies the user's per-category overrides to the targets of
+// a cleaner: disabled categories are dropped, reclassified ones get their new
+// safety level and are dropped if the clean level no longer allows them.
+func ApplyOverrides(cfg *config.Config, domain config.Domain, targets []CleanTarget) []CleanTarget {
+	if len(cfg.Overrides[domain.Key()]) == 0 {
+		return targets
+	}
+
+	kept := make([]CleanTarget, 0, len(targets))
+	for _, target := range targets {
+		if _, ok := cfg.Override(domain, target.Category); ok {
+			if !cfg.Allows(domain, target.Category, target.Safety) {
+				continue
+			}
+			target.Safety = cfg.SafetyFor(domain, target.Category, target.Safety)
+		}
+		kept = append(kept, target)
+	}
+
+	return kept
+}
+
+// excludeProtected drops the targets inside a directory protected by a keep
+// marker. Protected entries are removed from targets that list Entries, and
+// the target is dropped if none are left.
+func excludeProtected(targets []CleanTarget) []CleanTarget {
+	kept := make([]CleanTarget, 0, len(targets))
+
+	for _, target := range targets {
+		if utils.IsProtected(target.Path) {
+			continue
+		}
+
+		if len(target.Entries) > 0 {
+			entries := make([]string, 0, len(target.Entries))
+			for _, entry := range target.Entries {
+				if !utils.IsProtected(entry) {
+					entries = append(entries, entry)
+					continue
+				}
+				size, _ := utils.GetDirSize(entry)
+				target.SizeBytes -= size
+			}
+			if len(entries) == 0 {
+				continue
+			}
+			target.Entries = entries
+		}
+
+		kept = append(kept, target)
+	}
+
+	return kept
+}
+
+// evictCloudTargets switches targets synced with iCloud Drive to ActionEvict:
+// deleting them would delete them from every device, while evicting frees
+// the local disk and keeps them in the cloud
+func evictCloudTargets(targets []CleanTarget) []CleanTarget {
+	for i, target := range targets {
+		if target.Action == ActionDelete && utils.IsICloudPath(target.Path) {
+			targets[i].Action = ActionEvict
+			targets[i].Description += " (evict from local disk)"
+		}
+	}
+	return targets
+}
+
+// EnforcePolicy drops the targets the admin policy does not allow: forbidden
+// categories, safety levels above its maximum clean level and paths it
+// excludes, or that hold one. It runs whatever the flags and the user's
+// config say.
+func EnforcePolicy(cfg *config.Config, domain config.Domain, targets []CleanTarget) []CleanTarget {
+	if cfg.Policy == nil {
+		return targets
+	}
+
+	kept := make([]CleanTarget, 0, len(targets))
+	for _, target := range targets {
+		if cfg.Policy.Forbids(domain, target.Category) || !cfg.Policy.AllowsSafety(target.Safety) || cfg.Policy.ExcludesPath(target.Path) {
+			continue
+		}
+		// Targets that list entries keep their path, only the entries count
+		if len(target.Entries) == 0 && cfg.Policy.ExcludesWithin(target.Path) {
+			continue
+		}
+
+		if len(target.Entries) > 0 {
+			entries := make([]string, 0, len(target.Entries))
+			for _, entry := range target.Entries {
+				if !cfg.Policy.ExcludesPath(entry) && !cfg.Policy.ExcludesWithin(entry) {
+					entries = append(entries, entry)
+					continue
+				}
+				size, _ := utils.GetDirSize(entry)
+				target.SizeBytes -= size
+			}
+			if len(entries) == 0 {
+				continue
+			}
+			target.Entries = entries
+		}
+
+		kept = append(kept, target)
+	}
+
+	return kept
+}
+
+// selectTargets leaves out the targets of the categories the selection
+// (--domain, --only) doesn't include
+func selectTargets(cfg *config.Config, domain config.Domain, targets []CleanTarget) []CleanTarget {
+	if len(cfg.Selection) == 0 {
+		return targets
+	}
+
+	selected := make([]CleanTarget, 0, len(targets))
+	for _, target := range targets {
+		if cfg.Selection.Selects(domain, target.Category) {
+			selected = append(selected, target)
+		}
+	}
+	return selected
+}
+
+// prepareTargets applies the user's overrides to the selected scan results,
+// leaves out protected targets, escalates those of projects in use or
+// recently changed, enforces the admin policy and marks cloud-synced ones
+// for eviction
+func prepareTargets(cfg *config.Config, domain config.Domain, targets []CleanTarget) []CleanTarget {
+	targets = selectTargets(cfg, domain, targets)
+	targets = guardActiveProjects(cfg, guardOpenProjects(cfg, excludeProtected(ApplyOverrides(cfg, domain, targets))), time.Now())
+	return evictCloudTargets(EnforcePolicy(cfg, domain, targets))
+}
+
+// categoryName turns a file pattern or label into a target category, e.g.
+// ".parcel-cache" -> "parcel_cache", "npm-debug.log*" -> "npm_debug_log"
+func categoryName(s string) string {
+	var b strings.Builder
+	separate := false
+
+	for _, r := range strings.ToLower(s) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			separate = true
+			continue
+		}
+		if separate && b.Len() > 0 {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+		separate = false
+	}
+
+	return b.String()
+}
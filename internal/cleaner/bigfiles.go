@@ -0,0 +1,57 @@
+package cleaner
+
+import (
+	"context"
+	"sort"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+// FindLargestFiles returns the n largest files of at least minSize bytes,
+// largest first, in dirs or in the search directories when dirs is empty.
+// Unlike the cleaners it looks at single files of any kind: disk images,
+// logs, archives or videos left in project folders.
+func FindLargestFiles(ctx context.Context, cfg *config.Config, dirs []string, minSize int64, n int) ([]scanner.ScanResult, error) {
+	s, err := newSearchScanner(cfg, dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	largest := []scanner.ScanResult{}
+	for result := range s.FindLargeFiles(ctx, minSize) {
+		if result.Err != nil {
+			continue
+		}
+		if len(largest) == n && result.Size <= largest[n-1].Size {
+			continue
+		}
+
+		// Keep the n largest so far, sorted
+		i := sort.Search(len(largest), func(i int) bool {
+			return largest[i].Size < result.Size
+		})
+		largest = append(largest, scanner.ScanResult{})
+		copy(largest[i+1:], largest[i:])
+		largest[i] = result
+		if len(largest) > n {
+			largest = largest[:n]
+		}
+	}
+
+	return largest, ctx.Err()
+}
+
+// newSearchScanner returns a scanner configured from cfg that searches dirs,
+// or the search directories when dirs is empty
+func newSearchScanner(cfg *config.Config, dirs []string) (*scanner.Scanner, error) {
+	s, err := scanner.NewScanner()
+	if err != nil {
+		return nil, err
+	}
+	configureScanner(s, cfg)
+	if len(dirs) > 0 {
+		s.SetSearchDirs(dirs)
+	}
+	return s, nil
+}
@@ -0,0 +1,38 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestFindLargestFiles(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	for name, size := range map[string]int{"a.dmg": 500, "b.mov": 900, "c.log": 700, "d.zip": 300, "e.txt": 50} {
+		createTestFile(t, tmpDir, "app/"+name, strings.Repeat("x", size))
+	}
+
+	files, err := FindLargestFiles(ctx, config.NewDefaultConfig(), []string{tmpDir}, 100, 3)
+	if err != nil {
+		t.Fatalf("FindLargestFiles() error = %v", err)
+	}
+
+	expected := []string{"b.mov", "c.log", "a.dmg"}
+	if len(files) != len(expected) {
+		t.Fatalf("Expected %d files, got %+v", len(expected), files)
+	}
+	for i, name := range expected {
+		if filepath.Base(files[i].Path) != name {
+			t.Errorf("File %d = %s, want %s", i, files[i].Path, name)
+		}
+	}
+}
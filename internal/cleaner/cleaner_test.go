@@ -2,12 +2,22 @@ package cleaner
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/platform"
+	"github.com/0SansNom/epurer/internal/quarantine"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
 )
 
 // testTimeout is the default timeout for tests
@@ -77,6 +87,35 @@ func TestFrontendCleaner_Domain(t *testing.T) {
 	}
 }
 
+func TestFrontendCleaner_DetectSharesDetector(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Fake commands are shell scripts")
+	}
+	bin := setupTestDir(t)
+	defer os.RemoveAll(bin)
+	createTestFile(t, bin, "node", "#!/bin/sh\n")
+	if err := os.Chmod(filepath.Join(bin, "node"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	det, err := detector.NewDetector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleaner, _ := NewFrontendCleaner()
+	if detected, _ := cleaner.Detect(detector.WithDetector(context.Background(), det)); !detected {
+		t.Fatal("Expected node on PATH to be detected")
+	}
+
+	// detect gets the node the cleaner found, without looking again
+	os.RemoveAll(bin)
+	result := det.DetectAll()
+	if len(result.Frontend) != 1 || result.Frontend[0].Name != "node" {
+		t.Errorf("Expected the node the cleaner found, got %+v", result.Frontend)
+	}
+}
+
 func TestFrontendCleaner_Detect(t *testing.T) {
 	cleaner, _ := NewFrontendCleaner()
 	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
@@ -642,8 +681,9 @@ func TestNewSystemCleaners(t *testing.T) {
 		{"DNSCacheCleaner", NewDNSCacheCleaner, "DNS Cache"},
 		{"HomebrewCleaner", NewHomebrewCleaner, "Homebrew Cache"},
 		{"XcodeCleaner", NewXcodeCleaner, "Xcode DerivedData"},
-		{"LaunchpadCleaner", NewLaunchpadCleaner, "Launchpad Database"},
+		{"LaunchpadCleaner", NewLaunchpadCleaner, "Launchpad & Dock"},
 		{"IOSBackupCleaner", NewIOSBackupCleaner, "iOS Backups"},
+		{"MediaCacheCleaner", NewMediaCacheCleaner, "Mail & Media Caches"},
 	}
 
 	for _, tt := range tests {
@@ -690,6 +730,44 @@ func TestSystemCleaner_Detect(t *testing.T) {
 	}
 }
 
+func TestSystemCleaner_Detect_MacOSOnly(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	for _, c := range []Cleaner{NewDNSCacheCleaner(), NewLaunchpadCleaner(), NewIOSBackupCleaner(), NewMediaCacheCleaner()} {
+		if detected, _ := c.Detect(ctx); detected != platform.IsMacOS() {
+			t.Errorf("Expected %s to be detected only on macOS, got %v on %s", c.Name(), detected, platform.Name())
+		}
+	}
+}
+
+func TestStaleTempFiles(t *testing.T) {
+	tmp := setupTestDir(t)
+	defer os.RemoveAll(tmp)
+
+	old := time.Now().Add(-30 * day)
+	stale := createTestFile(t, tmp, "build-1234/output.o", "object")
+	createTestFile(t, tmp, "recent.log", "recent")
+	createTestFile(t, tmp, "tmux-1000/default.state", "state")
+	createTestFile(t, tmp, ".X0-lock", "lock")
+	createTestFile(t, tmp, "ide/session.state", "state")
+	for _, path := range []string{stale, filepath.Join(tmp, "tmux-1000"), filepath.Join(tmp, ".X0-lock"), filepath.Join(tmp, "ide")} {
+		setOldTimes(t, path, old)
+	}
+
+	// A folder holding a socket belongs to a running program
+	listener, err := net.Listen("unix", filepath.Join(tmp, "ide", "ide.sock"))
+	if err != nil {
+		t.Skipf("Can't create a socket: %v", err)
+	}
+	defer listener.Close()
+
+	files, size := staleTempFiles(tmp, time.Now())
+	if !slices.Equal(files, []string{stale}) || size != int64(len("object")) {
+		t.Errorf("Expected only the old build file, got %v (%d bytes)", files, size)
+	}
+}
+
 // =============================================================================
 // CleanTarget and CleanResult Tests
 // =============================================================================
@@ -745,6 +823,79 @@ func TestCleanResult_Fields(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Category Override Tests
+// =============================================================================
+
+func TestCategoryName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"node_modules", "node_modules"},
+		{".parcel-cache", "parcel_cache"},
+		{"__pycache__", "pycache"},
+		{"npm-debug.log*", "npm_debug_log"},
+		{"NuGet HTTP cache", "nuget_http_cache"},
+		{"llama.cpp", "llama_cpp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := categoryName(tt.input); got != tt.expected {
+				t.Errorf("categoryName(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	safe := config.Safe
+	dangerous := config.Dangerous
+	disabled := false
+
+	targets := []CleanTarget{
+		{Path: "/a/node_modules", Category: "node_modules", Safety: config.Moderate},
+		{Path: "/a/dist", Category: "dist", Safety: config.Safe},
+		{Path: "/npm", Category: "npm_cache", Safety: config.Safe},
+		{Path: "/yarn", Category: "yarn_cache", Safety: config.Safe},
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.CleanLevel = config.Aggressive
+	cfg.Overrides = map[string]map[string]config.Override{
+		"frontend": {
+			"node_modules": {Safety: &dangerous},
+			"npm_cache":    {Enabled: &disabled},
+			"yarn_cache":   {Safety: &safe},
+		},
+	}
+
+	result := ApplyOverrides(cfg, config.DomainFrontend, targets)
+	if len(result) != 3 {
+		t.Fatalf("Expected disabled category to be dropped, got %d targets", len(result))
+	}
+	if result[0].Safety != config.Dangerous {
+		t.Errorf("Expected node_modules to be Dangerous, got %v", result[0].Safety)
+	}
+	if result[1].Safety != config.Safe {
+		t.Errorf("Expected dist to keep its safety, got %v", result[1].Safety)
+	}
+
+	// Reclassified targets no longer allowed by the level are dropped
+	cfg.CleanLevel = config.Standard
+	result = ApplyOverrides(cfg, config.DomainFrontend, targets)
+	if len(result) != 2 {
+		t.Errorf("Expected node_modules to be dropped in standard mode, got %d targets", len(result))
+	}
+
+	// Overrides only apply to their domain
+	result = ApplyOverrides(cfg, config.DomainBackend, targets)
+	if len(result) != len(targets) {
+		t.Errorf("Expected targets of another domain to be untouched, got %d targets", len(result))
+	}
+}
+
 // =============================================================================
 // Safety Level Filtering Tests
 // =============================================================================
@@ -870,3 +1021,375 @@ func TestCleaner_EmptyTargets(t *testing.T) {
 		t.Errorf("Expected 0 results, got %d", len(results))
 	}
 }
+
+func TestCleaner_CleanProtectedPath(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	project := createTestDir(t, tmpDir, "client-app", map[string]string{
+		".epurer-keep":         "",
+		"node_modules/pkg.js":  "module.exports = {}",
+		"dist/bundle.js":       "bundle",
+		"dist/assets/logo.svg": "<svg/>",
+	})
+
+	cleaner, _ := NewFrontendCleaner()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	targets := []CleanTarget{
+		{Path: filepath.Join(project, "node_modules"), SizeBytes: 19, Safety: config.Moderate},
+		{Path: filepath.Join(project, "dist"), SizeBytes: 12, Safety: config.Safe, Entries: []string{filepath.Join(project, "dist", "bundle.js")}},
+	}
+
+	results, _ := cleaner.Clean(ctx, targets, false)
+	for _, result := range results {
+		if result.Success {
+			t.Errorf("Expected %s to be refused", result.Target.Path)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(project, "node_modules", "pkg.js")); err != nil {
+		t.Error("Protected node_modules was deleted")
+	}
+	if _, err := os.Stat(filepath.Join(project, "dist", "bundle.js")); err != nil {
+		t.Error("Protected entry was deleted")
+	}
+}
+
+func TestExcludeProtected(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	kept := createTestDir(t, tmpDir, "kept", map[string]string{
+		".epurer-keep": "",
+		"cache/a":      "12345",
+	})
+	free := createTestDir(t, tmpDir, "free", map[string]string{
+		"cache/b": "123",
+	})
+
+	targets := []CleanTarget{
+		{Path: filepath.Join(kept, "cache"), SizeBytes: 5},
+		{Path: filepath.Join(free, "cache"), SizeBytes: 3},
+		{Path: tmpDir, SizeBytes: 8, Entries: []string{filepath.Join(kept, "cache"), filepath.Join(free, "cache")}},
+		{Path: tmpDir, SizeBytes: 5, Entries: []string{filepath.Join(kept, "cache")}},
+		{Path: "docker:buildcache", SizeBytes: 100},
+	}
+
+	result := excludeProtected(targets)
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 targets, got %d: %+v", len(result), result)
+	}
+	if result[0].Path != filepath.Join(free, "cache") {
+		t.Errorf("Expected unprotected target to be kept, got %s", result[0].Path)
+	}
+	if len(result[1].Entries) != 1 || result[1].SizeBytes != 3 {
+		t.Errorf("Expected protected entry to be removed from target, got %+v", result[1])
+	}
+	if result[2].Path != "docker:buildcache" {
+		t.Errorf("Expected non-filesystem target to be kept, got %s", result[2].Path)
+	}
+}
+
+func TestEnforcePolicy(t *testing.T) {
+	level := config.Standard
+	cfg := config.NewDefaultConfig()
+	cfg.Policy = &config.Policy{
+		MaxCleanLevel: &level,
+		Forbidden:     []string{"system.ios_backups"},
+		Excludes:      []string{"/work/legal", "/Library/Caches/com.corp/keep"},
+	}
+
+	targets := []CleanTarget{
+		{Path: "/backups/a", Category: "ios_backups", Safety: config.Moderate},
+		{Path: "/Library/Caches", Category: "caches", Entries: []string{"/Library/Caches/com.corp", "/Library/Caches/com.other"}, Safety: config.Safe},
+		{Path: "/Downloads/old.dmg", Category: "installers", Safety: config.Dangerous},
+		{Path: "/work/legal/app/node_modules", Category: "caches", Safety: config.Safe},
+		{Path: "/work", Category: "caches", Entries: []string{"/work/legal/app", "/work/web"}},
+		// Removing it would remove the excluded folder inside it
+		{Path: "/Library/Caches/com.corp", Category: "caches", Safety: config.Safe},
+	}
+
+	result := EnforcePolicy(cfg, config.DomainSystem, targets)
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 targets, got %d: %+v", len(result), result)
+	}
+	if result[0].Path != "/Library/Caches" || len(result[0].Entries) != 1 || result[0].Entries[0] != "/Library/Caches/com.other" {
+		t.Errorf("Expected the allowed target without the entry holding an exclude, got %+v", result[0])
+	}
+	if len(result[1].Entries) != 1 || result[1].Entries[0] != "/work/web" {
+		t.Errorf("Expected the excluded entry to be removed, got %+v", result[1])
+	}
+}
+
+func TestSelectTargets(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Selection = config.Selection{{Domain: "frontend", Category: "npm_cache"}, {Category: "node_modules"}}
+
+	targets := []CleanTarget{
+		{Path: "/a/node_modules", Category: "node_modules"},
+		{Path: "/npm", Category: "npm_cache"},
+		{Path: "/yarn", Category: "yarn_cache"},
+	}
+
+	result := selectTargets(cfg, config.DomainFrontend, targets)
+	if len(result) != 2 || result[0].Path != "/a/node_modules" || result[1].Path != "/npm" {
+		t.Errorf("Expected node_modules and the npm cache, got %+v", result)
+	}
+	if result := selectTargets(cfg, config.DomainBackend, targets); len(result) != 1 {
+		t.Errorf("Expected only node_modules outside the frontend, got %+v", result)
+	}
+
+	cfg.Selection = nil
+	if result := selectTargets(cfg, config.DomainFrontend, targets); len(result) != 3 {
+		t.Errorf("Expected every target without a selection, got %+v", result)
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Action
+		wantErr  bool
+	}{
+		{"", ActionDelete, false},
+		{"delete", ActionDelete, false},
+		{"evict", ActionEvict, false},
+		{"archive", ActionArchive, false},
+		{"run", ActionRun, false},
+		{"move", ActionDelete, true},
+	}
+
+	for _, tt := range tests {
+		action, err := ParseAction(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAction(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if action != tt.expected {
+			t.Errorf("ParseAction(%q) = %s, expected %s", tt.input, action, tt.expected)
+		}
+		if !tt.wantErr && tt.input != "" && action.String() != tt.input {
+			t.Errorf("Action.String() = %s, expected %s", action.String(), tt.input)
+		}
+	}
+}
+
+func TestEvictCloudTargets(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cloudPath := filepath.Join(home, "Library", "Mobile Documents", "com~apple~CloudDocs", "app", "node_modules")
+	targets := []CleanTarget{
+		{Path: cloudPath, Description: "node_modules"},
+		{Path: filepath.Join(home, "code", "app", "node_modules"), Description: "node_modules"},
+	}
+
+	result := evictCloudTargets(targets)
+	if result[0].Action != ActionEvict {
+		t.Errorf("Expected iCloud target to be evicted, got %s", result[0].Action)
+	}
+	if !strings.Contains(result[0].Description, "evict") {
+		t.Errorf("Expected description to mention eviction, got %q", result[0].Description)
+	}
+	if result[1].Action != ActionDelete {
+		t.Errorf("Expected local target to be deleted, got %s", result[1].Action)
+	}
+}
+
+func TestCleanTargets_RemoveProgress(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	files := map[string]string{}
+	for i := range utils.RemoveBatch + 5 {
+		files[filepath.Join(string(rune('a'+i%4)), fmt.Sprintf("%d.o", i))] = ""
+	}
+	derived := createTestDir(t, tmpDir, "DerivedData", files)
+	small := createTestDir(t, tmpDir, "small", map[string]string{"a.bin": "0123456789"})
+
+	var reported []RemoveProgress
+	ctx := WithRemoveProgress(context.Background(), func(progress RemoveProgress) {
+		reported = append(reported, progress)
+	})
+	if _, err := cleanTargets(ctx, []CleanTarget{{Path: derived}, {Path: small}}, false); err != nil {
+		t.Fatalf("cleanTargets() returned error: %v", err)
+	}
+
+	// Only the large target reports progress, against its counted files
+	if len(reported) != 1 {
+		t.Fatalf("Expected one progress report, got %+v", reported)
+	}
+	if reported[0].Target.Path != derived || reported[0].Files != utils.RemoveBatch || reported[0].Total != utils.RemoveBatch+5 {
+		t.Errorf("Unexpected progress %+v", reported[0])
+	}
+}
+
+func TestCleanTargets_Quarantine(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	cache := createTestDir(t, tmpDir, "cache", map[string]string{"a.bin": "0123456789", "b.bin": "0123456789"})
+	store := quarantine.New(filepath.Join(tmpDir, "quarantine"))
+
+	ctx := WithQuarantine(context.Background(), store)
+	results, err := cleanTargets(ctx, []CleanTarget{{Path: cache}}, false)
+	if err != nil {
+		t.Fatalf("cleanTargets() returned error: %v", err)
+	}
+	if !results[0].Success || results[0].Files != 2 {
+		t.Errorf("Unexpected result %+v", results[0])
+	}
+	// Still on disk: moved, not freed
+	if results[0].BytesFreed != 0 || results[0].BytesQuarantined == 0 {
+		t.Errorf("Expected the bytes reported as quarantined, got %+v", results[0])
+	}
+
+	// Moved aside, not deleted
+	entries, _ := store.Entries()
+	if len(entries) != 1 || entries[0].OriginalPath != cache {
+		t.Fatalf("Expected the target in the quarantine, got %+v", entries)
+	}
+	if _, err := os.Stat(filepath.Join(store.Path(entries[0]), "a.bin")); err != nil {
+		t.Errorf("Expected the files kept in the quarantine: %v", err)
+	}
+}
+
+func TestCleanTargets_Archive(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	shot := createTestFile(t, tmpDir, "Desktop/Screenshot.png", "0123456789")
+	archiveDir := filepath.Join(tmpDir, "Archive")
+
+	results, err := cleanTargets(context.Background(), []CleanTarget{{Path: shot, Action: ActionArchive, ArchiveDir: archiveDir}}, false)
+	if err != nil {
+		t.Fatalf("cleanTargets() returned error: %v", err)
+	}
+	// Moved, not freed
+	if !results[0].Success || results[0].BytesFreed != 0 || results[0].BytesArchived == 0 {
+		t.Errorf("Expected the bytes reported as archived, got %+v", results[0])
+	}
+	if !utils.PathExists(filepath.Join(archiveDir, "Screenshot.png")) {
+		t.Error("Expected the file in the archive folder")
+	}
+}
+
+func TestCleanTargets_MeasuresBytesFreed(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	// Estimated well above what is on disk
+	cache := createTestDir(t, tmpDir, "cache", map[string]string{
+		"a.bin": "0123456789",
+		"b.bin": "0123456789",
+	})
+
+	// One entry is protected and stays behind
+	logs := createTestDir(t, tmpDir, "logs", map[string]string{
+		"old.log":                  "0123456789",
+		"kept/important.log":       "0123456789",
+		"kept/" + utils.KeepMarker: "",
+	})
+
+	// Protected target: nothing is removed
+	protected := createTestDir(t, tmpDir, "protected", map[string]string{
+		"data.bin":       "0123456789",
+		utils.KeepMarker: "",
+	})
+
+	targets := []CleanTarget{
+		{Path: cache, SizeBytes: 1000},
+		{Path: logs, SizeBytes: 20, Entries: []string{filepath.Join(logs, "old.log"), filepath.Join(logs, "kept")}},
+		{Path: protected, SizeBytes: 10},
+	}
+
+	results, err := cleanTargets(context.Background(), targets, false)
+	if err != nil {
+		t.Fatalf("cleanTargets() returned error: %v", err)
+	}
+
+	expected := []struct {
+		success bool
+		freed   int64
+	}{
+		{true, 20},
+		{true, 10},
+		{false, 0},
+	}
+	for i, tt := range expected {
+		if results[i].Success != tt.success {
+			t.Errorf("%s: expected success %v, got %v (%v)", targets[i].Path, tt.success, results[i].Success, results[i].Error)
+		}
+		if results[i].BytesFreed != tt.freed {
+			t.Errorf("%s: expected %d bytes freed, got %d", targets[i].Path, tt.freed, results[i].BytesFreed)
+		}
+	}
+
+	// Dry runs report the estimate
+	results, _ = cleanTargets(context.Background(), targets[:1], true)
+	if results[0].BytesFreed != 1000 {
+		t.Errorf("Expected dry run to report the estimate, got %d", results[0].BytesFreed)
+	}
+}
+
+func TestPatternProviders_SharedWalk(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// Version managers and Gradle wrappers make their cleaners look for
+	// project files too
+	for _, dir := range []string{
+		filepath.Join(home, ".rbenv", "versions", "3.2.0"),
+		filepath.Join(home, ".gradle", "wrapper", "dists", "gradle-8.0-bin"),
+		filepath.Join(home, "Projects", "app", "node_modules"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	projects := filepath.Join(home, "Projects")
+
+	cleaners := map[string]func(*scanner.Scanner) Cleaner{
+		"frontend":       func(s *scanner.Scanner) Cleaner { return &FrontendCleaner{scanner: s} },
+		"backend":        func(s *scanner.Scanner) Cleaner { return &BackendCleaner{scanner: s} },
+		"electron":       func(s *scanner.Scanner) Cleaner { return &ElectronCleaner{scanner: s} },
+		"dotnet":         func(s *scanner.Scanner) Cleaner { return &DotNetCleaner{scanner: s} },
+		"gamedev":        func(s *scanner.Scanner) Cleaner { return &GameDevCleaner{scanner: s} },
+		"mobile":         func(s *scanner.Scanner) Cleaner { return &MobileCleaner{scanner: s} },
+		"dataml":         func(s *scanner.Scanner) Cleaner { return &DataMLCleaner{scanner: s} },
+		"devops":         func(s *scanner.Scanner) Cleaner { return &DevOpsCleaner{scanner: s} },
+		"versionmanager": func(s *scanner.Scanner) Cleaner { return &VersionManagerCleaner{scanner: s} },
+	}
+
+	for name, newCleaner := range cleaners {
+		t.Run(name, func(t *testing.T) {
+			own, _ := scanner.NewScannerWithDirs([]string{projects})
+			c := newCleaner(own)
+
+			profile := scanner.NewProfile()
+			walkScanner, _ := scanner.NewScannerWithDirs([]string{projects})
+			walkScanner.SetProfile(profile)
+			walk := walkScanner.NewSharedWalk(context.Background())
+			walk.Register(c.(PatternProvider).Patterns()...)
+
+			cfg := config.NewDefaultConfig()
+			cfg.CleanLevel = config.Aggressive
+			cfg.ScanProfile = profile
+			cfg.SharedWalk = walk
+
+			ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+			defer cancel()
+			if _, err := c.Scan(ctx, cfg); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+
+			// A pattern missing from Patterns walks the tree on its own
+			for _, stats := range cfg.ScanProfile.Dirs() {
+				if stats.Root && stats.Walks > 1 {
+					t.Errorf("Expected one walk of %s, got %d: a pattern is missing from Patterns()", stats.Path, stats.Walks)
+				}
+			}
+		})
+	}
+}
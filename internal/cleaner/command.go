@@ -0,0 +1,90 @@
+package cleaner
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// CommandRunner runs the external commands of cleaners that clean through
+// other tools (Docker, Homebrew, ...). Output is for queries that change
+// nothing; Run is for commands that clean.
+type CommandRunner interface {
+	Output(name string, args ...string) ([]byte, error)
+	Run(name string, args ...string) error
+}
+
+// ExecRunner runs commands with os/exec
+type ExecRunner struct{}
+
+// Output runs a command and returns its standard output
+func (ExecRunner) Output(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// Run runs a command and waits for it to finish
+func (ExecRunner) Run(name string, args ...string) error {
+	return exec.Command(name, args...).Run()
+}
+
+// RecordingRunner records the commands passed to Run instead of running
+// them. Output calls go to Runner when it is set, so a dry run can still
+// query the real tools, and are answered from Outputs otherwise (in tests).
+type RecordingRunner struct {
+	Runner  CommandRunner     // Runs Output calls, if set
+	Outputs map[string]string // Output by command line, used when Runner is nil
+	Errors  map[string]error  // Error returned for a command line, by Output or Run
+
+	mu       sync.Mutex
+	commands []string
+}
+
+// Output returns the output of a query
+func (r *RecordingRunner) Output(name string, args ...string) ([]byte, error) {
+	line := commandLine(name, args...)
+	if err := r.Errors[line]; err != nil {
+		return nil, err
+	}
+	if r.Runner != nil {
+		return r.Runner.Output(name, args...)
+	}
+	output, ok := r.Outputs[line]
+	if !ok {
+		return nil, fmt.Errorf("no output recorded for %q", line)
+	}
+	return []byte(output), nil
+}
+
+// Run records a command
+func (r *RecordingRunner) Run(name string, args ...string) error {
+	line := commandLine(name, args...)
+
+	r.mu.Lock()
+	r.commands = append(r.commands, line)
+	r.mu.Unlock()
+
+	return r.Errors[line]
+}
+
+// Commands returns the command lines passed to Run so far, and forgets them
+func (r *RecordingRunner) Commands() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	commands := r.commands
+	r.commands = nil
+	return commands
+}
+
+// commandLine formats a command for display, quoting arguments with spaces
+func commandLine(name string, args ...string) string {
+	parts := []string{name}
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = fmt.Sprintf("%q", arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,100 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestRecordingRunner(t *testing.T) {
+	failure := errors.New("exit status 1")
+	runner := &RecordingRunner{
+		Outputs: map[string]string{"brew --cache": "/cache\n"},
+		Errors:  map[string]error{"docker volume prune -f": failure},
+	}
+
+	output, err := runner.Output("brew", "--cache")
+	if err != nil || string(output) != "/cache\n" {
+		t.Errorf("Output() = %q, %v", output, err)
+	}
+	if _, err := runner.Output("brew", "--prefix"); err == nil {
+		t.Error("Expected an error for a command without recorded output")
+	}
+
+	if err := runner.Run("docker", "image", "prune", "-f"); err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+	if err := runner.Run("docker", "volume", "prune", "-f"); err != failure {
+		t.Errorf("Run() error = %v, expected %v", err, failure)
+	}
+
+	expected := []string{"docker image prune -f", "docker volume prune -f"}
+	if commands := runner.Commands(); !slices.Equal(commands, expected) {
+		t.Errorf("Commands() = %v, expected %v", commands, expected)
+	}
+	if commands := runner.Commands(); len(commands) != 0 {
+		t.Errorf("Commands() should be empty once read, got %v", commands)
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	got := commandLine("colima", "ssh", "--", "echo", "hello world")
+	if got != `colima ssh -- echo "hello world"` {
+		t.Errorf("commandLine() = %s", got)
+	}
+}
+
+func TestDevOpsCleaner_Clean_Commands(t *testing.T) {
+	runner := &RecordingRunner{}
+	c := &DevOpsCleaner{runner: runner}
+	targets := []CleanTarget{{Path: "docker:buildcache", SizeBytes: 100, Safety: config.Safe}}
+
+	// Dry run: the command is listed but not run
+	results, err := c.Clean(context.Background(), targets, true)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if !slices.Equal(results[0].Commands, []string{"docker builder prune -f"}) {
+		t.Errorf("Expected dry run to list the prune command, got %v", results[0].Commands)
+	}
+	if commands := runner.Commands(); len(commands) != 0 {
+		t.Errorf("Dry run ran commands: %v", commands)
+	}
+
+	// Real run
+	results, _ = c.Clean(context.Background(), targets, false)
+	if !results[0].Success || results[0].BytesFreed != 100 {
+		t.Errorf("Unexpected result %+v", results[0])
+	}
+	if commands := runner.Commands(); !slices.Equal(commands, []string{"docker builder prune -f"}) {
+		t.Errorf("Expected the prune command to run, got %v", commands)
+	}
+}
+
+func TestSystemCleaner_Homebrew_Commands(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "downloads/wget.bottle.tar.gz", "bottle")
+
+	runner := &RecordingRunner{Outputs: map[string]string{"brew --cache": tmpDir + "\n"}}
+	c := &SystemCleaner{cleanerType: TypeHomebrew}
+	c.SetRunner(runner)
+
+	targets, err := c.scanHomebrew()
+	if err != nil || len(targets) != 1 || targets[0].Path != tmpDir {
+		t.Fatalf("scanHomebrew() = %v, %v", targets, err)
+	}
+
+	results, _ := c.Clean(context.Background(), targets, false)
+	if !results[0].Success {
+		t.Errorf("Clean() failed: %v", results[0].Error)
+	}
+	if commands := runner.Commands(); !slices.Equal(commands, []string{"brew cleanup --prune=all"}) {
+		t.Errorf("Expected brew cleanup to run, got %v", commands)
+	}
+}
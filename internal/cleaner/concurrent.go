@@ -0,0 +1,165 @@
+package cleaner
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// CleanJob is a target to clean with the cleaner that found it
+type CleanJob struct {
+	Cleaner Cleaner
+	Target  CleanTarget
+}
+
+// JobResult is the outcome of the job at Index of a CleanConcurrently call
+type JobResult struct {
+	Index  int
+	Result CleanResult
+}
+
+// CleanConcurrently cleans jobs with up to workers cleaners running at
+// once. Deletions are I/O-bound and those of different cleaners are
+// independent, but the jobs of one cleaner run one after the other, in
+// order: its targets may be nested, and command-based cleaners (brew,
+// docker) must not run twice at once.
+//
+// done, if set, is called with each result as soon as its job finishes, from
+// one goroutine at a time. Once ctx is cancelled the deletions in flight
+// finish and the jobs not started yet are left out. The results are
+// returned in job order.
+func CleanConcurrently(ctx context.Context, jobs []CleanJob, workers int, dryRun bool, done func(JobResult)) []JobResult {
+	// One queue per cleaner, in the order cleaners first appear
+	queues := [][]int{}
+	queueOf := make(map[string]int)
+	for i, job := range jobs {
+		name := job.Cleaner.Name()
+		q, ok := queueOf[name]
+		if !ok {
+			q = len(queues)
+			queueOf[name] = q
+			queues = append(queues, nil)
+		}
+		queues[q] = append(queues[q], i)
+	}
+
+	workers = max(1, min(workers, len(queues)))
+	queueCh := make(chan []int)
+	results := make(chan JobResult)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for queue := range queueCh {
+				for _, i := range queue {
+					result, ok := cleanJob(ctx, jobs[i], dryRun)
+					if !ok {
+						break
+					}
+					results <- JobResult{Index: i, Result: result}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		for _, queue := range queues {
+			queueCh <- queue
+		}
+		close(queueCh)
+		wg.Wait()
+		close(results)
+	}()
+
+	finished := []JobResult{}
+	for result := range results {
+		if done != nil {
+			done(result)
+		}
+		finished = append(finished, result)
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].Index < finished[j].Index
+	})
+	return finished
+}
+
+// cleanJob cleans the target of a job. It returns false if the job was
+// cancelled before its target was touched.
+func cleanJob(ctx context.Context, job CleanJob, dryRun bool) (CleanResult, bool) {
+	if pause := pauseOf(ctx); pause != nil {
+		pause.wait(ctx)
+	}
+	if ctx.Err() != nil {
+		return CleanResult{}, false
+	}
+
+	results, err := job.Cleaner.Clean(ctx, []CleanTarget{job.Target}, dryRun)
+	if len(results) > 0 {
+		return results[0], true
+	}
+	if ctx.Err() != nil {
+		return CleanResult{}, false
+	}
+	return CleanResult{Target: job.Target, Error: err}, true
+}
+
+// Pause holds back the jobs of CleanConcurrently that have not started yet
+// while it is set. The deletions in flight finish.
+type Pause struct {
+	mu      sync.Mutex
+	resumed chan struct{} // Closed on resuming, nil while not paused
+}
+
+// Set pauses the jobs not started yet, or resumes them
+func (p *Pause) Set(paused bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case paused && p.resumed == nil:
+		p.resumed = make(chan struct{})
+	case !paused && p.resumed != nil:
+		close(p.resumed)
+		p.resumed = nil
+	}
+}
+
+// Paused reports whether the jobs are held back
+func (p *Pause) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.resumed != nil
+}
+
+// wait returns once the jobs are resumed or ctx is cancelled
+func (p *Pause) wait(ctx context.Context) {
+	p.mu.Lock()
+	resumed := p.resumed
+	p.mu.Unlock()
+
+	if resumed != nil {
+		select {
+		case <-resumed:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// pauseKey is the context key of the pause set by WithPause
+type pauseKey struct{}
+
+// WithPause returns a context under which CleanConcurrently holds back the
+// jobs not started yet while pause is set
+func WithPause(ctx context.Context, pause *Pause) context.Context {
+	return context.WithValue(ctx, pauseKey{}, pause)
+}
+
+// pauseOf returns the pause set by WithPause, or nil
+func pauseOf(ctx context.Context) *Pause {
+	pause, _ := ctx.Value(pauseKey{}).(*Pause)
+	return pause
+}
@@ -0,0 +1,148 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+// trackingCleaner records how many of its Clean calls run at once, across
+// every trackingCleaner sharing the same counters
+type trackingCleaner struct {
+	name    string
+	delay   time.Duration
+	running *atomic.Int32
+	peak    *atomic.Int32
+
+	mu      sync.Mutex
+	overlap bool // Two calls of this cleaner ran at once
+	busy    bool
+}
+
+func (c *trackingCleaner) Name() string                             { return c.name }
+func (c *trackingCleaner) Domain() config.Domain                    { return config.DomainSystem }
+func (c *trackingCleaner) Detect(ctx context.Context) (bool, error) { return true, nil }
+
+func (c *trackingCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	return nil, nil
+}
+
+func (c *trackingCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	c.mu.Lock()
+	c.overlap = c.overlap || c.busy
+	c.busy = true
+	c.mu.Unlock()
+
+	n := c.running.Add(1)
+	for {
+		peak := c.peak.Load()
+		if n <= peak || c.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(c.delay)
+	c.running.Add(-1)
+
+	c.mu.Lock()
+	c.busy = false
+	c.mu.Unlock()
+
+	if targets[0].Path == "/fail" {
+		return nil, errors.New("boom")
+	}
+	return []CleanResult{{Target: targets[0], BytesFreed: targets[0].SizeBytes, Success: true}}, nil
+}
+
+func TestCleanConcurrently(t *testing.T) {
+	var running, peak atomic.Int32
+	cleaners := []*trackingCleaner{}
+	jobs := []CleanJob{}
+	for _, name := range []string{"A", "B", "C", "D"} {
+		c := &trackingCleaner{name: name, delay: 20 * time.Millisecond, running: &running, peak: &peak}
+		cleaners = append(cleaners, c)
+		for _, path := range []string{"/" + name + "1", "/" + name + "2"} {
+			jobs = append(jobs, CleanJob{Cleaner: c, Target: CleanTarget{Path: path, SizeBytes: 10}})
+		}
+	}
+	jobs = append(jobs, CleanJob{Cleaner: cleaners[0], Target: CleanTarget{Path: "/fail"}})
+
+	calls := 0
+	results := CleanConcurrently(context.Background(), jobs, 2, false, func(JobResult) { calls++ })
+
+	if len(results) != len(jobs) || calls != len(jobs) {
+		t.Fatalf("Expected %d results and calls, got %d and %d", len(jobs), len(results), calls)
+	}
+	for i, result := range results {
+		if result.Index != i || result.Result.Target.Path != jobs[i].Target.Path {
+			t.Errorf("Result %d is for job %d (%s)", i, result.Index, result.Result.Target.Path)
+		}
+	}
+	if last := results[len(results)-1].Result; last.Success || last.Error == nil {
+		t.Errorf("Expected the failing job's error, got %+v", last)
+	}
+
+	if peak.Load() != 2 {
+		t.Errorf("Expected 2 cleaners at once, got %d", peak.Load())
+	}
+	for _, c := range cleaners {
+		if c.overlap {
+			t.Errorf("Jobs of cleaner %s ran at once", c.name)
+		}
+	}
+}
+
+func TestCleanConcurrently_Cancelled(t *testing.T) {
+	var running, peak atomic.Int32
+	c := &trackingCleaner{name: "A", delay: 20 * time.Millisecond, running: &running, peak: &peak}
+	jobs := []CleanJob{
+		{Cleaner: c, Target: CleanTarget{Path: "/1"}},
+		{Cleaner: c, Target: CleanTarget{Path: "/2"}},
+		{Cleaner: c, Target: CleanTarget{Path: "/3"}},
+	}
+
+	// The deletion in flight when cancelling finishes, later ones don't start
+	ctx, cancel := context.WithCancel(context.Background())
+	results := CleanConcurrently(ctx, jobs, 4, false, func(JobResult) { cancel() })
+	if len(results) == 0 || len(results) == len(jobs) || results[0].Index != 0 {
+		t.Errorf("Expected the first jobs only, got %+v", results)
+	}
+
+	if results := CleanConcurrently(ctx, jobs, 4, false, nil); len(results) != 0 {
+		t.Errorf("Expected no job to run once cancelled, got %+v", results)
+	}
+}
+
+func TestCleanConcurrently_Paused(t *testing.T) {
+	var running, peak atomic.Int32
+	c := &trackingCleaner{name: "A", delay: 5 * time.Millisecond, running: &running, peak: &peak}
+	jobs := []CleanJob{
+		{Cleaner: c, Target: CleanTarget{Path: "/1"}},
+		{Cleaner: c, Target: CleanTarget{Path: "/2"}},
+	}
+
+	pause := &Pause{}
+	pause.Set(true)
+	done := make(chan []JobResult)
+	go func() {
+		done <- CleanConcurrently(WithPause(context.Background(), pause), jobs, 1, false, nil)
+	}()
+
+	select {
+	case results := <-done:
+		t.Fatalf("Expected no job to run while paused, got %+v", results)
+	case <-time.After(30 * time.Millisecond):
+	}
+	if !pause.Paused() {
+		t.Error("Expected Paused() while set")
+	}
+
+	pause.Set(false)
+	if results := <-done; len(results) != len(jobs) {
+		t.Errorf("Expected every job to run once resumed, got %+v", results)
+	}
+}
@@ -0,0 +1,128 @@
+package cleaner
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// aftermathKind is how the work left after cleaning a category adds up
+type aftermathKind int
+
+const (
+	perProject aftermathKind = iota // Done again in each project, e.g. npm install
+	download                        // Downloaded again as tools need it
+	once                            // Happens once, whatever the number of targets
+)
+
+// aftermath describes what cleaning a category leaves to do
+type aftermath struct {
+	kind aftermathKind
+	// Formatted with the number of projects (perProject) or the download
+	// size (download)
+	format string
+}
+
+// aftermaths holds what users will have to do again after cleaning, by
+// category. Categories whose removal goes unnoticed, such as logs and build
+// caches that fill again on their own, have none.
+var aftermaths = map[string]aftermath{
+	"node_modules":        {perProject, "reinstall node_modules in %d projects"},
+	"yarn_unplugged":      {perProject, "run yarn install in %d Yarn Berry projects"},
+	"php_vendor":          {perProject, "run composer install in %d projects"},
+	"cocoapods_pods":      {perProject, "run pod install in %d projects"},
+	"rust_target":         {perProject, "rebuild %d Rust projects from scratch"},
+	"tauri_target":        {perProject, "rebuild %d Tauri apps from scratch"},
+	"dart_tool":           {perProject, "run flutter pub get in %d projects"},
+	"terraform":           {perProject, "run terraform init in %d configurations"},
+	"tox":                 {perProject, "recreate the tox environments of %d projects"},
+	"go_mod_cache":        {download, "re-download ~%s of Go modules"},
+	"cargo_registry":      {download, "re-download ~%s of Rust crates"},
+	"maven_repository":    {download, "re-download ~%s of Maven libraries"},
+	"gradle_cache":        {download, "re-download ~%s of Gradle libraries"},
+	"nuget_packages":      {download, "re-download ~%s of NuGet packages"},
+	"mix_deps":            {perProject, "run mix deps.get in %d projects"},
+	"stack_programs":      {download, "re-download ~%s of GHC compilers"},
+	"renv_cache":          {download, "re-download ~%s of R packages to restore renv projects"},
+	"julia_packages":      {download, "re-download ~%s of Julia packages"},
+	"pipx_app":            {perProject, "reinstall %d pipx apps"},
+	"poetry_virtualenv":   {perProject, "run poetry install in %d projects"},
+	"android_ndk":         {download, "re-download ~%s of Android NDKs"},
+	"android_platform":    {download, "re-download ~%s of Android SDK platforms"},
+	"conan_packages":      {download, "re-download or rebuild ~%s of Conan packages"},
+	"cmake_build":         {perProject, "configure and rebuild %d CMake build directories"},
+	"npm_cache":           {download, "re-download ~%s of npm packages"},
+	"node_global_package": {perProject, "reinstall %d global Node.js packages"},
+	"yarn_cache":          {download, "re-download ~%s of Yarn packages"},
+	"yarn_berry_cache":    {download, "re-download ~%s of Yarn packages in projects"},
+	"pnpm_store":          {download, "re-download ~%s of pnpm packages"},
+	"composer_cache":      {download, "re-download ~%s of Composer packages"},
+	"cocoapods_cache":     {download, "re-download ~%s of pods"},
+	"homebrew_cache":      {download, "re-download ~%s of Homebrew packages to reinstall them"},
+	"huggingface_repos":   {download, "re-download ~%s of Hugging Face models"},
+	"ollama_models":       {download, "pull ~%s of Ollama models again"},
+	"torch_hub":           {download, "re-download ~%s of PyTorch models"},
+	"xcode_derived_data":  {once, "re-index Xcode projects on their next build"},
+	"xcode_module_cache":  {once, "wait for Xcode to rebuild its module cache"},
+	"ios_device_support":  {once, "wait for Xcode to copy device symbols when a device is connected"},
+	"simulator_devices":   {once, "recreate the deleted simulators, without their apps and data"},
+	"android_avds":        {once, "recreate the deleted Android emulators, without their apps and data"},
+}
+
+// Consequence is something users will have to do after cleaning a
+// category of targets
+type Consequence struct {
+	Category  string
+	Targets   int
+	SizeBytes int64
+	Summary   string // e.g. "reinstall node_modules in 7 projects"
+}
+
+// Consequences sums up what cleaning targets will leave to do, the largest
+// categories first
+func Consequences(targets []CleanTarget) []Consequence {
+	byCategory := make(map[string]*Consequence)
+	downloads := make(map[string]int64)
+	for _, target := range targets {
+		if _, ok := aftermaths[target.Category]; !ok {
+			continue
+		}
+		consequence, ok := byCategory[target.Category]
+		if !ok {
+			consequence = &Consequence{Category: target.Category}
+			byCategory[target.Category] = consequence
+		}
+		consequence.Targets++
+		consequence.SizeBytes += target.SizeBytes
+
+		// Packages are downloaded compressed when the rebuild cost says so
+		if cost, ok := target.RebuildCost(); ok && cost.Download > 0 {
+			downloads[target.Category] += cost.Download
+		} else {
+			downloads[target.Category] += target.SizeBytes
+		}
+	}
+
+	consequences := make([]Consequence, 0, len(byCategory))
+	for category, consequence := range byCategory {
+		a := aftermaths[category]
+		switch a.kind {
+		case perProject:
+			consequence.Summary = fmt.Sprintf(a.format, consequence.Targets)
+		case download:
+			consequence.Summary = fmt.Sprintf(a.format, utils.FormatBytes(downloads[category]))
+		default:
+			consequence.Summary = a.format
+		}
+		consequences = append(consequences, *consequence)
+	}
+
+	sort.Slice(consequences, func(i, j int) bool {
+		if consequences[i].SizeBytes != consequences[j].SizeBytes {
+			return consequences[i].SizeBytes > consequences[j].SizeBytes
+		}
+		return consequences[i].Category < consequences[j].Category
+	})
+	return consequences
+}
@@ -0,0 +1,49 @@
+package cleaner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConsequences(t *testing.T) {
+	targets := []CleanTarget{
+		{Path: "/a/node_modules", Category: "node_modules", SizeBytes: 100 * 1000 * 1000},
+		{Path: "/b/node_modules", Category: "node_modules", SizeBytes: 100 * 1000 * 1000},
+		{Path: "/go/pkg/mod", Category: "go_mod_cache", SizeBytes: 4 * 1000 * 1000 * 1000},
+		{Path: "/DerivedData/A", Category: "xcode_derived_data", SizeBytes: 10},
+		{Path: "/DerivedData/B", Category: "xcode_derived_data", SizeBytes: 10},
+		{Path: "/logs", Category: "system_logs", SizeBytes: 1000},
+	}
+
+	consequences := Consequences(targets)
+	if len(consequences) != 3 {
+		t.Fatalf("Expected 3 consequences, got %+v", consequences)
+	}
+
+	// Largest first; Go modules are downloaded compressed
+	expected := []string{
+		"re-download ~2.0 GB of Go modules",
+		"reinstall node_modules in 2 projects",
+		"re-index Xcode projects on their next build",
+	}
+	for i, summary := range expected {
+		if consequences[i].Summary != summary {
+			t.Errorf("Consequence %d = %q, want %q", i, consequences[i].Summary, summary)
+		}
+	}
+}
+
+func TestConsequences_None(t *testing.T) {
+	if consequences := Consequences([]CleanTarget{{Path: "/tmp/x", Category: "temp_files"}}); len(consequences) != 0 {
+		t.Errorf("Expected nothing to do after cleaning temp files, got %+v", consequences)
+	}
+}
+
+func TestAftermaths_Format(t *testing.T) {
+	for category, a := range aftermaths {
+		verbs := strings.Count(a.format, "%")
+		if (a.kind == once) != (verbs == 0) {
+			t.Errorf("Aftermath of %s has %d verbs for its kind: %q", category, verbs, a.format)
+		}
+	}
+}
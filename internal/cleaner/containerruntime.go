@@ -0,0 +1,240 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+	"github.com/dustin/go-humanize"
+)
+
+// containerRuntime is a Docker-compatible container CLI: Docker, Podman or
+// nerdctl (containerd). Its targets use pseudo-paths prefixed with its id,
+// e.g. "docker:images:dangling" or "nerdctl/k8s.io:containers:stopped".
+type containerRuntime struct {
+	name      string // "docker", "podman" or "nerdctl", also the category prefix
+	namespace string // containerd namespace, for nerdctl
+	runner    CommandRunner
+}
+
+// installedContainerRuntimes returns the container runtimes found in PATH.
+// nerdctl gets one runtime per containerd namespace.
+func installedContainerRuntimes(runner CommandRunner) []containerRuntime {
+	runtimes := []containerRuntime{}
+
+	for _, name := range []string{"docker", "podman"} {
+		if utils.CommandExists(name) {
+			runtimes = append(runtimes, containerRuntime{name: name, runner: runner})
+		}
+	}
+
+	if utils.CommandExists("nerdctl") {
+		output, err := runner.Output("nerdctl", "namespace", "ls", "-q")
+		if err == nil {
+			for _, namespace := range strings.Fields(string(output)) {
+				runtimes = append(runtimes, containerRuntime{name: "nerdctl", namespace: namespace, runner: runner})
+			}
+		}
+	}
+
+	return runtimes
+}
+
+// id returns the pseudo-path prefix of the runtime's targets
+func (r containerRuntime) id() string {
+	if r.namespace != "" {
+		return r.name + "/" + r.namespace
+	}
+	return r.name
+}
+
+// label returns the runtime name shown in target descriptions
+func (r containerRuntime) label() string {
+	label := strings.ToUpper(r.name[:1]) + r.name[1:]
+	if r.namespace != "" {
+		label += fmt.Sprintf(" (namespace %s)", r.namespace)
+	}
+	return label
+}
+
+// runtimeTarget splits a runtime target path into its runtime, running
+// commands with runner, and action
+func runtimeTarget(path string, runner CommandRunner) (containerRuntime, string, bool) {
+	id, action, ok := strings.Cut(path, ":")
+	if !ok {
+		return containerRuntime{}, "", false
+	}
+
+	switch {
+	case id == "docker" || id == "podman":
+		return containerRuntime{name: id, runner: runner}, action, true
+	case strings.HasPrefix(id, "nerdctl/"):
+		return containerRuntime{name: "nerdctl", namespace: strings.TrimPrefix(id, "nerdctl/"), runner: runner}, action, true
+	}
+	return containerRuntime{}, "", false
+}
+
+// args prepends the runtime's global arguments to args
+func (r containerRuntime) args(args ...string) []string {
+	if r.namespace != "" {
+		return append([]string{"--namespace", r.namespace}, args...)
+	}
+	return args
+}
+
+// output runs a runtime query and returns its output
+func (r containerRuntime) output(args ...string) ([]byte, error) {
+	return r.runner.Output(r.name, r.args(args...)...)
+}
+
+// run runs a runtime cleanup command
+func (r containerRuntime) run(args ...string) error {
+	return r.runner.Run(r.name, r.args(args...)...)
+}
+
+// countIDs runs a listing command printing one ID per line and counts them
+func (r containerRuntime) countIDs(args ...string) int {
+	output, err := r.output(args...)
+	if err != nil {
+		return 0
+	}
+	return len(strings.Fields(string(output)))
+}
+
+// danglingImagesSize estimates the size of dangling images
+func (r containerRuntime) danglingImagesSize() int64 {
+	// Estimate: ~100MB per dangling image (conservative)
+	return int64(r.countIDs("images", "-f", "dangling=true", "-q")) * 100 * 1024 * 1024
+}
+
+// stoppedContainersSize estimates the size of stopped containers
+func (r containerRuntime) stoppedContainersSize() int64 {
+	// Estimate: ~50MB per stopped container (conservative)
+	return int64(r.countIDs("ps", "-a", "-f", "status=exited", "-q")) * 50 * 1024 * 1024
+}
+
+// unusedVolumesSize estimates the size of volumes no container uses
+func (r containerRuntime) unusedVolumesSize() int64 {
+	// Estimate: ~200MB per volume (very conservative)
+	return int64(r.countIDs("volume", "ls", "-f", "dangling=true", "-q")) * 200 * 1024 * 1024
+}
+
+// buildCacheSize returns the reclaimable size of the Docker build cache
+func (r containerRuntime) buildCacheSize() int64 {
+	output, err := r.output("system", "df", "--format", "{{json .}}")
+	if err != nil {
+		return 0
+	}
+	return parseSystemDF(output)["Build Cache"]
+}
+
+// parseSystemDF reads the output of docker system df --format '{{json .}}',
+// one JSON object per line, and returns the reclaimable space by type
+// ("Images", "Containers", "Local Volumes", "Build Cache")
+func parseSystemDF(output []byte) map[string]int64 {
+	reclaimable := make(map[string]int64)
+
+	for _, line := range strings.Split(string(output), "\n") {
+		var row struct {
+			Type        string
+			Reclaimable string // e.g. "1.2GB (50%)"
+		}
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			continue
+		}
+		fields := strings.Fields(row.Reclaimable)
+		if len(fields) == 0 {
+			continue
+		}
+		if n, err := humanize.ParseBytes(fields[0]); err == nil {
+			reclaimable[row.Type] = int64(n)
+		}
+	}
+
+	return reclaimable
+}
+
+// reclaimableSize returns the space podman system df reports as reclaimable
+func (r containerRuntime) reclaimableSize() int64 {
+	output, err := r.output("system", "df", "--format", "{{.Reclaimable}}")
+	if err != nil {
+		return 0
+	}
+	// Lines look like "1.2GB (50%)"; the percentages don't parse and are skipped
+	return parseDockerSizes(string(output))
+}
+
+// podmanStorageDir returns the directory of Podman's rootless storage and
+// machines
+func podmanStorageDir(home string) string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "containers")
+	}
+	return filepath.Join(home, ".local", "share", "containers")
+}
+
+// scan returns the runtime's targets allowed by the configuration
+func (r containerRuntime) scan(cfg *config.Config, home string) []CleanTarget {
+	targets := []CleanTarget{}
+
+	add := func(action, category, description string, size int64, safety config.SafetyLevel) {
+		category = r.name + "_" + category
+		if size > 0 && cfg.Allows(config.DomainDevOps, category, safety) {
+			targets = append(targets, CleanTarget{
+				Path:        r.id() + ":" + action,
+				Category:    category,
+				Description: r.label() + " " + description,
+				SizeBytes:   size,
+				Safety:      safety,
+			})
+		}
+	}
+
+	// Dangling images and stopped containers (Moderate)
+	add("images:dangling", "dangling_images", "dangling images", r.danglingImagesSize(), config.Moderate)
+	add("containers:stopped", "stopped_containers", "stopped containers", r.stoppedContainersSize(), config.Moderate)
+
+	switch r.name {
+	case "docker":
+		// Build cache (Safe)
+		add("buildcache", "build_cache", "build cache", r.buildCacheSize(), config.Safe)
+	case "podman":
+		// podman system prune also drops unused networks and build cache (Moderate)
+		add("system", "system_prune", "reclaimable space (podman system prune)", r.reclaimableSize(), config.Moderate)
+	}
+
+	// Unused volumes (Dangerous - may contain data)
+	add("volumes:unused", "volumes", "unused volumes (DANGEROUS - may contain data)", r.unusedVolumesSize(), config.Dangerous)
+
+	if r.name == "podman" {
+		// All of Podman's storage and machines (Dangerous - podman system reset)
+		size, _ := utils.GetAllocatedSize(podmanStorageDir(home))
+		add("storage", "storage", "storage in ~/.local/share/containers (DANGEROUS - removes all images, containers and volumes)", size, config.Dangerous)
+	}
+
+	return targets
+}
+
+// clean runs the cleanup command of one of the runtime's targets
+func (r containerRuntime) clean(action string) error {
+	switch action {
+	case "images:dangling":
+		return r.run("image", "prune", "-f")
+	case "containers:stopped":
+		return r.run("container", "prune", "-f")
+	case "buildcache":
+		return r.run("builder", "prune", "-f")
+	case "volumes:unused":
+		return r.run("volume", "prune", "-f")
+	case "system":
+		return r.run("system", "prune", "-f")
+	case "storage":
+		return r.run("system", "reset", "--force")
+	}
+
+	return fmt.Errorf("unknown %s target %s", r.name, action)
+}
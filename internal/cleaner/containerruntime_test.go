@@ -0,0 +1,92 @@
+package cleaner
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRuntimeTarget(t *testing.T) {
+	tests := []struct {
+		path      string
+		ok        bool
+		name      string
+		namespace string
+		action    string
+	}{
+		{"docker:images:dangling", true, "docker", "", "images:dangling"},
+		{"podman:system", true, "podman", "", "system"},
+		{"nerdctl/k8s.io:containers:stopped", true, "nerdctl", "k8s.io", "containers:stopped"},
+		{"docker-desktop:reclaim", false, "", "", ""},
+		{"kind:images:dev", false, "", "", ""},
+		{"/home/me/.terraform", false, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		runtime, action, ok := runtimeTarget(tt.path, ExecRunner{})
+		if ok != tt.ok {
+			t.Errorf("runtimeTarget(%s) ok = %v, expected %v", tt.path, ok, tt.ok)
+			continue
+		}
+		if runtime.name != tt.name || runtime.namespace != tt.namespace || action != tt.action {
+			t.Errorf("runtimeTarget(%s) = %+v, %q", tt.path, runtime, action)
+		}
+		if ok && runtime.id()+":"+action != tt.path {
+			t.Errorf("Target path %s does not round-trip, got %s:%s", tt.path, runtime.id(), action)
+		}
+	}
+}
+
+func TestContainerRuntime_Clean(t *testing.T) {
+	runner := &RecordingRunner{}
+	nerdctl := containerRuntime{name: "nerdctl", namespace: "k8s.io", runner: runner}
+
+	if err := nerdctl.clean("images:dangling"); err != nil {
+		t.Fatalf("clean() error = %v", err)
+	}
+	if err := nerdctl.clean("unknown"); err == nil {
+		t.Error("Expected an error for an unknown action")
+	}
+
+	expected := []string{"nerdctl --namespace k8s.io image prune -f"}
+	if commands := runner.Commands(); !slices.Equal(commands, expected) {
+		t.Errorf("Commands() = %v, expected %v", commands, expected)
+	}
+	if label := nerdctl.label(); label != "Nerdctl (namespace k8s.io)" {
+		t.Errorf("label() = %q", label)
+	}
+	if label := (containerRuntime{name: "docker"}).label(); label != "Docker" {
+		t.Errorf("label() = %q, expected Docker", label)
+	}
+}
+
+func TestPodmanStorageDir(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "")
+	if dir := podmanStorageDir("/home/me"); dir != filepath.Join("/home/me", ".local", "share", "containers") {
+		t.Errorf("podmanStorageDir() = %s", dir)
+	}
+
+	t.Setenv("XDG_DATA_HOME", "/data")
+	if dir := podmanStorageDir("/home/me"); dir != filepath.Join("/data", "containers") {
+		t.Errorf("podmanStorageDir() with XDG_DATA_HOME = %s", dir)
+	}
+}
+
+func TestParseSystemDF(t *testing.T) {
+	output := []byte(`{"Active":"3","Reclaimable":"1.2GB (40%)","Size":"3GB","TotalCount":"8","Type":"Images"}
+{"Active":"0","Reclaimable":"0B","Size":"0B","TotalCount":"0","Type":"Containers"}
+{"Active":"0","Reclaimable":"512.5MB","Size":"2.1GB","TotalCount":"42","Type":"Build Cache"}
+WARNING: not json
+`)
+
+	reclaimable := parseSystemDF(output)
+	if got := reclaimable["Build Cache"]; got != 512_500_000 {
+		t.Errorf("Build Cache reclaimable = %d, expected 512500000", got)
+	}
+	if got := reclaimable["Images"]; got != 1_200_000_000 {
+		t.Errorf("Images reclaimable = %d, expected 1200000000", got)
+	}
+	if got := reclaimable["Containers"]; got != 0 {
+		t.Errorf("Containers reclaimable = %d, expected 0", got)
+	}
+}
@@ -2,17 +2,18 @@ package cleaner
 
 import (
 	"context"
-	"os"
 	"path/filepath"
 
 	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
 	"github.com/0SansNom/epurer/internal/scanner"
 	"github.com/0SansNom/epurer/pkg/utils"
 )
 
-// DataMLCleaner handles Data Science and ML cleanup (Conda, Jupyter, TensorFlow, PyTorch, etc.)
+// DataMLCleaner handles Data Science and ML cleanup (Conda, Jupyter, TensorFlow, PyTorch, R, Julia, etc.)
 type DataMLCleaner struct {
 	scanner *scanner.Scanner
+	runner  CommandRunner // Asks julia for its version, ExecRunner if nil
 }
 
 // NewDataMLCleaner creates a new DataMLCleaner
@@ -27,23 +28,36 @@ func NewDataMLCleaner() (Cleaner, error) {
 	}, nil
 }
 
+// SetRunner replaces the runner of the cleaner's external commands
+func (d *DataMLCleaner) SetRunner(runner CommandRunner) {
+	d.runner = runner
+}
+
+// commands returns the runner of the cleaner's external commands
+func (d *DataMLCleaner) commands() CommandRunner {
+	if d.runner == nil {
+		return ExecRunner{}
+	}
+	return d.runner
+}
+
 func (d *DataMLCleaner) Name() string {
 	return "Data/ML"
 }
 
 func (d *DataMLCleaner) Domain() config.Domain {
-	return config.DomainFrontend // TODO: Add DomainDataML to config
+	return config.DomainDataML
 }
 
 func (d *DataMLCleaner) Detect(ctx context.Context) (bool, error) {
-	return utils.CommandExists("conda") ||
-		utils.CommandExists("jupyter") ||
-		utils.CommandExists("python3"), nil
+	return detector.FromContext(ctx).HasCommand("conda", "jupyter", "python3", "R", "julia"), nil
 }
 
 func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	configureScanner(d.scanner, cfg)
+
 	targets := []CleanTarget{}
-	home, err := os.UserHomeDir()
+	home, err := cfg.HomeDir()
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +71,7 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        condaPkgsPath,
+				Category:    "conda_pkgs",
 				Description: "Conda package cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -71,6 +86,7 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        condaEnvsTarPath,
+				Category:    "conda_env_tarballs",
 				Description: "Conda environments tarball cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -85,6 +101,7 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        miniforgeCache,
+				Category:    "mamba_pkgs",
 				Description: "Mamba/Miniforge package cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -101,6 +118,7 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        jupyterRuntimePath,
+				Category:    "jupyter_runtime",
 				Description: "Jupyter runtime files",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -116,6 +134,7 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 100*1024*1024 { // > 100MB
 			targets = append(targets, CleanTarget{
 				Path:        jupyterKernelsPath,
+				Category:    "jupyter_kernels",
 				Description: "Jupyter kernels cache",
 				SizeBytes:   size,
 				Safety:      config.Moderate,
@@ -123,10 +142,6 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		}
 	}
 
-	// .ipynb_checkpoints (Safe - automatically created)
-	checkpointTargets := d.scanPattern(ctx, ".ipynb_checkpoints")
-	targets = append(targets, checkpointTargets...)
-
 	// === TensorFlow ===
 
 	// TensorFlow cache
@@ -138,6 +153,7 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        datasetsPath,
+					Category:    "keras_datasets",
 					Description: "Keras/TensorFlow datasets cache",
 					SizeBytes:   size,
 					Safety:      config.Safe,
@@ -151,6 +167,7 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        modelsPath,
+					Category:    "keras_models",
 					Description: "Keras/TensorFlow models cache",
 					SizeBytes:   size,
 					Safety:      config.Safe,
@@ -168,6 +185,7 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        torchHubPath,
+				Category:    "torch_hub",
 				Description: "PyTorch Hub cache (pretrained models)",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -177,20 +195,19 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 
 	// === Hugging Face ===
 
-	// Hugging Face transformers cache
+	// Hugging Face cache, one target per model not used recently
 	hfCachePath := filepath.Join(home, ".cache", "huggingface")
 	if utils.PathExists(hfCachePath) {
-		size, _ := utils.GetDirSize(hfCachePath)
-		if size > 0 {
-			targets = append(targets, CleanTarget{
-				Path:        hfCachePath,
-				Description: "Hugging Face transformers cache",
-				SizeBytes:   size,
-				Safety:      config.Safe,
-			})
-		}
+		hfTargets := scanHuggingFaceCache(hfCachePath, cfg.ModelMaxAgeDays)
+		targets = append(targets, hfTargets...)
 	}
 
+	// === Datasets ===
+
+	// Kaggle, torchvision, scikit-learn, spaCy and NLTK downloads (Safe - re-downloaded on demand)
+	datasetTargets := scanDatasetCaches(datasetCaches(home), cfg.DatasetMaxAgeDays)
+	targets = append(targets, datasetTargets...)
+
 	// === Weights & Biases ===
 
 	// W&B cache
@@ -200,6 +217,7 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        wandbCachePath,
+				Category:    "wandb_cache",
 				Description: "Weights & Biases cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -207,110 +225,51 @@ func (d *DataMLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		}
 	}
 
-	// wandb local logs (Moderate - may contain experiment data)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
-		wandbTargets := d.scanPattern(ctx, "wandb")
-		for _, target := range wandbTargets {
-			// Only include if it's a wandb directory with run logs
-			if utils.PathExists(filepath.Join(target.Path, "run-*")) {
-				target.Safety = config.Moderate
-				target.Description = "W&B experiment logs"
-				targets = append(targets, target)
-			}
-		}
-	}
-
-	// === MLflow ===
+	// === R ===
 
-	// MLflow artifacts (Moderate - experiment data)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
-		mlflowTargets := d.scanPattern(ctx, "mlruns")
-		targets = append(targets, mlflowTargets...)
+	// Package libraries of R versions no longer installed (Moderate - their
+	// packages would be installed again with that version)
+	if cfg.Allows(config.DomainDataML, "r_stale_library", config.Moderate) {
+		targets = append(targets, rLibraryTargets(home, rInstalled())...)
 	}
 
-	// === General Data Science ===
-
-	// .DS_Store files (Safe)
-	dsStoreTargets := d.scanPattern(ctx, ".DS_Store")
-	targets = append(targets, dsStoreTargets...)
-
-	return targets, nil
-}
-
-func (d *DataMLCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
-	results := make([]CleanResult, 0, len(targets))
-
-	for _, target := range targets {
-		result := CleanResult{
-			Target:  target,
-			Success: true,
-		}
-
-		if !dryRun {
-			err := utils.SafeRemove(target.Path, false)
-			if err != nil {
-				result.Success = false
-				result.Error = err
-			} else {
-				result.BytesFreed = target.SizeBytes
-			}
-		} else {
-			result.BytesFreed = target.SizeBytes
-		}
-
-		results = append(results, result)
-
-		select {
-		case <-ctx.Done():
-			return results, ctx.Err()
-		default:
-		}
+	// renv cache (Moderate - renv projects link their libraries into it)
+	renvPath := renvCache(home)
+	if size, _ := utils.GetDirSize(renvPath); size > 0 && cfg.Allows(config.DomainDataML, "renv_cache", config.Moderate) {
+		targets = append(targets, CleanTarget{
+			Path:        renvPath,
+			Category:    "renv_cache",
+			Description: "renv package cache",
+			SizeBytes:   size,
+			Safety:      config.Moderate,
+		})
 	}
 
-	return results, nil
-}
-
-// scanPattern scans for a specific pattern
-func (d *DataMLCleaner) scanPattern(ctx context.Context, pattern string) []CleanTarget {
-	targets := []CleanTarget{}
+	// === Julia ===
 
-	resultChan := d.scanner.FindByPattern(ctx, pattern)
-	for result := range resultChan {
-		if result.Err != nil {
-			continue
+	// Depot packages and artifacts (Moderate), precompiled caches of Julia
+	// versions no longer installed (Safe)
+	depot := juliaDepot(home)
+	for _, target := range juliaDepotTargets(depot, juliaInstalled(depot, d.commands())) {
+		if cfg.Allows(config.DomainDataML, target.Category, target.Safety) {
+			targets = append(targets, target)
 		}
+	}
 
-		desc := d.getDescriptionForPattern(pattern)
-		safety := config.Safe
-
-		// Special handling for certain patterns
-		if pattern == "wandb" || pattern == "mlruns" {
-			safety = config.Moderate
-		}
+	// === Project folders ===
 
-		targets = append(targets, CleanTarget{
-			Path:        result.Path,
-			Description: desc,
-			SizeBytes:   result.Size,
-			Safety:      safety,
-		})
-	}
+	// .ipynb_checkpoints, .DS_Store (Safe), wandb run logs and MLflow
+	// runs (Moderate - experiment data)
+	projectTargets := scanProjectPatterns(ctx, d.scanner, cfg, config.DomainDataML)
+	targets = append(targets, projectTargets...)
 
-	return targets
+	return targets, nil
 }
 
-// getDescriptionForPattern returns a human-readable description
-func (d *DataMLCleaner) getDescriptionForPattern(pattern string) string {
-	descriptions := map[string]string{
-		".ipynb_checkpoints": "Jupyter notebook checkpoints",
-		".DS_Store":          "macOS metadata files",
-		"wandb":              "W&B experiment logs",
-		"mlruns":             "MLflow experiment runs",
-	}
-
-	if desc, ok := descriptions[pattern]; ok {
-		return desc
-	}
+func (d *DataMLCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanTargets(ctx, targets, dryRun)
+}
 
-	return pattern
+func (d *DataMLCleaner) Patterns() []string {
+	return projectPatternNames(config.DomainDataML)
 }
@@ -0,0 +1,104 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// datasetCache is a directory where a library downloads datasets or models,
+// one entry per dataset
+type datasetCache struct {
+	path        string
+	description string
+	keep        map[string]bool // Entries that are not datasets (e.g. credentials)
+}
+
+// datasetCaches returns the dataset download directories of common data
+// science libraries
+func datasetCaches(home string) []datasetCache {
+	sklearnHome := os.Getenv("SCIKIT_LEARN_DATA")
+	if sklearnHome == "" {
+		sklearnHome = filepath.Join(home, "scikit_learn_data")
+	}
+
+	nltkHome := os.Getenv("NLTK_DATA")
+	if nltkHome == "" {
+		nltkHome = filepath.Join(home, "nltk_data")
+	}
+
+	return []datasetCache{
+		{path: filepath.Join(home, ".kaggle"), description: "Kaggle downloads", keep: map[string]bool{"kaggle.json": true, "access_token": true}},
+		{path: filepath.Join(home, ".cache", "kagglehub"), description: "KaggleHub cache"},
+		{path: filepath.Join(home, ".cache", "torch", "datasets"), description: "torchvision datasets"},
+		{path: sklearnHome, description: "scikit-learn datasets"},
+		{path: filepath.Join(home, ".cache", "spacy"), description: "spaCy models"},
+		{path: nltkHome, description: "NLTK data"},
+	}
+}
+
+// scanDatasetCaches returns one target per dataset cache, holding only the
+// datasets not used within maxAgeDays so the ones in active use stay put
+func scanDatasetCaches(caches []datasetCache, maxAgeDays int) []CleanTarget {
+	targets := []CleanTarget{}
+	cutoff := time.Now().AddDate(0, 0, -maxAgeDays)
+
+	for _, cache := range caches {
+		entries, err := os.ReadDir(cache.path)
+		if err != nil {
+			continue
+		}
+
+		stale := []string{}
+		for _, entry := range entries {
+			if cache.keep[entry.Name()] {
+				continue
+			}
+
+			path := filepath.Join(cache.path, entry.Name())
+			if maxAgeDays > 0 && !lastUsedTime(path).Before(cutoff) {
+				continue
+			}
+			stale = append(stale, path)
+		}
+
+		description := cache.description
+		if maxAgeDays > 0 {
+			description = fmt.Sprintf("%s unused for %d+ days", cache.description, maxAgeDays)
+		}
+
+		if target, ok := entriesTarget(cache.path, stale, categoryName(cache.description), description, config.Safe); ok {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// lastUsedTime returns the most recent access or modification time of any
+// file under path
+func lastUsedTime(path string) time.Time {
+	var lastUsed time.Time
+
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.ModTime().After(lastUsed) {
+			lastUsed = info.ModTime()
+		}
+		if !info.IsDir() {
+			if accessed := utils.AccessTime(info); accessed.After(lastUsed) {
+				lastUsed = accessed
+			}
+		}
+		return nil
+	})
+
+	return lastUsed
+}
@@ -0,0 +1,52 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScanDatasetCaches(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	kaggle := filepath.Join(tmpDir, ".kaggle")
+	createTestFile(t, kaggle, "kaggle.json", `{"key": "secret"}`)
+	createTestFile(t, kaggle, "titanic/train.csv", "old rows")
+	createTestFile(t, kaggle, "mnist/train.csv", "recent rows")
+
+	nltk := filepath.Join(tmpDir, "nltk_data")
+	createTestFile(t, nltk, "corpora/stopwords/english", "recent")
+
+	old := time.Now().AddDate(0, 0, -60)
+	setOldTimes(t, filepath.Join(kaggle, "titanic"), old)
+	setOldTimes(t, filepath.Join(kaggle, "kaggle.json"), old)
+
+	caches := []datasetCache{
+		{path: kaggle, description: "Kaggle downloads", keep: map[string]bool{"kaggle.json": true}},
+		{path: nltk, description: "NLTK data"},
+		{path: filepath.Join(tmpDir, "missing"), description: "Missing"},
+	}
+
+	targets := scanDatasetCaches(caches, 30)
+	if len(targets) != 1 {
+		t.Fatalf("Expected only the Kaggle target, got %v", targets)
+	}
+	if len(targets[0].Entries) != 1 || targets[0].Entries[0] != filepath.Join(kaggle, "titanic") {
+		t.Errorf("Expected only the stale dataset, got %v", targets[0].Entries)
+	}
+
+	// Without an age filter everything but the credentials is listed
+	all := scanDatasetCaches(caches, 0)
+	if len(all) != 2 {
+		t.Fatalf("Expected 2 targets without age filter, got %d", len(all))
+	}
+	for _, target := range all {
+		for _, entry := range target.Entries {
+			if filepath.Base(entry) == "kaggle.json" {
+				t.Error("Kaggle credentials must never be removed")
+			}
+		}
+	}
+}
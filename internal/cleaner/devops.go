@@ -3,18 +3,19 @@ package cleaner
 import (
 	"context"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
 
 	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
 	"github.com/0SansNom/epurer/internal/scanner"
 	"github.com/0SansNom/epurer/pkg/utils"
 )
 
-// DevOpsCleaner handles DevOps cleanup (Docker, Kubernetes, Terraform, Cloud CLIs)
+// DevOpsCleaner handles DevOps cleanup (Docker, Podman, containerd, Kubernetes,
+// local clusters and VMs, Terraform, Cloud CLIs)
 type DevOpsCleaner struct {
 	scanner *scanner.Scanner
+	runner  CommandRunner // Runs docker, kind, colima, ... commands
 }
 
 // NewDevOpsCleaner creates a new DevOpsCleaner
@@ -26,83 +27,42 @@ func NewDevOpsCleaner() (Cleaner, error) {
 
 	return &DevOpsCleaner{
 		scanner: s,
+		runner:  ExecRunner{},
 	}, nil
 }
 
+// SetRunner replaces the runner of the cleaner's external commands
+func (d *DevOpsCleaner) SetRunner(runner CommandRunner) {
+	d.runner = runner
+}
+
 func (d *DevOpsCleaner) Name() string {
 	return "DevOps"
 }
 
 func (d *DevOpsCleaner) Domain() config.Domain {
-	return config.DomainFrontend // TODO: Add DomainDevOps to config
+	return config.DomainDevOps
 }
 
 func (d *DevOpsCleaner) Detect(ctx context.Context) (bool, error) {
-	return utils.CommandExists("docker") ||
-		utils.CommandExists("kubectl") ||
-		utils.CommandExists("terraform") ||
-		utils.CommandExists("helm"), nil
+	return detector.FromContext(ctx).HasCommand("docker", "podman", "nerdctl", "kubectl", "terraform", "helm", "kind", "k3d", "colima", "limactl"), nil
 }
 
 func (d *DevOpsCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	configureScanner(d.scanner, cfg)
+
 	targets := []CleanTarget{}
-	home, err := os.UserHomeDir()
+	home, err := cfg.HomeDir()
 	if err != nil {
 		return nil, err
 	}
 
-	// === Docker ===
-
-	if utils.CommandExists("docker") {
-		// Dangling images (Moderate)
-		if cfg.CleanLevel.AllowsSafety(config.Moderate) {
-			danglingSize := d.getDockerDanglingSize()
-			if danglingSize > 0 {
-				targets = append(targets, CleanTarget{
-					Path:        "docker:images:dangling",
-					Description: "Docker dangling images",
-					SizeBytes:   danglingSize,
-					Safety:      config.Moderate,
-				})
-			}
-		}
-
-		// Stopped containers (Moderate)
-		if cfg.CleanLevel.AllowsSafety(config.Moderate) {
-			stoppedSize := d.getDockerStoppedContainersSize()
-			if stoppedSize > 0 {
-				targets = append(targets, CleanTarget{
-					Path:        "docker:containers:stopped",
-					Description: "Docker stopped containers",
-					SizeBytes:   stoppedSize,
-					Safety:      config.Moderate,
-				})
-			}
-		}
-
-		// Build cache (Safe)
-		buildCacheSize := d.getDockerBuildCacheSize()
-		if buildCacheSize > 0 {
-			targets = append(targets, CleanTarget{
-				Path:        "docker:buildcache",
-				Description: "Docker build cache",
-				SizeBytes:   buildCacheSize,
-				Safety:      config.Safe,
-			})
-		}
+	// === Containers ===
 
-		// Unused volumes (Dangerous - may contain data)
-		if cfg.CleanLevel.AllowsSafety(config.Dangerous) {
-			volumeSize := d.getDockerUnusedVolumesSize()
-			if volumeSize > 0 {
-				targets = append(targets, CleanTarget{
-					Path:        "docker:volumes:unused",
-					Description: "Docker unused volumes (DANGEROUS - may contain data)",
-					SizeBytes:   volumeSize,
-					Safety:      config.Dangerous,
-				})
-			}
-		}
+	// Docker, Podman and nerdctl (per containerd namespace), whichever are
+	// installed
+	for _, runtime := range installedContainerRuntimes(d.runner) {
+		targets = append(targets, runtime.scan(cfg, home)...)
 	}
 
 	// === Kubernetes ===
@@ -114,6 +74,7 @@ func (d *DevOpsCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        kubeCachePath,
+				Category:    "kube_cache",
 				Description: "Kubernetes cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -122,13 +83,14 @@ func (d *DevOpsCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 	}
 
 	// Minikube (Moderate - can be recreated)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
+	if cfg.Allows(config.DomainDevOps, "minikube", config.Moderate) {
 		minikubePath := filepath.Join(home, ".minikube")
 		if utils.PathExists(minikubePath) {
 			size, _ := utils.GetDirSize(minikubePath)
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        minikubePath,
+					Category:    "minikube",
 					Description: "Minikube cache and VMs",
 					SizeBytes:   size,
 					Safety:      config.Moderate,
@@ -137,10 +99,16 @@ func (d *DevOpsCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		}
 	}
 
+	// === Local clusters and VMs ===
+
+	// kind/k3d cluster images, Lima/Colima VM disks and Docker Desktop's
+	// disk image, cleaned with their own tools
+	targets = append(targets, scanLocalClusters(cfg, home, d.runner)...)
+
 	// === Terraform ===
 
 	// .terraform folders (Moderate - providers and modules)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
+	if cfg.Allows(config.DomainDevOps, "terraform", config.Moderate) {
 		terraformTargets := d.scanTerraform(ctx)
 		targets = append(targets, terraformTargets...)
 	}
@@ -154,6 +122,7 @@ func (d *DevOpsCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        awsCachePath,
+				Category:    "aws_cli_cache",
 				Description: "AWS CLI cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -168,6 +137,7 @@ func (d *DevOpsCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        helmCachePath,
+				Category:    "helm_cache",
 				Description: "Helm cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -178,13 +148,16 @@ func (d *DevOpsCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 	// === Vagrant ===
 
 	// Vagrant boxes (Moderate - can be large VMs)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
+	if cfg.Allows(config.DomainDevOps, "vagrant_boxes", config.Moderate) {
 		vagrantBoxesPath := filepath.Join(home, ".vagrant.d", "boxes")
-		if utils.PathExists(vagrantBoxesPath) {
+		if keep, ok := cfg.Retention(config.DomainDevOps, "vagrant_boxes"); ok {
+			targets = append(targets, retainedVagrantBoxes(vagrantBoxesPath, keep)...)
+		} else if utils.PathExists(vagrantBoxesPath) {
 			size, _ := utils.GetDirSize(vagrantBoxesPath)
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        vagrantBoxesPath,
+					Category:    "vagrant_boxes",
 					Description: "Vagrant boxes",
 					SizeBytes:   size,
 					Safety:      config.Moderate,
@@ -199,35 +172,47 @@ func (d *DevOpsCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 func (d *DevOpsCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
 	results := make([]CleanResult, 0, len(targets))
 
+	// Dry runs record the cleanup commands instead of running them
+	runner := d.runner
+	recorder := &RecordingRunner{Runner: d.runner}
+	if dryRun {
+		runner = recorder
+	}
+
 	for _, target := range targets {
 		result := CleanResult{
 			Target:  target,
 			Success: true,
 		}
 
-		// Docker commands are special
-		if strings.HasPrefix(target.Path, "docker:") {
-			err := d.cleanDockerTarget(target.Path, dryRun)
-			if err != nil {
+		// Container runtime commands are special
+		if runtime, action, ok := runtimeTarget(target.Path, runner); ok {
+			if err := runtime.clean(action); err != nil {
 				result.Success = false
 				result.Error = err
 			} else {
 				result.BytesFreed = target.SizeBytes
 			}
+		} else if isLocalClusterTarget(target.Path) {
+			home, _ := os.UserHomeDir()
+			freed, err := cleanLocalCluster(target, home, runner)
+			result.BytesFreed = freed
+			if dryRun {
+				result.BytesFreed = target.SizeBytes
+			}
+			if err != nil {
+				result.Success = false
+				result.Error = err
+			}
 		} else {
 			// Regular file/directory removal
 			if !dryRun {
-				err := utils.SafeRemove(target.Path, false)
-				if err != nil {
-					result.Success = false
-					result.Error = err
-				} else {
-					result.BytesFreed = target.SizeBytes
-				}
+				result = removeMeasured(ctx, target)
 			} else {
 				result.BytesFreed = target.SizeBytes
 			}
 		}
+		result.Commands = recorder.Commands()
 
 		results = append(results, result)
 
@@ -241,95 +226,8 @@ func (d *DevOpsCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun
 	return results, nil
 }
 
-// Docker helper methods
-
-func (d *DevOpsCleaner) getDockerDanglingSize() int64 {
-	cmd := exec.Command("docker", "images", "-f", "dangling=true", "-q")
-	output, err := cmd.Output()
-	if err != nil {
-		return 0
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 0 || (len(lines) == 1 && lines[0] == "") {
-		return 0
-	}
-
-	// Estimate: ~100MB per dangling image (conservative)
-	return int64(len(lines)) * 100 * 1024 * 1024
-}
-
-func (d *DevOpsCleaner) getDockerStoppedContainersSize() int64 {
-	cmd := exec.Command("docker", "ps", "-a", "-f", "status=exited", "-q")
-	output, err := cmd.Output()
-	if err != nil {
-		return 0
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 0 || (len(lines) == 1 && lines[0] == "") {
-		return 0
-	}
-
-	// Estimate: ~50MB per stopped container (conservative)
-	return int64(len(lines)) * 50 * 1024 * 1024
-}
-
-func (d *DevOpsCleaner) getDockerBuildCacheSize() int64 {
-	cmd := exec.Command("docker", "system", "df", "-v")
-	output, err := cmd.Output()
-	if err != nil {
-		return 0
-	}
-
-	// Parse output to find build cache size
-	// For now, return a conservative estimate
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "Build Cache") {
-			// Try to parse the size
-			// Format: "Build Cache   X   Y   Z"
-			// This is a simplified approach
-			return 1024 * 1024 * 1024 // 1GB estimate
-		}
-	}
-
-	return 0
-}
-
-func (d *DevOpsCleaner) getDockerUnusedVolumesSize() int64 {
-	cmd := exec.Command("docker", "volume", "ls", "-f", "dangling=true", "-q")
-	output, err := cmd.Output()
-	if err != nil {
-		return 0
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 0 || (len(lines) == 1 && lines[0] == "") {
-		return 0
-	}
-
-	// Estimate: ~200MB per volume (very conservative)
-	return int64(len(lines)) * 200 * 1024 * 1024
-}
-
-func (d *DevOpsCleaner) cleanDockerTarget(target string, dryRun bool) error {
-	if dryRun {
-		return nil
-	}
-
-	switch target {
-	case "docker:images:dangling":
-		return exec.Command("docker", "image", "prune", "-f").Run()
-	case "docker:containers:stopped":
-		return exec.Command("docker", "container", "prune", "-f").Run()
-	case "docker:buildcache":
-		return exec.Command("docker", "builder", "prune", "-f").Run()
-	case "docker:volumes:unused":
-		return exec.Command("docker", "volume", "prune", "-f").Run()
-	}
-
-	return nil
+func (d *DevOpsCleaner) Patterns() []string {
+	return []string{".terraform"}
 }
 
 // scanTerraform scans for .terraform folders
@@ -344,8 +242,11 @@ func (d *DevOpsCleaner) scanTerraform(ctx context.Context) []CleanTarget {
 
 		targets = append(targets, CleanTarget{
 			Path:        result.Path,
+			Category:    "terraform",
 			Description: "Terraform providers and modules",
 			SizeBytes:   result.Size,
+			Files:       result.Files,
+			Dirs:        result.Dirs,
 			Safety:      config.Moderate,
 		})
 	}
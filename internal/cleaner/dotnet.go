@@ -0,0 +1,197 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// DotNetCleaner handles .NET development cleanup (NuGet, dotnet SDK, bin/obj)
+type DotNetCleaner struct {
+	scanner *scanner.Scanner
+}
+
+// dotnetProjectPatterns are the files that mark a .NET project or solution
+var dotnetProjectPatterns = []string{"*.csproj", "*.fsproj", "*.vbproj", "*.sln"}
+
+// NewDotNetCleaner creates a new DotNetCleaner
+func NewDotNetCleaner() (Cleaner, error) {
+	s, err := scanner.NewScanner()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DotNetCleaner{
+		scanner: s,
+	}, nil
+}
+
+func (d *DotNetCleaner) Name() string {
+	return ".NET"
+}
+
+func (d *DotNetCleaner) Domain() config.Domain {
+	return config.DomainBackend
+}
+
+func (d *DotNetCleaner) Detect(ctx context.Context) (bool, error) {
+	det := detector.FromContext(ctx)
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, err
+	}
+
+	return det.HasCommand("dotnet") ||
+		det.HasPath(filepath.Join(home, ".nuget")), nil
+}
+
+func (d *DotNetCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	configureScanner(d.scanner, cfg)
+
+	targets := []CleanTarget{}
+	home, err := cfg.HomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// === NuGet ===
+
+	// Global packages folder (Moderate - restored on next build, but can take a while)
+	if cfg.Allows(config.DomainBackend, "nuget_packages", config.Moderate) {
+		nugetPackagesPath := filepath.Join(home, ".nuget", "packages")
+		if utils.PathExists(nugetPackagesPath) {
+			size, _ := utils.GetDirSize(nugetPackagesPath)
+			if size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        nugetPackagesPath,
+					Category:    "nuget_packages",
+					Description: "NuGet global packages",
+					SizeBytes:   size,
+					Safety:      config.Moderate,
+				})
+			}
+		}
+	}
+
+	// HTTP and plugin caches (Safe)
+	nugetCaches := map[string]string{
+		filepath.Join(home, ".local", "share", "NuGet", "http-cache"):    "NuGet HTTP cache",
+		filepath.Join(home, ".local", "share", "NuGet", "v3-cache"):      "NuGet v3 cache",
+		filepath.Join(home, ".local", "share", "NuGet", "plugins-cache"): "NuGet plugins cache",
+		filepath.Join(os.TempDir(), "NuGetScratch"):                      "NuGet scratch files",
+	}
+
+	for path, description := range nugetCaches {
+		if utils.PathExists(path) {
+			size, _ := utils.GetDirSize(path)
+			if size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        path,
+					Category:    categoryName(description),
+					Description: description,
+					SizeBytes:   size,
+					Safety:      config.Safe,
+				})
+			}
+		}
+	}
+
+	// === dotnet SDK ===
+
+	// Workload manifests, tool resolver cache and telemetry (Safe - recreated on demand)
+	dotnetCaches := map[string]string{
+		filepath.Join(home, ".dotnet", "sdk-advertising"):         "dotnet workload manifest cache",
+		filepath.Join(home, ".dotnet", "toolResolverCache"):       "dotnet tool resolver cache",
+		filepath.Join(home, ".dotnet", "TelemetryStorageService"): "dotnet telemetry data",
+	}
+
+	for path, description := range dotnetCaches {
+		if utils.PathExists(path) {
+			size, _ := utils.GetDirSize(path)
+			if size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        path,
+					Category:    categoryName(description),
+					Description: description,
+					SizeBytes:   size,
+					Safety:      config.Safe,
+				})
+			}
+		}
+	}
+
+	// === Projects ===
+
+	// bin/obj build output (Safe - rebuilt by dotnet build)
+	projectTargets := d.scanBuildOutput(ctx)
+	targets = append(targets, projectTargets...)
+
+	return targets, nil
+}
+
+func (d *DotNetCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanTargets(ctx, targets, dryRun)
+}
+
+func (d *DotNetCleaner) Patterns() []string {
+	return []string{"obj"}
+}
+
+// scanBuildOutput finds bin and obj folders next to .NET project files
+func (d *DotNetCleaner) scanBuildOutput(ctx context.Context) []CleanTarget {
+	targets := []CleanTarget{}
+
+	resultChan := d.scanner.FindByPattern(ctx, "obj")
+	for result := range resultChan {
+		if result.Err != nil {
+			continue
+		}
+
+		projectDir := filepath.Dir(result.Path)
+		if !isDotNetProject(projectDir) {
+			continue
+		}
+
+		targets = append(targets, CleanTarget{
+			Path:        result.Path,
+			Category:    "dotnet_obj",
+			Description: ".NET intermediate output (obj)",
+			SizeBytes:   result.Size,
+			Files:       result.Files,
+			Dirs:        result.Dirs,
+			Safety:      config.Safe,
+		})
+
+		binPath := filepath.Join(projectDir, "bin")
+		if utils.PathExists(binPath) {
+			size, _ := utils.GetDirSize(binPath)
+			if size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        binPath,
+					Category:    "dotnet_bin",
+					Description: ".NET build output (bin)",
+					SizeBytes:   size,
+					Safety:      config.Safe,
+				})
+			}
+		}
+	}
+
+	return targets
+}
+
+// isDotNetProject reports whether a directory contains a .NET project or
+// solution file
+func isDotNetProject(dir string) bool {
+	for _, pattern := range dotnetProjectPatterns {
+		if matches, _ := filepath.Glob(filepath.Join(dir, pattern)); len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
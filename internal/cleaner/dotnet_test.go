@@ -0,0 +1,62 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+func TestDotNetCleaner_ScanBuildOutput(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	app := filepath.Join(tmpDir, "App")
+	createTestFile(t, app, "App.csproj", "<Project />")
+	createTestFile(t, app, "obj/project.assets.json", "{}")
+	createTestFile(t, app, "bin/Debug/net8.0/App.dll", "dll")
+
+	lib := filepath.Join(tmpDir, "Lib")
+	createTestFile(t, lib, "Lib.fsproj", "<Project />")
+	createTestFile(t, lib, "obj/Lib.fsproj.nuget.g.props", "props")
+
+	// obj folders outside .NET projects are left alone
+	other := filepath.Join(tmpDir, "blender")
+	createTestFile(t, other, "obj/model.obj", "v 0 0 0")
+	createTestFile(t, other, "bin/tool", "binary")
+
+	s, _ := scanner.NewScannerWithDirs([]string{tmpDir})
+	d := &DotNetCleaner{scanner: s}
+	targets := d.scanBuildOutput(context.Background())
+
+	expected := map[string]bool{
+		filepath.Join(app, "obj"): true,
+		filepath.Join(app, "bin"): true,
+		filepath.Join(lib, "obj"): true,
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %d targets, got %d: %v", len(expected), len(targets), targets)
+	}
+	for _, target := range targets {
+		if !expected[target.Path] {
+			t.Errorf("Unexpected target %s", target.Path)
+		}
+	}
+}
+
+func TestIsDotNetProject(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "solution/All.sln", "")
+	createTestFile(t, tmpDir, "plain/README.md", "")
+
+	if !isDotNetProject(filepath.Join(tmpDir, "solution")) {
+		t.Error("Directory with a .sln file should be a .NET project")
+	}
+	if isDotNetProject(filepath.Join(tmpDir, "plain")) {
+		t.Error("Directory without project files should not be a .NET project")
+	}
+}
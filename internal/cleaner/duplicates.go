@@ -0,0 +1,254 @@
+package cleaner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// hashChunk is how much of the start and of the end of a file the partial
+// hash reads
+const hashChunk = 64 * 1024
+
+// DuplicateGroup is a set of files with identical contents
+type DuplicateGroup struct {
+	Size  int64  // Size of one file
+	Hash  string // SHA-256 of the contents
+	Paths []string
+}
+
+// Savings returns the space freed by keeping a single copy
+func (g DuplicateGroup) Savings() int64 {
+	return int64(len(g.Paths)-1) * g.Size
+}
+
+// FindDuplicateFiles returns the groups of identical files of at least
+// minSize bytes in dirs, or in the search directories when dirs is empty,
+// largest savings first. Files are compared by size first, then by a hash of
+// their first and last 64 KiB, and only files still alike are hashed in
+// full, using cfg.MaxConcurrent workers. Hard links to the same file count
+// once, since they take no extra space.
+func FindDuplicateFiles(ctx context.Context, cfg *config.Config, dirs []string, minSize int64) ([]DuplicateGroup, error) {
+	s, err := newSearchScanner(cfg, dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	bySize := make(map[int64][]string)
+	infos := make(map[string]os.FileInfo)
+	for result := range s.FindLargeFiles(ctx, minSize) {
+		if result.Err != nil || result.Size == 0 {
+			continue
+		}
+		info, err := os.Stat(result.Path)
+		if err != nil || isHardLinkOf(info, bySize[result.Size], infos) {
+			continue
+		}
+		infos[result.Path] = info
+		bySize[result.Size] = append(bySize[result.Size], result.Path)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	candidates := [][]string{}
+	for _, paths := range bySize {
+		if len(paths) > 1 {
+			sort.Strings(paths)
+			candidates = append(candidates, paths)
+		}
+	}
+
+	workers := cfg.MaxConcurrent
+	if workers < 1 {
+		workers = 1
+	}
+	candidates, _ = groupByHash(ctx, candidates, workers, true)
+	candidates, hashes := groupByHash(ctx, candidates, workers, false)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	groups := make([]DuplicateGroup, 0, len(candidates))
+	for _, paths := range candidates {
+		groups = append(groups, DuplicateGroup{
+			Size:  infos[paths[0]].Size(),
+			Hash:  hashes[paths[0]],
+			Paths: paths,
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Savings() != groups[j].Savings() {
+			return groups[i].Savings() > groups[j].Savings()
+		}
+		return groups[i].Paths[0] < groups[j].Paths[0]
+	})
+
+	return groups, nil
+}
+
+// isHardLinkOf reports whether info is the same file as one of paths
+func isHardLinkOf(info os.FileInfo, paths []string, infos map[string]os.FileInfo) bool {
+	for _, path := range paths {
+		if os.SameFile(info, infos[path]) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupByHash hashes the files of each group with a pool of workers and
+// splits the groups into files with the same hash, dropping files left
+// alone. Files that cannot be read are dropped. It also returns the hash of
+// every file kept.
+func groupByHash(ctx context.Context, groups [][]string, workers int, partial bool) ([][]string, map[string]string) {
+	paths := make(chan string)
+	hashes := make(map[string]string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				hash, err := hashFile(path, partial)
+				if err != nil {
+					continue
+				}
+				mu.Lock()
+				hashes[path] = hash
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, group := range groups {
+		for _, path := range group {
+			select {
+			case paths <- path:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+	}
+	close(paths)
+	wg.Wait()
+
+	split := [][]string{}
+	for _, group := range groups {
+		byHash := make(map[string][]string)
+		order := []string{}
+		for _, path := range group {
+			hash, ok := hashes[path]
+			if !ok {
+				continue
+			}
+			if _, seen := byHash[hash]; !seen {
+				order = append(order, hash)
+			}
+			byHash[hash] = append(byHash[hash], path)
+		}
+		for _, hash := range order {
+			if len(byHash[hash]) > 1 {
+				split = append(split, byHash[hash])
+			}
+		}
+	}
+
+	return split, hashes
+}
+
+// hashFile returns the SHA-256 of a file, or with partial set of its first
+// and last hashChunk bytes only
+func hashFile(path string, partial bool) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if !partial {
+		if _, err := io.Copy(h, file); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	if _, err := io.CopyN(h, file, hashChunk); err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if info, err := file.Stat(); err == nil && info.Size() > 2*hashChunk {
+		if _, err := file.Seek(-hashChunk, io.SeekEnd); err != nil {
+			return "", err
+		}
+		if _, err := io.CopyN(h, file, hashChunk); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CloneDuplicates replaces every file of a group but the first with an APFS
+// clone of the first (cp -c), which shares its blocks until either is
+// modified. Each replaced file keeps its owner, permissions and times. The
+// source and every copy are hashed again first: if any changed since the
+// group was found, nothing is replaced. A clone whose contents don't match
+// is thrown away, and cloning stops. It returns the space freed, and the
+// first error met.
+func CloneDuplicates(runner CommandRunner, group DuplicateGroup) (int64, error) {
+	infos := make([]os.FileInfo, len(group.Paths))
+	for i, path := range group.Paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, err
+		}
+		if info.Size() != group.Size {
+			return 0, fmt.Errorf("%s changed since it was scanned", path)
+		}
+		if hash, err := hashFile(path, false); err != nil || hash != group.Hash {
+			return 0, fmt.Errorf("%s changed since it was scanned", path)
+		}
+		infos[i] = info
+	}
+
+	var freed int64
+	source := group.Paths[0]
+	for i, path := range group.Paths[1:] {
+		info := infos[i+1]
+		clone := path + ".epurer-clone"
+		if err := runner.Run("cp", "-c", source, clone); err != nil {
+			os.Remove(clone)
+			return freed, fmt.Errorf("clone %s: %w", path, err)
+		}
+		// The source may have changed while it was copied
+		if hash, err := hashFile(clone, false); err != nil || hash != group.Hash {
+			os.Remove(clone)
+			return freed, fmt.Errorf("%s changed while it was cloned", source)
+		}
+
+		if uid, gid, ok := utils.FileOwner(info); ok {
+			os.Lchown(clone, uid, gid)
+		}
+		os.Chmod(clone, info.Mode())
+		os.Chtimes(clone, utils.AccessTime(info), info.ModTime())
+		if err := os.Rename(clone, path); err != nil {
+			os.Remove(clone)
+			return freed, err
+		}
+		freed += group.Size
+	}
+
+	return freed, nil
+}
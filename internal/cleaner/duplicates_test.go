@@ -0,0 +1,131 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+// copyRunner stands in for cp -c by copying the file
+type copyRunner struct{}
+
+func (copyRunner) Output(name string, args ...string) ([]byte, error) { return nil, nil }
+
+func (copyRunner) Run(name string, args ...string) error {
+	data, err := os.ReadFile(args[len(args)-2])
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(args[len(args)-1], data, 0600)
+}
+
+func TestFindDuplicateFiles(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	// Large enough for the partial hash to skip the middle
+	content := strings.Repeat("a", 3*hashChunk)
+	sameEnds := content[:hashChunk] + strings.Repeat("b", hashChunk) + content[2*hashChunk:]
+
+	createTestFile(t, tmpDir, "app/assets/video.mp4", content)
+	createTestFile(t, tmpDir, "backup/video.mp4", content)
+	createTestFile(t, tmpDir, "old/video-copy.mp4", content)
+	createTestFile(t, tmpDir, "app/other.mp4", sameEnds)
+	createTestFile(t, tmpDir, "app/small-a.txt", "same")
+	createTestFile(t, tmpDir, "app/small-b.txt", "same")
+	// A hard link takes no extra space
+	if err := os.Link(filepath.Join(tmpDir, "backup/video.mp4"), filepath.Join(tmpDir, "backup/link.mp4")); err != nil {
+		t.Fatal(err)
+	}
+
+	groups, err := FindDuplicateFiles(ctx, config.NewDefaultConfig(), []string{tmpDir}, 100)
+	if err != nil {
+		t.Fatalf("FindDuplicateFiles() error = %v", err)
+	}
+
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 group, got %+v", groups)
+	}
+	group := groups[0]
+	if len(group.Paths) != 3 {
+		t.Errorf("Expected the 3 copies of the video, got %v", group.Paths)
+	}
+	if group.Savings() != int64(2*len(content)) {
+		t.Errorf("Savings() = %d, want %d", group.Savings(), 2*len(content))
+	}
+}
+
+func TestCloneDuplicates(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	a := createTestFile(t, tmpDir, "a.bin", "same contents")
+	b := createTestFile(t, tmpDir, "b.bin", "same contents")
+	c := createTestFile(t, tmpDir, "c.bin", "same contents")
+	mtime := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(b, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(b, 0600)
+
+	hash, err := hashFile(a, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	group := DuplicateGroup{Size: 13, Hash: hash, Paths: []string{a, b, c}}
+
+	// c changed after the scan: nothing is replaced
+	if err := os.WriteFile(c, []byte("other content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	freed, err := CloneDuplicates(copyRunner{}, group)
+	if err == nil || freed != 0 {
+		t.Errorf("Expected the group to be left alone, got %d bytes freed, error %v", freed, err)
+	}
+	if data, _ := os.ReadFile(c); string(data) != "other content" {
+		t.Error("Changed files should not be replaced")
+	}
+
+	group.Paths = []string{a, b}
+	freed, err = CloneDuplicates(copyRunner{}, group)
+	if err != nil || freed != 13 {
+		t.Fatalf("Expected 13 bytes freed, got %d, error %v", freed, err)
+	}
+	info, err := os.Stat(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(mtime) || info.Mode().Perm() != 0600 {
+		t.Errorf("The clone should keep the file's metadata, got %v %v", info.ModTime(), info.Mode())
+	}
+	if _, err := os.Stat(b + ".epurer-clone"); !os.IsNotExist(err) {
+		t.Error("No temporary clone should be left behind")
+	}
+}
+
+func TestCloneDuplicates_SourceChanged(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	a := createTestFile(t, tmpDir, "a.bin", "same contents")
+	b := createTestFile(t, tmpDir, "b.bin", "same contents")
+	hash, _ := hashFile(a, false)
+	group := DuplicateGroup{Size: 13, Hash: hash, Paths: []string{a, b}}
+
+	// The source is the one that changed: the copy must not be overwritten
+	os.WriteFile(a, []byte("edited source"), 0644)
+	if freed, err := CloneDuplicates(copyRunner{}, group); err == nil || freed != 0 {
+		t.Errorf("Expected nothing to be cloned, got %d bytes freed, error %v", freed, err)
+	}
+	if data, _ := os.ReadFile(b); string(data) != "same contents" {
+		t.Errorf("Expected the copy to be kept, got %q", data)
+	}
+}
@@ -0,0 +1,195 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// ElectronCleaner handles desktop app build cleanup (Electron, Tauri)
+type ElectronCleaner struct {
+	scanner *scanner.Scanner
+}
+
+// electronOutputDirs are the folders electron-forge and electron-builder
+// package apps into. dist is already covered by the Frontend cleaner.
+var electronOutputDirs = map[string]string{
+	"out":            "Electron Forge output (out)",
+	"release-builds": "Electron packaged builds (release-builds)",
+}
+
+// NewElectronCleaner creates a new ElectronCleaner
+func NewElectronCleaner() (Cleaner, error) {
+	s, err := scanner.NewScanner()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ElectronCleaner{
+		scanner: s,
+	}, nil
+}
+
+func (e *ElectronCleaner) Name() string {
+	return "Electron/Tauri"
+}
+
+func (e *ElectronCleaner) Domain() config.Domain {
+	return config.DomainFrontend
+}
+
+func (e *ElectronCleaner) Detect(ctx context.Context) (bool, error) {
+	det := detector.FromContext(ctx)
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, err
+	}
+
+	return det.HasPath(filepath.Join(home, "Library", "Caches", "electron"), filepath.Join(home, "Library", "Caches", "electron-builder")) ||
+		det.HasCommand("cargo-tauri", "node"), nil
+}
+
+func (e *ElectronCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	configureScanner(e.scanner, cfg)
+
+	targets := []CleanTarget{}
+	home, err := cfg.HomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// === Global download caches (Safe - re-downloaded on next build) ===
+
+	downloadCaches := map[string]string{
+		filepath.Join(home, "Library", "Caches", "electron"):         "Electron binaries cache",
+		filepath.Join(home, "Library", "Caches", "electron-builder"): "electron-builder cache",
+	}
+
+	for path, description := range downloadCaches {
+		if utils.PathExists(path) {
+			size, _ := utils.GetDirSize(path)
+			if size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        path,
+					Category:    categoryName(description),
+					Description: description,
+					SizeBytes:   size,
+					Safety:      config.Safe,
+				})
+			}
+		}
+	}
+
+	// === Electron projects ===
+
+	// Packaged app output (Safe - rebuilt by make/package scripts)
+	for pattern, description := range electronOutputDirs {
+		resultChan := e.scanner.FindByPattern(ctx, pattern)
+		for result := range resultChan {
+			if result.Err != nil || !isElectronProject(filepath.Dir(result.Path)) {
+				continue
+			}
+
+			targets = append(targets, CleanTarget{
+				Path:        result.Path,
+				Category:    "electron_" + categoryName(pattern),
+				Description: description,
+				SizeBytes:   result.Size,
+				Files:       result.Files,
+				Dirs:        result.Dirs,
+				Safety:      config.Safe,
+			})
+		}
+	}
+
+	// === Tauri projects ===
+
+	// src-tauri/target is skipped by the Backend cleaner's Rust scan
+	tauriTargets := e.scanTauriTargets(ctx, cfg)
+	targets = append(targets, tauriTargets...)
+
+	return targets, nil
+}
+
+func (e *ElectronCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanTargets(ctx, targets, dryRun)
+}
+
+func (e *ElectronCleaner) Patterns() []string {
+	patterns := []string{"src-tauri"}
+	for pattern := range electronOutputDirs {
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// scanTauriTargets finds the Rust build output of Tauri projects. It follows
+// the same rules as other Rust projects: stale artifacts only in cargo-sweep
+// mode, otherwise the whole folder as Moderate.
+func (e *ElectronCleaner) scanTauriTargets(ctx context.Context, cfg *config.Config) []CleanTarget {
+	targets := []CleanTarget{}
+
+	resultChan := e.scanner.FindByPattern(ctx, "src-tauri")
+	for result := range resultChan {
+		if result.Err != nil {
+			continue
+		}
+
+		targetPath := filepath.Join(result.Path, "target")
+		if !isTauriTarget(targetPath) {
+			continue
+		}
+
+		if cfg.CargoSweepDays > 0 {
+			home, _ := cfg.HomeDir()
+			pinned := pinsInstalledToolchain(result.Path, home)
+			sweep := sweepCargoTarget(targetPath, time.Now().AddDate(0, 0, -cfg.CargoSweepDays), pinned)
+			if len(sweep.entries) > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        targetPath,
+					Category:    "tauri_target",
+					Description: fmt.Sprintf("Tauri %s", sweep.describe(cfg.CargoSweepDays)),
+					SizeBytes:   sweep.size,
+					Safety:      config.Safe,
+					Entries:     sweep.entries,
+				})
+			}
+		} else if cfg.Allows(config.DomainFrontend, "tauri_target", config.Moderate) {
+			size, _ := utils.GetDirSize(targetPath)
+			if size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        targetPath,
+					Category:    "tauri_target",
+					Description: "Tauri build output (src-tauri/target)",
+					SizeBytes:   size,
+					Safety:      config.Moderate,
+				})
+			}
+		}
+	}
+
+	return targets
+}
+
+// isTauriTarget reports whether a target folder is the Rust build output of
+// a Tauri app
+func isTauriTarget(path string) bool {
+	srcTauri := filepath.Dir(path)
+	return filepath.Base(srcTauri) == "src-tauri" &&
+		utils.PathExists(path) &&
+		(utils.PathExists(filepath.Join(srcTauri, "tauri.conf.json")) ||
+			utils.PathExists(filepath.Join(srcTauri, "Tauri.toml")))
+}
+
+// isElectronProject reports whether a directory is a Node.js project that
+// depends on Electron
+func isElectronProject(dir string) bool {
+	return dependsOn(dir, "electron")
+}
@@ -0,0 +1,71 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+func TestIsElectronProject(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "app/package.json", `{"devDependencies": {"electron": "^28.0.0"}}`)
+	createTestFile(t, tmpDir, "web/package.json", `{"dependencies": {"react": "^18.0.0"}}`)
+	createTestFile(t, tmpDir, "broken/package.json", `{`)
+
+	tests := []struct {
+		dir      string
+		expected bool
+	}{
+		{"app", true},
+		{"web", false},
+		{"broken", false},
+		{"missing", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			if got := isElectronProject(filepath.Join(tmpDir, tt.dir)); got != tt.expected {
+				t.Errorf("isElectronProject(%s) = %v, want %v", tt.dir, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestElectronCleaner_ScanTauriTargets(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	app := filepath.Join(tmpDir, "notes")
+	createTestFile(t, app, "src-tauri/Cargo.toml", "[package]")
+	createTestFile(t, app, "src-tauri/tauri.conf.json", "{}")
+	createTestFile(t, app, "src-tauri/target/debug/notes", "binary")
+
+	// A src-tauri folder without Tauri config is not a Tauri app
+	createTestFile(t, tmpDir, "other/src-tauri/target/debug/x", "binary")
+
+	s, _ := scanner.NewScannerWithDirs([]string{tmpDir})
+	e := &ElectronCleaner{scanner: s}
+
+	cfg := config.NewDefaultConfig()
+	cfg.CleanLevel = config.Standard
+
+	targets := e.scanTauriTargets(context.Background(), cfg)
+	if len(targets) != 1 || targets[0].Path != filepath.Join(app, "src-tauri", "target") {
+		t.Fatalf("Expected only the Tauri target folder, got %v", targets)
+	}
+	if targets[0].Safety != config.Moderate {
+		t.Errorf("Expected Moderate safety, got %v", targets[0].Safety)
+	}
+
+	// The Rust scan must not report the same folder twice
+	b := &BackendCleaner{scanner: s}
+	if rustTargets := b.scanRustTargets(context.Background()); len(rustTargets) != 0 {
+		t.Errorf("Backend Rust scan should skip Tauri targets, got %v", rustTargets)
+	}
+}
@@ -0,0 +1,600 @@
+package cleaner
+
+import "sort"
+
+// Explanation tells someone who doesn't know a tool what a kind of target
+// is, so they can decide whether to let it go
+type Explanation struct {
+	What        string // What the target holds
+	Regenerates string // What brings it back, if anything
+	Consequence string // What deleting it costs
+}
+
+// explanations describes targets by category. Categories that only differ
+// by tool share their wording on purpose: the reader wants to know what a
+// cache is, not how each tool names it.
+var explanations = map[string]Explanation{
+	"node_modules": {
+		What:        "The packages a JavaScript project depends on, installed from its package.json.",
+		Regenerates: "npm, yarn or pnpm install, run in the project.",
+		Consequence: "The project won't build or run until its packages are installed again, which downloads them.",
+	},
+	"npm_cache": {
+		What:        "Packages npm downloaded, kept to install them again without the network.",
+		Regenerates: "npm, as projects install packages.",
+		Consequence: "The next installs download their packages again.",
+	},
+	"yarn_berry_global_cache": {
+		What:        "Packages Yarn 2+ downloaded, shared by the projects that use the global cache.",
+		Regenerates: "yarn install, as projects install packages.",
+		Consequence: "The next installs download their packages again.",
+	},
+	"yarn_berry_cache": {
+		What:        "The package archives a Yarn 2+ project keeps in .yarn/cache.",
+		Regenerates: "yarn install.",
+		Consequence: "The project needs a yarn install, which downloads them again.",
+	},
+	"yarn_zero_install_cache": {
+		What:        "The package archives of a zero-install Yarn project, committed to git so it runs without installing.",
+		Regenerates: "git checkout of the folder, or yarn install.",
+		Consequence: "git shows the archives as deleted until they are restored.",
+	},
+	"yarn_unplugged": {
+		What:        "Packages Yarn Plug'n'Play extracts because they build native code or need real files.",
+		Regenerates: "yarn install.",
+		Consequence: "The project needs a yarn install before it runs again.",
+	},
+	"yarn_install_state": {
+		What:        "The state Yarn 2+ keeps to speed up the next install.",
+		Regenerates: "yarn install.",
+		Consequence: "The next install takes a little longer.",
+	},
+	"npx_cache": {
+		What:        "Packages npx downloaded to run one-off tools, such as project generators.",
+		Regenerates: "npx, the next time it runs a tool.",
+		Consequence: "The next npx run of each tool downloads it again.",
+	},
+	"node_global_package": {
+		What:        "A package installed globally with npm, yarn or pnpm, and its commands.",
+		Regenerates: "Nothing: it has to be installed again, e.g. npm install -g.",
+		Consequence: "Its commands are no longer available.",
+	},
+	"yarn_cache": {
+		What:        "Packages Yarn downloaded, kept to install them again without the network.",
+		Regenerates: "Yarn, as projects install packages.",
+		Consequence: "The next installs download their packages again.",
+	},
+	"pnpm_store": {
+		What:        "The single copy of every package pnpm projects link to.",
+		Regenerates: "pnpm install, run in each project.",
+		Consequence: "pnpm projects need an install before they build again; packages are downloaded again.",
+	},
+	"gatsby_cache": {
+		What:        "What Gatsby keeps between builds: processed images, data and webpack caches.",
+		Regenerates: "gatsby build and gatsby develop.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"remix_cache": {
+		What:        "The build cache of a Remix project.",
+		Regenerates: "remix build and remix dev.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"astro": {
+		What:        "The types and content collection data Astro generates for a project.",
+		Regenerates: "astro dev, astro build or astro sync.",
+		Consequence: "Nothing is lost, the editor misses the types until Astro runs again.",
+	},
+	"svelte_kit": {
+		What:        "The files SvelteKit generates to build and type-check a project.",
+		Regenerates: "vite dev, vite build or svelte-kit sync.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"nuxt": {
+		What:        "The files Nuxt generates to build and serve a project.",
+		Regenerates: "nuxt dev, nuxt build or nuxt prepare.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"nuxt_output": {
+		What:        "The server and client bundles a Nuxt or Nitro build produced.",
+		Regenerates: "nuxt build.",
+		Consequence: "Nothing is lost, the project has to be built again before it is deployed or previewed.",
+	},
+	"vercel_output": {
+		What:        "The output of vercel build, ready to deploy.",
+		Regenerates: "vercel build, or the framework's build with its Vercel adapter.",
+		Consequence: "Nothing is lost, the project has to be built again before it is deployed.",
+	},
+	"ccache": {
+		What:        "Compiled objects ccache keeps to skip compiling C and C++ sources that haven't changed.",
+		Regenerates: "ccache, as sources compile again.",
+		Consequence: "Nothing is lost, the next builds compile everything again.",
+	},
+	"cmake_build": {
+		What:        "A build directory CMake configured: its cache, generated build files and compiled output.",
+		Regenerates: "cmake configure and build, with the options used before.",
+		Consequence: "The project has to be configured and built again from scratch; options passed to cmake must be passed again.",
+	},
+	"ninja_log": {
+		What:        "The logs Ninja left in a folder whose build files were removed.",
+		Regenerates: "Nothing, they are of no use without the build.",
+		Consequence: "None.",
+	},
+	"conan_packages": {
+		What:        "The C and C++ packages Conan downloaded or built for your projects.",
+		Regenerates: "conan install, run in each project.",
+		Consequence: "The next installs download or build their packages again.",
+	},
+	"vcpkg_buildtrees": {
+		What:        "The sources and intermediate files vcpkg built ports from.",
+		Regenerates: "vcpkg install, when a port is built again.",
+		Consequence: "Nothing is lost, installed ports stay.",
+	},
+	"vcpkg_downloads": {
+		What:        "The source archives and tools vcpkg downloaded to build ports.",
+		Regenerates: "vcpkg install, when a port is built again.",
+		Consequence: "Building a port again downloads its sources again.",
+	},
+	"vcpkg_packages": {
+		What:        "Ports vcpkg staged before installing them.",
+		Regenerates: "vcpkg install.",
+		Consequence: "Nothing is lost, installed ports stay.",
+	},
+	"stack_snapshots": {
+		What:        "The Haskell packages Stack built for the snapshots your projects use.",
+		Regenerates: "stack build, run in each project.",
+		Consequence: "The next builds download and compile their packages again, which can take long.",
+	},
+	"stack_pantry": {
+		What:        "The Hackage index and package sources Stack downloaded.",
+		Regenerates: "stack build or stack update.",
+		Consequence: "The next build downloads the index and sources again.",
+	},
+	"stack_programs": {
+		What:        "The GHC compilers Stack installed.",
+		Regenerates: "stack setup, or the next stack build.",
+		Consequence: "The next build downloads its compiler again.",
+	},
+	"stack_work": {
+		What:        "The build output of a Stack project.",
+		Regenerates: "stack build.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"cabal_store": {
+		What:        "The Haskell packages Cabal built, shared by your projects.",
+		Regenerates: "cabal build, run in each project.",
+		Consequence: "The next builds compile their packages again, which can take long.",
+	},
+	"cabal_packages": {
+		What:        "Package archives and the Hackage index Cabal downloaded.",
+		Regenerates: "cabal update and cabal build.",
+		Consequence: "The next builds download them again.",
+	},
+	"dist_newstyle": {
+		What:        "The build output of a Cabal project.",
+		Regenerates: "cabal build.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"hex_packages": {
+		What:        "Elixir and Erlang packages Hex downloaded.",
+		Regenerates: "mix deps.get or rebar3, as projects fetch their dependencies.",
+		Consequence: "The next fetches download their packages again.",
+	},
+	"mix_build": {
+		What:        "The compiled output of an Elixir project.",
+		Regenerates: "mix compile.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"mix_deps": {
+		What:        "The dependencies of an Elixir project, fetched from its mix.exs.",
+		Regenerates: "mix deps.get, run in the project.",
+		Consequence: "The project won't build until its dependencies are fetched again.",
+	},
+	"rebar3_cache": {
+		What:        "Packages and plugins rebar3 downloaded.",
+		Regenerates: "rebar3, as projects fetch their dependencies.",
+		Consequence: "The next builds download them again.",
+	},
+	"rebar3_build": {
+		What:        "The compiled output and dependencies of an Erlang project.",
+		Regenerates: "rebar3 compile.",
+		Consequence: "The next build fetches dependencies and compiles everything again.",
+	},
+	"zig_cache": {
+		What:        "The compiled artifacts Zig shares between projects, and the packages it fetched.",
+		Regenerates: "zig build, as projects build.",
+		Consequence: "The next builds compile and fetch again.",
+	},
+	"zig_project_cache": {
+		What:        "The build cache of a Zig project.",
+		Regenerates: "zig build.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"r_stale_library": {
+		What:        "The R packages you installed for a version of R that is no longer installed.",
+		Regenerates: "install.packages, with that version of R installed again.",
+		Consequence: "Nothing for the installed versions of R, which use libraries of their own.",
+	},
+	"renv_cache": {
+		What:        "The packages renv installed once for all the R projects that use it.",
+		Regenerates: "renv::restore(), run in each project.",
+		Consequence: "renv projects need a restore before they run again; packages are downloaded again.",
+	},
+	"julia_packages": {
+		What:        "The source of the Julia packages your environments use.",
+		Regenerates: "Pkg.instantiate() or Pkg.add, run in each environment.",
+		Consequence: "Environments need their packages downloaded again before they load.",
+	},
+	"julia_artifacts": {
+		What:        "Binary libraries and data Julia packages downloaded.",
+		Regenerates: "Pkg.instantiate(), or loading the packages.",
+		Consequence: "Packages download their artifacts again the next time they are installed.",
+	},
+	"julia_compiled": {
+		What:        "Packages precompiled by a version of Julia that is no longer installed.",
+		Regenerates: "Nothing, no installed Julia can load them.",
+		Consequence: "None.",
+	},
+	"turbo_cache": {
+		What:        "Task outputs Turborepo saved to replay builds, tests and lints that haven't changed.",
+		Regenerates: "turbo run, as tasks run again.",
+		Consequence: "Nothing is lost, the next runs of each task take longer.",
+	},
+	"nx_cache": {
+		What:        "Task outputs Nx saved to replay builds, tests and lints that haven't changed.",
+		Regenerates: "nx run, as tasks run again.",
+		Consequence: "Nothing is lost, the next runs of each task take longer.",
+	},
+	"nx_cloud_cache": {
+		What:        "Task outputs Nx downloaded from its remote cache.",
+		Regenerates: "nx run, as tasks fetch their outputs again.",
+		Consequence: "The next runs download their outputs again.",
+	},
+	"angular_cache": {
+		What:        "The Angular CLI's build cache.",
+		Regenerates: "ng build and ng serve.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"dist": {
+		What:        "The output of a project's build: bundled scripts, styles and assets.",
+		Regenerates: "The project's build command.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"build": {
+		What:        "The output of a project's build.",
+		Regenerates: "The project's build command.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"out": {
+		What:        "The output of a project's build or export.",
+		Regenerates: "The project's build command.",
+		Consequence: "Nothing is lost, the next build takes longer.",
+	},
+	"next": {
+		What:        "Pages and bundles Next.js compiled for a project.",
+		Regenerates: "next dev or next build.",
+		Consequence: "The next start of the dev server or build compiles the project again.",
+	},
+	"coverage": {
+		What:        "Reports of which lines the tests of a project ran.",
+		Regenerates: "Running the tests with coverage.",
+		Consequence: "Past reports are gone until the tests run again.",
+	},
+	"pycache": {
+		What:        "Python bytecode, compiled from the source to start programs faster.",
+		Regenerates: "Python, the next time the code runs.",
+		Consequence: "None: the first run afterwards is a little slower.",
+	},
+	"pytest_cache": {
+		What:        "What pytest remembers between runs, such as the tests that failed last.",
+		Regenerates: "pytest.",
+		Consequence: "pytest --last-failed runs every test once.",
+	},
+	"mypy_cache": {
+		What:        "Type information mypy reuses between runs.",
+		Regenerates: "mypy.",
+		Consequence: "The next check takes longer.",
+	},
+	"tox": {
+		What:        "Virtual environments tox created to test a project with each Python version.",
+		Regenerates: "tox.",
+		Consequence: "The next tox run creates and installs its environments again.",
+	},
+	"ds_store": {
+		What:        "Files where Finder keeps the view settings of a folder.",
+		Regenerates: "Finder, when the folder is opened.",
+		Consequence: "Folders forget their icon positions and view options.",
+	},
+	"go_build_cache": {
+		What:        "Compiled Go packages, reused to build and test faster.",
+		Regenerates: "go build and go test.",
+		Consequence: "The next builds compile everything again.",
+	},
+	"go_mod_cache": {
+		What:        "The source of the Go modules your projects depend on.",
+		Regenerates: "go mod download, or any go build.",
+		Consequence: "Modules are downloaded again on the next build of each project.",
+	},
+	"cargo_registry": {
+		What:        "The source of the Rust crates your projects depend on.",
+		Regenerates: "cargo build or cargo fetch.",
+		Consequence: "Crates are downloaded again on the next build of each project.",
+	},
+	"rust_target": {
+		What:        "Everything cargo compiled for a Rust project, in every profile.",
+		Regenerates: "cargo build.",
+		Consequence: "The next build compiles the project and its crates from scratch, which can take minutes.",
+	},
+	"maven_repository": {
+		What:        "The Java libraries Maven projects depend on.",
+		Regenerates: "mvn, as projects build.",
+		Consequence: "Libraries are downloaded again on the next build of each project.",
+	},
+	"gradle_cache": {
+		What:        "Libraries and build outputs Gradle reuses between builds.",
+		Regenerates: "Gradle, as projects build.",
+		Consequence: "The next builds download libraries and compile again.",
+	},
+	"gradle_wrapper_dists": {
+		What:        "Gradle versions downloaded by the wrappers of your projects.",
+		Regenerates: "./gradlew, in each project.",
+		Consequence: "Each Gradle version is downloaded again when a project needs it.",
+	},
+	"nuget_packages": {
+		What:        "The .NET packages your projects depend on.",
+		Regenerates: "dotnet restore, or any dotnet build.",
+		Consequence: "Packages are downloaded again on the next build of each project.",
+	},
+	"dotnet_bin": {
+		What:        "The compiled output of a .NET project.",
+		Regenerates: "dotnet build.",
+		Consequence: "Nothing is lost, the next build compiles the project again.",
+	},
+	"dotnet_obj": {
+		What:        "Intermediate files of a .NET build.",
+		Regenerates: "dotnet build.",
+		Consequence: "Nothing is lost, the next build compiles the project again.",
+	},
+	"php_vendor": {
+		What:        "The packages a PHP project depends on, installed from its composer.json.",
+		Regenerates: "composer install, run in the project.",
+		Consequence: "The project won't run until its packages are installed again.",
+	},
+	"composer_cache": {
+		What:        "Packages Composer downloaded, kept to install them again without the network.",
+		Regenerates: "Composer, as projects install packages.",
+		Consequence: "The next installs download their packages again.",
+	},
+	"gem_cache": {
+		What:        "Ruby gems downloaded by RubyGems and Bundler.",
+		Regenerates: "gem install and bundle install.",
+		Consequence: "The next installs download their gems again.",
+	},
+	"cocoapods_pods": {
+		What:        "The libraries of an iOS or macOS project, installed from its Podfile.",
+		Regenerates: "pod install, run in the project.",
+		Consequence: "The project won't build until its pods are installed again.",
+	},
+	"cocoapods_cache": {
+		What:        "Pods CocoaPods downloaded, kept to install them again quickly.",
+		Regenerates: "pod install.",
+		Consequence: "The next installs download their pods again.",
+	},
+	"xcode_derived_data": {
+		What:        "Build products and the code index Xcode keeps for every project you opened.",
+		Regenerates: "Xcode, as you build and open projects.",
+		Consequence: "The next build of each project starts from scratch and Xcode indexes it again.",
+	},
+	"xcode_archives": {
+		What:        "App builds you archived in Xcode to distribute them, with their debug symbols.",
+		Regenerates: "Nothing: an archive is only made again by archiving the same version.",
+		Consequence: "Crash reports of released versions can no longer be symbolicated unless the symbols are uploaded elsewhere.",
+	},
+	"xcode_module_cache": {
+		What:        "Precompiled system and library modules Xcode reuses between builds.",
+		Regenerates: "Xcode, on the next build.",
+		Consequence: "The next builds take longer.",
+	},
+	"ios_device_support": {
+		What:        "Debug symbols Xcode copied from each iOS version of the devices you connected.",
+		Regenerates: "Xcode, when a device with that iOS version is connected.",
+		Consequence: "Connecting such a device again copies its symbols again, which takes a few minutes.",
+	},
+	"simulator_devices": {
+		What:        "Simulated iPhones and iPads, with the apps and data installed on them.",
+		Regenerates: "Xcode or xcrun simctl, creating the simulator again.",
+		Consequence: "Apps and data on the simulators are lost.",
+	},
+	"simulator_caches": {
+		What:        "Caches of the iOS simulators.",
+		Regenerates: "The simulators, as they run.",
+		Consequence: "Simulators start a bit slower the next time.",
+	},
+	"android_platform": {
+		What:        "The Android SDK Platform of an API level, which projects compile against.",
+		Regenerates: "The SDK Manager of Android Studio, or Gradle when a project compiles against it.",
+		Consequence: "A project that compiles against that API level downloads it again.",
+	},
+	"android_system_image": {
+		What:        "The system images emulators of an API level start from.",
+		Regenerates: "The SDK Manager of Android Studio.",
+		Consequence: "Creating an emulator of that API level downloads them again.",
+	},
+	"android_ndk": {
+		What:        "A version of the Android NDK, which builds native C and C++ code.",
+		Regenerates: "The SDK Manager, or Gradle when a project needs that version.",
+		Consequence: "A project that builds native code with that version downloads it again, about 1 GB.",
+	},
+	"android_emulator_leftover": {
+		What:        "A copy of the Android emulator an interrupted update left next to the one in use.",
+		Regenerates: "Nothing, the emulator in use stays.",
+		Consequence: "None.",
+	},
+	"android_avds": {
+		What:        "Android emulators, with the apps and data installed on them.",
+		Regenerates: "Android Studio's Device Manager, creating the emulator again.",
+		Consequence: "Apps and data on the emulators are lost.",
+	},
+	"android_build": {
+		What:        "The compiled output of an Android project.",
+		Regenerates: "Gradle, on the next build.",
+		Consequence: "Nothing is lost, the next build compiles the project again.",
+	},
+	"flutter_build": {
+		What:        "The compiled output of a Flutter project.",
+		Regenerates: "flutter build or flutter run.",
+		Consequence: "Nothing is lost, the next build compiles the project again.",
+	},
+	"dart_tool": {
+		What:        "Package settings and build caches of a Dart or Flutter project.",
+		Regenerates: "dart pub get or flutter pub get.",
+		Consequence: "The project needs a pub get before it builds again.",
+	},
+	"homebrew_cache": {
+		What:        "Installers Homebrew downloaded for the packages you installed.",
+		Regenerates: "brew install and brew upgrade.",
+		Consequence: "Reinstalling a package downloads it again.",
+	},
+	"homebrew_stale_downloads": {
+		What:        "Installers Homebrew downloaded for packages or versions that are no longer installed.",
+		Regenerates: "brew install, if you install them again.",
+		Consequence: "Nothing installed is affected.",
+	},
+	"homebrew_old_kegs": {
+		What:        "Older versions of Homebrew packages kept after an upgrade.",
+		Regenerates: "Nothing: the newer version is the one in use.",
+		Consequence: "Switching back to the old version needs it installed again.",
+	},
+	"homebrew_unlinked_kegs": {
+		What:        "Homebrew packages installed but not linked, so their commands are not available.",
+		Regenerates: "brew install or brew link.",
+		Consequence: "Nothing that runs is affected, brew install brings them back.",
+	},
+	"docker_desktop_disk": {
+		What:        "Space Docker Desktop's virtual machine disk still takes for data deleted inside the VM.",
+		Regenerates: "The disk grows again as Docker stores images, containers and volumes.",
+		Consequence: "None for Docker's data, but it runs a privileged container from a locally pulled image.",
+	},
+	"terraform": {
+		What:        "Providers and modules Terraform downloaded for a configuration.",
+		Regenerates: "terraform init.",
+		Consequence: "The configuration needs a terraform init before plan or apply.",
+	},
+	"huggingface_repos": {
+		What:        "Models and datasets downloaded from Hugging Face.",
+		Regenerates: "The library that downloaded them, the next time a script uses them.",
+		Consequence: "They are downloaded again when needed, which can be several gigabytes.",
+	},
+	"ollama_models": {
+		What:        "Language models pulled with Ollama.",
+		Regenerates: "ollama pull.",
+		Consequence: "The model is downloaded again before its next use.",
+	},
+	"torch_hub": {
+		What:        "Pretrained models PyTorch downloaded.",
+		Regenerates: "PyTorch, the next time a script loads them.",
+		Consequence: "They are downloaded again when needed.",
+	},
+	"conda_pkgs": {
+		What:        "Packages conda downloaded to create environments.",
+		Regenerates: "conda, as environments are created or updated.",
+		Consequence: "Creating an environment downloads its packages again. Existing environments keep working.",
+	},
+	"poetry_virtualenv": {
+		What:        "The virtualenv Poetry created for a project, with its installed packages.",
+		Regenerates: "poetry install, run in the project.",
+		Consequence: "The project needs a poetry install before it runs again; nothing if the project was deleted.",
+	},
+	"uv_cache": {
+		What:        "Packages and Python builds uv downloaded, shared by the projects and tools it installs.",
+		Regenerates: "uv, as projects install packages.",
+		Consequence: "The next installs download their packages again.",
+	},
+	"pipx_app": {
+		What:        "A Python command-line app installed with pipx, in a virtualenv of its own.",
+		Regenerates: "Nothing: it has to be installed again with pipx install.",
+		Consequence: "Its commands are no longer available.",
+	},
+	"poetry_cache": {
+		What:        "Python packages Poetry downloaded.",
+		Regenerates: "poetry install.",
+		Consequence: "The next installs download their packages again.",
+	},
+	"ios_backups": {
+		What:        "Backups of iPhones and iPads made by Finder or iTunes.",
+		Regenerates: "Nothing: a new backup only holds what is on the device now.",
+		Consequence: "A device lost or wiped can't be restored to the state of a deleted backup.",
+	},
+	"installers": {
+		What:        "Disk images and installers downloaded from the web.",
+		Regenerates: "Downloading them again.",
+		Consequence: "The app stays installed; reinstalling it takes a new download.",
+	},
+	"trash": {
+		What:        "Files you moved to the Trash.",
+		Regenerates: "Nothing.",
+		Consequence: "They can't be put back.",
+	},
+	"caches": {
+		What:        "Data apps keep to start and run faster.",
+		Regenerates: "Each app, as it runs.",
+		Consequence: "Apps may be slower the first time they start afterwards.",
+	},
+	"system_logs": {
+		What:        "Log files written by macOS and apps.",
+		Regenerates: "macOS and the apps, as they run.",
+		Consequence: "Past events can no longer be looked up to diagnose a problem.",
+	},
+	"temp_files": {
+		What:        "Temporary files apps left behind.",
+		Regenerates: "Apps, when they need them.",
+		Consequence: "None for apps that are closed.",
+	},
+}
+
+// Explain returns what the target is, what regenerates it and what deleting
+// it costs. It reports false for kinds of targets not described yet.
+func (t CleanTarget) Explain() (Explanation, bool) {
+	explanation, ok := explanations[t.Category]
+	return explanation, ok
+}
+
+// ExplainedCategory is a kind of target found by a scan, with its
+// explanation
+type ExplainedCategory struct {
+	Category    string
+	Example     string // Description of one of its targets
+	Targets     int
+	SizeBytes   int64
+	Explanation Explanation
+}
+
+// ExplainCategories groups targets by kind and explains each kind that can
+// be, the largest first
+func ExplainCategories(targets []CleanTarget) []ExplainedCategory {
+	byCategory := make(map[string]*ExplainedCategory)
+	for _, target := range targets {
+		explanation, ok := target.Explain()
+		if !ok {
+			continue
+		}
+		category, ok := byCategory[target.Category]
+		if !ok {
+			category = &ExplainedCategory{Category: target.Category, Example: target.Description, Explanation: explanation}
+			byCategory[target.Category] = category
+		}
+		category.Targets++
+		category.SizeBytes += target.SizeBytes
+	}
+
+	categories := make([]ExplainedCategory, 0, len(byCategory))
+	for _, category := range byCategory {
+		categories = append(categories, *category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].SizeBytes != categories[j].SizeBytes {
+			return categories[i].SizeBytes > categories[j].SizeBytes
+		}
+		return categories[i].Category < categories[j].Category
+	})
+	return categories
+}
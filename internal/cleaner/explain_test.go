@@ -0,0 +1,43 @@
+package cleaner
+
+import "testing"
+
+func TestExplanations_Complete(t *testing.T) {
+	for category, explanation := range explanations {
+		if explanation.What == "" || explanation.Regenerates == "" || explanation.Consequence == "" {
+			t.Errorf("Explanation of %s is incomplete: %+v", category, explanation)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	if _, ok := (CleanTarget{Category: "node_modules"}).Explain(); !ok {
+		t.Error("Expected node_modules to be explained")
+	}
+	if _, ok := (CleanTarget{Category: "bogus"}).Explain(); ok {
+		t.Error("Expected no explanation for an unknown category")
+	}
+}
+
+func TestExplainCategories(t *testing.T) {
+	targets := []CleanTarget{
+		{Path: "/a/node_modules", Category: "node_modules", Description: "node_modules dependencies", SizeBytes: 100},
+		{Path: "/b/node_modules", Category: "node_modules", Description: "node_modules dependencies", SizeBytes: 200},
+		{Path: "/DerivedData", Category: "xcode_derived_data", Description: "Xcode DerivedData", SizeBytes: 1000},
+		{Path: "/unknown", Category: "bogus", SizeBytes: 5000},
+	}
+
+	categories := ExplainCategories(targets)
+	if len(categories) != 2 {
+		t.Fatalf("Expected 2 explained categories, got %+v", categories)
+	}
+	if categories[0].Category != "xcode_derived_data" || categories[0].Targets != 1 {
+		t.Errorf("Expected DerivedData first, got %+v", categories[0])
+	}
+	if categories[1].Category != "node_modules" || categories[1].Targets != 2 || categories[1].SizeBytes != 300 {
+		t.Errorf("Expected node_modules grouped, got %+v", categories[1])
+	}
+	if categories[1].Explanation.What == "" {
+		t.Error("Expected the explanation to be set")
+	}
+}
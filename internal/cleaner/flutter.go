@@ -0,0 +1,152 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// scanFlutterCaches returns the global Dart and Flutter caches: package
+// versions in the pub cache superseded by a newer one, engine artifacts of
+// Flutter SDKs other than activeSDK (e.g. installed with fvm) and the Dart
+// analysis server cache. All are downloaded again when needed.
+func scanFlutterCaches(home, activeSDK string) []CleanTarget {
+	targets := []CleanTarget{}
+
+	pubCache := os.Getenv("PUB_CACHE")
+	if pubCache == "" {
+		pubCache = filepath.Join(home, ".pub-cache")
+	}
+	if old := oldPubPackages(pubCache); len(old) > 0 {
+		if target, ok := entriesTarget(pubCache, old, "pub_cache_old_versions", "Older package versions in the pub cache", config.Safe); ok {
+			targets = append(targets, target)
+		}
+	}
+
+	for _, sdk := range flutterSDKs(home) {
+		if sameDir(sdk, activeSDK) {
+			continue
+		}
+
+		artifacts := filepath.Join(sdk, "bin", "cache", "artifacts")
+		size, _ := utils.GetDirSize(artifacts)
+		if size > 0 {
+			targets = append(targets, CleanTarget{
+				Path:        artifacts,
+				Category:    "flutter_engine_artifacts",
+				Description: fmt.Sprintf("Flutter %s engine artifacts (SDK not in use)", filepath.Base(sdk)),
+				SizeBytes:   size,
+				Safety:      config.Safe,
+			})
+		}
+	}
+
+	for _, cache := range []toolCache{
+		{path: filepath.Join(home, ".dartServer"), category: "dart_analysis_cache", description: "Dart analysis server cache"},
+		{path: filepath.Join(home, ".dart-server"), category: "dart_analysis_cache", description: "Dart analysis server cache"},
+	} {
+		size, _ := utils.GetDirSize(cache.path)
+		if size > 0 {
+			targets = append(targets, CleanTarget{
+				Path:        cache.path,
+				Category:    cache.category,
+				Description: cache.description,
+				SizeBytes:   size,
+				Safety:      config.Safe,
+			})
+		}
+	}
+
+	return targets
+}
+
+// oldPubPackages returns the package directories of the pub cache
+// (hosted/<server>/<name>-<version>) that have a newer version of the same
+// package next to them
+func oldPubPackages(pubCache string) []string {
+	old := []string{}
+
+	servers, _ := filepath.Glob(filepath.Join(pubCache, "hosted", "*"))
+	for _, server := range servers {
+		entries, err := os.ReadDir(server)
+		if err != nil {
+			continue
+		}
+
+		// Package names can't contain "-", versions can (1.0.0-beta)
+		versions := make(map[string][]string)
+		for _, entry := range entries {
+			name, version, ok := strings.Cut(entry.Name(), "-")
+			if !ok || !entry.IsDir() {
+				continue
+			}
+			versions[name] = append(versions[name], version)
+		}
+
+		names := make([]string, 0, len(versions))
+		for name := range versions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			list := versions[name]
+			sort.Slice(list, func(i, j int) bool {
+				return compareVersions(list[i], list[j]) > 0
+			})
+			for _, version := range list[1:] {
+				old = append(old, filepath.Join(server, name+"-"+version))
+			}
+		}
+	}
+
+	return old
+}
+
+// flutterSDKs returns the Flutter SDKs installed with fvm
+func flutterSDKs(home string) []string {
+	sdks := []string{}
+	for _, dir := range []string{filepath.Join(home, "fvm", "versions"), filepath.Join(home, ".fvm", "versions")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				sdks = append(sdks, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	return sdks
+}
+
+// activeFlutterSDK returns the root of the Flutter SDK whose flutter command
+// is in PATH, or "" if there is none
+func activeFlutterSDK() string {
+	path, err := exec.LookPath("flutter")
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	// <sdk>/bin/flutter
+	return filepath.Dir(filepath.Dir(path))
+}
+
+// sameDir reports whether two paths point to the same directory, following
+// symlinks (fvm's "default" link points to one of the versions)
+func sameDir(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
@@ -0,0 +1,66 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOldPubPackages(t *testing.T) {
+	pubCache := t.TempDir()
+	hosted := filepath.Join(pubCache, "hosted", "pub.dev")
+	for _, dir := range []string{"http-0.13.6", "http-1.2.0", "http-1.10.0", "path-1.9.0", "intl-0.19.0-beta.1", "intl-0.18.1"} {
+		if err := os.MkdirAll(filepath.Join(hosted, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	old := oldPubPackages(pubCache)
+
+	expected := map[string]bool{
+		filepath.Join(hosted, "http-0.13.6"): true,
+		filepath.Join(hosted, "http-1.2.0"):  true,
+		filepath.Join(hosted, "intl-0.18.1"): true,
+	}
+	if len(old) != len(expected) {
+		t.Fatalf("Expected %d old versions, got %v", len(expected), old)
+	}
+	for _, path := range old {
+		if !expected[path] {
+			t.Errorf("Unexpected old version %s", path)
+		}
+	}
+}
+
+func TestScanFlutterCaches(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+	t.Setenv("PUB_CACHE", "")
+
+	createTestFile(t, home, ".pub-cache/hosted/pub.dev/http-0.13.6/lib/http.dart", "library http;")
+	createTestFile(t, home, ".pub-cache/hosted/pub.dev/http-1.2.0/lib/http.dart", "library http;")
+	createTestFile(t, home, "fvm/versions/3.19.0/bin/cache/artifacts/engine/flutter.a", "engine")
+	createTestFile(t, home, "fvm/versions/3.22.0/bin/cache/artifacts/engine/flutter.a", "engine")
+	createTestFile(t, home, ".dartServer/.analysis-driver/data", "index")
+
+	active := filepath.Join(home, "fvm", "versions", "3.22.0")
+	targets := scanFlutterCaches(home, active)
+
+	categories := map[string]string{}
+	for _, target := range targets {
+		categories[target.Category] = target.Path
+	}
+
+	if len(targets) != 3 {
+		t.Fatalf("Expected 3 targets, got %d: %v", len(targets), targets)
+	}
+	if categories["pub_cache_old_versions"] != filepath.Join(home, ".pub-cache") {
+		t.Errorf("Expected old pub versions target, got %v", categories)
+	}
+	if categories["flutter_engine_artifacts"] != filepath.Join(home, "fvm", "versions", "3.19.0", "bin", "cache", "artifacts") {
+		t.Errorf("Expected only the inactive SDK's artifacts, got %v", categories)
+	}
+	if categories["dart_analysis_cache"] != filepath.Join(home, ".dartServer") {
+		t.Errorf("Expected Dart analysis cache target, got %v", categories)
+	}
+}
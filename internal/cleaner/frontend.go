@@ -4,8 +4,11 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/platform"
 	"github.com/0SansNom/epurer/internal/scanner"
 	"github.com/0SansNom/epurer/pkg/utils"
 )
@@ -13,6 +16,7 @@ import (
 // FrontendCleaner handles frontend development cleanup (Node.js, npm, yarn, pnpm, etc.)
 type FrontendCleaner struct {
 	scanner *scanner.Scanner
+	runner  CommandRunner // Runs git to tell committed Yarn caches, ExecRunner if nil
 }
 
 // NewFrontendCleaner creates a new FrontendCleaner
@@ -27,6 +31,19 @@ func NewFrontendCleaner() (Cleaner, error) {
 	}, nil
 }
 
+// SetRunner replaces the runner of the cleaner's external commands
+func (f *FrontendCleaner) SetRunner(runner CommandRunner) {
+	f.runner = runner
+}
+
+// commands returns the runner of the cleaner's external commands
+func (f *FrontendCleaner) commands() CommandRunner {
+	if f.runner == nil {
+		return ExecRunner{}
+	}
+	return f.runner
+}
+
 func (f *FrontendCleaner) Name() string {
 	return "Frontend"
 }
@@ -37,28 +54,43 @@ func (f *FrontendCleaner) Domain() config.Domain {
 
 func (f *FrontendCleaner) Detect(ctx context.Context) (bool, error) {
 	// Check if Node.js ecosystem tools are installed
-	return utils.CommandExists("node") ||
-		utils.CommandExists("npm") ||
-		utils.CommandExists("yarn") ||
-		utils.CommandExists("pnpm"), nil
+	return detector.FromContext(ctx).HasCommand("node", "npm", "yarn", "pnpm"), nil
 }
 
 func (f *FrontendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	configureScanner(f.scanner, cfg)
+
 	targets := []CleanTarget{}
-	home, err := os.UserHomeDir()
+	home, err := cfg.HomeDir()
 	if err != nil {
 		return nil, err
 	}
 
 	// === Package manager caches (Safe - always can be rebuilt) ===
 
-	// npm cache
-	npmCachePath := filepath.Join(home, ".npm")
-	if utils.PathExists(npmCachePath) {
+	layout := platform.Current()
+
+	// npm cache, without the npx cache listed on its own below
+	npmCachePath := npmCacheDir(layout, home)
+	npxCachePath := filepath.Join(npmCachePath, "_npx")
+	if utils.PathExists(npxCachePath) {
+		var entries []string
+		children, _ := os.ReadDir(npmCachePath)
+		for _, child := range children {
+			if child.Name() != "_npx" {
+				entries = append(entries, filepath.Join(npmCachePath, child.Name()))
+			}
+		}
+		if target, ok := entriesTarget(npmCachePath, entries, "npm_cache", "npm cache", config.Safe); ok {
+			target.Description = "npm cache"
+			targets = append(targets, target)
+		}
+	} else if utils.PathExists(npmCachePath) {
 		size, _ := utils.GetDirSize(npmCachePath)
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        npmCachePath,
+				Category:    "npm_cache",
 				Description: "npm cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -66,13 +98,25 @@ func (f *FrontendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]Clean
 		}
 	}
 
+	// npx cache: one-off tools run with npx, often several GB
+	if size, _ := utils.GetDirSize(npxCachePath); size > 0 {
+		targets = append(targets, CleanTarget{
+			Path:        npxCachePath,
+			Category:    "npx_cache",
+			Description: "npx cache",
+			SizeBytes:   size,
+			Safety:      config.Safe,
+		})
+	}
+
 	// yarn cache
-	yarnCachePath := filepath.Join(home, ".cache", "yarn")
+	yarnCachePath := yarnCacheDir(layout, home)
 	if utils.PathExists(yarnCachePath) {
 		size, _ := utils.GetDirSize(yarnCachePath)
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        yarnCachePath,
+				Category:    "yarn_cache",
 				Description: "Yarn cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -87,6 +131,7 @@ func (f *FrontendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]Clean
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        yarnGlobalCache,
+				Category:    "yarn_global_cache",
 				Description: "Yarn global cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -94,231 +139,126 @@ func (f *FrontendCleaner) Scan(ctx context.Context, cfg *config.Config) ([]Clean
 		}
 	}
 
-	// pnpm store
-	pnpmStorePath := filepath.Join(home, ".pnpm-store")
-	if utils.PathExists(pnpmStorePath) {
-		size, _ := utils.GetDirSize(pnpmStorePath)
+	// Yarn Berry (2+) global cache, shared by projects that don't keep a
+	// cache of their own
+	yarnBerryCache := filepath.Join(home, ".yarn", "berry", "cache")
+	if size, _ := utils.GetDirSize(yarnBerryCache); size > 0 {
+		targets = append(targets, CleanTarget{
+			Path:        yarnBerryCache,
+			Category:    "yarn_berry_global_cache",
+			Description: "Yarn Berry global cache",
+			SizeBytes:   size,
+			Safety:      config.Safe,
+		})
+	}
+
+	// pnpm stores
+	for _, pnpmStorePath := range pnpmStoreDirs(layout, home) {
+		if utils.PathExists(pnpmStorePath) {
+			size, _ := utils.GetDirSize(pnpmStorePath)
+			if size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        pnpmStorePath,
+					Category:    "pnpm_store",
+					Description: "pnpm store",
+					SizeBytes:   size,
+					Safety:      config.Safe,
+				})
+			}
+		}
+	}
+
+	// TypeScript server cache (type definitions fetched by tsserver)
+	tsserverCachePath := filepath.Join(home, "Library", "Caches", "typescript")
+	if utils.PathExists(tsserverCachePath) {
+		size, _ := utils.GetDirSize(tsserverCachePath)
 		if size > 0 {
 			targets = append(targets, CleanTarget{
-				Path:        pnpmStorePath,
-				Description: "pnpm store",
+				Path:        tsserverCachePath,
+				Category:    "tsserver_cache",
+				Description: "TypeScript server cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
 			})
 		}
 	}
 
-	// === node_modules (Moderate - needs npm install) ===
-
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
-		nodeModulesTargets := f.scanNodeModules(ctx)
-		targets = append(targets, nodeModulesTargets...)
+	// Nx Cloud cache (task outputs downloaded from the remote cache)
+	nxCloudPath := filepath.Join(home, ".cache", "nx-cloud")
+	if size, _ := utils.GetDirSize(nxCloudPath); size > 0 {
+		targets = append(targets, CleanTarget{
+			Path:        nxCloudPath,
+			Category:    "nx_cloud_cache",
+			Description: "Nx Cloud cache",
+			SizeBytes:   size,
+			Safety:      config.Safe,
+		})
 	}
 
-	// === Build outputs (Safe - easily rebuilt) ===
-
-	// dist folders
-	distTargets := f.scanPattern(ctx, "dist")
-	targets = append(targets, distTargets...)
-
-	// build folders
-	buildTargets := f.scanPattern(ctx, "build")
-	targets = append(targets, buildTargets...)
-
-	// out folders (Next.js, etc.)
-	outTargets := f.scanPattern(ctx, "out")
-	targets = append(targets, outTargets...)
-
-	// .next (Next.js)
-	nextTargets := f.scanPattern(ctx, ".next")
-	targets = append(targets, nextTargets...)
+	// === Global packages (Moderate - their commands need a reinstall) ===
 
-	// === Bundler caches (Safe) ===
-
-	// Vite cache
-	viteTargets := f.scanPattern(ctx, ".vite")
-	targets = append(targets, viteTargets...)
-
-	// Parcel cache
-	parcelTargets := f.scanPattern(ctx, ".parcel-cache")
-	targets = append(targets, parcelTargets...)
-
-	// Webpack cache (inside node_modules/.cache/webpack)
-	// We'll get this with a more specific scan
-	webpackCacheTargets := f.scanNestedCache(ctx, ".cache/webpack")
-	targets = append(targets, webpackCacheTargets...)
-
-	// Turbo cache
-	turboCacheTargets := f.scanNestedCache(ctx, ".cache/turbo")
-	targets = append(targets, turboCacheTargets...)
-
-	// === Testing coverage (Safe) ===
-
-	coverageTargets := f.scanPattern(ctx, "coverage")
-	targets = append(targets, coverageTargets...)
-
-	nycTargets := f.scanPattern(ctx, ".nyc_output")
-	targets = append(targets, nycTargets...)
+	if cfg.Allows(config.DomainFrontend, "node_global_package", config.Moderate) {
+		targets = append(targets, nodeGlobalPackages(nodeGlobalRoots(home), time.Now())...)
+	}
 
-	// === Linter caches (Safe) ===
+	// === Project folders ===
 
-	eslintTargets := f.scanPattern(ctx, ".eslintcache")
-	targets = append(targets, eslintTargets...)
+	// node_modules (Moderate - needs npm install), build outputs, bundler
+	// caches, coverage reports and logs (Safe - easily rebuilt)
+	projectTargets := scanProjectPatterns(ctx, f.scanner, cfg, config.DomainFrontend)
+	targets = append(targets, projectTargets...)
 
-	// === Storybook (Safe) ===
+	// === Yarn Berry caches, unplugged packages and install states ===
 
-	storybookTargets := f.scanPattern(ctx, "storybook-static")
-	targets = append(targets, storybookTargets...)
+	targets = append(targets, scanYarnBerry(ctx, f.scanner, f.commands(), cfg)...)
 
-	// === Log files (Safe) ===
+	// === Turborepo, Nx and Angular caches of monorepo packages (Safe) ===
 
-	npmLogTargets := f.scanPattern(ctx, "npm-debug.log*")
-	targets = append(targets, npmLogTargets...)
+	targets = append(targets, scanMonorepoCaches(ctx, f.scanner, cfg)...)
 
-	yarnLogTargets := f.scanPattern(ctx, "yarn-error.log*")
-	targets = append(targets, yarnLogTargets...)
+	// === Bundler caches inside node_modules (Safe) ===
 
-	yarnDebugTargets := f.scanPattern(ctx, "yarn-debug.log*")
-	targets = append(targets, yarnDebugTargets...)
+	for _, pattern := range nestedCachePatterns {
+		targets = append(targets, f.scanNestedCache(ctx, pattern)...)
+	}
 
 	return targets, nil
 }
 
 func (f *FrontendCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
-	results := make([]CleanResult, 0, len(targets))
-
-	for _, target := range targets {
-		result := CleanResult{
-			Target:  target,
-			Success: true,
-		}
-
-		if !dryRun {
-			err := utils.SafeRemove(target.Path, false)
-			if err != nil {
-				result.Success = false
-				result.Error = err
-			} else {
-				result.BytesFreed = target.SizeBytes
-			}
-		} else {
-			// In dry-run, just report what would be freed
-			result.BytesFreed = target.SizeBytes
-		}
-
-		results = append(results, result)
-
-		// Check for cancellation
-		select {
-		case <-ctx.Done():
-			return results, ctx.Err()
-		default:
-		}
-	}
-
-	return results, nil
+	return cleanTargets(ctx, targets, dryRun)
 }
 
-// scanNodeModules scans for node_modules directories
-func (f *FrontendCleaner) scanNodeModules(ctx context.Context) []CleanTarget {
-	targets := []CleanTarget{}
-
-	resultChan := f.scanner.FindByPattern(ctx, "node_modules")
-	for result := range resultChan {
-		if result.Err != nil {
-			continue
-		}
-
-		// Skip node_modules inside other node_modules (nested dependencies)
-		if filepath.Base(filepath.Dir(result.Path)) == "node_modules" {
-			continue
-		}
-
-		targets = append(targets, CleanTarget{
-			Path:        result.Path,
-			Description: "node_modules dependencies",
-			SizeBytes:   result.Size,
-			Safety:      config.Moderate,
-		})
-	}
+func (f *FrontendCleaner) Patterns() []string {
+	patterns := append(projectPatternNames(config.DomainFrontend), nestedCachePatterns...)
+	return append(append(patterns, yarnBerryPattern), monorepoCachePatterns()...)
+}
 
-	return targets
+// nestedCachePatterns find the bundler caches kept inside node_modules
+var nestedCachePatterns = []string{
+	"**/node_modules/.cache/webpack",
 }
 
-// scanPattern is a generic scanner for simple patterns
-func (f *FrontendCleaner) scanPattern(ctx context.Context, pattern string) []CleanTarget {
+// scanNestedCache scans for the caches inside node_modules matching pattern
+func (f *FrontendCleaner) scanNestedCache(ctx context.Context, pattern string) []CleanTarget {
 	targets := []CleanTarget{}
 
-	resultChan := f.scanner.FindByPattern(ctx, pattern)
-	for result := range resultChan {
-		if result.Err != nil {
+	for result := range f.scanner.FindByPattern(ctx, pattern) {
+		if result.Err != nil || result.Size == 0 {
 			continue
 		}
 
-		// Determine description based on pattern
-		desc := f.getDescriptionForPattern(pattern)
-
+		name := filepath.Base(result.Path)
 		targets = append(targets, CleanTarget{
 			Path:        result.Path,
-			Description: desc,
+			Category:    categoryName(name) + "_cache",
+			Description: name + " cache",
 			SizeBytes:   result.Size,
+			Files:       result.Files,
+			Dirs:        result.Dirs,
 			Safety:      config.Safe,
 		})
 	}
 
 	return targets
 }
-
-// scanNestedCache scans for caches inside node_modules
-func (f *FrontendCleaner) scanNestedCache(ctx context.Context, subPath string) []CleanTarget {
-	targets := []CleanTarget{}
-
-	// First find all node_modules
-	nodeModulesChan := f.scanner.FindByPattern(ctx, "node_modules")
-	for nmResult := range nodeModulesChan {
-		if nmResult.Err != nil {
-			continue
-		}
-
-		// Check if the cache exists inside this node_modules
-		cachePath := filepath.Join(nmResult.Path, subPath)
-		if utils.PathExists(cachePath) {
-			size, _ := utils.GetDirSize(cachePath)
-			if size > 0 {
-				targets = append(targets, CleanTarget{
-					Path:        cachePath,
-					Description: filepath.Base(subPath) + " cache",
-					SizeBytes:   size,
-					Safety:      config.Safe,
-				})
-			}
-		}
-	}
-
-	return targets
-}
-
-// getDescriptionForPattern returns a human-readable description for a pattern
-func (f *FrontendCleaner) getDescriptionForPattern(pattern string) string {
-	descriptions := map[string]string{
-		"dist":              "Build output (dist)",
-		"build":             "Build output (build)",
-		"out":               "Build output (out)",
-		".next":             "Next.js build cache",
-		".vite":             "Vite cache",
-		".parcel-cache":     "Parcel cache",
-		"coverage":          "Test coverage reports",
-		".nyc_output":       "NYC coverage output",
-		".eslintcache":      "ESLint cache",
-		"storybook-static":  "Storybook static build",
-		"npm-debug.log*":    "npm debug logs",
-		"yarn-error.log*":   "Yarn error logs",
-		"yarn-debug.log*":   "Yarn debug logs",
-	}
-
-	if desc, ok := descriptions[pattern]; ok {
-		return desc
-	}
-
-	return pattern
-}
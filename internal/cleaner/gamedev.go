@@ -0,0 +1,210 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// GameDevCleaner handles game development cleanup (Unity, Unreal Engine)
+type GameDevCleaner struct {
+	scanner *scanner.Scanner
+}
+
+// gameProjectDir describes a regenerable folder inside a game project
+type gameProjectDir struct {
+	name        string
+	category    string
+	description string
+	safety      config.SafetyLevel
+}
+
+// unityProjectDirs are regenerated by the Unity editor when a project is
+// opened. The obj folder of script builds is left to the .NET cleaner, the
+// project holding the .csproj files Unity generates.
+var unityProjectDirs = []gameProjectDir{
+	{"Library", "unity_library", "Unity imported assets cache (Library)", config.Moderate},
+	{"Temp", "unity_temp", "Unity temporary files (Temp)", config.Safe},
+}
+
+// unrealProjectDirs are regenerated by Unreal Engine on the next build
+var unrealProjectDirs = []gameProjectDir{
+	{"Intermediate", "unreal_intermediate", "Unreal intermediate build files (Intermediate)", config.Safe},
+	{"DerivedDataCache", "unreal_ddc", "Unreal derived data cache (DerivedDataCache)", config.Moderate},
+}
+
+// NewGameDevCleaner creates a new GameDevCleaner
+func NewGameDevCleaner() (Cleaner, error) {
+	s, err := scanner.NewScanner()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GameDevCleaner{
+		scanner: s,
+	}, nil
+}
+
+func (g *GameDevCleaner) Name() string {
+	return "Game Dev"
+}
+
+func (g *GameDevCleaner) Domain() config.Domain {
+	return config.DomainGameDev
+}
+
+func (g *GameDevCleaner) Detect(ctx context.Context) (bool, error) {
+	det := detector.FromContext(ctx)
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, err
+	}
+
+	return det.HasPath(
+		"/Applications/Unity Hub.app",
+		"/Applications/Unity",
+		"/Users/Shared/Epic Games",
+		filepath.Join(home, "Library", "Unity"),
+		filepath.Join(home, "Library", "Application Support", "Epic"),
+	), nil
+}
+
+func (g *GameDevCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	configureScanner(g.scanner, cfg)
+
+	targets := []CleanTarget{}
+	home, err := cfg.HomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// === Unity ===
+
+	// Global caches and Unity Hub logs (Safe)
+	unityCaches := map[string]string{
+		filepath.Join(home, "Library", "Unity", "cache"):                          "Unity global cache",
+		filepath.Join(home, "Library", "Logs", "Unity"):                           "Unity editor logs",
+		filepath.Join(home, "Library", "Application Support", "UnityHub", "logs"): "Unity Hub logs",
+	}
+
+	for path, description := range unityCaches {
+		if utils.PathExists(path) {
+			size, _ := utils.GetDirSize(path)
+			if size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        path,
+					Category:    categoryName(description),
+					Description: description,
+					SizeBytes:   size,
+					Safety:      config.Safe,
+				})
+			}
+		}
+	}
+
+	// Project folders (Library is Moderate - reimporting a large project is slow)
+	for _, projectDir := range g.findProjects(ctx, "ProjectSettings", isUnityProject) {
+		targets = append(targets, gameProjectTargets(projectDir, unityProjectDirs, cfg)...)
+	}
+
+	// === Unreal Engine ===
+
+	// Shared derived data cache (Moderate - shaders are recompiled on next launch)
+	if cfg.Allows(config.DomainGameDev, "unreal_shared_ddc", config.Moderate) {
+		unrealDDCPath := filepath.Join(home, "Library", "Application Support", "Epic", "UnrealEngine", "Common", "DerivedDataCache")
+		if utils.PathExists(unrealDDCPath) {
+			size, _ := utils.GetDirSize(unrealDDCPath)
+			if size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        unrealDDCPath,
+					Category:    "unreal_shared_ddc",
+					Description: "Unreal shared derived data cache",
+					SizeBytes:   size,
+					Safety:      config.Moderate,
+				})
+			}
+		}
+	}
+
+	// Project folders
+	for _, projectDir := range g.findProjects(ctx, "*.uproject", nil) {
+		targets = append(targets, gameProjectTargets(projectDir, unrealProjectDirs, cfg)...)
+	}
+
+	return targets, nil
+}
+
+func (g *GameDevCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanTargets(ctx, targets, dryRun)
+}
+
+func (g *GameDevCleaner) Patterns() []string {
+	return []string{"ProjectSettings", "*.uproject"}
+}
+
+// findProjects returns the directories containing a marker matching pattern.
+// If isProject is set, it must also accept the directory.
+func (g *GameDevCleaner) findProjects(ctx context.Context, pattern string, isProject func(string) bool) []string {
+	projects := []string{}
+	seen := make(map[string]bool)
+
+	resultChan := g.scanner.FindByPattern(ctx, pattern)
+	for result := range resultChan {
+		if result.Err != nil {
+			continue
+		}
+
+		projectDir := filepath.Dir(result.Path)
+		key := utils.PathKey(projectDir, utils.IsCaseInsensitive(projectDir))
+		if seen[key] || (isProject != nil && !isProject(projectDir)) {
+			continue
+		}
+
+		seen[key] = true
+		projects = append(projects, projectDir)
+	}
+
+	return projects
+}
+
+// gameProjectTargets returns the regenerable folders of a game project that
+// are allowed by the clean level
+func gameProjectTargets(projectDir string, dirs []gameProjectDir, cfg *config.Config) []CleanTarget {
+	targets := []CleanTarget{}
+
+	for _, dir := range dirs {
+		if !cfg.Allows(config.DomainGameDev, dir.category, dir.safety) {
+			continue
+		}
+
+		path := filepath.Join(projectDir, dir.name)
+		if !utils.PathExists(path) {
+			continue
+		}
+
+		size, _ := utils.GetDirSize(path)
+		if size > 0 {
+			targets = append(targets, CleanTarget{
+				Path:        path,
+				Category:    dir.category,
+				Description: dir.description,
+				SizeBytes:   size,
+				Safety:      dir.safety,
+			})
+		}
+	}
+
+	return targets
+}
+
+// isUnityProject reports whether a directory is a Unity project, i.e. it
+// contains both Assets and ProjectSettings
+func isUnityProject(dir string) bool {
+	return utils.PathExists(filepath.Join(dir, "Assets")) &&
+		utils.PathExists(filepath.Join(dir, "ProjectSettings"))
+}
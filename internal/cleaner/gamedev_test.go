@@ -0,0 +1,96 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+func TestGameDevCleaner_UnityProjects(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	game := filepath.Join(tmpDir, "MyGame")
+	createTestFile(t, game, "Assets/Scenes/Main.unity", "scene")
+	createTestFile(t, game, "ProjectSettings/ProjectVersion.txt", "m_EditorVersion: 2022.3.10f1")
+	createTestFile(t, game, "Library/ArtifactDB", "artifacts")
+	createTestFile(t, game, "Temp/UnityLockfile", "lock")
+	createTestFile(t, game, "obj/Debug/Assembly-CSharp.dll", "dll")
+
+	// A ProjectSettings folder alone is not a Unity project
+	createTestFile(t, tmpDir, "other/ProjectSettings/settings.txt", "x")
+	createTestFile(t, tmpDir, "other/Library/data", "x")
+
+	s, _ := scanner.NewScannerWithDirs([]string{tmpDir})
+	g := &GameDevCleaner{scanner: s}
+
+	projects := g.findProjects(context.Background(), "ProjectSettings", isUnityProject)
+	if len(projects) != 1 || projects[0] != game {
+		t.Fatalf("Expected only %s, got %v", game, projects)
+	}
+
+	tests := []struct {
+		level    config.CleanLevel
+		expected int
+	}{
+		{config.Conservative, 1},
+		{config.Standard, 2},
+	}
+
+	for _, tt := range tests {
+		cfg := config.NewDefaultConfig()
+		cfg.CleanLevel = tt.level
+
+		targets := gameProjectTargets(game, unityProjectDirs, cfg)
+		if len(targets) != tt.expected {
+			t.Errorf("Level %v: expected %d targets, got %d", tt.level, tt.expected, len(targets))
+		}
+	}
+	// A category reclassified as Safe is scanned in conservative mode
+	safe := config.Safe
+	cfg := config.NewDefaultConfig()
+	cfg.CleanLevel = config.Conservative
+	cfg.Overrides = map[string]map[string]config.Override{
+		"gamedev": {"unity_library": {Safety: &safe}},
+	}
+
+	if targets := gameProjectTargets(game, unityProjectDirs, cfg); len(targets) != 2 {
+		t.Errorf("Expected overridden Library to be included, got %d targets", len(targets))
+	}
+}
+
+func TestGameDevCleaner_UnrealProjects(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	game := filepath.Join(tmpDir, "Shooter")
+	createTestFile(t, game, "Shooter.uproject", "{}")
+	createTestFile(t, game, "Intermediate/Build/Mac/obj.o", "obj")
+	createTestFile(t, game, "DerivedDataCache/shaders.ddp", "ddc")
+	createTestFile(t, game, "Content/Maps/Level.umap", "map")
+
+	s, _ := scanner.NewScannerWithDirs([]string{tmpDir})
+	g := &GameDevCleaner{scanner: s}
+
+	projects := g.findProjects(context.Background(), "*.uproject", nil)
+	if len(projects) != 1 || projects[0] != game {
+		t.Fatalf("Expected only %s, got %v", game, projects)
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.CleanLevel = config.Standard
+
+	targets := gameProjectTargets(game, unrealProjectDirs, cfg)
+	if len(targets) != 2 {
+		t.Fatalf("Expected 2 targets, got %d: %v", len(targets), targets)
+	}
+	for _, target := range targets {
+		if filepath.Base(target.Path) == "Content" {
+			t.Error("Content folder must never be a target")
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package cleaner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// gradleBuildCacheMaxAge is how old a local build cache entry must be before
+// it is considered stale
+const gradleBuildCacheMaxAge = 30 * 24 * time.Hour
+
+// gradleCacheEntryDepth is the level below ~/.gradle/caches at which the
+// cache is pruned entry by entry: caches/transforms-3/<hash>/...,
+// caches/modules-2/files-2.1/<group>, ...
+const gradleCacheEntryDepth = 3
+
+// gradleCacheTarget returns the Gradle cache, pruned by entry of each cache
+// (a transform, a group of downloaded modules, ...) if criteria are set. The
+// local build cache is left out: scanGradleExtras offers its stale entries.
+func gradleCacheTarget(dir string, criteria PruneCriteria) (CleanTarget, bool) {
+	buildCache := filepath.Join(dir, "build-cache-1")
+
+	entries := []string{}
+	description := "Gradle cache"
+	if criteria.Enabled() {
+		for _, entry := range pruneEntries(dir, gradleCacheEntryDepth, criteria, time.Now()) {
+			if !utils.HasPathPrefix(entry, buildCache) {
+				entries = append(entries, entry)
+			}
+		}
+		description += " " + criteria.String()
+	} else {
+		children, _ := os.ReadDir(dir)
+		for _, child := range children {
+			if path := filepath.Join(dir, child.Name()); path != buildCache {
+				entries = append(entries, path)
+			}
+		}
+	}
+	return entriesTarget(dir, entries, "gradle_cache", description, config.Safe)
+}
+
+// scanGradleExtras finds Gradle leftovers beyond the main caches folder:
+// daemon logs, wrapper distributions no project references anymore, and
+// stale local build cache entries
+func scanGradleExtras(ctx context.Context, s *scanner.Scanner, home string) []CleanTarget {
+	targets := []CleanTarget{}
+	gradleHome := filepath.Join(home, ".gradle")
+
+	if !utils.PathExists(gradleHome) {
+		return targets
+	}
+
+	// Daemon logs (Safe)
+	logs, _ := filepath.Glob(filepath.Join(gradleHome, "daemon", "*", "*.log"))
+	if target, ok := entriesTarget(filepath.Join(gradleHome, "daemon"), logs, "gradle_daemon_logs", "Gradle daemon logs", config.Safe); ok {
+		targets = append(targets, target)
+	}
+
+	// Wrapper distributions not referenced by any project (Safe - re-downloaded on demand)
+	distsPath := filepath.Join(gradleHome, "wrapper", "dists")
+	if utils.PathExists(distsPath) {
+		referenced := findGradleWrapperDists(ctx, s)
+
+		entries, _ := os.ReadDir(distsPath)
+		for _, entry := range entries {
+			if !entry.IsDir() || referenced[entry.Name()] {
+				continue
+			}
+
+			distPath := filepath.Join(distsPath, entry.Name())
+			size, _ := utils.GetDirSize(distPath)
+			if size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        distPath,
+					Category:    "gradle_wrapper_dists",
+					Description: fmt.Sprintf("Gradle wrapper distribution %s (unused)", entry.Name()),
+					SizeBytes:   size,
+					Safety:      config.Safe,
+				})
+			}
+		}
+	}
+
+	// Local build cache entries older than 30 days (Safe)
+	buildCachePath := filepath.Join(gradleHome, "caches", "build-cache-1")
+	if entries, err := os.ReadDir(buildCachePath); err == nil {
+		cutoff := time.Now().Add(-gradleBuildCacheMaxAge)
+		stale := []string{}
+
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || info.IsDir() || !info.ModTime().Before(cutoff) {
+				continue
+			}
+			stale = append(stale, filepath.Join(buildCachePath, entry.Name()))
+		}
+
+		if target, ok := entriesTarget(buildCachePath, stale, "gradle_build_cache", "Gradle build cache entries older than 30 days", config.Safe); ok {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// findGradleWrapperDists returns the wrapper distribution names (e.g.
+// "gradle-8.5-bin") referenced by gradle-wrapper.properties files in the
+// search directories
+func findGradleWrapperDists(ctx context.Context, s *scanner.Scanner) map[string]bool {
+	referenced := make(map[string]bool)
+
+	resultChan := s.FindByPattern(ctx, "gradle-wrapper.properties")
+	for result := range resultChan {
+		if result.Err != nil {
+			continue
+		}
+
+		if dist := parseGradleDistName(result.Path); dist != "" {
+			referenced[dist] = true
+		}
+	}
+
+	return referenced
+}
+
+// parseGradleDistName reads the distributionUrl from a wrapper properties
+// file and returns the distribution name Gradle stores it under
+func parseGradleDistName(propertiesPath string) string {
+	file, err := os.Open(propertiesPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	sc := bufio.NewScanner(file)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if !strings.HasPrefix(line, "distributionUrl") {
+			continue
+		}
+
+		idx := strings.Index(line, "=")
+		if idx < 0 {
+			continue
+		}
+
+		url := strings.ReplaceAll(strings.TrimSpace(line[idx+1:]), `\:`, ":")
+		name := url[strings.LastIndex(url, "/")+1:]
+		return strings.TrimSuffix(name, ".zip")
+	}
+
+	return ""
+}
+
+// entriesTarget builds a target that removes only the given entries inside
+// root. It returns false if there is nothing to remove.
+func entriesTarget(root string, entries []string, category, description string, safety config.SafetyLevel) (CleanTarget, bool) {
+	var size int64
+	for _, entry := range entries {
+		entrySize, _ := utils.GetDirSize(entry)
+		size += entrySize
+	}
+
+	if size == 0 {
+		return CleanTarget{}, false
+	}
+
+	return CleanTarget{
+		Path:        root,
+		Category:    category,
+		Description: fmt.Sprintf("%s (%d entries)", description, len(entries)),
+		SizeBytes:   size,
+		Safety:      safety,
+		Entries:     entries,
+	}, true
+}
@@ -0,0 +1,115 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+func TestParseGradleDistName(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	path := createTestFile(t, tmpDir, "gradle-wrapper.properties",
+		"distributionBase=GRADLE_USER_HOME\ndistributionUrl=https\\://services.gradle.org/distributions/gradle-8.5-bin.zip\n")
+
+	if got := parseGradleDistName(path); got != "gradle-8.5-bin" {
+		t.Errorf("parseGradleDistName() = %q, want %q", got, "gradle-8.5-bin")
+	}
+
+	if got := parseGradleDistName(filepath.Join(tmpDir, "missing")); got != "" {
+		t.Errorf("parseGradleDistName(missing) = %q, want empty", got)
+	}
+}
+
+func TestScanGradleExtras(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	projects := filepath.Join(home, "Projects")
+	createTestFile(t, projects, "app/gradle/wrapper/gradle-wrapper.properties",
+		"distributionUrl=https\\://services.gradle.org/distributions/gradle-8.5-bin.zip\n")
+
+	createTestFile(t, home, ".gradle/wrapper/dists/gradle-8.5-bin/abc/gradle.zip", "in use")
+	createTestFile(t, home, ".gradle/wrapper/dists/gradle-7.0-all/def/gradle.zip", "unused")
+	createTestFile(t, home, ".gradle/daemon/8.5/daemon-123.out.log", "log")
+	oldEntry := createTestFile(t, home, ".gradle/caches/build-cache-1/oldentry", "old")
+	createTestFile(t, home, ".gradle/caches/build-cache-1/newentry", "new")
+
+	old := time.Now().AddDate(0, 0, -45)
+	os.Chtimes(oldEntry, old, old)
+
+	s, _ := scanner.NewScannerWithDirs([]string{projects})
+	targets := scanGradleExtras(ctx, s, home)
+
+	found := map[string]CleanTarget{}
+	for _, target := range targets {
+		found[target.Path] = target
+	}
+
+	if _, ok := found[filepath.Join(home, ".gradle/wrapper/dists/gradle-7.0-all")]; !ok {
+		t.Error("Expected unused wrapper distribution to be a target")
+	}
+	if _, ok := found[filepath.Join(home, ".gradle/wrapper/dists/gradle-8.5-bin")]; ok {
+		t.Error("Referenced wrapper distribution should not be a target")
+	}
+	if target, ok := found[filepath.Join(home, ".gradle/daemon")]; !ok || len(target.Entries) != 1 {
+		t.Errorf("Expected daemon logs target with 1 entry, got %+v", target)
+	}
+
+	buildCache, ok := found[filepath.Join(home, ".gradle/caches/build-cache-1")]
+	if !ok {
+		t.Fatal("Expected stale build cache target")
+	}
+	if len(buildCache.Entries) != 1 || buildCache.Entries[0] != oldEntry {
+		t.Errorf("Expected only the old build cache entry, got %v", buildCache.Entries)
+	}
+}
+
+func TestScanGradleExtras_NoGradleHome(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	s, _ := scanner.NewScannerWithDirs([]string{})
+	if targets := scanGradleExtras(context.Background(), s, home); len(targets) != 0 {
+		t.Errorf("Expected no targets without ~/.gradle, got %d", len(targets))
+	}
+}
+
+func TestGradleCacheTarget(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	caches := filepath.Join(home, ".gradle", "caches")
+	createTestFile(t, caches, "modules-2/files-2.1/com.google/a.jar", "jar")
+	createTestFile(t, caches, "build-cache-1/entry", "entry")
+	old := time.Now().AddDate(0, 0, -45)
+	for _, path := range []string{"modules-2/files-2.1/com.google/a.jar", "build-cache-1/entry"} {
+		os.Chtimes(filepath.Join(caches, path), old, old)
+	}
+
+	// The build cache is scanGradleExtras' own target, whether the cache is
+	// cleaned wholesale or pruned
+	for _, criteria := range []PruneCriteria{{}, {MaxAge: 30 * 24 * time.Hour}} {
+		target, ok := gradleCacheTarget(caches, criteria)
+		if !ok {
+			t.Fatalf("Expected a Gradle cache target with criteria %+v", criteria)
+		}
+		for _, entry := range target.Entries {
+			if strings.Contains(entry, "build-cache-1") {
+				t.Errorf("Expected the build cache left out with criteria %+v, got %v", criteria, target.Entries)
+			}
+		}
+		if target.SizeBytes != int64(len("jar")) {
+			t.Errorf("Expected the size of the modules only, got %d", target.SizeBytes)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+)
+
+// TestScan_FakeHome points every cleaner at a fake home directory and checks
+// that each finds the caches and project folders laid out in it, and that
+// nothing is found in the real home directory
+func TestScan_FakeHome(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	// Environment variables that would send cleaners elsewhere
+	for _, name := range []string{"PUB_CACHE", "LIMA_HOME", "XDG_CACHE_HOME", "XDG_DATA_HOME", "SCIKIT_LEARN_DATA", "NLTK_DATA"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("TMPDIR", filepath.Join(home, "tmp"))
+
+	createTestFile(t, home, ".npm/_cacache/index-v5/00/entry", "npm")
+	createTestFile(t, home, "Projects/web/package.json", "{}")
+	createTestFile(t, home, "Projects/web/node_modules/react/index.js", "react")
+	// ~/Library/Caches/pip on macOS, ~/.cache/pip on Linux
+	pip, _ := filepath.Rel(home, pipCacheDir(platform.Current(), home))
+	createTestFile(t, home, filepath.Join(pip, "http", "entry"), "pip")
+	createTestFile(t, home, "Projects/api/__pycache__/app.cpython-312.pyc", "bytecode")
+	createTestFile(t, home, "Library/Developer/Xcode/DerivedData/App-abc/Build/app", "build")
+	createTestFile(t, home, ".keras/datasets/mnist.npz", "mnist")
+	createTestFile(t, home, ".kube/cache/discovery/index", "discovery")
+	createTestFile(t, home, "Projects/infra/.terraform/modules/modules.json", "{}")
+	// ~/.Trash on macOS, ~/.local/share/Trash on Linux
+	trash, _ := filepath.Rel(home, platform.TrashDir(home))
+	createTestFile(t, home, filepath.Join(trash, "old.txt"), "trash")
+
+	cfg := config.NewDefaultConfig()
+	cfg.Home = home
+
+	newFrontend, _ := NewFrontendCleaner()
+	newBackend, _ := NewBackendCleaner()
+	newMobile, _ := NewMobileCleaner()
+	newDataML, _ := NewDataMLCleaner()
+	newDevOps, _ := NewDevOpsCleaner()
+
+	tests := []struct {
+		cleaner  Cleaner
+		expected map[string]string // Path relative to home to category
+	}{
+		{newFrontend, map[string]string{
+			".npm":                      "npm_cache",
+			"Projects/web/node_modules": "node_modules",
+		}},
+		{newBackend, map[string]string{
+			pip:                        "pip_cache",
+			"Projects/api/__pycache__": "pycache",
+		}},
+		{newMobile, map[string]string{
+			"Library/Developer/Xcode/DerivedData": "xcode_derived_data",
+		}},
+		{newDataML, map[string]string{
+			".keras/datasets": "keras_datasets",
+		}},
+		{newDevOps, map[string]string{
+			".kube/cache":               "kube_cache",
+			"Projects/infra/.terraform": "terraform",
+		}},
+		{NewTrashCleaner(), map[string]string{
+			trash: "trash",
+		}},
+	}
+
+	realHome, _ := os.UserHomeDir()
+
+	for _, tt := range tests {
+		t.Run(tt.cleaner.Name(), func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+			defer cancel()
+
+			targets, err := tt.cleaner.Scan(ctx, cfg)
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+
+			found := make(map[string]string)
+			for _, target := range targets {
+				found[target.Path] = target.Category
+				if realHome != "" && !strings.HasPrefix(home, realHome) && strings.HasPrefix(target.Path, realHome+string(filepath.Separator)) {
+					t.Errorf("Target %s is in the real home directory", target.Path)
+				}
+			}
+
+			for rel, category := range tt.expected {
+				path := filepath.Join(home, rel)
+				if got, ok := found[path]; !ok {
+					t.Errorf("Expected target %s, got %v", rel, targets)
+				} else if got != category {
+					t.Errorf("Expected category %q for %s, got %q", category, rel, got)
+				}
+			}
+		})
+	}
+}
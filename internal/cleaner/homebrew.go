@@ -0,0 +1,216 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// brewInstalls is what Homebrew has installed, from brew list and brew
+// outdated
+type brewInstalls struct {
+	formulae map[string][]string // Installed versions by formula
+	casks    map[string][]string // Installed versions by cask
+	upgrades map[string]string   // Version an outdated formula or cask upgrades to
+	pinned   map[string]bool     // Formulae brew upgrade and brew cleanup leave alone
+}
+
+// brewOutdated is the part of `brew outdated --json=v2` read here
+type brewOutdated struct {
+	Formulae []struct {
+		Name           string `json:"name"`
+		CurrentVersion string `json:"current_version"`
+	} `json:"formulae"`
+	Casks []struct {
+		Name           string `json:"name"`
+		CurrentVersion string `json:"current_version"`
+	} `json:"casks"`
+}
+
+// brewInstalled lists what Homebrew has installed. It fails if the formulae
+// can't be listed; the other lists refine the candidates and may be missing.
+func brewInstalled(runner CommandRunner) (*brewInstalls, error) {
+	output, err := runner.Output("brew", "list", "--formula", "--versions")
+	if err != nil {
+		return nil, err
+	}
+	installs := &brewInstalls{
+		formulae: parseBrewVersions(string(output)),
+		casks:    map[string][]string{},
+		upgrades: map[string]string{},
+		pinned:   map[string]bool{},
+	}
+
+	if output, err := runner.Output("brew", "list", "--cask", "--versions"); err == nil {
+		installs.casks = parseBrewVersions(string(output))
+	}
+	if output, err := runner.Output("brew", "list", "--pinned"); err == nil {
+		for _, name := range strings.Fields(string(output)) {
+			installs.pinned[name] = true
+		}
+	}
+
+	var outdated brewOutdated
+	if output, err := runner.Output("brew", "outdated", "--json=v2"); err == nil && json.Unmarshal(output, &outdated) == nil {
+		for _, formula := range outdated.Formulae {
+			installs.upgrades[formula.Name] = formula.CurrentVersion
+		}
+		for _, cask := range outdated.Casks {
+			installs.upgrades[cask.Name] = cask.CurrentVersion
+		}
+	}
+
+	return installs, nil
+}
+
+// parseBrewVersions parses `brew list --versions`: one package per line,
+// followed by its installed versions
+func parseBrewVersions(output string) map[string][]string {
+	versions := make(map[string][]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			versions[fields[0]] = append(versions[fields[0]], fields[1:]...)
+		}
+	}
+	return versions
+}
+
+// wanted reports whether a download of version of a package is still useful:
+// it is installed, or is the version the package upgrades to
+func (b *brewInstalls) wanted(versions map[string][]string, name, rest string) bool {
+	installed, ok := versions[name]
+	if !ok {
+		return false
+	}
+	// rest is the version followed by the bottle tag and extension, e.g.
+	// 1.21.4.arm64_sonoma.bottle.tar.gz
+	matches := func(version string) bool {
+		return version != "" && (rest == version || strings.HasPrefix(rest, version+"."))
+	}
+	for _, version := range installed {
+		if matches(version) {
+			return true
+		}
+	}
+	return matches(b.upgrades[name])
+}
+
+// staleBrewDownloads returns the downloads of the Homebrew cache that belong
+// to no installed formula or cask version, with the links pointing to them.
+// Downloads are named <sha256>--<formula>--<version>...; casks are only
+// known from the links of the Cask folder, named <cask>--<version>...
+func staleBrewDownloads(cacheDir string, installs *brewInstalls) ([]string, int64) {
+	stale := make(map[string]bool)
+
+	downloads := filepath.Join(cacheDir, "downloads")
+	entries, _ := os.ReadDir(downloads)
+	for _, entry := range entries {
+		parts := strings.SplitN(entry.Name(), "--", 3)
+		if len(parts) != 3 || entry.IsDir() || strings.HasSuffix(entry.Name(), ".incomplete") {
+			continue
+		}
+		if !installs.wanted(installs.formulae, parts[1], parts[2]) {
+			stale[filepath.Join(downloads, entry.Name())] = true
+		}
+	}
+
+	// Links into the downloads go with them
+	var links []string
+	for _, dir := range []string{cacheDir, filepath.Join(cacheDir, "Cask")} {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if entry.Type()&os.ModeSymlink == 0 {
+				continue
+			}
+			link := filepath.Join(dir, entry.Name())
+			target, err := os.Readlink(link)
+			if err != nil {
+				continue
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(dir, target)
+			}
+			target = filepath.Clean(target)
+
+			if stale[target] {
+				links = append(links, link)
+				continue
+			}
+			if dir != cacheDir {
+				parts := strings.SplitN(entry.Name(), "--", 2)
+				if len(parts) == 2 && !installs.wanted(installs.casks, parts[0], parts[1]) && utils.PathExists(target) {
+					stale[target] = true
+					links = append(links, link)
+				}
+			}
+		}
+	}
+
+	var paths []string
+	var size int64
+	for path := range stale {
+		if info, err := os.Lstat(path); err == nil {
+			paths = append(paths, path)
+			size += info.Size()
+		}
+	}
+	sort.Strings(paths)
+	sort.Strings(links)
+	return append(paths, links...), size
+}
+
+// brewKegTargets returns the kegs of the Cellar no installed formula uses:
+// older versions of a formula linked to another one (Safe, what brew cleanup
+// removes), and the kegs of formulae with no opt link at all, which nothing
+// can run (Moderate). Pinned formulae are left alone.
+func brewKegTargets(cellar, prefix string, installs *brewInstalls) []CleanTarget {
+	targets := []CleanTarget{}
+
+	names := make([]string, 0, len(installs.formulae))
+	for name := range installs.formulae {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if installs.pinned[name] {
+			continue
+		}
+
+		active := ""
+		if target, err := os.Readlink(filepath.Join(prefix, "opt", name)); err == nil {
+			active = filepath.Base(target)
+		}
+
+		for _, version := range installs.formulae[name] {
+			if version == active {
+				continue
+			}
+			keg := filepath.Join(cellar, name, version)
+			if info, err := os.Stat(keg); err != nil || !info.IsDir() {
+				continue
+			}
+			size, _ := utils.GetDirSize(keg)
+
+			target := CleanTarget{Path: keg, SizeBytes: size}
+			if active != "" {
+				target.Category = "homebrew_old_kegs"
+				target.Description = "Homebrew " + name + " " + version + " (replaced by " + active + ")"
+				target.Safety = config.Safe
+			} else {
+				target.Category = "homebrew_unlinked_kegs"
+				target.Description = "Homebrew " + name + " " + version + " (unlinked)"
+				target.Safety = config.Moderate
+			}
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
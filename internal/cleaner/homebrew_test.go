@@ -0,0 +1,125 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// setupHomebrew creates a Homebrew cache, Cellar and prefix, and the runner
+// answering brew's queries about them
+func setupHomebrew(t *testing.T, tmpDir string) *RecordingRunner {
+	t.Helper()
+	cache := filepath.Join(tmpDir, "cache")
+	prefix := filepath.Join(tmpDir, "prefix")
+	cellar := filepath.Join(prefix, "Cellar")
+
+	// wget 1.21.3 and 1.21.4 are installed, git 2.43.0 upgrades to 2.44.0
+	createTestFile(t, cache, "downloads/aaa--wget--1.21.4.arm64_sonoma.bottle.tar.gz", "current")
+	createTestFile(t, cache, "downloads/bbb--wget--1.21.2.arm64_sonoma.bottle.tar.gz", "old")
+	createTestFile(t, cache, "downloads/ccc--oldtool--2.0.arm64_sonoma.bottle.tar.gz", "uninstalled")
+	createTestFile(t, cache, "downloads/ddd--git--2.44.0.arm64_sonoma.bottle.tar.gz", "upgrade")
+	createTestFile(t, cache, "downloads/eee--Firefox 120.0.dmg", "cask")
+	createTestFile(t, cache, "downloads/fff--Rectangle.dmg", "cask")
+	for link, target := range map[string]string{
+		"oldtool--2.0.arm64_sonoma.bottle.tar.gz": "downloads/ccc--oldtool--2.0.arm64_sonoma.bottle.tar.gz",
+		"wget--1.21.4.arm64_sonoma.bottle.tar.gz": "downloads/aaa--wget--1.21.4.arm64_sonoma.bottle.tar.gz",
+		"Cask/firefox--120.0.dmg":                 "../downloads/eee--Firefox 120.0.dmg",
+		"Cask/rectangle--0.77.dmg":                "../downloads/fff--Rectangle.dmg",
+	} {
+		os.MkdirAll(filepath.Dir(filepath.Join(cache, link)), 0755)
+		if err := os.Symlink(target, filepath.Join(cache, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// wget has an old keg, jq is not linked, node is pinned
+	for _, keg := range []string{"wget/1.21.3", "wget/1.21.4", "git/2.43.0", "jq/1.7", "node/20.1.0", "node/21.0.0"} {
+		createTestFile(t, cellar, keg+"/bin/tool", "binary")
+	}
+	os.MkdirAll(filepath.Join(prefix, "opt"), 0755)
+	for name, version := range map[string]string{"wget": "1.21.4", "git": "2.43.0", "node": "20.1.0"} {
+		if err := os.Symlink("../Cellar/"+name+"/"+version, filepath.Join(prefix, "opt", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return &RecordingRunner{Outputs: map[string]string{
+		"brew --cache":                   cache + "\n",
+		"brew --cellar":                  cellar + "\n",
+		"brew --prefix":                  prefix + "\n",
+		"brew list --formula --versions": "wget 1.21.3 1.21.4\ngit 2.43.0\njq 1.7\nnode 20.1.0 21.0.0\n",
+		"brew list --cask --versions":    "rectangle 0.77\n",
+		"brew list --pinned":             "node\n",
+		"brew outdated --json=v2":        `{"formulae": [{"name": "git", "installed_versions": ["2.43.0"], "current_version": "2.44.0"}], "casks": []}`,
+	}}
+}
+
+func TestSystemCleaner_Homebrew_Leftovers(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	runner := setupHomebrew(t, tmpDir)
+	c := &SystemCleaner{cleanerType: TypeHomebrew}
+	c.SetRunner(runner)
+
+	targets, err := c.scanHomebrew()
+	if err != nil {
+		t.Fatalf("scanHomebrew() returned error: %v", err)
+	}
+
+	byCategory := make(map[string][]CleanTarget)
+	for _, target := range targets {
+		byCategory[target.Category] = append(byCategory[target.Category], target)
+	}
+
+	// Old and uninstalled bottles, the uninstalled cask, and their links
+	cache := filepath.Join(tmpDir, "cache")
+	downloads := byCategory["homebrew_stale_downloads"]
+	if len(downloads) != 1 {
+		t.Fatalf("Expected one stale downloads target, got %+v", targets)
+	}
+	expected := []string{
+		filepath.Join(cache, "downloads/bbb--wget--1.21.2.arm64_sonoma.bottle.tar.gz"),
+		filepath.Join(cache, "downloads/ccc--oldtool--2.0.arm64_sonoma.bottle.tar.gz"),
+		filepath.Join(cache, "downloads/eee--Firefox 120.0.dmg"),
+		filepath.Join(cache, "Cask/firefox--120.0.dmg"),
+		filepath.Join(cache, "oldtool--2.0.arm64_sonoma.bottle.tar.gz"),
+	}
+	if !slices.Equal(downloads[0].Entries, expected) {
+		t.Errorf("Expected entries %v, got %v", expected, downloads[0].Entries)
+	}
+	if downloads[0].SizeBytes != int64(len("old")+len("uninstalled")+len("cask")) {
+		t.Errorf("Unexpected size %d", downloads[0].SizeBytes)
+	}
+
+	// The pinned formula's old keg is left alone
+	old := byCategory["homebrew_old_kegs"]
+	if len(old) != 1 || old[0].Path != filepath.Join(tmpDir, "prefix/Cellar/wget/1.21.3") {
+		t.Errorf("Expected the old wget keg, got %+v", old)
+	}
+	unlinked := byCategory["homebrew_unlinked_kegs"]
+	if len(unlinked) != 1 || unlinked[0].Path != filepath.Join(tmpDir, "prefix/Cellar/jq/1.7") {
+		t.Errorf("Expected the unlinked jq keg, got %+v", unlinked)
+	}
+	if _, ok := byCategory["homebrew_cache"]; ok {
+		t.Error("The whole cache should not be offered when brew lists what is installed")
+	}
+
+	// Removed directly, without brew cleanup
+	results, _ := c.Clean(context.Background(), downloads, false)
+	if !results[0].Success {
+		t.Errorf("Clean() failed: %v", results[0].Error)
+	}
+	if commands := runner.Commands(); len(commands) != 0 {
+		t.Errorf("Expected no command to run, got %v", commands)
+	}
+	if _, err := os.Stat(filepath.Join(cache, "downloads/aaa--wget--1.21.4.arm64_sonoma.bottle.tar.gz")); err != nil {
+		t.Error("The installed bottle should be kept")
+	}
+	if _, err := os.Lstat(filepath.Join(cache, "oldtool--2.0.arm64_sonoma.bottle.tar.gz")); !os.IsNotExist(err) {
+		t.Error("The link to a removed bottle should be removed")
+	}
+}
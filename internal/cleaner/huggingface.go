@@ -0,0 +1,166 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// hfIncompleteGrace is how long an incomplete download is left alone, since
+// it may still be in progress
+const hfIncompleteGrace = time.Hour
+
+// hfRepo is a single model, dataset or space in the Hugging Face hub cache
+type hfRepo struct {
+	path     string    // e.g. ~/.cache/huggingface/hub/models--org--name
+	kind     string    // "model", "dataset" or "space"
+	name     string    // e.g. "org/name"
+	size     int64     // Total size on disk
+	lastUsed time.Time // Latest blob access or ref update
+}
+
+// scanHuggingFaceCache lists the Hugging Face cache as individual targets:
+// one per hub repo not used within maxAgeDays, plus lock files, incomplete
+// downloads and the non-hub caches (datasets, xet). Auth tokens stored next
+// to the caches are never touched.
+func scanHuggingFaceCache(hfCachePath string, maxAgeDays int) []CleanTarget {
+	targets := []CleanTarget{}
+	hubPath := filepath.Join(hfCachePath, "hub")
+	cutoff := time.Now().AddDate(0, 0, -maxAgeDays)
+
+	// Hub repos not used recently (Safe - re-downloaded on demand)
+	for _, repo := range listHFRepos(hubPath) {
+		if repo.size == 0 || (maxAgeDays > 0 && !repo.lastUsed.Before(cutoff)) {
+			continue
+		}
+
+		targets = append(targets, CleanTarget{
+			Path:        repo.path,
+			Category:    "huggingface_repos",
+			Description: repo.describe(),
+			SizeBytes:   repo.size,
+			Safety:      config.Safe,
+		})
+	}
+
+	// Lock files and abandoned downloads (Safe)
+	if target, ok := entriesTarget(hubPath, hfLeftovers(hubPath), "huggingface_leftovers", "Hugging Face lock files and incomplete downloads", config.Safe); ok {
+		targets = append(targets, target)
+	}
+
+	// Other caches next to the hub, e.g. datasets (Safe)
+	others := []string{}
+	entries, _ := os.ReadDir(hfCachePath)
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != "hub" {
+			others = append(others, filepath.Join(hfCachePath, entry.Name()))
+		}
+	}
+	if target, ok := entriesTarget(hfCachePath, others, "huggingface_other_caches", "Hugging Face datasets and other caches", config.Safe); ok {
+		targets = append(targets, target)
+	}
+
+	return targets
+}
+
+// listHFRepos returns the repos in a hub cache, least recently used first
+func listHFRepos(hubPath string) []hfRepo {
+	repos := []hfRepo{}
+
+	entries, err := os.ReadDir(hubPath)
+	if err != nil {
+		return repos
+	}
+
+	for _, entry := range entries {
+		parts := strings.Split(entry.Name(), "--")
+		if !entry.IsDir() || len(parts) < 2 {
+			continue
+		}
+
+		kind := strings.TrimSuffix(parts[0], "s")
+		if kind != "model" && kind != "dataset" && kind != "space" {
+			continue
+		}
+
+		path := filepath.Join(hubPath, entry.Name())
+		size, _ := utils.GetDirSize(path)
+
+		repos = append(repos, hfRepo{
+			path:     path,
+			kind:     kind,
+			name:     strings.Join(parts[1:], "/"),
+			size:     size,
+			lastUsed: hfRepoLastUsed(path),
+		})
+	}
+
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].lastUsed.Before(repos[j].lastUsed)
+	})
+
+	return repos
+}
+
+// hfRepoLastUsed returns the most recent access time of the repo's blobs or
+// modification time of its refs (updated on each download)
+func hfRepoLastUsed(repoPath string) time.Time {
+	var lastUsed time.Time
+
+	for _, sub := range []string{"blobs", "refs"} {
+		filepath.Walk(filepath.Join(repoPath, sub), func(_ string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+
+			if info.ModTime().After(lastUsed) {
+				lastUsed = info.ModTime()
+			}
+			if sub == "blobs" {
+				if accessed := utils.AccessTime(info); accessed.After(lastUsed) {
+					lastUsed = accessed
+				}
+			}
+			return nil
+		})
+	}
+
+	return lastUsed
+}
+
+// hfLeftovers returns the lock files and the incomplete downloads that are no
+// longer being written to
+func hfLeftovers(hubPath string) []string {
+	leftovers := []string{}
+
+	locks, _ := os.ReadDir(filepath.Join(hubPath, ".locks"))
+	for _, lock := range locks {
+		leftovers = append(leftovers, filepath.Join(hubPath, ".locks", lock.Name()))
+	}
+
+	incomplete, _ := filepath.Glob(filepath.Join(hubPath, "*", "blobs", "*.incomplete"))
+	cutoff := time.Now().Add(-hfIncompleteGrace)
+	for _, path := range incomplete {
+		if info, err := os.Stat(path); err == nil && info.ModTime().Before(cutoff) {
+			leftovers = append(leftovers, path)
+		}
+	}
+
+	return leftovers
+}
+
+// describe returns a human-readable description of the repo
+func (r hfRepo) describe() string {
+	if r.lastUsed.IsZero() {
+		return fmt.Sprintf("Hugging Face %s %s", r.kind, r.name)
+	}
+
+	days := int(time.Since(r.lastUsed).Hours() / 24)
+	return fmt.Sprintf("Hugging Face %s %s (last used %d days ago)", r.kind, r.name, days)
+}
@@ -0,0 +1,88 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScanHuggingFaceCache(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	hub := filepath.Join(tmpDir, "hub")
+	createTestFile(t, hub, "models--meta-llama--Llama-2-7b/blobs/aaaa", "old weights")
+	createTestFile(t, hub, "models--meta-llama--Llama-2-7b/refs/main", "abc")
+	createTestFile(t, hub, "models--bert-base-uncased/blobs/bbbb", "recent weights")
+	createTestFile(t, hub, "models--bert-base-uncased/refs/main", "def")
+	createTestFile(t, hub, "models--bert-base-uncased/blobs/cccc.incomplete", "partial")
+	createTestFile(t, hub, ".locks/models--bert-base-uncased/bbbb.lock", "")
+	createTestFile(t, tmpDir, "datasets/squad/data.arrow", "rows")
+	createTestFile(t, tmpDir, "token", "hf_secret")
+
+	old := time.Now().AddDate(0, 0, -90)
+	setOldTimes(t, filepath.Join(hub, "models--meta-llama--Llama-2-7b"), old)
+	setOldTimes(t, filepath.Join(hub, "models--bert-base-uncased", "blobs", "cccc.incomplete"), old)
+
+	targets := scanHuggingFaceCache(tmpDir, 30)
+
+	byPath := make(map[string]CleanTarget)
+	for _, target := range targets {
+		byPath[target.Path] = target
+	}
+
+	if _, ok := byPath[filepath.Join(hub, "models--meta-llama--Llama-2-7b")]; !ok {
+		t.Error("Expected the stale model to be a target")
+	}
+	if _, ok := byPath[filepath.Join(hub, "models--bert-base-uncased")]; ok {
+		t.Error("Recently used model should not be a target")
+	}
+
+	leftovers, ok := byPath[hub]
+	if !ok {
+		t.Fatal("Expected a lock files and incomplete downloads target")
+	}
+	if len(leftovers.Entries) != 2 {
+		t.Errorf("Expected 2 leftover entries, got %v", leftovers.Entries)
+	}
+
+	others, ok := byPath[tmpDir]
+	if !ok {
+		t.Fatal("Expected a target for the non-hub caches")
+	}
+	for _, entry := range others.Entries {
+		if filepath.Base(entry) == "token" || filepath.Base(entry) == "hub" {
+			t.Errorf("Unexpected entry %s", entry)
+		}
+	}
+
+	// With no age limit every model is listed
+	all := scanHuggingFaceCache(tmpDir, 0)
+	if len(all) != 4 {
+		t.Errorf("Expected 4 targets without age limit, got %d", len(all))
+	}
+}
+
+func TestListHFRepos(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "models--org--model/blobs/a", "x")
+	createTestFile(t, tmpDir, "datasets--org--data/blobs/b", "x")
+	createTestFile(t, tmpDir, "version.txt", "1")
+	createTestFile(t, tmpDir, "unknown--thing/blobs/c", "x")
+
+	repos := listHFRepos(tmpDir)
+	if len(repos) != 2 {
+		t.Fatalf("Expected 2 repos, got %d: %v", len(repos), repos)
+	}
+
+	names := map[string]string{}
+	for _, repo := range repos {
+		names[repo.name] = repo.kind
+	}
+	if names["org/model"] != "model" || names["org/data"] != "dataset" {
+		t.Errorf("Unexpected repos: %v", names)
+	}
+}
@@ -0,0 +1,62 @@
+package cleaner
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// Claim is a target of a scan that concerns an inspected path
+type Claim struct {
+	Cleaner string
+	Target  CleanTarget
+	Covers  bool // The target is or contains the path; otherwise it lies inside it
+}
+
+// FindClaims returns the targets of a scan, by cleaner name, that are the
+// path, contain it, or lie inside it. A target covers the path through one
+// of its entries too. The targets covering the path come first, then the
+// largest.
+func FindClaims(targetsByCleaner map[string][]CleanTarget, path string) []Claim {
+	path = filepath.Clean(path)
+	claims := []Claim{}
+
+	for name, targets := range targetsByCleaner {
+		for _, target := range targets {
+			if !filepath.IsAbs(target.Path) {
+				continue
+			}
+			switch {
+			case covers(target, path):
+				claims = append(claims, Claim{Cleaner: name, Target: target, Covers: true})
+			case utils.HasPathPrefix(target.Path, path):
+				claims = append(claims, Claim{Cleaner: name, Target: target})
+			}
+		}
+	}
+
+	sort.SliceStable(claims, func(i, j int) bool {
+		if claims[i].Covers != claims[j].Covers {
+			return claims[i].Covers
+		}
+		if claims[i].Target.SizeBytes != claims[j].Target.SizeBytes {
+			return claims[i].Target.SizeBytes > claims[j].Target.SizeBytes
+		}
+		return claims[i].Target.Path < claims[j].Target.Path
+	})
+	return claims
+}
+
+// covers reports whether cleaning target removes path
+func covers(target CleanTarget, path string) bool {
+	if len(target.Entries) == 0 {
+		return utils.HasPathPrefix(path, target.Path)
+	}
+	for _, entry := range target.Entries {
+		if utils.HasPathPrefix(path, entry) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package cleaner
+
+import "testing"
+
+func TestFindClaims(t *testing.T) {
+	targetsByCleaner := map[string][]CleanTarget{
+		"Frontend": {
+			{Path: "/work/app/node_modules", Category: "node_modules", SizeBytes: 100},
+			{Path: "/work/app/node_modules/.cache", Category: "build_cache", SizeBytes: 10},
+			{Path: "/work/other/node_modules", Category: "node_modules", SizeBytes: 500},
+		},
+		"System": {
+			{Path: "/cache", Category: "user_caches", SizeBytes: 50, Entries: []string{"/cache/a", "/cache/b"}},
+		},
+		"DevOps": {
+			{Path: "docker:images", Category: "docker_images", SizeBytes: 1000},
+		},
+	}
+
+	claims := FindClaims(targetsByCleaner, "/work/app/node_modules/.cache/babel")
+	if len(claims) != 2 || !claims[0].Covers || !claims[1].Covers {
+		t.Fatalf("Expected the two targets covering the path, got %+v", claims)
+	}
+	if claims[0].Target.Path != "/work/app/node_modules" || claims[0].Cleaner != "Frontend" {
+		t.Errorf("Expected the largest target first, got %+v", claims[0])
+	}
+
+	claims = FindClaims(targetsByCleaner, "/work")
+	if len(claims) != 3 {
+		t.Fatalf("Expected the 3 targets inside /work, got %+v", claims)
+	}
+	if claims[0].Covers || claims[0].Target.Path != "/work/other/node_modules" {
+		t.Errorf("Expected targets inside the path, largest first, got %+v", claims[0])
+	}
+
+	if claims := FindClaims(targetsByCleaner, "/cache/b/file"); len(claims) != 1 || !claims[0].Covers {
+		t.Errorf("Expected the entry to cover the path, got %+v", claims)
+	}
+	if claims := FindClaims(targetsByCleaner, "/cache/c"); len(claims) != 0 {
+		t.Errorf("Expected no claim outside the entries, got %+v", claims)
+	}
+}
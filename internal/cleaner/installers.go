@@ -0,0 +1,126 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// InstallersCleaner finds disk images, installer packages and Xcode
+// archives left in ~/Downloads and on the Desktop once the app they carried
+// is installed. A single Xcode .xip takes over 30 GB. Each file is its own
+// Moderate target, and files used within the configured age are left alone.
+type InstallersCleaner struct{}
+
+// installerKinds are the extensions of installer files, with what they are
+var installerKinds = map[string]string{
+	".dmg": "Disk image",
+	".iso": "Disk image",
+	".pkg": "Installer package",
+	".xip": "Xcode archive",
+}
+
+// NewInstallersCleaner creates a new InstallersCleaner
+func NewInstallersCleaner() Cleaner {
+	return &InstallersCleaner{}
+}
+
+func (i *InstallersCleaner) Name() string {
+	return "Installers"
+}
+
+func (i *InstallersCleaner) Domain() config.Domain {
+	return config.DomainSystem
+}
+
+func (i *InstallersCleaner) Detect(ctx context.Context) (bool, error) {
+	det := detector.FromContext(ctx)
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, err
+	}
+
+	for _, dir := range installerDirs(home) {
+		if det.HasPath(dir) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (i *InstallersCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	if !cfg.Allows(config.DomainSystem, "installers", config.Moderate) {
+		return []CleanTarget{}, nil
+	}
+
+	home, err := cfg.HomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanInstallers(installerDirs(home), cfg.InstallerMaxAgeDays, time.Now()), nil
+}
+
+func (i *InstallersCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanTargets(ctx, targets, dryRun)
+}
+
+// installerDirs returns the folders downloads usually end up in
+func installerDirs(home string) []string {
+	return []string{filepath.Join(home, "Downloads"), filepath.Join(home, "Desktop")}
+}
+
+// scanInstallers returns one target per installer file directly in dirs not
+// used for maxAgeDays, largest first. Installer packages may be bundles
+// (folders), which count as one file.
+func scanInstallers(dirs []string, maxAgeDays int, now time.Time) []CleanTarget {
+	targets := []CleanTarget{}
+	cutoff := now.AddDate(0, 0, -maxAgeDays)
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			kind, ok := installerKinds[strings.ToLower(filepath.Ext(entry.Name()))]
+			if !ok || utils.IsDatalessEntry(entry) {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			lastUsed := lastUsedTime(path)
+			if lastUsed.After(cutoff) {
+				continue
+			}
+
+			size, _ := utils.GetDirSize(path)
+			if size == 0 {
+				continue
+			}
+
+			days := int(now.Sub(lastUsed).Hours() / 24)
+			targets = append(targets, CleanTarget{
+				Path:        path,
+				Category:    "installers",
+				Description: fmt.Sprintf("%s in ~/%s, unused for %d days", kind, filepath.Base(dir), days),
+				SizeBytes:   size,
+				Safety:      config.Moderate,
+			})
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].SizeBytes > targets[j].SizeBytes
+	})
+	return targets
+}
@@ -0,0 +1,69 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestScanInstallers(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	now := time.Now()
+	old := now.AddDate(0, 0, -45)
+	age := func(path string, when time.Time) {
+		t.Helper()
+		if err := os.Chtimes(path, when, when); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	downloads := filepath.Join(home, "Downloads")
+	desktop := filepath.Join(home, "Desktop")
+	age(createTestFile(t, downloads, "Xcode_16.xip", "a large archive"), old)
+	age(createTestFile(t, downloads, "Docker.DMG", "disk image"), old)
+	age(createTestFile(t, desktop, "ubuntu.iso", "iso"), old)
+	createTestFile(t, downloads, "Slack.dmg", "downloaded yesterday")
+	age(createTestFile(t, downloads, "report.pdf", "not an installer"), old)
+	age(createTestFile(t, downloads, "old/Nested.dmg", "not directly in Downloads"), old)
+
+	// Package bundles are folders
+	age(createTestFile(t, downloads, "Tool.pkg/Contents/Archive.pax", "payload"), old)
+	age(filepath.Join(downloads, "Tool.pkg", "Contents"), old)
+	age(filepath.Join(downloads, "Tool.pkg"), old)
+
+	targets := scanInstallers(installerDirs(home), 30, now)
+
+	expected := map[string]bool{
+		filepath.Join(downloads, "Xcode_16.xip"): true,
+		filepath.Join(downloads, "Docker.DMG"):   true,
+		filepath.Join(desktop, "ubuntu.iso"):     true,
+		filepath.Join(downloads, "Tool.pkg"):     true,
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %d targets, got %v", len(expected), targets)
+	}
+	for i, target := range targets {
+		if !expected[target.Path] {
+			t.Errorf("Unexpected target %s", target.Path)
+		}
+		if target.Safety != config.Moderate || target.Category != "installers" {
+			t.Errorf("Expected a Moderate installers target, got %+v", target)
+		}
+		if i > 0 && target.SizeBytes > targets[i-1].SizeBytes {
+			t.Error("Targets should be sorted largest first")
+		}
+	}
+	if targets[0].Description != "Xcode archive in ~/Downloads, unused for 45 days" {
+		t.Errorf("Unexpected description %q", targets[0].Description)
+	}
+
+	// Nothing is old enough for a longer threshold
+	if targets := scanInstallers(installerDirs(home), 60, now); len(targets) != 0 {
+		t.Errorf("Expected no targets unused for 60 days, got %v", targets)
+	}
+}
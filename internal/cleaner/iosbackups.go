@@ -0,0 +1,113 @@
+package cleaner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// iosBackup is a device backup made by Finder or iTunes, as its Info.plist
+// describes it
+type iosBackup struct {
+	Path       string
+	DeviceName string    // "" if unknown
+	OSVersion  string    // As in "17.4.1", "" if unknown
+	LastBackup time.Time // Modification time of the folder if not recorded
+	Size       int64
+}
+
+// describe returns what a backup is, as listed before deletion
+func (b iosBackup) describe(now time.Time) string {
+	device := "unknown device " + filepath.Base(b.Path)
+	if b.DeviceName != "" {
+		device = b.DeviceName
+	}
+	if b.OSVersion != "" {
+		device += " (iOS " + b.OSVersion + ")"
+	}
+
+	days := int(now.Sub(b.LastBackup).Hours() / 24)
+	return fmt.Sprintf("Backup of %s, last made on %s (%d days ago)", device, b.LastBackup.Format("2006-01-02"), days)
+}
+
+// readIOSBackups returns the backups in dir, oldest first. Info.plist files
+// are read with readPlist, which returns them as XML.
+func readIOSBackups(dir string, readPlist func(path string) ([]byte, error)) []iosBackup {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	backups := []iosBackup{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		size, _ := utils.GetDirSize(path)
+		if size == 0 {
+			continue
+		}
+
+		backup := iosBackup{Path: path, Size: size}
+		if info, err := entry.Info(); err == nil {
+			backup.LastBackup = info.ModTime()
+		}
+
+		if data, err := readPlist(filepath.Join(path, "Info.plist")); err == nil {
+			if values, err := utils.ParsePlistDict(data); err == nil {
+				backup.DeviceName = values["Device Name"]
+				backup.OSVersion = values["Product Version"]
+				if date, err := time.Parse(time.RFC3339, values["Last Backup Date"]); err == nil {
+					backup.LastBackup = date
+				}
+			}
+		}
+		backups = append(backups, backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].LastBackup.Before(backups[j].LastBackup)
+	})
+	return backups
+}
+
+// iosBackupTargets returns one Dangerous target per backup, so that only
+// the backups of devices no longer owned can be picked
+func iosBackupTargets(backups []iosBackup, now time.Time) []CleanTarget {
+	targets := make([]CleanTarget, 0, len(backups))
+	for _, backup := range backups {
+		targets = append(targets, CleanTarget{
+			Path:        backup.Path,
+			Category:    "ios_backups",
+			Description: backup.describe(now),
+			SizeBytes:   backup.Size,
+			Safety:      config.Dangerous,
+		})
+	}
+	return targets
+}
+
+// readPlist reads a property list as XML, converting binary ones with plutil
+func (s *SystemCleaner) readPlist(path string) ([]byte, error) {
+	return readPlistXML(s.commands(), path)
+}
+
+// readPlistXML reads a property list as XML, converting binary ones with
+// plutil run by runner
+func readPlistXML(runner CommandRunner, path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte("bplist")) {
+		return runner.Output("plutil", "-convert", "xml1", "-o", "-", path)
+	}
+	return data, nil
+}
@@ -0,0 +1,80 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+const testBackupInfo = `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>Applications</key>
+	<dict>
+		<key>com.example.app</key>
+		<dict/>
+	</dict>
+	<key>Device Name</key>
+	<string>Old iPhone</string>
+	<key>Last Backup Date</key>
+	<date>2024-01-02T10:20:30Z</date>
+	<key>Product Version</key>
+	<string>16.7.2</string>
+</dict>
+</plist>`
+
+func TestReadIOSBackups(t *testing.T) {
+	dir := setupTestDir(t)
+	defer os.RemoveAll(dir)
+
+	createTestFile(t, dir, "00008030-AAAA/Info.plist", testBackupInfo)
+	createTestFile(t, dir, "00008030-AAAA/Manifest.db", "manifest")
+	createTestFile(t, dir, "00008101-BBBB/Manifest.db", "no info")
+	os.MkdirAll(filepath.Join(dir, "empty"), 0755)
+
+	backups := readIOSBackups(dir, os.ReadFile)
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 backups, got %+v", backups)
+	}
+
+	old := backups[0]
+	if old.DeviceName != "Old iPhone" || old.OSVersion != "16.7.2" {
+		t.Errorf("Expected the device from Info.plist first, got %+v", old)
+	}
+	if !old.LastBackup.Equal(time.Date(2024, 1, 2, 10, 20, 30, 0, time.UTC)) {
+		t.Errorf("Expected the recorded backup date, got %v", old.LastBackup)
+	}
+
+	now := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	targets := iosBackupTargets(backups, now)
+	if targets[0].Description != "Backup of Old iPhone (iOS 16.7.2), last made on 2024-01-02 (59 days ago)" {
+		t.Errorf("Unexpected description %q", targets[0].Description)
+	}
+	if targets[0].Path != filepath.Join(dir, "00008030-AAAA") || targets[0].Safety != config.Dangerous {
+		t.Errorf("Expected a Dangerous target per backup, got %+v", targets[0])
+	}
+	if backups[1].DeviceName != "" || !strings.HasPrefix(targets[1].Description, "Backup of unknown device 00008101-BBBB,") {
+		t.Errorf("Expected a backup without Info.plist to be listed by folder, got %q", targets[1].Description)
+	}
+}
+
+func TestSystemCleaner_IOSBackups_Binary(t *testing.T) {
+	dir := setupTestDir(t)
+	defer os.RemoveAll(dir)
+
+	path := createTestFile(t, dir, "00008030-AAAA/Info.plist", "bplist00binary")
+	runner := &RecordingRunner{Outputs: map[string]string{
+		"plutil -convert xml1 -o - " + path: testBackupInfo,
+	}}
+	s := &SystemCleaner{cleanerType: TypeIOSBackups}
+	s.SetRunner(runner)
+
+	backups := readIOSBackups(dir, s.readPlist)
+	if len(backups) != 1 || backups[0].DeviceName != "Old iPhone" {
+		t.Errorf("Expected the binary Info.plist to be converted, got %+v", backups)
+	}
+}
@@ -0,0 +1,128 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// gradleCacheCategories are the Gradle folders running daemons read and
+// write: deleting them under a daemon corrupts its builds
+var gradleCacheCategories = map[string]bool{
+	"gradle_cache":         true,
+	"gradle_build_cache":   true,
+	"gradle_wrapper_dists": true,
+}
+
+// mavenCacheCategories are the Maven folders running builds read and write
+var mavenCacheCategories = map[string]bool{
+	"maven_repository": true,
+}
+
+// Command lines of the running JVM build tools, matched by pgrep -f
+const (
+	gradleDaemonProcess = "org.gradle.launcher.daemon.bootstrap.GradleDaemon"
+	mavenProcess        = "org.codehaus.plexus.classworlds.launcher.Launcher"
+)
+
+// onWindows is set when the caches of this machine are used by Windows
+// processes, which pgrep can't list
+const onWindows = runtime.GOOS == "windows"
+
+// processQuery returns the command printing the ids of the processes whose
+// command line contains pattern: pgrep, or PowerShell on Windows
+func processQuery(windows bool, pattern string) (string, []string) {
+	if windows {
+		return "powershell.exe", []string{"-NoProfile", "-NonInteractive", "-Command",
+			"Get-CimInstance Win32_Process | Where-Object { $_.CommandLine -like '*" + pattern + "*' } | ForEach-Object { $_.ProcessId }"}
+	}
+	return "pgrep", []string{"-f", pattern}
+}
+
+// runningProcesses returns the ids of the processes whose command line
+// contains pattern, with pgrep or with PowerShell if windows is set. An
+// error means the processes couldn't be listed: pgrep exits with status 1,
+// not an error, when none matches.
+func runningProcesses(runner CommandRunner, windows bool, pattern string) ([]string, error) {
+	name, args := processQuery(windows, pattern)
+	output, err := runner.Output(name, args...)
+	var exit interface{ ExitCode() int }
+	if !windows && errors.As(err, &exit) && exit.ExitCode() == 1 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// prepareJVMCaches gets the Gradle and Maven caches among targets ready to
+// be deleted, windows telling how to list the processes using them (see
+// runningProcesses). Running Gradle daemons are stopped with gradle --stop;
+// the caches of a tool still running afterwards, of a Maven build in
+// progress, or of a tool whose processes can't be listed are left alone
+// and returned as failed results saying why. Dry runs stop nothing.
+func prepareJVMCaches(runner CommandRunner, windows bool, targets []CleanTarget, dryRun bool) ([]CleanTarget, []CleanResult) {
+	gradle, maven := false, false
+	for _, target := range targets {
+		gradle = gradle || gradleCacheCategories[target.Category]
+		maven = maven || mavenCacheCategories[target.Category]
+	}
+	if dryRun || (!gradle && !maven) {
+		return targets, nil
+	}
+
+	var gradlePids, mavenPids []string
+	var gradleErr, mavenErr error
+	if gradle {
+		gradlePids, gradleErr = runningProcesses(runner, windows, gradleDaemonProcess)
+		if len(gradlePids) > 0 {
+			// Stops the daemons of the Gradle version on the PATH; those of
+			// other versions keep running and are caught below
+			runner.Run("gradle", "--stop")
+			gradlePids, gradleErr = runningProcesses(runner, windows, gradleDaemonProcess)
+		}
+	}
+	if maven {
+		mavenPids, mavenErr = runningProcesses(runner, windows, mavenProcess)
+	}
+
+	ready := make([]CleanTarget, 0, len(targets))
+	var skipped []CleanResult
+	for _, target := range targets {
+		switch {
+		case gradleCacheCategories[target.Category] && gradleErr != nil:
+			skipped = append(skipped, CleanResult{Target: target, Error: fmt.Errorf(
+				"skipped, can't tell whether Gradle daemons are running: %w", gradleErr)})
+		case mavenCacheCategories[target.Category] && mavenErr != nil:
+			skipped = append(skipped, CleanResult{Target: target, Error: fmt.Errorf(
+				"skipped, can't tell whether a Maven build is running: %w", mavenErr)})
+		case gradleCacheCategories[target.Category] && len(gradlePids) > 0:
+			skipped = append(skipped, CleanResult{Target: target, Error: fmt.Errorf(
+				"skipped, Gradle daemons are still running (pid %s): stop them with gradle --stop from each project, then clean again",
+				strings.Join(gradlePids, ", "))})
+		case mavenCacheCategories[target.Category] && len(mavenPids) > 0:
+			skipped = append(skipped, CleanResult{Target: target, Error: fmt.Errorf(
+				"skipped, a Maven build is running (pid %s): clean again once it is done", strings.Join(mavenPids, ", "))})
+		default:
+			ready = append(ready, target)
+		}
+	}
+	return ready, skipped
+}
+
+// cleanJVMTargets removes targets like cleanTargets, once the Gradle and
+// Maven caches among them are ready to be deleted
+func cleanJVMTargets(ctx context.Context, runner CommandRunner, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanJVMTargetsOn(ctx, runner, onWindows, targets, dryRun)
+}
+
+// cleanJVMTargetsOn is cleanJVMTargets for caches used by the processes
+// runner reaches, on Windows if windows is set
+func cleanJVMTargetsOn(ctx context.Context, runner CommandRunner, windows bool, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	ready, skipped := prepareJVMCaches(runner, windows, targets, dryRun)
+	results, err := cleanTargets(ctx, ready, dryRun)
+	return append(results, skipped...), err
+}
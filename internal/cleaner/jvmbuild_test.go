@@ -0,0 +1,139 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// exitStatus is the error of a command exiting with a status
+type exitStatus int
+
+func (e exitStatus) Error() string { return fmt.Sprintf("exit status %d", int(e)) }
+func (e exitStatus) ExitCode() int { return int(e) }
+
+// daemonRunner answers pgrep with the daemons left running, which
+// gradle --stop stops unless stubborn is set, and with no match for the
+// processes not recorded. Without pgrep, listing processes fails.
+type daemonRunner struct {
+	RecordingRunner
+	gradle   string
+	stubborn bool
+	noPgrep  bool
+}
+
+func (d *daemonRunner) Output(name string, args ...string) ([]byte, error) {
+	switch {
+	case name == "pgrep" && d.noPgrep:
+		return nil, exec.ErrNotFound
+	case name == "pgrep" && args[1] == gradleDaemonProcess:
+		if d.gradle == "" {
+			return nil, exitStatus(1)
+		}
+		return []byte(d.gradle), nil
+	case name == "pgrep":
+		if output, ok := d.Outputs[commandLine(name, args...)]; ok {
+			return []byte(output), nil
+		}
+		return nil, exitStatus(1)
+	}
+	return d.RecordingRunner.Output(name, args...)
+}
+
+func (d *daemonRunner) Run(name string, args ...string) error {
+	if name == "gradle" && !d.stubborn {
+		d.gradle = ""
+	}
+	return d.RecordingRunner.Run(name, args...)
+}
+
+func TestCleanJVMTargets(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	targets := func() []CleanTarget {
+		createTestFile(t, tmpDir, "gradle/caches/modules-2/files.bin", "gradle")
+		createTestFile(t, tmpDir, "m2/repository/junit/junit.jar", "maven")
+		createTestFile(t, tmpDir, "npm/_cacache/index", "npm")
+		return []CleanTarget{
+			{Path: filepath.Join(tmpDir, "gradle", "caches"), Category: "gradle_cache"},
+			{Path: filepath.Join(tmpDir, "m2", "repository"), Category: "maven_repository"},
+			{Path: filepath.Join(tmpDir, "npm"), Category: "npm_cache"},
+		}
+	}
+	failed := func(results []CleanResult) []string {
+		var categories []string
+		for _, result := range results {
+			if result.Error != nil {
+				categories = append(categories, result.Target.Category)
+			}
+		}
+		return categories
+	}
+
+	// Daemons that stop, and a Maven build in progress
+	runner := &daemonRunner{gradle: "4242\n"}
+	runner.Outputs = map[string]string{"pgrep -f " + mavenProcess: "777\n"}
+	results, err := cleanJVMTargets(context.Background(), runner, targets(), false)
+	if err != nil {
+		t.Fatalf("cleanJVMTargets() returned error: %v", err)
+	}
+	if !slices.Equal(runner.Commands(), []string{"gradle --stop"}) {
+		t.Error("Expected the Gradle daemons to be stopped")
+	}
+	if got := failed(results); !slices.Equal(got, []string{"maven_repository"}) {
+		t.Errorf("Expected only the Maven repository to be skipped, got %v", got)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "m2", "repository")); err != nil {
+		t.Error("The Maven repository should be kept while Maven runs")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "gradle", "caches")); !os.IsNotExist(err) {
+		t.Error("The Gradle cache should be deleted once the daemons stopped")
+	}
+
+	// Daemons of another Gradle version keep running
+	runner = &daemonRunner{gradle: "4242\n", stubborn: true}
+	results, _ = cleanJVMTargets(context.Background(), runner, targets(), false)
+	if got := failed(results); !slices.Equal(got, []string{"gradle_cache"}) {
+		t.Errorf("Expected the Gradle cache to be skipped, got %v", got)
+	}
+	for _, result := range results {
+		if result.Error != nil && !strings.Contains(result.Error.Error(), "4242") {
+			t.Errorf("Expected the running daemon in the warning, got %v", result.Error)
+		}
+	}
+
+	// Processes that can't be listed may be using the caches
+	runner = &daemonRunner{noPgrep: true}
+	results, _ = cleanJVMTargets(context.Background(), runner, targets(), false)
+	if got := failed(results); !slices.Equal(got, []string{"gradle_cache", "maven_repository"}) {
+		t.Errorf("Expected both JVM caches to be skipped, got %v", got)
+	}
+
+	// Dry runs stop nothing
+	runner = &daemonRunner{gradle: "4242\n"}
+	results, _ = cleanJVMTargets(context.Background(), runner, targets(), true)
+	if len(failed(results)) != 0 || len(runner.Commands()) != 0 {
+		t.Errorf("Expected a dry run to stop and skip nothing, got %+v", results)
+	}
+}
+
+func TestRunningProcesses_Windows(t *testing.T) {
+	name, args := processQuery(true, gradleDaemonProcess)
+	runner := &RecordingRunner{Outputs: map[string]string{commandLine(name, args...): "4242\r\n5151\r\n"}}
+	pids, err := runningProcesses(runner, true, gradleDaemonProcess)
+	if err != nil || !slices.Equal(pids, []string{"4242", "5151"}) {
+		t.Errorf("Expected both daemons, got %v (%v)", pids, err)
+	}
+
+	// Only pgrep's status 1 means no match
+	runner = &RecordingRunner{Errors: map[string]error{commandLine(name, args...): exitStatus(1)}}
+	if _, err := runningProcesses(runner, true, gradleDaemonProcess); err == nil {
+		t.Error("Expected PowerShell failing to be an error")
+	}
+}
@@ -0,0 +1,209 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+)
+
+// LeftoversCleaner finds what uninstalled apps left behind in ~/Library:
+// Application Support folders, preference files and sandbox containers named
+// after a bundle ID no installed app has. They hold settings and data, so
+// each app's leftovers are one Moderate target.
+type LeftoversCleaner struct{}
+
+// bundleIDPattern matches reverse-DNS bundle IDs such as com.tinyspeck.slackmacgap.
+// Folders named after the app (e.g. "Slack") are too ambiguous to be matched.
+var bundleIDPattern = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9_-]+){2,}$`)
+
+// systemBundlePrefixes are bundle ID prefixes of macOS components, which have
+// no app in /Applications but are never leftovers
+var systemBundlePrefixes = []string{"com.apple.", "group.com.apple.", "org.cups.", "org.openssh."}
+
+// NewLeftoversCleaner creates a new LeftoversCleaner
+func NewLeftoversCleaner() Cleaner {
+	return &LeftoversCleaner{}
+}
+
+func (l *LeftoversCleaner) Name() string {
+	return "App Leftovers"
+}
+
+func (l *LeftoversCleaner) Domain() config.Domain {
+	return config.DomainSystem
+}
+
+func (l *LeftoversCleaner) Detect(ctx context.Context) (bool, error) {
+	// Needed to read the bundle IDs of installed apps
+	return detector.FromContext(ctx).HasCommand("plutil"), nil
+}
+
+func (l *LeftoversCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	if !cfg.Allows(config.DomainSystem, "app_leftovers", config.Moderate) {
+		return []CleanTarget{}, nil
+	}
+
+	home, err := cfg.HomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	installed := installedBundleIDs(findApps(applicationDirs(home)), plistBundleID)
+	if len(installed) == 0 {
+		// Without the installed apps everything would look orphaned
+		return []CleanTarget{}, nil
+	}
+
+	return scanLeftovers(home, installed), nil
+}
+
+func (l *LeftoversCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanTargets(ctx, targets, dryRun)
+}
+
+// applicationDirs returns the folders apps are installed in
+func applicationDirs(home string) []string {
+	return []string{"/Applications", "/System/Applications", filepath.Join(home, "Applications")}
+}
+
+// findApps returns the app bundles in dirs and their direct subfolders
+// (e.g. /Applications/Utilities), without looking inside the bundles
+func findApps(dirs []string) []string {
+	apps := []string{}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if strings.HasSuffix(entry.Name(), ".app") {
+				apps = append(apps, path)
+				continue
+			}
+			if !entry.IsDir() {
+				continue
+			}
+
+			subEntries, err := os.ReadDir(path)
+			if err != nil {
+				continue
+			}
+			for _, sub := range subEntries {
+				if strings.HasSuffix(sub.Name(), ".app") {
+					apps = append(apps, filepath.Join(path, sub.Name()))
+				}
+			}
+		}
+	}
+
+	return apps
+}
+
+// plistBundleID reads the bundle ID of an app from its Info.plist, which may
+// be binary, or returns "" if it has none
+func plistBundleID(app string) string {
+	infoPlist := filepath.Join(app, "Contents", "Info.plist")
+	output, err := exec.Command("plutil", "-extract", "CFBundleIdentifier", "raw", "-o", "-", infoPlist).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// installedBundleIDs returns the lowercase bundle IDs of the apps, read with
+// bundleID
+func installedBundleIDs(apps []string, bundleID func(string) string) map[string]bool {
+	installed := make(map[string]bool, len(apps))
+	for _, app := range apps {
+		if id := bundleID(app); id != "" {
+			installed[strings.ToLower(id)] = true
+		}
+	}
+	return installed
+}
+
+// isLeftover reports whether a bundle ID belongs to no installed app. IDs of
+// helpers and extensions (com.vendor.app.helper) belong to their app.
+func isLeftover(id string, installed map[string]bool) bool {
+	id = strings.ToLower(id)
+
+	for _, prefix := range systemBundlePrefixes {
+		if strings.HasPrefix(id, prefix) {
+			return false
+		}
+	}
+
+	for candidate := id; ; {
+		if installed[candidate] {
+			return false
+		}
+		dot := strings.LastIndex(candidate, ".")
+		if dot < 0 {
+			return true
+		}
+		candidate = candidate[:dot]
+	}
+}
+
+// scanLeftovers returns one target per uninstalled app, listing its
+// Application Support folder, preference file and container as entries
+func scanLeftovers(home string, installed map[string]bool) []CleanTarget {
+	library := filepath.Join(home, "Library")
+	leftovers := make(map[string][]string) // Bundle ID to paths
+
+	locations := []struct {
+		dir    string
+		suffix string // Extension after the bundle ID in entry names
+	}{
+		{filepath.Join(library, "Application Support"), ""},
+		{filepath.Join(library, "Preferences"), ".plist"},
+		{filepath.Join(library, "Containers"), ""},
+	}
+
+	for _, location := range locations {
+		entries, err := os.ReadDir(location.dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasSuffix(name, location.suffix) {
+				continue
+			}
+
+			id := strings.TrimSuffix(name, location.suffix)
+			if !bundleIDPattern.MatchString(id) || !isLeftover(id, installed) {
+				continue
+			}
+			leftovers[id] = append(leftovers[id], filepath.Join(location.dir, name))
+		}
+	}
+
+	ids := make([]string, 0, len(leftovers))
+	for id := range leftovers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	targets := []CleanTarget{}
+	for _, id := range ids {
+		description := fmt.Sprintf("Leftovers of uninstalled app %s", id)
+		if target, ok := entriesTarget(library, leftovers[id], "app_leftovers", description, config.Moderate); ok {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
@@ -0,0 +1,86 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsLeftover(t *testing.T) {
+	installed := map[string]bool{"com.tinyspeck.slackmacgap": true, "com.jetbrains.goland": true}
+
+	tests := []struct {
+		id       string
+		expected bool
+	}{
+		{"com.tinyspeck.slackmacgap", false},
+		{"com.TinySpeck.SlackMacGap", false},           // Case-insensitive
+		{"com.jetbrains.goland.helper", false},         // Helper of an installed app
+		{"com.apple.Safari", false},                    // System component
+		{"com.spotify.client", true},                   // Uninstalled
+		{"com.jetbrains", true},                        // Only a prefix of an installed ID
+		{"com.tinyspeck.slackmacgap-old.helper", true}, // Not the same app
+	}
+
+	for _, tt := range tests {
+		if got := isLeftover(tt.id, installed); got != tt.expected {
+			t.Errorf("isLeftover(%s) = %v, expected %v", tt.id, got, tt.expected)
+		}
+	}
+}
+
+func TestInstalledBundleIDs(t *testing.T) {
+	apps := t.TempDir()
+	for _, app := range []string{"Slack.app", "Utilities/Terminal.app", "Broken.app"} {
+		if err := os.MkdirAll(filepath.Join(apps, app, "Contents"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found := findApps([]string{apps, filepath.Join(apps, "missing")})
+	if len(found) != 3 {
+		t.Fatalf("Expected 3 apps, got %v", found)
+	}
+
+	ids := map[string]string{"Slack.app": "com.tinyspeck.slackmacgap", "Terminal.app": "com.apple.Terminal"}
+	installed := installedBundleIDs(found, func(app string) string {
+		return ids[filepath.Base(app)]
+	})
+
+	if len(installed) != 2 || !installed["com.tinyspeck.slackmacgap"] || !installed["com.apple.terminal"] {
+		t.Errorf("Unexpected installed bundle IDs %v", installed)
+	}
+}
+
+func TestScanLeftovers(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	library := filepath.Join(home, "Library")
+	createTestFile(t, library, "Application Support/com.spotify.client/cache.db", "data")
+	createTestFile(t, library, "Preferences/com.spotify.client.plist", "<plist/>")
+	createTestFile(t, library, "Containers/com.spotify.client/Data/file", "data")
+	createTestFile(t, library, "Preferences/com.tinyspeck.slackmacgap.plist", "<plist/>")
+	createTestFile(t, library, "Preferences/com.apple.finder.plist", "<plist/>")
+	createTestFile(t, library, "Preferences/.GlobalPreferences.plist", "<plist/>")
+	createTestFile(t, library, "Application Support/Slack/storage", "data") // Named after the app: ignored
+	createTestFile(t, library, "Containers/org.videolan.vlc/Data/file", "data")
+
+	installed := map[string]bool{"com.tinyspeck.slackmacgap": true}
+	targets := scanLeftovers(home, installed)
+
+	if len(targets) != 2 {
+		t.Fatalf("Expected 2 targets, got %d: %v", len(targets), targets)
+	}
+
+	spotify := targets[0]
+	if spotify.Path != library || len(spotify.Entries) != 3 {
+		t.Errorf("Expected the 3 Spotify leftovers under ~/Library, got %+v", spotify)
+	}
+	if spotify.Category != "app_leftovers" {
+		t.Errorf("Expected category app_leftovers, got %s", spotify.Category)
+	}
+	if targets[1].Entries[0] != filepath.Join(library, "Containers", "org.videolan.vlc") {
+		t.Errorf("Expected the VLC container, got %v", targets[1].Entries)
+	}
+}
@@ -0,0 +1,510 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+	"github.com/dustin/go-humanize"
+)
+
+// Local clusters and VMs are cleaned with their own tools, so their targets
+// use pseudo-paths like the Docker ones:
+//
+//	kind:images:<cluster>, kind:cluster:<cluster>    (same for k3d)
+//	lima:trim:<instance>, lima:vm:<instance>         (same for colima, by profile)
+//	docker-desktop:reclaim
+var localClusterPrefixes = []string{"kind:", "k3d:", "lima:", "colima:", "docker-desktop:"}
+
+// isLocalClusterTarget reports whether a target path is a local cluster or
+// VM pseudo-path
+func isLocalClusterTarget(path string) bool {
+	for _, prefix := range localClusterPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// localCluster is a kind or k3d cluster, whose nodes are Docker containers
+type localCluster struct {
+	tool  string // "kind" or "k3d"
+	name  string
+	nodes []string // Node container names
+}
+
+// imageStore returns the containerd image store inside the cluster's nodes
+func (c localCluster) imageStore() string {
+	if c.tool == "k3d" {
+		return "/var/lib/rancher/k3s/agent/containerd"
+	}
+	return "/var/lib/containerd"
+}
+
+// kindClusters lists kind clusters and their node containers
+func kindClusters(runner CommandRunner) []localCluster {
+	output, err := runner.Output("kind", "get", "clusters")
+	if err != nil {
+		return nil
+	}
+
+	clusters := []localCluster{}
+	for _, name := range strings.Fields(string(output)) {
+		nodes, err := runner.Output("kind", "get", "nodes", "--name", name)
+		if err != nil {
+			continue
+		}
+		clusters = append(clusters, localCluster{tool: "kind", name: name, nodes: strings.Fields(string(nodes))})
+	}
+	return clusters
+}
+
+// k3dClusters lists k3d clusters and their node containers
+func k3dClusters(runner CommandRunner) []localCluster {
+	output, err := runner.Output("k3d", "cluster", "list", "-o", "json")
+	if err != nil {
+		return nil
+	}
+	return parseK3dClusters(output)
+}
+
+// parseK3dClusters reads the output of k3d cluster list -o json. Load
+// balancer nodes hold no images and are left out.
+func parseK3dClusters(output []byte) []localCluster {
+	var list []struct {
+		Name  string `json:"name"`
+		Nodes []struct {
+			Name string `json:"name"`
+			Role string `json:"role"`
+		} `json:"nodes"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil
+	}
+
+	clusters := []localCluster{}
+	for _, c := range list {
+		cluster := localCluster{tool: "k3d", name: c.Name}
+		for _, node := range c.Nodes {
+			if node.Role == "server" || node.Role == "agent" {
+				cluster.nodes = append(cluster.nodes, node.Name)
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+// imageStoreSize returns the size of the image stores of the cluster's
+// running nodes
+func (c localCluster) imageStoreSize(runner CommandRunner) int64 {
+	var size int64
+	for _, node := range c.nodes {
+		output, err := runner.Output("docker", "exec", node, "du", "-sk", c.imageStore())
+		if err != nil {
+			continue
+		}
+		size += parseDuKilobytes(string(output))
+	}
+	return size
+}
+
+// unusedImagesSize returns the size of the images no container of the
+// cluster's running nodes uses, which crictl rmi --prune deletes. Layers
+// shared with images in use are counted too, so it can still be above
+// what a prune frees.
+func (c localCluster) unusedImagesSize(runner CommandRunner) int64 {
+	var size int64
+	for _, node := range c.nodes {
+		images, err := runner.Output("docker", "exec", node, "crictl", "images", "-o", "json")
+		if err != nil {
+			continue
+		}
+		containers, err := runner.Output("docker", "exec", node, "crictl", "ps", "-a", "-o", "json")
+		if err != nil {
+			continue
+		}
+		size += parseUnusedImages(images, containers)
+	}
+	return size
+}
+
+// parseUnusedImages sums the sizes of the images listed by crictl images
+// that no container listed by crictl ps uses
+func parseUnusedImages(images, containers []byte) int64 {
+	var imageList struct {
+		Images []struct {
+			ID       string          `json:"id"`
+			RepoTags []string        `json:"repoTags"`
+			Size     json.RawMessage `json:"size"` // A string or a number depending on crictl's version
+		} `json:"images"`
+	}
+	var containerList struct {
+		Containers []struct {
+			Image struct {
+				Image string `json:"image"`
+			} `json:"image"`
+			ImageRef string `json:"imageRef"`
+		} `json:"containers"`
+	}
+	if json.Unmarshal(images, &imageList) != nil || json.Unmarshal(containers, &containerList) != nil {
+		return 0
+	}
+
+	used := make(map[string]bool)
+	for _, container := range containerList.Containers {
+		used[container.ImageRef] = true
+		used[container.Image.Image] = true
+	}
+
+	var size int64
+	for _, image := range imageList.Images {
+		inUse := used[image.ID]
+		for _, tag := range image.RepoTags {
+			inUse = inUse || used[tag]
+		}
+		if inUse {
+			continue
+		}
+		if n, err := strconv.ParseInt(strings.Trim(string(image.Size), `"`), 10, 64); err == nil {
+			size += n
+		}
+	}
+	return size
+}
+
+// parseDuKilobytes reads the size printed by du -sk, in bytes
+func parseDuKilobytes(output string) int64 {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return 0
+	}
+	kb, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return kb * 1024
+}
+
+// localVM is a Lima virtual machine. Colima runs its VMs with Lima too,
+// under ~/.colima/_lima.
+type localVM struct {
+	tool    string // "lima" or "colima"
+	name    string // Instance name for Lima, profile for Colima
+	dir     string // Instance directory, holding the disk images
+	running bool
+}
+
+// limaHome returns the directory holding Lima instances
+func limaHome(home string) string {
+	if dir := os.Getenv("LIMA_HOME"); dir != "" {
+		return dir
+	}
+	return filepath.Join(home, ".lima")
+}
+
+// localVMs lists the Lima and Colima instances found on disk. An instance
+// is running while its host agent's pid file exists.
+func localVMs(home string) []localVM {
+	roots := []struct{ tool, dir string }{
+		{"lima", limaHome(home)},
+		{"colima", filepath.Join(home, ".colima", "_lima")},
+	}
+
+	vms := []localVM{}
+	for _, root := range roots {
+		entries, err := os.ReadDir(root.dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			// _config, _disks, ... are not instances
+			if !entry.IsDir() || strings.HasPrefix(entry.Name(), "_") {
+				continue
+			}
+			dir := filepath.Join(root.dir, entry.Name())
+			if !utils.PathExists(filepath.Join(dir, "lima.yaml")) {
+				continue
+			}
+
+			name := entry.Name()
+			if root.tool == "colima" {
+				name = colimaProfile(name)
+			}
+			vms = append(vms, localVM{
+				tool:    root.tool,
+				name:    name,
+				dir:     dir,
+				running: utils.PathExists(filepath.Join(dir, "ha.pid")),
+			})
+		}
+	}
+	return vms
+}
+
+// colimaProfile returns the Colima profile of a Lima instance name
+// ("colima" for the default profile, "colima-<profile>" for the others)
+func colimaProfile(instance string) string {
+	if profile, ok := strings.CutPrefix(instance, "colima-"); ok {
+		return profile
+	}
+	return "default"
+}
+
+// localVMDir returns the instance directory of a Lima or Colima VM
+func localVMDir(home, tool, name string) string {
+	if tool == "lima" {
+		return filepath.Join(limaHome(home), name)
+	}
+	if name == "default" {
+		return filepath.Join(home, ".colima", "_lima", "colima")
+	}
+	return filepath.Join(home, ".colima", "_lima", "colima-"+name)
+}
+
+// shell returns the command line running args inside the VM
+func (vm localVM) shell(args ...string) []string {
+	if vm.tool == "colima" {
+		return append([]string{"colima", "ssh", "--profile", vm.name, "--"}, args...)
+	}
+	return append([]string{"limactl", "shell", vm.name}, args...)
+}
+
+// guestUsedBytes returns the space used by the VM's own filesystems, as
+// seen from inside the VM (shared host folders are left out)
+func (vm localVM) guestUsedBytes(runner CommandRunner) (int64, error) {
+	df := vm.shell("df", "-B1", "--output=used", "--local",
+		"-x", "tmpfs", "-x", "devtmpfs", "-x", "overlay", "-x", "squashfs",
+		"-x", "virtiofs", "-x", "9p", "-x", "fuse.sshfs")
+	output, err := runner.Output(df[0], df[1:]...)
+	if err != nil {
+		return 0, err
+	}
+	return parseDfUsed(string(output)), nil
+}
+
+// parseDfUsed sums the sizes printed by df --output=used, skipping the
+// header line
+func parseDfUsed(output string) int64 {
+	var used int64
+	for _, line := range strings.Split(output, "\n") {
+		if n, err := strconv.ParseInt(strings.TrimSpace(line), 10, 64); err == nil {
+			used += n
+		}
+	}
+	return used
+}
+
+// dockerDesktopDisk returns the path of Docker Desktop's VM disk image
+func dockerDesktopDisk(home string) string {
+	return filepath.Join(home, "Library", "Containers", "com.docker.docker", "Data", "vms", "0", "data", "Docker.raw")
+}
+
+// reclaimImage is the image trimming Docker Desktop's disk from inside its
+// VM. It runs privileged, so it is never pulled: the user pulls it once, and
+// it runs by the digest of that copy.
+const reclaimImage = "docker/desktop-reclaim-space"
+
+// reclaimImageRef returns the digest reference of the local copy of
+// reclaimImage
+func reclaimImageRef(runner CommandRunner) (string, error) {
+	output, err := runner.Output("docker", "image", "inspect", "--format", "{{index .RepoDigests 0}}", reclaimImage)
+	if err != nil {
+		return "", fmt.Errorf("%s is not pulled: %w", reclaimImage, err)
+	}
+	ref := strings.TrimSpace(string(output))
+	if !strings.HasPrefix(ref, reclaimImage+"@sha256:") {
+		return "", fmt.Errorf("%s has no digest (%q)", reclaimImage, ref)
+	}
+	return ref, nil
+}
+
+// dockerUsedBytes returns the space Docker reports for its images,
+// containers, volumes and build cache
+func dockerUsedBytes(runner CommandRunner) (int64, error) {
+	output, err := runner.Output("docker", "system", "df", "--format", "{{.Size}}")
+	if err != nil {
+		return 0, err
+	}
+	return parseDockerSizes(string(output)), nil
+}
+
+// parseDockerSizes sums the sizes printed by docker system df (e.g. "1.2GB")
+func parseDockerSizes(output string) int64 {
+	var used int64
+	for _, line := range strings.Fields(output) {
+		if n, err := humanize.ParseBytes(line); err == nil {
+			used += int64(n)
+		}
+	}
+	return used
+}
+
+// scanLocalClusters returns targets for kind and k3d clusters, Lima and
+// Colima VMs and Docker Desktop's disk image
+func scanLocalClusters(cfg *config.Config, home string, runner CommandRunner) []CleanTarget {
+	targets := []CleanTarget{}
+
+	// kind / k3d: prune unused images inside the nodes (Moderate - pulled
+	// again when needed), or delete whole clusters (Dangerous)
+	clusters := []localCluster{}
+	if utils.CommandExists("docker") {
+		if utils.CommandExists("kind") {
+			clusters = append(clusters, kindClusters(runner)...)
+		}
+		if utils.CommandExists("k3d") {
+			clusters = append(clusters, k3dClusters(runner)...)
+		}
+	}
+	for _, cluster := range clusters {
+		if cfg.Allows(config.DomainDevOps, cluster.tool+"_images", config.Moderate) {
+			if unused := cluster.unusedImagesSize(runner); unused > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        cluster.tool + ":images:" + cluster.name,
+					Category:    cluster.tool + "_images",
+					Description: fmt.Sprintf("%s cluster %q unused images", cluster.tool, cluster.name),
+					SizeBytes:   unused,
+					Safety:      config.Moderate,
+				})
+			}
+		}
+
+		size := cluster.imageStoreSize(runner)
+		if size == 0 {
+			continue
+		}
+		if cfg.Allows(config.DomainDevOps, cluster.tool+"_clusters", config.Dangerous) {
+			targets = append(targets, CleanTarget{
+				Path:        cluster.tool + ":cluster:" + cluster.name,
+				Category:    cluster.tool + "_clusters",
+				Description: fmt.Sprintf("%s cluster %q (DANGEROUS - deletes the cluster and its workloads)", cluster.tool, cluster.name),
+				SizeBytes:   size,
+				Safety:      config.Dangerous,
+			})
+		}
+	}
+
+	// Lima / Colima: trim the disk of running VMs (Safe - only gives back
+	// blocks the guest no longer uses), or delete VMs (Dangerous)
+	for _, vm := range localVMs(home) {
+		allocated, _ := utils.GetAllocatedSize(vm.dir)
+		if allocated == 0 {
+			continue
+		}
+
+		if vm.running && cfg.Allows(config.DomainDevOps, vm.tool+"_disk_trim", config.Safe) {
+			if used, err := vm.guestUsedBytes(runner); err == nil && allocated > used {
+				targets = append(targets, CleanTarget{
+					Path:        vm.tool + ":trim:" + vm.name,
+					Category:    vm.tool + "_disk_trim",
+					Description: fmt.Sprintf("%s VM %q disk space freed inside the VM (%s on disk)", vm.tool, vm.name, utils.FormatBytes(allocated)),
+					SizeBytes:   allocated - used,
+					Safety:      config.Safe,
+				})
+			}
+		}
+		if cfg.Allows(config.DomainDevOps, vm.tool+"_vms", config.Dangerous) {
+			targets = append(targets, CleanTarget{
+				Path:        vm.tool + ":vm:" + vm.name,
+				Category:    vm.tool + "_vms",
+				Description: fmt.Sprintf("%s VM %q disk images (DANGEROUS - deletes the VM)", vm.tool, vm.name),
+				SizeBytes:   allocated,
+				Safety:      config.Dangerous,
+			})
+		}
+	}
+
+	// Docker Desktop: Docker.raw is a sparse file that keeps blocks freed
+	// inside the VM until they are trimmed. Trimming runs a privileged
+	// container in the VM (Moderate), only from a local copy of its image.
+	if info, err := os.Stat(dockerDesktopDisk(home)); err == nil && cfg.Allows(config.DomainDevOps, "docker_desktop_disk", config.Moderate) {
+		allocated := utils.AllocatedSize(info)
+		if _, err := reclaimImageRef(runner); err == nil {
+			if used, err := dockerUsedBytes(runner); err == nil && allocated > used {
+				targets = append(targets, CleanTarget{
+					Path:        "docker-desktop:reclaim",
+					Category:    "docker_desktop_disk",
+					Description: fmt.Sprintf("Docker Desktop disk space not in use (Docker.raw takes %s on disk)", utils.FormatBytes(allocated)),
+					SizeBytes:   allocated - used,
+					Safety:      config.Moderate,
+				})
+			}
+		}
+	}
+
+	return targets
+}
+
+// cleanLocalCluster runs the cleanup of a local cluster or VM target and
+// returns the space it freed, measured again afterwards where possible
+func cleanLocalCluster(target CleanTarget, home string, runner CommandRunner) (int64, error) {
+	scheme, name, _ := strings.Cut(target.Path, ":")
+	action, name, _ := strings.Cut(name, ":")
+
+	switch scheme {
+	case "kind", "k3d":
+		if action == "cluster" {
+			if scheme == "kind" {
+				return target.SizeBytes, runner.Run("kind", "delete", "cluster", "--name", name)
+			}
+			return target.SizeBytes, runner.Run("k3d", "cluster", "delete", name)
+		}
+
+		var cluster localCluster
+		clusters := kindClusters(runner)
+		if scheme == "k3d" {
+			clusters = k3dClusters(runner)
+		}
+		for _, c := range clusters {
+			if c.name == name {
+				cluster = c
+			}
+		}
+		if cluster.name == "" {
+			return 0, fmt.Errorf("%s cluster %q not found", scheme, name)
+		}
+		before := cluster.imageStoreSize(runner)
+		var lastErr error
+		for _, node := range cluster.nodes {
+			if err := runner.Run("docker", "exec", node, "crictl", "rmi", "--prune"); err != nil {
+				lastErr = err
+			}
+		}
+		return max(before-cluster.imageStoreSize(runner), 0), lastErr
+
+	case "lima", "colima":
+		vm := localVM{tool: scheme, name: name, dir: localVMDir(home, scheme, name)}
+		if action == "vm" {
+			if scheme == "colima" {
+				return target.SizeBytes, runner.Run("colima", "delete", "--force", "--profile", name)
+			}
+			return target.SizeBytes, runner.Run("limactl", "delete", "--force", name)
+		}
+
+		before, _ := utils.GetAllocatedSize(vm.dir)
+		fstrim := vm.shell("sudo", "fstrim", "-a")
+		err := runner.Run(fstrim[0], fstrim[1:]...)
+		after, _ := utils.GetAllocatedSize(vm.dir)
+		return max(before-after, 0), err
+
+	case "docker-desktop":
+		image, err := reclaimImageRef(runner)
+		if err != nil {
+			return 0, err
+		}
+		disk := dockerDesktopDisk(home)
+		before, _ := utils.GetAllocatedSize(disk)
+		err = runner.Run("docker", "run", "--rm", "--privileged", "--pid=host", "--pull=never", image)
+		after, _ := utils.GetAllocatedSize(disk)
+		return max(before-after, 0), err
+	}
+
+	return 0, fmt.Errorf("unknown target %s", target.Path)
+}
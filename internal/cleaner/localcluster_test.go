@@ -0,0 +1,135 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseK3dClusters(t *testing.T) {
+	output := []byte(`[{"name":"dev","nodes":[
+		{"name":"k3d-dev-server-0","role":"server"},
+		{"name":"k3d-dev-agent-0","role":"agent"},
+		{"name":"k3d-dev-serverlb","role":"loadbalancer"}]}]`)
+
+	clusters := parseK3dClusters(output)
+	if len(clusters) != 1 {
+		t.Fatalf("Expected 1 cluster, got %v", clusters)
+	}
+	if clusters[0].name != "dev" || len(clusters[0].nodes) != 2 {
+		t.Errorf("Expected cluster dev with 2 nodes, got %+v", clusters[0])
+	}
+	if clusters[0].imageStore() != "/var/lib/rancher/k3s/agent/containerd" {
+		t.Errorf("Unexpected k3d image store %s", clusters[0].imageStore())
+	}
+
+	if clusters := parseK3dClusters([]byte("not json")); len(clusters) != 0 {
+		t.Errorf("Expected no clusters for invalid output, got %v", clusters)
+	}
+}
+
+func TestParseSizes(t *testing.T) {
+	if got := parseDuKilobytes("2048\t/var/lib/containerd\n"); got != 2048*1024 {
+		t.Errorf("parseDuKilobytes() = %d, expected %d", got, 2048*1024)
+	}
+	if got := parseDfUsed("     Used\n1000000\n  500000\n"); got != 1500000 {
+		t.Errorf("parseDfUsed() = %d, expected 1500000", got)
+	}
+	if got := parseDockerSizes("1.5GB\n200MB\n0B\n"); got != 1_700_000_000 {
+		t.Errorf("parseDockerSizes() = %d, expected 1700000000", got)
+	}
+}
+
+func TestLocalVMs(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	t.Setenv("LIMA_HOME", "")
+	createTestFile(t, tmpDir, ".lima/docker/lima.yaml", "arch: aarch64")
+	createTestFile(t, tmpDir, ".lima/docker/diffdisk", "disk")
+	createTestFile(t, tmpDir, ".lima/docker/ha.pid", "123")
+	createTestFile(t, tmpDir, ".lima/_config/user", "key")
+	createTestFile(t, tmpDir, ".colima/_lima/colima/lima.yaml", "arch: aarch64")
+	createTestFile(t, tmpDir, ".colima/_lima/colima-k8s/lima.yaml", "arch: aarch64")
+
+	vms := localVMs(tmpDir)
+	if len(vms) != 3 {
+		t.Fatalf("Expected 3 VMs, got %+v", vms)
+	}
+
+	byName := make(map[string]localVM)
+	for _, vm := range vms {
+		byName[vm.tool+":"+vm.name] = vm
+		if dir := localVMDir(tmpDir, vm.tool, vm.name); dir != vm.dir {
+			t.Errorf("localVMDir(%s, %s) = %s, expected %s", vm.tool, vm.name, dir, vm.dir)
+		}
+	}
+
+	if !byName["lima:docker"].running {
+		t.Error("Expected Lima VM docker to be running")
+	}
+	if _, ok := byName["colima:default"]; !ok {
+		t.Errorf("Expected Colima default profile, got %+v", vms)
+	}
+	if vm, ok := byName["colima:k8s"]; !ok || vm.running {
+		t.Errorf("Expected stopped Colima k8s profile, got %+v", vms)
+	}
+	if vm := byName["colima:k8s"]; vm.dir != filepath.Join(tmpDir, ".colima", "_lima", "colima-k8s") {
+		t.Errorf("Unexpected Colima instance dir %s", vm.dir)
+	}
+}
+
+func TestIsLocalClusterTarget(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"kind:images:dev", true},
+		{"colima:trim:default", true},
+		{"docker-desktop:reclaim", true},
+		{"docker:buildcache", false},
+		{"/Users/me/.lima", false},
+	}
+
+	for _, tt := range tests {
+		if got := isLocalClusterTarget(tt.path); got != tt.expected {
+			t.Errorf("isLocalClusterTarget(%s) = %v, expected %v", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestParseUnusedImages(t *testing.T) {
+	images := []byte(`{"images":[
+		{"id":"sha256:aaa","repoTags":["docker.io/library/nginx:1.27"],"size":"1000"},
+		{"id":"sha256:bbb","repoTags":["docker.io/library/redis:7"],"size":"2000"},
+		{"id":"sha256:ccc","repoTags":["registry.k8s.io/pause:3.10"],"size":300},
+		{"id":"sha256:ddd","repoTags":[],"size":"4000"}]}`)
+	containers := []byte(`{"containers":[
+		{"image":{"image":"sha256:aaa"},"imageRef":"sha256:aaa"},
+		{"image":{"image":"registry.k8s.io/pause:3.10"},"imageRef":""}]}`)
+
+	if got := parseUnusedImages(images, containers); got != 6000 {
+		t.Errorf("parseUnusedImages() = %d, expected 6000", got)
+	}
+	if got := parseUnusedImages([]byte("not json"), containers); got != 0 {
+		t.Errorf("Expected 0 for invalid output, got %d", got)
+	}
+}
+
+func TestReclaimImageRef(t *testing.T) {
+	inspect := commandLine("docker", "image", "inspect", "--format", "{{index .RepoDigests 0}}", reclaimImage)
+
+	runner := &RecordingRunner{Outputs: map[string]string{inspect: reclaimImage + "@sha256:0123\n"}}
+	if ref, err := reclaimImageRef(runner); err != nil || ref != reclaimImage+"@sha256:0123" {
+		t.Errorf("reclaimImageRef() = %q, %v", ref, err)
+	}
+
+	runner = &RecordingRunner{Outputs: map[string]string{inspect: "<no value>\n"}}
+	if _, err := reclaimImageRef(runner); err == nil {
+		t.Error("Expected an error for an image without digest")
+	}
+
+	if _, err := reclaimImageRef(&RecordingRunner{}); err == nil {
+		t.Error("Expected an error when the image is not pulled")
+	}
+}
@@ -0,0 +1,287 @@
+package cleaner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// LocalLLMCleaner handles locally downloaded LLM weights (Ollama, LM Studio,
+// llama.cpp, MLX). Models are multi-GB and slow to fetch again, so each one
+// is a separate Moderate target.
+type LocalLLMCleaner struct{}
+
+// modelFileExtensions are the weight formats listed as individual models
+var modelFileExtensions = []string{".gguf", ".safetensors", ".bin", ".npz"}
+
+// ollamaModel is a model tag pulled with Ollama
+type ollamaModel struct {
+	name     string   // e.g. "llama3:8b"
+	manifest string   // Path of the manifest file
+	blobs    []string // Paths of the layer blobs
+}
+
+// NewLocalLLMCleaner creates a new LocalLLMCleaner
+func NewLocalLLMCleaner() Cleaner {
+	return &LocalLLMCleaner{}
+}
+
+func (l *LocalLLMCleaner) Name() string {
+	return "Local LLMs"
+}
+
+func (l *LocalLLMCleaner) Domain() config.Domain {
+	return config.DomainDataML
+}
+
+func (l *LocalLLMCleaner) Detect(ctx context.Context) (bool, error) {
+	det := detector.FromContext(ctx)
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, err
+	}
+
+	if det.HasCommand("ollama") {
+		return true, nil
+	}
+
+	for dir := range localModelDirs(home) {
+		if det.HasPath(dir) {
+			return true, nil
+		}
+	}
+
+	return det.HasPath(filepath.Join(home, ".ollama", "models")), nil
+}
+
+func (l *LocalLLMCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	targets := []CleanTarget{}
+	home, err := cfg.HomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// === Ollama ===
+
+	ollamaPath := filepath.Join(home, ".ollama", "models")
+	if utils.PathExists(ollamaPath) {
+		for _, target := range scanOllamaModels(ollamaPath) {
+			if cfg.Allows(config.DomainDataML, target.Category, target.Safety) {
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	// === LM Studio, llama.cpp, MLX ===
+
+	// Model files (Moderate - slow to download again). A folder may be a
+	// link to another, such as LM Studio's old models folder, so files are
+	// only offered once.
+	seen := make(map[string]bool)
+	for dir, source := range localModelDirs(home) {
+		category := categoryName(source) + "_models"
+		if !cfg.Allows(config.DomainDataML, category, config.Moderate) {
+			continue
+		}
+
+		for _, path := range findModelFiles(dir) {
+			resolved, err := filepath.EvalSymlinks(path)
+			if err != nil || seen[resolved] {
+				continue
+			}
+			seen[resolved] = true
+
+			size, _ := utils.GetDirSize(path)
+			if size > 0 {
+				rel, _ := filepath.Rel(dir, path)
+				targets = append(targets, CleanTarget{
+					Path:        path,
+					Category:    category,
+					Description: fmt.Sprintf("%s model %s", source, rel),
+					SizeBytes:   size,
+					Safety:      config.Moderate,
+				})
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+func (l *LocalLLMCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanTargets(ctx, targets, dryRun)
+}
+
+// localModelDirs returns the download directories of tools that store one
+// file per model, keyed to the tool name
+func localModelDirs(home string) map[string]string {
+	return map[string]string{
+		filepath.Join(home, ".lmstudio", "models"):            "LM Studio",
+		filepath.Join(home, ".cache", "lm-studio", "models"):  "LM Studio",
+		filepath.Join(home, "Library", "Caches", "llama.cpp"): "llama.cpp",
+		filepath.Join(home, ".cache", "llama.cpp"):            "llama.cpp",
+		filepath.Join(home, ".cache", "mlx"):                  "MLX",
+	}
+}
+
+// findModelFiles returns the model weight files under dir
+func findModelFiles(dir string) []string {
+	files := []string{}
+
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		for _, modelExt := range modelFileExtensions {
+			if ext == modelExt {
+				files = append(files, path)
+				break
+			}
+		}
+		return nil
+	})
+
+	return files
+}
+
+// scanOllamaModels returns one target per pulled model tag, named after its
+// manifest. A target covers the manifest and the blobs no other tag uses, so
+// removing it never breaks another model; tags whose blobs are all shared
+// free nothing and are left out. Blobs not referenced by any manifest are
+// returned as a separate target.
+func scanOllamaModels(modelsPath string) []CleanTarget {
+	targets := []CleanTarget{}
+	models := readOllamaModels(modelsPath)
+
+	refCount := make(map[string]int)
+	for _, model := range models {
+		for _, blob := range model.blobs {
+			refCount[blob]++
+		}
+	}
+
+	for _, model := range models {
+		entries := []string{model.manifest}
+		var size int64
+		for _, blob := range model.blobs {
+			if refCount[blob] == 1 {
+				entries = append(entries, blob)
+				blobSize, _ := utils.GetDirSize(blob)
+				size += blobSize
+			}
+		}
+
+		if size == 0 {
+			continue
+		}
+		targets = append(targets, CleanTarget{
+			Path:        model.manifest,
+			Category:    "ollama_models",
+			Description: fmt.Sprintf("Ollama model %s", model.name),
+			SizeBytes:   size,
+			Safety:      config.Moderate,
+			Entries:     entries,
+		})
+	}
+
+	// Blobs left behind by interrupted pulls or removed models (Safe)
+	orphans := []string{}
+	blobs, _ := filepath.Glob(filepath.Join(modelsPath, "blobs", "sha256-*"))
+	for _, blob := range blobs {
+		if refCount[blob] == 0 {
+			orphans = append(orphans, blob)
+		}
+	}
+	if target, ok := entriesTarget(modelsPath, orphans, "ollama_orphan_blobs", "Ollama unreferenced blobs", config.Safe); ok {
+		targets = append(targets, target)
+	}
+
+	return targets
+}
+
+// readOllamaModels parses the manifests under modelsPath/manifests
+func readOllamaModels(modelsPath string) []ollamaModel {
+	models := []ollamaModel{}
+	manifestsPath := filepath.Join(modelsPath, "manifests")
+
+	filepath.WalkDir(manifestsPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		var manifest struct {
+			Config struct {
+				Digest string `json:"digest"`
+			} `json:"config"`
+			Layers []struct {
+				Digest string `json:"digest"`
+			} `json:"layers"`
+		}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil
+		}
+
+		model := ollamaModel{
+			name:     ollamaModelName(manifestsPath, path),
+			manifest: path,
+		}
+
+		digests := []string{manifest.Config.Digest}
+		for _, layer := range manifest.Layers {
+			digests = append(digests, layer.Digest)
+		}
+		for _, digest := range digests {
+			if digest != "" {
+				blob := filepath.Join(modelsPath, "blobs", strings.Replace(digest, ":", "-", 1))
+				model.blobs = append(model.blobs, blob)
+			}
+		}
+
+		models = append(models, model)
+		return nil
+	})
+
+	return models
+}
+
+// ollamaModelName turns a manifest path into the name shown by `ollama list`,
+// e.g. registry.ollama.ai/library/llama3/8b -> llama3:8b
+func ollamaModelName(manifestsPath, path string) string {
+	rel, err := filepath.Rel(manifestsPath, path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 2 {
+		return rel
+	}
+
+	tag := parts[len(parts)-1]
+	repo := parts[:len(parts)-1]
+
+	// Hide the default registry and namespace like the ollama CLI does
+	if len(repo) > 0 && repo[0] == "registry.ollama.ai" {
+		repo = repo[1:]
+	}
+	if len(repo) > 1 && repo[0] == "library" {
+		repo = repo[1:]
+	}
+
+	return strings.Join(repo, "/") + ":" + tag
+}
@@ -0,0 +1,119 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestScanOllamaModels(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	manifests := filepath.Join(tmpDir, "manifests", "registry.ollama.ai", "library")
+	createTestFile(t, manifests, "llama3/8b", `{"config": {"digest": "sha256:cfg1"}, "layers": [{"digest": "sha256:shared"}, {"digest": "sha256:w1"}]}`)
+	createTestFile(t, manifests, "mistral/latest", `{"config": {"digest": "sha256:cfg2"}, "layers": [{"digest": "sha256:shared"}, {"digest": "sha256:w2"}]}`)
+	// Another tag of the same model frees nothing
+	createTestFile(t, manifests, "mistral/7b", `{"config": {"digest": "sha256:cfg2"}, "layers": [{"digest": "sha256:shared"}, {"digest": "sha256:w2"}]}`)
+
+	for _, blob := range []string{"cfg1", "cfg2", "shared", "w1", "w2", "orphan"} {
+		createTestFile(t, tmpDir, "blobs/sha256-"+blob, "data-"+blob)
+	}
+
+	targets := scanOllamaModels(tmpDir)
+	if len(targets) != 2 {
+		t.Fatalf("Expected llama3:8b and the orphan blobs, got %d: %v", len(targets), targets)
+	}
+
+	byDescription := make(map[string]CleanTarget)
+	for _, target := range targets {
+		byDescription[target.Description] = target
+	}
+
+	llama, ok := byDescription["Ollama model llama3:8b"]
+	if !ok {
+		t.Fatalf("Expected llama3:8b target, got %v", targets)
+	}
+	for _, entry := range llama.Entries {
+		if filepath.Base(entry) == "sha256-shared" {
+			t.Error("Blob shared with another model must not be removed with llama3:8b")
+		}
+	}
+	if len(llama.Entries) != 3 {
+		t.Errorf("Expected manifest and 2 blobs, got %v", llama.Entries)
+	}
+	if llama.Path != filepath.Join(manifests, "llama3", "8b") {
+		t.Errorf("Expected the target named after its manifest, got %s", llama.Path)
+	}
+
+	orphans, ok := byDescription["Ollama unreferenced blobs (1 entries)"]
+	if !ok {
+		t.Fatalf("Expected orphan blobs target, got %v", targets)
+	}
+	if filepath.Base(orphans.Entries[0]) != "sha256-orphan" {
+		t.Errorf("Unexpected orphan entry %s", orphans.Entries[0])
+	}
+}
+
+func TestOllamaModelName(t *testing.T) {
+	manifests := filepath.Join("models", "manifests")
+
+	tests := []struct {
+		rel      string
+		expected string
+	}{
+		{"registry.ollama.ai/library/llama3/8b", "llama3:8b"},
+		{"registry.ollama.ai/user/custom/latest", "user/custom:latest"},
+		{"hf.co/org/model/Q4_K_M", "hf.co/org/model:Q4_K_M"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			got := ollamaModelName(manifests, filepath.Join(manifests, filepath.FromSlash(tt.rel)))
+			if got != tt.expected {
+				t.Errorf("ollamaModelName() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindModelFiles(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "lmstudio-community/Qwen/qwen-7b-Q4.gguf", "weights")
+	createTestFile(t, tmpDir, "lmstudio-community/Qwen/README.md", "readme")
+
+	files := findModelFiles(tmpDir)
+	if len(files) != 1 || filepath.Base(files[0]) != "qwen-7b-Q4.gguf" {
+		t.Errorf("Expected only the gguf file, got %v", files)
+	}
+}
+
+func TestLocalLLMCleaner_ScanLinkedFolders(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	createTestFile(t, home, ".lmstudio/models/lmstudio-community/qwen/qwen-7b.gguf", "weights")
+	// LM Studio's old folder, linked to the new one
+	if err := os.MkdirAll(filepath.Join(home, ".cache"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(home, ".lmstudio"), filepath.Join(home, ".cache", "lm-studio")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.Home = home
+	cfg.CleanLevel = config.Standard
+	targets, err := NewLocalLLMCleaner().Scan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Scan() returned error: %v", err)
+	}
+	if len(targets) != 1 {
+		t.Errorf("Expected the model once, got %+v", targets)
+	}
+}
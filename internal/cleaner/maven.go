@@ -0,0 +1,101 @@
+package cleaner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// mavenSnapshotMaxAge is how old a SNAPSHOT artifact version must be before
+// it is pruned, regardless of when it was last used
+const mavenSnapshotMaxAge = 7 * 24 * time.Hour
+
+// mavenPrune describes the artifact versions selected for removal from a
+// local Maven repository
+type mavenPrune struct {
+	entries   []string // Version directories to remove
+	size      int64    // Total size of entries
+	unused    int      // Versions not accessed within the cutoff
+	snapshots int      // Stale SNAPSHOT versions
+}
+
+// pruneMavenRepository walks a local Maven repository and selects artifact
+// versions that have not been accessed since the cutoff, plus SNAPSHOT
+// versions older than a week. Everything else in the repository is kept.
+func pruneMavenRepository(repoPath string, cutoff time.Time) mavenPrune {
+	var prune mavenPrune
+	snapshotCutoff := time.Now().Add(-mavenSnapshotMaxAge)
+
+	filepath.WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
+		utils.Throttle()
+		if err != nil || !d.IsDir() || !isMavenVersionDir(path) {
+			return nil
+		}
+
+		lastUsed, modified := mavenVersionTimes(path)
+
+		switch {
+		case strings.HasSuffix(d.Name(), "-SNAPSHOT") && modified.Before(snapshotCutoff):
+			prune.snapshots++
+		case lastUsed.Before(cutoff):
+			prune.unused++
+		default:
+			return filepath.SkipDir
+		}
+
+		size, _ := utils.GetDirSize(path)
+		prune.size += size
+		prune.entries = append(prune.entries, path)
+
+		return filepath.SkipDir
+	})
+
+	return prune
+}
+
+// describe returns a human-readable description of the prune
+func (p mavenPrune) describe(days int) string {
+	return fmt.Sprintf("Maven artifacts unused for %d+ days (%d versions, %d old snapshots)", days, p.unused, p.snapshots)
+}
+
+// isMavenVersionDir reports whether a directory holds a single artifact
+// version, i.e. it directly contains a .pom file
+func isMavenVersionDir(path string) bool {
+	matches, _ := filepath.Glob(filepath.Join(path, "*.pom"))
+	return len(matches) > 0
+}
+
+// mavenVersionTimes returns the most recent access time (or modification
+// time, whichever is later) and the most recent modification time of the
+// files in an artifact version directory
+func mavenVersionTimes(path string) (lastUsed, modified time.Time) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return lastUsed, modified
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		if info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+		if accessed := utils.AccessTime(info); accessed.After(lastUsed) {
+			lastUsed = accessed
+		}
+	}
+
+	if modified.After(lastUsed) {
+		lastUsed = modified
+	}
+
+	return lastUsed, modified
+}
@@ -0,0 +1,72 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPruneMavenRepository(t *testing.T) {
+	repo := setupTestDir(t)
+	defer os.RemoveAll(repo)
+
+	createTestFile(t, repo, "org/example/lib/1.0/lib-1.0.pom", "<project/>")
+	createTestFile(t, repo, "org/example/lib/1.0/lib-1.0.jar", "old jar")
+	createTestFile(t, repo, "org/example/lib/2.0/lib-2.0.pom", "<project/>")
+	createTestFile(t, repo, "org/example/lib/2.0/lib-2.0.jar", "new jar")
+	createTestFile(t, repo, "org/example/app/3.0-SNAPSHOT/app-3.0-SNAPSHOT.pom", "<project/>")
+	createTestFile(t, repo, "org/example/app/4.0-SNAPSHOT/app-4.0-SNAPSHOT.pom", "<project/>")
+
+	unused := time.Now().AddDate(0, 0, -200)
+	setOldTimes(t, filepath.Join(repo, "org/example/lib/1.0"), unused)
+
+	lastWeek := time.Now().AddDate(0, 0, -10)
+	setOldTimes(t, filepath.Join(repo, "org/example/app/3.0-SNAPSHOT"), lastWeek)
+
+	prune := pruneMavenRepository(repo, time.Now().AddDate(0, 0, -90))
+
+	if prune.unused != 1 {
+		t.Errorf("Expected 1 unused version, got %d", prune.unused)
+	}
+	if prune.snapshots != 1 {
+		t.Errorf("Expected 1 stale snapshot, got %d", prune.snapshots)
+	}
+
+	expected := map[string]bool{
+		filepath.Join(repo, "org/example/lib/1.0"):          true,
+		filepath.Join(repo, "org/example/app/3.0-SNAPSHOT"): true,
+	}
+	if len(prune.entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %v", len(expected), prune.entries)
+	}
+	for _, entry := range prune.entries {
+		if !expected[entry] {
+			t.Errorf("Unexpected entry %s", entry)
+		}
+	}
+}
+
+func TestPruneMavenRepository_Empty(t *testing.T) {
+	repo := setupTestDir(t)
+	defer os.RemoveAll(repo)
+
+	prune := pruneMavenRepository(repo, time.Now())
+	if len(prune.entries) != 0 || prune.size != 0 {
+		t.Errorf("Expected nothing to prune, got %+v", prune)
+	}
+}
+
+func TestIsMavenVersionDir(t *testing.T) {
+	repo := setupTestDir(t)
+	defer os.RemoveAll(repo)
+
+	createTestFile(t, repo, "g/a/1.0/a-1.0.pom", "<project/>")
+
+	if !isMavenVersionDir(filepath.Join(repo, "g/a/1.0")) {
+		t.Error("Expected directory with a .pom to be a version dir")
+	}
+	if isMavenVersionDir(filepath.Join(repo, "g/a")) {
+		t.Error("Expected artifact directory not to be a version dir")
+	}
+}
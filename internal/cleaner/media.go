@@ -0,0 +1,90 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+)
+
+// mediaCache is a folder of a macOS app that often grows large on developer
+// Macs but holds user content rather than rebuildable artifacts. These are
+// off by default: each category has to be enabled in the config file.
+type mediaCache struct {
+	path        string
+	category    string
+	description string
+	safety      config.SafetyLevel
+}
+
+// mediaCaches returns the Mail, Messages, Music, Podcasts and QuickLook
+// folders; tmpDir is the per-user temporary directory QuickLook's cache sits
+// next to
+func mediaCaches(home, tmpDir string) []mediaCache {
+	caches := []mediaCache{
+		{
+			path:        filepath.Join(home, "Library", "Containers", "com.apple.mail", "Data", "Library", "Mail Downloads"),
+			category:    "mail_downloads",
+			description: "Mail attachments opened from messages",
+			safety:      config.Moderate,
+		},
+		{
+			path:        filepath.Join(home, "Library", "Messages", "Attachments"),
+			category:    "messages_attachments",
+			description: "Messages attachments (DANGEROUS - removed from conversations)",
+			safety:      config.Dangerous,
+		},
+		{
+			path:        filepath.Join(platform.CacheDir(home), "com.apple.Music"),
+			category:    "music_cache",
+			description: "Music streaming and artwork cache",
+			safety:      config.Moderate,
+		},
+		{
+			path:        filepath.Join(home, "Library", "Group Containers", "243LU875E5.groups.com.apple.podcasts", "Library", "Cache"),
+			category:    "podcasts_cache",
+			description: "Podcasts episode cache",
+			safety:      config.Moderate,
+		},
+	}
+
+	if cacheDir, ok := darwinUserCacheDir(tmpDir); ok {
+		caches = append(caches, mediaCache{
+			path:        filepath.Join(cacheDir, "com.apple.QuickLook.thumbnailcache"),
+			category:    "quicklook_thumbnails",
+			description: "QuickLook thumbnails",
+			safety:      config.Moderate,
+		})
+	}
+
+	return caches
+}
+
+// scanMediaCaches returns one target per media folder the user opted into,
+// holding the folder's content so the folder itself stays in place
+func scanMediaCaches(cfg *config.Config, caches []mediaCache) []CleanTarget {
+	targets := []CleanTarget{}
+
+	for _, cache := range caches {
+		if !cfg.OptedIn(config.DomainSystem, cache.category) || !cfg.Allows(config.DomainSystem, cache.category, cache.safety) {
+			continue
+		}
+
+		entries, err := os.ReadDir(cache.path)
+		if err != nil {
+			continue
+		}
+
+		paths := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			paths = append(paths, filepath.Join(cache.path, entry.Name()))
+		}
+
+		if target, ok := entriesTarget(cache.path, paths, cache.category, cache.description, cache.safety); ok {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
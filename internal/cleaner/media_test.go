@@ -0,0 +1,79 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestMediaCaches_QuickLook(t *testing.T) {
+	home := t.TempDir()
+
+	caches := mediaCaches(home, "/var/folders/ab/xyz123/T/")
+	last := caches[len(caches)-1]
+	if last.category != "quicklook_thumbnails" || last.path != "/var/folders/ab/xyz123/C/com.apple.QuickLook.thumbnailcache" {
+		t.Errorf("Unexpected QuickLook cache %+v", last)
+	}
+
+	// Not a macOS per-user temporary directory
+	for _, cache := range mediaCaches(home, "/tmp") {
+		if cache.category == "quicklook_thumbnails" {
+			t.Errorf("Expected no QuickLook cache outside /var/folders, got %s", cache.path)
+		}
+	}
+}
+
+func TestScanMediaCaches(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	createTestFile(t, home, "Mail Downloads/ABC/report.pdf", "pdf content")
+	createTestFile(t, home, "Mail Downloads/DEF/photo.jpg", "jpg content")
+	createTestFile(t, home, "Attachments/00/01/video.mov", "mov content")
+
+	caches := []mediaCache{
+		{path: filepath.Join(home, "Mail Downloads"), category: "mail_downloads", description: "Mail downloads", safety: config.Moderate},
+		{path: filepath.Join(home, "Attachments"), category: "messages_attachments", description: "Messages attachments", safety: config.Dangerous},
+	}
+
+	// Off by default
+	cfg := config.NewDefaultConfig()
+	cfg.CleanLevel = config.Aggressive
+	if targets := scanMediaCaches(cfg, caches); len(targets) != 0 {
+		t.Fatalf("Expected no targets without opting in, got %v", targets)
+	}
+
+	enabled := true
+	cfg.Overrides = map[string]map[string]config.Override{
+		"system": {
+			"mail_downloads":       {Enabled: &enabled},
+			"messages_attachments": {Enabled: &enabled},
+		},
+	}
+
+	tests := []struct {
+		level    config.CleanLevel
+		expected int
+	}{
+		{config.Conservative, 0},
+		{config.Standard, 1},
+		{config.Aggressive, 2},
+	}
+
+	for _, tt := range tests {
+		cfg.CleanLevel = tt.level
+		targets := scanMediaCaches(cfg, caches)
+		if len(targets) != tt.expected {
+			t.Errorf("Level %v: expected %d targets, got %d", tt.level, tt.expected, len(targets))
+		}
+	}
+
+	// The folders themselves are kept, only their content is removed
+	cfg.CleanLevel = config.Standard
+	target := scanMediaCaches(cfg, caches)[0]
+	if target.Path != filepath.Join(home, "Mail Downloads") || len(target.Entries) != 2 {
+		t.Errorf("Expected the two Mail downloads as entries, got %+v", target)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
 	"github.com/0SansNom/epurer/internal/scanner"
 	"github.com/0SansNom/epurer/pkg/utils"
 )
@@ -13,6 +14,7 @@ import (
 // MobileCleaner handles mobile development cleanup (iOS, Android, Flutter)
 type MobileCleaner struct {
 	scanner *scanner.Scanner
+	runner  CommandRunner // Lists the running JVM builds and runs gradle --stop before the Gradle cache is deleted, ExecRunner if nil
 }
 
 // NewMobileCleaner creates a new MobileCleaner
@@ -27,26 +29,42 @@ func NewMobileCleaner() (Cleaner, error) {
 	}, nil
 }
 
+// SetRunner replaces the runner of the cleaner's external commands
+func (m *MobileCleaner) SetRunner(runner CommandRunner) {
+	m.runner = runner
+}
+
+// commands returns the runner of the cleaner's external commands
+func (m *MobileCleaner) commands() CommandRunner {
+	if m.runner == nil {
+		return ExecRunner{}
+	}
+	return m.runner
+}
+
 func (m *MobileCleaner) Name() string {
 	return "Mobile"
 }
 
 func (m *MobileCleaner) Domain() config.Domain {
-	return config.DomainFrontend // TODO: Add DomainMobile to config
+	return config.DomainMobile
 }
 
 func (m *MobileCleaner) Detect(ctx context.Context) (bool, error) {
 	// Check if Xcode, Android Studio, or Flutter are present
-	hasXcode := utils.PathExists("/Applications/Xcode.app")
-	hasAndroid := utils.CommandExists("adb") || utils.PathExists(filepath.Join(os.Getenv("HOME"), "Library/Android"))
-	hasFlutter := utils.CommandExists("flutter")
+	det := detector.FromContext(ctx)
+	hasXcode := det.HasPath("/Applications/Xcode.app")
+	hasAndroid := det.HasCommand("adb") || det.HasPath(filepath.Join(os.Getenv("HOME"), "Library/Android"))
+	hasFlutter := det.HasCommand("flutter")
 
 	return hasXcode || hasAndroid || hasFlutter, nil
 }
 
 func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	configureScanner(m.scanner, cfg)
+
 	targets := []CleanTarget{}
-	home, err := os.UserHomeDir()
+	home, err := cfg.HomeDir()
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +78,7 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        derivedDataPath,
+				Category:    "xcode_derived_data",
 				Description: "Xcode DerivedData (rebuilds automatically)",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -68,13 +87,16 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 	}
 
 	// Archives (Moderate - old app versions)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
+	if cfg.Allows(config.DomainMobile, "xcode_archives", config.Moderate) {
 		archivesPath := filepath.Join(home, "Library", "Developer", "Xcode", "Archives")
-		if utils.PathExists(archivesPath) {
+		if keep, ok := xcodeArchivesRetention(cfg); ok {
+			targets = append(targets, retainedXcodeArchives(archivesPath, keep, config.Moderate)...)
+		} else if utils.PathExists(archivesPath) {
 			size, _ := utils.GetDirSize(archivesPath)
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        archivesPath,
+					Category:    "xcode_archives",
 					Description: "Xcode Archives (old app versions)",
 					SizeBytes:   size,
 					Safety:      config.Moderate,
@@ -90,6 +112,7 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        moduleCachePath,
+				Category:    "xcode_module_cache",
 				Description: "Xcode Module Cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -104,6 +127,7 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        deviceSupportPath,
+				Category:    "ios_device_support",
 				Description: "iOS Device Support symbols",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -118,6 +142,7 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        watchDeviceSupportPath,
+				Category:    "watchos_device_support",
 				Description: "watchOS Device Support symbols",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -132,6 +157,7 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        tvDeviceSupportPath,
+				Category:    "tvos_device_support",
 				Description: "tvOS Device Support symbols",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -140,13 +166,14 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 	}
 
 	// CoreSimulator Caches (Moderate)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
+	if cfg.Allows(config.DomainMobile, "simulator_caches", config.Moderate) {
 		simCachePath := filepath.Join(home, "Library", "Developer", "CoreSimulator", "Caches")
 		if utils.PathExists(simCachePath) {
 			size, _ := utils.GetDirSize(simCachePath)
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        simCachePath,
+					Category:    "simulator_caches",
 					Description: "iOS Simulator caches",
 					SizeBytes:   size,
 					Safety:      config.Moderate,
@@ -156,11 +183,14 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 
 		// Old simulator devices (can be huge)
 		devicesPath := filepath.Join(home, "Library", "Developer", "CoreSimulator", "Devices")
-		if utils.PathExists(devicesPath) {
+		if keep, ok := cfg.Retention(config.DomainMobile, "simulator_devices"); ok {
+			targets = append(targets, retainedSimulators(devicesPath, keep)...)
+		} else if utils.PathExists(devicesPath) {
 			size, _ := utils.GetDirSize(devicesPath)
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        devicesPath,
+					Category:    "simulator_devices",
 					Description: "iOS Simulator devices (can be recreated)",
 					SizeBytes:   size,
 					Safety:      config.Moderate,
@@ -176,6 +206,7 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        xcodeCachePath,
+				Category:    "xcode_cache",
 				Description: "Xcode general cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -183,11 +214,27 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		}
 	}
 
-	// === Android ===
+	// === Swift Package Manager / Carthage / Tuist ===
+
+	// Package and build caches (Safe - fetched again on resolve). Project
+	// .build and Carthage/Build folders come from the pattern registry.
+	for _, cache := range swiftToolCaches(home) {
+		if !cfg.Allows(config.DomainMobile, cache.category, config.Safe) {
+			continue
+		}
+		size, _ := utils.GetDirSize(cache.path)
+		if size > 0 {
+			targets = append(targets, CleanTarget{
+				Path:        cache.path,
+				Category:    cache.category,
+				Description: cache.description,
+				SizeBytes:   size,
+				Safety:      config.Safe,
+			})
+		}
+	}
 
-	// Android build folders (Safe - rebuilt)
-	androidBuildTargets := m.scanAndroidBuildFolders(ctx)
-	targets = append(targets, androidBuildTargets...)
+	// === Android ===
 
 	// Gradle cache (Safe)
 	gradleCachePath := filepath.Join(home, ".gradle", "caches")
@@ -196,6 +243,7 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        gradleCachePath,
+				Category:    "gradle_cache",
 				Description: "Gradle cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -204,7 +252,7 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 	}
 
 	// Android SDK build cache (Safe)
-	androidSDKPath := filepath.Join(home, "Library", "Android", "sdk")
+	androidSDKPath := androidSDKDir(home)
 	if utils.PathExists(androidSDKPath) {
 		buildCachePath := filepath.Join(androidSDKPath, "build-cache")
 		if utils.PathExists(buildCachePath) {
@@ -212,6 +260,7 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        buildCachePath,
+					Category:    "android_build_cache",
 					Description: "Android SDK build cache",
 					SizeBytes:   size,
 					Safety:      config.Safe,
@@ -220,14 +269,27 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		}
 	}
 
+	// SDK platforms, system images and NDKs no local project uses
+	// (Moderate), emulator copies left by updates (Safe)
+	if utils.PathExists(androidSDKPath) {
+		refs := scanAndroidReferences(ctx, m.scanner)
+		avdLevels := avdSystemImages(filepath.Join(home, ".android", "avd"))
+		for _, target := range androidSDKTargets(androidSDKPath, refs, avdLevels) {
+			if cfg.Allows(config.DomainMobile, target.Category, target.Safety) {
+				targets = append(targets, target)
+			}
+		}
+	}
+
 	// AVD (Android Virtual Devices) - Moderate
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
+	if cfg.Allows(config.DomainMobile, "android_avds", config.Moderate) {
 		avdPath := filepath.Join(home, ".android", "avd")
 		if utils.PathExists(avdPath) {
 			size, _ := utils.GetDirSize(avdPath)
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        avdPath,
+					Category:    "android_avds",
 					Description: "Android Virtual Devices",
 					SizeBytes:   size,
 					Safety:      config.Moderate,
@@ -245,6 +307,7 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        podsCachePath,
+				Category:    "cocoapods_cache",
 				Description: "CocoaPods cache",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -252,123 +315,53 @@ func (m *MobileCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 		}
 	}
 
-	// === Flutter ===
-
-	// .dart_tool (Safe - rebuilt)
-	dartToolTargets := m.scanDartTool(ctx)
-	targets = append(targets, dartToolTargets...)
+	// === Flutter / Dart ===
 
-	// Flutter build (Safe)
-	flutterBuildTargets := m.scanFlutterBuild(ctx)
-	targets = append(targets, flutterBuildTargets...)
-
-	return targets, nil
-}
-
-func (m *MobileCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
-	results := make([]CleanResult, 0, len(targets))
-
-	for _, target := range targets {
-		result := CleanResult{
-			Target:  target,
-			Success: true,
-		}
-
-		if !dryRun {
-			err := utils.SafeRemove(target.Path, false)
-			if err != nil {
-				result.Success = false
-				result.Error = err
-			} else {
-				result.BytesFreed = target.SizeBytes
-			}
-		} else {
-			result.BytesFreed = target.SizeBytes
-		}
-
-		results = append(results, result)
-
-		select {
-		case <-ctx.Done():
-			return results, ctx.Err()
-		default:
+	// Old pub package versions, engine artifacts of Flutter SDKs not in use
+	// and the analysis server cache (Safe - downloaded again when needed)
+	for _, target := range scanFlutterCaches(home, activeFlutterSDK()) {
+		if cfg.Allows(config.DomainMobile, target.Category, target.Safety) {
+			targets = append(targets, target)
 		}
 	}
 
-	return results, nil
-}
-
-// scanAndroidBuildFolders scans for Android build folders
-func (m *MobileCleaner) scanAndroidBuildFolders(ctx context.Context) []CleanTarget {
-	targets := []CleanTarget{}
-
-	// Look for app/build or build folders in Android projects
-	resultChan := m.scanner.FindByPattern(ctx, "build")
-	for result := range resultChan {
-		if result.Err != nil {
-			continue
-		}
+	// === React Native ===
 
-		// Check if this is an Android build folder
-		// (contains typical Android build artifacts)
-		parent := filepath.Dir(result.Path)
-		if filepath.Base(parent) == "app" || utils.PathExists(filepath.Join(parent, "gradle.properties")) {
-			targets = append(targets, CleanTarget{
-				Path:        result.Path,
-				Description: "Android build output",
-				SizeBytes:   result.Size,
-				Safety:      config.Safe,
-			})
+	// Metro bundler caches and the Watchman log (Safe - rebuilt on start)
+	for _, target := range scanReactNativeCaches(home, os.TempDir()) {
+		if cfg.Allows(config.DomainMobile, target.Category, target.Safety) {
+			targets = append(targets, target)
 		}
 	}
 
-	return targets
-}
+	// === Project folders ===
 
-// scanDartTool scans for .dart_tool folders (Flutter/Dart)
-func (m *MobileCleaner) scanDartTool(ctx context.Context) []CleanTarget {
-	targets := []CleanTarget{}
+	// Android and Flutter build folders, .dart_tool, Swift package .build,
+	// Carthage/Build, android/.gradle and native .cxx folders (Safe -
+	// rebuilt), CocoaPods Pods folders (Moderate - needs pod install)
+	projectTargets := scanProjectPatterns(ctx, m.scanner, cfg, config.DomainMobile)
+	targets = append(targets, projectTargets...)
 
-	resultChan := m.scanner.FindByPattern(ctx, ".dart_tool")
-	for result := range resultChan {
-		if result.Err != nil {
-			continue
-		}
+	return targets, nil
+}
 
-		targets = append(targets, CleanTarget{
-			Path:        result.Path,
-			Description: "Flutter/Dart build cache",
-			SizeBytes:   result.Size,
-			Safety:      config.Safe,
-		})
-	}
+func (m *MobileCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanJVMTargets(ctx, m.commands(), targets, dryRun)
+}
 
-	return targets
+func (m *MobileCleaner) Patterns() []string {
+	return append(projectPatternNames(config.DomainMobile), "build.gradle*")
 }
 
-// scanFlutterBuild scans for Flutter build folders
-func (m *MobileCleaner) scanFlutterBuild(ctx context.Context) []CleanTarget {
-	targets := []CleanTarget{}
+// swiftToolCaches returns the global caches of Swift Package Manager,
+// Carthage and Tuist
+func swiftToolCaches(home string) []toolCache {
+	caches := filepath.Join(home, "Library", "Caches")
 
-	// Flutter projects have a "build" folder at the root
-	resultChan := m.scanner.FindByPattern(ctx, "build")
-	for result := range resultChan {
-		if result.Err != nil {
-			continue
-		}
-
-		// Check if this is a Flutter project by looking for pubspec.yaml
-		parent := filepath.Dir(result.Path)
-		pubspecPath := filepath.Join(parent, "pubspec.yaml")
-		if utils.PathExists(pubspecPath) {
-			targets = append(targets, CleanTarget{
-				Path:        result.Path,
-				Description: "Flutter build output",
-				SizeBytes:   result.Size,
-				Safety:      config.Safe,
-			})
-		}
+	return []toolCache{
+		{path: filepath.Join(caches, "org.swift.swiftpm"), category: "swiftpm_cache", description: "Swift Package Manager cache"},
+		{path: filepath.Join(caches, "org.carthage.CarthageKit"), category: "carthage_cache", description: "Carthage cache"},
+		{path: filepath.Join(home, ".cache", "tuist"), category: "tuist_cache", description: "Tuist cache"},
+		{path: filepath.Join(home, ".tuist", "Cache"), category: "tuist_cache", description: "Tuist cache (Tuist 3)"},
 	}
-
-	return targets
 }
@@ -0,0 +1,82 @@
+package cleaner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+// monorepoCaches find the caches of monorepo task runners and of the Angular
+// CLI, at the root of a monorepo or in its packages. The segments of a
+// pattern after ** lead from the package the cache belongs to.
+var monorepoCaches = []struct {
+	pattern, category, description string
+}{
+	{".turbo", "turbo_cache", "Turborepo cache"},
+	{"**/node_modules/.cache/turbo", "turbo_cache", "Turborepo cache"},
+	{"**/node_modules/.cache/nx", "nx_cache", "Nx cache"},
+	{"**/.nx/cache", "nx_cache", "Nx cache"},
+	{"**/.angular/cache", "angular_cache", "Angular CLI cache"},
+}
+
+// monorepoCachePatterns returns the scanner patterns of monorepoCaches
+func monorepoCachePatterns() []string {
+	patterns := []string{}
+	for _, cache := range monorepoCaches {
+		patterns = append(patterns, cache.pattern)
+	}
+	return patterns
+}
+
+// scanMonorepoCaches finds the Turborepo, Nx and Angular caches, each named
+// after the workspace package it belongs to
+func scanMonorepoCaches(ctx context.Context, s *scanner.Scanner, cfg *config.Config) []CleanTarget {
+	targets := []CleanTarget{}
+
+	for _, cache := range monorepoCaches {
+		if !cfg.Allows(config.DomainFrontend, cache.category, config.Safe) {
+			continue
+		}
+		depth := strings.Count(strings.TrimPrefix(cache.pattern, "**/"), "/") + 1
+		for result := range s.FindByPattern(ctx, cache.pattern) {
+			if result.Err != nil || result.Size == 0 {
+				continue
+			}
+
+			project := result.Path
+			for i := 0; i < depth; i++ {
+				project = filepath.Dir(project)
+			}
+			targets = append(targets, CleanTarget{
+				Path:        result.Path,
+				Category:    cache.category,
+				Description: cache.description + " (workspace " + workspaceName(project) + ")",
+				SizeBytes:   result.Size,
+				Files:       result.Files,
+				Dirs:        result.Dirs,
+				Safety:      config.Safe,
+			})
+		}
+	}
+
+	return targets
+}
+
+// workspaceName returns the name of the package in dir, from its
+// package.json, or the name of the folder
+func workspaceName(dir string) string {
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var manifest struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(data, &manifest) == nil && manifest.Name != "" {
+			return manifest.Name
+		}
+	}
+	return filepath.Base(dir)
+}
@@ -0,0 +1,59 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+func TestScanMonorepoCaches(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestDir(t, tmpDir, "acme", map[string]string{
+		"package.json":                          `{"name": "acme", "workspaces": ["apps/*"]}`,
+		".turbo/cookies/1.cookie":               "cookie",
+		"node_modules/.cache/turbo/abc.tar.zst": "outputs",
+		".nx/cache/123/outputs.tar":             "outputs",
+		"apps/web/package.json":                 `{"name": "@acme/web"}`,
+		"apps/web/.turbo/turbo-build.log":       "log",
+		"apps/web/node_modules/.cache/nx/456":   "outputs",
+		"apps/admin/.angular/cache/17/babel":    "babel",
+	})
+
+	s, _ := scanner.NewScannerWithDirs([]string{tmpDir})
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	found := make(map[string]CleanTarget)
+	for _, target := range scanMonorepoCaches(ctx, s, config.NewDefaultConfig()) {
+		rel, _ := filepath.Rel(tmpDir, target.Path)
+		found[filepath.ToSlash(rel)] = target
+	}
+
+	expected := map[string]string{
+		"acme/.turbo":                          "Turborepo cache (workspace acme)",
+		"acme/node_modules/.cache/turbo":       "Turborepo cache (workspace acme)",
+		"acme/.nx/cache":                       "Nx cache (workspace acme)",
+		"acme/apps/web/.turbo":                 "Turborepo cache (workspace @acme/web)",
+		"acme/apps/web/node_modules/.cache/nx": "Nx cache (workspace @acme/web)",
+		"acme/apps/admin/.angular/cache":       "Angular CLI cache (workspace admin)",
+	}
+	if len(found) != len(expected) {
+		t.Errorf("Expected %d targets, got %+v", len(expected), found)
+	}
+	for rel, description := range expected {
+		target, ok := found[rel]
+		if !ok {
+			t.Errorf("Expected a target for %s", rel)
+			continue
+		}
+		if target.Description != description || target.Safety != config.Safe {
+			t.Errorf("Unexpected target for %s: %+v", rel, target)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// NativeCleaner handles C/C++ development cleanup (ccache, CMake, Conan,
+// vcpkg, Ninja)
+type NativeCleaner struct {
+	scanner *scanner.Scanner
+	runner  CommandRunner // Runs ccache
+}
+
+// vcpkgDirs are the folders of a vcpkg checkout that installs bring back
+var vcpkgDirs = []struct {
+	name, category, description string
+}{
+	{"buildtrees", "vcpkg_buildtrees", "vcpkg build trees"},
+	{"downloads", "vcpkg_downloads", "vcpkg downloaded sources"},
+	{"packages", "vcpkg_packages", "vcpkg staged packages"},
+}
+
+// NewNativeCleaner creates a new NativeCleaner
+func NewNativeCleaner() (Cleaner, error) {
+	s, err := scanner.NewScanner()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NativeCleaner{
+		scanner: s,
+		runner:  ExecRunner{},
+	}, nil
+}
+
+// SetRunner replaces the runner of the cleaner's external commands
+func (n *NativeCleaner) SetRunner(runner CommandRunner) {
+	n.runner = runner
+}
+
+func (n *NativeCleaner) Name() string {
+	return "C/C++"
+}
+
+func (n *NativeCleaner) Domain() config.Domain {
+	return config.DomainBackend
+}
+
+func (n *NativeCleaner) Detect(ctx context.Context) (bool, error) {
+	return detector.FromContext(ctx).HasCommand("cmake", "ccache", "conan", "vcpkg", "ninja"), nil
+}
+
+func (n *NativeCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	configureScanner(n.scanner, cfg)
+
+	targets := []CleanTarget{}
+	home, err := cfg.HomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// === Compiler and package caches ===
+
+	// ccache (Safe - filled again as sources compile), keeping its config
+	if cfg.Allows(config.DomainBackend, "ccache", config.Safe) {
+		if target, ok := n.ccacheTarget(home); ok {
+			targets = append(targets, target)
+		}
+	}
+
+	// Conan 2 package cache (Moderate - packages are downloaded or built again)
+	conanPath := filepath.Join(home, ".conan2", "p")
+	if cfg.Allows(config.DomainBackend, "conan_packages", config.Moderate) {
+		if size, _ := utils.GetDirSize(conanPath); size > 0 {
+			targets = append(targets, CleanTarget{
+				Path:        conanPath,
+				Category:    "conan_packages",
+				Description: "Conan package cache",
+				SizeBytes:   size,
+				Safety:      config.Moderate,
+			})
+		}
+	}
+
+	// vcpkg build trees, downloads and staged packages (Safe - installed
+	// ports are kept in installed/)
+	for _, root := range vcpkgRoots(home) {
+		for _, dir := range vcpkgDirs {
+			path := filepath.Join(root, dir.name)
+			if size, _ := utils.GetDirSize(path); size > 0 && cfg.Allows(config.DomainBackend, dir.category, config.Safe) {
+				targets = append(targets, CleanTarget{
+					Path:        path,
+					Category:    dir.category,
+					Description: dir.description,
+					SizeBytes:   size,
+					Safety:      config.Safe,
+				})
+			}
+		}
+	}
+
+	// === Projects ===
+
+	// CMake build directories and Ninja leftovers (Safe - rebuilt by the
+	// next configure and build)
+	if cfg.Allows(config.DomainBackend, "cmake_build", config.Safe) {
+		targets = append(targets, n.scanCMakeBuilds(ctx)...)
+	}
+	if cfg.Allows(config.DomainBackend, "ninja_log", config.Safe) {
+		targets = append(targets, n.scanNinjaLeftovers(ctx)...)
+	}
+
+	return targets, nil
+}
+
+func (n *NativeCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanTargets(ctx, targets, dryRun)
+}
+
+func (n *NativeCleaner) Patterns() []string {
+	return []string{"CMakeCache.txt", ".ninja_log"}
+}
+
+// ccacheTarget returns the content of the ccache folder but its config, with
+// the hit rate `ccache -s` reports, if there is a ccache folder
+func (n *NativeCleaner) ccacheTarget(home string) (CleanTarget, bool) {
+	dir := ""
+	if output, err := n.runner.Output("ccache", "--get-config", "cache_dir"); err == nil {
+		dir = strings.TrimSpace(string(output))
+	}
+	if dir == "" {
+		candidates := []string{
+			os.Getenv("CCACHE_DIR"),
+			filepath.Join(home, "Library", "Caches", "ccache"),
+			filepath.Join(home, ".cache", "ccache"),
+			filepath.Join(home, ".ccache"),
+		}
+		for _, candidate := range candidates {
+			if candidate != "" && utils.PathExists(candidate) {
+				dir = candidate
+				break
+			}
+		}
+	}
+	if dir == "" {
+		return CleanTarget{}, false
+	}
+
+	children, err := os.ReadDir(dir)
+	if err != nil {
+		return CleanTarget{}, false
+	}
+	var entries []string
+	for _, child := range children {
+		if child.Name() != "ccache.conf" {
+			entries = append(entries, filepath.Join(dir, child.Name()))
+		}
+	}
+
+	description := "ccache compiler cache"
+	if output, err := n.runner.Output("ccache", "-s"); err == nil {
+		if rate := ccacheHitRate(string(output)); rate != "" {
+			description += ", " + rate + " hit rate"
+		}
+	}
+	return entriesTarget(dir, entries, "ccache", description, config.Safe)
+}
+
+// ccacheHitRate returns the hit rate in the output of `ccache -s`: a
+// percentage after "Hits:" (ccache 4) or "cache hit rate" (ccache 3)
+func ccacheHitRate(stats string) string {
+	for _, line := range strings.Split(stats, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "Hits:"):
+			open, end := strings.LastIndex(line, "("), strings.LastIndex(line, ")")
+			if open >= 0 && end > open {
+				return strings.TrimSpace(line[open+1 : end])
+			}
+		case strings.HasPrefix(line, "cache hit rate"):
+			fields := strings.Fields(strings.TrimPrefix(line, "cache hit rate"))
+			return strings.Join(fields, " ")
+		}
+	}
+	return ""
+}
+
+// vcpkgRoots returns the vcpkg checkouts: VCPKG_ROOT and ~/vcpkg
+func vcpkgRoots(home string) []string {
+	candidates := []string{os.Getenv("VCPKG_ROOT"), filepath.Join(home, "vcpkg")}
+
+	roots := []string{}
+	for _, root := range candidates {
+		if root == "" || !utils.PathExists(filepath.Join(root, ".vcpkg-root")) {
+			continue
+		}
+		if !slices.Contains(roots, root) {
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
+
+// scanCMakeBuilds finds the build directories CMake configured, by their
+// CMakeCache.txt and CMakeFiles. In-source builds, configured in the folder
+// of the CMakeLists.txt, and search directories are left alone, and so are
+// the builds inside another one, such as FetchContent's _deps/*-build.
+func (n *NativeCleaner) scanCMakeBuilds(ctx context.Context) []CleanTarget {
+	dirs := []string{}
+	for result := range n.scanner.FindByPattern(ctx, "CMakeCache.txt") {
+		if result.Err != nil {
+			continue
+		}
+		dir := filepath.Dir(result.Path)
+		if !utils.PathExists(filepath.Join(dir, "CMakeFiles")) || utils.PathExists(filepath.Join(dir, "CMakeLists.txt")) ||
+			slices.Contains(n.scanner.GetSearchDirs(), dir) {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+
+	// Sorted, a build comes before the ones inside it
+	slices.Sort(dirs)
+	builds := []string{}
+	targets := []CleanTarget{}
+	for _, dir := range dirs {
+		if slices.ContainsFunc(builds, func(build string) bool { return utils.HasPathPrefix(dir, build) }) {
+			continue
+		}
+		builds = append(builds, dir)
+
+		size, files, err := utils.GetDirUsage(dir)
+		if err != nil || size == 0 {
+			continue
+		}
+		targets = append(targets, CleanTarget{
+			Path:        dir,
+			Category:    "cmake_build",
+			Description: "CMake build directory (" + filepath.Base(dir) + ")",
+			SizeBytes:   size,
+			Files:       files,
+			Safety:      config.Safe,
+		})
+	}
+
+	return targets
+}
+
+// scanNinjaLeftovers finds the logs Ninja leaves in folders whose build was
+// removed: a .ninja_log, and its .ninja_deps, without a build.ninja
+func (n *NativeCleaner) scanNinjaLeftovers(ctx context.Context) []CleanTarget {
+	targets := []CleanTarget{}
+
+	for result := range n.scanner.FindByPattern(ctx, ".ninja_log") {
+		if result.Err != nil {
+			continue
+		}
+		dir := filepath.Dir(result.Path)
+		if utils.PathExists(filepath.Join(dir, "build.ninja")) {
+			continue
+		}
+
+		entries := []string{result.Path}
+		if deps := filepath.Join(dir, ".ninja_deps"); utils.PathExists(deps) {
+			entries = append(entries, deps)
+		}
+		if target, ok := entriesTarget(dir, entries, "ninja_log", "Ninja logs of a removed build", config.Safe); ok {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
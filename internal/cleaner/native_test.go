@@ -0,0 +1,124 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+func TestCcacheHitRate(t *testing.T) {
+	tests := []struct {
+		stats    string
+		expected string
+	}{
+		{"Cacheable calls:   1234 / 1300 (94.92%)\n  Hits:             800 / 1234 (64.83%)\n    Direct:         700 /  800 (87.50%)\n  Misses:           434 / 1234 (35.17%)\n", "64.83%"},
+		{"cache hit (direct)                   700\ncache hit rate                     64.83 %\n", "64.83 %"},
+		{"Local storage:\n  Cache size (GB): 0.0 / 5.0 ( 0.00%)\n", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ccacheHitRate(tt.stats); got != tt.expected {
+			t.Errorf("ccacheHitRate(%q) = %q, expected %q", tt.stats, got, tt.expected)
+		}
+	}
+}
+
+func TestNativeCleaner_Ccache(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	dir := filepath.Join(home, "ccache")
+	createTestFile(t, dir, "ccache.conf", "max_size = 5G")
+	createTestFile(t, dir, "a/b/1234R", "object")
+	createTestFile(t, dir, "stats", "stats")
+
+	n := &NativeCleaner{}
+	n.SetRunner(&RecordingRunner{Outputs: map[string]string{
+		"ccache --get-config cache_dir": dir + "\n",
+		"ccache -s":                     "  Hits:             800 / 1234 (64.83%)\n",
+	}})
+
+	target, ok := n.ccacheTarget(home)
+	if !ok {
+		t.Fatal("Expected a ccache target")
+	}
+	if !slices.Equal(target.Entries, []string{filepath.Join(dir, "a"), filepath.Join(dir, "stats")}) {
+		t.Errorf("Expected the cache without its config, got %v", target.Entries)
+	}
+	if target.Description != "ccache compiler cache, 64.83% hit rate (2 entries)" {
+		t.Errorf("Unexpected description %q", target.Description)
+	}
+}
+
+func TestNativeCleaner_Scan(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	projects := filepath.Join(home, "Projects")
+	createTestDir(t, projects, "engine", map[string]string{
+		"CMakeLists.txt":                     "project(engine)",
+		"build/CMakeCache.txt":               "CMAKE_BUILD_TYPE:STRING=Debug",
+		"build/CMakeFiles/engine.dir/a.o":    "object",
+		"build/build.ninja":                  "rule cc",
+		"build/.ninja_log":                   "# ninja log v5",
+		"cmake-build-release/CMakeCache.txt": "CMAKE_BUILD_TYPE:STRING=Release",
+		"cmake-build-release/CMakeFiles/x":   "x",
+		// FetchContent builds inside the build directory
+		"build/_deps/fmt-build/CMakeCache.txt":    "cache",
+		"build/_deps/fmt-build/CMakeFiles/x":      "x",
+		"build/_deps/fmt-subbuild/CMakeCache.txt": "cache",
+		"build/_deps/fmt-subbuild/CMakeFiles/x":   "x",
+	})
+	// An in-source build, and the logs of a removed build
+	createTestDir(t, projects, "legacy", map[string]string{
+		"CMakeLists.txt":  "project(legacy)",
+		"CMakeCache.txt":  "cache",
+		"CMakeFiles/x":    "x",
+		"old/.ninja_log":  "# ninja log v5",
+		"old/.ninja_deps": "deps",
+		"old/notes.txt":   "kept",
+	})
+	createTestFile(t, home, ".conan2/p/zlib1a2b/p/lib/libz.a", "archive")
+	createTestFile(t, home, "vcpkg/.vcpkg-root", "")
+	createTestFile(t, home, "vcpkg/buildtrees/zlib/src/zlib.c", "source")
+	createTestFile(t, home, "vcpkg/downloads/zlib-1.3.tar.gz", "tarball")
+	createTestFile(t, home, "vcpkg/installed/arm64-osx/lib/libz.a", "installed")
+
+	s, _ := scanner.NewScannerWithDirs([]string{projects})
+	n := &NativeCleaner{scanner: s, runner: &RecordingRunner{}}
+	cfg := config.NewDefaultConfig()
+	cfg.Home = home
+	cfg.CleanLevel = config.Standard
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	targets, err := n.Scan(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Scan() returned error: %v", err)
+	}
+
+	expected := map[string]string{
+		filepath.Join(projects, "engine", "build"):               "cmake_build",
+		filepath.Join(projects, "engine", "cmake-build-release"): "cmake_build",
+		filepath.Join(projects, "legacy", "old"):                 "ninja_log",
+		filepath.Join(home, ".conan2", "p"):                      "conan_packages",
+		filepath.Join(home, "vcpkg", "buildtrees"):               "vcpkg_buildtrees",
+		filepath.Join(home, "vcpkg", "downloads"):                "vcpkg_downloads",
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %d targets, got %d: %+v", len(expected), len(targets), targets)
+	}
+	for _, target := range targets {
+		if expected[target.Path] != target.Category {
+			t.Errorf("Unexpected target %s (%s)", target.Path, target.Category)
+		}
+		if target.Category == "ninja_log" && len(target.Entries) != 2 {
+			t.Errorf("Expected the Ninja log and deps only, got %v", target.Entries)
+		}
+	}
+}
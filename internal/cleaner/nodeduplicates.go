@@ -0,0 +1,257 @@
+package cleaner
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+// NodePackageCopies is one npm package release (same name and version)
+// installed more than once across node_modules folders
+type NodePackageCopies struct {
+	Name    string
+	Version string
+	Size    int64    // Size of one copy, without its own node_modules
+	Paths   []string // Package folders
+}
+
+// Total returns the size of all copies
+func (c NodePackageCopies) Total() int64 {
+	return int64(len(c.Paths)) * c.Size
+}
+
+// Savings returns the space a single shared copy would save: all copies but
+// one
+func (c NodePackageCopies) Savings() int64 {
+	return int64(len(c.Paths)-1) * c.Size
+}
+
+// NodeWorkspaceCandidate is a folder holding several projects that install
+// the same packages, which a workspace at that folder would install once
+type NodeWorkspaceCandidate struct {
+	Dir      string
+	Projects int   // Projects with packages also installed by another one
+	Savings  int64 // Space saved by installing those packages once
+}
+
+// nodePackage is a package folder found inside a node_modules tree
+type nodePackage struct {
+	name    string
+	version string
+	size    int64
+}
+
+// FindNodeModulesDirs returns the node_modules folders in the search
+// directories. Folders nested inside another node_modules are not returned,
+// their packages are read with the outer one.
+func FindNodeModulesDirs(ctx context.Context, cfg *config.Config) ([]string, error) {
+	s, err := scanner.NewScanner()
+	if err != nil {
+		return nil, err
+	}
+	configureScanner(s, cfg)
+
+	dirs := []string{}
+	for result := range s.FindByPattern(ctx, "node_modules") {
+		if result.Err == nil {
+			dirs = append(dirs, result.Path)
+		}
+	}
+	return dirs, nil
+}
+
+// NodeDuplicates returns the package releases installed more than once in
+// the given node_modules folders, whether in different projects or nested
+// in the same one, largest total size first. Packages are identified by the
+// name and version of their package.json.
+func NodeDuplicates(nodeModulesDirs []string) []NodePackageCopies {
+	byRelease := make(map[string]*NodePackageCopies)
+
+	for _, dir := range nodeModulesDirs {
+		packages := nodeModulesPackages(dir)
+
+		paths := make([]string, 0, len(packages))
+		for path := range packages {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			pkg := packages[path]
+			if pkg.name == "" || pkg.version == "" {
+				continue
+			}
+
+			key := pkg.name + "@" + pkg.version
+			copies, ok := byRelease[key]
+			if !ok {
+				copies = &NodePackageCopies{Name: pkg.name, Version: pkg.version, Size: pkg.size}
+				byRelease[key] = copies
+			}
+			copies.Paths = append(copies.Paths, path)
+		}
+	}
+
+	duplicates := []NodePackageCopies{}
+	for _, copies := range byRelease {
+		if len(copies.Paths) > 1 {
+			duplicates = append(duplicates, *copies)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Total() != duplicates[j].Total() {
+			return duplicates[i].Total() > duplicates[j].Total()
+		}
+		return duplicates[i].Name < duplicates[j].Name
+	})
+
+	return duplicates
+}
+
+// NodeWorkspaceCandidates returns the folders directly holding two or more
+// projects that install the same package releases, largest savings first
+func NodeWorkspaceCandidates(duplicates []NodePackageCopies) []NodeWorkspaceCandidate {
+	savings := make(map[string]int64)
+	projects := make(map[string]map[string]bool)
+
+	for _, copies := range duplicates {
+		byParent := make(map[string]map[string]bool)
+		for _, path := range copies.Paths {
+			project := nodeProjectDir(path)
+			parent := filepath.Dir(project)
+			if byParent[parent] == nil {
+				byParent[parent] = make(map[string]bool)
+			}
+			byParent[parent][project] = true
+		}
+
+		for parent, parentProjects := range byParent {
+			if len(parentProjects) < 2 {
+				continue
+			}
+			savings[parent] += int64(len(parentProjects)-1) * copies.Size
+			if projects[parent] == nil {
+				projects[parent] = make(map[string]bool)
+			}
+			for project := range parentProjects {
+				projects[parent][project] = true
+			}
+		}
+	}
+
+	candidates := []NodeWorkspaceCandidate{}
+	for dir, saved := range savings {
+		candidates = append(candidates, NodeWorkspaceCandidate{Dir: dir, Projects: len(projects[dir]), Savings: saved})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Savings != candidates[j].Savings {
+			return candidates[i].Savings > candidates[j].Savings
+		}
+		return candidates[i].Dir < candidates[j].Dir
+	})
+
+	return candidates
+}
+
+// nodeModulesPackages reads every package of a node_modules tree, nested
+// ones included, keyed by package folder. Each file counts towards the
+// package that owns it, so a package's size excludes its own node_modules.
+// pnpm's .pnpm store is skipped: its packages are hard links to the global
+// store and the top-level entries pointing to them are symlinks.
+func nodeModulesPackages(root string) map[string]*nodePackage {
+	packages := make(map[string]*nodePackage)
+
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == ".pnpm" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		dir, ok := nodePackageDir(path)
+		if !ok {
+			return nil
+		}
+		pkg, ok := packages[dir]
+		if !ok {
+			pkg = &nodePackage{}
+			packages[dir] = pkg
+		}
+
+		if info, err := d.Info(); err == nil {
+			pkg.size += info.Size()
+		}
+		if path == filepath.Join(dir, "package.json") {
+			pkg.name, pkg.version = readPackageRelease(path)
+		}
+		return nil
+	})
+
+	return packages
+}
+
+// nodePackageDir returns the package folder owning a file of a node_modules
+// tree: the folder right below the last node_modules of its path, or two
+// below for scoped packages (@scope/name). Dot entries such as .bin or
+// .package-lock.json belong to no package.
+func nodePackageDir(path string) (string, bool) {
+	marker := string(filepath.Separator) + "node_modules" + string(filepath.Separator)
+	i := strings.LastIndex(path, marker)
+	if i < 0 {
+		return "", false
+	}
+
+	parts := strings.Split(path[i+len(marker):], string(filepath.Separator))
+	levels := 1
+	if strings.HasPrefix(parts[0], "@") {
+		levels = 2
+	}
+	// The last part is the file itself
+	if len(parts) <= levels || strings.HasPrefix(parts[0], ".") {
+		return "", false
+	}
+
+	return filepath.Join(path[:i+len(marker)], filepath.Join(parts[:levels]...)), true
+}
+
+// nodeProjectDir returns the project a package folder is installed in: the
+// folder holding its outermost node_modules
+func nodeProjectDir(packageDir string) string {
+	marker := string(filepath.Separator) + "node_modules" + string(filepath.Separator)
+	if i := strings.Index(packageDir, marker); i >= 0 {
+		return packageDir[:i]
+	}
+	return filepath.Dir(packageDir)
+}
+
+// readPackageRelease returns the name and version of a package.json, or
+// empty strings if it cannot be read
+func readPackageRelease(path string) (string, string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", ""
+	}
+
+	var manifest struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", ""
+	}
+	return manifest.Name, manifest.Version
+}
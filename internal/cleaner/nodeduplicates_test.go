@@ -0,0 +1,95 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// createNodePackage creates a package folder with a package.json and a file
+// of the given size
+func createNodePackage(t *testing.T, dir, rel, name, version string, size int) {
+	t.Helper()
+	createTestFile(t, dir, rel+"/package.json", fmt.Sprintf(`{"name": %q, "version": %q}`, name, version))
+	createTestFile(t, dir, rel+"/index.js", strings.Repeat("x", size))
+}
+
+func TestNodeDuplicates(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createNodePackage(t, tmpDir, "acme/web/node_modules/lodash", "lodash", "4.17.21", 1000)
+	createNodePackage(t, tmpDir, "acme/api/node_modules/lodash", "lodash", "4.17.21", 1000)
+	// Nested copy in the same project, counted without the outer package
+	createNodePackage(t, tmpDir, "acme/api/node_modules/@babel/core", "@babel/core", "7.24.0", 500)
+	createNodePackage(t, tmpDir, "acme/api/node_modules/@babel/core/node_modules/lodash", "lodash", "4.17.21", 1000)
+	createNodePackage(t, tmpDir, "blog/node_modules/@babel/core", "@babel/core", "7.24.0", 500)
+	// Another version is not a duplicate
+	createNodePackage(t, tmpDir, "blog/node_modules/lodash", "lodash", "3.10.1", 800)
+	// pnpm links packages from its store
+	createNodePackage(t, tmpDir, "blog/node_modules/.pnpm/react@18.2.0/node_modules/react", "react", "18.2.0", 300)
+	createNodePackage(t, tmpDir, "acme/web/node_modules/.pnpm/react@18.2.0/node_modules/react", "react", "18.2.0", 300)
+	createTestFile(t, tmpDir, "acme/web/node_modules/.package-lock.json", "{}")
+
+	dirs := []string{
+		filepath.Join(tmpDir, "acme", "web", "node_modules"),
+		filepath.Join(tmpDir, "acme", "api", "node_modules"),
+		filepath.Join(tmpDir, "blog", "node_modules"),
+	}
+	duplicates := NodeDuplicates(dirs)
+
+	if len(duplicates) != 2 {
+		t.Fatalf("Expected lodash and @babel/core, got %+v", duplicates)
+	}
+
+	lodash := duplicates[0]
+	if lodash.Name != "lodash" || lodash.Version != "4.17.21" || len(lodash.Paths) != 3 {
+		t.Errorf("Unexpected duplicate %+v", lodash)
+	}
+	size := int64(1000 + len(`{"name": "lodash", "version": "4.17.21"}`))
+	if lodash.Size != size || lodash.Savings() != 2*size || lodash.Total() != 3*size {
+		t.Errorf("Size = %d, Savings() = %d, want %d and %d", lodash.Size, lodash.Savings(), size, 2*size)
+	}
+
+	babel := duplicates[1]
+	if babel.Name != "@babel/core" || len(babel.Paths) != 2 {
+		t.Errorf("Unexpected duplicate %+v", babel)
+	}
+	if babel.Size != int64(500+len(`{"name": "@babel/core", "version": "7.24.0"}`)) {
+		t.Errorf("Nested node_modules should not count towards @babel/core, got %d", babel.Size)
+	}
+
+	candidates := NodeWorkspaceCandidates(duplicates)
+	if len(candidates) != 1 {
+		t.Fatalf("Expected one workspace candidate, got %+v", candidates)
+	}
+	if candidates[0].Dir != filepath.Join(tmpDir, "acme") || candidates[0].Projects != 2 || candidates[0].Savings != size {
+		t.Errorf("Unexpected workspace candidate %+v", candidates[0])
+	}
+}
+
+func TestNodePackageDir(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"/p/node_modules/react/index.js", "/p/node_modules/react", true},
+		{"/p/node_modules/react/lib/a.js", "/p/node_modules/react", true},
+		{"/p/node_modules/@types/node/index.d.ts", "/p/node_modules/@types/node", true},
+		{"/p/node_modules/a/node_modules/b/x.js", "/p/node_modules/a/node_modules/b", true},
+		{"/p/node_modules/.bin/tsc", "", false},
+		{"/p/node_modules/.package-lock.json", "", false},
+		{"/p/node_modules/@types/README", "", false},
+		{"/p/src/index.js", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := nodePackageDir(filepath.FromSlash(tt.path))
+		if ok != tt.ok || got != filepath.FromSlash(tt.want) {
+			t.Errorf("nodePackageDir(%q) = %q, %v, want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
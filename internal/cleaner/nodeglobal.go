@@ -0,0 +1,175 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// nodeGlobalRoot is a node_modules folder of global installs, with the
+// folders holding the links to their commands
+type nodeGlobalRoot struct {
+	manager string // npm, yarn or pnpm
+	path    string
+	bins    []string
+}
+
+// nodeGlobalKeep are the global packages that come with Node.js itself
+var nodeGlobalKeep = map[string]bool{
+	"npm":      true,
+	"corepack": true,
+}
+
+// nodeGlobalRoots returns the folders where npm, yarn and pnpm install
+// global packages in the home directory. System prefixes such as
+// /usr/local or /opt/homebrew are left out: their packages belong to the
+// system or to Homebrew, not to the user.
+func nodeGlobalRoots(home string) []nodeGlobalRoot {
+	npmPrefixes := []string{filepath.Join(home, ".npm-global")}
+	if prefix := os.Getenv("NPM_CONFIG_PREFIX"); prefix != "" && utils.HasPathPrefix(filepath.Clean(prefix), home) {
+		npmPrefixes = append(npmPrefixes, filepath.Clean(prefix))
+	}
+	nvm, _ := filepath.Glob(filepath.Join(home, ".nvm", "versions", "node", "*"))
+	npmPrefixes = append(npmPrefixes, nvm...)
+
+	var roots []nodeGlobalRoot
+	seen := make(map[string]bool)
+	for _, prefix := range npmPrefixes {
+		if !seen[prefix] {
+			seen[prefix] = true
+			roots = append(roots, nodeGlobalRoot{"npm", filepath.Join(prefix, "lib", "node_modules"), []string{filepath.Join(prefix, "bin")}})
+		}
+	}
+
+	yarn := filepath.Join(home, ".config", "yarn", "global", "node_modules")
+	roots = append(roots, nodeGlobalRoot{"yarn", yarn, []string{filepath.Join(home, ".yarn", "bin"), filepath.Join(yarn, ".bin")}})
+
+	pnpmHome := os.Getenv("PNPM_HOME")
+	if pnpmHome == "" || !utils.HasPathPrefix(filepath.Clean(pnpmHome), home) {
+		pnpmHome = filepath.Join(platform.DataDir(home), "pnpm")
+		if platform.IsMacOS() {
+			pnpmHome = filepath.Join(home, "Library", "pnpm")
+		}
+	}
+	pnpm, _ := filepath.Glob(filepath.Join(pnpmHome, "global", "*", "node_modules"))
+	for _, path := range pnpm {
+		roots = append(roots, nodeGlobalRoot{"pnpm", path, []string{pnpmHome, filepath.Join(path, ".bin")}})
+	}
+
+	return roots
+}
+
+// nodeGlobalPackages returns a target for each package installed globally
+// in roots, with the links to its commands, the largest first. They are
+// Moderate: their commands are gone until installed again. The packages
+// that come with Node.js are left alone.
+func nodeGlobalPackages(roots []nodeGlobalRoot, now time.Time) []CleanTarget {
+	targets := []CleanTarget{}
+
+	for _, root := range roots {
+		for _, pkg := range globalPackageDirs(root.path) {
+			name, _ := filepath.Rel(root.path, pkg)
+			name = filepath.ToSlash(name)
+			if nodeGlobalKeep[name] {
+				continue
+			}
+
+			size, _ := utils.GetDirSize(pkg)
+			if size == 0 {
+				continue
+			}
+
+			version := ""
+			if data, err := os.ReadFile(filepath.Join(pkg, "package.json")); err == nil {
+				var manifest struct {
+					Version string `json:"version"`
+				}
+				if json.Unmarshal(data, &manifest) == nil && manifest.Version != "" {
+					version = "@" + manifest.Version
+				}
+			}
+
+			// Package files carry the date of the tarball, the folder the
+			// date of the install; access times would change as it is read
+			description := fmt.Sprintf("%s global package %s%s", root.manager, name, version)
+			if info, err := os.Stat(pkg); err == nil {
+				description += fmt.Sprintf(" (installed %d days ago)", int(now.Sub(info.ModTime()).Hours()/24))
+			}
+
+			targets = append(targets, CleanTarget{
+				Path:        pkg,
+				Category:    "node_global_package",
+				Description: description,
+				SizeBytes:   size,
+				Safety:      config.Moderate,
+				Entries:     append([]string{pkg}, binLinksInto(root.bins, pkg)...),
+			})
+		}
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool {
+		return targets[i].SizeBytes > targets[j].SizeBytes
+	})
+	return targets
+}
+
+// globalPackageDirs returns the package folders of a node_modules folder,
+// looking into @scope folders
+func globalPackageDirs(root string) []string {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if !strings.HasPrefix(name, "@") {
+			dirs = append(dirs, filepath.Join(root, name))
+			continue
+		}
+		scoped, _ := os.ReadDir(filepath.Join(root, name))
+		for _, pkg := range scoped {
+			if pkg.IsDir() {
+				dirs = append(dirs, filepath.Join(root, name, pkg.Name()))
+			}
+		}
+	}
+	return dirs
+}
+
+// binLinksInto returns the links of bin folders that point inside pkg
+func binLinksInto(bins []string, pkg string) []string {
+	var links []string
+	for _, bin := range bins {
+		entries, _ := os.ReadDir(bin)
+		for _, entry := range entries {
+			if entry.Type()&os.ModeSymlink == 0 {
+				continue
+			}
+			link := filepath.Join(bin, entry.Name())
+			target, err := os.Readlink(link)
+			if err != nil {
+				continue
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(bin, target)
+			}
+			if utils.HasPathPrefix(filepath.Clean(target), pkg) {
+				links = append(links, link)
+			}
+		}
+	}
+	return links
+}
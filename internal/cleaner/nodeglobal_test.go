@@ -0,0 +1,133 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestNodeGlobalPackages(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	root := filepath.Join(tmpDir, "lib", "node_modules")
+	bin := filepath.Join(tmpDir, "bin")
+	createTestFile(t, root, "typescript/package.json", `{"version": "5.3.3"}`)
+	createTestFile(t, root, "typescript/lib/tsc.js", "a large compiler")
+	createTestFile(t, root, "@vue/cli/package.json", `{"version": "5.0.8"}`)
+	createTestFile(t, root, "npm/package.json", `{"version": "10.2.4"}`)
+	createTestFile(t, root, "corepack/package.json", `{"version": "0.23.0"}`)
+	os.MkdirAll(bin, 0755)
+	os.Symlink("../lib/node_modules/typescript/lib/tsc.js", filepath.Join(bin, "tsc"))
+	os.Symlink("../lib/node_modules/npm/bin/npm-cli.js", filepath.Join(bin, "npm"))
+
+	old := time.Now().AddDate(0, 0, -40)
+	setOldTimes(t, filepath.Join(root, "typescript"), old)
+
+	targets := nodeGlobalPackages([]nodeGlobalRoot{{"npm", root, []string{bin}}}, time.Now())
+	if len(targets) != 2 {
+		t.Fatalf("Expected typescript and @vue/cli, got %+v", targets)
+	}
+
+	// The largest first, with the link to its command
+	ts := targets[0]
+	if ts.Path != filepath.Join(root, "typescript") || ts.Category != "node_global_package" || ts.Safety != config.Moderate {
+		t.Errorf("Unexpected target %+v", ts)
+	}
+	if ts.Description != "npm global package typescript@5.3.3 (installed 40 days ago)" {
+		t.Errorf("Unexpected description %q", ts.Description)
+	}
+	if !slices.Equal(ts.Entries, []string{ts.Path, filepath.Join(bin, "tsc")}) {
+		t.Errorf("Expected the package and its command link, got %v", ts.Entries)
+	}
+	if targets[1].Path != filepath.Join(root, "@vue", "cli") {
+		t.Errorf("Expected the scoped package, got %+v", targets[1])
+	}
+}
+
+func TestFrontendCleaner_NpxCache(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	createTestFile(t, home, ".npm/_cacache/index-v5/00/entry", "npm")
+	createTestFile(t, home, ".npm/_npx/0a1b2c/node_modules/create-vite/index.js", "create-vite")
+
+	cfg := config.NewDefaultConfig()
+	cfg.Home = home
+	c, _ := NewFrontendCleaner()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	targets, err := c.Scan(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Scan() returned error: %v", err)
+	}
+
+	found := make(map[string]CleanTarget)
+	for _, target := range targets {
+		found[target.Category] = target
+	}
+
+	npx, ok := found["npx_cache"]
+	if !ok || npx.Path != filepath.Join(home, ".npm", "_npx") || npx.Safety != config.Safe {
+		t.Errorf("Expected the npx cache as a Safe target, got %+v", targets)
+	}
+	// The npm cache leaves the npx cache out
+	npm := found["npm_cache"]
+	if !slices.Equal(npm.Entries, []string{filepath.Join(home, ".npm", "_cacache")}) || npm.SizeBytes != int64(len("npm")) {
+		t.Errorf("Expected the npm cache without _npx, got %+v", npm)
+	}
+}
+
+func TestNodeGlobalRoots_UserOwned(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	t.Setenv("NPM_CONFIG_PREFIX", "/usr/local")
+	t.Setenv("PNPM_HOME", "/opt/pnpm")
+	for _, root := range nodeGlobalRoots(home) {
+		if !strings.HasPrefix(root.path, home) {
+			t.Errorf("Expected roots below home only, got %s", root.path)
+		}
+	}
+}
+
+func TestFrontendCleaner_GlobalPackagesLevel(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	createTestFile(t, home, ".npm-global/lib/node_modules/typescript/package.json", `{"version": "5.3.3"}`)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Home = home
+	cfg.CleanLevel = config.Conservative
+
+	f, _ := NewFrontendCleaner()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	targets, err := f.Scan(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	for _, target := range targets {
+		if target.Category == "node_global_package" {
+			t.Errorf("Expected no global packages at the conservative level, got %+v", target)
+		}
+	}
+
+	cfg.CleanLevel = config.Standard
+	targets, _ = f.Scan(ctx, cfg)
+	found := false
+	for _, target := range targets {
+		found = found || target.Category == "node_global_package"
+	}
+	if !found {
+		t.Error("Expected the global package at the standard level")
+	}
+}
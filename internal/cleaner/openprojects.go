@@ -0,0 +1,226 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// artifactDirs are the folders whose open files show that a project is
+// running: a dev server reading node_modules, a build writing to target
+var artifactDirs = []string{"node_modules", "target"}
+
+// FindOpenProjects returns the projects in use, by root directory, with
+// what uses them: those open in VS Code or a JetBrains IDE, and those a
+// process runs in or reads the node_modules or target folder of, as listed
+// by lsof run with runner. Store them in cfg.OpenProjects so their targets
+// are escalated to Dangerous.
+func FindOpenProjects(runner CommandRunner, home string) map[string]string {
+	open := make(map[string]string)
+	add := func(root, reason string) {
+		if _, ok := open[root]; root != "" && !ok {
+			open[root] = reason
+		}
+	}
+
+	for _, dir := range vscodeOpenFolders(home) {
+		add(utils.ProjectRootOf(dir), "open in VS Code")
+	}
+	for dir, ide := range jetbrainsOpenProjects(home) {
+		add(utils.ProjectRootOf(dir), "open in "+ide)
+	}
+
+	output, err := runner.Output("lsof", "-w", "-n", "-P", "-u", strconv.Itoa(os.Getuid()), "-Fcfn")
+	if err != nil && len(output) == 0 {
+		return open
+	}
+	for _, file := range parseLsof(string(output)) {
+		if file.fd == "cwd" {
+			add(utils.ProjectRootOf(file.path), "in use by "+file.command)
+			continue
+		}
+		if dir := artifactParent(file.path); dir != "" {
+			add(utils.ProjectRootOf(dir), "in use by "+file.command)
+		}
+	}
+	return open
+}
+
+// openFile is a file a process has open, from lsof -F output
+type openFile struct {
+	command string
+	fd      string // File descriptor, or "cwd" for the working directory
+	path    string
+}
+
+// parseLsof reads the output of lsof -Fcfn: one field per line, the command
+// (c) once per process, then the descriptor (f) and name (n) of each file
+func parseLsof(output string) []openFile {
+	files := []openFile{}
+	command, fd := "", ""
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		value := line[1:]
+		switch line[0] {
+		case 'p':
+			command, fd = "", ""
+		case 'c':
+			command = value
+		case 'f':
+			fd = value
+		case 'n':
+			if filepath.IsAbs(value) {
+				files = append(files, openFile{command: command, fd: fd, path: value})
+			}
+		}
+	}
+	return files
+}
+
+// artifactParent returns the folder holding the first node_modules or
+// target folder of path, or "" if path is in none
+func artifactParent(path string) string {
+	parts := strings.Split(path, string(filepath.Separator))
+	for i, part := range parts {
+		for _, dir := range artifactDirs {
+			if part == dir && i > 1 {
+				return strings.Join(parts[:i], string(filepath.Separator))
+			}
+		}
+	}
+	return ""
+}
+
+// vscodeOpenFolders returns the folders of the VS Code windows open, or to
+// be restored on the next launch, from its storage.json
+func vscodeOpenFolders(home string) []string {
+	type window struct {
+		Folder string `json:"folder"`
+	}
+	var storage struct {
+		WindowsState struct {
+			LastActiveWindow window   `json:"lastActiveWindow"`
+			OpenedWindows    []window `json:"openedWindows"`
+		} `json:"windowsState"`
+	}
+
+	folders := []string{}
+	// ~/Library/Application Support on macOS, ~/.config on Linux
+	data, err := os.ReadFile(filepath.Join(platform.ConfigDir(home), "Code", "User", "globalStorage", "storage.json"))
+	if err != nil || json.Unmarshal(data, &storage) != nil {
+		return folders
+	}
+
+	windows := append([]window{storage.WindowsState.LastActiveWindow}, storage.WindowsState.OpenedWindows...)
+	for _, w := range windows {
+		if u, err := url.Parse(w.Folder); err == nil && u.Scheme == "file" {
+			folders = append(folders, u.Path)
+		}
+	}
+	return folders
+}
+
+// jetbrainsOpenProjects returns the projects JetBrains IDEs have open, with
+// the IDE, from the recentProjects.xml of each installed version
+func jetbrainsOpenProjects(home string) map[string]string {
+	projects := make(map[string]string)
+
+	base := filepath.Join(platform.ConfigDir(home), "JetBrains")
+	for _, product := range subdirNames(base) {
+		file, err := os.Open(filepath.Join(base, product, "options", "recentProjects.xml"))
+		if err != nil {
+			continue
+		}
+		// "IntelliJIdea2024.1" -> "IntelliJIdea"
+		ide := strings.TrimRight(product, "0123456789.")
+		for _, dir := range parseRecentProjects(xml.NewDecoder(file), home) {
+			projects[dir] = ide
+		}
+		file.Close()
+	}
+	return projects
+}
+
+// parseRecentProjects returns the projects marked opened in a JetBrains
+// recentProjects.xml, whose entries look like
+//
+//	<entry key="$USER_HOME$/Projects/api">
+//	  <value><RecentProjectMetaInfo opened="true" .../></value>
+//	</entry>
+func parseRecentProjects(decoder *xml.Decoder, home string) []string {
+	projects := []string{}
+	key := ""
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return projects
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "entry":
+			key = ""
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "key" {
+					key = strings.ReplaceAll(attr.Value, "$USER_HOME$", home)
+				}
+			}
+		case "RecentProjectMetaInfo":
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "opened" && attr.Value == "true" && filepath.IsAbs(key) {
+					projects = append(projects, key)
+				}
+			}
+		}
+	}
+}
+
+// guardOpenProjects escalates the targets inside a project in use to
+// Dangerous, noting what uses it, and drops them if the clean level does
+// not allow Dangerous targets: deleting node_modules under a running dev
+// server breaks it
+func guardOpenProjects(cfg *config.Config, targets []CleanTarget) []CleanTarget {
+	if len(cfg.OpenProjects) == 0 {
+		return targets
+	}
+
+	kept := make([]CleanTarget, 0, len(targets))
+	for _, target := range targets {
+		if reason, ok := openProjectOf(cfg.OpenProjects, target.Path); ok {
+			if !cfg.CleanLevel.AllowsSafety(config.Dangerous) {
+				continue
+			}
+			target.Safety = config.Dangerous
+			target.Description += " (project " + reason + ")"
+		}
+		kept = append(kept, target)
+	}
+	return kept
+}
+
+// openProjectOf returns what uses the open project path is inside, if any.
+// Editors may not write the path the way the scan found it, so case and
+// Unicode normalization are ignored where the volume ignores them.
+func openProjectOf(open map[string]string, path string) (string, bool) {
+	for root, reason := range open {
+		if path != root && utils.HasPathPrefix(path, root) {
+			return reason, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,114 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+)
+
+func TestParseLsof(t *testing.T) {
+	output := "p101\ncnode\nfcwd\nn/Users/me/Projects/web\nf12\nn/Users/me/Projects/web/node_modules/vite/dist/index.js\nf13\nnlocalhost:5173\np202\nczsh\nfcwd\nn/Users/me\n"
+
+	files := parseLsof(output)
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 open files with a path, got %+v", files)
+	}
+	if files[0] != (openFile{command: "node", fd: "cwd", path: "/Users/me/Projects/web"}) {
+		t.Errorf("Unexpected first file %+v", files[0])
+	}
+	if files[2].command != "zsh" || files[2].fd != "cwd" {
+		t.Errorf("Expected the fields of the second process, got %+v", files[2])
+	}
+}
+
+func TestArtifactParent(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/p/web/node_modules/vite/node_modules/esbuild/bin", "/p/web"},
+		{"/p/api/target/debug/api", "/p/api"},
+		{"/p/web/src/index.ts", ""},
+	}
+
+	for _, tt := range tests {
+		if got := artifactParent(tt.path); got != tt.expected {
+			t.Errorf("artifactParent(%s) = %q, want %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestFindOpenProjects(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	projects := filepath.Join(home, "Projects")
+	web := filepath.Dir(createTestFile(t, projects, "web/package.json", "{}"))
+	api := filepath.Dir(createTestFile(t, projects, "api/Cargo.toml", ""))
+	app := filepath.Dir(createTestFile(t, projects, "app/package.json", "{}"))
+	backend := filepath.Dir(createTestFile(t, projects, "backend/pom.xml", ""))
+	createTestFile(t, projects, "closed/package.json", "{}")
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	settings := platform.ConfigDir(home)
+	createTestFile(t, settings, "Code/User/globalStorage/storage.json",
+		`{"windowsState": {"lastActiveWindow": {"folder": "file://`+app+`/src"}, "openedWindows": []}}`)
+	createTestFile(t, settings, "JetBrains/IntelliJIdea2024.1/options/recentProjects.xml",
+		`<application><component name="RecentProjectsManager"><option name="additionalInfo"><map>
+<entry key="$USER_HOME$/Projects/backend"><value><RecentProjectMetaInfo opened="true"/></value></entry>
+<entry key="$USER_HOME$/Projects/closed"><value><RecentProjectMetaInfo opened="false"/></value></entry>
+</map></option></component></application>`)
+
+	runner := &RecordingRunner{Outputs: map[string]string{
+		"lsof -w -n -P -u " + strconv.Itoa(os.Getuid()) + " -Fcfn": "p1\nczsh\nfcwd\nn" + web + "/src\n" +
+			"p2\ncrust-analyzer\nf3\nn" + api + "/target/debug/build.log\n" +
+			"p3\nczsh\nfcwd\nn" + projects + "\n",
+	}}
+
+	open := FindOpenProjects(runner, home)
+	expected := map[string]string{
+		web:     "in use by zsh",
+		api:     "in use by rust-analyzer",
+		app:     "open in VS Code",
+		backend: "open in IntelliJIdea",
+	}
+	if len(open) != len(expected) {
+		t.Errorf("Expected %d open projects, got %v", len(expected), open)
+	}
+	for root, reason := range expected {
+		if open[root] != reason {
+			t.Errorf("Expected %s to be %q, got %q", root, reason, open[root])
+		}
+	}
+}
+
+func TestGuardOpenProjects(t *testing.T) {
+	targets := []CleanTarget{
+		{Path: "/p/web/node_modules", Description: "node_modules dependencies", Safety: config.Moderate},
+		{Path: "/p/website/dist", Description: "Build output (dist)", Safety: config.Safe},
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.OpenProjects = map[string]string{"/p/web": "in use by node"}
+
+	kept := guardOpenProjects(cfg, targets)
+	if len(kept) != 1 || kept[0].Path != "/p/website/dist" {
+		t.Errorf("Expected the open project's target to be left out below aggressive, got %+v", kept)
+	}
+
+	cfg.CleanLevel = config.Aggressive
+	kept = guardOpenProjects(cfg, targets)
+	if len(kept) != 2 || kept[0].Safety != config.Dangerous {
+		t.Fatalf("Expected the open project's target to be Dangerous, got %+v", kept)
+	}
+	if kept[0].Description != "node_modules dependencies (project in use by node)" {
+		t.Errorf("Unexpected description %q", kept[0].Description)
+	}
+	if kept[1].Safety != config.Safe {
+		t.Error("Expected other targets to keep their safety")
+	}
+}
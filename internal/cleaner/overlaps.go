@@ -0,0 +1,115 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// SubtractOverlaps takes the targets of each cleaner out of the targets of
+// other cleaners that contain them, such as the pip cache found by Backend
+// inside the user caches found by System. The outer target keeps the rest
+// of its folder, listed as Entries and measured again, and is dropped if
+// nothing is left. Otherwise the same files would be counted twice and
+// deleted by two cleaners at once. Targets found by one cleaner are left
+// as they are: a cleaner deletes its own targets in order.
+//
+// Two targets of the same path keep the one of the cleaner whose name sorts
+// first. Targets that run commands are not files and are left alone.
+func SubtractOverlaps(targetsByCleaner map[string][]CleanTarget) {
+	names := make([]string, 0, len(targetsByCleaner))
+	for name := range targetsByCleaner {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, outer := range names {
+		kept := make([]CleanTarget, 0, len(targetsByCleaner[outer]))
+		for _, target := range targetsByCleaner[outer] {
+			var inner []string
+			for _, other := range names {
+				if other == outer {
+					continue
+				}
+				for _, candidate := range targetsByCleaner[other] {
+					if candidate.Action == ActionRun || len(candidate.Path) < len(target.Path) || !utils.HasPathPrefix(candidate.Path, target.Path) {
+						continue
+					}
+					// The same path stays with the first cleaner only
+					if utils.SamePath(candidate.Path, target.Path) && other > outer {
+						continue
+					}
+					inner = append(inner, candidate.Path)
+				}
+			}
+			if target.Action == ActionRun || len(inner) == 0 {
+				kept = append(kept, target)
+				continue
+			}
+
+			covered := target.Entries
+			if len(covered) == 0 {
+				covered = []string{target.Path}
+			}
+			entries := subtractPaths(covered, inner)
+			if slices.Equal(entries, covered) {
+				kept = append(kept, target)
+				continue
+			}
+
+			var size int64
+			for _, entry := range entries {
+				entrySize, _ := utils.GetDirSize(entry)
+				size += entrySize
+			}
+			if size == 0 {
+				continue
+			}
+			target.Entries = entries
+			target.SizeBytes = size
+			kept = append(kept, target)
+		}
+
+		if len(kept) > 0 {
+			targetsByCleaner[outer] = kept
+		} else {
+			delete(targetsByCleaner, outer)
+		}
+	}
+}
+
+// subtractPaths returns paths without the inner paths: paths inside one of
+// them are left out, and paths containing one are replaced by their
+// children, down to the folders holding the inner paths
+func subtractPaths(paths, inner []string) []string {
+	result := []string{}
+	for _, path := range paths {
+		contains := false
+		removed := false
+		for _, in := range inner {
+			if utils.HasPathPrefix(path, in) {
+				removed = true
+				break
+			}
+			if utils.HasPathPrefix(in, path) {
+				contains = true
+			}
+		}
+		switch {
+		case removed:
+		case contains:
+			children, _ := os.ReadDir(path)
+			childPaths := make([]string, len(children))
+			for i, child := range children {
+				childPaths[i] = filepath.Join(path, child.Name())
+			}
+			result = append(result, subtractPaths(childPaths, inner)...)
+		default:
+			result = append(result, path)
+		}
+	}
+	return result
+}
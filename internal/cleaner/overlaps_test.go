@@ -0,0 +1,68 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestSubtractOverlaps(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	caches := filepath.Join(home, ".cache")
+	createTestFile(t, caches, "pip/http/entry", "pip")
+	createTestFile(t, caches, "fontconfig/cache", "fonts")
+	createTestFile(t, caches, "go-build/00/entry", "build")
+	createTestFile(t, caches, "go-build/README", "readme")
+
+	targetsByCleaner := map[string][]CleanTarget{
+		"Cache": {
+			{Path: caches, Category: "user_caches", SizeBytes: 17, Safety: config.Safe},
+			actionTarget("dns_cache", "system:dns_cache", 0),
+		},
+		"Backend": {
+			{Path: filepath.Join(caches, "pip"), Category: "pip_cache", SizeBytes: 3, Safety: config.Safe},
+			{Path: filepath.Join(caches, "go-build", "00"), Category: "go_build_cache", SizeBytes: 5, Safety: config.Safe},
+		},
+	}
+	SubtractOverlaps(targetsByCleaner)
+
+	if len(targetsByCleaner["Backend"]) != 2 {
+		t.Errorf("Expected the inner targets to be kept, got %+v", targetsByCleaner["Backend"])
+	}
+	user := targetsByCleaner["Cache"][0]
+	expected := []string{filepath.Join(caches, "fontconfig"), filepath.Join(caches, "go-build", "README")}
+	if !slices.Equal(user.Entries, expected) {
+		t.Errorf("Expected the rest of the user caches, got %v", user.Entries)
+	}
+	if user.SizeBytes != int64(len("fonts")+len("readme")) {
+		t.Errorf("Expected the user caches to be measured again, got %d", user.SizeBytes)
+	}
+	if len(targetsByCleaner["Cache"]) != 2 {
+		t.Error("Expected the action target to be left alone")
+	}
+}
+
+func TestSubtractOverlaps_SamePath(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	createTestFile(t, home, "models/model.gguf", "weights")
+	path := filepath.Join(home, "models")
+	targetsByCleaner := map[string][]CleanTarget{
+		"Local LLMs": {{Path: path, SizeBytes: 7}},
+		"Data/ML":    {{Path: path, SizeBytes: 7}},
+	}
+	SubtractOverlaps(targetsByCleaner)
+
+	if len(targetsByCleaner["Data/ML"]) != 1 || len(targetsByCleaner["Local LLMs"]) != 0 {
+		t.Errorf("Expected the path to stay with one cleaner, got %+v", targetsByCleaner)
+	}
+	if _, ok := targetsByCleaner["Local LLMs"]; ok {
+		t.Error("Expected a cleaner left without targets to be removed")
+	}
+}
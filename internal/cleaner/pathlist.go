@@ -0,0 +1,113 @@
+package cleaner
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// RemovedPaths returns the absolute paths cleaning target removes: its
+// entries, or its path. Targets that aren't paths (e.g. "docker:images")
+// remove none, nor do the ones evicted, archived or reset by a maintenance
+// action that doesn't delete its path: their files stay on disk.
+func (t CleanTarget) RemovedPaths() []string {
+	switch t.Action {
+	case ActionEvict, ActionArchive:
+		return []string{}
+	case ActionRun:
+		if !maintenanceActions[t.Category].remove {
+			return []string{}
+		}
+	}
+
+	paths := t.Entries
+	if len(paths) == 0 {
+		paths = []string{t.Path}
+	}
+
+	absolute := []string{}
+	for _, path := range paths {
+		if filepath.IsAbs(path) {
+			absolute = append(absolute, path)
+		}
+	}
+	return absolute
+}
+
+// ParsePathList reads a list of paths separated by NUL characters, as
+// clean --print0 writes them, or else by lines. Empty entries are skipped.
+func ParsePathList(data []byte) []string {
+	separator := []byte("\n")
+	if bytes.IndexByte(data, 0) >= 0 {
+		separator = []byte{0}
+	}
+
+	paths := []string{}
+	for _, entry := range bytes.Split(data, separator) {
+		path := strings.TrimSuffix(string(entry), "\r")
+		if strings.TrimSpace(path) != "" {
+			paths = append(paths, filepath.Clean(path))
+		}
+	}
+	return paths
+}
+
+// SelectPaths keeps the targets, by cleaner, that remove one of paths, and
+// returns the paths none of them removes. A target listed by its path is
+// kept whole; one whose entries are listed is narrowed to them. Paths inside
+// a target aren't targets themselves and aren't selected, nor are targets
+// that remove no path.
+func SelectPaths(targetsByCleaner map[string][]CleanTarget, paths []string) (map[string][]CleanTarget, []string) {
+	selected := make(map[string][]CleanTarget)
+	matched := make([]bool, len(paths))
+
+	for name, targets := range targetsByCleaner {
+		for _, target := range targets {
+			if len(target.RemovedPaths()) == 0 {
+				continue
+			}
+
+			whole := false
+			for i, path := range paths {
+				if utils.SamePath(path, target.Path) {
+					whole, matched[i] = true, true
+				}
+			}
+			if whole {
+				selected[name] = append(selected[name], target)
+				continue
+			}
+
+			entries := []string{}
+			var size int64
+			for _, entry := range target.Entries {
+				for i, path := range paths {
+					if utils.SamePath(path, entry) {
+						matched[i] = true
+						entries = append(entries, entry)
+						entrySize, _ := utils.GetDirSize(entry)
+						size += entrySize
+						break
+					}
+				}
+			}
+			if len(entries) > 0 {
+				narrowed := target
+				narrowed.Entries = entries
+				narrowed.SizeBytes = size
+				narrowed.Files, narrowed.Dirs = 0, 0
+				selected[name] = append(selected[name], narrowed)
+			}
+		}
+	}
+
+	unmatched := []string{}
+	for i, path := range paths {
+		if !matched[i] {
+			unmatched = append(unmatched, path)
+		}
+	}
+	return selected, unmatched
+}
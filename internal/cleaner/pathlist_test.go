@@ -0,0 +1,93 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRemovedPaths(t *testing.T) {
+	tests := []struct {
+		target CleanTarget
+		want   []string
+	}{
+		{CleanTarget{Path: "/p/node_modules"}, []string{"/p/node_modules"}},
+		{CleanTarget{Path: "/cache", Entries: []string{"/cache/a", "/cache/b"}}, []string{"/cache/a", "/cache/b"}},
+		{CleanTarget{Path: "docker:images"}, []string{}},
+		// Maintenance actions only remove the path of the ones deleting it
+		{actionTarget("launchpad_layout", "/Users/me/Library/Application Support/Dock", 100), []string{}},
+		{actionTarget("launch_services", lsregister, 0), []string{}},
+		{actionTarget("dock_icon_cache", "/private/var/folders/x/C/com.apple.dock.iconcache", 100), []string{"/private/var/folders/x/C/com.apple.dock.iconcache"}},
+		// Evicted and archived files stay on disk
+		{CleanTarget{Path: "/Users/me/Library/Mobile Documents/big.mov", Action: ActionEvict}, []string{}},
+		{CleanTarget{Path: "/Users/me/Desktop/Screenshot.png", Action: ActionArchive}, []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.target.RemovedPaths(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("RemovedPaths() of %s = %v, want %v", tt.target.Path, got, tt.want)
+		}
+	}
+}
+
+func TestParsePathList(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"nul separated", "/a/b\x00/c d/e\x00", []string{"/a/b", "/c d/e"}},
+		{"lines", "/a/b\r\n\n/c/\n", []string{"/a/b", "/c"}},
+		{"newline in a name", "/a\nb\x00/c\x00", []string{"/a\nb", "/c"}},
+		{"empty", "", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParsePathList([]byte(tt.data)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePathList(%q) = %q, want %q", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectPaths(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	cache := filepath.Join(tmpDir, "cache")
+	createTestFile(t, cache, "a/data", "12345")
+	createTestFile(t, cache, "b/data", "123")
+
+	targetsByCleaner := map[string][]CleanTarget{
+		"Frontend": {
+			{Path: "/p/node_modules", SizeBytes: 100},
+			{Path: "/q/node_modules", SizeBytes: 200},
+		},
+		"System": {
+			{Path: cache, SizeBytes: 8, Files: 2, Entries: []string{filepath.Join(cache, "a"), filepath.Join(cache, "b")}},
+			actionTarget("launchpad_layout", "/dock", 100),
+		},
+	}
+
+	paths := []string{"/q/node_modules", filepath.Join(cache, "b"), "/p/node_modules/react", "/elsewhere", "/dock"}
+	selected, unmatched := SelectPaths(targetsByCleaner, paths)
+
+	if frontend := selected["Frontend"]; len(frontend) != 1 || frontend[0].Path != "/q/node_modules" || frontend[0].SizeBytes != 200 {
+		t.Errorf("Expected the listed node_modules whole, got %+v", frontend)
+	}
+	system := selected["System"]
+	if len(system) != 1 {
+		t.Fatalf("Expected the cache narrowed to the listed entry, got %+v", system)
+	}
+	if !reflect.DeepEqual(system[0].Entries, []string{filepath.Join(cache, "b")}) || system[0].SizeBytes != 3 {
+		t.Errorf("Expected entry b only, 3 bytes, got %v and %d", system[0].Entries, system[0].SizeBytes)
+	}
+	if len(targetsByCleaner["System"][0].Entries) != 2 {
+		t.Error("Expected the scanned target to be left untouched")
+	}
+	if !reflect.DeepEqual(unmatched, []string{"/p/node_modules/react", "/elsewhere", "/dock"}) {
+		t.Errorf("Expected the paths no target removes, got %v", unmatched)
+	}
+}
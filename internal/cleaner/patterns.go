@@ -0,0 +1,282 @@
+package cleaner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+// projectType identifies a kind of project from the directory a pattern
+// matched in
+type projectType struct {
+	name     string   // e.g. "Rust"
+	markers  []string // Files (glob patterns allowed) one of which marks the project
+	dirNames []string // Alternatively, names the directory itself may have
+}
+
+// Project types used to confirm pattern matches
+var (
+	rustProject    = projectType{name: "Rust", markers: []string{"Cargo.toml"}}
+	phpProject     = projectType{name: "PHP", markers: []string{"composer.json"}}
+	flutterProject = projectType{name: "Flutter", markers: []string{"pubspec.yaml"}}
+	androidProject = projectType{name: "Android", markers: []string{"gradle.properties"}, dirNames: []string{"app"}}
+	scalaProject   = projectType{name: "Scala", markers: []string{"build.sbt", "build.sc"}}
+	swiftPackage   = projectType{name: "Swift package", markers: []string{"Package.swift"}}
+	carthageDir    = projectType{name: "Carthage", dirNames: []string{"Carthage"}}
+	podsProject    = projectType{name: "CocoaPods", markers: []string{"Podfile"}}
+	reactNativeDir = projectType{name: "React Native Android", dirNames: []string{"android"}}
+	// anyProject is marked by the manifest of any kind of project
+	anyProject = projectType{name: "Project", markers: []string{
+		"package.json", "pyproject.toml", "setup.py", "CMakeLists.txt", "meson.build", "Makefile",
+		"Cargo.toml", "go.mod", "pom.xml", "build.gradle*", "composer.json", "Gemfile",
+		"Package.swift", "pubspec.yaml", "*.csproj", "*.sln", "mix.exs", "deno.json",
+	}}
+)
+
+// within reports whether dir or one of its ancestors is a project of this
+// type. The home directory and above are not projects.
+func (p projectType) within(dir string) bool {
+	home, _ := os.UserHomeDir()
+	for ; dir != home; dir = filepath.Dir(dir) {
+		if p.matches(dir) {
+			return true
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return false
+}
+
+// matches reports whether dir is a project of this type
+func (p projectType) matches(dir string) bool {
+	for _, name := range p.dirNames {
+		if filepath.Base(dir) == name {
+			return true
+		}
+	}
+	for _, marker := range p.markers {
+		if matches, _ := filepath.Glob(filepath.Join(dir, marker)); len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// projectPattern describes a file or folder found in projects that can be
+// removed. Patterns are matched by name in the search directories; the
+// optional conditions are checked on each match.
+type projectPattern struct {
+	pattern     string             // Name pattern passed to the scanner (e.g. "__pycache__", "*.pyc")
+	category    string             // Target category, categoryName(pattern) if empty
+	description string             // Target description
+	safety      config.SafetyLevel // Risk of removing the match
+	project     *projectType       // Project the match's parent directory must be, if set
+	notProject  *projectType       // Project the match's parent directory must not be, if set
+	inProject   *projectType       // Project the match's parent or one of its ancestors must be, if set
+	contains    string             // Entry (glob pattern allowed) that must exist inside the match, if set
+	unless      string             // Entry (glob pattern allowed) that must not exist inside the match, if set
+	packages    []string           // Packages one of which the project's package.json must depend on, if set
+	skipNested  bool               // Ignore matches directly inside another match (e.g. nested node_modules)
+}
+
+// projectPatterns is the registry of simple project patterns, by domain.
+// Cleaners scan the patterns of their domain with scanProjectPatterns; a
+// new kind of project leftover only needs a new entry here.
+var projectPatterns = map[config.Domain][]projectPattern{
+	config.DomainFrontend: {
+		{pattern: "node_modules", description: "node_modules dependencies", safety: config.Moderate, skipNested: true},
+		// Generic names, only build outputs inside a project (not a folder
+		// of photos named "out"). CMake builds are the C/C++ cleaner's.
+		{pattern: "dist", description: "Build output (dist)", safety: config.Safe, inProject: &anyProject, unless: "CMakeCache.txt"},
+		{pattern: "build", description: "Build output (build)", safety: config.Safe, inProject: &anyProject, unless: "CMakeCache.txt"},
+		{pattern: "out", description: "Build output (out)", safety: config.Safe, inProject: &anyProject, unless: "CMakeCache.txt"},
+		{pattern: ".next", description: "Next.js build cache", safety: config.Safe},
+		// Framework folders with generic names, only where the framework is used
+		{pattern: ".cache", category: "gatsby_cache", description: "Gatsby cache", safety: config.Safe, packages: []string{"gatsby"}},
+		{pattern: ".cache", category: "remix_cache", description: "Remix build cache", safety: config.Safe, packages: []string{"@remix-run/dev"}},
+		{pattern: ".astro", description: "Astro generated types and content cache", safety: config.Safe, packages: []string{"astro"}},
+		{pattern: ".svelte-kit", description: "SvelteKit build output", safety: config.Safe, packages: []string{"@sveltejs/kit"}},
+		{pattern: ".nuxt", description: "Nuxt build output", safety: config.Safe, packages: []string{"nuxt"}},
+		{pattern: ".output", category: "nuxt_output", description: "Nuxt server build (.output)", safety: config.Safe, packages: []string{"nuxt", "nitropack"}},
+		{pattern: "**/.vercel/output", category: "vercel_output", description: "Vercel build output", safety: config.Safe,
+			packages: []string{"vercel", "next", "nuxt", "@sveltejs/adapter-vercel", "@astrojs/vercel", "@vercel/remix"}},
+		{pattern: ".vite", description: "Vite cache", safety: config.Safe},
+		{pattern: ".parcel-cache", description: "Parcel cache", safety: config.Safe},
+		{pattern: "coverage", description: "Test coverage reports", safety: config.Safe},
+		{pattern: ".nyc_output", description: "NYC coverage output", safety: config.Safe},
+		{pattern: ".eslintcache", description: "ESLint cache", safety: config.Safe},
+		{pattern: "storybook-static", description: "Storybook static build", safety: config.Safe},
+		{pattern: "npm-debug.log*", description: "npm debug logs", safety: config.Safe},
+		{pattern: "yarn-error.log*", description: "Yarn error logs", safety: config.Safe},
+		{pattern: "yarn-debug.log*", description: "Yarn debug logs", safety: config.Safe},
+	},
+	config.DomainBackend: {
+		{pattern: "__pycache__", description: "Python bytecode cache", safety: config.Safe},
+		{pattern: "*.pyc", description: "Python compiled files", safety: config.Safe},
+		{pattern: ".pytest_cache", description: "pytest cache", safety: config.Safe},
+		{pattern: ".mypy_cache", description: "mypy type checker cache", safety: config.Safe},
+		{pattern: ".tox", description: "tox test environments", safety: config.Safe},
+		// Rust target folders are handled separately (cargo-sweep)
+		{pattern: "target", description: "Build output (target)", safety: config.Safe, notProject: &rustProject},
+		{pattern: "vendor", category: "php_vendor", description: "PHP vendor dependencies", safety: config.Moderate, project: &phpProject},
+		{pattern: ".bloop", description: "Bloop build server output", safety: config.Safe, project: &scalaProject},
+		{pattern: ".metals", description: "Metals language server cache", safety: config.Safe, project: &scalaProject},
+	},
+	config.DomainMobile: {
+		{pattern: "build", category: "android_build", description: "Android build output", safety: config.Safe, project: &androidProject},
+		{pattern: ".dart_tool", category: "dart_tool", description: "Flutter/Dart build cache", safety: config.Safe},
+		{pattern: "build", category: "flutter_build", description: "Flutter build output", safety: config.Safe, project: &flutterProject},
+		{pattern: ".build", category: "swiftpm_build", description: "Swift package build output", safety: config.Safe, project: &swiftPackage},
+		{pattern: "Build", category: "carthage_build", description: "Carthage built frameworks", safety: config.Safe, project: &carthageDir},
+		{pattern: "Pods", category: "cocoapods_pods", description: "CocoaPods dependencies (Pods)", safety: config.Moderate, project: &podsProject},
+		{pattern: ".gradle", category: "android_project_gradle", description: "Android project Gradle cache", safety: config.Safe, project: &reactNativeDir},
+		{pattern: ".cxx", category: "android_native_build", description: "Android native build cache (Hermes, C++)", safety: config.Safe, project: &androidProject},
+	},
+	config.DomainDataML: {
+		{pattern: ".ipynb_checkpoints", description: "Jupyter notebook checkpoints", safety: config.Safe},
+		{pattern: "wandb", description: "W&B experiment logs", safety: config.Moderate, contains: "run-*"},
+		{pattern: "mlruns", description: "MLflow experiment runs", safety: config.Moderate},
+		{pattern: ".DS_Store", description: "macOS metadata files", safety: config.Safe},
+	},
+}
+
+// projectPatternNames returns the scanner patterns registered for a domain
+func projectPatternNames(domain config.Domain) []string {
+	names := []string{}
+	for _, p := range projectPatterns[domain] {
+		if !slices.Contains(names, p.pattern) {
+			names = append(names, p.pattern)
+		}
+	}
+	return names
+}
+
+// scanProjectPatterns scans every registered pattern of a domain that the
+// clean level allows and returns the matches as targets
+func scanProjectPatterns(ctx context.Context, s *scanner.Scanner, cfg *config.Config, domain config.Domain) []CleanTarget {
+	targets := []CleanTarget{}
+
+	for _, p := range projectPatterns[domain] {
+		if !cfg.Allows(domain, p.categoryName(), p.safety) {
+			continue
+		}
+		targets = append(targets, p.scan(ctx, s)...)
+	}
+
+	return targets
+}
+
+// categoryName returns the category of the pattern's targets
+func (p projectPattern) categoryName() string {
+	if p.category != "" {
+		return p.category
+	}
+	return categoryName(p.pattern)
+}
+
+// scan finds the matches of the pattern that meet its conditions
+func (p projectPattern) scan(ctx context.Context, s *scanner.Scanner) []CleanTarget {
+	targets := []CleanTarget{}
+
+	resultChan := s.FindByPattern(ctx, p.pattern)
+	for result := range resultChan {
+		if result.Err != nil || !p.accepts(result.Path) {
+			continue
+		}
+
+		targets = append(targets, CleanTarget{
+			Path:        result.Path,
+			Category:    p.categoryName(),
+			Description: p.description,
+			SizeBytes:   result.Size,
+			Files:       result.Files,
+			Dirs:        result.Dirs,
+			Safety:      p.safety,
+		})
+	}
+
+	return targets
+}
+
+// projectDir returns the project folder of a match: its parent, or for a
+// path pattern the folder the segments after ** start from
+func (p projectPattern) projectDir(path string) string {
+	dir := filepath.Dir(path)
+	for i := strings.Count(strings.TrimPrefix(p.pattern, "**/"), "/"); i > 0; i-- {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+// accepts checks the pattern's conditions on a match
+func (p projectPattern) accepts(path string) bool {
+	parent := filepath.Dir(path)
+
+	if p.skipNested {
+		if matched, _ := filepath.Match(p.pattern, filepath.Base(parent)); matched {
+			return false
+		}
+	}
+	if p.project != nil && !p.project.matches(parent) {
+		return false
+	}
+	if p.notProject != nil && p.notProject.matches(parent) {
+		return false
+	}
+	if p.inProject != nil && !p.inProject.within(parent) {
+		return false
+	}
+	if p.contains != "" {
+		if matches, _ := filepath.Glob(filepath.Join(path, p.contains)); len(matches) == 0 {
+			return false
+		}
+	}
+	if p.unless != "" {
+		if matches, _ := filepath.Glob(filepath.Join(path, p.unless)); len(matches) > 0 {
+			return false
+		}
+	}
+	if p.packages != nil && !dependsOn(p.projectDir(path), p.packages...) {
+		return false
+	}
+
+	return true
+}
+
+// dependsOn reports whether the package.json of dir lists one of packages
+// in its dependencies or devDependencies
+func dependsOn(dir string, packages ...string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return false
+	}
+
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return false
+	}
+
+	for _, name := range packages {
+		_, inDeps := pkg.Dependencies[name]
+		_, inDevDeps := pkg.DevDependencies[name]
+		if inDeps || inDevDeps {
+			return true
+		}
+	}
+	return false
+}
+
+// isCargoTarget reports whether a target folder belongs to a Rust project
+func isCargoTarget(path string) bool {
+	return rustProject.matches(filepath.Dir(path))
+}
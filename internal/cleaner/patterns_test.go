@@ -0,0 +1,262 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+func TestProjectType_Matches(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "rusty/Cargo.toml", "[package]")
+	createTestFile(t, tmpDir, "plain/README.md", "# plain")
+	createTestFile(t, tmpDir, "app/main.kt", "fun main() {}")
+
+	tests := []struct {
+		project  projectType
+		dir      string
+		expected bool
+	}{
+		{rustProject, "rusty", true},
+		{rustProject, "plain", false},
+		{androidProject, "app", true}, // Directory name alone is enough
+		{androidProject, "plain", false},
+		{projectType{markers: []string{"*.md"}}, "plain", true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.project.matches(filepath.Join(tmpDir, tt.dir)); got != tt.expected {
+			t.Errorf("%s project matches(%s) = %v, expected %v", tt.project.name, tt.dir, got, tt.expected)
+		}
+	}
+}
+
+func TestProjectPattern_Accepts(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "shop/composer.json", "{}")
+	createTestFile(t, tmpDir, "shop/vendor/autoload.php", "<?php")
+	createTestFile(t, tmpDir, "site/vendor/lib.js", "lib")
+	createTestFile(t, tmpDir, "rusty/Cargo.toml", "[package]")
+	createTestFile(t, tmpDir, "rusty/target/debug/app", "bin")
+	createTestFile(t, tmpDir, "java/target/app.jar", "jar")
+	createTestFile(t, tmpDir, "ml/wandb/run-20240101/log", "log")
+	createTestFile(t, tmpDir, "empty/wandb/settings", "")
+	createTestFile(t, tmpDir, "scala/build.sbt", "name := \"app\"")
+	createTestFile(t, tmpDir, "scala/.metals/metals.h2.db", "db")
+	createTestFile(t, tmpDir, "notes/.metals/metals.log", "log")
+	createTestFile(t, tmpDir, "web/package.json", "{}")
+	createTestFile(t, tmpDir, "web/packages/ui/dist/index.js", "js")
+	createTestFile(t, tmpDir, "native/CMakeLists.txt", "project(app)")
+	createTestFile(t, tmpDir, "native/out/app", "bin")
+	createTestFile(t, tmpDir, "native/build/CMakeCache.txt", "cache")
+	createTestFile(t, tmpDir, "Documents/holidays/out/IMG_0001.jpg", "photo")
+
+	vendor := projectPattern{pattern: "vendor", project: &phpProject}
+	target := projectPattern{pattern: "target", notProject: &rustProject}
+	wandb := projectPattern{pattern: "wandb", contains: "run-*"}
+	nodeModules := projectPattern{pattern: "node_modules", skipNested: true}
+	metals := projectPattern{pattern: ".metals", project: &scalaProject}
+	dist := projectPattern{pattern: "dist", inProject: &anyProject}
+	out := projectPattern{pattern: "out", inProject: &anyProject}
+	build := projectPattern{pattern: "build", inProject: &anyProject, unless: "CMakeCache.txt"}
+
+	tests := []struct {
+		name     string
+		pattern  projectPattern
+		path     string
+		expected bool
+	}{
+		{"vendor in Composer project", vendor, "shop/vendor", true},
+		{"vendor elsewhere", vendor, "site/vendor", false},
+		{"Java target", target, "java/target", true},
+		{"Rust target", target, "rusty/target", false},
+		{"wandb with runs", wandb, "ml/wandb", true},
+		{"wandb without runs", wandb, "empty/wandb", false},
+		{"top-level node_modules", nodeModules, "web/node_modules", true},
+		{"nested node_modules", nodeModules, "web/node_modules/node_modules", false},
+		{"Metals in sbt project", metals, "scala/.metals", true},
+		{"Metals outside Scala project", metals, "notes/.metals", false},
+		{"dist in a workspace package", dist, "web/packages/ui/dist", true},
+		{"out in a CMake project", out, "native/out", true},
+		{"out of photos", out, "Documents/holidays/out", false},
+		{"CMake build directory", build, "native/build", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pattern.accepts(filepath.Join(tmpDir, tt.path)); got != tt.expected {
+				t.Errorf("accepts(%s) = %v, expected %v", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScanProjectPatterns(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "shop/composer.json", "{}")
+	createTestFile(t, tmpDir, "shop/vendor/autoload.php", "<?php")
+	createTestFile(t, tmpDir, "shop/__pycache__/mod.cpython-312.pyc", "bytecode")
+	createTestFile(t, tmpDir, "rusty/Cargo.toml", "[package]")
+	createTestFile(t, tmpDir, "rusty/target/debug/app", "bin")
+
+	s, _ := scanner.NewScannerWithDirs([]string{tmpDir})
+
+	tests := []struct {
+		level    config.CleanLevel
+		expected map[string]string // Path to category
+	}{
+		{config.Conservative, map[string]string{
+			filepath.Join(tmpDir, "shop", "__pycache__"):                        "pycache",
+			filepath.Join(tmpDir, "shop", "__pycache__", "mod.cpython-312.pyc"): "pyc",
+		}},
+		{config.Standard, map[string]string{
+			filepath.Join(tmpDir, "shop", "__pycache__"):                        "pycache",
+			filepath.Join(tmpDir, "shop", "__pycache__", "mod.cpython-312.pyc"): "pyc",
+			filepath.Join(tmpDir, "shop", "vendor"):                             "php_vendor",
+		}},
+	}
+
+	for _, tt := range tests {
+		cfg := config.NewDefaultConfig()
+		cfg.CleanLevel = tt.level
+
+		targets := scanProjectPatterns(context.Background(), s, cfg, config.DomainBackend)
+		if len(targets) != len(tt.expected) {
+			t.Fatalf("Level %v: expected %d targets, got %d: %v", tt.level, len(tt.expected), len(targets), targets)
+		}
+		for _, target := range targets {
+			category, ok := tt.expected[target.Path]
+			if !ok {
+				t.Errorf("Level %v: unexpected target %s", tt.level, target.Path)
+				continue
+			}
+			if target.Category != category {
+				t.Errorf("Expected category %q for %s, got %q", category, target.Path, target.Category)
+			}
+		}
+	}
+}
+
+func TestProjectPatternNames_Unique(t *testing.T) {
+	names := projectPatternNames(config.DomainMobile)
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			t.Errorf("Pattern %q listed twice", name)
+		}
+		seen[name] = true
+	}
+	if !seen["build"] || !seen[".dart_tool"] {
+		t.Errorf("Expected build and .dart_tool patterns, got %v", names)
+	}
+}
+
+func TestScanProjectPatterns_Swift(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "kit/Package.swift", "// swift-tools-version:5.9")
+	createTestFile(t, tmpDir, "kit/.build/debug/kit.o", "object")
+	createTestFile(t, tmpDir, "app/Carthage/Build/Alamofire.xcframework/Info.plist", "plist")
+	createTestFile(t, tmpDir, "app/Carthage/Checkouts/Alamofire/README.md", "readme")
+	createTestFile(t, tmpDir, "docs/Build/index.html", "html")  // Not Carthage output
+	createTestFile(t, tmpDir, "tool/.build/cache.bin", "cache") // No Package.swift
+
+	s, _ := scanner.NewScannerWithDirs([]string{tmpDir})
+	targets := scanProjectPatterns(context.Background(), s, config.NewDefaultConfig(), config.DomainMobile)
+
+	expected := map[string]string{
+		filepath.Join(tmpDir, "kit", ".build"):            "swiftpm_build",
+		filepath.Join(tmpDir, "app", "Carthage", "Build"): "carthage_build",
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %d targets, got %d: %v", len(expected), len(targets), targets)
+	}
+	for _, target := range targets {
+		if expected[target.Path] != target.Category {
+			t.Errorf("Unexpected target %s (%s)", target.Path, target.Category)
+		}
+	}
+}
+
+func TestScanProjectPatterns_ReactNative(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "rn/ios/Podfile", "platform :ios")
+	createTestFile(t, tmpDir, "rn/ios/Pods/hermes-engine/hermes.xcframework", "framework")
+	createTestFile(t, tmpDir, "rn/android/.gradle/8.3/checksums.bin", "gradle")
+	createTestFile(t, tmpDir, "rn/android/app/.cxx/Debug/build.ninja", "ninja")
+	createTestFile(t, tmpDir, "notes/Pods/list.txt", "pods") // No Podfile
+
+	s, _ := scanner.NewScannerWithDirs([]string{tmpDir})
+	cfg := config.NewDefaultConfig()
+	cfg.CleanLevel = config.Standard
+	targets := scanProjectPatterns(context.Background(), s, cfg, config.DomainMobile)
+
+	expected := map[string]string{
+		filepath.Join(tmpDir, "rn", "ios", "Pods"):            "cocoapods_pods",
+		filepath.Join(tmpDir, "rn", "android", ".gradle"):     "android_project_gradle",
+		filepath.Join(tmpDir, "rn", "android", "app", ".cxx"): "android_native_build",
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %d targets, got %d: %v", len(expected), len(targets), targets)
+	}
+	for _, target := range targets {
+		if expected[target.Path] != target.Category {
+			t.Errorf("Unexpected target %s (%s)", target.Path, target.Category)
+		}
+	}
+}
+
+func TestScanProjectPatterns_Frameworks(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "blog/package.json", `{"dependencies": {"gatsby": "^5.13.0"}}`)
+	createTestFile(t, tmpDir, "blog/.cache/webpack/stats.json", "stats")
+	createTestFile(t, tmpDir, "docs/package.json", `{"devDependencies": {"astro": "^4.0.0", "@astrojs/vercel": "^7.0.0"}}`)
+	createTestFile(t, tmpDir, "docs/.astro/types.d.ts", "types")
+	createTestFile(t, tmpDir, "docs/.vercel/output/config.json", "{}")
+	createTestFile(t, tmpDir, "docs/.vercel/project.json", "{}")
+	createTestFile(t, tmpDir, "shop/package.json", `{"dependencies": {"nuxt": "^3.9.0"}}`)
+	createTestFile(t, tmpDir, "shop/.nuxt/nuxt.d.ts", "types")
+	createTestFile(t, tmpDir, "shop/.output/server/index.mjs", "server")
+	createTestFile(t, tmpDir, "kit/package.json", `{"devDependencies": {"@sveltejs/kit": "^2.0.0"}}`)
+	createTestFile(t, tmpDir, "kit/.svelte-kit/generated/root.js", "root")
+	// Generic names in projects without the framework
+	createTestFile(t, tmpDir, "tool/package.json", `{"dependencies": {"react": "^18.2.0"}}`)
+	createTestFile(t, tmpDir, "tool/.cache/data.json", "data")
+	createTestFile(t, tmpDir, "tool/.output/report.txt", "report")
+	createTestFile(t, tmpDir, "notes/.cache/thumbnails.db", "thumbnails")
+
+	s, _ := scanner.NewScannerWithDirs([]string{tmpDir})
+	targets := scanProjectPatterns(context.Background(), s, config.NewDefaultConfig(), config.DomainFrontend)
+
+	expected := map[string]string{
+		filepath.Join(tmpDir, "blog", ".cache"):            "gatsby_cache",
+		filepath.Join(tmpDir, "docs", ".astro"):            "astro",
+		filepath.Join(tmpDir, "docs", ".vercel", "output"): "vercel_output",
+		filepath.Join(tmpDir, "shop", ".nuxt"):             "nuxt",
+		filepath.Join(tmpDir, "shop", ".output"):           "nuxt_output",
+		filepath.Join(tmpDir, "kit", ".svelte-kit"):        "svelte_kit",
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %d targets, got %d: %v", len(expected), len(targets), targets)
+	}
+	for _, target := range targets {
+		if expected[target.Path] != target.Category {
+			t.Errorf("Unexpected target %s (%s)", target.Path, target.Category)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package cleaner
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// scanPHPProjectCaches finds framework caches inside Composer projects:
+// Laravel storage/framework/cache, Symfony var/cache and PHPUnit result
+// caches. All of them are regenerated on the next request or test run.
+func scanPHPProjectCaches(ctx context.Context, s *scanner.Scanner) []CleanTarget {
+	targets := []CleanTarget{}
+
+	resultChan := s.FindByPattern(ctx, "composer.json")
+	for result := range resultChan {
+		if result.Err != nil || isInsideVendor(result.Path) {
+			continue
+		}
+
+		targets = append(targets, phpProjectCaches(filepath.Dir(result.Path))...)
+	}
+
+	return targets
+}
+
+// phpProjectCaches returns the cache targets of a single Composer project
+func phpProjectCaches(projectDir string) []CleanTarget {
+	targets := []CleanTarget{}
+
+	candidates := []struct {
+		path        string
+		category    string
+		description string
+		applies     bool
+	}{
+		{
+			path:        filepath.Join(projectDir, "storage", "framework", "cache"),
+			category:    "laravel_cache",
+			description: "Laravel framework cache",
+			applies:     utils.PathExists(filepath.Join(projectDir, "artisan")),
+		},
+		{
+			path:        filepath.Join(projectDir, "var", "cache"),
+			category:    "symfony_cache",
+			description: "Symfony cache",
+			applies:     isSymfonyProject(projectDir),
+		},
+		{
+			path:        filepath.Join(projectDir, ".phpunit.result.cache"),
+			category:    "phpunit_cache",
+			description: "PHPUnit result cache",
+			applies:     true,
+		},
+	}
+
+	for _, candidate := range candidates {
+		if !candidate.applies || !utils.PathExists(candidate.path) {
+			continue
+		}
+
+		size, _ := utils.GetDirSize(candidate.path)
+		if size > 0 {
+			targets = append(targets, CleanTarget{
+				Path:        candidate.path,
+				Category:    candidate.category,
+				Description: candidate.description,
+				SizeBytes:   size,
+				Safety:      config.Safe,
+			})
+		}
+	}
+
+	return targets
+}
+
+// isSymfonyProject reports whether a Composer project uses Symfony
+func isSymfonyProject(projectDir string) bool {
+	return utils.PathExists(filepath.Join(projectDir, "symfony.lock")) ||
+		utils.PathExists(filepath.Join(projectDir, "bin", "console"))
+}
+
+// isInsideVendor reports whether a path belongs to an installed dependency
+func isInsideVendor(path string) bool {
+	for _, part := range strings.Split(filepath.Dir(path), string(filepath.Separator)) {
+		if part == "vendor" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+func TestScanPHPProjectCaches(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	laravel := filepath.Join(tmpDir, "shop")
+	createTestFile(t, laravel, "composer.json", "{}")
+	createTestFile(t, laravel, "artisan", "#!/usr/bin/env php")
+	createTestFile(t, laravel, "storage/framework/cache/data/ab/cd", "cached")
+	createTestFile(t, laravel, ".phpunit.result.cache", "{}")
+
+	symfony := filepath.Join(tmpDir, "api")
+	createTestFile(t, symfony, "composer.json", "{}")
+	createTestFile(t, symfony, "symfony.lock", "{}")
+	createTestFile(t, symfony, "var/cache/dev/container.php", "<?php")
+
+	// Plain Composer project: framework folders are not caches here
+	plain := filepath.Join(tmpDir, "lib")
+	createTestFile(t, plain, "composer.json", "{}")
+	createTestFile(t, plain, "var/cache/data.bin", "not a framework cache")
+
+	// Dependencies shipping their own caches are left alone
+	createTestFile(t, laravel, "vendor/acme/pkg/composer.json", "{}")
+	createTestFile(t, laravel, "vendor/acme/pkg/.phpunit.result.cache", "{}")
+
+	s, _ := scanner.NewScannerWithDirs([]string{tmpDir})
+	targets := scanPHPProjectCaches(context.Background(), s)
+
+	expected := map[string]bool{
+		filepath.Join(laravel, "storage", "framework", "cache"): true,
+		filepath.Join(laravel, ".phpunit.result.cache"):         true,
+		filepath.Join(symfony, "var", "cache"):                  true,
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %d targets, got %d: %v", len(expected), len(targets), targets)
+	}
+	for _, target := range targets {
+		if !expected[target.Path] {
+			t.Errorf("Unexpected target %s", target.Path)
+		}
+		if target.SizeBytes == 0 {
+			t.Errorf("Expected non-zero size for %s", target.Path)
+		}
+	}
+}
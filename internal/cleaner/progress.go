@@ -0,0 +1,64 @@
+package cleaner
+
+import (
+	"context"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/quarantine"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// RemoveProgress reports how far the removal of a target holding more than
+// utils.RemoveBatch files has gone
+type RemoveProgress struct {
+	Target  CleanTarget
+	Files   int       // Files deleted so far
+	Total   int       // Files in the target when its removal started
+	Started time.Time // When the removal started
+}
+
+// Fraction returns the share of the target deleted so far, between 0 and 1
+func (p RemoveProgress) Fraction() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return min(1, float64(p.Files)/float64(p.Total))
+}
+
+// Remaining estimates the time the removal has left at the pace files were
+// deleted so far. It returns false while it can't tell yet.
+func (p RemoveProgress) Remaining() (time.Duration, bool) {
+	return utils.EstimateRemaining(time.Since(p.Started), int64(p.Files), int64(p.Total))
+}
+
+// progressKey is the context key of the function set by WithRemoveProgress
+type progressKey struct{}
+
+// WithRemoveProgress returns a context under which the cleaners deleting
+// targets from disk call progress as the removal of large targets goes
+// along. With several cleaners running at once, progress is called from
+// several goroutines.
+func WithRemoveProgress(ctx context.Context, progress func(RemoveProgress)) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+// removeProgress returns the function set by WithRemoveProgress, or nil
+func removeProgress(ctx context.Context) func(RemoveProgress) {
+	progress, _ := ctx.Value(progressKey{}).(func(RemoveProgress))
+	return progress
+}
+
+// quarantineKey is the context key of the store set by WithQuarantine
+type quarantineKey struct{}
+
+// WithQuarantine returns a context under which the cleaners deleting
+// targets from disk move them into store instead
+func WithQuarantine(ctx context.Context, store *quarantine.Store) context.Context {
+	return context.WithValue(ctx, quarantineKey{}, store)
+}
+
+// quarantineStore returns the store set by WithQuarantine, or nil
+func quarantineStore(ctx context.Context) *quarantine.Store {
+	store, _ := ctx.Value(quarantineKey{}).(*quarantine.Store)
+	return store
+}
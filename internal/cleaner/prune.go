@@ -0,0 +1,142 @@
+package cleaner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// PruneCriteria selects the entries of a cache directory to remove when it
+// is cleaned partially instead of wholesale. An entry must meet every
+// criterion that is set.
+type PruneCriteria struct {
+	MaxAge  time.Duration // Entries not modified for this long (0 = any age)
+	MinSize int64         // Entries at least this large (0 = any size)
+}
+
+// pruneCriteria returns the partial clean criteria of the configuration
+func pruneCriteria(cfg *config.Config) PruneCriteria {
+	return PruneCriteria{
+		MaxAge:  time.Duration(cfg.CacheMaxAgeDays) * 24 * time.Hour,
+		MinSize: cfg.CacheMinEntrySize,
+	}
+}
+
+// Enabled reports whether any criterion is set
+func (c PruneCriteria) Enabled() bool {
+	return c.MaxAge > 0 || c.MinSize > 0
+}
+
+// String describes the criteria, e.g. "older than 30 days, 10 MB or larger"
+func (c PruneCriteria) String() string {
+	parts := []string{}
+	if c.MaxAge > 0 {
+		parts = append(parts, fmt.Sprintf("older than %d days", int(c.MaxAge.Hours()/24)))
+	}
+	if c.MinSize > 0 {
+		parts = append(parts, utils.FormatBytes(c.MinSize)+" or larger")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// matches checks the criteria on an entry's size and last modification
+func (c PruneCriteria) matches(size int64, modified, now time.Time) bool {
+	if c.MaxAge > 0 && now.Sub(modified) < c.MaxAge {
+		return false
+	}
+	return size >= c.MinSize
+}
+
+// pruneEntries selects the entries inside root that meet the criteria.
+// With depth 0 the entries are the files themselves; otherwise they are the
+// files and folders depth levels below root, a folder's age being that of
+// its most recently modified file so that folders still in use are kept.
+func pruneEntries(root string, depth int, criteria PruneCriteria, now time.Time) []string {
+	entries := []string{}
+	rootDepth := strings.Count(filepath.Clean(root), string(filepath.Separator))
+
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		utils.Throttle()
+		if err != nil || path == root {
+			return nil
+		}
+		if utils.IsDatalessEntry(d) {
+			// Cloud-only entries take no local space
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		level := strings.Count(path, string(filepath.Separator)) - rootDepth
+		if d.IsDir() && (depth == 0 || level < depth) {
+			return nil
+		}
+
+		size, modified := entryUsage(path, d)
+		if criteria.matches(size, modified, now) {
+			entries = append(entries, path)
+		}
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+
+	return entries
+}
+
+// entryUsage returns the size of an entry and the last modification of its
+// files
+func entryUsage(path string, d fs.DirEntry) (int64, time.Time) {
+	if !d.IsDir() {
+		info, err := d.Info()
+		if err != nil {
+			return 0, time.Time{}
+		}
+		return info.Size(), info.ModTime()
+	}
+
+	var size int64
+	var modified time.Time
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		utils.Throttle()
+		if err != nil || info.IsDir() || utils.IsDataless(info) {
+			return nil
+		}
+		size += info.Size()
+		if info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+		return nil
+	})
+	return size, modified
+}
+
+// cacheTarget returns a target for a cache directory: the whole directory,
+// or only the entries meeting the partial clean criteria when they are set.
+// It returns false if there is nothing to remove.
+func cacheTarget(root string, depth int, criteria PruneCriteria, category, description string, safety config.SafetyLevel) (CleanTarget, bool) {
+	if !criteria.Enabled() {
+		size, _ := utils.GetDirSize(root)
+		if size == 0 {
+			return CleanTarget{}, false
+		}
+		return CleanTarget{
+			Path:        root,
+			Category:    category,
+			Description: description,
+			SizeBytes:   size,
+			Safety:      safety,
+		}, true
+	}
+
+	entries := pruneEntries(root, depth, criteria, time.Now())
+	return entriesTarget(root, entries, category, description+" "+criteria.String(), safety)
+}
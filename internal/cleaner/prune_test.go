@@ -0,0 +1,155 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+)
+
+// createAgedFile creates a file of the given size last modified age ago
+func createAgedFile(t *testing.T, dir, rel string, size int, age time.Duration) string {
+	t.Helper()
+	path := createTestFile(t, dir, rel, strings.Repeat("x", size))
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPruneCriteria_String(t *testing.T) {
+	criteria := PruneCriteria{MaxAge: 30 * day, MinSize: 10_000_000}
+	if got := criteria.String(); got != "older than 30 days, 10 MB or larger" {
+		t.Errorf("String() = %q", got)
+	}
+	if (PruneCriteria{}).Enabled() {
+		t.Error("Empty criteria should be disabled")
+	}
+}
+
+func TestPruneEntries_Files(t *testing.T) {
+	root := setupTestDir(t)
+	defer os.RemoveAll(root)
+
+	old := createAgedFile(t, root, "http/a/b/old", 100, 60*day)
+	createAgedFile(t, root, "http/a/c/recent", 100, 2*day)
+	oldSmall := createAgedFile(t, root, "wheels/small", 10, 60*day)
+
+	entries := pruneEntries(root, 0, PruneCriteria{MaxAge: 30 * day}, time.Now())
+	if len(entries) != 2 || !slices.Contains(entries, old) || !slices.Contains(entries, oldSmall) {
+		t.Errorf("Expected the two old files, got %v", entries)
+	}
+
+	entries = pruneEntries(root, 0, PruneCriteria{MaxAge: 30 * day, MinSize: 50}, time.Now())
+	if len(entries) != 1 || entries[0] != old {
+		t.Errorf("Expected only the large old file, got %v", entries)
+	}
+}
+
+func TestPruneEntries_Folders(t *testing.T) {
+	root := setupTestDir(t)
+	defer os.RemoveAll(root)
+
+	createAgedFile(t, root, "com.old.app/data", 100, 60*day)
+	createAgedFile(t, root, "com.old.app/sub/more", 100, 90*day)
+	// One recent file keeps the whole folder
+	createAgedFile(t, root, "com.used.app/data", 100, 60*day)
+	createAgedFile(t, root, "com.used.app/fresh", 1, time.Hour)
+	loose := createAgedFile(t, root, "loose.cache", 100, 60*day)
+
+	entries := pruneEntries(root, 1, PruneCriteria{MaxAge: 30 * day}, time.Now())
+	if len(entries) != 2 || !slices.Contains(entries, filepath.Join(root, "com.old.app")) || !slices.Contains(entries, loose) {
+		t.Errorf("Expected the unused app folder and the old file, got %v", entries)
+	}
+
+	entries = pruneEntries(root, 1, PruneCriteria{MinSize: 150}, time.Now())
+	if len(entries) != 1 || entries[0] != filepath.Join(root, "com.old.app") {
+		t.Errorf("Expected the folder of 200 bytes, got %v", entries)
+	}
+}
+
+func TestCacheTarget(t *testing.T) {
+	root := setupTestDir(t)
+	defer os.RemoveAll(root)
+
+	createAgedFile(t, root, "old", 300, 60*day)
+	createAgedFile(t, root, "recent", 100, day)
+
+	// Without criteria the whole cache is removed
+	target, ok := cacheTarget(root, 0, PruneCriteria{}, "pip_cache", "pip cache", config.Safe)
+	if !ok || target.SizeBytes != 400 || len(target.Entries) != 0 || target.Description != "pip cache" {
+		t.Errorf("Expected the whole cache, got %+v", target)
+	}
+
+	target, ok = cacheTarget(root, 0, PruneCriteria{MaxAge: 30 * day}, "pip_cache", "pip cache", config.Safe)
+	if !ok || target.SizeBytes != 300 || len(target.Entries) != 1 {
+		t.Fatalf("Expected only the old entry, got %+v", target)
+	}
+	if target.Description != "pip cache older than 30 days (1 entries)" {
+		t.Errorf("Description = %q", target.Description)
+	}
+
+	// Cleaning reports the bytes actually freed and keeps recent entries
+	result := removeMeasured(context.Background(), target)
+	if !result.Success || result.BytesFreed != 300 || result.Files != 1 {
+		t.Errorf("Expected 300 bytes freed in 1 file, got %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(root, "recent")); err != nil {
+		t.Error("Recent entries should be kept")
+	}
+
+	if _, ok := cacheTarget(root, 0, PruneCriteria{MaxAge: 30 * day}, "pip_cache", "pip cache", config.Safe); ok {
+		t.Error("Expected no target once old entries are gone")
+	}
+}
+
+func TestBackendScan_PrunedCaches(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	t.Setenv("XDG_CACHE_HOME", "")
+	pip, _ := filepath.Rel(home, pipCacheDir(platform.Current(), home))
+	createAgedFile(t, home, filepath.Join(pip, "http/a/old"), 100, 60*day)
+	createAgedFile(t, home, filepath.Join(pip, "http/b/recent"), 100, day)
+	createAgedFile(t, home, ".gradle/caches/modules-2/files-2.1/com.old/lib.jar", 100, 60*day)
+	createAgedFile(t, home, ".gradle/caches/modules-2/files-2.1/com.used/lib.jar", 100, day)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Home = home
+	cfg.CacheMaxAgeDays = 30
+
+	b, err := NewBackendCleaner()
+	if err != nil {
+		t.Fatalf("NewBackendCleaner() error = %v", err)
+	}
+	targets, err := b.Scan(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	for _, category := range []string{"pip_cache", "gradle_cache"} {
+		found := false
+		for _, target := range targets {
+			if target.Category != category {
+				continue
+			}
+			found = true
+			if len(target.Entries) != 1 || target.SizeBytes != 100 {
+				t.Errorf("%s: expected only the old entry, got %+v", category, target)
+			}
+		}
+		if !found {
+			t.Errorf("Expected a %s target", category)
+		}
+	}
+}
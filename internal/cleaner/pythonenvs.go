@@ -0,0 +1,246 @@
+package cleaner
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// pipxDirs returns the folder of the pipx virtualenvs and the folder of the
+// links to their commands. pipx 1.3 and later keep the virtualenvs in the
+// platform data folder, unless an older pipx left them in ~/.local/pipx.
+func pipxDirs(home string) (venvs string, bin string) {
+	pipxHome := os.Getenv("PIPX_HOME")
+	if pipxHome == "" {
+		pipxHome = filepath.Join(home, ".local", "pipx")
+		if !utils.PathExists(pipxHome) {
+			pipxHome = filepath.Join(platform.DataDir(home), "pipx")
+		}
+	}
+	bin = os.Getenv("PIPX_BIN_DIR")
+	if bin == "" {
+		bin = filepath.Join(home, ".local", "bin")
+	}
+	return filepath.Join(pipxHome, "venvs"), bin
+}
+
+// pipxApps returns a target for each app installed with pipx, with the
+// links to its commands, the largest first. They are Moderate: their
+// commands are gone until installed again.
+func pipxApps(venvs, bin string, now time.Time) []CleanTarget {
+	targets := []CleanTarget{}
+
+	entries, _ := os.ReadDir(venvs)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		venv := filepath.Join(venvs, entry.Name())
+		size, _ := utils.GetDirSize(venv)
+		if size == 0 {
+			continue
+		}
+
+		version := ""
+		if data, err := os.ReadFile(filepath.Join(venv, "pipx_metadata.json")); err == nil {
+			var metadata struct {
+				MainPackage struct {
+					PackageVersion string `json:"package_version"`
+				} `json:"main_package"`
+			}
+			if json.Unmarshal(data, &metadata) == nil && metadata.MainPackage.PackageVersion != "" {
+				version = " " + metadata.MainPackage.PackageVersion
+			}
+		}
+
+		description := "pipx app " + entry.Name() + version
+		if info, err := os.Stat(venv); err == nil {
+			description += fmt.Sprintf(" (installed %d days ago)", int(now.Sub(info.ModTime()).Hours()/24))
+		}
+
+		targets = append(targets, CleanTarget{
+			Path:        venv,
+			Category:    "pipx_app",
+			Description: description,
+			SizeBytes:   size,
+			Safety:      config.Moderate,
+			Entries:     append([]string{venv}, binLinksInto([]string{bin}, venv)...),
+		})
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool {
+		return targets[i].SizeBytes > targets[j].SizeBytes
+	})
+	return targets
+}
+
+// uvCache returns the uv cache folder: UV_CACHE_DIR, or the uv folder of
+// XDG_CACHE_HOME, which uv follows on macOS too, of the platform cache
+// folder if an older uv made one there, or of ~/.cache
+func uvCache(home string) string {
+	if dir := os.Getenv("UV_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	if platform.Current() == platform.WindowsLayout {
+		return filepath.Join(platform.DataDir(home), "uv", "cache")
+	}
+	if dir := os.Getenv("XDG_CACHE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "uv")
+	}
+	if path := filepath.Join(platform.CacheDir(home), "uv"); utils.PathExists(path) {
+		return path
+	}
+	return filepath.Join(home, ".cache", "uv")
+}
+
+// poetryVenvName returns the name Poetry gives the virtualenvs of a project:
+// its sanitized name and a hash of its folder, followed by -py<version>
+func poetryVenvName(name, dir string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" $`!*@\"\\\r\n\t", r) {
+			return '_'
+		}
+		return r
+	}, strings.ToLower(name))
+	if len(sanitized) > 42 {
+		sanitized = sanitized[:42]
+	}
+	return sanitized + "-" + poetryVenvHash(dir)
+}
+
+// poetryVenvHash returns the hash of a project folder in the names of its
+// Poetry virtualenvs
+func poetryVenvHash(dir string) string {
+	sum := sha256.Sum256([]byte(dir))
+	return base64.URLEncoding.EncodeToString(sum[:])[:8]
+}
+
+// pyprojectName returns the project name of a pyproject.toml, from its
+// [tool.poetry] or [project] table
+func pyprojectName(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	table := ""
+	lines := bufio.NewScanner(file)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if strings.HasPrefix(line, "[") {
+			table = line
+			continue
+		}
+		if table != "[tool.poetry]" && table != "[project]" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "name" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// poetryProjects maps the names Poetry gives virtualenvs to the projects
+// with a pyproject.toml in the search directories
+func poetryProjects(ctx context.Context, s *scanner.Scanner) map[string]string {
+	projects := make(map[string]string)
+	for result := range s.FindByPattern(ctx, "pyproject.toml") {
+		if result.Err != nil {
+			continue
+		}
+		if name := pyprojectName(result.Path); name != "" {
+			dir := filepath.Dir(result.Path)
+			projects[poetryVenvName(name, dir)] = dir
+		}
+	}
+	return projects
+}
+
+// poetryVenvSource returns the project folder a Poetry virtualenv installed
+// in development mode, from the .pth files Poetry writes in site-packages.
+// Editable dependencies have .pth files too, so only the folder whose hash
+// is in the virtualenv's name (name without -py<version>) is the project.
+func poetryVenvSource(venv, name string) string {
+	pths, _ := filepath.Glob(filepath.Join(venv, "lib", "python*", "site-packages", "*.pth"))
+	for _, pth := range pths {
+		data, err := os.ReadFile(pth)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if !filepath.IsAbs(line) {
+				continue
+			}
+			for _, dir := range []string{line, strings.TrimSuffix(line, string(filepath.Separator)+"src")} {
+				if strings.HasSuffix(name, "-"+poetryVenvHash(dir)) {
+					return dir
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// poetryVenvs returns a target for each Poetry virtualenv, named after the
+// project it belongs to. Those of deleted projects are Safe, the others
+// Moderate: their project needs a poetry install.
+func poetryVenvs(dir string, projects map[string]string) []CleanTarget {
+	targets := []CleanTarget{}
+
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		venv := filepath.Join(dir, entry.Name())
+		size, _ := utils.GetDirSize(venv)
+		if size == 0 {
+			continue
+		}
+
+		name, python := entry.Name(), ""
+		if i := strings.LastIndex(name, "-py"); i > 0 {
+			name, python = name[:i], " (Python "+name[i+3:]+")"
+		}
+
+		target := CleanTarget{
+			Path:      venv,
+			Category:  "poetry_virtualenv",
+			SizeBytes: size,
+			Safety:    config.Moderate,
+		}
+		project, ok := projects[name]
+		if !ok {
+			project = poetryVenvSource(venv, name)
+		}
+		switch {
+		case project != "" && !utils.PathExists(project):
+			target.Description = "Poetry virtualenv of deleted project " + project + python
+			target.Safety = config.Safe
+		case project != "":
+			target.Description = "Poetry virtualenv of " + project + python
+		default:
+			target.Description = "Poetry virtualenv " + name + python + " (project not found)"
+		}
+		targets = append(targets, target)
+	}
+
+	return targets
+}
@@ -0,0 +1,135 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+)
+
+func TestPoetryVenvName(t *testing.T) {
+	// As computed by Poetry's EnvManager.generate_env_name
+	if got := poetryVenvName("My API", "/Users/me/Projects/my-api"); got != "my_api-2M_Uclb4" {
+		t.Errorf("poetryVenvName() = %q", got)
+	}
+}
+
+func TestPyprojectName(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestFile(t, tmpDir, "poetry/pyproject.toml", "[build-system]\nrequires = [\"poetry-core\"]\n\n[tool.poetry]\nname = \"billing\"\nversion = \"0.1.0\"\n")
+	createTestFile(t, tmpDir, "pep621/pyproject.toml", "[project]\nname = 'reports'\n")
+	createTestFile(t, tmpDir, "tools/pyproject.toml", "[tool.black]\nline-length = 100\n")
+
+	tests := map[string]string{"poetry": "billing", "pep621": "reports", "tools": ""}
+	for dir, expected := range tests {
+		if got := pyprojectName(filepath.Join(tmpDir, dir, "pyproject.toml")); got != expected {
+			t.Errorf("pyprojectName(%s) = %q, expected %q", dir, got, expected)
+		}
+	}
+}
+
+func TestPoetryVenvs(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	live := filepath.Join(tmpDir, "Projects", "billing")
+	createTestFile(t, live, "pyproject.toml", "[tool.poetry]\nname = \"billing\"\n")
+	venvs := filepath.Join(tmpDir, "virtualenvs")
+	liveVenv := poetryVenvName("billing", live) + "-py3.12"
+	createTestFile(t, venvs, liveVenv+"/bin/python", "python")
+	// A project deleted since, known from the path Poetry installed it from
+	reports := filepath.Join(tmpDir, "Projects", "reports")
+	reportsVenv := poetryVenvName("reports", reports) + "-py3.11"
+	createTestFile(t, venvs, reportsVenv+"/lib/python3.11/site-packages/reports.pth", filepath.Join(reports, "src")+"\n")
+	// A live project whose editable dependency was deleted
+	createTestFile(t, venvs, "scratch-12345678-py3.10/lib/python3.10/site-packages/helpers.pth", filepath.Join(tmpDir, "Projects", "helpers")+"\n")
+
+	targets := poetryVenvs(venvs, map[string]string{poetryVenvName("billing", live): live})
+
+	found := make(map[string]CleanTarget)
+	for _, target := range targets {
+		found[filepath.Base(target.Path)] = target
+	}
+	if len(targets) != 3 {
+		t.Fatalf("Expected 3 virtualenvs, got %+v", targets)
+	}
+	if target := found[liveVenv]; target.Description != "Poetry virtualenv of "+live+" (Python 3.12)" || target.Safety != config.Moderate {
+		t.Errorf("Unexpected target %+v", target)
+	}
+	deleted := found[reportsVenv]
+	if deleted.Description != "Poetry virtualenv of deleted project "+reports+" (Python 3.11)" || deleted.Safety != config.Safe {
+		t.Errorf("Expected the virtualenv of the deleted project as Safe, got %+v", deleted)
+	}
+	if unknown := found["scratch-12345678-py3.10"]; unknown.Safety != config.Moderate {
+		t.Errorf("Expected an unknown project's virtualenv as Moderate, got %+v", unknown)
+	}
+}
+
+func TestPipxApps(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	venvs := filepath.Join(tmpDir, "pipx", "venvs")
+	bin := filepath.Join(tmpDir, "bin")
+	createTestFile(t, venvs, "black/pipx_metadata.json", `{"main_package": {"package": "black", "package_version": "24.1.1"}}`)
+	createTestFile(t, venvs, "black/bin/black", "script")
+	os.MkdirAll(bin, 0755)
+	os.Symlink(filepath.Join(venvs, "black", "bin", "black"), filepath.Join(bin, "black"))
+	os.Symlink("/usr/bin/true", filepath.Join(bin, "other"))
+
+	setOldTimes(t, filepath.Join(venvs, "black"), time.Now().AddDate(0, 0, -12))
+
+	targets := pipxApps(venvs, bin, time.Now())
+	if len(targets) != 1 {
+		t.Fatalf("Expected one app, got %+v", targets)
+	}
+	if targets[0].Description != "pipx app black 24.1.1 (installed 12 days ago)" || targets[0].Safety != config.Moderate {
+		t.Errorf("Unexpected target %+v", targets[0])
+	}
+	if !slices.Equal(targets[0].Entries, []string{filepath.Join(venvs, "black"), filepath.Join(bin, "black")}) {
+		t.Errorf("Expected the venv and its command link, got %v", targets[0].Entries)
+	}
+}
+
+func TestPipxDirs(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+	t.Setenv("PIPX_HOME", "")
+	t.Setenv("PIPX_BIN_DIR", "")
+
+	// pipx 1.3 and later use the platform data folder
+	if venvs, _ := pipxDirs(home); venvs != filepath.Join(platform.DataDir(home), "pipx", "venvs") {
+		t.Errorf("Expected the venvs in the data folder, got %s", venvs)
+	}
+
+	// Older pipx installs stay where they were
+	os.MkdirAll(filepath.Join(home, ".local", "pipx"), 0755)
+	if venvs, bin := pipxDirs(home); venvs != filepath.Join(home, ".local", "pipx", "venvs") || bin != filepath.Join(home, ".local", "bin") {
+		t.Errorf("Expected the venvs of the older pipx, got %s and %s", venvs, bin)
+	}
+}
+
+func TestUVCache(t *testing.T) {
+	if platform.Current() == platform.WindowsLayout {
+		t.Skip("uv keeps its cache in LOCALAPPDATA on Windows")
+	}
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+	t.Setenv("UV_CACHE_DIR", "")
+
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "xdg"))
+	if got := uvCache(home); got != filepath.Join(home, "xdg", "uv") {
+		t.Errorf("Expected the uv folder of XDG_CACHE_HOME, got %s", got)
+	}
+
+	t.Setenv("XDG_CACHE_HOME", "")
+	if got := uvCache(home); got != filepath.Join(home, ".cache", "uv") {
+		t.Errorf("Expected ~/.cache/uv, got %s", got)
+	}
+}
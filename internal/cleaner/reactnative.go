@@ -0,0 +1,61 @@
+package cleaner
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// metroTempPatterns match the caches Metro and its haste map leave in $TMPDIR
+var metroTempPatterns = []string{"metro-*", "haste-map-*"}
+
+// watchmanPrefixes are the Homebrew prefixes Watchman keeps its state under
+var watchmanPrefixes = []string{"/opt/homebrew", "/usr/local"}
+
+// scanReactNativeCaches returns the global caches React Native projects
+// build up: Metro bundler caches in tmpDir and ~/.metro, and the Watchman
+// log. Pods, android/.gradle and native build folders inside the projects
+// come from the pattern registry.
+func scanReactNativeCaches(home, tmpDir string) []CleanTarget {
+	targets := []CleanTarget{}
+
+	metro := []string{}
+	for _, pattern := range metroTempPatterns {
+		matches, _ := filepath.Glob(filepath.Join(tmpDir, pattern))
+		metro = append(metro, matches...)
+	}
+	if target, ok := entriesTarget(tmpDir, metro, "metro_cache", "Metro bundler cache", config.Safe); ok {
+		targets = append(targets, target)
+	}
+
+	metroHome := filepath.Join(home, ".metro")
+	if size, _ := utils.GetDirSize(metroHome); size > 0 {
+		targets = append(targets, CleanTarget{
+			Path:        metroHome,
+			Category:    "metro_cache",
+			Description: "Metro bundler cache (~/.metro)",
+			SizeBytes:   size,
+			Safety:      config.Safe,
+		})
+	}
+
+	if u, err := user.Current(); err == nil {
+		for _, prefix := range watchmanPrefixes {
+			log := filepath.Join(prefix, "var", "run", "watchman", u.Username+"-state", "log")
+			if info, err := os.Stat(log); err == nil && info.Size() > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        log,
+					Category:    "watchman_log",
+					Description: "Watchman log",
+					SizeBytes:   info.Size(),
+					Safety:      config.Safe,
+				})
+			}
+		}
+	}
+
+	return targets
+}
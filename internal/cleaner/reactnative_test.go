@@ -0,0 +1,37 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanReactNativeCaches(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	home := filepath.Join(tmpDir, "home")
+	temp := filepath.Join(tmpDir, "T")
+	createTestFile(t, temp, "metro-cache/a1/b2", "bundle")
+	createTestFile(t, temp, "haste-map-rn-1234", "map")
+	createTestFile(t, temp, "other-tool/cache", "keep")
+	createTestFile(t, home, ".metro/cache.json", "{}")
+
+	targets := scanReactNativeCaches(home, temp)
+
+	paths := make(map[string]CleanTarget)
+	for _, target := range targets {
+		paths[target.Path] = target
+	}
+
+	tempTarget, ok := paths[temp]
+	if !ok {
+		t.Fatalf("Expected a Metro target for %s, got %v", temp, targets)
+	}
+	if len(tempTarget.Entries) != 2 {
+		t.Errorf("Expected 2 Metro entries in TMPDIR, got %v", tempTarget.Entries)
+	}
+	if _, ok := paths[filepath.Join(home, ".metro")]; !ok {
+		t.Errorf("Expected ~/.metro target, got %v", targets)
+	}
+}
@@ -0,0 +1,50 @@
+package cleaner
+
+import "time"
+
+// RebuildCost is a rough estimate of what getting a removed target back
+// takes: the packages downloaded again and the typical reinstall time
+type RebuildCost struct {
+	Download int64         // Bytes downloaded again (0 when the target is compiled locally)
+	Time     time.Duration // Typical reinstall or rebuild time
+	Command  string        // Command that brings the target back, e.g. "npm install"
+}
+
+// rebuildProfile describes how a kind of target is rebuilt
+type rebuildProfile struct {
+	command       string
+	downloadRatio float64 // Share of the size on disk downloaded again (packages are compressed)
+	bytesPerSec   int64   // Size on disk restored per second, downloading and extracting or compiling
+}
+
+// rebuildProfiles holds the heuristics for targets that must be rebuilt
+// after deletion, by category. The figures assume a typical broadband
+// connection and a laptop: they are meant to weigh the trade-off, not to
+// predict the exact time.
+var rebuildProfiles = map[string]rebuildProfile{
+	"node_modules":     {command: "npm install", downloadRatio: 0.35, bytesPerSec: 15 * 1000 * 1000},
+	"php_vendor":       {command: "composer install", downloadRatio: 0.4, bytesPerSec: 15 * 1000 * 1000},
+	"cocoapods_pods":   {command: "pod install", downloadRatio: 0.5, bytesPerSec: 10 * 1000 * 1000},
+	"go_mod_cache":     {command: "go mod download", downloadRatio: 0.5, bytesPerSec: 20 * 1000 * 1000},
+	"maven_repository": {command: "mvn dependency:resolve", downloadRatio: 0.95, bytesPerSec: 10 * 1000 * 1000},
+	"nuget_packages":   {command: "dotnet restore", downloadRatio: 0.6, bytesPerSec: 15 * 1000 * 1000},
+	"rust_target":      {command: "cargo build", bytesPerSec: 5 * 1000 * 1000},
+	"tauri_target":     {command: "cargo build", bytesPerSec: 5 * 1000 * 1000},
+}
+
+// RebuildCost estimates what rebuilding the target would cost once it is
+// deleted. It reports false for targets that need no rebuild or whose cost
+// is unknown.
+func (t CleanTarget) RebuildCost() (RebuildCost, bool) {
+	profile, ok := rebuildProfiles[t.Category]
+	if !ok || t.SizeBytes <= 0 {
+		return RebuildCost{}, false
+	}
+
+	seconds := t.SizeBytes / profile.bytesPerSec
+	return RebuildCost{
+		Download: int64(float64(t.SizeBytes) * profile.downloadRatio),
+		Time:     time.Duration(max(seconds, 1)) * time.Second,
+		Command:  profile.command,
+	}, true
+}
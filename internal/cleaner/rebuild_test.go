@@ -0,0 +1,50 @@
+package cleaner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestRebuildCost(t *testing.T) {
+	target := CleanTarget{Category: "node_modules", SizeBytes: 300 * 1000 * 1000, Safety: config.Moderate}
+
+	cost, ok := target.RebuildCost()
+	if !ok {
+		t.Fatal("Expected a rebuild cost for node_modules")
+	}
+	if cost.Command != "npm install" {
+		t.Errorf("Command = %q, want npm install", cost.Command)
+	}
+	if cost.Download != 105*1000*1000 {
+		t.Errorf("Download = %d, want 105 MB", cost.Download)
+	}
+	if cost.Time != 20*time.Second {
+		t.Errorf("Time = %v, want 20s", cost.Time)
+	}
+}
+
+func TestRebuildCost_CompiledLocally(t *testing.T) {
+	cost, ok := CleanTarget{Category: "rust_target", SizeBytes: 1000}.RebuildCost()
+	if !ok {
+		t.Fatal("Expected a rebuild cost for rust_target")
+	}
+	if cost.Download != 0 {
+		t.Errorf("Build output is not downloaded, got %d", cost.Download)
+	}
+	if cost.Time != time.Second {
+		t.Errorf("Time = %v, want at least one second", cost.Time)
+	}
+}
+
+func TestRebuildCost_NoRebuild(t *testing.T) {
+	for _, target := range []CleanTarget{
+		{Category: "npm_cache", SizeBytes: 1000},
+		{Category: "node_modules"},
+	} {
+		if _, ok := target.RebuildCost(); ok {
+			t.Errorf("Expected no rebuild cost for %+v", target)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// Retention policies ("keep": N in the config file) keep the newest items of
+// a category and offer the older ones, each as its own target, instead of
+// the whole folder. Items are counted per group: the archives of one app,
+// the simulators of one platform, the versions of one box.
+
+// beyondRetention returns the generations (dates or versions) past the keep
+// newest, compare ordering them from oldest to newest
+func beyondRetention(generations []string, keep int, compare func(a, b string) int) map[string]bool {
+	unique := []string{}
+	seen := make(map[string]bool)
+	for _, generation := range generations {
+		if !seen[generation] {
+			seen[generation] = true
+			unique = append(unique, generation)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool {
+		return compare(unique[i], unique[j]) > 0
+	})
+
+	older := make(map[string]bool)
+	if keep < len(unique) {
+		for _, generation := range unique[keep:] {
+			older[generation] = true
+		}
+	}
+	return older
+}
+
+// xcodeArchive is an .xcarchive, as its Info.plist describes it
+type xcodeArchive struct {
+	path    string
+	name    string // App name
+	created time.Time
+}
+
+// xcodeArchivesRetention returns how many archives of each app to keep, set
+// under mobile ("mobile": {"xcode_archives": {"keep": 2}}) whichever of the
+// Mobile and System cleaners finds them, or under system
+func xcodeArchivesRetention(cfg *config.Config) (int, bool) {
+	if keep, ok := cfg.Retention(config.DomainMobile, "xcode_archives"); ok {
+		return keep, true
+	}
+	return cfg.Retention(config.DomainSystem, "xcode_archives")
+}
+
+// retainedXcodeArchives returns one target per Xcode archive in
+// archivesPath (one folder per day) past the keep newest of its app.
+// Archives whose app cannot be read are kept.
+func retainedXcodeArchives(archivesPath string, keep int, safety config.SafetyLevel) []CleanTarget {
+	byApp := make(map[string][]xcodeArchive)
+	apps := []string{}
+
+	days, _ := os.ReadDir(archivesPath)
+	for _, day := range days {
+		if !day.IsDir() {
+			continue
+		}
+		entries, _ := os.ReadDir(filepath.Join(archivesPath, day.Name()))
+		for _, entry := range entries {
+			if filepath.Ext(entry.Name()) != ".xcarchive" {
+				continue
+			}
+			path := filepath.Join(archivesPath, day.Name(), entry.Name())
+			data, err := readPlistXML(ExecRunner{}, filepath.Join(path, "Info.plist"))
+			if err != nil {
+				continue
+			}
+			values, err := utils.ParsePlistDict(data)
+			if err != nil || values["Name"] == "" {
+				continue
+			}
+
+			archive := xcodeArchive{path: path, name: values["Name"]}
+			if created, err := time.Parse(time.RFC3339, values["CreationDate"]); err == nil {
+				archive.created = created.UTC()
+			} else if info, err := entry.Info(); err == nil {
+				archive.created = info.ModTime().UTC()
+			}
+
+			if _, ok := byApp[archive.name]; !ok {
+				apps = append(apps, archive.name)
+			}
+			byApp[archive.name] = append(byApp[archive.name], archive)
+		}
+	}
+
+	targets := []CleanTarget{}
+	sort.Strings(apps)
+	for _, app := range apps {
+		dates := []string{}
+		for _, archive := range byApp[app] {
+			dates = append(dates, archive.created.Format(time.RFC3339))
+		}
+		older := beyondRetention(dates, keep, strings.Compare)
+
+		for _, archive := range byApp[app] {
+			if !older[archive.created.Format(time.RFC3339)] {
+				continue
+			}
+			size, _ := utils.GetDirSize(archive.path)
+			targets = append(targets, CleanTarget{
+				Path:        archive.path,
+				Category:    "xcode_archives",
+				Description: fmt.Sprintf("Xcode archive of %s from %s, older than the %d kept", app, archive.created.Format("2006-01-02"), keep),
+				SizeBytes:   size,
+				Safety:      safety,
+			})
+		}
+	}
+	return targets
+}
+
+// simulatorRuntime returns the platform and version of a CoreSimulator
+// runtime identifier, as in "com.apple.CoreSimulator.SimRuntime.iOS-17-2"
+func simulatorRuntime(runtime string) (string, string, bool) {
+	name := runtime[strings.LastIndex(runtime, ".")+1:]
+	platform, version, ok := strings.Cut(name, "-")
+	if !ok || platform == "" || version == "" {
+		return "", "", false
+	}
+	return platform, strings.ReplaceAll(version, "-", "."), true
+}
+
+// retainedSimulators returns one target per simulator device in devicesPath
+// whose OS version is past the keep newest of its platform, so that keeping
+// 2 keeps the simulators of the 2 latest iOS versions. Devices whose
+// runtime cannot be read are kept.
+func retainedSimulators(devicesPath string, keep int) []CleanTarget {
+	type device struct {
+		path, name, version string
+	}
+	byPlatform := make(map[string][]device)
+	platforms := []string{}
+
+	entries, _ := os.ReadDir(devicesPath)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(devicesPath, entry.Name())
+		data, err := readPlistXML(ExecRunner{}, filepath.Join(path, "device.plist"))
+		if err != nil {
+			continue
+		}
+		values, err := utils.ParsePlistDict(data)
+		if err != nil {
+			continue
+		}
+		platform, version, ok := simulatorRuntime(values["runtime"])
+		if !ok {
+			continue
+		}
+
+		if _, ok := byPlatform[platform]; !ok {
+			platforms = append(platforms, platform)
+		}
+		byPlatform[platform] = append(byPlatform[platform], device{path, values["name"], version})
+	}
+
+	targets := []CleanTarget{}
+	sort.Strings(platforms)
+	for _, platform := range platforms {
+		versions := []string{}
+		for _, d := range byPlatform[platform] {
+			versions = append(versions, d.version)
+		}
+		older := beyondRetention(versions, keep, compareVersions)
+
+		for _, d := range byPlatform[platform] {
+			if !older[d.version] {
+				continue
+			}
+			size, _ := utils.GetDirSize(d.path)
+			targets = append(targets, CleanTarget{
+				Path:        d.path,
+				Category:    "simulator_devices",
+				Description: fmt.Sprintf("%s %s simulator %s, older than the %d %s versions kept", platform, d.version, d.name, keep, platform),
+				SizeBytes:   size,
+				Safety:      config.Moderate,
+			})
+		}
+	}
+	return targets
+}
+
+// retainedVagrantBoxes returns one target per version of a Vagrant box in
+// boxesPath (box/version/provider) past the keep newest of that box
+func retainedVagrantBoxes(boxesPath string, keep int) []CleanTarget {
+	targets := []CleanTarget{}
+
+	boxes, _ := os.ReadDir(boxesPath)
+	for _, box := range boxes {
+		if !box.IsDir() {
+			continue
+		}
+		// Slashes in box names are escaped in folder names
+		name := strings.ReplaceAll(box.Name(), "-VAGRANTSLASH-", "/")
+
+		versions := subdirNames(filepath.Join(boxesPath, box.Name()))
+		older := beyondRetention(versions, keep, compareVersions)
+
+		for _, version := range versions {
+			if !older[version] {
+				continue
+			}
+			path := filepath.Join(boxesPath, box.Name(), version)
+			size, _ := utils.GetDirSize(path)
+			targets = append(targets, CleanTarget{
+				Path:        path,
+				Category:    "vagrant_boxes",
+				Description: fmt.Sprintf("Vagrant box %s %s, older than the %d versions kept", name, version, keep),
+				SizeBytes:   size,
+				Safety:      config.Moderate,
+			})
+		}
+	}
+	return targets
+}
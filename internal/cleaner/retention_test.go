@@ -0,0 +1,138 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestBeyondRetention(t *testing.T) {
+	older := beyondRetention([]string{"16.4", "17.2", "15.0", "17.2", "16.4"}, 2, compareVersions)
+	if len(older) != 1 || !older["15.0"] {
+		t.Errorf("Expected only 15.0 past the 2 newest versions, got %v", older)
+	}
+
+	if older := beyondRetention([]string{"1.0"}, 3, compareVersions); len(older) != 0 {
+		t.Errorf("Expected nothing past the window, got %v", older)
+	}
+}
+
+func TestRetainedXcodeArchives(t *testing.T) {
+	dir := setupTestDir(t)
+	defer os.RemoveAll(dir)
+
+	archive := func(day, name, app, created string) {
+		createTestFile(t, dir, filepath.Join(day, name+".xcarchive", "Info.plist"),
+			`<plist version="1.0"><dict><key>CreationDate</key><date>`+created+`</date><key>Name</key><string>`+app+`</string></dict></plist>`)
+	}
+	archive("2024-01-01", "App 1", "App", "2024-01-01T10:00:00Z")
+	archive("2024-02-01", "App 2", "App", "2024-02-01T10:00:00Z")
+	archive("2024-03-01", "App 3", "App", "2024-03-01T10:00:00Z")
+	archive("2024-01-01", "Other 1", "Other", "2024-01-01T12:00:00Z")
+
+	targets := retainedXcodeArchives(dir, 2, config.Moderate)
+	if len(targets) != 1 {
+		t.Fatalf("Expected the oldest App archive only, got %v", targets)
+	}
+	if targets[0].Path != filepath.Join(dir, "2024-01-01", "App 1.xcarchive") {
+		t.Errorf("Unexpected target %s", targets[0].Path)
+	}
+	if targets[0].Description != "Xcode archive of App from 2024-01-01, older than the 2 kept" {
+		t.Errorf("Unexpected description %q", targets[0].Description)
+	}
+}
+
+func TestXcodeArchivesRetention(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	if _, ok := xcodeArchivesRetention(cfg); ok {
+		t.Error("Expected no retention by default")
+	}
+
+	// Set under mobile, as documented, it applies to the System cleaner too
+	keep := 2
+	cfg.Overrides[config.DomainMobile.Key()] = map[string]config.Override{"xcode_archives": {Keep: &keep}}
+	if got, ok := xcodeArchivesRetention(cfg); !ok || got != 2 {
+		t.Errorf("Expected to keep 2 archives, got %d (%v)", got, ok)
+	}
+
+	cfg = config.NewDefaultConfig()
+	cfg.Overrides[config.DomainSystem.Key()] = map[string]config.Override{"xcode_archives": {Keep: &keep}}
+	if got, ok := xcodeArchivesRetention(cfg); !ok || got != 2 {
+		t.Errorf("Expected the retention set under system, got %d (%v)", got, ok)
+	}
+}
+
+func TestRetainedSimulators(t *testing.T) {
+	dir := setupTestDir(t)
+	defer os.RemoveAll(dir)
+
+	device := func(udid, name, runtime string) {
+		createTestFile(t, dir, filepath.Join(udid, "device.plist"),
+			`<plist version="1.0"><dict><key>name</key><string>`+name+`</string><key>runtime</key><string>com.apple.CoreSimulator.SimRuntime.`+runtime+`</string></dict></plist>`)
+	}
+	device("A", "iPhone 15", "iOS-17-2")
+	device("B", "iPhone 14", "iOS-16-4")
+	device("C", "iPhone 13", "iOS-15-0")
+	device("D", "iPad", "iOS-15-0")
+	device("E", "Apple Watch", "watchOS-9-0")
+	createTestFile(t, dir, "F/data/file", "no device.plist")
+
+	targets := retainedSimulators(dir, 2)
+	if len(targets) != 2 {
+		t.Fatalf("Expected the 2 iOS 15.0 simulators, got %v", targets)
+	}
+	for _, target := range targets {
+		if !strings.HasPrefix(target.Description, "iOS 15.0 simulator") {
+			t.Errorf("Unexpected target %q", target.Description)
+		}
+	}
+}
+
+func TestRetainedVagrantBoxes(t *testing.T) {
+	dir := setupTestDir(t)
+	defer os.RemoveAll(dir)
+
+	for _, version := range []string{"20231010.0.0", "20240101.0.0", "20240301.0.0", "20240401.0.0"} {
+		createTestFile(t, dir, filepath.Join("ubuntu-VAGRANTSLASH-jammy64", version, "virtualbox", "box.vmdk"), "disk")
+	}
+	createTestFile(t, dir, "debian-VAGRANTSLASH-bookworm64/12.0.0/virtualbox/box.vmdk", "disk")
+
+	targets := retainedVagrantBoxes(dir, 3)
+	if len(targets) != 1 {
+		t.Fatalf("Expected one box version past the 3 kept, got %v", targets)
+	}
+	if targets[0].Description != "Vagrant box ubuntu/jammy64 20231010.0.0, older than the 3 versions kept" {
+		t.Errorf("Unexpected description %q", targets[0].Description)
+	}
+}
+
+func TestMobileCleaner_SimulatorRetention(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	devices := filepath.Join(home, "Library", "Developer", "CoreSimulator", "Devices")
+	createTestFile(t, devices, "A/device.plist",
+		`<plist version="1.0"><dict><key>runtime</key><string>com.apple.CoreSimulator.SimRuntime.iOS-17-2</string></dict></plist>`)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Home = home
+	keep := 1
+	cfg.Overrides["mobile"] = map[string]config.Override{"simulator_devices": {Keep: &keep}}
+
+	m, err := NewMobileCleaner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets, err := m.Scan(t.Context(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range targets {
+		if target.Category == "simulator_devices" {
+			t.Errorf("Expected the only iOS version to be kept, got %s", target.Path)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// majorMinor returns the major.minor part of the version a name starts
+// with, e.g. "4.3" for "4.3-arm64" or "1.10" for "1.10.0+0.aarch64"
+func majorMinor(name string) string {
+	end := 0
+	for end < len(name) && (name[end] == '.' || (name[end] >= '0' && name[end] <= '9')) {
+		end++
+	}
+	parts := strings.Split(strings.Trim(name[:end], "."), ".")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// installedVersions returns the major.minor versions named by the entries
+// of dirs, after prefix: R framework versions, Homebrew kegs, Julia apps
+func installedVersions(dirs []string, prefix string) map[string]bool {
+	versions := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), prefix)
+			if !ok {
+				continue
+			}
+			if version := majorMinor(name); version != "" {
+				versions[version] = true
+			}
+		}
+	}
+	return versions
+}
+
+// rInstalled returns the R versions installed from CRAN or Homebrew
+func rInstalled() map[string]bool {
+	return installedVersions([]string{
+		"/Library/Frameworks/R.framework/Versions",
+		"/opt/homebrew/Cellar/r",
+		"/usr/local/Cellar/r",
+	}, "")
+}
+
+// rLibraryTargets returns the user package libraries of R versions that are
+// no longer installed, found in ~/Library/R/<arch>/<version>/library or
+// ~/Library/R/<version>/library. Nothing is offered when no R installation
+// is found, since the libraries in use can't be told apart.
+func rLibraryTargets(home string, installed map[string]bool) []CleanTarget {
+	targets := []CleanTarget{}
+	if len(installed) == 0 {
+		return targets
+	}
+
+	libraries, _ := filepath.Glob(filepath.Join(home, "Library", "R", "*", "library"))
+	arch, _ := filepath.Glob(filepath.Join(home, "Library", "R", "*", "*", "library"))
+	for _, library := range append(libraries, arch...) {
+		version := majorMinor(filepath.Base(filepath.Dir(library)))
+		if version == "" || installed[version] {
+			continue
+		}
+		size, _ := utils.GetDirSize(library)
+		if size == 0 {
+			continue
+		}
+		targets = append(targets, CleanTarget{
+			Path:        library,
+			Category:    "r_stale_library",
+			Description: "R " + version + " packages (R " + version + " is no longer installed)",
+			SizeBytes:   size,
+			Safety:      config.Moderate,
+		})
+	}
+
+	return targets
+}
+
+// renvCache returns the renv package cache, shared by the R projects that
+// use renv
+func renvCache(home string) string {
+	if root := os.Getenv("RENV_PATHS_ROOT"); root != "" {
+		return filepath.Join(root, "cache")
+	}
+	// The user cache folder of R, from tools::R_user_dir()
+	layout := platform.Current()
+	switch layout {
+	case platform.MacOSLayout:
+		return filepath.Join(layout.ToolCacheDir(home), "org.R-project.R", "R", "renv", "cache")
+	case platform.WindowsLayout:
+		return filepath.Join(layout.ToolCacheDir(home), "R", "cache", "R", "renv", "cache")
+	default:
+		return filepath.Join(layout.ToolCacheDir(home), "R", "renv", "cache")
+	}
+}
+
+// juliaDepot returns the Julia depot: the first entry of JULIA_DEPOT_PATH,
+// or ~/.julia
+func juliaDepot(home string) string {
+	if depots := filepath.SplitList(os.Getenv("JULIA_DEPOT_PATH")); len(depots) > 0 && depots[0] != "" {
+		return depots[0]
+	}
+	return filepath.Join(home, ".julia")
+}
+
+// juliaHomebrewDirs are the folders of the Julia versions installed with
+// Homebrew, as a formula or a cask
+var juliaHomebrewDirs = []string{
+	"/opt/homebrew/Cellar/julia",
+	"/usr/local/Cellar/julia",
+	"/opt/homebrew/Caskroom/julia",
+	"/usr/local/Caskroom/julia",
+}
+
+// juliaInstalled returns the Julia versions installed with juliaup, as
+// apps, with Homebrew or from the tarballs unpacked in /opt or /usr/local,
+// and the version of the julia found on PATH, which covers Linux packages
+func juliaInstalled(depot string, runner CommandRunner) map[string]bool {
+	versions := installedVersions([]string{filepath.Join(depot, "juliaup")}, "julia-")
+	for _, found := range []map[string]bool{
+		installedVersions([]string{"/Applications"}, "Julia-"),
+		installedVersions(juliaHomebrewDirs, ""),
+		installedVersions([]string{"/opt", "/usr/local"}, "julia-"),
+	} {
+		for version := range found {
+			versions[version] = true
+		}
+	}
+
+	// julia --version prints "julia version 1.10.4"
+	if output, err := runner.Output("julia", "--version"); err == nil {
+		fields := strings.Fields(string(output))
+		if len(fields) > 0 {
+			if version := majorMinor(fields[len(fields)-1]); version != "" {
+				versions[version] = true
+			}
+		}
+	}
+	return versions
+}
+
+// juliaDepotTargets returns the packages and artifacts of a Julia depot
+// (Moderate: projects download them again), and the precompiled caches of
+// Julia versions no longer installed (Safe: nothing can load them). The
+// caches are all kept when no Julia installation is found.
+func juliaDepotTargets(depot string, installed map[string]bool) []CleanTarget {
+	targets := []CleanTarget{}
+
+	for _, dir := range []struct{ name, category, description string }{
+		{"packages", "julia_packages", "Julia packages"},
+		{"artifacts", "julia_artifacts", "Julia artifacts (binary dependencies)"},
+	} {
+		path := filepath.Join(depot, dir.name)
+		if size, _ := utils.GetDirSize(path); size > 0 {
+			targets = append(targets, CleanTarget{
+				Path:        path,
+				Category:    dir.category,
+				Description: dir.description,
+				SizeBytes:   size,
+				Safety:      config.Moderate,
+			})
+		}
+	}
+
+	if len(installed) == 0 {
+		return targets
+	}
+	compiled, _ := filepath.Glob(filepath.Join(depot, "compiled", "v*"))
+	sort.Strings(compiled)
+	for _, path := range compiled {
+		version := majorMinor(strings.TrimPrefix(filepath.Base(path), "v"))
+		if version == "" || installed[version] {
+			continue
+		}
+		if size, _ := utils.GetDirSize(path); size > 0 {
+			targets = append(targets, CleanTarget{
+				Path:        path,
+				Category:    "julia_compiled",
+				Description: "Julia " + version + " precompiled packages (Julia " + version + " is no longer installed)",
+				SizeBytes:   size,
+				Safety:      config.Safe,
+			})
+		}
+	}
+
+	return targets
+}
@@ -0,0 +1,102 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestMajorMinor(t *testing.T) {
+	tests := map[string]string{
+		"4.3-arm64":                     "4.3",
+		"4.2":                           "4.2",
+		"4.3.2_1":                       "4.3",
+		"1.10.0+0.aarch64.apple.darwin": "1.10",
+		"1.9.app":                       "1.9",
+		"Current":                       "",
+		"arm64":                         "",
+		"4":                             "",
+	}
+	for name, expected := range tests {
+		if got := majorMinor(name); got != expected {
+			t.Errorf("majorMinor(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func TestRLibraryTargets(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	createTestFile(t, home, "Library/R/4.2/library/dplyr/DESCRIPTION", "Package: dplyr")
+	createTestFile(t, home, "Library/R/arm64/4.3/library/dplyr/DESCRIPTION", "Package: dplyr")
+	createTestFile(t, home, "Library/R/arm64/4.4/library/ggplot2/DESCRIPTION", "Package: ggplot2")
+
+	targets := rLibraryTargets(home, map[string]bool{"4.4": true})
+	if len(targets) != 2 {
+		t.Fatalf("Expected the 4.2 and 4.3 libraries, got %+v", targets)
+	}
+	stale := map[string]bool{
+		filepath.Join(home, "Library/R/4.2/library"):       true,
+		filepath.Join(home, "Library/R/arm64/4.3/library"): true,
+	}
+	for _, target := range targets {
+		if !stale[target.Path] || target.Category != "r_stale_library" || target.Safety != config.Moderate {
+			t.Errorf("Unexpected target %+v", target)
+		}
+	}
+
+	// Without any R installation found, no library is known to be stale
+	if targets := rLibraryTargets(home, map[string]bool{}); len(targets) != 0 {
+		t.Errorf("Expected no target, got %+v", targets)
+	}
+}
+
+func TestJuliaDepotTargets(t *testing.T) {
+	depot := setupTestDir(t)
+	defer os.RemoveAll(depot)
+
+	createTestFile(t, depot, "packages/DataFrames/abc/src/DataFrames.jl", "module DataFrames end")
+	createTestFile(t, depot, "artifacts/0123/lib/libopenblas.dylib", "library")
+	createTestFile(t, depot, "compiled/v1.9/DataFrames/abc.ji", "cache")
+	createTestFile(t, depot, "compiled/v1.10/DataFrames/abc.ji", "cache")
+	createTestFile(t, depot, "juliaup/julia-1.10.0+0.aarch64.apple.darwin14/bin/julia", "binary")
+
+	targets := juliaDepotTargets(depot, juliaInstalled(depot, &RecordingRunner{}))
+
+	found := make(map[string]CleanTarget)
+	for _, target := range targets {
+		found[target.Category] = target
+	}
+	if len(targets) != 3 {
+		t.Fatalf("Expected packages, artifacts and the 1.9 cache, got %+v", targets)
+	}
+	if found["julia_packages"].Safety != config.Moderate || found["julia_artifacts"].Safety != config.Moderate {
+		t.Errorf("Expected packages and artifacts as Moderate, got %+v", targets)
+	}
+	if compiled := found["julia_compiled"]; compiled.Path != filepath.Join(depot, "compiled", "v1.9") || compiled.Safety != config.Safe {
+		t.Errorf("Expected the cache of Julia 1.9 only, got %+v", compiled)
+	}
+}
+
+func TestJuliaInstalled_Path(t *testing.T) {
+	depot := setupTestDir(t)
+	defer os.RemoveAll(depot)
+
+	// A Linux package or Homebrew julia, found on PATH only
+	runner := &RecordingRunner{Outputs: map[string]string{"julia --version": "julia version 1.9.4\n"}}
+	if installed := juliaInstalled(depot, runner); !installed["1.9"] {
+		t.Errorf("Expected Julia 1.9 from PATH, got %v", installed)
+	}
+
+	// The cache of the version on PATH is kept
+	createTestFile(t, depot, "compiled/v1.9/DataFrames/abc.ji", "cache")
+	createTestFile(t, depot, "compiled/v1.8/DataFrames/abc.ji", "cache")
+	for _, target := range juliaDepotTargets(depot, juliaInstalled(depot, runner)) {
+		if target.Path == filepath.Join(depot, "compiled", "v1.9") {
+			t.Errorf("Expected the cache of Julia 1.9 to be kept, got %+v", target)
+		}
+	}
+}
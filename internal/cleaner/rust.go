@@ -0,0 +1,282 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// cargoUnit is a single compiled unit inside a Cargo profile directory,
+// identified by its .fingerprint/<crate>-<hash> entry.
+type cargoUnit struct {
+	name    string    // Fingerprint directory name (e.g. "serde-1a2b3c4d")
+	hash    string    // Metadata hash shared by all artifacts of the unit
+	rustc   string    // Compiler hash recorded in the fingerprint JSON
+	modTime time.Time // Most recent modification inside the fingerprint
+}
+
+// cargoSweep describes the stale artifacts found in one Cargo target directory
+type cargoSweep struct {
+	entries []string // Paths to remove
+	size    int64    // Total size of entries
+	units   int      // Number of stale units
+}
+
+// sweepCargoTarget finds build artifacts in a Cargo target directory that
+// cargo-sweep would remove: units not rebuilt since the cutoff, and units
+// built by a different compiler than the most recent build (i.e. from a
+// toolchain that is no longer in use). Recent artifacts are left alone so
+// incremental builds stay usable. With pinned set, the project pins an
+// installed toolchain that may not be the one of the most recent build, so
+// units are swept by age only.
+func sweepCargoTarget(targetDir string, cutoff time.Time, pinned bool) cargoSweep {
+	var sweep cargoSweep
+
+	for _, profileDir := range cargoProfileDirs(targetDir) {
+		units := readCargoUnits(filepath.Join(profileDir, ".fingerprint"))
+		if len(units) == 0 {
+			continue
+		}
+
+		// The compiler that produced the most recent build is considered current
+		var current cargoUnit
+		for _, u := range units {
+			if u.modTime.After(current.modTime) {
+				current = u
+			}
+		}
+
+		artifacts := cargoArtifactsByHash(profileDir)
+
+		for _, u := range units {
+			toolchainGone := !pinned && u.rustc != "" && current.rustc != "" && u.rustc != current.rustc
+			if !u.modTime.Before(cutoff) && !toolchainGone {
+				continue
+			}
+
+			sweep.units++
+			paths := []string{filepath.Join(profileDir, ".fingerprint", u.name)}
+
+			buildDir := filepath.Join(profileDir, "build", u.name)
+			if utils.PathExists(buildDir) {
+				paths = append(paths, buildDir)
+			}
+			paths = append(paths, artifacts[u.hash]...)
+
+			for _, p := range paths {
+				size, _ := utils.GetDirSize(p)
+				sweep.size += size
+				sweep.entries = append(sweep.entries, p)
+			}
+		}
+
+		// Incremental compilation sessions are swept by age only
+		incrementalDir := filepath.Join(profileDir, "incremental")
+		entries, err := os.ReadDir(incrementalDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(incrementalDir, entry.Name())
+			if latestModTime(path).Before(cutoff) {
+				size, _ := utils.GetDirSize(path)
+				sweep.size += size
+				sweep.entries = append(sweep.entries, path)
+			}
+		}
+	}
+
+	return sweep
+}
+
+// describe returns a human-readable description of the sweep
+func (s cargoSweep) describe(days int) string {
+	return fmt.Sprintf("Rust stale build artifacts (%d units older than %d days or from old toolchains)", s.units, days)
+}
+
+// rustToolchainFiles are the files rustup reads the toolchain of a project
+// from, by order of precedence
+var rustToolchainFiles = []string{"rust-toolchain.toml", "rust-toolchain"}
+
+// pinnedToolchain returns the toolchain channel pinned for dir by a toolchain
+// file in it or a parent folder, as rustup looks them up. The channel is ""
+// for toolchain files that only set a path.
+func pinnedToolchain(dir string) (string, bool) {
+	for {
+		for _, name := range rustToolchainFiles {
+			if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+				return parseToolchainFile(string(data)), true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// parseToolchainFile reads the channel of a toolchain file: the channel key
+// of its [toolchain] table, or the whole file in the legacy format
+func parseToolchainFile(data string) string {
+	if !strings.Contains(data, "[") {
+		return strings.TrimSpace(data)
+	}
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "channel" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// toolchainInstalled checks if rustup has a toolchain of channel, named
+// <channel>-<host triple> in its toolchains folder. Toolchains set by path
+// (channel "") are taken as installed.
+func toolchainInstalled(rustupHome, channel string) bool {
+	if channel == "" {
+		return true
+	}
+	entries, _ := os.ReadDir(filepath.Join(rustupHome, "toolchains"))
+	for _, entry := range entries {
+		if entry.Name() == channel || strings.HasPrefix(entry.Name(), channel+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// pinsInstalledToolchain reports whether a project pins a toolchain that
+// rustup has installed
+func pinsInstalledToolchain(projectDir, home string) bool {
+	channel, pinned := pinnedToolchain(projectDir)
+	return pinned && toolchainInstalled(rustupHome(home), channel)
+}
+
+// rustupHome returns the folder rustup keeps its toolchains in
+func rustupHome(home string) string {
+	if dir := os.Getenv("RUSTUP_HOME"); dir != "" {
+		return dir
+	}
+	return filepath.Join(home, ".rustup")
+}
+
+// cargoProfileDirs returns the profile directories (debug, release, and
+// per-target-triple profiles) inside a Cargo target directory
+func cargoProfileDirs(targetDir string) []string {
+	var dirs []string
+
+	candidates, _ := filepath.Glob(filepath.Join(targetDir, "*", ".fingerprint"))
+	nested, _ := filepath.Glob(filepath.Join(targetDir, "*", "*", ".fingerprint"))
+	candidates = append(candidates, nested...)
+
+	for _, fingerprint := range candidates {
+		dirs = append(dirs, filepath.Dir(fingerprint))
+	}
+
+	return dirs
+}
+
+// readCargoUnits reads every unit recorded in a .fingerprint directory
+func readCargoUnits(fingerprintDir string) []cargoUnit {
+	entries, err := os.ReadDir(fingerprintDir)
+	if err != nil {
+		return nil
+	}
+
+	units := make([]cargoUnit, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		idx := strings.LastIndex(name, "-")
+		if idx < 0 {
+			continue
+		}
+
+		dir := filepath.Join(fingerprintDir, name)
+		units = append(units, cargoUnit{
+			name:    name,
+			hash:    name[idx+1:],
+			rustc:   readFingerprintRustc(dir),
+			modTime: latestModTime(dir),
+		})
+	}
+
+	return units
+}
+
+// readFingerprintRustc extracts the compiler hash from the JSON fingerprint
+// files of a unit, returning "" if none can be read
+func readFingerprintRustc(dir string) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, match := range matches {
+		data, err := os.ReadFile(match)
+		if err != nil {
+			continue
+		}
+
+		var fingerprint struct {
+			Rustc json.Number `json:"rustc"`
+		}
+		if err := json.Unmarshal(data, &fingerprint); err == nil && fingerprint.Rustc != "" {
+			return fingerprint.Rustc.String()
+		}
+	}
+
+	return ""
+}
+
+// cargoArtifactsByHash maps unit hashes to the files in deps/ that belong to
+// them (e.g. libserde-<hash>.rlib, serde-<hash>.d)
+func cargoArtifactsByHash(profileDir string) map[string][]string {
+	artifacts := make(map[string][]string)
+	depsDir := filepath.Join(profileDir, "deps")
+
+	entries, err := os.ReadDir(depsDir)
+	if err != nil {
+		return artifacts
+	}
+
+	for _, entry := range entries {
+		stem := entry.Name()
+		if idx := strings.Index(stem, "."); idx >= 0 {
+			stem = stem[:idx]
+		}
+		idx := strings.LastIndex(stem, "-")
+		if idx < 0 {
+			continue
+		}
+		hash := stem[idx+1:]
+		artifacts[hash] = append(artifacts[hash], filepath.Join(depsDir, entry.Name()))
+	}
+
+	return artifacts
+}
+
+// latestModTime returns the most recent modification time of a path or
+// anything inside it
+func latestModTime(path string) time.Time {
+	var latest time.Time
+
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		utils.Throttle()
+		if err != nil {
+			return nil
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+
+	return latest
+}
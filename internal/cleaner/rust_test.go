@@ -0,0 +1,172 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setOldTimes sets the mtime of every file under path to the given time
+func setOldTimes(t *testing.T, path string, when time.Time) {
+	t.Helper()
+	filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if err := os.Chtimes(p, when, when); err != nil {
+			t.Fatalf("Failed to set times on %s: %v", p, err)
+		}
+		return nil
+	})
+}
+
+func TestSweepCargoTarget_OldUnits(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	target := filepath.Join(tmpDir, "target")
+	createTestFile(t, target, "debug/.fingerprint/old-aaaa/lib-old.json", `{"rustc": 111}`)
+	createTestFile(t, target, "debug/.fingerprint/new-bbbb/lib-new.json", `{"rustc": 111}`)
+	createTestFile(t, target, "debug/deps/libold-aaaa.rlib", "old rlib")
+	createTestFile(t, target, "debug/deps/old-aaaa.d", "old deps")
+	createTestFile(t, target, "debug/deps/libnew-bbbb.rlib", "new rlib")
+	createTestFile(t, target, "debug/build/old-aaaa/output", "build output")
+
+	old := time.Now().AddDate(0, 0, -60)
+	setOldTimes(t, filepath.Join(target, "debug/.fingerprint/old-aaaa"), old)
+
+	sweep := sweepCargoTarget(target, time.Now().AddDate(0, 0, -30), false)
+
+	if sweep.units != 1 {
+		t.Fatalf("Expected 1 stale unit, got %d", sweep.units)
+	}
+
+	expected := map[string]bool{
+		filepath.Join(target, "debug/.fingerprint/old-aaaa"): true,
+		filepath.Join(target, "debug/build/old-aaaa"):        true,
+		filepath.Join(target, "debug/deps/libold-aaaa.rlib"): true,
+		filepath.Join(target, "debug/deps/old-aaaa.d"):       true,
+	}
+	if len(sweep.entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d: %v", len(expected), len(sweep.entries), sweep.entries)
+	}
+	for _, entry := range sweep.entries {
+		if !expected[entry] {
+			t.Errorf("Unexpected entry %s", entry)
+		}
+	}
+	if sweep.size == 0 {
+		t.Error("Expected non-zero sweep size")
+	}
+}
+
+func TestSweepCargoTarget_OldToolchain(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	target := filepath.Join(tmpDir, "target")
+	createTestFile(t, target, "release/.fingerprint/stale-cccc/lib-stale.json", `{"rustc": 1}`)
+	createTestFile(t, target, "release/.fingerprint/fresh-dddd/lib-fresh.json", `{"rustc": 2}`)
+
+	// The fresh unit is the most recent build, so its compiler is current
+	setOldTimes(t, filepath.Join(target, "release/.fingerprint/stale-cccc"), time.Now().Add(-time.Hour))
+
+	sweep := sweepCargoTarget(target, time.Now().AddDate(0, 0, -30), false)
+
+	if sweep.units != 1 {
+		t.Fatalf("Expected 1 unit from old toolchain, got %d", sweep.units)
+	}
+	if sweep.entries[0] != filepath.Join(target, "release/.fingerprint/stale-cccc") {
+		t.Errorf("Expected stale-cccc fingerprint, got %s", sweep.entries[0])
+	}
+}
+
+func TestPinsInstalledToolchain(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	t.Setenv("RUSTUP_HOME", "")
+	createTestFile(t, home, ".rustup/toolchains/1.75.0-aarch64-apple-darwin/bin/rustc", "rustc")
+	projects := filepath.Join(home, "Projects")
+	createTestFile(t, projects, "pinned/rust-toolchain.toml", "[toolchain]\nchannel = \"1.75.0\"\ncomponents = [\"clippy\"]\n")
+	createTestFile(t, projects, "legacy/rust-toolchain", "1.75.0\n")
+	createTestFile(t, projects, "workspace/rust-toolchain.toml", "[toolchain]\nchannel = \"nightly-2024-01-01\"\n")
+	createTestDir(t, projects, "workspace/crates/core", nil)
+	createTestDir(t, projects, "unpinned", nil)
+
+	tests := map[string]bool{
+		"pinned":                true,
+		"legacy":                true,
+		"workspace/crates/core": false, // Pinned by the workspace, but not installed
+		"unpinned":              false,
+	}
+	for dir, expected := range tests {
+		if got := pinsInstalledToolchain(filepath.Join(projects, dir), home); got != expected {
+			t.Errorf("pinsInstalledToolchain(%s) = %v, expected %v", dir, got, expected)
+		}
+	}
+}
+
+func TestSweepCargoTarget_PinnedToolchain(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	target := filepath.Join(tmpDir, "target")
+	createTestFile(t, target, "release/.fingerprint/pinned-cccc/lib-pinned.json", `{"rustc": 1}`)
+	createTestFile(t, target, "release/.fingerprint/nightly-dddd/lib-nightly.json", `{"rustc": 2}`)
+	setOldTimes(t, filepath.Join(target, "release/.fingerprint/pinned-cccc"), time.Now().Add(-time.Hour))
+
+	// The pinned toolchain may not be the one of the most recent build
+	if sweep := sweepCargoTarget(target, time.Now().AddDate(0, 0, -30), true); len(sweep.entries) != 0 {
+		t.Errorf("Expected recent units kept in a pinned project, got %v", sweep.entries)
+	}
+}
+
+func TestSweepCargoTarget_Incremental(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	target := filepath.Join(tmpDir, "target")
+	createTestFile(t, target, "debug/.fingerprint/app-eeee/bin-app.json", `{"rustc": 5}`)
+	createTestFile(t, target, "debug/incremental/app-old/s-1/query-cache.bin", "old")
+	createTestFile(t, target, "debug/incremental/app-new/s-2/query-cache.bin", "new")
+
+	setOldTimes(t, filepath.Join(target, "debug/incremental/app-old"), time.Now().AddDate(0, 0, -90))
+
+	sweep := sweepCargoTarget(target, time.Now().AddDate(0, 0, -30), false)
+
+	if len(sweep.entries) != 1 || sweep.entries[0] != filepath.Join(target, "debug/incremental/app-old") {
+		t.Errorf("Expected only the old incremental session, got %v", sweep.entries)
+	}
+}
+
+func TestSweepCargoTarget_NotCargo(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	sweep := sweepCargoTarget(tmpDir, time.Now(), false)
+	if len(sweep.entries) != 0 {
+		t.Errorf("Expected no entries for a non-Cargo directory, got %v", sweep.entries)
+	}
+}
+
+func TestRemoveTarget_Entries(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	keep := createTestFile(t, tmpDir, "keep.txt", "keep")
+	drop := createTestFile(t, tmpDir, "drop.txt", "drop")
+
+	_, err := removeTarget(CleanTarget{Path: tmpDir, Entries: []string{drop}}, nil, nil)
+	if err != nil {
+		t.Fatalf("removeTarget() returned error: %v", err)
+	}
+
+	if _, err := os.Stat(drop); !os.IsNotExist(err) {
+		t.Error("Entry was not removed")
+	}
+	if _, err := os.Stat(keep); err != nil {
+		t.Error("File outside Entries was removed")
+	}
+}
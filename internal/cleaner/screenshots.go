@@ -0,0 +1,201 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// ScreenshotsCleaner finds screenshots and screen recordings piling up on
+// the Desktop, or wherever screencapture saves them, after bug reports and
+// demos. They are the user's own files, so both categories are off by
+// default and have to be enabled in the config file. Files newer than
+// cfg.ScreenshotMaxAgeDays are left alone, and with cfg.ScreenshotArchiveDir
+// set they are moved to a dated folder instead of deleted.
+type ScreenshotsCleaner struct {
+	runner CommandRunner // Reads the screencapture location, ExecRunner if nil
+}
+
+// screenRecordingMinSize is the size from which a screen recording is
+// offered: short clips take little space
+const screenRecordingMinSize = 50 * 1000 * 1000
+
+// Name prefixes of the files macOS (in English and French) and CleanShot
+// save, and the extensions of images and videos among them
+var (
+	screenshotPrefixes      = []string{"Screenshot ", "Screen Shot ", "Capture d", "CleanShot "}
+	screenRecordingPrefixes = []string{"Screen Recording ", "Enregistrement de l", "CleanShot "}
+	screenshotExts          = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".heic": true, ".tiff": true}
+	screenRecordingExts     = map[string]bool{".mov": true, ".mp4": true}
+)
+
+// NewScreenshotsCleaner creates a new ScreenshotsCleaner
+func NewScreenshotsCleaner() Cleaner {
+	return &ScreenshotsCleaner{}
+}
+
+// SetRunner replaces the runner of the cleaner's external commands
+func (s *ScreenshotsCleaner) SetRunner(runner CommandRunner) {
+	s.runner = runner
+}
+
+// commands returns the runner of the cleaner's external commands
+func (s *ScreenshotsCleaner) commands() CommandRunner {
+	if s.runner == nil {
+		return ExecRunner{}
+	}
+	return s.runner
+}
+
+func (s *ScreenshotsCleaner) Name() string {
+	return "Screenshots"
+}
+
+func (s *ScreenshotsCleaner) Domain() config.Domain {
+	return config.DomainSystem
+}
+
+func (s *ScreenshotsCleaner) Detect(ctx context.Context) (bool, error) {
+	det := detector.FromContext(ctx)
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, err
+	}
+	return det.HasPath(filepath.Join(home, "Desktop")), nil
+}
+
+func (s *ScreenshotsCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	if !cfg.OptedIn(config.DomainSystem, "screenshots") && !cfg.OptedIn(config.DomainSystem, "screen_recordings") {
+		return []CleanTarget{}, nil
+	}
+
+	home, err := cfg.HomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	archiveDir := ""
+	if cfg.ScreenshotArchiveDir != "" {
+		archiveDir = filepath.Join(expandUserPath(home, cfg.ScreenshotArchiveDir), now.Format("2006-01-02"))
+	}
+
+	return scanScreenshots(cfg, s.screenshotDirs(home), archiveDir, now), nil
+}
+
+func (s *ScreenshotsCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanTargets(ctx, targets, dryRun)
+}
+
+// screenshotDirs returns the Desktop and the folder screenshots are saved
+// to, if it was changed (defaults write com.apple.screencapture location)
+func (s *ScreenshotsCleaner) screenshotDirs(home string) []string {
+	desktop := filepath.Join(home, "Desktop")
+	dirs := []string{desktop}
+
+	output, err := s.commands().Output("defaults", "read", "com.apple.screencapture", "location")
+	if err != nil {
+		return dirs
+	}
+	location := filepath.Clean(expandUserPath(home, strings.TrimSpace(string(output))))
+	if filepath.IsAbs(location) && location != desktop {
+		dirs = append(dirs, location)
+	}
+	return dirs
+}
+
+// expandUserPath expands a leading ~ to home
+func expandUserPath(home, path string) string {
+	if path == "~" {
+		return home
+	}
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
+
+// scanScreenshots returns, for each folder, one target holding the
+// screenshots older than cfg.ScreenshotMaxAgeDays, and one target per screen
+// recording that old and at least screenRecordingMinSize large. Targets are
+// moved into archiveDir when it is set.
+func scanScreenshots(cfg *config.Config, dirs []string, archiveDir string, now time.Time) []CleanTarget {
+	targets := []CleanTarget{}
+	cutoff := now.AddDate(0, 0, -cfg.ScreenshotMaxAgeDays)
+	wantScreenshots := cfg.OptedIn(config.DomainSystem, "screenshots") && cfg.Allows(config.DomainSystem, "screenshots", config.Moderate)
+	wantRecordings := cfg.OptedIn(config.DomainSystem, "screen_recordings") && cfg.Allows(config.DomainSystem, "screen_recordings", config.Moderate)
+
+	action := ActionDelete
+	suffix := ""
+	if archiveDir != "" {
+		action = ActionArchive
+		suffix = " (moved to " + archiveDir + ")"
+	}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		screenshots := []string{}
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() || utils.IsDatalessEntry(entry) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || info.ModTime().After(cutoff) {
+				continue
+			}
+
+			name := entry.Name()
+			ext := strings.ToLower(filepath.Ext(name))
+			path := filepath.Join(dir, name)
+			switch {
+			case wantScreenshots && screenshotExts[ext] && hasAnyPrefix(name, screenshotPrefixes):
+				screenshots = append(screenshots, path)
+			case wantRecordings && screenRecordingExts[ext] && hasAnyPrefix(name, screenRecordingPrefixes) && info.Size() >= screenRecordingMinSize:
+				days := int(now.Sub(info.ModTime()).Hours() / 24)
+				targets = append(targets, CleanTarget{
+					Path:        path,
+					Category:    "screen_recordings",
+					Description: fmt.Sprintf("Screen recording from %d days ago%s", days, suffix),
+					SizeBytes:   info.Size(),
+					Safety:      config.Moderate,
+					Action:      action,
+					ArchiveDir:  archiveDir,
+				})
+			}
+		}
+
+		description := fmt.Sprintf("%d screenshots older than %d days%s", len(screenshots), cfg.ScreenshotMaxAgeDays, suffix)
+		if target, ok := entriesTarget(dir, screenshots, "screenshots", description, config.Moderate); ok {
+			target.Action = action
+			target.ArchiveDir = archiveDir
+			targets = append(targets, target)
+		}
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool {
+		return targets[i].SizeBytes > targets[j].SizeBytes
+	})
+	return targets
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+// screenshotsConfig returns a config with the screenshot categories enabled
+func screenshotsConfig(categories ...string) *config.Config {
+	cfg := config.NewDefaultConfig()
+	enabled := true
+	cfg.Overrides = map[string]map[string]config.Override{"system": {}}
+	for _, category := range categories {
+		cfg.Overrides["system"][category] = config.Override{Enabled: &enabled}
+	}
+	return cfg
+}
+
+func TestScanScreenshots(t *testing.T) {
+	desktop := setupTestDir(t)
+	defer os.RemoveAll(desktop)
+
+	now := time.Now()
+	old := now.AddDate(0, 0, -40)
+	age := func(path string) {
+		t.Helper()
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	age(createTestFile(t, desktop, "Screenshot 2026-09-01 at 10.00.00.png", "png"))
+	age(createTestFile(t, desktop, "CleanShot 2026-09-02 at 11.00.00@2x.png", "png"))
+	age(createTestFile(t, desktop, "notes.png", "not a screenshot"))
+	createTestFile(t, desktop, "Screenshot 2026-10-15 at 09.00.00.png", "too recent")
+
+	recording := createTestFile(t, desktop, "Screen Recording 2026-09-03 at 12.00.00.mov", "")
+	if err := os.Truncate(recording, screenRecordingMinSize); err != nil {
+		t.Fatal(err)
+	}
+	age(recording)
+	age(createTestFile(t, desktop, "Screen Recording 2026-09-04 at 12.00.00.mov", "short clip"))
+
+	// Off by default
+	if targets := scanScreenshots(config.NewDefaultConfig(), []string{desktop}, "", now); len(targets) != 0 {
+		t.Fatalf("Expected no targets without opting in, got %v", targets)
+	}
+
+	targets := scanScreenshots(screenshotsConfig("screenshots", "screen_recordings"), []string{desktop}, "", now)
+	if len(targets) != 2 {
+		t.Fatalf("Expected a recording and a screenshots target, got %v", targets)
+	}
+	if targets[0].Path != recording || targets[0].Category != "screen_recordings" {
+		t.Errorf("Expected the large recording first, got %+v", targets[0])
+	}
+	screenshots := targets[1]
+	if screenshots.Path != desktop || len(screenshots.Entries) != 2 || screenshots.Action != ActionDelete {
+		t.Errorf("Expected the 2 old screenshots to be deleted, got %+v", screenshots)
+	}
+
+	// Only the categories opted into
+	targets = scanScreenshots(screenshotsConfig("screen_recordings"), []string{desktop}, "", now)
+	if len(targets) != 1 || targets[0].Category != "screen_recordings" {
+		t.Errorf("Expected only the recording, got %v", targets)
+	}
+
+	// Conservative runs skip Moderate targets
+	cfg := screenshotsConfig("screenshots", "screen_recordings")
+	cfg.CleanLevel = config.Conservative
+	if targets := scanScreenshots(cfg, []string{desktop}, "", now); len(targets) != 0 {
+		t.Errorf("Expected no targets in conservative mode, got %v", targets)
+	}
+}
+
+func TestScreenshots_Archive(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	desktop := filepath.Join(tmpDir, "Desktop")
+	archiveDir := filepath.Join(tmpDir, "Archive", "2026-10-16")
+	old := time.Now().AddDate(0, 0, -40)
+	for _, name := range []string{"Screenshot 1.png", "Screenshot 2.png"} {
+		if err := os.Chtimes(createTestFile(t, desktop, name, "png"), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Already archived today under the same name
+	createTestFile(t, archiveDir, "Screenshot 1.png", "earlier")
+
+	targets := scanScreenshots(screenshotsConfig("screenshots"), []string{desktop}, archiveDir, time.Now())
+	if len(targets) != 1 || targets[0].Action != ActionArchive || !strings.Contains(targets[0].Description, "moved to") {
+		t.Fatalf("Expected a target to archive, got %v", targets)
+	}
+
+	results, err := NewScreenshotsCleaner().Clean(context.Background(), targets, false)
+	if err != nil || !results[0].Success {
+		t.Fatalf("Archiving failed: %v %v", err, results)
+	}
+
+	for _, name := range []string{"Screenshot 1.png", "Screenshot 1 (2).png", "Screenshot 2.png"} {
+		if _, err := os.Stat(filepath.Join(archiveDir, name)); err != nil {
+			t.Errorf("Expected %s in the archive: %v", name, err)
+		}
+	}
+	if entries, _ := os.ReadDir(desktop); len(entries) != 0 {
+		t.Errorf("Expected the Desktop to be empty, got %v", entries)
+	}
+	moved, _ := os.Stat(filepath.Join(archiveDir, "Screenshot 2.png"))
+	if moved == nil || !moved.ModTime().Equal(old) {
+		t.Error("Archived files should keep their modification time")
+	}
+}
+
+func TestScreenshotDirs(t *testing.T) {
+	home := "/Users/me"
+	s := &ScreenshotsCleaner{}
+
+	s.SetRunner(&RecordingRunner{Outputs: map[string]string{
+		"defaults read com.apple.screencapture location": "~/Pictures/Screenshots\n",
+	}})
+	dirs := s.screenshotDirs(home)
+	if len(dirs) != 2 || dirs[1] != "/Users/me/Pictures/Screenshots" {
+		t.Errorf("Expected the Desktop and the custom location, got %v", dirs)
+	}
+
+	// Not set
+	s.SetRunner(&RecordingRunner{})
+	if dirs := s.screenshotDirs(home); len(dirs) != 1 || dirs[0] != "/Users/me/Desktop" {
+		t.Errorf("Expected the Desktop only, got %v", dirs)
+	}
+}
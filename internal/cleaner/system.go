@@ -4,16 +4,21 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/platform"
 	"github.com/0SansNom/epurer/pkg/utils"
 )
 
 // SystemCleaner handles system-level cleanup operations
 type SystemCleaner struct {
 	cleanerType string
+	runner      CommandRunner // Runs brew, plutil, action and xcode-select commands, ExecRunner if nil
 }
 
 // System cleaner types
@@ -27,6 +32,7 @@ const (
 	TypeXcode      = "xcode"
 	TypeLaunchpad  = "launchpad"
 	TypeIOSBackups = "ios_backups"
+	TypeMedia      = "media"
 )
 
 // Factory functions for each system cleaner type
@@ -67,6 +73,23 @@ func NewIOSBackupCleaner() Cleaner {
 	return &SystemCleaner{cleanerType: TypeIOSBackups}
 }
 
+func NewMediaCacheCleaner() Cleaner {
+	return &SystemCleaner{cleanerType: TypeMedia}
+}
+
+// SetRunner replaces the runner of the cleaner's external commands
+func (s *SystemCleaner) SetRunner(runner CommandRunner) {
+	s.runner = runner
+}
+
+// commands returns the runner of the cleaner's external commands
+func (s *SystemCleaner) commands() CommandRunner {
+	if s.runner == nil {
+		return ExecRunner{}
+	}
+	return s.runner
+}
+
 // Implement Cleaner interface
 
 func (s *SystemCleaner) Name() string {
@@ -86,9 +109,11 @@ func (s *SystemCleaner) Name() string {
 	case TypeXcode:
 		return "Xcode DerivedData"
 	case TypeLaunchpad:
-		return "Launchpad Database"
+		return "Launchpad & Dock"
 	case TypeIOSBackups:
 		return "iOS Backups"
+	case TypeMedia:
+		return "Mail & Media Caches"
 	default:
 		return "Unknown"
 	}
@@ -99,15 +124,19 @@ func (s *SystemCleaner) Domain() config.Domain {
 }
 
 func (s *SystemCleaner) Detect(ctx context.Context) (bool, error) {
+	det := detector.FromContext(ctx)
 	switch s.cleanerType {
 	case TypeHomebrew:
 		// Only applicable if Homebrew is installed
-		return utils.CommandExists("brew"), nil
+		return det.HasCommand("brew"), nil
 	case TypeXcode:
 		// Only applicable if Xcode is installed
-		return utils.PathExists("/Applications/Xcode.app"), nil
-	case TypeDNS, TypeTrash, TypeCache, TypeLogs, TypeTemp, TypeLaunchpad, TypeIOSBackups:
-		// Always applicable on macOS
+		return det.HasPath("/Applications/Xcode.app"), nil
+	case TypeDNS, TypeLaunchpad, TypeIOSBackups, TypeMedia:
+		// macOS databases and apps
+		return platform.IsMacOS(), nil
+	case TypeTrash, TypeCache, TypeLogs, TypeTemp:
+		// Always applicable
 		return true, nil
 	default:
 		return false, fmt.Errorf("unknown cleaner type: %s", s.cleanerType)
@@ -117,7 +146,7 @@ func (s *SystemCleaner) Detect(ctx context.Context) (bool, error) {
 func (s *SystemCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
 	switch s.cleanerType {
 	case TypeTrash:
-		return s.scanTrash()
+		return s.scanTrash(cfg)
 	case TypeCache:
 		return s.scanCaches(cfg)
 	case TypeLogs:
@@ -129,11 +158,13 @@ func (s *SystemCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 	case TypeHomebrew:
 		return s.scanHomebrew()
 	case TypeXcode:
-		return s.scanXcode()
+		return s.scanXcode(cfg)
 	case TypeLaunchpad:
-		return s.scanLaunchpad()
+		return s.scanLaunchpad(cfg)
 	case TypeIOSBackups:
 		return s.scanIOSBackups(cfg)
+	case TypeMedia:
+		return s.scanMedia(cfg)
 	default:
 		return nil, fmt.Errorf("unknown cleaner type: %s", s.cleanerType)
 	}
@@ -142,32 +173,38 @@ func (s *SystemCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTa
 func (s *SystemCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
 	results := make([]CleanResult, 0, len(targets))
 
+	// Dry runs record the cleanup commands instead of running them
+	runner := s.commands()
+	recorder := &RecordingRunner{Runner: runner}
+	if dryRun {
+		runner = recorder
+	}
+
 	for _, target := range targets {
 		var result CleanResult
 		result.Target = target
 
-		// Special handling for DNS cache (uses command)
-		if s.cleanerType == TypeDNS {
-			err := s.cleanDNSCache(dryRun)
-			result.Success = err == nil
-			result.Error = err
-			result.BytesFreed = 0 // DNS cache doesn't have measurable size
-		} else if s.cleanerType == TypeHomebrew {
-			// Homebrew uses its own cleanup command
-			err := s.cleanHomebrew(dryRun)
+		// Maintenance actions (DNS flush, Launchpad reset...) run commands
+		if target.Action == ActionRun {
+			result = runAction(runner, target, dryRun)
+		} else if target.Category == "homebrew_cache" {
+			// The whole Homebrew cache goes through its own cleanup command
+			err := cleanHomebrew(runner)
 			result.Success = err == nil
 			result.Error = err
 			result.BytesFreed = target.SizeBytes // Estimate
 		} else {
 			// Standard file/directory removal
-			err := utils.SafeRemove(target.Path, dryRun)
-			result.Success = err == nil
-			result.Error = err
-			if result.Success {
+			if !dryRun {
+				result = removeMeasured(ctx, target)
+			} else {
+				result.Success = true
 				result.BytesFreed = target.SizeBytes
 			}
 		}
 
+		result.Commands = recorder.Commands()
+
 		results = append(results, result)
 	}
 
@@ -176,20 +213,21 @@ func (s *SystemCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun
 
 // Private scan methods
 
-func (s *SystemCleaner) scanTrash() ([]CleanTarget, error) {
+func (s *SystemCleaner) scanTrash(cfg *config.Config) ([]CleanTarget, error) {
 	targets := []CleanTarget{}
-	home, err := os.UserHomeDir()
+	home, err := cfg.HomeDir()
 	if err != nil {
 		return nil, err
 	}
 
 	// User trash
-	trashPath := filepath.Join(home, ".Trash")
+	trashPath := platform.TrashDir(home)
 	if utils.PathExists(trashPath) {
 		size, _ := utils.GetDirSize(trashPath)
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        trashPath,
+				Category:    "trash",
 				Description: "User trash",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -198,15 +236,15 @@ func (s *SystemCleaner) scanTrash() ([]CleanTarget, error) {
 	}
 
 	// External volumes trash
-	volumesPattern := "/Volumes/*/.Trashes"
-	matches, err := filepath.Glob(volumesPattern)
-	if err == nil {
+	for _, volumesPattern := range platform.VolumeTrashPatterns() {
+		matches, _ := filepath.Glob(volumesPattern)
 		for _, match := range matches {
 			if utils.PathExists(match) {
 				size, _ := utils.GetDirSize(match)
 				if size > 0 {
 					targets = append(targets, CleanTarget{
 						Path:        match,
+						Category:    "external_trash",
 						Description: fmt.Sprintf("External volume trash: %s", filepath.Dir(match)),
 						SizeBytes:   size,
 						Safety:      config.Safe,
@@ -221,37 +259,29 @@ func (s *SystemCleaner) scanTrash() ([]CleanTarget, error) {
 
 func (s *SystemCleaner) scanCaches(cfg *config.Config) ([]CleanTarget, error) {
 	targets := []CleanTarget{}
-	home, err := os.UserHomeDir()
+	home, err := cfg.HomeDir()
 	if err != nil {
 		return nil, err
 	}
 
+	// With --older-than / --larger-than, only the matching app cache folders
+	// are removed instead of the whole directories
+	criteria := pruneCriteria(cfg)
+
 	// User caches (always safe)
-	userCachePath := filepath.Join(home, "Library", "Caches")
+	userCachePath := platform.CacheDir(home)
 	if utils.PathExists(userCachePath) {
-		size, _ := utils.GetDirSize(userCachePath)
-		if size > 0 {
-			targets = append(targets, CleanTarget{
-				Path:        userCachePath,
-				Description: "User caches",
-				SizeBytes:   size,
-				Safety:      config.Safe,
-			})
+		if target, ok := cacheTarget(userCachePath, 1, criteria, "user_caches", "User caches", config.Safe); ok {
+			targets = append(targets, target)
 		}
 	}
 
 	// System caches (moderate - requires sudo)
-	if cfg.CleanLevel.AllowsSafety(config.Moderate) {
-		systemCachePath := "/Library/Caches"
-		if utils.PathExists(systemCachePath) {
-			size, _ := utils.GetDirSize(systemCachePath)
-			if size > 0 {
-				targets = append(targets, CleanTarget{
-					Path:        systemCachePath,
-					Description: "System caches",
-					SizeBytes:   size,
-					Safety:      config.Moderate,
-				})
+	if cfg.Allows(config.DomainSystem, "system_caches", config.Moderate) {
+		systemCachePath := platform.SystemCacheDir()
+		if systemCachePath != "" && utils.PathExists(systemCachePath) {
+			if target, ok := cacheTarget(systemCachePath, 1, criteria, "system_caches", "System caches", config.Moderate); ok {
+				targets = append(targets, target)
 			}
 		}
 	}
@@ -280,6 +310,7 @@ func (s *SystemCleaner) scanLogs(cfg *config.Config) ([]CleanTarget, error) {
 		if totalSize > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        "/private/var/log/asl",
+				Category:    "asl_logs",
 				Description: "ASL log files",
 				SizeBytes:   totalSize,
 				Safety:      config.Moderate,
@@ -287,10 +318,23 @@ func (s *SystemCleaner) scanLogs(cfg *config.Config) ([]CleanTarget, error) {
 		}
 	}
 
-	// System logs
-	logPattern := "/private/var/log/*.log"
-	matches, err = filepath.Glob(logPattern)
-	if err == nil && len(matches) > 0 {
+	// System logs, only the log files: the folder holds the state of
+	// logging daemons and, on Linux, the journal. Elsewhere than macOS, only
+	// rotated logs are offered. Windows keeps its logs in the event log.
+	logDir := platform.SystemLogDir()
+	if logDir == "" {
+		return targets, nil
+	}
+	matches = nil
+	for _, pattern := range platform.SystemLogPatterns() {
+		found, _ := filepath.Glob(filepath.Join(logDir, pattern))
+		for _, match := range found {
+			if !slices.Contains(matches, match) {
+				matches = append(matches, match)
+			}
+		}
+	}
+	if len(matches) > 0 {
 		var totalSize int64
 		for _, match := range matches {
 			if info, err := os.Stat(match); err == nil {
@@ -299,10 +343,12 @@ func (s *SystemCleaner) scanLogs(cfg *config.Config) ([]CleanTarget, error) {
 		}
 		if totalSize > 0 {
 			targets = append(targets, CleanTarget{
-				Path:        "/private/var/log",
+				Path:        logDir,
+				Category:    "system_logs",
 				Description: "System log files",
 				SizeBytes:   totalSize,
 				Safety:      config.Moderate,
+				Entries:     matches,
 			})
 		}
 	}
@@ -313,71 +359,175 @@ func (s *SystemCleaner) scanLogs(cfg *config.Config) ([]CleanTarget, error) {
 func (s *SystemCleaner) scanTemp() ([]CleanTarget, error) {
 	targets := []CleanTarget{}
 
-	tempPaths := []string{
-		"/private/var/tmp",
-		"/private/tmp",
-	}
-
-	for _, path := range tempPaths {
-		if utils.PathExists(path) {
-			size, _ := utils.GetDirSize(path)
+	for _, path := range platform.TempDirs() {
+		if !utils.PathExists(path) {
+			continue
+		}
+		if !platform.WholeTempDirs() {
+			entries, size := staleTempFiles(path, time.Now())
 			if size > 0 {
 				targets = append(targets, CleanTarget{
 					Path:        path,
-					Description: fmt.Sprintf("Temporary files in %s", path),
+					Category:    "temp_files",
+					Description: fmt.Sprintf("Temporary files in %s unused for %d days", path, int(tempFileMinAge/day)),
 					SizeBytes:   size,
 					Safety:      config.Safe,
+					Entries:     entries,
 				})
 			}
+			continue
+		}
+		size, _ := utils.GetDirSize(path)
+		if size > 0 {
+			targets = append(targets, CleanTarget{
+				Path:        path,
+				Category:    "temp_files",
+				Description: fmt.Sprintf("Temporary files in %s", path),
+				SizeBytes:   size,
+				Safety:      config.Safe,
+			})
 		}
 	}
 
 	return targets, nil
 }
 
+// tempFileMinAge is how long a file of a shared temp folder must have gone
+// unmodified and unread to be offered, as systemd-tmpfiles does for /tmp
+const tempFileMinAge = 10 * day
+
+// tempRuntimePrefixes start the names of the folders and files of shared
+// temp folders that running sessions use: X11 and ICE sockets and locks,
+// ssh-agent, tmux, GnuPG, keyrings, D-Bus, PulseAudio and the private
+// folders of systemd services
+var tempRuntimePrefixes = []string{".X", ".ICE-unix", ".font-unix", ".Test-unix", "ssh-", "tmux-", "gpg-", "keyring-", "dbus-", "pulse-", "systemd-private-", "snap-private-tmp"}
+
+// staleTempFiles returns the regular files of the user in a shared temp
+// folder that have not been modified or read for tempFileMinAge, with
+// their size. Runtime folders, and any folder holding a socket, are left
+// alone with everything below them.
+func staleTempFiles(dir string, now time.Time) ([]string, int64) {
+	children, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, 0
+	}
+	for _, child := range children {
+		if child.Type()&os.ModeSocket != 0 {
+			return nil, 0
+		}
+	}
+
+	var files []string
+	var size int64
+	for _, child := range children {
+		if isTempRuntime(child.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, child.Name())
+		if child.IsDir() {
+			childFiles, childSize := staleTempFiles(path, now)
+			files = append(files, childFiles...)
+			size += childSize
+			continue
+		}
+		if !child.Type().IsRegular() {
+			continue
+		}
+		info, err := child.Info()
+		if err != nil || !utils.OwnedByUser(info) {
+			continue
+		}
+		if now.Sub(info.ModTime()) < tempFileMinAge || now.Sub(utils.AccessTime(info)) < tempFileMinAge {
+			continue
+		}
+		files = append(files, path)
+		size += info.Size()
+	}
+	return files, size
+}
+
+// isTempRuntime reports whether an entry of a temp folder belongs to a
+// running session
+func isTempRuntime(name string) bool {
+	for _, prefix := range tempRuntimePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *SystemCleaner) scanDNS() ([]CleanTarget, error) {
 	// DNS cache doesn't have a measurable size, but we report it as cleanable
-	return []CleanTarget{
-		{
-			Path:        "system:dns_cache",
-			Description: "DNS cache (via dscacheutil)",
-			SizeBytes:   0,
-			Safety:      config.Safe,
-		},
-	}, nil
+	return []CleanTarget{actionTarget("dns_cache", "system:dns_cache", 0)}, nil
 }
 
+// scanHomebrew returns the downloads of the Homebrew cache and the kegs of
+// the Cellar that no installed formula or cask uses. If brew can't list
+// what is installed, the whole cache is offered to brew cleanup instead.
 func (s *SystemCleaner) scanHomebrew() ([]CleanTarget, error) {
+	runner := s.commands()
+
 	// Homebrew cache location
-	cmd := exec.Command("brew", "--cache")
-	output, err := cmd.Output()
+	output, err := runner.Output("brew", "--cache")
 	if err != nil {
 		return nil, err
 	}
+	cachePath := filepath.Clean(strings.TrimSpace(string(output)))
 
-	cachePath := string(output)
-	cachePath = filepath.Clean(cachePath[:len(cachePath)-1]) // Remove newline
+	installs, err := brewInstalled(runner)
+	if err != nil {
+		return scanHomebrewCache(cachePath), nil
+	}
 
+	targets := []CleanTarget{}
+	if entries, size := staleBrewDownloads(cachePath, installs); len(entries) > 0 {
+		targets = append(targets, CleanTarget{
+			Path:        cachePath,
+			Category:    "homebrew_stale_downloads",
+			Description: "Homebrew downloads of uninstalled formulae, casks and versions",
+			SizeBytes:   size,
+			Safety:      config.Safe,
+			Entries:     entries,
+		})
+	}
+
+	cellar, err := runner.Output("brew", "--cellar")
+	if err != nil {
+		return targets, nil
+	}
+	prefix, err := runner.Output("brew", "--prefix")
+	if err != nil {
+		return targets, nil
+	}
+	targets = append(targets, brewKegTargets(strings.TrimSpace(string(cellar)), strings.TrimSpace(string(prefix)), installs)...)
+
+	return targets, nil
+}
+
+// scanHomebrewCache returns the whole Homebrew cache, cleaned with brew cleanup
+func scanHomebrewCache(cachePath string) []CleanTarget {
 	if utils.PathExists(cachePath) {
 		size, _ := utils.GetDirSize(cachePath)
 		if size > 0 {
 			return []CleanTarget{
 				{
 					Path:        cachePath,
+					Category:    "homebrew_cache",
 					Description: "Homebrew cache",
 					SizeBytes:   size,
 					Safety:      config.Safe,
 				},
-			}, nil
+			}
 		}
 	}
 
-	return []CleanTarget{}, nil
+	return []CleanTarget{}
 }
 
-func (s *SystemCleaner) scanXcode() ([]CleanTarget, error) {
+func (s *SystemCleaner) scanXcode(cfg *config.Config) ([]CleanTarget, error) {
 	targets := []CleanTarget{}
-	home, err := os.UserHomeDir()
+	home, err := cfg.HomeDir()
 	if err != nil {
 		return nil, err
 	}
@@ -389,6 +539,7 @@ func (s *SystemCleaner) scanXcode() ([]CleanTarget, error) {
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        derivedDataPath,
+				Category:    "xcode_derived_data",
 				Description: "Xcode DerivedData",
 				SizeBytes:   size,
 				Safety:      config.Safe,
@@ -398,11 +549,14 @@ func (s *SystemCleaner) scanXcode() ([]CleanTarget, error) {
 
 	// Archives (optional, only if size is significant)
 	archivesPath := filepath.Join(home, "Library", "Developer", "Xcode", "Archives")
-	if utils.PathExists(archivesPath) {
+	if keep, ok := xcodeArchivesRetention(cfg); ok {
+		targets = append(targets, retainedXcodeArchives(archivesPath, keep, config.Moderate)...)
+	} else if utils.PathExists(archivesPath) {
 		size, _ := utils.GetDirSize(archivesPath)
 		if size > 0 {
 			targets = append(targets, CleanTarget{
 				Path:        archivesPath,
+				Category:    "xcode_archives",
 				Description: "Xcode Archives",
 				SizeBytes:   size,
 				Safety:      config.Moderate,
@@ -410,88 +564,59 @@ func (s *SystemCleaner) scanXcode() ([]CleanTarget, error) {
 		}
 	}
 
+	// Documentation and Previews caches
+	targets = append(targets, xcodeDocCaches(home)...)
+
+	// Device symbols for OS versions the installed Xcode no longer supports
+	if output, err := s.commands().Output("xcode-select", "-p"); err == nil {
+		minimums := xcodeMinimumVersions(strings.TrimSpace(string(output)))
+		targets = append(targets, scanUnsupportedDeviceSupport(home, minimums)...)
+	}
+
 	return targets, nil
 }
 
-func (s *SystemCleaner) scanLaunchpad() ([]CleanTarget, error) {
-	home, err := os.UserHomeDir()
+func (s *SystemCleaner) scanLaunchpad(cfg *config.Config) ([]CleanTarget, error) {
+	home, err := cfg.HomeDir()
 	if err != nil {
 		return nil, err
 	}
 
-	dbPath := filepath.Join(home, "Library", "Application Support", "Dock")
-	if utils.PathExists(dbPath) {
-		// Launchpad DB is a special case - we rebuild it, not delete it
-		return []CleanTarget{
-			{
-				Path:        dbPath,
-				Description: "Launchpad database (will be rebuilt)",
-				SizeBytes:   0, // Negligible size
-				Safety:      config.Dangerous,
-			},
-		}, nil
-	}
-
-	return []CleanTarget{}, nil
+	// Reset and rebuild actions rather than deletions
+	return scanActions(cfg, home, os.TempDir()), nil
 }
 
 func (s *SystemCleaner) scanIOSBackups(cfg *config.Config) ([]CleanTarget, error) {
 	// Only show iOS backups in aggressive mode
-	if !cfg.CleanLevel.AllowsSafety(config.Dangerous) {
+	if !cfg.Allows(config.DomainSystem, "ios_backups", config.Dangerous) {
 		return []CleanTarget{}, nil
 	}
 
-	home, err := os.UserHomeDir()
+	home, err := cfg.HomeDir()
 	if err != nil {
 		return nil, err
 	}
 
+	// One target per device backup, oldest first
 	backupPath := filepath.Join(home, "Library", "Application Support", "MobileSync", "Backup")
-	if utils.PathExists(backupPath) {
-		size, _ := utils.GetDirSize(backupPath)
-		if size > 0 {
-			return []CleanTarget{
-				{
-					Path:        backupPath,
-					Description: "iOS device backups (DANGEROUS - may contain important data)",
-					SizeBytes:   size,
-					Safety:      config.Dangerous,
-				},
-			}, nil
-		}
-	}
-
-	return []CleanTarget{}, nil
+	return iosBackupTargets(readIOSBackups(backupPath, s.readPlist), time.Now()), nil
 }
 
-// Private clean methods
-
-func (s *SystemCleaner) cleanDNSCache(dryRun bool) error {
-	if dryRun {
-		return nil
-	}
-
-	// Flush DNS cache
-	cmd := exec.Command("dscacheutil", "-flushcache")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to flush DNS cache: %w", err)
+func (s *SystemCleaner) scanMedia(cfg *config.Config) ([]CleanTarget, error) {
+	home, err := cfg.HomeDir()
+	if err != nil {
+		return nil, err
 	}
 
-	// Kill mDNSResponder (requires sudo for full effect)
-	cmd = exec.Command("killall", "-HUP", "mDNSResponder")
-	cmd.Run() // Ignore error if we don't have sudo
-
-	return nil
+	// Opt-in only: every category is skipped unless enabled in the config file
+	return scanMediaCaches(cfg, mediaCaches(home, os.TempDir())), nil
 }
 
-func (s *SystemCleaner) cleanHomebrew(dryRun bool) error {
-	if dryRun {
-		return nil
-	}
+// Private clean methods
 
+func cleanHomebrew(runner CommandRunner) error {
 	// Run brew cleanup
-	cmd := exec.Command("brew", "cleanup", "--prune=all")
-	if err := cmd.Run(); err != nil {
+	if err := runner.Run("brew", "cleanup", "--prune=all"); err != nil {
 		return fmt.Errorf("failed to run brew cleanup: %w", err)
 	}
 
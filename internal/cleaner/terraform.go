@@ -0,0 +1,163 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// TerraformProviderCopies is one provider release (same source, version and
+// platform) installed in several .terraform folders
+type TerraformProviderCopies struct {
+	Source   string // e.g. "registry.terraform.io/hashicorp/aws"
+	Version  string
+	Platform string // e.g. "darwin_arm64"
+	Size     int64  // Size of one copy
+	Paths    []string
+}
+
+// Savings returns the space a shared plugin cache would save: all copies
+// but one
+func (c TerraformProviderCopies) Savings() int64 {
+	return int64(len(c.Paths)-1) * c.Size
+}
+
+// FindTerraformDirs returns the .terraform folders in the search directories
+func FindTerraformDirs(ctx context.Context, cfg *config.Config) ([]string, error) {
+	s, err := scanner.NewScanner()
+	if err != nil {
+		return nil, err
+	}
+	configureScanner(s, cfg)
+
+	dirs := []string{}
+	for result := range s.FindByPattern(ctx, ".terraform") {
+		if result.Err == nil {
+			dirs = append(dirs, result.Path)
+		}
+	}
+	return dirs, nil
+}
+
+// TerraformProviderDuplicates returns the provider releases installed in
+// more than one of the given .terraform folders, largest savings first.
+// Providers are laid out as providers/<host>/<namespace>/<type>/<version>/<platform>;
+// platform folders that are symlinks already point to a plugin cache and
+// take no space.
+func TerraformProviderDuplicates(terraformDirs []string) []TerraformProviderCopies {
+	byRelease := make(map[string]*TerraformProviderCopies)
+
+	for _, dir := range terraformDirs {
+		providers := filepath.Join(dir, "providers")
+		matches, _ := filepath.Glob(filepath.Join(providers, "*", "*", "*", "*", "*"))
+
+		for _, path := range matches {
+			info, err := os.Lstat(path)
+			if err != nil || !info.IsDir() {
+				continue
+			}
+
+			rel, _ := filepath.Rel(providers, path)
+			parts := strings.Split(rel, string(filepath.Separator))
+			size, _ := utils.GetDirSize(path)
+			if size == 0 {
+				continue
+			}
+
+			key := filepath.ToSlash(rel)
+			copies, ok := byRelease[key]
+			if !ok {
+				copies = &TerraformProviderCopies{
+					Source:   strings.Join(parts[:3], "/"),
+					Version:  parts[3],
+					Platform: parts[4],
+					Size:     size,
+				}
+				byRelease[key] = copies
+			}
+			copies.Paths = append(copies.Paths, path)
+		}
+	}
+
+	duplicates := []TerraformProviderCopies{}
+	for _, copies := range byRelease {
+		if len(copies.Paths) > 1 {
+			duplicates = append(duplicates, *copies)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Savings() != duplicates[j].Savings() {
+			return duplicates[i].Savings() > duplicates[j].Savings()
+		}
+		return duplicates[i].Source < duplicates[j].Source
+	})
+
+	return duplicates
+}
+
+// pluginCacheDirPattern matches the plugin_cache_dir setting of a Terraform
+// CLI configuration file
+var pluginCacheDirPattern = regexp.MustCompile(`(?m)^\s*plugin_cache_dir\s*=\s*"([^"]*)"`)
+
+// TerraformRCPath returns the Terraform CLI configuration file
+// (TF_CLI_CONFIG_FILE, or ~/.terraformrc)
+func TerraformRCPath(home string) string {
+	if path := os.Getenv("TF_CLI_CONFIG_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(home, ".terraformrc")
+}
+
+// TerraformPluginCacheDir returns the shared plugin cache Terraform is set
+// up to use, from TF_PLUGIN_CACHE_DIR or the CLI configuration file, or ""
+// if there is none
+func TerraformPluginCacheDir(home string) string {
+	if dir := os.Getenv("TF_PLUGIN_CACHE_DIR"); dir != "" {
+		return dir
+	}
+
+	data, err := os.ReadFile(TerraformRCPath(home))
+	if err != nil {
+		return ""
+	}
+	if match := pluginCacheDirPattern.FindSubmatch(data); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+// ConfigureTerraformPluginCache adds a shared plugin cache
+// (~/.terraform.d/plugin-cache) to the Terraform CLI configuration file and
+// creates it. Providers are then installed once and linked into each
+// .terraform folder on the next terraform init. It returns the cache
+// directory, leaving an existing setting untouched.
+func ConfigureTerraformPluginCache(home string) (string, error) {
+	if dir := TerraformPluginCacheDir(home); dir != "" {
+		return dir, nil
+	}
+
+	dir := filepath.Join(home, ".terraform.d", "plugin-cache")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	rc := TerraformRCPath(home)
+	f, err := os.OpenFile(rc, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "\n# Added by epurer: share provider downloads between projects\nplugin_cache_dir = %q\n", dir); err != nil {
+		return "", fmt.Errorf("failed to update %s: %w", rc, err)
+	}
+	return dir, nil
+}
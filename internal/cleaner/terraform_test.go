@@ -0,0 +1,93 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTerraformProviderDuplicates(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	aws := "providers/registry.terraform.io/hashicorp/aws/5.31.0/darwin_arm64/terraform-provider-aws_v5.31.0"
+	random := "providers/registry.terraform.io/hashicorp/random/3.6.0/darwin_arm64/terraform-provider-random_v3.6.0"
+	createTestFile(t, tmpDir, "api/.terraform/"+aws, strings.Repeat("a", 1000))
+	createTestFile(t, tmpDir, "web/.terraform/"+aws, strings.Repeat("a", 1000))
+	createTestFile(t, tmpDir, "db/.terraform/"+aws, strings.Repeat("a", 1000))
+	createTestFile(t, tmpDir, "api/.terraform/"+random, "random")
+
+	// Linked from a plugin cache: takes no space in the project
+	cached := filepath.Join(tmpDir, "cache", "darwin_arm64")
+	createTestFile(t, tmpDir, "cache/darwin_arm64/terraform-provider-random_v3.6.0", "random")
+	linkDir := filepath.Join(tmpDir, "web", ".terraform", filepath.Dir(filepath.Dir(random)))
+	if err := os.MkdirAll(linkDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(cached, filepath.Join(linkDir, "darwin_arm64")); err != nil {
+		t.Fatal(err)
+	}
+
+	dirs := []string{
+		filepath.Join(tmpDir, "api", ".terraform"),
+		filepath.Join(tmpDir, "web", ".terraform"),
+		filepath.Join(tmpDir, "db", ".terraform"),
+	}
+	duplicates := TerraformProviderDuplicates(dirs)
+
+	if len(duplicates) != 1 {
+		t.Fatalf("Expected 1 duplicated provider, got %+v", duplicates)
+	}
+	copies := duplicates[0]
+	if copies.Source != "registry.terraform.io/hashicorp/aws" || copies.Version != "5.31.0" || copies.Platform != "darwin_arm64" {
+		t.Errorf("Unexpected provider %+v", copies)
+	}
+	if len(copies.Paths) != 3 {
+		t.Errorf("Expected 3 copies, got %v", copies.Paths)
+	}
+	if copies.Savings() != 2000 {
+		t.Errorf("Savings() = %d, expected 2000", copies.Savings())
+	}
+}
+
+func TestConfigureTerraformPluginCache(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	t.Setenv("TF_CLI_CONFIG_FILE", "")
+	t.Setenv("TF_PLUGIN_CACHE_DIR", "")
+	createTestFile(t, home, ".terraformrc", "disable_checkpoint = true\n")
+
+	if dir := TerraformPluginCacheDir(home); dir != "" {
+		t.Fatalf("Expected no plugin cache, got %s", dir)
+	}
+
+	dir, err := ConfigureTerraformPluginCache(home)
+	if err != nil {
+		t.Fatalf("ConfigureTerraformPluginCache() error = %v", err)
+	}
+	if dir != filepath.Join(home, ".terraform.d", "plugin-cache") {
+		t.Errorf("Unexpected plugin cache %s", dir)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("Plugin cache was not created: %v", err)
+	}
+	if got := TerraformPluginCacheDir(home); got != dir {
+		t.Errorf("TerraformPluginCacheDir() = %q after configuring, expected %q", got, dir)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(home, ".terraformrc"))
+	if !strings.HasPrefix(string(data), "disable_checkpoint = true\n") {
+		t.Errorf("Existing settings were not kept:\n%s", data)
+	}
+
+	// A second run leaves the file alone
+	if _, err := ConfigureTerraformPluginCache(home); err != nil {
+		t.Fatalf("Second ConfigureTerraformPluginCache() error = %v", err)
+	}
+	again, _ := os.ReadFile(filepath.Join(home, ".terraformrc"))
+	if string(again) != string(data) {
+		t.Errorf("Configuration changed on second run:\n%s", again)
+	}
+}
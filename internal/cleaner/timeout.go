@@ -0,0 +1,59 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+// scanGracePeriod is how long a cleaner may keep running after its budget
+// expires to hand back what it found so far. Cleaners still running after
+// that are abandoned.
+var scanGracePeriod = 2 * time.Second
+
+// ScanWithTimeout runs c.Scan within the per-cleaner budget set in
+// cfg.ScanTimeout (0 = no limit). When the budget expires the scan context is
+// cancelled and the targets found so far are returned with timedOut set, so
+// one huge directory can't block the whole run. The targets returned go
+// through prepareTargets.
+func ScanWithTimeout(ctx context.Context, c Cleaner, cfg *config.Config) (targets []CleanTarget, timedOut bool, err error) {
+	defer func() {
+		targets = prepareTargets(cfg, c.Domain(), targets)
+	}()
+
+	if cfg.ScanTimeout <= 0 {
+		targets, err = c.Scan(ctx, cfg)
+		return targets, false, err
+	}
+
+	scanCtx, cancel := context.WithTimeout(ctx, cfg.ScanTimeout)
+	defer cancel()
+
+	type scanResult struct {
+		targets []CleanTarget
+		err     error
+	}
+
+	done := make(chan scanResult, 1)
+	go func() {
+		targets, err := c.Scan(scanCtx, cfg)
+		done <- scanResult{targets, err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.targets, errors.Is(scanCtx.Err(), context.DeadlineExceeded), result.err
+	case <-scanCtx.Done():
+	}
+
+	timedOut = errors.Is(scanCtx.Err(), context.DeadlineExceeded)
+
+	select {
+	case result := <-done:
+		return result.targets, timedOut, result.err
+	case <-time.After(scanGracePeriod):
+		return nil, timedOut, nil
+	}
+}
@@ -0,0 +1,82 @@
+package cleaner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+// slowCleaner is a cleaner whose Scan takes a fixed time, optionally
+// ignoring cancellation
+type slowCleaner struct {
+	delay        time.Duration
+	ignoreCancel bool
+}
+
+func (s *slowCleaner) Name() string                             { return "Slow" }
+func (s *slowCleaner) Domain() config.Domain                    { return config.DomainSystem }
+func (s *slowCleaner) Detect(ctx context.Context) (bool, error) { return true, nil }
+
+func (s *slowCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	found := []CleanTarget{{Path: "/found/early", SizeBytes: 1}}
+
+	if s.ignoreCancel {
+		time.Sleep(s.delay)
+		return found, nil
+	}
+
+	select {
+	case <-time.After(s.delay):
+		found = append(found, CleanTarget{Path: "/found/late", SizeBytes: 1})
+	case <-ctx.Done():
+	}
+	return found, nil
+}
+
+func (s *slowCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanTargets(ctx, targets, dryRun)
+}
+
+func TestScanWithTimeout(t *testing.T) {
+	oldGrace := scanGracePeriod
+	scanGracePeriod = 50 * time.Millisecond
+	defer func() { scanGracePeriod = oldGrace }()
+
+	tests := []struct {
+		name         string
+		cleaner      *slowCleaner
+		timeout      time.Duration
+		wantTargets  int
+		wantTimedOut bool
+	}{
+		{"finishes in time", &slowCleaner{delay: time.Millisecond}, time.Second, 2, false},
+		{"no budget", &slowCleaner{delay: 10 * time.Millisecond}, 0, 2, false},
+		{"partial results", &slowCleaner{delay: time.Minute}, 20 * time.Millisecond, 1, true},
+		{"ignores cancellation", &slowCleaner{delay: time.Second, ignoreCancel: true}, 20 * time.Millisecond, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cfg.ScanTimeout = tt.timeout
+
+			start := time.Now()
+			targets, timedOut, err := ScanWithTimeout(context.Background(), tt.cleaner, cfg)
+			if err != nil {
+				t.Fatalf("ScanWithTimeout() returned error: %v", err)
+			}
+
+			if len(targets) != tt.wantTargets {
+				t.Errorf("Expected %d targets, got %d", tt.wantTargets, len(targets))
+			}
+			if timedOut != tt.wantTimedOut {
+				t.Errorf("Expected timedOut = %v, got %v", tt.wantTimedOut, timedOut)
+			}
+			if tt.timeout > 0 && time.Since(start) > tt.timeout+scanGracePeriod+500*time.Millisecond {
+				t.Errorf("ScanWithTimeout() did not respect the budget, took %v", time.Since(start))
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package cleaner
+
+import (
+	"path/filepath"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+)
+
+// coreCache is a cache of a cross-platform tool, which every layout of home
+// directories keeps somewhere else
+type coreCache struct {
+	path        string
+	category    string
+	description string
+	domain      config.Domain
+	safety      config.SafetyLevel
+}
+
+// npmCacheDir returns the npm cache below home: ~/.npm, or npm-cache in
+// %LOCALAPPDATA% on Windows
+func npmCacheDir(layout platform.Layout, home string) string {
+	if layout == platform.WindowsLayout {
+		return filepath.Join(layout.ToolCacheDir(home), "npm-cache")
+	}
+	return filepath.Join(home, ".npm")
+}
+
+// yarnCacheDir returns the Yarn 1 cache below home. On macOS it is
+// ~/.cache/yarn, next to the global cache in ~/Library/Caches/Yarn.
+func yarnCacheDir(layout platform.Layout, home string) string {
+	switch layout {
+	case platform.WindowsLayout:
+		return filepath.Join(layout.ToolCacheDir(home), "Yarn", "Cache")
+	case platform.LinuxLayout:
+		return filepath.Join(layout.ToolCacheDir(home), "yarn")
+	default:
+		return filepath.Join(home, ".cache", "yarn")
+	}
+}
+
+// pnpmStoreDirs returns the pnpm stores below home: ~/.pnpm-store, used by
+// pnpm before version 7, and the default store of later versions, in the
+// data folder outside macOS
+func pnpmStoreDirs(layout platform.Layout, home string) []string {
+	store := filepath.Join(layout.DataDir(home), "pnpm", "store")
+	if layout == platform.MacOSLayout {
+		store = filepath.Join(home, "Library", "pnpm", "store")
+	}
+	return []string{filepath.Join(home, ".pnpm-store"), store}
+}
+
+// pipCacheDir returns the pip cache below home
+func pipCacheDir(layout platform.Layout, home string) string {
+	if layout == platform.WindowsLayout {
+		return filepath.Join(layout.ToolCacheDir(home), "pip", "Cache")
+	}
+	return filepath.Join(layout.ToolCacheDir(home), "pip")
+}
+
+// poetryCacheDir returns the Poetry cache below home
+func poetryCacheDir(layout platform.Layout, home string) string {
+	if layout == platform.WindowsLayout {
+		return filepath.Join(layout.ToolCacheDir(home), "pypoetry", "Cache")
+	}
+	return filepath.Join(layout.ToolCacheDir(home), "pypoetry")
+}
+
+// goBuildCacheDir returns the Go build cache below home
+func goBuildCacheDir(layout platform.Layout, home string) string {
+	return filepath.Join(layout.ToolCacheDir(home), "go-build")
+}
+
+// coreCaches returns the caches of the cross-platform tools below a home
+// directory of the given layout, as the Frontend and Backend cleaners find
+// them in the user's home
+func coreCaches(layout platform.Layout, home string) []coreCache {
+	caches := []coreCache{
+		{npmCacheDir(layout, home), "npm_cache", "npm cache", config.DomainFrontend, config.Safe},
+		{yarnCacheDir(layout, home), "yarn_cache", "Yarn cache", config.DomainFrontend, config.Safe},
+	}
+	for _, store := range pnpmStoreDirs(layout, home) {
+		caches = append(caches, coreCache{store, "pnpm_store", "pnpm store", config.DomainFrontend, config.Safe})
+	}
+	return append(caches,
+		coreCache{filepath.Join(home, ".gradle", "caches"), "gradle_cache", "Gradle cache", config.DomainBackend, config.Safe},
+		coreCache{filepath.Join(home, ".m2", "repository"), "maven_repository", "Maven local repository", config.DomainBackend, config.Moderate},
+		coreCache{goBuildCacheDir(layout, home), "go_build_cache", "Go build cache", config.DomainBackend, config.Safe},
+		coreCache{filepath.Join(home, "go", "pkg", "mod"), "go_mod_cache", "Go module cache", config.DomainBackend, config.Moderate},
+		coreCache{filepath.Join(home, ".cargo", "registry"), "cargo_registry", "Cargo registry cache", config.DomainBackend, config.Safe},
+		coreCache{pipCacheDir(layout, home), "pip_cache", "pip cache", config.DomainBackend, config.Safe},
+	)
+}
@@ -0,0 +1,178 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// toolchain is a less common language toolchain: its global caches and the
+// folders its projects build into. Neither is looked for unless the
+// toolchain is installed, so machines without it are not walked for them.
+type toolchain struct {
+	name     string
+	commands []string         // Commands one of which marks the toolchain installed
+	homes    []string         // Alternatively, folders below home one of which does
+	caches   []toolchainCache // Global caches, below home
+	projects []projectPattern // Project folders
+}
+
+// toolchainCache is a global cache of a toolchain
+type toolchainCache struct {
+	path        string // Below home
+	category    string
+	description string
+	safety      config.SafetyLevel
+}
+
+// Project types of the toolchains, confirming their pattern matches
+var (
+	stackProject = projectType{name: "Stack", markers: []string{"stack.yaml"}}
+	cabalProject = projectType{name: "Cabal", markers: []string{"*.cabal", "cabal.project"}}
+	mixProject   = projectType{name: "Mix", markers: []string{"mix.exs"}}
+	rebarProject = projectType{name: "rebar3", markers: []string{"rebar.config"}}
+	zigProject   = projectType{name: "Zig", markers: []string{"build.zig"}}
+)
+
+// toolchains are the toolchains the backend cleaner covers beside Python,
+// Java, Go, Rust, PHP and Ruby
+var toolchains = []toolchain{
+	{
+		name:     "Haskell Stack",
+		commands: []string{"stack"},
+		homes:    []string{".stack"},
+		caches: []toolchainCache{
+			{".stack/snapshots", "stack_snapshots", "Stack snapshot package builds", config.Moderate},
+			{".stack/pantry", "stack_pantry", "Stack package index and sources", config.Moderate},
+			{".stack/programs", "stack_programs", "GHC compilers installed by Stack", config.Moderate},
+		},
+		projects: []projectPattern{
+			{pattern: ".stack-work", description: "Stack build output (.stack-work)", safety: config.Safe, project: &stackProject},
+		},
+	},
+	{
+		name:     "Cabal",
+		commands: []string{"cabal"},
+		homes:    []string{".cabal", ".local/state/cabal"},
+		caches: []toolchainCache{
+			{".cabal/store", "cabal_store", "Cabal package store", config.Moderate},
+			{".local/state/cabal/store", "cabal_store", "Cabal package store", config.Moderate},
+			{".cabal/packages", "cabal_packages", "Cabal downloaded packages", config.Safe},
+			{".cache/cabal/packages", "cabal_packages", "Cabal downloaded packages", config.Safe},
+		},
+		projects: []projectPattern{
+			{pattern: "dist-newstyle", description: "Cabal build output (dist-newstyle)", safety: config.Safe, project: &cabalProject},
+		},
+	},
+	{
+		name:     "Elixir",
+		commands: []string{"mix", "elixir"},
+		homes:    []string{".mix", ".hex"},
+		caches: []toolchainCache{
+			{".hex/packages", "hex_packages", "Hex package cache", config.Safe},
+		},
+		projects: []projectPattern{
+			{pattern: "_build", category: "mix_build", description: "Mix build output (_build)", safety: config.Safe, project: &mixProject},
+			{pattern: "deps", category: "mix_deps", description: "Mix dependencies (deps)", safety: config.Moderate, project: &mixProject},
+		},
+	},
+	{
+		name:     "Erlang rebar3",
+		commands: []string{"rebar3"},
+		homes:    []string{".cache/rebar3"},
+		caches: []toolchainCache{
+			{".cache/rebar3", "rebar3_cache", "rebar3 cache", config.Safe},
+		},
+		projects: []projectPattern{
+			{pattern: "_build", category: "rebar3_build", description: "rebar3 build output (_build)", safety: config.Safe, project: &rebarProject, notProject: &mixProject},
+		},
+	},
+	{
+		name:     "Zig",
+		commands: []string{"zig"},
+		homes:    []string{".cache/zig", "Library/Caches/zig"},
+		caches: []toolchainCache{
+			{".cache/zig", "zig_cache", "Zig global cache", config.Safe},
+			{"Library/Caches/zig", "zig_cache", "Zig global cache", config.Safe},
+		},
+		projects: []projectPattern{
+			{pattern: ".zig-cache", category: "zig_project_cache", description: "Zig build cache (.zig-cache)", safety: config.Safe, project: &zigProject},
+		},
+	},
+}
+
+// installed reports whether the toolchain is found on this machine
+func (t toolchain) installed(home string) bool {
+	for _, command := range t.commands {
+		if utils.CommandExists(command) {
+			return true
+		}
+	}
+	for _, dir := range t.homes {
+		if utils.PathExists(filepath.Join(home, dir)) {
+			return true
+		}
+	}
+	return false
+}
+
+// installedToolchains returns the toolchains installed on this machine
+func installedToolchains(home string) []toolchain {
+	installed := []toolchain{}
+	for _, t := range toolchains {
+		if t.installed(home) {
+			installed = append(installed, t)
+		}
+	}
+	return installed
+}
+
+// toolchainPatterns returns the project patterns of the installed
+// toolchains
+func toolchainPatterns() []string {
+	home, _ := os.UserHomeDir()
+
+	patterns := []string{}
+	for _, t := range installedToolchains(home) {
+		for _, p := range t.projects {
+			patterns = append(patterns, p.pattern)
+		}
+	}
+	return patterns
+}
+
+// scanToolchains returns the global caches and project folders of the
+// installed toolchains that the clean level allows
+func scanToolchains(ctx context.Context, s *scanner.Scanner, cfg *config.Config, home string) []CleanTarget {
+	targets := []CleanTarget{}
+
+	for _, t := range installedToolchains(home) {
+		for _, cache := range t.caches {
+			if !cfg.Allows(config.DomainBackend, cache.category, cache.safety) {
+				continue
+			}
+			path := filepath.Join(home, cache.path)
+			if size, _ := utils.GetDirSize(path); size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        path,
+					Category:    cache.category,
+					Description: cache.description,
+					SizeBytes:   size,
+					Safety:      cache.safety,
+				})
+			}
+		}
+
+		for _, p := range t.projects {
+			if cfg.Allows(config.DomainBackend, p.categoryName(), p.safety) {
+				targets = append(targets, p.scan(ctx, s)...)
+			}
+		}
+	}
+
+	return targets
+}
@@ -0,0 +1,64 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+func TestScanToolchains(t *testing.T) {
+	if utils.CommandExists("cabal") {
+		t.Skip("cabal is installed")
+	}
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	createTestFile(t, home, ".stack/snapshots/aarch64-osx/lts-22.6/lib/libHSbase.a", "library")
+	createTestFile(t, home, ".stack/config.yaml", "templates: {}")
+	createTestFile(t, home, ".hex/packages/hexpm/phoenix-1.7.10.tar", "tarball")
+	createTestFile(t, home, ".hex/hex.config", "{api_key, <<\"secret\">>}.")
+
+	projects := filepath.Join(home, "Projects")
+	createTestDir(t, projects, "hs", map[string]string{
+		"stack.yaml":                    "resolver: lts-22.6",
+		".stack-work/dist/build/app.o":  "object",
+		"dist-newstyle/cache/plan.json": "{}", // Cabal is not installed
+	})
+	createTestDir(t, projects, "phx", map[string]string{
+		"mix.exs":                     "defmodule Phx.MixProject do end",
+		"_build/dev/lib/phx/app.beam": "beam",
+		"deps/phoenix/mix.exs":        "defmodule Phoenix.MixProject do end",
+	})
+	createTestDir(t, projects, "site", map[string]string{
+		"_build/index.html": "html", // Not a Mix project
+	})
+
+	s, _ := scanner.NewScannerWithDirs([]string{projects})
+	cfg := config.NewDefaultConfig()
+	cfg.CleanLevel = config.Standard
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	targets := scanToolchains(ctx, s, cfg, home)
+
+	expected := map[string]string{
+		filepath.Join(home, ".stack", "snapshots"):   "stack_snapshots",
+		filepath.Join(home, ".hex", "packages"):      "hex_packages",
+		filepath.Join(projects, "hs", ".stack-work"): "stack_work",
+		filepath.Join(projects, "phx", "_build"):     "mix_build",
+		filepath.Join(projects, "phx", "deps"):       "mix_deps",
+	}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %d targets, got %d: %+v", len(expected), len(targets), targets)
+	}
+	for _, target := range targets {
+		if expected[target.Path] != target.Category {
+			t.Errorf("Unexpected target %s (%s)", target.Path, target.Category)
+		}
+	}
+}
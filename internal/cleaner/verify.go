@@ -0,0 +1,61 @@
+package cleaner
+
+import "os"
+
+// Outcome is what checking a cleaned target on disk found
+type Outcome int
+
+const (
+	Freed   Outcome = iota // Gone, as expected
+	Partial                // Some of it left behind
+	Failed                 // Still there, nothing freed
+)
+
+// Verification is the outcome of a cleaned target, checked on disk once
+// cleaning is over
+type Verification struct {
+	Result    CleanResult
+	Outcome   Outcome
+	Remaining int64 // Bytes of the target still on disk
+}
+
+// Verify checks on disk what cleaning left of the targets of results, which
+// catches partial deletions and permission issues the cleaners didn't
+// report. Targets that aren't paths, that ran commands or that were evicted
+// can't be checked this way and go by their result.
+func Verify(results []CleanResult) []Verification {
+	verifications := make([]Verification, 0, len(results))
+	for _, result := range results {
+		v := Verification{Result: result, Outcome: Freed}
+		paths := result.Target.RemovedPaths()
+
+		switch {
+		case result.Target.Action == ActionRun || result.Target.Action == ActionEvict || len(paths) == 0:
+			if !result.Success {
+				v.Outcome = Failed
+			}
+		case !anyExists(paths):
+		default:
+			v.Remaining, _ = targetUsage(result.Target)
+			switch {
+			case v.Remaining == 0:
+			case result.BytesFreed > 0 || result.BytesQuarantined > 0 || result.BytesArchived > 0:
+				v.Outcome = Partial
+			default:
+				v.Outcome = Failed
+			}
+		}
+		verifications = append(verifications, v)
+	}
+	return verifications
+}
+
+// anyExists reports whether any of paths is still on disk
+func anyExists(paths []string) bool {
+	for _, path := range paths {
+		if _, err := os.Lstat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
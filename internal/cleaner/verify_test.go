@@ -0,0 +1,50 @@
+package cleaner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	dir := setupTestDir(t)
+	defer os.RemoveAll(dir)
+
+	left := createTestDir(t, dir, "partial", map[string]string{"locked.bin": "0123456789"})
+	stuck := createTestDir(t, dir, "stuck", map[string]string{"a.bin": "0123456789"})
+	kept := createTestDir(t, dir, "kept", map[string]string{"keep.txt": "x"})
+
+	results := []CleanResult{
+		{Target: CleanTarget{Path: filepath.Join(dir, "gone"), SizeBytes: 100}, Success: true, BytesFreed: 100},
+		{Target: CleanTarget{Path: left, SizeBytes: 110}, BytesFreed: 100, Error: errors.New("permission denied")},
+		{Target: CleanTarget{Path: stuck, SizeBytes: 10}, Error: errors.New("permission denied")},
+		{Target: CleanTarget{Path: kept, Entries: []string{filepath.Join(kept, "cache")}}, Success: true, BytesFreed: 50},
+		{Target: CleanTarget{Path: "docker:images"}, Success: true},
+		{Target: CleanTarget{Path: dir, Action: ActionRun, Category: "dns_cache"}, Error: errors.New("exit status 1")},
+	}
+
+	verifications := Verify(results)
+
+	expected := []struct {
+		outcome   Outcome
+		remaining int64
+	}{
+		{Freed, 0},
+		{Partial, 10},
+		{Failed, 10},
+		{Freed, 0}, // Only the entries were removed
+		{Freed, 0},
+		{Failed, 0},
+	}
+	if len(verifications) != len(expected) {
+		t.Fatalf("Expected %d verifications, got %d", len(expected), len(verifications))
+	}
+	for i, want := range expected {
+		got := verifications[i]
+		if got.Outcome != want.outcome || got.Remaining != want.remaining {
+			t.Errorf("%s: got outcome %d with %d bytes left, want %d with %d",
+				got.Result.Target.Path, got.Outcome, got.Remaining, want.outcome, want.remaining)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package cleaner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// VersionManagerCleaner handles language versions installed through version
+// managers (rbenv, nvm, pyenv, asdf) that no project uses anymore
+type VersionManagerCleaner struct {
+	scanner *scanner.Scanner
+}
+
+// versionManager describes where a version manager keeps its installs
+type versionManager struct {
+	name        string // Manager name (e.g. "rbenv")
+	tool        string // Tool the versions belong to (e.g. "ruby")
+	versionsDir string // Directory containing one folder per installed version
+	globalFile  string // File holding the global default version, if any
+}
+
+// versionFiles maps per-project version files to the tool they pin
+var versionFiles = map[string]string{
+	".ruby-version":   "ruby",
+	".nvmrc":          "nodejs",
+	".python-version": "python",
+}
+
+// NewVersionManagerCleaner creates a new VersionManagerCleaner
+func NewVersionManagerCleaner() (Cleaner, error) {
+	s, err := scanner.NewScanner()
+	if err != nil {
+		return nil, err
+	}
+
+	return &VersionManagerCleaner{
+		scanner: s,
+	}, nil
+}
+
+func (v *VersionManagerCleaner) Name() string {
+	return "Version Managers"
+}
+
+func (v *VersionManagerCleaner) Domain() config.Domain {
+	return config.DomainBackend
+}
+
+func (v *VersionManagerCleaner) Detect(ctx context.Context) (bool, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, err
+	}
+
+	return len(findVersionManagers(home)) > 0, nil
+}
+
+func (v *VersionManagerCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	configureScanner(v.scanner, cfg)
+
+	targets := []CleanTarget{}
+
+	// Installed versions need a reinstall if a project needs them again (Moderate)
+	if !cfg.CleanLevel.AllowsSafety(config.Moderate) {
+		return targets, nil
+	}
+
+	home, err := cfg.HomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	managers := findVersionManagers(home)
+	if len(managers) == 0 {
+		return targets, nil
+	}
+
+	refs := v.collectVersionRefs(ctx, home)
+
+	for _, manager := range managers {
+		toolRefs := refs[manager.tool]
+		if manager.globalFile != "" {
+			toolRefs = append(toolRefs, readVersionFile(manager.globalFile)...)
+		}
+
+		for _, version := range unreferencedVersions(manager, toolRefs) {
+			path := filepath.Join(manager.versionsDir, version)
+			size, _ := utils.GetDirSize(path)
+			if size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        path,
+					Category:    categoryName(manager.name) + "_versions",
+					Description: fmt.Sprintf("%s %s %s (not used by any project)", manager.name, manager.tool, version),
+					SizeBytes:   size,
+					Safety:      config.Moderate,
+				})
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+func (v *VersionManagerCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	return cleanTargets(ctx, targets, dryRun)
+}
+
+func (v *VersionManagerCleaner) Patterns() []string {
+	patterns := []string{".tool-versions"}
+	for fileName := range versionFiles {
+		patterns = append(patterns, fileName)
+	}
+	return patterns
+}
+
+// collectVersionRefs gathers the versions pinned by project version files
+// under the search directories, keyed by tool
+func (v *VersionManagerCleaner) collectVersionRefs(ctx context.Context, home string) map[string][]string {
+	refs := make(map[string][]string)
+
+	for fileName, tool := range versionFiles {
+		resultChan := v.scanner.FindByPattern(ctx, fileName)
+		for result := range resultChan {
+			if result.Err != nil {
+				continue
+			}
+			refs[tool] = append(refs[tool], readVersionFile(result.Path)...)
+		}
+	}
+
+	toolVersionFiles := []string{filepath.Join(home, ".tool-versions")}
+	resultChan := v.scanner.FindByPattern(ctx, ".tool-versions")
+	for result := range resultChan {
+		if result.Err == nil {
+			toolVersionFiles = append(toolVersionFiles, result.Path)
+		}
+	}
+
+	for _, path := range toolVersionFiles {
+		for tool, versions := range readToolVersions(path) {
+			refs[tool] = append(refs[tool], versions...)
+		}
+	}
+
+	return refs
+}
+
+// findVersionManagers returns the version managers installed in home
+func findVersionManagers(home string) []versionManager {
+	candidates := []versionManager{
+		{"rbenv", "ruby", filepath.Join(home, ".rbenv", "versions"), filepath.Join(home, ".rbenv", "version")},
+		{"pyenv", "python", filepath.Join(home, ".pyenv", "versions"), filepath.Join(home, ".pyenv", "version")},
+		{"nvm", "nodejs", filepath.Join(home, ".nvm", "versions", "node"), filepath.Join(home, ".nvm", "alias", "default")},
+	}
+
+	// asdf keeps one install folder per plugin
+	plugins, _ := os.ReadDir(filepath.Join(home, ".asdf", "installs"))
+	for _, plugin := range plugins {
+		if plugin.IsDir() {
+			candidates = append(candidates, versionManager{
+				name:        "asdf",
+				tool:        plugin.Name(),
+				versionsDir: filepath.Join(home, ".asdf", "installs", plugin.Name()),
+			})
+		}
+	}
+
+	managers := []versionManager{}
+	for _, manager := range candidates {
+		if utils.PathExists(manager.versionsDir) {
+			managers = append(managers, manager)
+		}
+	}
+
+	return managers
+}
+
+// unreferencedVersions returns the installed versions of a manager that no
+// reference pins. If a reference is an alias that can't be resolved offline
+// (e.g. "lts/*"), the newest installed version is kept as well.
+func unreferencedVersions(manager versionManager, refs []string) []string {
+	entries, err := os.ReadDir(manager.versionsDir)
+	if err != nil {
+		return nil
+	}
+
+	installed := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			installed = append(installed, entry.Name())
+		}
+	}
+	sort.Slice(installed, func(i, j int) bool {
+		return compareVersions(installed[i], installed[j]) < 0
+	})
+
+	keepNewest := false
+	for _, ref := range refs {
+		if isVersionAlias(ref) {
+			keepNewest = true
+		}
+	}
+
+	unused := []string{}
+	for i, version := range installed {
+		if keepNewest && i == len(installed)-1 {
+			continue
+		}
+		if !versionReferenced(version, refs) {
+			unused = append(unused, version)
+		}
+	}
+
+	return unused
+}
+
+// versionReferenced reports whether an installed version satisfies any of
+// the references. A reference like "18" or "3.11" matches every installed
+// version with that prefix.
+func versionReferenced(installed string, refs []string) bool {
+	normalized := normalizeVersion(installed)
+	for _, ref := range refs {
+		ref = normalizeVersion(ref)
+		if ref == "" {
+			continue
+		}
+		if normalized == ref || strings.HasPrefix(normalized, ref+".") || strings.HasPrefix(normalized, ref+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// isVersionAlias reports whether a reference is a symbolic alias rather than
+// a version number
+func isVersionAlias(ref string) bool {
+	ref = normalizeVersion(ref)
+	return ref != "" && (ref[0] < '0' || ref[0] > '9')
+}
+
+// normalizeVersion strips whitespace and a leading "v" from a version
+func normalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if len(version) > 1 && version[0] == 'v' && version[1] >= '0' && version[1] <= '9' {
+		version = version[1:]
+	}
+	return version
+}
+
+// compareVersions compares dotted version strings numerically where possible
+func compareVersions(a, b string) int {
+	partsA := strings.FieldsFunc(normalizeVersion(a), isVersionSeparator)
+	partsB := strings.FieldsFunc(normalizeVersion(b), isVersionSeparator)
+
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numA, errA := strconv.Atoi(partsA[i])
+		numB, errB := strconv.Atoi(partsB[i])
+		switch {
+		case errA == nil && errB == nil && numA != numB:
+			return numA - numB
+		case (errA != nil || errB != nil) && partsA[i] != partsB[i]:
+			return strings.Compare(partsA[i], partsB[i])
+		}
+	}
+
+	return len(partsA) - len(partsB)
+}
+
+func isVersionSeparator(r rune) bool {
+	return r == '.' || r == '-'
+}
+
+// readVersionFile reads a version file with one version per line
+// (.ruby-version, .nvmrc, .python-version, ~/.rbenv/version)
+func readVersionFile(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	versions := []string{}
+	sc := bufio.NewScanner(file)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			versions = append(versions, line)
+		}
+	}
+
+	return versions
+}
+
+// readToolVersions parses an asdf .tool-versions file ("nodejs 18.1.0 16.0.0")
+func readToolVersions(path string) map[string][]string {
+	toolVersions := make(map[string][]string)
+
+	for _, line := range readVersionFile(path) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		toolVersions[fields[0]] = append(toolVersions[fields[0]], fields[1:]...)
+	}
+
+	return toolVersions
+}
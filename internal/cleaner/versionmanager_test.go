@@ -0,0 +1,108 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUnreferencedVersions(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed []string
+		refs      []string
+		expected  []string
+	}{
+		{
+			name:      "exact references",
+			installed: []string{"3.1.4", "3.2.2", "2.7.8"},
+			refs:      []string{"3.2.2"},
+			expected:  []string{"2.7.8", "3.1.4"},
+		},
+		{
+			name:      "leading v and prefix references",
+			installed: []string{"v16.20.0", "v18.17.1", "v20.5.0"},
+			refs:      []string{"v20.5.0", "18"},
+			expected:  []string{"v16.20.0"},
+		},
+		{
+			name:      "prefix does not match partial component",
+			installed: []string{"3.1.0", "3.11.4"},
+			refs:      []string{"3.1"},
+			expected:  []string{"3.11.4"},
+		},
+		{
+			name:      "alias keeps newest",
+			installed: []string{"v18.17.1", "v9.11.2", "v20.5.0"},
+			refs:      []string{"lts/*"},
+			expected:  []string{"v9.11.2", "v18.17.1"},
+		},
+		{
+			name:      "no references",
+			installed: []string{"1.0.0"},
+			refs:      nil,
+			expected:  []string{"1.0.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := setupTestDir(t)
+			defer os.RemoveAll(tmpDir)
+
+			for _, version := range tt.installed {
+				createTestDir(t, tmpDir, version, nil)
+			}
+
+			manager := versionManager{name: "test", tool: "test", versionsDir: tmpDir}
+			got := unreferencedVersions(manager, tt.refs)
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("unreferencedVersions() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadToolVersions(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	path := createTestFile(t, tmpDir, ".tool-versions", "# pinned tools\nnodejs 18.17.1 16.20.0\nruby 3.2.2\n\n")
+
+	got := readToolVersions(path)
+	expected := map[string][]string{
+		"nodejs": {"18.17.1", "16.20.0"},
+		"ruby":   {"3.2.2"},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("readToolVersions() = %v, want %v", got, expected)
+	}
+}
+
+func TestFindVersionManagers(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestDir(t, tmpDir, ".rbenv/versions/3.2.2", nil)
+	createTestDir(t, tmpDir, ".asdf/installs/golang/1.21.0", nil)
+
+	managers := findVersionManagers(tmpDir)
+
+	found := make(map[string]string)
+	for _, manager := range managers {
+		found[manager.name+"/"+manager.tool] = manager.versionsDir
+	}
+
+	if found["rbenv/ruby"] != filepath.Join(tmpDir, ".rbenv", "versions") {
+		t.Errorf("Expected rbenv to be detected, got %v", found)
+	}
+	if found["asdf/golang"] != filepath.Join(tmpDir, ".asdf", "installs", "golang") {
+		t.Errorf("Expected asdf golang plugin to be detected, got %v", found)
+	}
+	if _, ok := found["nvm/nodejs"]; ok {
+		t.Error("nvm should not be detected without ~/.nvm")
+	}
+}
@@ -0,0 +1,138 @@
+package cleaner
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// WSLCleaner finds the caches of the cross-platform tools on the other side
+// of WSL: in the Windows user profile when run in a WSL distribution, in the
+// distributions' homes when run on Windows. Both sides fill their own npm,
+// Gradle or Go caches, and the other side's are out of sight. It only scans
+// with cfg.ScanWSL (--wsl): reaching the other side starts commands and its
+// files are slow to walk.
+type WSLCleaner struct {
+	homes  func() []platform.WSLHome // Finds the other side's homes, platform.WSLHomes if nil
+	runner CommandRunner             // Reaches the other side's JVM builds before their caches are deleted, ExecRunner if nil
+}
+
+// NewWSLCleaner creates a new WSLCleaner
+func NewWSLCleaner() Cleaner {
+	return &WSLCleaner{}
+}
+
+// SetRunner replaces the runner of the cleaner's external commands
+func (w *WSLCleaner) SetRunner(runner CommandRunner) {
+	w.runner = runner
+}
+
+// commands returns the runner of the cleaner's external commands
+func (w *WSLCleaner) commands() CommandRunner {
+	if w.runner == nil {
+		return ExecRunner{}
+	}
+	return w.runner
+}
+
+func (w *WSLCleaner) Name() string {
+	return "WSL"
+}
+
+func (w *WSLCleaner) Domain() config.Domain {
+	return config.DomainSystem
+}
+
+func (w *WSLCleaner) Detect(ctx context.Context) (bool, error) {
+	return platform.WSL(), nil
+}
+
+func (w *WSLCleaner) Scan(ctx context.Context, cfg *config.Config) ([]CleanTarget, error) {
+	targets := []CleanTarget{}
+	if !cfg.ScanWSL {
+		return targets, nil
+	}
+
+	homes := w.homes
+	if homes == nil {
+		homes = platform.WSLHomes
+	}
+	for _, home := range homes() {
+		for _, cache := range coreCaches(home.Layout, home.Path) {
+			if ctx.Err() != nil {
+				return targets, nil
+			}
+			if !cfg.Allows(cache.domain, cache.category, cache.safety) || !utils.PathExists(cache.path) {
+				continue
+			}
+			if size, _ := utils.GetDirSize(cache.path); size > 0 {
+				targets = append(targets, CleanTarget{
+					Path:        cache.path,
+					Category:    cache.category,
+					Description: cache.description + " (" + home.Side + ")",
+					SizeBytes:   size,
+					Safety:      cache.safety,
+				})
+			}
+		}
+	}
+	return targets, nil
+}
+
+// Clean removes the targets of each side once the Gradle daemons and Maven
+// builds running there are out of the way of their caches
+func (w *WSLCleaner) Clean(ctx context.Context, targets []CleanTarget, dryRun bool) ([]CleanResult, error) {
+	sides := make(map[string][]CleanTarget)
+	for _, target := range targets {
+		distro := platform.WSLDistro(target.Path)
+		sides[distro] = append(sides[distro], target)
+	}
+
+	results := make([]CleanResult, 0, len(targets))
+	for _, distro := range slices.Sorted(maps.Keys(sides)) {
+		runner := wslRunner{runner: w.commands(), distro: distro}
+		sideResults, err := cleanJVMTargetsOn(ctx, runner, distro == "", sides[distro], dryRun)
+		results = append(results, sideResults...)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// wslRunner runs commands on the other side of WSL: in distro through
+// wsl.exe when set, on Windows otherwise, through cmd.exe for commands that
+// aren't Windows executables (gradle)
+type wslRunner struct {
+	runner CommandRunner
+	distro string
+}
+
+// command returns the command line running name and args on the other side
+func (r wslRunner) command(name string, args []string) (string, []string) {
+	switch {
+	case r.distro != "":
+		return "wsl.exe", append([]string{"-d", r.distro, "-e", name}, args...)
+	case strings.HasSuffix(name, ".exe") || onWindows:
+		return name, args
+	default:
+		return "cmd.exe", append([]string{"/c", name}, args...)
+	}
+}
+
+// Output runs a command on the other side and returns its standard output
+func (r wslRunner) Output(name string, args ...string) ([]byte, error) {
+	name, args = r.command(name, args)
+	return r.runner.Output(name, args...)
+}
+
+// Run runs a command on the other side and waits for it to finish
+func (r wslRunner) Run(name string, args ...string) error {
+	name, args = r.command(name, args)
+	return r.runner.Run(name, args...)
+}
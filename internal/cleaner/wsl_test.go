@@ -0,0 +1,135 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/platform"
+)
+
+func TestCoreCaches_Windows(t *testing.T) {
+	home := filepath.Join("C:", "Users", "me")
+	local := filepath.Join(home, "AppData", "Local")
+	expected := map[string]string{
+		"npm_cache":        filepath.Join(local, "npm-cache"),
+		"yarn_cache":       filepath.Join(local, "Yarn", "Cache"),
+		"gradle_cache":     filepath.Join(home, ".gradle", "caches"),
+		"maven_repository": filepath.Join(home, ".m2", "repository"),
+		"go_build_cache":   filepath.Join(local, "go-build"),
+		"cargo_registry":   filepath.Join(home, ".cargo", "registry"),
+		"pip_cache":        filepath.Join(local, "pip", "Cache"),
+	}
+	if platform.Current() == platform.WindowsLayout {
+		t.Skip("Paths follow %LOCALAPPDATA% on Windows")
+	}
+
+	found := make(map[string]string)
+	for _, cache := range coreCaches(platform.WindowsLayout, home) {
+		if _, ok := found[cache.category]; !ok {
+			found[cache.category] = cache.path
+		}
+	}
+	for category, path := range expected {
+		if found[category] != path {
+			t.Errorf("%s: expected %s, got %s", category, path, found[category])
+		}
+	}
+}
+
+func TestWSLCleaner_Scan(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+	t.Setenv("XDG_CACHE_HOME", "")
+	createTestFile(t, home, ".cache/go-build/00/entry", "build")
+	createTestFile(t, home, "go/pkg/mod/cache/download/entry", "module")
+
+	w := &WSLCleaner{homes: func() []platform.WSLHome {
+		return []platform.WSLHome{{Path: home, Layout: platform.LinuxLayout, Side: "WSL Ubuntu"}}
+	}}
+	cfg := config.NewDefaultConfig()
+
+	// Only scanned with --wsl
+	if targets, _ := w.Scan(ctx, cfg); len(targets) != 0 {
+		t.Fatalf("Expected no targets without --wsl, got %+v", targets)
+	}
+
+	cfg.ScanWSL = true
+	cfg.CleanLevel = config.Conservative
+	targets, err := w.Scan(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(targets) != 1 {
+		t.Fatalf("Expected the Go build cache alone at the conservative level, got %+v", targets)
+	}
+	if targets[0].Path != filepath.Join(home, ".cache", "go-build") || targets[0].Description != "Go build cache (WSL Ubuntu)" {
+		t.Errorf("Unexpected target %+v", targets[0])
+	}
+
+	cfg.CleanLevel = config.Standard
+	if targets, _ := w.Scan(ctx, cfg); len(targets) != 2 {
+		t.Errorf("Expected the Go module cache too at the standard level, got %+v", targets)
+	}
+}
+
+func TestWSLCleaner_Clean(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The Windows side is this machine on Windows")
+	}
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	targets := func() []CleanTarget {
+		createTestFile(t, home, ".gradle/caches/modules-2/files.bin", "gradle")
+		createTestFile(t, home, "AppData/Local/npm-cache/index", "npm")
+		return []CleanTarget{
+			{Path: filepath.Join(home, ".gradle", "caches"), Category: "gradle_cache"},
+			{Path: filepath.Join(home, "AppData", "Local", "npm-cache"), Category: "npm_cache"},
+		}
+	}
+	name, args := processQuery(true, gradleDaemonProcess)
+	query := commandLine(name, args...)
+
+	// The Gradle daemons of the Windows side are listed with PowerShell and
+	// stopped through cmd.exe
+	runner := &RecordingRunner{Outputs: map[string]string{query: "4242\r\n"}}
+	w := &WSLCleaner{runner: runner}
+	results, err := w.Clean(context.Background(), targets(), false)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if !slices.Contains(runner.Commands(), "cmd.exe /c gradle --stop") {
+		t.Errorf("Expected the daemons stopped on Windows, got %v", runner.Commands())
+	}
+	for _, result := range results {
+		if result.Success != (result.Target.Category == "npm_cache") {
+			t.Errorf("Expected the Gradle cache kept while its daemon runs, got %+v", result)
+		}
+	}
+
+	// No daemon: both are deleted
+	runner = &RecordingRunner{Outputs: map[string]string{query: ""}}
+	w = &WSLCleaner{runner: runner}
+	results, _ = w.Clean(context.Background(), targets(), false)
+	for _, result := range results {
+		if !result.Success {
+			t.Errorf("Expected %s to be deleted, got %v", result.Target.Path, result.Error)
+		}
+	}
+}
+
+func TestWSLRunner(t *testing.T) {
+	runner := &RecordingRunner{Outputs: map[string]string{"wsl.exe -d Ubuntu -e pgrep -f java": "4242"}}
+	output, err := wslRunner{runner: runner, distro: "Ubuntu"}.Output("pgrep", "-f", "java")
+	if err != nil || string(output) != "4242" {
+		t.Errorf("Expected pgrep to run in the distribution, got %q (%v)", output, err)
+	}
+}
@@ -0,0 +1,156 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// deviceSupportPlatforms are the platforms Xcode keeps device symbols for,
+// with the name of their SDK in Xcode's Platforms folder
+var deviceSupportPlatforms = []struct {
+	name string // As in "iOS DeviceSupport"
+	sdk  string
+	key  string // Key of the platform in SDKSettings.json
+}{
+	{"iOS", "iPhoneOS", "iphoneos"},
+	{"watchOS", "WatchOS", "watchos"},
+	{"tvOS", "AppleTVOS", "appletvos"},
+}
+
+// deviceSupportVersion matches the OS version and build of a DeviceSupport
+// folder, named "17.0 (21A329)" or "iPhone15,2 17.0 (21A329)"
+var deviceSupportVersion = regexp.MustCompile(`(\d+(?:\.\d+)*) \(([0-9A-Za-z]+)\)$`)
+
+// xcodeDocCaches returns one target per Xcode documentation and Previews
+// cache, all rebuilt by Xcode when needed. The versions the documentation
+// cache holds are listed in its description.
+func xcodeDocCaches(home string) []CleanTarget {
+	developer := filepath.Join(home, "Library", "Developer")
+	caches := []struct {
+		path        string
+		category    string
+		description string
+	}{
+		{filepath.Join(developer, "Shared", "Documentation", "DocC"), "docc_cache", "DocC documentation cache"},
+		{filepath.Join(developer, "Xcode", "DocumentationCache"), "xcode_documentation_cache", "Xcode documentation cache"},
+		{filepath.Join(developer, "Xcode", "UserData", "Previews"), "xcode_previews", "SwiftUI Previews simulators and caches"},
+	}
+
+	targets := []CleanTarget{}
+	for _, cache := range caches {
+		size, _ := utils.GetDirSize(cache.path)
+		if size == 0 {
+			continue
+		}
+
+		description := cache.description
+		if cache.category == "xcode_documentation_cache" {
+			if versions := subdirNames(cache.path); len(versions) > 0 {
+				description += " (Xcode " + strings.Join(versions, ", ") + ")"
+			}
+		}
+
+		targets = append(targets, CleanTarget{
+			Path:        cache.path,
+			Category:    cache.category,
+			Description: description,
+			SizeBytes:   size,
+			Safety:      config.Safe,
+		})
+	}
+
+	return targets
+}
+
+// subdirNames returns the names of the folders in dir, sorted
+func subdirNames(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// xcodeMinimumVersions returns the oldest OS version the Xcode in
+// developerDir supports for each DeviceSupport platform, read from the
+// SDKSettings.json of its SDKs. Platforms without an SDK are left out.
+func xcodeMinimumVersions(developerDir string) map[string]string {
+	minimums := make(map[string]string)
+
+	for _, platform := range deviceSupportPlatforms {
+		path := filepath.Join(developerDir, "Platforms", platform.sdk+".platform", "Developer", "SDKs", platform.sdk+".sdk", "SDKSettings.json")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var settings struct {
+			SupportedTargets map[string]struct {
+				MinimumDeploymentTarget string
+			}
+		}
+		if err := json.Unmarshal(data, &settings); err != nil {
+			continue
+		}
+		if minimum := settings.SupportedTargets[platform.key].MinimumDeploymentTarget; minimum != "" {
+			minimums[platform.name] = minimum
+		}
+	}
+
+	return minimums
+}
+
+// scanUnsupportedDeviceSupport returns one target per DeviceSupport folder
+// of an OS version older than the installed Xcode supports: it can no
+// longer debug such devices, so their symbols are of no use. The Mobile
+// cleaner offers the whole DeviceSupport folders; SubtractOverlaps leaves
+// it the versions these targets don't cover.
+func scanUnsupportedDeviceSupport(home string, minimums map[string]string) []CleanTarget {
+	targets := []CleanTarget{}
+
+	for _, platform := range deviceSupportPlatforms {
+		minimum, ok := minimums[platform.name]
+		if !ok {
+			continue
+		}
+
+		dir := filepath.Join(home, "Library", "Developer", "Xcode", platform.name+" DeviceSupport")
+		for _, name := range subdirNames(dir) {
+			match := deviceSupportVersion.FindStringSubmatch(name)
+			if match == nil || compareVersions(match[1], minimum) >= 0 {
+				continue
+			}
+
+			path := filepath.Join(dir, name)
+			size, _ := utils.GetDirSize(path)
+			if size == 0 {
+				continue
+			}
+			targets = append(targets, CleanTarget{
+				Path:        path,
+				Category:    "unsupported_device_support",
+				Description: fmt.Sprintf("%s %s (%s) device support, Xcode supports %s %s and later", platform.name, match[1], match[2], platform.name, minimum),
+				SizeBytes:   size,
+				Safety:      config.Safe,
+			})
+		}
+	}
+
+	return targets
+}
@@ -0,0 +1,126 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestXcodeDocCaches(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	developer := filepath.Join(home, "Library", "Developer")
+	createTestFile(t, developer, "Shared/Documentation/DocC/index/data.bin", "docc")
+	createTestFile(t, developer, "Xcode/DocumentationCache/15.2/index.db", "docs")
+	createTestFile(t, developer, "Xcode/DocumentationCache/14.3/index.db", "docs")
+
+	targets := xcodeDocCaches(home)
+	if len(targets) != 2 {
+		t.Fatalf("Expected DocC and documentation cache targets, got %v", targets)
+	}
+	if targets[1].Description != "Xcode documentation cache (Xcode 14.3, 15.2)" {
+		t.Errorf("Expected the cached versions in the description, got %q", targets[1].Description)
+	}
+	for _, target := range targets {
+		if target.Safety != config.Safe {
+			t.Errorf("Expected %s to be Safe", target.Path)
+		}
+	}
+}
+
+func TestXcodeMinimumVersions(t *testing.T) {
+	developerDir := setupTestDir(t)
+	defer os.RemoveAll(developerDir)
+
+	createTestFile(t, developerDir, "Platforms/iPhoneOS.platform/Developer/SDKs/iPhoneOS.sdk/SDKSettings.json",
+		`{"SupportedTargets": {"iphoneos": {"MinimumDeploymentTarget": "12.0"}}}`)
+	createTestFile(t, developerDir, "Platforms/WatchOS.platform/Developer/SDKs/WatchOS.sdk/SDKSettings.json", `not json`)
+
+	minimums := xcodeMinimumVersions(developerDir)
+	if len(minimums) != 1 || minimums["iOS"] != "12.0" {
+		t.Errorf("Expected iOS 12.0 only, got %v", minimums)
+	}
+}
+
+func TestScanUnsupportedDeviceSupport(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	xcode := filepath.Join(home, "Library", "Developer", "Xcode")
+	createTestFile(t, xcode, "iOS DeviceSupport/11.4 (15F79)/Symbols/lib.dylib", "old")
+	createTestFile(t, xcode, "iOS DeviceSupport/iPhone10,3 10.3.3 (14G60)/Symbols/lib.dylib", "old")
+	createTestFile(t, xcode, "iOS DeviceSupport/17.0 (21A329)/Symbols/lib.dylib", "current")
+	createTestFile(t, xcode, "iOS DeviceSupport/unknown/Symbols/lib.dylib", "unparsable")
+	createTestFile(t, xcode, "watchOS DeviceSupport/4.0 (15R372)/Symbols/lib.dylib", "no minimum known")
+
+	targets := scanUnsupportedDeviceSupport(home, map[string]string{"iOS": "12.0"})
+	if len(targets) != 2 {
+		t.Fatalf("Expected 2 unsupported iOS versions, got %v", targets)
+	}
+	if targets[0].Description != "iOS 11.4 (15F79) device support, Xcode supports iOS 12.0 and later" {
+		t.Errorf("Unexpected description %q", targets[0].Description)
+	}
+	if !strings.HasSuffix(targets[1].Path, "iPhone10,3 10.3.3 (14G60)") {
+		t.Errorf("Expected the 10.3.3 folder, got %s", targets[1].Path)
+	}
+}
+
+func TestXcodeCleaner_ScanDeveloperCaches(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+	developerDir := filepath.Join(home, "Xcode.app", "Contents", "Developer")
+
+	createTestFile(t, developerDir, "Platforms/iPhoneOS.platform/Developer/SDKs/iPhoneOS.sdk/SDKSettings.json",
+		`{"SupportedTargets": {"iphoneos": {"MinimumDeploymentTarget": "12.0"}}}`)
+	createTestFile(t, home, "Library/Developer/Xcode/iOS DeviceSupport/11.4 (15F79)/Symbols/lib.dylib", "old")
+	createTestFile(t, home, "Library/Developer/Xcode/UserData/Previews/Simulator Devices/device.plist", "preview")
+
+	s := NewXcodeCleaner().(*SystemCleaner)
+	s.SetRunner(&RecordingRunner{Outputs: map[string]string{"xcode-select -p": developerDir + "\n"}})
+	cfg := config.NewDefaultConfig()
+	cfg.Home = home
+
+	targets, err := s.Scan(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Scan() returned error: %v", err)
+	}
+
+	categories := map[string]bool{}
+	for _, target := range targets {
+		categories[target.Category] = true
+	}
+	if !categories["xcode_previews"] || !categories["unsupported_device_support"] {
+		t.Errorf("Expected Previews and unsupported device support targets, got %v", targets)
+	}
+}
+
+func TestScanUnsupportedDeviceSupport_MobileOverlap(t *testing.T) {
+	home := setupTestDir(t)
+	defer os.RemoveAll(home)
+
+	deviceSupport := filepath.Join(home, "Library", "Developer", "Xcode", "iOS DeviceSupport")
+	createTestFile(t, deviceSupport, "11.4 (15F79)/Symbols/lib.dylib", "old")
+	createTestFile(t, deviceSupport, "17.0 (21A329)/Symbols/lib.dylib", "current")
+
+	// The Mobile cleaner offers the whole folder, the Xcode cleaner the
+	// version it no longer supports: the folder keeps the other versions
+	targetsByCleaner := map[string][]CleanTarget{
+		"Mobile": {{Path: deviceSupport, Category: "ios_device_support", SizeBytes: 10, Safety: config.Safe}},
+		"Xcode":  scanUnsupportedDeviceSupport(home, map[string]string{"iOS": "12.0"}),
+	}
+	SubtractOverlaps(targetsByCleaner)
+
+	mobile := targetsByCleaner["Mobile"]
+	if len(mobile) != 1 || !slices.Equal(mobile[0].Entries, []string{filepath.Join(deviceSupport, "17.0 (21A329)")}) {
+		t.Fatalf("Expected the supported version alone in the Mobile target, got %+v", mobile)
+	}
+	if mobile[0].SizeBytes != int64(len("current")) {
+		t.Errorf("Expected the unsupported version not counted twice, got %d bytes", mobile[0].SizeBytes)
+	}
+}
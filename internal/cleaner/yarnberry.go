@@ -0,0 +1,100 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// yarnBerryPattern finds the .yarn folders of Yarn 2+ (Berry) projects
+const yarnBerryPattern = ".yarn"
+
+// yarnBerryParts are what a Berry project keeps in .yarn that an install
+// brings back. The cache is only there when the global cache is off; it is
+// left alone by default in zero-install repositories, which commit it.
+var yarnBerryParts = []struct {
+	name, category, description string
+	safety                      config.SafetyLevel
+}{
+	{"cache", "yarn_berry_cache", "Yarn Berry project cache", config.Moderate},
+	{"unplugged", "yarn_unplugged", "Yarn Berry unplugged packages", config.Moderate},
+	{"install-state.gz", "yarn_install_state", "Yarn Berry install state", config.Safe},
+}
+
+// zeroInstallCategory is the category of the caches committed to git, only
+// offered when enabled in the config file
+const zeroInstallCategory = "yarn_zero_install_cache"
+
+// scanYarnBerry finds the caches, unplugged packages and install states of
+// Yarn Berry projects: folders with a package.json and a .yarnrc.yml. runner
+// asks git whether their caches are committed.
+func scanYarnBerry(ctx context.Context, s *scanner.Scanner, runner CommandRunner, cfg *config.Config) []CleanTarget {
+	targets := []CleanTarget{}
+
+	for result := range s.FindByPattern(ctx, yarnBerryPattern) {
+		if result.Err != nil {
+			continue
+		}
+		project := filepath.Dir(result.Path)
+		if !utils.PathExists(filepath.Join(project, "package.json")) || !utils.PathExists(filepath.Join(project, ".yarnrc.yml")) {
+			continue
+		}
+
+		for _, part := range yarnBerryParts {
+			path := filepath.Join(result.Path, part.name)
+			size, files, err := utils.GetDirUsage(path)
+			if err != nil || size == 0 {
+				continue
+			}
+
+			target := CleanTarget{
+				Path:        path,
+				Category:    part.category,
+				Description: part.description,
+				SizeBytes:   size,
+				Files:       files,
+				Safety:      part.safety,
+			}
+			if part.name == "cache" && isCommitted(runner, path) {
+				target.Category = zeroInstallCategory
+				target.Description = "Yarn zero-install cache (committed to git)"
+				target.Safety = config.Dangerous
+				if !cfg.OptedIn(config.DomainFrontend, zeroInstallCategory) {
+					continue
+				}
+			}
+			if !cfg.Allows(config.DomainFrontend, target.Category, target.Safety) {
+				continue
+			}
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// isCommitted reports whether files inside dir are tracked by the git
+// repository dir is in, as git ls-files --error-unmatch tells. Only its
+// "no match" status means untracked: when git fails otherwise, or isn't
+// installed, the files are taken as committed.
+func isCommitted(runner CommandRunner, dir string) bool {
+	for root := filepath.Dir(dir); ; root = filepath.Dir(root) {
+		if utils.PathExists(filepath.Join(root, ".git")) {
+			rel, err := filepath.Rel(root, dir)
+			if err != nil {
+				return true
+			}
+			_, err = runner.Output("git", "-C", root, "ls-files", "--error-unmatch", "--", filepath.ToSlash(rel))
+			var exitErr *exec.ExitError
+			return err == nil || !errors.As(err, &exitErr) || exitErr.ExitCode() != 1
+		}
+		if filepath.Dir(root) == root {
+			return false
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/scanner"
+)
+
+// scanYarnBerryIn scans dir for Yarn Berry projects, asking runner about
+// their git repositories
+func scanYarnBerryIn(t *testing.T, dir string, runner CommandRunner, cfg *config.Config) map[string]CleanTarget {
+	t.Helper()
+	s, _ := scanner.NewScannerWithDirs([]string{dir})
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	found := make(map[string]CleanTarget)
+	for _, target := range scanYarnBerry(ctx, s, runner, cfg) {
+		rel, _ := filepath.Rel(dir, target.Path)
+		found[filepath.ToSlash(rel)] = target
+	}
+	return found
+}
+
+func TestScanYarnBerry(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestDir(t, tmpDir, "app", map[string]string{
+		"package.json":                    "{}",
+		".yarnrc.yml":                     "nodeLinker: pnp",
+		".yarn/cache/react-npm-18.zip":    "zip",
+		".yarn/unplugged/esbuild/bin/esb": "binary",
+		".yarn/install-state.gz":          "state",
+		".yarn/releases/yarn-4.0.2.cjs":   "yarn",
+	})
+	// Not a Berry project
+	createTestDir(t, tmpDir, "classic", map[string]string{
+		"package.json":          "{}",
+		".yarn/cache/entry.zip": "zip",
+	})
+
+	cfg := config.NewDefaultConfig()
+	found := scanYarnBerryIn(t, tmpDir, &RecordingRunner{}, cfg)
+
+	expected := map[string]string{
+		"app/.yarn/cache":            "yarn_berry_cache",
+		"app/.yarn/unplugged":        "yarn_unplugged",
+		"app/.yarn/install-state.gz": "yarn_install_state",
+	}
+	if len(found) != len(expected) {
+		t.Errorf("Expected %d targets, got %+v", len(expected), found)
+	}
+	for rel, category := range expected {
+		if found[rel].Category != category {
+			t.Errorf("Expected %s as %s, got %+v", rel, category, found[rel])
+		}
+	}
+}
+
+func TestScanYarnBerry_ZeroInstall(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	createTestDir(t, tmpDir, "app", map[string]string{
+		"package.json":                 "{}",
+		".yarnrc.yml":                  "enableGlobalCache: false",
+		".yarn/cache/react-npm-18.zip": "zip",
+		".yarn/install-state.gz":       "state",
+		".git/HEAD":                    "ref: refs/heads/main",
+	})
+	app := filepath.Join(tmpDir, "app")
+	lsFiles := commandLine("git", "-C", app, "ls-files", "--error-unmatch", "--", ".yarn/cache")
+
+	// git lists the cache: it is committed
+	runner := &RecordingRunner{Outputs: map[string]string{lsFiles: ".yarn/cache/react-npm-18.zip\n"}}
+	cfg := config.NewDefaultConfig()
+	found := scanYarnBerryIn(t, tmpDir, runner, cfg)
+	if _, ok := found["app/.yarn/cache"]; ok {
+		t.Errorf("A committed cache should be left alone by default, got %+v", found)
+	}
+	if _, ok := found["app/.yarn/install-state.gz"]; !ok {
+		t.Errorf("Expected the install state, got %+v", found)
+	}
+
+	// Offered as Dangerous once enabled
+	enabled := true
+	cfg.CleanLevel = config.Aggressive
+	cfg.Overrides[config.DomainFrontend.Key()] = map[string]config.Override{zeroInstallCategory: {Enabled: &enabled}}
+	found = scanYarnBerryIn(t, tmpDir, runner, cfg)
+	if target := found["app/.yarn/cache"]; target.Category != zeroInstallCategory || target.Safety != config.Dangerous {
+		t.Errorf("Expected the zero-install cache as Dangerous, got %+v", target)
+	}
+
+	// git failing for another reason than no match: taken as committed
+	runner = &RecordingRunner{Errors: map[string]error{lsFiles: exec.ErrNotFound}}
+	found = scanYarnBerryIn(t, tmpDir, runner, cfg)
+	if target := found["app/.yarn/cache"]; target.Category != zeroInstallCategory {
+		t.Errorf("Expected the cache taken as committed, got %+v", target)
+	}
+
+	// No file of the cache is tracked: an ordinary cache
+	if runtime.GOOS == "windows" {
+		return
+	}
+	unmatched := exec.Command("sh", "-c", "exit 1").Run()
+	runner = &RecordingRunner{Errors: map[string]error{lsFiles: unmatched}}
+	found = scanYarnBerryIn(t, tmpDir, runner, cfg)
+	if target := found["app/.yarn/cache"]; target.Category != "yarn_berry_cache" {
+		t.Errorf("Expected an untracked cache, got %+v", target)
+	}
+}
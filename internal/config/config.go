@@ -1,14 +1,19 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/scanner"
+)
 
 // SafetyLevel indicates the risk level of a cleanup operation
 type SafetyLevel int
 
 const (
-	Safe       SafetyLevel = iota // 🟢 No risk - easily rebuilt (caches, logs)
-	Moderate                      // 🟡 Rebuild needed (node_modules, builds)
-	Dangerous                     // 🔴 Potential data loss (backups, databases)
+	Safe      SafetyLevel = iota // 🟢 No risk - easily rebuilt (caches, logs)
+	Moderate                     // 🟡 Rebuild needed (node_modules, builds)
+	Dangerous                    // 🔴 Potential data loss (backups, databases)
 )
 
 // String returns human-readable representation
@@ -39,13 +44,28 @@ func (s SafetyLevel) Icon() string {
 	}
 }
 
+// ParseSafetyLevel converts a lowercase name ("safe", "moderate",
+// "dangerous") to a SafetyLevel
+func ParseSafetyLevel(s string) (SafetyLevel, error) {
+	switch s {
+	case "safe":
+		return Safe, nil
+	case "moderate":
+		return Moderate, nil
+	case "dangerous":
+		return Dangerous, nil
+	default:
+		return Safe, fmt.Errorf("invalid safety level: %s (must be safe, moderate, or dangerous)", s)
+	}
+}
+
 // CleanLevel represents the aggressiveness of cleaning
 type CleanLevel int
 
 const (
 	Conservative CleanLevel = iota // Only Safe items
-	Standard                        // Safe + Moderate items
-	Aggressive                      // All items including Dangerous
+	Standard                       // Safe + Moderate items
+	Aggressive                     // All items including Dangerous
 )
 
 // String returns human-readable representation
@@ -96,6 +116,11 @@ type Domain int
 const (
 	DomainSystem   Domain = iota // System-level cleaners (trash, cache, logs)
 	DomainFrontend               // Frontend development (node_modules, npm cache)
+	DomainBackend                // Backend development (Python, Java, Go, Rust, PHP, Ruby)
+	DomainMobile                 // Mobile development (Xcode, Android, Flutter)
+	DomainDevOps                 // DevOps tooling (Docker, Kubernetes, Terraform)
+	DomainDataML                 // Data Science and ML (Conda, Jupyter, model caches)
+	DomainGameDev                // Game development (Unity, Unreal)
 )
 
 // String returns human-readable representation
@@ -105,19 +130,179 @@ func (d Domain) String() string {
 		return "System"
 	case DomainFrontend:
 		return "Frontend"
+	case DomainBackend:
+		return "Backend"
+	case DomainMobile:
+		return "Mobile"
+	case DomainDevOps:
+		return "DevOps"
+	case DomainDataML:
+		return "Data/ML"
+	case DomainGameDev:
+		return "Game Dev"
 	default:
 		return "Unknown"
 	}
 }
 
+// Key returns the lowercase name used for the domain in flags and the config
+// file (e.g. "dataml")
+func (d Domain) Key() string {
+	switch d {
+	case DomainSystem:
+		return "system"
+	case DomainFrontend:
+		return "frontend"
+	case DomainBackend:
+		return "backend"
+	case DomainMobile:
+		return "mobile"
+	case DomainDevOps:
+		return "devops"
+	case DomainDataML:
+		return "dataml"
+	case DomainGameDev:
+		return "gamedev"
+	default:
+		return "unknown"
+	}
+}
+
+// Override changes how one category of targets is treated. Unset fields keep
+// the cleaner's default.
+type Override struct {
+	Safety  *SafetyLevel // Safety level to report instead of the default
+	Enabled *bool        // false hides the category entirely
+	Keep    *int         // Newest items to keep, only older ones are offered
+}
+
+// Hook is a shell command run before or after cleaning, such as quitting
+// Docker Desktop before pruning or starting a backup script
+type Hook struct {
+	Command        string `json:"command"`          // Run with sh -c, none if empty
+	AbortOnFailure bool   `json:"abort_on_failure"` // A failing pre hook stops the run (or the cleaner)
+}
+
+// CleanerHooks are the hooks run around the targets of one cleaner
+type CleanerHooks struct {
+	Pre  Hook `json:"pre"`
+	Post Hook `json:"post"`
+}
+
+// Hooks are the hooks of a clean run, from the config file
+type Hooks struct {
+	PreClean  Hook                    `json:"pre_clean"`
+	PostClean Hook                    `json:"post_clean"`
+	Cleaners  map[string]CleanerHooks `json:"cleaners"` // By cleaner name, e.g. "DevOps"
+}
+
+// Webhook is where the summary of each clean run is posted
+type Webhook struct {
+	URL    string `json:"url"`    // None if empty
+	Format string `json:"format"` // "slack" or "json", from the URL if empty
+}
+
+// WorkHours are the hours of the week scheduled runs stay away from
+type WorkHours struct {
+	Start time.Duration  // After midnight
+	End   time.Duration  // After midnight, before Start if the hours span it
+	Days  []time.Weekday // Days the hours start on
+}
+
+// Contains reports whether t falls within the work hours
+func (w WorkHours) Contains(t time.Time) bool {
+	if w.Start == w.End {
+		return false
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	clock := t.Sub(midnight)
+
+	day := t.Weekday()
+	if w.End < w.Start && clock < w.End {
+		// Early hours of hours that started the day before
+		day = (day + 6) % 7
+	} else if clock < w.Start || (w.End > w.Start && clock >= w.End) {
+		return false
+	}
+	for _, d := range w.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Schedule is when scheduled runs (clean --scheduled) may clean, from the
+// config file. Runs are deferred until the next one otherwise.
+type Schedule struct {
+	RequireACPower bool          // Only clean when the Mac is plugged in
+	MinIdle        time.Duration // Only clean after this long without keyboard or mouse input
+	WorkHours      *WorkHours    // Never clean during these, if set
+}
+
 // Config holds runtime configuration for the cleaner
 type Config struct {
-	DryRun        bool       // If true, don't actually delete anything
-	Interactive   bool       // If true, ask for confirmation before cleaning
-	Domains       []Domain   // Which domains to clean (empty = all)
-	CleanLevel    CleanLevel // How aggressive to be
-	MaxConcurrent int        // Max number of concurrent scans
-	Verbose       bool       // Enable verbose output
+	DryRun        bool          // If true, don't actually delete anything
+	Interactive   bool          // If true, ask for confirmation before cleaning
+	Domains       []Domain      // Which domains to clean (empty = all)
+	CleanLevel    CleanLevel    // How aggressive to be
+	MaxConcurrent int           // Max number of cleaners deleting or hashing files at once
+	Verbose       bool          // Enable verbose output
+	ScanTimeout   time.Duration // Time budget for each cleaner's scan (0 = no limit)
+	ScanMaxDepth  int           // Directory levels below each project folder to scan (0 = no limit)
+	ScanExcludes  []string      // Extra paths or directory names project scans skip
+	SearchDirs    []string      // Project folders to scan on top of the default ones
+	Volumes       []string      // Folders of external volumes to scan for projects (--volume)
+	ScanWSL       bool          // Scan the other side of WSL for tool caches too (--wsl)
+	Selection     Selection     // Domains and categories to clean (--domain, --only; empty = all)
+	Home          string        // Home directory to scan instead of the user's (tests)
+
+	// Collects project scan statistics when set (report --profile)
+	ScanProfile *scanner.Profile
+
+	// One walk of the project folders shared by all cleaners, when set
+	SharedWalk *scanner.SharedWalk
+
+	// Projects in use (open in an editor, or running), by root directory,
+	// with what uses them. Their targets are escalated to Dangerous.
+	OpenProjects map[string]string
+
+	// Cleaner-specific options
+	CargoSweepDays       int    // If > 0, prune Rust target/ folders of artifacts older than this instead of removing them
+	MavenMaxAgeDays      int    // If > 0, prune Maven artifacts not used for this long instead of the whole repository
+	ModelMaxAgeDays      int    // Only suggest Hugging Face models not used for this long (0 = all models)
+	DatasetMaxAgeDays    int    // Only suggest downloaded datasets not used for this long (0 = all datasets)
+	InstallerMaxAgeDays  int    // Only suggest installers in Downloads and Desktop not used for this long
+	ScreenshotMaxAgeDays int    // Only suggest screenshots and screen recordings older than this
+	ScreenshotArchiveDir string // If set, screenshots are moved to a dated folder in it instead of deleted
+	CacheMaxAgeDays      int    // If > 0, only remove system, pip and Gradle cache entries not modified for this long
+	CacheMinEntrySize    int64  // If > 0, only remove system, pip and Gradle cache entries at least this large
+
+	// Shell hooks run around clean runs, from the config file
+	Hooks Hooks
+
+	// Webhook the summary of each clean run is posted to, if set
+	Webhook Webhook
+
+	// Conditions scheduled runs wait for, from the config file
+	Schedule Schedule
+
+	// Name of the output theme from the config file, empty for the default.
+	// The command resolving it checks the name.
+	Theme string
+
+	// If true, targets are moved to the quarantine instead of deleted, to be
+	// restored or purged later
+	Quarantine           bool
+	QuarantineMaxAgeDays int   // Quarantined targets are purged after this many days (0 = kept until purged)
+	QuarantineMaxBytes   int64 // If > 0, the oldest quarantined targets are purged to stay under this size
+
+	// Admin policy, if one is installed. It takes precedence over the rest.
+	Policy *Policy
+
+	// Per-category overrides from the config file, keyed by domain key then
+	// target category (e.g. Overrides["frontend"]["node_modules"])
+	Overrides map[string]map[string]Override
 }
 
 // NewDefaultConfig returns a Config with sensible defaults
@@ -129,5 +314,69 @@ func NewDefaultConfig() *Config {
 		CleanLevel:    Standard,
 		MaxConcurrent: 4,
 		Verbose:       false,
+		ScanTimeout:   60 * time.Second,
+		ScanMaxDepth:  10,
+		ScanExcludes:  []string{},
+		SearchDirs:    []string{},
+
+		MavenMaxAgeDays:      90,
+		ModelMaxAgeDays:      30,
+		DatasetMaxAgeDays:    30,
+		InstallerMaxAgeDays:  30,
+		ScreenshotMaxAgeDays: 30,
+		QuarantineMaxAgeDays: 30,
+
+		Overrides: map[string]map[string]Override{},
+	}
+}
+
+// Override returns the user's override for a target category, if any
+func (c *Config) Override(domain Domain, category string) (Override, bool) {
+	override, ok := c.Overrides[domain.Key()][category]
+	return override, ok
+}
+
+// SafetyFor returns the safety level of a target category, applying the
+// user's override to the cleaner's default
+func (c *Config) SafetyFor(domain Domain, category string, safety SafetyLevel) SafetyLevel {
+	if override, ok := c.Override(domain, category); ok && override.Safety != nil {
+		return *override.Safety
+	}
+	return safety
+}
+
+// OptedIn reports whether the user turned on a category that is off by
+// default, by setting "enabled": true for it in the config file
+func (c *Config) OptedIn(domain Domain, category string) bool {
+	if c.Policy.Forbids(domain, category) {
+		return false
+	}
+	override, ok := c.Override(domain, category)
+	return ok && override.Enabled != nil && *override.Enabled
+}
+
+// Retention returns how many of the newest items of a category to keep, if
+// the user set a retention policy ("keep": N) for it. Cleaners that support
+// one then offer the older items one by one instead of the whole folder.
+func (c *Config) Retention(domain Domain, category string) (int, bool) {
+	override, ok := c.Override(domain, category)
+	if !ok || override.Keep == nil {
+		return 0, false
+	}
+	return *override.Keep, true
+}
+
+// Allows reports whether targets of a category should be scanned at the
+// configured clean level, taking overrides into account. Cleaners use it
+// instead of CleanLevel.AllowsSafety so a category reclassified as Safe is
+// still found in conservative mode. Categories the admin policy forbids, or
+// that are left out of the selection, are never allowed.
+func (c *Config) Allows(domain Domain, category string, safety SafetyLevel) bool {
+	if c.Policy.Forbids(domain, category) || !c.Selection.Selects(domain, category) {
+		return false
+	}
+	if override, ok := c.Override(domain, category); ok && override.Enabled != nil && !*override.Enabled {
+		return false
 	}
+	return c.CleanLevel.AllowsSafety(c.SafetyFor(domain, category, safety))
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 )
 
 // =============================================================================
@@ -49,6 +50,39 @@ func TestSafetyLevel_Icon(t *testing.T) {
 	}
 }
 
+func TestParseSafetyLevel(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    SafetyLevel
+		expectError bool
+	}{
+		{"safe", Safe, false},
+		{"moderate", Moderate, false},
+		{"dangerous", Dangerous, false},
+		{"Safe", Safe, true}, // case sensitive
+		{"risky", Safe, true},
+		{"", Safe, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSafetyLevel(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseSafetyLevel(%q) expected error, got nil", tt.input)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("ParseSafetyLevel(%q) unexpected error: %v", tt.input, err)
+				}
+				if got != tt.expected {
+					t.Errorf("ParseSafetyLevel(%q) = %v, want %v", tt.input, got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
 // =============================================================================
 // CleanLevel Tests
 // =============================================================================
@@ -149,6 +183,11 @@ func TestDomain_String(t *testing.T) {
 	}{
 		{DomainSystem, "System"},
 		{DomainFrontend, "Frontend"},
+		{DomainBackend, "Backend"},
+		{DomainMobile, "Mobile"},
+		{DomainDevOps, "DevOps"},
+		{DomainDataML, "Data/ML"},
+		{DomainGameDev, "Game Dev"},
 		{Domain(99), "Unknown"},
 	}
 
@@ -161,6 +200,30 @@ func TestDomain_String(t *testing.T) {
 	}
 }
 
+func TestDomain_Key(t *testing.T) {
+	tests := []struct {
+		domain   Domain
+		expected string
+	}{
+		{DomainSystem, "system"},
+		{DomainFrontend, "frontend"},
+		{DomainBackend, "backend"},
+		{DomainMobile, "mobile"},
+		{DomainDevOps, "devops"},
+		{DomainDataML, "dataml"},
+		{DomainGameDev, "gamedev"},
+		{Domain(99), "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := tt.domain.Key(); got != tt.expected {
+				t.Errorf("Domain.Key() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 // =============================================================================
 // Config Tests
 // =============================================================================
@@ -196,6 +259,30 @@ func TestNewDefaultConfig(t *testing.T) {
 	if cfg.Verbose != false {
 		t.Errorf("Expected Verbose to be false, got %v", cfg.Verbose)
 	}
+
+	if cfg.ScanTimeout != 60*time.Second {
+		t.Errorf("Expected ScanTimeout to be 60s, got %v", cfg.ScanTimeout)
+	}
+
+	if cfg.ScanMaxDepth != 10 {
+		t.Errorf("Expected ScanMaxDepth to be 10, got %d", cfg.ScanMaxDepth)
+	}
+
+	if len(cfg.ScanExcludes) != 0 {
+		t.Errorf("Expected empty ScanExcludes, got %v", cfg.ScanExcludes)
+	}
+
+	if cfg.MavenMaxAgeDays != 90 {
+		t.Errorf("Expected MavenMaxAgeDays to be 90, got %d", cfg.MavenMaxAgeDays)
+	}
+
+	if cfg.ModelMaxAgeDays != 30 {
+		t.Errorf("Expected ModelMaxAgeDays to be 30, got %d", cfg.ModelMaxAgeDays)
+	}
+
+	if cfg.DatasetMaxAgeDays != 30 {
+		t.Errorf("Expected DatasetMaxAgeDays to be 30, got %d", cfg.DatasetMaxAgeDays)
+	}
 }
 
 func TestConfig_Modification(t *testing.T) {
@@ -237,11 +324,11 @@ func TestConfig_Modification(t *testing.T) {
 func TestConfigWithCleanLevel_Integration(t *testing.T) {
 	// Test that config levels work correctly with safety filtering
 	tests := []struct {
-		name            string
-		cleanLevel      CleanLevel
-		safeCount       int // number of Safe items that should be included
-		moderateCount   int // number of Moderate items that should be included
-		dangerousCount  int // number of Dangerous items that should be included
+		name           string
+		cleanLevel     CleanLevel
+		safeCount      int // number of Safe items that should be included
+		moderateCount  int // number of Moderate items that should be included
+		dangerousCount int // number of Dangerous items that should be included
 	}{
 		{"Conservative", Conservative, 1, 0, 0},
 		{"Standard", Standard, 1, 1, 0},
@@ -284,3 +371,107 @@ func TestConfigWithCleanLevel_Integration(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_Overrides(t *testing.T) {
+	safe := Safe
+	dangerous := Dangerous
+	disabled := false
+
+	cfg := NewDefaultConfig()
+	cfg.CleanLevel = Conservative
+	cfg.Overrides = map[string]map[string]Override{
+		"frontend": {
+			"node_modules": {Safety: &dangerous},
+			"npm_cache":    {Safety: &safe},
+		},
+		"system": {
+			"trash": {Enabled: &disabled},
+		},
+	}
+
+	if got := cfg.SafetyFor(DomainFrontend, "node_modules", Moderate); got != Dangerous {
+		t.Errorf("SafetyFor(node_modules) = %v, want Dangerous", got)
+	}
+	if got := cfg.SafetyFor(DomainBackend, "node_modules", Moderate); got != Moderate {
+		t.Errorf("SafetyFor() in another domain = %v, want default Moderate", got)
+	}
+
+	if !cfg.Allows(DomainFrontend, "npm_cache", Moderate) {
+		t.Error("Expected category reclassified as Safe to be allowed in conservative mode")
+	}
+	if cfg.Allows(DomainSystem, "trash", Safe) {
+		t.Error("Expected disabled category not to be allowed")
+	}
+
+	cfg.CleanLevel = Standard
+	if cfg.Allows(DomainFrontend, "node_modules", Moderate) {
+		t.Error("Expected category reclassified as Dangerous not to be allowed in standard mode")
+	}
+	if !cfg.Allows(DomainFrontend, "dist", Moderate) {
+		t.Error("Expected category without override to keep its default")
+	}
+}
+
+func TestConfig_OptedIn(t *testing.T) {
+	enabled := true
+	disabled := false
+
+	cfg := NewDefaultConfig()
+	cfg.Overrides = map[string]map[string]Override{
+		"system": {
+			"mail_downloads":       {Enabled: &enabled},
+			"messages_attachments": {Enabled: &disabled},
+			"music_cache":          {Safety: new(SafetyLevel)},
+		},
+	}
+
+	if !cfg.OptedIn(DomainSystem, "mail_downloads") {
+		t.Error("Expected category enabled in the config file to be opted in")
+	}
+	if cfg.OptedIn(DomainSystem, "messages_attachments") {
+		t.Error("Expected disabled category not to be opted in")
+	}
+	if cfg.OptedIn(DomainSystem, "music_cache") {
+		t.Error("Expected category with only a safety override not to be opted in")
+	}
+	if cfg.OptedIn(DomainSystem, "quicklook_thumbnails") {
+		t.Error("Expected category without override not to be opted in")
+	}
+}
+
+func TestWorkHours_Contains(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	day := WorkHours{Start: 9 * time.Hour, End: 18 * time.Hour, Days: weekdays}
+	night := WorkHours{Start: 22 * time.Hour, End: 6 * time.Hour, Days: []time.Weekday{time.Friday}}
+
+	// 2025-01-17 is a Friday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 1, day, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name  string
+		hours WorkHours
+		at    time.Time
+		want  bool
+	}{
+		{"friday morning", day, at(17, 10, 0), true},
+		{"friday at the start", day, at(17, 9, 0), true},
+		{"friday at the end", day, at(17, 18, 0), false},
+		{"friday before", day, at(17, 8, 59), false},
+		{"saturday", day, at(18, 10, 0), false},
+		{"friday night", night, at(17, 23, 0), true},
+		{"saturday early hours", night, at(18, 5, 0), true},
+		{"saturday night", night, at(18, 23, 0), false},
+		{"friday early hours", night, at(17, 5, 0), false},
+		{"friday evening", night, at(17, 20, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hours.Contains(tt.at); got != tt.want {
+				t.Errorf("Contains(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
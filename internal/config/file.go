@@ -0,0 +1,278 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// FileName is the name of the config file inside the state directory
+const FileName = "config.json"
+
+// file is the layout of the config file:
+//
+//	{
+//	  "cleaners": {
+//	    "frontend": {"node_modules": {"safety": "dangerous"}},
+//	    "system": {"ios_backups": {"enabled": false}},
+//	    "mobile": {"xcode_archives": {"keep": 2}}
+//	  },
+//	  "screenshots": {"max_age_days": 14, "archive_dir": "~/Pictures/Screenshots"},
+//	  "maven": {"max_age_days": 180},
+//	  "search_dirs": ["~/work", "~/src"],
+//	  "hooks": {
+//	    "pre_clean": {"command": "~/bin/backup.sh", "abort_on_failure": true},
+//	    "cleaners": {"DevOps": {"pre": {"command": "osascript -e 'quit app \"Docker\"'"}}}
+//	  },
+//	  "webhook": {"url": "https://hooks.slack.com/services/...", "format": "slack"},
+//	  "quarantine": {"enabled": true, "max_age_days": 14, "max_size": "20GB"},
+//	  "schedule": {
+//	    "require_ac_power": true,
+//	    "min_idle_minutes": 15,
+//	    "work_hours": {"start": "09:00", "end": "18:00", "days": ["mon", "tue", "wed", "thu", "fri"]}
+//	  },
+//	  "theme": "colorblind"
+//	}
+type file struct {
+	Cleaners map[string]map[string]struct {
+		Safety  string `json:"safety"`
+		Enabled *bool  `json:"enabled"`
+		Keep    *int   `json:"keep"`
+	} `json:"cleaners"`
+	Screenshots struct {
+		MaxAgeDays *int   `json:"max_age_days"`
+		ArchiveDir string `json:"archive_dir"`
+	} `json:"screenshots"`
+	Maven struct {
+		MaxAgeDays *int `json:"max_age_days"`
+	} `json:"maven"`
+	SearchDirs []string           `json:"search_dirs"`
+	Hooks      Hooks              `json:"hooks"`
+	Webhook    Webhook            `json:"webhook"`
+	Quarantine quarantineSettings `json:"quarantine"`
+	Schedule   struct {
+		RequireACPower bool `json:"require_ac_power"`
+		MinIdleMinutes int  `json:"min_idle_minutes"`
+		WorkHours      *struct {
+			Start string   `json:"start"`
+			End   string   `json:"end"`
+			Days  []string `json:"days"`
+		} `json:"work_hours"`
+	} `json:"schedule"`
+	Theme string `json:"theme"`
+}
+
+// quarantineSettings is the quarantine section of the config file. It was
+// once a bool, still accepted as "quarantine": true.
+type quarantineSettings struct {
+	Enabled    bool   `json:"enabled"`
+	MaxAgeDays *int   `json:"max_age_days"`
+	MaxSize    string `json:"max_size"`
+}
+
+// UnmarshalJSON reads the quarantine section, or a bool enabling it with the
+// default settings
+func (q *quarantineSettings) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &q.Enabled); err == nil {
+		return nil
+	}
+	type settings quarantineSettings
+	return json.Unmarshal(data, (*settings)(q))
+}
+
+// weekdays are the day names the work hours of the config file accept
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseClock parses a time of day such as "09:00" as the time after midnight
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day such as 09:00", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// FilePath returns the config file in the state directory
+func FilePath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Load returns the default config with the user's config file applied, and
+// the admin policy on top of it. The directories the policy excludes are
+// added to the scan excludes.
+func Load() (*Config, error) {
+	path, err := FilePath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Policy, err = LoadPolicy(PolicyFilePath())
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Policy != nil {
+		cfg.ScanExcludes = append(cfg.ScanExcludes, cfg.Policy.Excludes...)
+	}
+	return cfg, nil
+}
+
+// LoadFile returns the default config with the config file at path applied.
+// A missing file leaves the defaults untouched.
+func LoadFile(path string) (*Config, error) {
+	cfg := NewDefaultConfig()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	for domain, categories := range f.Cleaners {
+		for category, entry := range categories {
+			override := Override{Enabled: entry.Enabled, Keep: entry.Keep}
+			if entry.Safety != "" {
+				safety, err := ParseSafetyLevel(entry.Safety)
+				if err != nil {
+					return nil, fmt.Errorf("invalid config %s: cleaners.%s.%s: %w", path, domain, category, err)
+				}
+				override.Safety = &safety
+			}
+			if entry.Keep != nil && *entry.Keep < 0 {
+				return nil, fmt.Errorf("invalid config %s: cleaners.%s.%s.keep must not be negative", path, domain, category)
+			}
+
+			if cfg.Overrides[domain] == nil {
+				cfg.Overrides[domain] = make(map[string]Override)
+			}
+			cfg.Overrides[domain][category] = override
+		}
+	}
+
+	if maxAge := f.Screenshots.MaxAgeDays; maxAge != nil {
+		if *maxAge < 0 {
+			return nil, fmt.Errorf("invalid config %s: screenshots.max_age_days must not be negative", path)
+		}
+		cfg.ScreenshotMaxAgeDays = *maxAge
+	}
+	cfg.ScreenshotArchiveDir = f.Screenshots.ArchiveDir
+	if maxAge := f.Maven.MaxAgeDays; maxAge != nil {
+		if *maxAge < 0 {
+			return nil, fmt.Errorf("invalid config %s: maven.max_age_days must not be negative", path)
+		}
+		cfg.MavenMaxAgeDays = *maxAge
+	}
+	for _, dir := range f.SearchDirs {
+		expanded, err := utils.ExpandHome(dir)
+		if err != nil {
+			return nil, err
+		}
+		cfg.SearchDirs = append(cfg.SearchDirs, expanded)
+	}
+	cfg.Hooks = f.Hooks
+	cfg.Quarantine = f.Quarantine.Enabled
+	if maxAge := f.Quarantine.MaxAgeDays; maxAge != nil {
+		if *maxAge < 0 {
+			return nil, fmt.Errorf("invalid config %s: quarantine.max_age_days must not be negative", path)
+		}
+		cfg.QuarantineMaxAgeDays = *maxAge
+	}
+	if f.Quarantine.MaxSize != "" {
+		size, err := humanize.ParseBytes(f.Quarantine.MaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config %s: quarantine.max_size: %w", path, err)
+		}
+		cfg.QuarantineMaxBytes = int64(size)
+	}
+
+	switch f.Webhook.Format {
+	case "", "slack", "json":
+		cfg.Webhook = f.Webhook
+	default:
+		return nil, fmt.Errorf("invalid config %s: webhook.format must be slack or json", path)
+	}
+
+	cfg.Schedule.RequireACPower = f.Schedule.RequireACPower
+	if f.Schedule.MinIdleMinutes < 0 {
+		return nil, fmt.Errorf("invalid config %s: schedule.min_idle_minutes must not be negative", path)
+	}
+	cfg.Schedule.MinIdle = time.Duration(f.Schedule.MinIdleMinutes) * time.Minute
+	if hours := f.Schedule.WorkHours; hours != nil {
+		work := WorkHours{Days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}}
+		if work.Start, err = parseClock(hours.Start); err != nil {
+			return nil, fmt.Errorf("invalid config %s: schedule.work_hours.start: %w", path, err)
+		}
+		if work.End, err = parseClock(hours.End); err != nil {
+			return nil, fmt.Errorf("invalid config %s: schedule.work_hours.end: %w", path, err)
+		}
+		if hours.Days != nil {
+			work.Days = nil
+			for _, name := range hours.Days {
+				day, ok := weekdays[strings.ToLower(name)]
+				if !ok {
+					return nil, fmt.Errorf("invalid config %s: schedule.work_hours.days: %q is not a day such as mon", path, name)
+				}
+				work.Days = append(work.Days, day)
+			}
+		}
+		cfg.Schedule.WorkHours = &work
+	}
+
+	cfg.Theme = f.Theme
+
+	return cfg, nil
+}
+
+// SaveSearchDirs sets the search_dirs of the config file at path, keeping
+// everything else in it. The file is created if needed.
+func SaveSearchDirs(path string, dirs []string) error {
+	settings := map[string]json.RawMessage{}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+
+	settings["search_dirs"], err = json.Marshal(dirs)
+	if err != nil {
+		return err
+	}
+	data, err = json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
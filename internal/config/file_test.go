@@ -0,0 +1,273 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Config File Tests
+// =============================================================================
+
+func TestLoadFile_Missing(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+
+	if len(cfg.Overrides) != 0 {
+		t.Errorf("Expected no overrides, got %v", cfg.Overrides)
+	}
+}
+
+func TestLoadFile_Overrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	data := `{
+  "cleaners": {
+    "frontend": {"node_modules": {"safety": "dangerous"}},
+    "system": {"ios_backups": {"enabled": false}}
+  }
+}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+
+	override, ok := cfg.Override(DomainFrontend, "node_modules")
+	if !ok || override.Safety == nil || *override.Safety != Dangerous {
+		t.Errorf("Expected node_modules to be Dangerous, got %+v", override)
+	}
+	if override.Enabled != nil {
+		t.Error("Expected Enabled to stay unset")
+	}
+
+	override, ok = cfg.Override(DomainSystem, "ios_backups")
+	if !ok || override.Enabled == nil || *override.Enabled {
+		t.Errorf("Expected ios_backups to be disabled, got %+v", override)
+	}
+
+	// Defaults are kept
+	if cfg.CleanLevel != Standard || cfg.MavenMaxAgeDays != 90 {
+		t.Error("Expected defaults to be kept")
+	}
+}
+
+func TestLoadFile_Screenshots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	data := `{"screenshots": {"max_age_days": 14, "archive_dir": "~/Pictures/Screenshots"}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+	if cfg.ScreenshotMaxAgeDays != 14 || cfg.ScreenshotArchiveDir != "~/Pictures/Screenshots" {
+		t.Errorf("Expected screenshots settings to be read, got %d and %q", cfg.ScreenshotMaxAgeDays, cfg.ScreenshotArchiveDir)
+	}
+}
+
+func TestLoadFile_Maven(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(`{"maven": {"max_age_days": 0}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+	if cfg.MavenMaxAgeDays != 0 {
+		t.Errorf("Expected the whole Maven repository to be cleaned, got max age %d", cfg.MavenMaxAgeDays)
+	}
+}
+
+func TestLoadFile_Retention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	data := `{"cleaners": {"devops": {"vagrant_boxes": {"keep": 3}}}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+	if keep, ok := cfg.Retention(DomainDevOps, "vagrant_boxes"); !ok || keep != 3 {
+		t.Errorf("Expected to keep 3 Vagrant boxes, got %d (%v)", keep, ok)
+	}
+	if _, ok := cfg.Retention(DomainMobile, "xcode_archives"); ok {
+		t.Error("Expected no retention policy for Xcode archives")
+	}
+}
+
+func TestLoadFile_Hooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	data := `{"hooks": {
+  "pre_clean": {"command": "backup.sh", "abort_on_failure": true},
+  "cleaners": {"DevOps": {"pre": {"command": "docker-stop"}, "post": {"command": "docker-start"}}}
+}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+	if cfg.Hooks.PreClean != (Hook{Command: "backup.sh", AbortOnFailure: true}) {
+		t.Errorf("Unexpected pre_clean hook %+v", cfg.Hooks.PreClean)
+	}
+	if cfg.Hooks.PostClean.Command != "" {
+		t.Error("Expected no post_clean hook")
+	}
+	if devops := cfg.Hooks.Cleaners["DevOps"]; devops.Pre.Command != "docker-stop" || devops.Post.Command != "docker-start" {
+		t.Errorf("Unexpected DevOps hooks %+v", devops)
+	}
+}
+
+func TestLoadFile_Quarantine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	data := `{"quarantine": {"enabled": true, "max_age_days": 14, "max_size": "20GB"}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+	if !cfg.Quarantine || cfg.QuarantineMaxAgeDays != 14 || cfg.QuarantineMaxBytes != 20_000_000_000 {
+		t.Errorf("Expected quarantine settings to be read, got %v, %d and %d", cfg.Quarantine, cfg.QuarantineMaxAgeDays, cfg.QuarantineMaxBytes)
+	}
+
+	// The bool of older config files
+	if err := os.WriteFile(path, []byte(`{"quarantine": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+	if !cfg.Quarantine || cfg.QuarantineMaxAgeDays != NewDefaultConfig().QuarantineMaxAgeDays {
+		t.Errorf("Expected the quarantine enabled with default settings, got %v and %d", cfg.Quarantine, cfg.QuarantineMaxAgeDays)
+	}
+}
+
+func TestLoadFile_Schedule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	data := `{"schedule": {"require_ac_power": true, "min_idle_minutes": 15, "work_hours": {"start": "09:30", "end": "18:00"}}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+	if !cfg.Schedule.RequireACPower || cfg.Schedule.MinIdle != 15*time.Minute {
+		t.Errorf("Expected schedule conditions to be read, got %+v", cfg.Schedule)
+	}
+	hours := cfg.Schedule.WorkHours
+	if hours == nil || hours.Start != 9*time.Hour+30*time.Minute || hours.End != 18*time.Hour {
+		t.Fatalf("Unexpected work hours %+v", hours)
+	}
+	if len(hours.Days) != 5 || hours.Days[0] != time.Monday || hours.Days[4] != time.Friday {
+		t.Errorf("Expected work hours on weekdays by default, got %v", hours.Days)
+	}
+}
+
+func TestLoadFile_Theme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(`{"theme": "colorblind"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+	if cfg.Theme != "colorblind" {
+		t.Errorf("Expected theme colorblind, got %q", cfg.Theme)
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"malformed json", `{"cleaners": `},
+		{"unknown safety", `{"cleaners": {"frontend": {"node_modules": {"safety": "risky"}}}}`},
+		{"negative screenshot age", `{"screenshots": {"max_age_days": -1}}`},
+		{"negative Maven age", `{"maven": {"max_age_days": -1}}`},
+		{"negative keep", `{"cleaners": {"mobile": {"xcode_archives": {"keep": -1}}}}`},
+		{"unknown webhook format", `{"webhook": {"url": "https://example.com", "format": "xml"}}`},
+		{"negative quarantine age", `{"quarantine": {"max_age_days": -1}}`},
+		{"invalid quarantine size", `{"quarantine": {"max_size": "lots"}}`},
+		{"negative idle time", `{"schedule": {"min_idle_minutes": -5}}`},
+		{"invalid work hours", `{"schedule": {"work_hours": {"start": "9am", "end": "18:00"}}}`},
+		{"unknown work day", `{"schedule": {"work_hours": {"start": "09:00", "end": "18:00", "days": ["monday"]}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := LoadFile(path); err == nil {
+				t.Error("Expected error for invalid config")
+			}
+		})
+	}
+}
+
+func TestLoad_UsesStateDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("EPURER_HOME", dir)
+
+	data := `{"cleaners": {"devops": {"docker_volumes": {"enabled": false}}}}`
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	if _, ok := cfg.Override(DomainDevOps, "docker_volumes"); !ok {
+		t.Error("Expected override from the state directory config")
+	}
+}
+
+func TestSaveSearchDirs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(`{"screenshots": {"max_age_days": 14}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SaveSearchDirs(path, []string{"/src", "/work"}); err != nil {
+		t.Fatalf("SaveSearchDirs() returned error: %v", err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() returned error: %v", err)
+	}
+	if len(cfg.SearchDirs) != 2 || cfg.SearchDirs[1] != "/work" {
+		t.Errorf("Expected the search dirs saved, got %v", cfg.SearchDirs)
+	}
+	if cfg.ScreenshotMaxAgeDays != 14 {
+		t.Errorf("Expected the rest of the file kept, got max age %d", cfg.ScreenshotMaxAgeDays)
+	}
+}
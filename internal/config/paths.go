@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// HomeDir returns the home directory cleaners scan: Home when set, so tests
+// can point every cleaner at a fake home, and the user's otherwise
+func (c *Config) HomeDir() (string, error) {
+	if c.Home != "" {
+		return c.Home, nil
+	}
+	return os.UserHomeDir()
+}
+
+// StateDir returns the directory where epurer keeps its own files (run
+// history, plans). It defaults to ~/.epurer and can be moved with the
+// EPURER_HOME environment variable.
+func StateDir() (string, error) {
+	if dir := os.Getenv("EPURER_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".epurer"), nil
+}
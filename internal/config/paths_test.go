@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// =============================================================================
+// StateDir Tests
+// =============================================================================
+
+func TestStateDir_Default(t *testing.T) {
+	t.Setenv("EPURER_HOME", "")
+
+	dir, err := StateDir()
+	if err != nil {
+		t.Fatalf("StateDir() returned error: %v", err)
+	}
+
+	home, _ := os.UserHomeDir()
+	if dir != filepath.Join(home, ".epurer") {
+		t.Errorf("StateDir() = %v, want ~/.epurer", dir)
+	}
+}
+
+func TestStateDir_Override(t *testing.T) {
+	t.Setenv("EPURER_HOME", "/tmp/epurer-state")
+
+	dir, err := StateDir()
+	if err != nil {
+		t.Fatalf("StateDir() returned error: %v", err)
+	}
+
+	if dir != "/tmp/epurer-state" {
+		t.Errorf("StateDir() = %v, want /tmp/epurer-state", dir)
+	}
+}
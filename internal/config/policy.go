@@ -0,0 +1,148 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// PolicyPath is where an MDM profile installs the admin policy
+const PolicyPath = "/Library/Managed Preferences/epurer-policy.json"
+
+// Policy is set by the admins of a fleet of machines. It takes precedence
+// over the user's config and flags:
+//
+//	{
+//	  "max_clean_level": "standard",
+//	  "forbidden": ["system.ios_backups", "dataml"],
+//	  "exclude": ["~/Work/legal-hold", "/Volumes/Shared"]
+//	}
+type Policy struct {
+	Path          string      // File the policy was read from
+	MaxCleanLevel *CleanLevel // Highest clean level allowed, if set
+	Forbidden     []string    // Domain keys, or "domain.category", never cleaned
+	Excludes      []string    // Paths never scanned nor cleaned, "~" being each user's home
+}
+
+// PolicyFilePath returns the policy file: PolicyPath, or the file set by the
+// EPURER_POLICY environment variable if root owns it. Users can set the
+// variable, so it can't point to a policy of their own.
+func PolicyFilePath() string {
+	if path := os.Getenv("EPURER_POLICY"); path != "" && ownedByRoot(path) {
+		return path
+	}
+	return PolicyPath
+}
+
+// ownedByRoot reports whether path exists and belongs to root
+func ownedByRoot(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	uid, _, ok := utils.FileOwner(info)
+	return ok && uid == 0
+}
+
+// LoadPolicy reads the policy at path. A missing file means no policy (nil).
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f struct {
+		MaxCleanLevel string   `json:"max_clean_level"`
+		Forbidden     []string `json:"forbidden"`
+		Exclude       []string `json:"exclude"`
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+
+	p := &Policy{Path: path, Forbidden: f.Forbidden}
+	if f.MaxCleanLevel != "" {
+		level, err := ParseCleanLevel(f.MaxCleanLevel)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy %s: max_clean_level: %w", path, err)
+		}
+		p.MaxCleanLevel = &level
+	}
+	for _, exclude := range f.Exclude {
+		if !strings.HasPrefix(exclude, "/") && !strings.HasPrefix(exclude, "~") {
+			return nil, fmt.Errorf("invalid policy %s: exclude %q must be a path starting with / or ~", path, exclude)
+		}
+		expanded, err := utils.ExpandHome(exclude)
+		if err != nil {
+			return nil, err
+		}
+		p.Excludes = append(p.Excludes, filepath.Clean(expanded))
+	}
+
+	return p, nil
+}
+
+// Forbids reports whether the policy forbids cleaning a target category,
+// alone or with its whole domain. A nil policy forbids nothing.
+func (p *Policy) Forbids(domain Domain, category string) bool {
+	if p == nil {
+		return false
+	}
+	for _, forbidden := range p.Forbidden {
+		if forbidden == domain.Key() || forbidden == domain.Key()+"."+category {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsSafety reports whether the policy's maximum clean level allows a
+// safety level
+func (p *Policy) AllowsSafety(safety SafetyLevel) bool {
+	return p == nil || p.MaxCleanLevel == nil || p.MaxCleanLevel.AllowsSafety(safety)
+}
+
+// ExcludesPath reports whether path is or lies inside a directory the policy
+// excludes, whatever the case and Unicode normalization of their names
+func (p *Policy) ExcludesPath(path string) bool {
+	if p == nil {
+		return false
+	}
+	for _, exclude := range p.Excludes {
+		if utils.HasPathPrefix(path, exclude) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExcludesWithin reports whether a directory the policy excludes lies inside
+// path, so that removing path would remove it too
+func (p *Policy) ExcludesWithin(path string) bool {
+	if p == nil {
+		return false
+	}
+	for _, exclude := range p.Excludes {
+		if utils.HasPathPrefix(exclude, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyPolicy lowers the clean level to the policy's maximum, once flags have
+// set it. It reports whether the level was lowered.
+func (c *Config) ApplyPolicy() bool {
+	if c.Policy == nil || c.Policy.MaxCleanLevel == nil || c.CleanLevel <= *c.Policy.MaxCleanLevel {
+		return false
+	}
+	c.CleanLevel = *c.Policy.MaxCleanLevel
+	return true
+}
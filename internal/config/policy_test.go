@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// =============================================================================
+// Policy Tests
+// =============================================================================
+
+func writePolicy(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "epurer-policy.json")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := writePolicy(t, `{
+  "max_clean_level": "standard",
+  "forbidden": ["system.ios_backups", "dataml"],
+  "exclude": ["/Volumes/Shared"]
+}`)
+
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy() returned error: %v", err)
+	}
+	if p.MaxCleanLevel == nil || *p.MaxCleanLevel != Standard {
+		t.Errorf("Expected max clean level standard, got %v", p.MaxCleanLevel)
+	}
+
+	if !p.Forbids(DomainSystem, "ios_backups") || !p.Forbids(DomainDataML, "conda_pkgs") {
+		t.Error("Expected forbidden category and domain")
+	}
+	if p.Forbids(DomainSystem, "trash") {
+		t.Error("Expected other system categories to be allowed")
+	}
+
+	if !p.ExcludesPath("/Volumes/Shared/app/node_modules") || p.ExcludesPath("/Volumes/SharedOther") {
+		t.Error("Expected paths under the exclude, and only those, to be excluded")
+	}
+	if !p.ExcludesWithin("/Volumes") || !p.ExcludesWithin("/Volumes/Shared") || p.ExcludesWithin("/Volumes/Shared/app") {
+		t.Error("Expected the folders holding the exclude, and only those, to contain it")
+	}
+	if p.AllowsSafety(Dangerous) || !p.AllowsSafety(Moderate) {
+		t.Error("Expected the max clean level to bound safety levels")
+	}
+}
+
+func TestLoadPolicy_Missing(t *testing.T) {
+	p, err := LoadPolicy(filepath.Join(t.TempDir(), "epurer-policy.json"))
+	if err != nil || p != nil {
+		t.Fatalf("Expected no policy, got %v (%v)", p, err)
+	}
+	if p.Forbids(DomainSystem, "ios_backups") || !p.AllowsSafety(Dangerous) || p.ExcludesPath("/") {
+		t.Error("Expected a nil policy to allow everything")
+	}
+}
+
+func TestPolicyFilePath(t *testing.T) {
+	path := writePolicy(t, `{"max_clean_level": "conservative"}`)
+	t.Setenv("EPURER_POLICY", path)
+
+	// A policy users could write themselves is ignored
+	want := PolicyPath
+	if os.Getuid() == 0 && runtime.GOOS != "windows" {
+		want = path
+	}
+	if got := PolicyFilePath(); got != want {
+		t.Errorf("PolicyFilePath() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadPolicy_Invalid(t *testing.T) {
+	for _, data := range []string{
+		`{"max_clean_level": "reckless"}`,
+		`{"exclude": ["node_modules"]}`,
+		`{`,
+	} {
+		if _, err := LoadPolicy(writePolicy(t, data)); err == nil {
+			t.Errorf("Expected an error for %s", data)
+		}
+	}
+}
+
+func TestConfig_Policy(t *testing.T) {
+	level := Standard
+	cfg := NewDefaultConfig()
+	cfg.CleanLevel = Aggressive
+	cfg.Policy = &Policy{MaxCleanLevel: &level, Forbidden: []string{"system.ios_backups"}}
+
+	enabled := true
+	cfg.Overrides["system"] = map[string]Override{"ios_backups": {Enabled: &enabled}}
+
+	if !cfg.ApplyPolicy() || cfg.CleanLevel != Standard {
+		t.Errorf("Expected the clean level lowered to standard, got %s", cfg.CleanLevel)
+	}
+	if cfg.ApplyPolicy() {
+		t.Error("Expected the clean level to be lowered only once")
+	}
+	if cfg.OptedIn(DomainSystem, "ios_backups") || cfg.Allows(DomainSystem, "ios_backups", Safe) {
+		t.Error("Expected the policy to win over the user's config")
+	}
+}
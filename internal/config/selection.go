@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SelectionEntry picks a whole domain, one category in every domain, or one
+// category in one domain
+type SelectionEntry struct {
+	Domain   string // Domain key, any domain if empty
+	Category string // Target category, any category if empty
+}
+
+// String returns the entry the way it is written on the command line
+func (e SelectionEntry) String() string {
+	switch {
+	case e.Category == "":
+		return e.Domain
+	case e.Domain == "":
+		return e.Category
+	default:
+		return e.Domain + "." + e.Category
+	}
+}
+
+// Selection narrows a run to some domains and target categories. An empty
+// selection selects everything.
+type Selection []SelectionEntry
+
+// domainKeys are the keys of the domains, as written in selections
+var domainKeys = map[string]bool{
+	DomainSystem.Key():   true,
+	DomainFrontend.Key(): true,
+	DomainBackend.Key():  true,
+	DomainMobile.Key():   true,
+	DomainDevOps.Key():   true,
+	DomainDataML.Key():   true,
+	DomainGameDev.Key():  true,
+}
+
+// ParseSelectionEntry parses a domain key ("backend"), a category
+// ("node_modules") or both ("frontend.npm_cache"). Categories may be written
+// with dashes, e.g. "npm-cache".
+func ParseSelectionEntry(s string) (SelectionEntry, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "data/ml" {
+		s = DomainDataML.Key()
+	}
+	if domainKeys[s] {
+		return SelectionEntry{Domain: s}, nil
+	}
+
+	entry := SelectionEntry{Category: s}
+	if domain, category, ok := strings.Cut(s, "."); ok {
+		if !domainKeys[domain] {
+			return SelectionEntry{}, fmt.Errorf("invalid selection %q: unknown domain %s", s, domain)
+		}
+		entry = SelectionEntry{Domain: domain, Category: category}
+	}
+
+	entry.Category = strings.ReplaceAll(entry.Category, "-", "_")
+	if entry.Category == "" {
+		return SelectionEntry{}, fmt.Errorf("invalid selection %q: missing category", s)
+	}
+	return entry, nil
+}
+
+// Selects reports whether the selection includes the targets of a category
+func (s Selection) Selects(domain Domain, category string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, entry := range s {
+		if (entry.Domain == "" || entry.Domain == domain.Key()) && (entry.Category == "" || entry.Category == category) {
+			return true
+		}
+	}
+	return false
+}
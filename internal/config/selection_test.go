@@ -0,0 +1,57 @@
+package config
+
+import "testing"
+
+func TestParseSelectionEntry(t *testing.T) {
+	tests := []struct {
+		input string
+		want  SelectionEntry
+	}{
+		{"backend", SelectionEntry{Domain: "backend"}},
+		{"Data/ML", SelectionEntry{Domain: "dataml"}},
+		{"node_modules", SelectionEntry{Category: "node_modules"}},
+		{"gradle-cache", SelectionEntry{Category: "gradle_cache"}},
+		{"frontend.npm-cache", SelectionEntry{Domain: "frontend", Category: "npm_cache"}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSelectionEntry(tt.input)
+		if err != nil {
+			t.Fatalf("ParseSelectionEntry(%q) returned error: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("ParseSelectionEntry(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, invalid := range []string{"web.npm_cache", "frontend.", ""} {
+		if _, err := ParseSelectionEntry(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestSelection_Selects(t *testing.T) {
+	selection := Selection{{Domain: "backend"}, {Domain: "frontend", Category: "npm_cache"}, {Category: "node_modules"}}
+
+	tests := []struct {
+		domain   Domain
+		category string
+		want     bool
+	}{
+		{DomainBackend, "pip_cache", true},
+		{DomainFrontend, "npm_cache", true},
+		{DomainFrontend, "yarn_cache", false},
+		{DomainMobile, "node_modules", true},
+		{DomainSystem, "npm_cache", false},
+	}
+
+	for _, tt := range tests {
+		if got := selection.Selects(tt.domain, tt.category); got != tt.want {
+			t.Errorf("Selects(%s, %s) = %v, want %v", tt.domain.Key(), tt.category, got, tt.want)
+		}
+	}
+	if !(Selection{}).Selects(DomainSystem, "trash") {
+		t.Error("Expected an empty selection to select everything")
+	}
+}
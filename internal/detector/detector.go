@@ -1,8 +1,15 @@
 package detector
 
 import (
+	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/0SansNom/epurer/pkg/utils"
 )
@@ -10,17 +17,136 @@ import (
 // StackDetector detects installed development tools and frameworks
 type StackDetector struct {
 	homePath string
+
+	// Runs a command and returns its output, to read tool versions (exec
+	// with a timeout when nil)
+	run func(name string, args ...string) ([]byte, error)
+
+	mu     sync.Mutex
+	result *DetectionResult // Memoized by DetectAll, nil until then or after Refresh
+
+	lookMu   sync.Mutex
+	commands map[string]string // Commands looked for so far, with the path found ("" if none)
+	paths    map[string]bool   // Folders and files looked for so far, and whether they exist
+}
+
+// Tool is a detected development tool. Version, Footprint and Reclaimable
+// are only filled by DetectDetailed.
+type Tool struct {
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	Path        string `json:"path,omitempty"`        // Command or folder it was found at
+	Footprint   int64  `json:"footprint,omitempty"`   // Bytes of its caches and data below home
+	Reclaimable int64  `json:"reclaimable,omitempty"` // Bytes of its caches, which can be cleaned now
 }
 
 // DetectionResult contains all detected tools organized by category
 type DetectionResult struct {
-	Frontend []string // Node.js, npm, yarn, pnpm, etc.
-	Backend  []string // Python, Java, Go, Rust, PHP, Ruby
-	Mobile   []string // Xcode, Android Studio, Flutter
-	DevOps   []string // Docker, Kubernetes, Terraform, Helm
-	DataML   []string // Conda, Jupyter, TensorFlow, PyTorch
+	Frontend []Tool // Node.js, npm, yarn, pnpm, etc.
+	Backend  []Tool // Python, Java, Go, Rust, PHP, Ruby
+	Mobile   []Tool // Xcode, Android Studio, Flutter
+	DevOps   []Tool // Docker, Kubernetes, Terraform, Helm
+	DataML   []Tool // Conda, Jupyter, TensorFlow, PyTorch
+}
+
+// Category is one category of a DetectionResult, with its label
+type Category struct {
+	Label string
+	Tools []Tool
 }
 
+// Categories returns the categories of the result, in display order
+func (r DetectionResult) Categories() []Category {
+	return []Category{
+		{"Frontend", r.Frontend},
+		{"Backend", r.Backend},
+		{"Mobile", r.Mobile},
+		{"DevOps", r.DevOps},
+		{"Data/ML", r.DataML},
+	}
+}
+
+// toolSpec tells how to find a tool, read its version and measure the
+// folders it fills
+type toolSpec struct {
+	name          string
+	commands      []string // Commands one of which marks the tool installed
+	paths         []string // Alternatively, folders one of which does (below home if relative)
+	pythonPackage string   // Alternatively, a Python package installed for the user
+	version       []string // Command printing its version, if not the command found with --version
+	caches        []string // Caches below home, reclaimable now
+	data          []string // Other folders below home it fills (installs, images, SDKs)
+}
+
+// frontendTools, backendTools, mobileTools, devopsTools and dataMLTools are
+// the tools looked for in each category
+var (
+	frontendTools = []toolSpec{
+		{name: "node", commands: []string{"node"}},
+		{name: "npm", commands: []string{"npm"}, caches: []string{".npm", "AppData/Local/npm-cache"}},
+		{name: "yarn", commands: []string{"yarn"}, caches: []string{".cache/yarn", "Library/Caches/Yarn", "AppData/Local/Yarn/Cache", ".yarn/berry/cache"}},
+		{name: "pnpm", commands: []string{"pnpm"}, caches: []string{".pnpm-store", "Library/pnpm/store", ".local/share/pnpm/store", "AppData/Local/pnpm/store"}},
+		{name: "bun", commands: []string{"bun"}, caches: []string{".bun/install/cache"}},
+		{name: "deno", commands: []string{"deno"}, caches: []string{"Library/Caches/deno", ".cache/deno"}},
+	}
+
+	backendTools = []toolSpec{
+		{name: "python", commands: []string{"python3", "python"}, data: []string{".pyenv/versions"}},
+		{name: "java", commands: []string{"java"}, version: []string{"java", "-version"}},
+		{name: "go", commands: []string{"go"}, version: []string{"go", "version"},
+			caches: []string{"Library/Caches/go-build", ".cache/go-build", "AppData/Local/go-build", "go/pkg/mod"}},
+		{name: "rust", commands: []string{"cargo"}, caches: []string{".cargo/registry", ".cargo/git"}, data: []string{".rustup/toolchains"}},
+		{name: "php", commands: []string{"php"}, caches: []string{".composer/cache", "Library/Caches/composer", ".cache/composer"}},
+		{name: "ruby", commands: []string{"ruby"}, data: []string{".gem", ".rbenv/versions"}},
+		{name: ".net", commands: []string{"dotnet"}, caches: []string{".nuget/packages"}},
+		{name: "maven", commands: []string{"mvn"}, caches: []string{".m2/repository"}},
+		{name: "gradle", commands: []string{"gradle"}, caches: []string{".gradle/caches", ".gradle/wrapper"}},
+	}
+
+	mobileTools = []toolSpec{
+		{name: "xcode", paths: []string{"/Applications/Xcode.app"}, version: []string{"xcodebuild", "-version"},
+			caches: []string{"Library/Developer/Xcode/DerivedData", "Library/Developer/Xcode/iOS DeviceSupport"},
+			data:   []string{"Library/Developer/CoreSimulator", "Library/Developer/Xcode/Archives"}},
+		{name: "android", commands: []string{"adb"}, paths: []string{"Library/Android"},
+			caches: []string{".android/cache"}, data: []string{"Library/Android/sdk", ".android/avd"}},
+		{name: "flutter", commands: []string{"flutter"}, caches: []string{".pub-cache"}},
+		{name: "cocoapods", commands: []string{"pod"}, caches: []string{"Library/Caches/CocoaPods"}},
+	}
+
+	devopsTools = []toolSpec{
+		{name: "docker", commands: []string{"docker"}, data: []string{"Library/Containers/com.docker.docker"}},
+		{name: "podman", commands: []string{"podman"}, data: []string{".local/share/containers"}},
+		{name: "containerd", commands: []string{"nerdctl"}},
+		{name: "kubernetes", commands: []string{"kubectl"}, version: []string{"kubectl", "version", "--client"}, caches: []string{".kube/cache"}},
+		{name: "terraform", commands: []string{"terraform"}, caches: []string{".terraform.d/plugin-cache"}},
+		{name: "helm", commands: []string{"helm"}, version: []string{"helm", "version", "--short"},
+			caches: []string{"Library/Caches/helm", ".cache/helm"}},
+		{name: "minikube", commands: []string{"minikube"}, version: []string{"minikube", "version"},
+			caches: []string{".minikube/cache"}, data: []string{".minikube/machines"}},
+		{name: "vagrant", commands: []string{"vagrant"}, data: []string{".vagrant.d/boxes"}},
+		{name: "aws-cli", commands: []string{"aws"}, caches: []string{".aws/cli/cache"}},
+		{name: "gcloud", commands: []string{"gcloud"}},
+		{name: "azure-cli", commands: []string{"az"}},
+	}
+
+	dataMLTools = []toolSpec{
+		{name: "conda", commands: []string{"conda"}, caches: []string{"miniconda3/pkgs", "anaconda3/pkgs", "miniforge3/pkgs"}},
+		{name: "jupyter", commands: []string{"jupyter"}},
+		{name: "pip", commands: []string{"pip3", "pip"}, caches: []string{"Library/Caches/pip", ".cache/pip", "AppData/Local/pip/Cache"}},
+		// Common ML frameworks, found by their Python package
+		{name: "tensorflow", pythonPackage: "tensorflow", caches: []string{".keras/datasets"}},
+		{name: "pytorch", pythonPackage: "torch", caches: []string{".cache/torch"}},
+	}
+)
+
+// versionTimeout bounds each command run to read a version, since some
+// (flutter, gcloud) start slowly or may hang
+const versionTimeout = 10 * time.Second
+
+// versionRe matches the first version number in a command's output, e.g.
+// "20.11.0" in "v20.11.0" or "1.22.0" in "go version go1.22.0 darwin/arm64"
+var versionRe = regexp.MustCompile(`\d+\.\d+(?:\.\d+)*`)
+
 // NewDetector creates a new StackDetector
 func NewDetector() (*StackDetector, error) {
 	home, err := os.UserHomeDir()
@@ -33,138 +159,243 @@ func NewDetector() (*StackDetector, error) {
 	}, nil
 }
 
-// DetectAll detects all development tools on the system
+// DetectAll detects all development tools on the system, with the path
+// each was found at. The result is worked out once and reused by later
+// calls, HasFrontend and the like included, until Refresh.
 func (d *StackDetector) DetectAll() DetectionResult {
-	result := DetectionResult{
-		Frontend: []string{},
-		Backend:  []string{},
-		Mobile:   []string{},
-		DevOps:   []string{},
-		DataML:   []string{},
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.result == nil {
+		d.result = &DetectionResult{
+			Frontend: d.detect(frontendTools),
+			Backend:  d.detect(backendTools),
+			Mobile:   d.detect(mobileTools),
+			DevOps:   d.detect(devopsTools),
+			DataML:   d.detect(dataMLTools),
+		}
+	}
+	// Copies, so callers can't change the result others get
+	return DetectionResult{
+		Frontend: slices.Clone(d.result.Frontend),
+		Backend:  slices.Clone(d.result.Backend),
+		Mobile:   slices.Clone(d.result.Mobile),
+		DevOps:   slices.Clone(d.result.DevOps),
+		DataML:   slices.Clone(d.result.DataML),
 	}
+}
 
-	// === Frontend Detection ===
+// Refresh forgets the tools detected so far and detects them again, for
+// when tools may have been installed or removed since
+func (d *StackDetector) Refresh() DetectionResult {
+	d.mu.Lock()
+	d.result = nil
+	d.mu.Unlock()
+
+	d.lookMu.Lock()
+	d.commands, d.paths = nil, nil
+	d.lookMu.Unlock()
+	return d.DetectAll()
+}
 
-	if utils.CommandExists("node") {
-		result.Frontend = append(result.Frontend, "node")
-	}
-	if utils.CommandExists("npm") {
-		result.Frontend = append(result.Frontend, "npm")
-	}
-	if utils.CommandExists("yarn") {
-		result.Frontend = append(result.Frontend, "yarn")
-	}
-	if utils.CommandExists("pnpm") {
-		result.Frontend = append(result.Frontend, "pnpm")
-	}
-	if utils.CommandExists("bun") {
-		result.Frontend = append(result.Frontend, "bun")
+// HasCommand reports whether one of commands is on PATH. Like DetectAll,
+// it looks for each command once until Refresh, so the cleaners checking
+// for their tools and DetectAll share what was found.
+func (d *StackDetector) HasCommand(commands ...string) bool {
+	for _, command := range commands {
+		if _, ok := d.lookPath(command); ok {
+			return true
+		}
 	}
-	if utils.CommandExists("deno") {
-		result.Frontend = append(result.Frontend, "deno")
+	return false
+}
+
+// HasPath reports whether one of paths exists, looking for each once until
+// Refresh
+func (d *StackDetector) HasPath(paths ...string) bool {
+	for _, path := range paths {
+		if d.exists(path) {
+			return true
+		}
 	}
+	return false
+}
 
-	// === Backend Detection ===
+// lookPath returns the path of command on PATH, remembered from the first
+// time it was looked for
+func (d *StackDetector) lookPath(command string) (string, bool) {
+	d.lookMu.Lock()
+	defer d.lookMu.Unlock()
 
-	if utils.CommandExists("python3") || utils.CommandExists("python") {
-		result.Backend = append(result.Backend, "python")
-	}
-	if utils.CommandExists("java") {
-		result.Backend = append(result.Backend, "java")
-	}
-	if utils.CommandExists("go") {
-		result.Backend = append(result.Backend, "go")
-	}
-	if utils.CommandExists("cargo") {
-		result.Backend = append(result.Backend, "rust")
-	}
-	if utils.CommandExists("php") {
-		result.Backend = append(result.Backend, "php")
-	}
-	if utils.CommandExists("ruby") {
-		result.Backend = append(result.Backend, "ruby")
+	if path, ok := d.commands[command]; ok {
+		return path, path != ""
 	}
-	if utils.CommandExists("dotnet") {
-		result.Backend = append(result.Backend, ".net")
+	if d.commands == nil {
+		d.commands = make(map[string]string)
 	}
-	if utils.CommandExists("mvn") {
-		result.Backend = append(result.Backend, "maven")
+	path, _ := exec.LookPath(command)
+	d.commands[command] = path
+	return path, path != ""
+}
+
+// exists reports whether path exists, remembered from the first time it was
+// looked for
+func (d *StackDetector) exists(path string) bool {
+	d.lookMu.Lock()
+	defer d.lookMu.Unlock()
+
+	if found, ok := d.paths[path]; ok {
+		return found
 	}
-	if utils.CommandExists("gradle") {
-		result.Backend = append(result.Backend, "gradle")
+	if d.paths == nil {
+		d.paths = make(map[string]bool)
 	}
+	d.paths[path] = utils.PathExists(path)
+	return d.paths[path]
+}
 
-	// === Mobile Detection ===
+// detectorKey is the context key of the detector set by WithDetector
+type detectorKey struct{}
 
-	if utils.PathExists("/Applications/Xcode.app") {
-		result.Mobile = append(result.Mobile, "xcode")
-	}
-	if utils.CommandExists("adb") {
-		result.Mobile = append(result.Mobile, "android")
+// WithDetector returns a context under which FromContext returns d, so that
+// the cleaners and the commands of a run share its detection
+func WithDetector(ctx context.Context, d *StackDetector) context.Context {
+	return context.WithValue(ctx, detectorKey{}, d)
+}
+
+// FromContext returns the detector set by WithDetector, or a new one that
+// only knows the commands and paths looked for through it
+func FromContext(ctx context.Context) *StackDetector {
+	if d, ok := ctx.Value(detectorKey{}).(*StackDetector); ok && d != nil {
+		return d
 	}
-	androidHome := filepath.Join(d.homePath, "Library", "Android")
-	if utils.PathExists(androidHome) {
-		if !contains(result.Mobile, "android") {
-			result.Mobile = append(result.Mobile, "android")
+	home, _ := os.UserHomeDir()
+	return &StackDetector{homePath: home}
+}
+
+// DetectDetailed returns the tools DetectAll found, with their version and
+// the disk space their caches and data take. It runs each tool's version
+// command and sizes its folders, which takes a while.
+func (d *StackDetector) DetectDetailed() DetectionResult {
+	result := d.DetectAll()
+
+	var wg sync.WaitGroup
+	for _, category := range []struct {
+		tools []Tool
+		specs []toolSpec
+	}{
+		{result.Frontend, frontendTools},
+		{result.Backend, backendTools},
+		{result.Mobile, mobileTools},
+		{result.DevOps, devopsTools},
+		{result.DataML, dataMLTools},
+	} {
+		for i := range category.tools {
+			spec := specNamed(category.specs, category.tools[i].Name)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				d.describe(spec, &category.tools[i])
+			}()
 		}
 	}
-	if utils.CommandExists("flutter") {
-		result.Mobile = append(result.Mobile, "flutter")
-	}
-	if utils.CommandExists("pod") {
-		result.Mobile = append(result.Mobile, "cocoapods")
-	}
+	wg.Wait()
 
-	// === DevOps Detection ===
+	return result
+}
 
-	if utils.CommandExists("docker") {
-		result.DevOps = append(result.DevOps, "docker")
-	}
-	if utils.CommandExists("kubectl") {
-		result.DevOps = append(result.DevOps, "kubernetes")
-	}
-	if utils.CommandExists("terraform") {
-		result.DevOps = append(result.DevOps, "terraform")
-	}
-	if utils.CommandExists("helm") {
-		result.DevOps = append(result.DevOps, "helm")
-	}
-	if utils.CommandExists("minikube") {
-		result.DevOps = append(result.DevOps, "minikube")
+// specNamed returns the spec of the tool named name among specs
+func specNamed(specs []toolSpec, name string) toolSpec {
+	for _, spec := range specs {
+		if spec.name == name {
+			return spec
+		}
 	}
-	if utils.CommandExists("vagrant") {
-		result.DevOps = append(result.DevOps, "vagrant")
+	return toolSpec{name: name}
+}
+
+// detect returns the tools of specs that are installed
+func (d *StackDetector) detect(specs []toolSpec) []Tool {
+	tools := []Tool{}
+	for _, spec := range specs {
+		if tool, ok := d.find(spec); ok {
+			tools = append(tools, tool)
+		}
 	}
-	if utils.CommandExists("aws") {
-		result.DevOps = append(result.DevOps, "aws-cli")
+	return tools
+}
+
+// find looks for the tool of spec, returning it with the path it was found
+// at
+func (d *StackDetector) find(spec toolSpec) (Tool, bool) {
+	for _, command := range spec.commands {
+		if path, ok := d.lookPath(command); ok {
+			return Tool{Name: spec.name, Path: path}, true
+		}
 	}
-	if utils.CommandExists("gcloud") {
-		result.DevOps = append(result.DevOps, "gcloud")
+	for _, path := range spec.paths {
+		if !filepath.IsAbs(path) {
+			if d.homePath == "" {
+				continue
+			}
+			path = filepath.Join(d.homePath, path)
+		}
+		if d.exists(path) {
+			return Tool{Name: spec.name, Path: path}, true
+		}
 	}
-	if utils.CommandExists("az") {
-		result.DevOps = append(result.DevOps, "azure-cli")
+	if spec.pythonPackage != "" {
+		if path := d.pythonPackage(spec.pythonPackage); path != "" {
+			return Tool{Name: spec.name, Path: path}, true
+		}
 	}
+	return Tool{}, false
+}
 
-	// === Data Science / ML Detection ===
-
-	if utils.CommandExists("conda") {
-		result.DataML = append(result.DataML, "conda")
+// describe fills in the version of a found tool and the space its folders
+// take
+func (d *StackDetector) describe(spec toolSpec, tool *Tool) {
+	if spec.pythonPackage != "" {
+		tool.Version = pythonPackageVersion(tool.Path)
+	} else {
+		command := spec.version
+		if command == nil {
+			command = []string{tool.Path, "--version"}
+		}
+		if output, err := d.output(command[0], command[1:]...); err == nil || len(output) > 0 {
+			tool.Version = parseVersion(string(output))
+		}
 	}
-	if utils.CommandExists("jupyter") {
-		result.DataML = append(result.DataML, "jupyter")
+
+	if d.homePath == "" {
+		return
 	}
-	if utils.CommandExists("pip3") || utils.CommandExists("pip") {
-		result.DataML = append(result.DataML, "pip")
+	for _, dir := range spec.caches {
+		size, _ := utils.GetDirSize(filepath.Join(d.homePath, dir))
+		tool.Reclaimable += size
+		tool.Footprint += size
 	}
-	// Check for common ML frameworks by looking for Python packages
-	if d.hasPythonPackage("tensorflow") {
-		result.DataML = append(result.DataML, "tensorflow")
+	for _, dir := range spec.data {
+		size, _ := utils.GetDirSize(filepath.Join(d.homePath, dir))
+		tool.Footprint += size
 	}
-	if d.hasPythonPackage("torch") {
-		result.DataML = append(result.DataML, "pytorch")
+}
+
+// output runs a command and returns its combined output: some tools print
+// their version on stderr (java -version)
+func (d *StackDetector) output(name string, args ...string) ([]byte, error) {
+	if d.run != nil {
+		return d.run(name, args...)
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
 
-	return result
+// parseVersion returns the first version number in a version command's
+// output, or "" if it has none
+func parseVersion(output string) string {
+	return versionRe.FindString(output)
 }
 
 // HasFrontend checks if any frontend tools are detected
@@ -199,62 +430,17 @@ func (d *StackDetector) HasDataML() bool {
 
 // GetSummary returns a human-readable summary of detected tools
 func (d *StackDetector) GetSummary() string {
-	result := d.DetectAll()
 	summary := ""
 
-	if len(result.Frontend) > 0 {
-		summary += "Frontend: "
-		for i, tool := range result.Frontend {
-			if i > 0 {
-				summary += ", "
-			}
-			summary += tool
+	for _, category := range d.DetectAll().Categories() {
+		if len(category.Tools) == 0 {
+			continue
 		}
-		summary += "\n"
-	}
-
-	if len(result.Backend) > 0 {
-		summary += "Backend: "
-		for i, tool := range result.Backend {
-			if i > 0 {
-				summary += ", "
-			}
-			summary += tool
+		names := make([]string, len(category.Tools))
+		for i, tool := range category.Tools {
+			names[i] = tool.Name
 		}
-		summary += "\n"
-	}
-
-	if len(result.Mobile) > 0 {
-		summary += "Mobile: "
-		for i, tool := range result.Mobile {
-			if i > 0 {
-				summary += ", "
-			}
-			summary += tool
-		}
-		summary += "\n"
-	}
-
-	if len(result.DevOps) > 0 {
-		summary += "DevOps: "
-		for i, tool := range result.DevOps {
-			if i > 0 {
-				summary += ", "
-			}
-			summary += tool
-		}
-		summary += "\n"
-	}
-
-	if len(result.DataML) > 0 {
-		summary += "Data/ML: "
-		for i, tool := range result.DataML {
-			if i > 0 {
-				summary += ", "
-			}
-			summary += tool
-		}
-		summary += "\n"
+		summary += category.Label + ": " + strings.Join(names, ", ") + "\n"
 	}
 
 	if summary == "" {
@@ -266,6 +452,12 @@ func (d *StackDetector) GetSummary() string {
 
 // hasPythonPackage checks if a Python package is installed
 func (d *StackDetector) hasPythonPackage(pkg string) bool {
+	return d.pythonPackage(pkg) != ""
+}
+
+// pythonPackage returns the folder of a Python package installed for the
+// user, or "" if it is not
+func (d *StackDetector) pythonPackage(pkg string) string {
 	// Check in common site-packages locations
 	pythonPaths := []string{
 		filepath.Join(d.homePath, "Library", "Python"),
@@ -277,20 +469,20 @@ func (d *StackDetector) hasPythonPackage(pkg string) bool {
 			// Look for the package in site-packages
 			matches, _ := filepath.Glob(filepath.Join(basePath, "*/site-packages/"+pkg))
 			if len(matches) > 0 {
-				return true
+				return matches[0]
 			}
 		}
 	}
 
-	return false
+	return ""
 }
 
-// contains checks if a string slice contains a value
-func contains(slice []string, value string) bool {
-	for _, item := range slice {
-		if item == value {
-			return true
-		}
+// pythonPackageVersion returns the version of the Python package in dir,
+// from the name of the .dist-info folder installed next to it
+func pythonPackageVersion(dir string) string {
+	infos, _ := filepath.Glob(filepath.Join(filepath.Dir(dir), filepath.Base(dir)+"-*.dist-info"))
+	if len(infos) == 0 {
+		return ""
 	}
-	return false
+	return parseVersion(strings.TrimPrefix(filepath.Base(infos[0]), filepath.Base(dir)))
 }
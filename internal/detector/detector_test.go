@@ -1,6 +1,7 @@
 package detector
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -30,11 +31,11 @@ func TestNewDetector(t *testing.T) {
 
 func TestDetectionResult_Structure(t *testing.T) {
 	result := DetectionResult{
-		Frontend: []string{"node", "npm"},
-		Backend:  []string{"python", "go"},
-		Mobile:   []string{"xcode"},
-		DevOps:   []string{"docker"},
-		DataML:   []string{"jupyter"},
+		Frontend: []Tool{{Name: "node"}, {Name: "npm"}},
+		Backend:  []Tool{{Name: "python"}, {Name: "go"}},
+		Mobile:   []Tool{{Name: "xcode"}},
+		DevOps:   []Tool{{Name: "docker"}},
+		DataML:   []Tool{{Name: "jupyter"}},
 	}
 
 	if len(result.Frontend) != 2 {
@@ -91,6 +92,76 @@ func TestDetectAll(t *testing.T) {
 	t.Logf("Detected DataML: %v", result.DataML)
 }
 
+func TestDetectAll_Memoized(t *testing.T) {
+	home := t.TempDir()
+	android := filepath.Join(home, "Library", "Android")
+	os.MkdirAll(android, 0755)
+	detector := &StackDetector{homePath: home}
+
+	foundAt := func(result DetectionResult) string {
+		for _, tool := range result.Mobile {
+			if tool.Name == "android" {
+				return tool.Path
+			}
+		}
+		return ""
+	}
+
+	first := detector.DetectAll()
+	path := foundAt(first)
+	if path == "" {
+		t.Fatalf("Expected Android to be detected from %s, got %+v", android, first.Mobile)
+	}
+
+	// Later calls reuse the result, even once the folder is gone, and
+	// changing a result doesn't change the next
+	os.RemoveAll(android)
+	for i := range first.Mobile {
+		first.Mobile[i].Path = "changed"
+	}
+	if again := detector.DetectAll(); foundAt(again) != path {
+		t.Errorf("Expected the memoized result, got %+v", again.Mobile)
+	}
+
+	if refreshed := detector.Refresh(); foundAt(refreshed) == android {
+		t.Errorf("Expected Refresh to detect again, got %+v", refreshed.Mobile)
+	}
+}
+
+func TestHasCommand_Memoized(t *testing.T) {
+	bin := t.TempDir()
+	tool := filepath.Join(bin, "epurer-test-tool")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	detector := &StackDetector{}
+
+	if !detector.HasCommand("missing", "epurer-test-tool") || !detector.HasPath("/nonexistent", bin) {
+		t.Fatal("Expected the tool and its folder to be found")
+	}
+
+	// Later checks reuse what was found, until Refresh
+	os.RemoveAll(bin)
+	if !detector.HasCommand("epurer-test-tool") || !detector.HasPath(bin) {
+		t.Error("Expected the memoized command and path")
+	}
+	detector.Refresh()
+	if detector.HasCommand("epurer-test-tool") || detector.HasPath(bin) {
+		t.Error("Expected Refresh to look again")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	detector := &StackDetector{}
+	if got := FromContext(WithDetector(context.Background(), detector)); got != detector {
+		t.Errorf("Expected the detector of the context, got %p", got)
+	}
+	if got := FromContext(context.Background()); got == nil || got == detector {
+		t.Errorf("Expected a new detector without one in the context, got %p", got)
+	}
+}
+
 // =============================================================================
 // HasXxx Tests
 // =============================================================================
@@ -166,32 +237,86 @@ func TestGetSummary_Format(t *testing.T) {
 }
 
 // =============================================================================
-// contains Helper Tests
+// Version and Footprint Tests
 // =============================================================================
 
-func TestContains(t *testing.T) {
+func TestParseVersion(t *testing.T) {
 	tests := []struct {
-		name     string
-		slice    []string
-		value    string
-		expected bool
+		output   string
+		expected string
 	}{
-		{"Found first", []string{"a", "b", "c"}, "a", true},
-		{"Found middle", []string{"a", "b", "c"}, "b", true},
-		{"Found last", []string{"a", "b", "c"}, "c", true},
-		{"Not found", []string{"a", "b", "c"}, "d", false},
-		{"Empty slice", []string{}, "a", false},
-		{"Empty value found", []string{"", "a"}, "", true},
-		{"Case sensitive", []string{"Node", "NPM"}, "node", false},
+		{"v20.11.0\n", "20.11.0"},
+		{"go version go1.22.0 darwin/arm64\n", "1.22.0"},
+		{"openjdk version \"21.0.1\" 2023-10-17\n", "21.0.1"},
+		{"aws-cli/2.13.0 Python/3.11.4 Darwin/23.0.0\n", "2.13.0"},
+		{"Xcode 15.2\nBuild version 15C500b\n", "15.2"},
+		{"command not found", ""},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := contains(tt.slice, tt.value)
-			if result != tt.expected {
-				t.Errorf("contains(%v, %q) = %v, want %v", tt.slice, tt.value, result, tt.expected)
-			}
-		})
+		if got := parseVersion(tt.output); got != tt.expected {
+			t.Errorf("parseVersion(%q) = %q, want %q", tt.output, got, tt.expected)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	home := t.TempDir()
+	for path, size := range map[string]int{".npm/_cacache/a": 300, ".npm/_cacache/b": 200, ".nvm/versions/node": 1000} {
+		os.MkdirAll(filepath.Join(home, filepath.Dir(path)), 0755)
+		os.WriteFile(filepath.Join(home, path), make([]byte, size), 0644)
+	}
+
+	var ran []string
+	detector := &StackDetector{homePath: home, run: func(name string, args ...string) ([]byte, error) {
+		ran = append([]string{name}, args...)
+		return []byte("10.2.4\n"), nil
+	}}
+
+	spec := toolSpec{name: "npm", commands: []string{"npm"}, caches: []string{".npm", ".missing"}, data: []string{".nvm"}}
+	tool := Tool{Name: "npm", Path: "/usr/local/bin/npm"}
+	detector.describe(spec, &tool)
+
+	if strings.Join(ran, " ") != "/usr/local/bin/npm --version" {
+		t.Errorf("Expected the found command to be run with --version, ran %v", ran)
+	}
+	if tool.Version != "10.2.4" {
+		t.Errorf("Expected version 10.2.4, got %q", tool.Version)
+	}
+	if tool.Reclaimable != 500 {
+		t.Errorf("Expected 500 reclaimable bytes, got %d", tool.Reclaimable)
+	}
+	if tool.Footprint != 1500 {
+		t.Errorf("Expected a footprint of 1500 bytes, got %d", tool.Footprint)
+	}
+
+	spec.version = []string{"npm", "version"}
+	detector.describe(spec, &Tool{Path: "/usr/local/bin/npm"})
+	if strings.Join(ran, " ") != "npm version" {
+		t.Errorf("Expected the spec's version command to be run, ran %v", ran)
+	}
+}
+
+func TestPythonPackageVersion(t *testing.T) {
+	sitePackages := t.TempDir()
+	os.Mkdir(filepath.Join(sitePackages, "torch"), 0755)
+	os.Mkdir(filepath.Join(sitePackages, "torch-2.1.0.dist-info"), 0755)
+	os.Mkdir(filepath.Join(sitePackages, "torchvision-0.16.0.dist-info"), 0755)
+
+	if got := pythonPackageVersion(filepath.Join(sitePackages, "torch")); got != "2.1.0" {
+		t.Errorf("Expected version 2.1.0, got %q", got)
+	}
+	if got := pythonPackageVersion(filepath.Join(sitePackages, "numpy")); got != "" {
+		t.Errorf("Expected no version without a .dist-info folder, got %q", got)
+	}
+}
+
+func TestCategories(t *testing.T) {
+	result := DetectionResult{Backend: []Tool{{Name: "go"}}}
+
+	categories := result.Categories()
+	if len(categories) != 5 || categories[1].Label != "Backend" || len(categories[1].Tools) != 1 {
+		t.Errorf("Unexpected categories %+v", categories)
 	}
 }
 
@@ -282,8 +407,8 @@ func TestDetector_SummaryMatchesDetection(t *testing.T) {
 		}
 		// Check that detected tools appear in summary
 		for _, tool := range result.Frontend {
-			if !strings.Contains(summary, tool) {
-				t.Errorf("Summary missing frontend tool: %s", tool)
+			if !strings.Contains(summary, tool.Name) {
+				t.Errorf("Summary missing frontend tool: %s", tool.Name)
 			}
 		}
 	}
@@ -0,0 +1,210 @@
+// Package diagnostics helps troubleshoot epurer: the doctor checks of its
+// environment and the self-report bundle users attach to bug reports
+package diagnostics
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/history"
+	"github.com/0SansNom/epurer/internal/hooks"
+)
+
+// Timing is how long one cleaner took to detect and scan
+type Timing struct {
+	Cleaner  string        `json:"cleaner"`
+	Duration time.Duration `json:"duration"`
+	Targets  int           `json:"targets"`
+	Files    int           `json:"files"`
+	Dirs     int           `json:"dirs"`
+	TimedOut bool          `json:"timed_out"`
+}
+
+// Bundle is what a self-report contains, before redaction
+type Bundle struct {
+	Version  string
+	Config   *config.Config
+	LastRun  *history.Run // nil if epurer never cleaned anything
+	Detected detector.DetectionResult
+	Timings  []Timing
+}
+
+// system is the system.json file of the archive
+type system struct {
+	Version     string    `json:"version"`
+	GeneratedAt time.Time `json:"generated_at"`
+	OS          string    `json:"os"`
+	Arch        string    `json:"arch"`
+	GoVersion   string    `json:"go_version"`
+	CPUs        int       `json:"cpus"`
+}
+
+// override is a config override as written to config.json
+type override struct {
+	Safety  string `json:"safety,omitempty"`
+	Enabled *bool  `json:"enabled,omitempty"`
+}
+
+// sanitizedConfig is the config.json file of the archive: the settings that
+// change what a scan finds, with the excluded paths redacted
+type sanitizedConfig struct {
+	CleanLevel           string                         `json:"clean_level"`
+	Domains              []string                       `json:"domains"`
+	MaxConcurrent        int                            `json:"max_concurrent"`
+	ScanTimeout          string                         `json:"scan_timeout"`
+	ScanMaxDepth         int                            `json:"scan_max_depth"`
+	ScanExcludes         []string                       `json:"scan_excludes"`
+	CargoSweepDays       int                            `json:"cargo_sweep_days"`
+	MavenMaxAgeDays      int                            `json:"maven_max_age_days"`
+	ModelMaxAgeDays      int                            `json:"model_max_age_days"`
+	DatasetMaxAgeDays    int                            `json:"dataset_max_age_days"`
+	InstallerMaxAgeDays  int                            `json:"installer_max_age_days"`
+	ScreenshotMaxAgeDays int                            `json:"screenshot_max_age_days"`
+	CacheMaxAgeDays      int                            `json:"cache_max_age_days"`
+	CacheMinEntrySize    int64                          `json:"cache_min_entry_size"`
+	Overrides            map[string]map[string]override `json:"overrides"`
+}
+
+// sanitizeConfig keeps the settings of cfg worth reporting
+func sanitizeConfig(cfg *config.Config, r *Redactor) sanitizedConfig {
+	sanitized := sanitizedConfig{
+		CleanLevel:           cfg.CleanLevel.String(),
+		Domains:              []string{},
+		MaxConcurrent:        cfg.MaxConcurrent,
+		ScanTimeout:          cfg.ScanTimeout.String(),
+		ScanMaxDepth:         cfg.ScanMaxDepth,
+		ScanExcludes:         r.Paths(cfg.ScanExcludes),
+		CargoSweepDays:       cfg.CargoSweepDays,
+		MavenMaxAgeDays:      cfg.MavenMaxAgeDays,
+		ModelMaxAgeDays:      cfg.ModelMaxAgeDays,
+		DatasetMaxAgeDays:    cfg.DatasetMaxAgeDays,
+		InstallerMaxAgeDays:  cfg.InstallerMaxAgeDays,
+		ScreenshotMaxAgeDays: cfg.ScreenshotMaxAgeDays,
+		CacheMaxAgeDays:      cfg.CacheMaxAgeDays,
+		CacheMinEntrySize:    cfg.CacheMinEntrySize,
+		Overrides:            map[string]map[string]override{},
+	}
+
+	for _, domain := range cfg.Domains {
+		sanitized.Domains = append(sanitized.Domains, domain.Key())
+	}
+
+	for domain, categories := range cfg.Overrides {
+		sanitized.Overrides[domain] = map[string]override{}
+		for category, o := range categories {
+			entry := override{Enabled: o.Enabled}
+			if o.Safety != nil {
+				entry.Safety = o.Safety.String()
+			}
+			sanitized.Overrides[domain][category] = entry
+		}
+	}
+
+	return sanitized
+}
+
+// redactRun returns a copy of run with the paths and error messages of its
+// results redacted, and the commands and output of its hooks
+func redactRun(run history.Run, r *Redactor) history.Run {
+	redacted := run
+	redacted.Results = make([]history.Result, 0, len(run.Results))
+	for _, result := range run.Results {
+		result.Path = r.Path(result.Path)
+		result.Description = r.Text(result.Description)
+		result.Error = r.Text(result.Error)
+		redacted.Results = append(redacted.Results, result)
+	}
+	redacted.Hooks = make([]hooks.Result, 0, len(run.Hooks))
+	for _, hook := range run.Hooks {
+		hook.Command = r.Text(hook.Command)
+		hook.Output = r.Text(hook.Output)
+		hook.Error = r.Text(hook.Error)
+		redacted.Hooks = append(redacted.Hooks, hook)
+	}
+	return redacted
+}
+
+// redactDetected returns a copy of detected with the paths its tools were
+// found at redacted
+func redactDetected(detected detector.DetectionResult, r *Redactor) detector.DetectionResult {
+	redact := func(tools []detector.Tool) []detector.Tool {
+		redacted := make([]detector.Tool, 0, len(tools))
+		for _, tool := range tools {
+			tool.Path = r.Path(tool.Path)
+			redacted = append(redacted, tool)
+		}
+		return redacted
+	}
+	return detector.DetectionResult{
+		Frontend: redact(detected.Frontend),
+		Backend:  redact(detected.Backend),
+		Mobile:   redact(detected.Mobile),
+		DevOps:   redact(detected.DevOps),
+		DataML:   redact(detected.DataML),
+	}
+}
+
+// archiveFile is a JSON file of the archive
+type archiveFile struct {
+	name  string
+	value any
+}
+
+// Write writes the bundle as a zip archive to w, redacting it with r. The
+// archive holds system.json, config.json, detected.json, timings.json and,
+// if there is one, last-run.json.
+func (b Bundle) Write(w io.Writer, r *Redactor, now time.Time) error {
+	files := []archiveFile{
+		{"system.json", system{
+			Version:     b.Version,
+			GeneratedAt: now,
+			OS:          runtime.GOOS,
+			Arch:        runtime.GOARCH,
+			GoVersion:   runtime.Version(),
+			CPUs:        runtime.NumCPU(),
+		}},
+		{"config.json", sanitizeConfig(b.Config, r)},
+		{"detected.json", redactDetected(b.Detected, r)},
+		{"timings.json", sortedTimings(b.Timings)},
+	}
+	if b.LastRun != nil {
+		files = append(files, archiveFile{"last-run.json", redactRun(*b.LastRun, r)})
+	}
+
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		data, err := json.MarshalIndent(file.value, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		entry, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: now,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := entry.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// sortedTimings returns the timings slowest first
+func sortedTimings(timings []Timing) []Timing {
+	sorted := append([]Timing{}, timings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Duration > sorted[j].Duration
+	})
+	return sorted
+}
@@ -0,0 +1,131 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/history"
+	"github.com/0SansNom/epurer/internal/hooks"
+)
+
+// readArchive returns the files of a zip archive by name
+func readArchive(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Invalid archive: %v", err)
+	}
+
+	files := make(map[string]string)
+	for _, file := range archive.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[file.Name] = string(content)
+	}
+	return files
+}
+
+func TestBundle_Write(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.ScanExcludes = []string{"/Users/alice/clients/acme", "vendor"}
+	cfg.Domains = []config.Domain{config.DomainFrontend}
+
+	bundle := Bundle{
+		Version: "1.1.0",
+		Config:  cfg,
+		LastRun: &history.Run{
+			Command: "clean",
+			Results: []history.Result{
+				{Cleaner: "Frontend", Path: "/Users/alice/clients/acme/node_modules", Description: "node_modules", Success: true},
+				{Cleaner: "System", Path: "/Users/alice/Library/Caches/com.spotify.client", Error: "open /Users/alice/Library/Caches/com.spotify.client/x: operation not permitted"},
+			},
+			Hooks: []hooks.Result{
+				{Name: "post_clean", Command: "/Users/alice/bin/notify.sh --to alice", Output: "sent to /Users/alice/clients/acme", Error: "exit status 1"},
+			},
+		},
+		Detected: detector.DetectionResult{
+			Frontend: []detector.Tool{{Name: "Node.js", Version: "22.1.0", Path: "/Users/alice/.nvm/versions/node/v22.1.0/bin/node"}},
+			DevOps:   []detector.Tool{{Name: "Docker", Path: "docker"}},
+		},
+		Timings: []Timing{
+			{Cleaner: "System", Duration: time.Second},
+			{Cleaner: "Frontend", Duration: 5 * time.Second, TimedOut: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := bundle.Write(&buf, NewRedactor("/Users/alice", "alice"), time.Now()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	files := readArchive(t, buf.Bytes())
+	for _, name := range []string{"system.json", "config.json", "detected.json", "timings.json", "last-run.json"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("Archive is missing %s", name)
+		}
+		if strings.Contains(files[name], "alice") || strings.Contains(files[name], "acme") {
+			t.Errorf("%s is not redacted:\n%s", name, files[name])
+		}
+	}
+
+	var sanitized sanitizedConfig
+	if err := json.Unmarshal([]byte(files["config.json"]), &sanitized); err != nil {
+		t.Fatal(err)
+	}
+	if len(sanitized.ScanExcludes) != 2 || sanitized.ScanExcludes[1] != "vendor" || sanitized.Domains[0] != "frontend" {
+		t.Errorf("Unexpected config %+v", sanitized)
+	}
+
+	var run history.Run
+	if err := json.Unmarshal([]byte(files["last-run.json"]), &run); err != nil {
+		t.Fatal(err)
+	}
+	if run.Results[1].Path != "~/Library/Caches/com.spotify.client" {
+		t.Errorf("Cache paths should be kept, got %q", run.Results[1].Path)
+	}
+	if hook := run.Hooks[0]; hook.Name != "post_clean" || hook.Error != "exit status 1" || !strings.Contains(hook.Command, Redacted) {
+		t.Errorf("Expected the hook with its command redacted, got %+v", hook)
+	}
+
+	var detected detector.DetectionResult
+	if err := json.Unmarshal([]byte(files["detected.json"]), &detected); err != nil {
+		t.Fatal(err)
+	}
+	if detected.Frontend[0].Path != Redacted || detected.Frontend[0].Version != "22.1.0" || detected.DevOps[0].Path != "docker" {
+		t.Errorf("Expected the tool paths redacted, got %+v", detected)
+	}
+
+	var timings []Timing
+	if err := json.Unmarshal([]byte(files["timings.json"]), &timings); err != nil {
+		t.Fatal(err)
+	}
+	if timings[0].Cleaner != "Frontend" {
+		t.Errorf("Expected the slowest cleaner first, got %s", timings[0].Cleaner)
+	}
+}
+
+func TestBundle_Write_NoHistory(t *testing.T) {
+	var buf bytes.Buffer
+	bundle := Bundle{Version: "1.1.0", Config: config.NewDefaultConfig()}
+	if err := bundle.Write(&buf, NewRedactor("/Users/alice", "alice"), time.Now()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if _, ok := readArchive(t, buf.Bytes())["last-run.json"]; ok {
+		t.Error("Expected no last-run.json without history")
+	}
+}
@@ -0,0 +1,259 @@
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/plan"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// Status is the outcome of a doctor check
+type Status int
+
+const (
+	Pass Status = iota // Works as expected
+	Skip               // Does not apply on this system
+	Warn               // Some cleaning is limited
+	Fail               // Something is broken and needs fixing
+)
+
+// Icon returns the symbol printed before a check
+func (s Status) Icon() string {
+	switch s {
+	case Pass:
+		return "✅"
+	case Skip:
+		return "➖"
+	case Warn:
+		return "⚠️"
+	default:
+		return "❌"
+	}
+}
+
+// Check is the result of one doctor check
+type Check struct {
+	Name   string
+	Status Status
+	Detail string // What was found
+	Fix    string // What to do about it, for Warn and Fail
+}
+
+// staleManifestAge is how old an unfinished run manifest must be to be
+// reported as stale rather than as a run waiting to be resumed
+const staleManifestAge = 7 * 24 * time.Hour
+
+// requiredCommands are the external commands cleaners rely on, with how to
+// get each one
+var requiredCommands = []struct {
+	name string
+	use  string
+	fix  string
+}{
+	{"brew", "Homebrew caches and old formula versions", "Install Homebrew from https://brew.sh, or ignore this if you don't use it"},
+	{"docker", "Docker images, volumes and build cache", "Install Docker Desktop, or ignore this if you don't use Docker"},
+	{"xcrun", "iOS simulators", "Run `xcode-select --install` to install the Xcode command line tools"},
+	{"tmutil", "local Time Machine snapshots", "tmutil ships with macOS in /usr/bin: check that /usr/bin is in your PATH"},
+}
+
+// cacheRoots are the folders of the home directory most cleaners delete in
+var cacheRoots = []string{
+	"Library/Caches",
+	"Library/Logs",
+	"Library/Developer/Xcode/DerivedData",
+	".cache",
+	".npm",
+	".gradle",
+	".m2",
+	".cargo",
+}
+
+// Doctor checks that epurer can do its job on this machine
+type Doctor struct {
+	Home         string
+	StateDir     string
+	PolicyPath   string // Admin policy file, config.PolicyFilePath() outside tests
+	GOOS         string
+	CommandFound func(name string) bool // utils.CommandExists outside tests
+	Now          time.Time
+}
+
+// Run performs every check, in the order they are printed
+func (d Doctor) Run() []Check {
+	checks := []Check{d.CheckFullDiskAccess()}
+	checks = append(checks, d.CheckCommands()...)
+	checks = append(checks,
+		d.CheckCacheRoots(),
+		d.CheckConfig(),
+		d.CheckPolicy(),
+		d.CheckRunManifest(),
+	)
+	return checks
+}
+
+// CheckFullDiskAccess checks that the terminal can read the folders macOS
+// protects, such as ~/Library/Safari, without which Mail, Messages and
+// Safari data are skipped
+func (d Doctor) CheckFullDiskAccess() Check {
+	check := Check{Name: "Full Disk Access"}
+	if d.GOOS != "darwin" {
+		check.Status = Skip
+		check.Detail = "Only needed on macOS"
+		return check
+	}
+
+	for _, dir := range []string{"Library/Safari", "Library/Mail", "Library/Messages"} {
+		_, err := os.ReadDir(filepath.Join(d.Home, dir))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			check.Status = Fail
+			check.Detail = fmt.Sprintf("~/%s cannot be read: Mail, Messages and Safari data are skipped", dir)
+			check.Fix = "Open System Settings › Privacy & Security › Full Disk Access and turn it on for your terminal, then restart it"
+			return check
+		}
+		check.Status = Pass
+		check.Detail = "Protected folders can be read"
+		return check
+	}
+
+	check.Status = Skip
+	check.Detail = "No protected folder to test"
+	return check
+}
+
+// CheckCommands checks that the external commands cleaners use are
+// installed, one check per command
+func (d Doctor) CheckCommands() []Check {
+	checks := make([]Check, 0, len(requiredCommands))
+	for _, command := range requiredCommands {
+		check := Check{Name: "Command " + command.name}
+		if d.CommandFound(command.name) {
+			check.Status = Pass
+			check.Detail = "Found: " + command.use + " can be cleaned"
+		} else {
+			check.Status = Warn
+			check.Detail = "Not found: " + command.use + " are not cleaned"
+			check.Fix = command.fix
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// CheckCacheRoots checks that the main cache folders can be written to,
+// since cleaning deletes inside them
+func (d Doctor) CheckCacheRoots() Check {
+	check := Check{Name: "Cache folders writable", Status: Pass}
+
+	readOnly := []string{}
+	checked := 0
+	for _, root := range cacheRoots {
+		path := filepath.Join(d.Home, root)
+		if !utils.PathExists(path) {
+			continue
+		}
+		checked++
+		if !utils.IsWritable(path) {
+			readOnly = append(readOnly, "~/"+root)
+		}
+	}
+
+	if len(readOnly) > 0 {
+		check.Status = Fail
+		check.Detail = "Not writable: " + strings.Join(readOnly, ", ")
+		check.Fix = "Take back ownership, e.g. `sudo chown -R $(whoami) " + readOnly[0] + "` (often left by a tool run with sudo)"
+		return check
+	}
+	check.Detail = fmt.Sprintf("%d cache folders can be written to", checked)
+	return check
+}
+
+// CheckConfig checks that the config file, if any, is valid
+func (d Doctor) CheckConfig() Check {
+	check := Check{Name: "Config file", Status: Pass}
+	path := filepath.Join(d.StateDir, config.FileName)
+
+	if !utils.PathExists(path) {
+		check.Detail = "No config file, using the defaults"
+		return check
+	}
+	if _, err := config.LoadFile(path); err != nil {
+		check.Status = Fail
+		check.Detail = err.Error()
+		check.Fix = "Fix the file, or remove it to go back to the defaults: rm " + path
+		return check
+	}
+
+	check.Detail = path + " is valid"
+	return check
+}
+
+// CheckPolicy checks that the admin policy, if any, is valid: epurer refuses
+// to run with a broken one
+func (d Doctor) CheckPolicy() Check {
+	check := Check{Name: "Admin policy", Status: Pass}
+
+	p, err := config.LoadPolicy(d.PolicyPath)
+	if err != nil {
+		check.Status = Fail
+		check.Detail = err.Error()
+		check.Fix = "Ask your admins to fix the policy deployed to " + d.PolicyPath
+		return check
+	}
+	if p == nil {
+		check.Detail = "No admin policy"
+		return check
+	}
+
+	check.Detail = d.PolicyPath + " is valid"
+	if p.MaxCleanLevel != nil {
+		check.Detail += ", clean level up to " + p.MaxCleanLevel.String()
+	}
+	return check
+}
+
+// CheckRunManifest checks for data left by an interrupted clean: a run
+// manifest waiting to be resumed, or one too old or broken to be of use
+func (d Doctor) CheckRunManifest() Check {
+	check := Check{Name: "Interrupted runs", Status: Pass}
+	path := filepath.Join(d.StateDir, plan.ManifestFileName)
+
+	if utils.PathExists(path + ".tmp") {
+		check.Status = Warn
+		check.Detail = "A run manifest was left half written"
+		check.Fix = "rm " + path + ".tmp"
+		return check
+	}
+	if !utils.PathExists(path) {
+		check.Detail = "No interrupted clean"
+		return check
+	}
+
+	p, err := plan.Load(path)
+	if err != nil {
+		check.Status = Fail
+		check.Detail = "The run manifest cannot be read: " + err.Error()
+		check.Fix = "rm " + path
+		return check
+	}
+
+	age := d.Now.Sub(p.CreatedAt)
+	check.Status = Warn
+	if age > staleManifestAge {
+		check.Detail = fmt.Sprintf("A clean interrupted %d days ago was never finished (%d targets left)", int(age.Hours()/24), len(p.Pending()))
+		check.Fix = "Its targets may have changed since: remove it with `rm " + path + "` and run clean again"
+		return check
+	}
+	check.Detail = fmt.Sprintf("A clean was interrupted with %d targets left", len(p.Pending()))
+	check.Fix = "Run `epurer clean --resume` to finish it"
+	return check
+}
@@ -0,0 +1,140 @@
+package diagnostics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/plan"
+)
+
+// newTestDoctor returns a Doctor for a fake home where only brew is installed
+func newTestDoctor(t *testing.T) Doctor {
+	t.Helper()
+	home := t.TempDir()
+	return Doctor{
+		Home:         home,
+		StateDir:     filepath.Join(home, ".epurer"),
+		PolicyPath:   filepath.Join(home, "epurer-policy.json"),
+		GOOS:         "darwin",
+		CommandFound: func(name string) bool { return name == "brew" },
+		Now:          time.Now(),
+	}
+}
+
+func TestDoctor_Commands(t *testing.T) {
+	checks := newTestDoctor(t).CheckCommands()
+
+	if len(checks) != len(requiredCommands) {
+		t.Fatalf("Expected one check per command, got %d", len(checks))
+	}
+	for _, check := range checks {
+		want := Warn
+		if check.Name == "Command brew" {
+			want = Pass
+		}
+		if check.Status != want {
+			t.Errorf("%s: status %v, want %v", check.Name, check.Status, want)
+		}
+		if check.Status == Warn && check.Fix == "" {
+			t.Errorf("%s: expected a fix", check.Name)
+		}
+	}
+}
+
+func TestDoctor_FullDiskAccess(t *testing.T) {
+	d := newTestDoctor(t)
+	if check := d.CheckFullDiskAccess(); check.Status != Skip {
+		t.Errorf("Expected Skip without protected folders, got %+v", check)
+	}
+
+	if err := os.MkdirAll(filepath.Join(d.Home, "Library", "Safari"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if check := d.CheckFullDiskAccess(); check.Status != Pass {
+		t.Errorf("Expected Pass for a readable folder, got %+v", check)
+	}
+
+	d.GOOS = "linux"
+	if check := d.CheckFullDiskAccess(); check.Status != Skip {
+		t.Errorf("Expected Skip outside macOS, got %+v", check)
+	}
+}
+
+func TestDoctor_Config(t *testing.T) {
+	d := newTestDoctor(t)
+	if check := d.CheckConfig(); check.Status != Pass {
+		t.Errorf("A missing config file should pass, got %+v", check)
+	}
+
+	path := filepath.Join(d.StateDir, config.FileName)
+	if err := os.MkdirAll(d.StateDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"cleaners": {"frontend": {"node_modules": {"safety": "risky"}}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := d.CheckConfig()
+	if check.Status != Fail || !strings.Contains(check.Fix, path) {
+		t.Errorf("Expected an invalid config with a fix, got %+v", check)
+	}
+}
+
+func TestDoctor_Policy(t *testing.T) {
+	d := newTestDoctor(t)
+	if check := d.CheckPolicy(); check.Status != Pass {
+		t.Errorf("A missing policy should pass, got %+v", check)
+	}
+
+	if err := os.WriteFile(d.PolicyPath, []byte(`{"max_clean_level": "standard"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if check := d.CheckPolicy(); check.Status != Pass || !strings.Contains(check.Detail, "standard") {
+		t.Errorf("Expected a valid policy, got %+v", check)
+	}
+
+	if err := os.WriteFile(d.PolicyPath, []byte(`{"max_clean_level": "reckless"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if check := d.CheckPolicy(); check.Status != Fail {
+		t.Errorf("Expected an invalid policy to fail, got %+v", check)
+	}
+}
+
+func TestDoctor_RunManifest(t *testing.T) {
+	d := newTestDoctor(t)
+	if check := d.CheckRunManifest(); check.Status != Pass {
+		t.Errorf("Expected Pass without a manifest, got %+v", check)
+	}
+
+	path := filepath.Join(d.StateDir, plan.ManifestFileName)
+	p := plan.New(map[string][]cleaner.CleanTarget{
+		"System": {{Path: "/tmp/cache", SizeBytes: 10}},
+	}, config.Standard)
+	if err := plan.Save(path, p); err != nil {
+		t.Fatal(err)
+	}
+
+	check := d.CheckRunManifest()
+	if check.Status != Warn || !strings.Contains(check.Fix, "--resume") {
+		t.Errorf("Expected a run to resume, got %+v", check)
+	}
+
+	d.Now = time.Now().Add(30 * 24 * time.Hour)
+	check = d.CheckRunManifest()
+	if check.Status != Warn || !strings.Contains(check.Detail, "never finished") || !strings.Contains(check.Fix, "rm ") {
+		t.Errorf("Expected a stale manifest, got %+v", check)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if check := d.CheckRunManifest(); check.Status != Fail {
+		t.Errorf("Expected a broken manifest to fail, got %+v", check)
+	}
+}
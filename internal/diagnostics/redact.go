@@ -0,0 +1,134 @@
+package diagnostics
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// Redacted replaces a path outside the known cache locations
+const Redacted = "<redacted>"
+
+// redactedUser replaces the user name wherever it appears
+const redactedUser = "<user>"
+
+// homeCacheDirs are the folders of the home directory whose paths are kept in
+// a report: they say which cache is involved without revealing anything
+// about the user's projects
+var homeCacheDirs = []string{
+	"Library/Caches",
+	"Library/Logs",
+	"Library/Developer",
+	"Library/Application Support/Code/Cache",
+	".cache",
+	".npm",
+	".yarn",
+	".pnpm-store",
+	".bun",
+	".gradle",
+	".m2",
+	".cargo",
+	".rustup",
+	"go/pkg/mod",
+	".cocoapods",
+	".nuget",
+	".pub-cache",
+	".android",
+	".docker",
+	".terraform.d",
+	".conda",
+	".ollama",
+	".epurer",
+	".Trash",
+}
+
+// systemCacheDirs are the system-wide cache locations whose paths are kept
+var systemCacheDirs = []string{
+	"/Library/Caches",
+	"/Library/Logs",
+	"/private/var/folders",
+	"/private/var/log",
+	"/tmp",
+}
+
+// pathPattern finds paths in free text: tokens starting with / or ~/ at the
+// start of the text or after a space, quote, parenthesis or equals sign, up
+// to the first colon
+var pathPattern = regexp.MustCompile(`(^|[\s"'(=])((?:~|/)[^\s"'(),:]*)`)
+
+// Redactor hides personal information from what goes into a report: the
+// user name, and every path that is not inside a known cache location
+type Redactor struct {
+	home string
+	user *regexp.Regexp // Matches the user name as a whole word
+}
+
+// NewRedactor creates a Redactor for the given home directory and user name
+func NewRedactor(home, user string) *Redactor {
+	r := &Redactor{home: utils.NFC(filepath.Clean(home))}
+	if user != "" {
+		r.user = regexp.MustCompile(`\b` + regexp.QuoteMeta(user) + `\b`)
+	}
+	return r
+}
+
+// Path returns path with the home directory shortened to ~ if it is inside
+// a known cache location, and Redacted otherwise. Pseudo-paths such as
+// "docker:images" are not on disk and are returned unchanged.
+func (r *Redactor) Path(path string) string {
+	if path == "" || (!strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "~")) {
+		return path
+	}
+
+	clean := utils.NFC(filepath.Clean(path))
+	if rel, ok := strings.CutPrefix(clean, "~/"); ok {
+		clean = filepath.Join(r.home, rel)
+	}
+
+	for _, dir := range homeCacheDirs {
+		if within(clean, filepath.Join(r.home, dir)) {
+			return r.hideUser("~" + strings.TrimPrefix(clean, r.home))
+		}
+	}
+	for _, dir := range systemCacheDirs {
+		if within(clean, dir) {
+			return r.hideUser(clean)
+		}
+	}
+
+	return Redacted
+}
+
+// Text redacts every path found in free text, such as an error message,
+// then the user name
+func (r *Redactor) Text(text string) string {
+	text = pathPattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := pathPattern.FindStringSubmatch(match)
+		return groups[1] + r.Path(groups[2])
+	})
+	return r.hideUser(text)
+}
+
+// Paths redacts each path of a list
+func (r *Redactor) Paths(paths []string) []string {
+	redacted := make([]string, 0, len(paths))
+	for _, path := range paths {
+		redacted = append(redacted, r.Path(path))
+	}
+	return redacted
+}
+
+// hideUser replaces the user name in s
+func (r *Redactor) hideUser(s string) string {
+	if r.user == nil {
+		return s
+	}
+	return r.user.ReplaceAllLiteralString(s, redactedUser)
+}
+
+// within reports whether path is dir or inside it. Both are in NFC.
+func within(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
@@ -0,0 +1,53 @@
+package diagnostics
+
+import "testing"
+
+func TestRedactor_Path(t *testing.T) {
+	r := NewRedactor("/Users/alice", "alice")
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/Users/alice/Library/Caches/com.apple.Safari", "~/Library/Caches/com.apple.Safari"},
+		{"/Users/alice/.npm/_cacache", "~/.npm/_cacache"},
+		{"~/.gradle/caches", "~/.gradle/caches"},
+		{"/Library/Caches/com.apple.iconservices", "/Library/Caches/com.apple.iconservices"},
+		{"/Users/alice/work/secret-project/node_modules", Redacted},
+		{"/Users/alice/Library/Cachesfoo", Redacted},
+		{"/Volumes/Backup/.Trashes", Redacted},
+		{"docker:images", "docker:images"},
+		{"node_modules", "node_modules"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := r.Path(tt.path); got != tt.want {
+			t.Errorf("Path(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRedactor_Text(t *testing.T) {
+	r := NewRedactor("/Users/alice", "alice")
+
+	got := r.Text(`remove /Users/alice/code/app/build: permission denied (owner alice, cache "/Users/alice/.cache/pip")`)
+	want := `remove <redacted>: permission denied (owner <user>, cache "~/.cache/pip")`
+	if got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+
+	// The user name is only replaced as a whole word
+	if got := NewRedactor("/home/al", "al").Text("also calls al"); got != "also calls <user>" {
+		t.Errorf("Text() = %q", got)
+	}
+}
+
+func TestRedactor_PathNormalization(t *testing.T) {
+	// Home composed, path decomposed as HFS+ stores it
+	r := NewRedactor("/Users/zoé", "zoé")
+
+	if got := r.Path("/Users/zoé/Library/Caches/com.apple.Safari"); got != "~/Library/Caches/com.apple.Safari" {
+		t.Errorf("Path() = %q, expected the cache path whatever the normalization", got)
+	}
+}
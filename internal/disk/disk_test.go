@@ -0,0 +1,82 @@
+package disk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSnapshots(t *testing.T) {
+	output := `Snapshots for disk /:
+com.apple.TimeMachine.2025-01-15-123456.local
+com.apple.TimeMachine.2025-01-14-080000.local
+com.apple.os.update-ABC123
+`
+
+	snapshots := parseSnapshots(output)
+	if len(snapshots) != 2 {
+		t.Fatalf("Expected 2 snapshots, got %d: %+v", len(snapshots), snapshots)
+	}
+
+	if snapshots[0].Name != "com.apple.TimeMachine.2025-01-14-080000.local" {
+		t.Errorf("Expected oldest snapshot first, got %s", snapshots[0].Name)
+	}
+
+	expected := time.Date(2025, 1, 15, 12, 34, 56, 0, time.Local)
+	if !snapshots[1].Date.Equal(expected) {
+		t.Errorf("Expected date %v, got %v", expected, snapshots[1].Date)
+	}
+}
+
+func TestParseSnapshots_Empty(t *testing.T) {
+	if snapshots := parseSnapshots(""); len(snapshots) != 0 {
+		t.Errorf("Expected no snapshots, got %+v", snapshots)
+	}
+}
+
+func TestPurgeable(t *testing.T) {
+	values := map[string]string{"APFSContainerFree": "100", "FreeSpace": "250"}
+	if got := purgeable(values); got != 150 {
+		t.Errorf("Expected 150 purgeable bytes, got %d", got)
+	}
+}
+
+func TestPurgeable_Missing(t *testing.T) {
+	if got := purgeable(map[string]string{"FreeSpace": "10"}); got != 0 {
+		t.Errorf("Expected 0 without container free space, got %d", got)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	u, err := usage(map[string]string{
+		"TotalSize":         "1000",
+		"FreeSpace":         "400",
+		"APFSContainerFree": "300",
+	})
+	if err != nil {
+		t.Fatalf("usage failed: %v", err)
+	}
+
+	expected := Usage{Total: 1000, Used: 600, Free: 300, Purgeable: 100}
+	if u != expected {
+		t.Errorf("Expected %+v, got %+v", expected, u)
+	}
+	if u.Available() != 400 {
+		t.Errorf("Expected 400 available, got %d", u.Available())
+	}
+}
+
+func TestUsage_NoContainer(t *testing.T) {
+	u, err := usage(map[string]string{"TotalSize": "1000", "FreeSpace": "400"})
+	if err != nil {
+		t.Fatalf("usage failed: %v", err)
+	}
+	if u.Free != 400 || u.Purgeable != 0 || u.Used != 600 {
+		t.Errorf("Unexpected usage: %+v", u)
+	}
+}
+
+func TestUsage_Missing(t *testing.T) {
+	if _, err := usage(map[string]string{"FreeSpace": "400"}); err == nil {
+		t.Error("Expected error without volume size")
+	}
+}
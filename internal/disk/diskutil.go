@@ -0,0 +1,90 @@
+package disk
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// Usage is the space of a volume as macOS accounts for it
+type Usage struct {
+	Total     int64 // Capacity of the volume
+	Used      int64 // Space taken by files, not counting purgeable space
+	Free      int64 // Unallocated space
+	Purgeable int64 // Space macOS can reclaim on demand, mostly local snapshots
+}
+
+// Available is the space Finder reports as available: free plus purgeable
+func (u Usage) Available() int64 {
+	return u.Free + u.Purgeable
+}
+
+// DiskutilAvailable reports whether diskutil can be used on this system
+func DiskutilAvailable() bool {
+	return utils.CommandExists("diskutil")
+}
+
+// VolumeUsage returns the space usage of a volume from
+// `diskutil info -plist`
+func VolumeUsage(ctx context.Context, volume string) (Usage, error) {
+	info, err := volumeInfo(ctx, volume)
+	if err != nil {
+		return Usage{}, err
+	}
+	return usage(info)
+}
+
+// usage computes the space usage from diskutil info keys. APFS volumes
+// share their container's free space, so it is preferred over the volume's.
+func usage(info map[string]string) (Usage, error) {
+	total, err := strconv.ParseInt(info["TotalSize"], 10, 64)
+	if err != nil {
+		return Usage{}, fmt.Errorf("volume size not reported by diskutil")
+	}
+
+	free, err := strconv.ParseInt(info["APFSContainerFree"], 10, 64)
+	if err != nil {
+		free, err = strconv.ParseInt(info["FreeSpace"], 10, 64)
+		if err != nil {
+			return Usage{}, fmt.Errorf("free space not reported by diskutil")
+		}
+	}
+
+	u := Usage{Total: total, Free: free, Purgeable: purgeable(info)}
+	u.Used = max(total-u.Free-u.Purgeable, 0)
+	return u, nil
+}
+
+// PurgeableBytes estimates the purgeable space of a volume, mostly held by
+// local snapshots: the free space macOS reports for the volume, which
+// counts purgeable data as available, minus what is really unallocated in
+// its APFS container
+func PurgeableBytes(ctx context.Context, volume string) (int64, error) {
+	info, err := volumeInfo(ctx, volume)
+	if err != nil {
+		return 0, err
+	}
+	return purgeable(info), nil
+}
+
+// purgeable computes the purgeable space from diskutil info keys
+func purgeable(info map[string]string) int64 {
+	free, errFree := strconv.ParseInt(info["FreeSpace"], 10, 64)
+	containerFree, errContainer := strconv.ParseInt(info["APFSContainerFree"], 10, 64)
+	if errFree != nil || errContainer != nil || free <= containerFree {
+		return 0
+	}
+	return free - containerFree
+}
+
+// volumeInfo returns the top-level keys of `diskutil info -plist volume`
+func volumeInfo(ctx context.Context, volume string) (map[string]string, error) {
+	output, err := exec.CommandContext(ctx, "diskutil", "info", "-plist", volume).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume info: %w", err)
+	}
+	return utils.ParsePlistDict(output)
+}
@@ -0,0 +1,82 @@
+package disk
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// snapshotPrefix starts the name of every local Time Machine snapshot
+const snapshotPrefix = "com.apple.TimeMachine."
+
+// snapshotDateLayout is the date format in snapshot names
+// (com.apple.TimeMachine.2025-01-15-123456.local)
+const snapshotDateLayout = "2006-01-02-150405"
+
+// thinAllBytes asks tmutil to purge as much snapshot space as it can
+const thinAllBytes = "999999999999"
+
+// Snapshot is an APFS local Time Machine snapshot. APFS keeps the blocks of
+// files deleted after a snapshot was taken, so cleaning doesn't free space
+// until the snapshot is thinned.
+type Snapshot struct {
+	Name string    // e.g. "com.apple.TimeMachine.2025-01-15-123456.local"
+	Date time.Time // When the snapshot was taken, zero if the name has no date
+}
+
+// TimeMachineAvailable reports whether tmutil can be used on this system
+func TimeMachineAvailable() bool {
+	return utils.CommandExists("tmutil")
+}
+
+// ListSnapshots returns the local Time Machine snapshots of a volume, oldest
+// first
+func ListSnapshots(ctx context.Context, volume string) ([]Snapshot, error) {
+	output, err := exec.CommandContext(ctx, "tmutil", "listlocalsnapshots", volume).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local snapshots: %w", err)
+	}
+
+	return parseSnapshots(string(output)), nil
+}
+
+// ThinSnapshots asks Time Machine to purge as much local snapshot space of a
+// volume as possible. The backups on the backup disk are not touched.
+func ThinSnapshots(ctx context.Context, volume string) error {
+	output, err := exec.CommandContext(ctx, "tmutil", "thinlocalsnapshots", volume, thinAllBytes, "4").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to thin local snapshots: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// parseSnapshots parses the output of `tmutil listlocalsnapshots`, which
+// starts with a "Snapshots for disk /:" header on recent macOS versions
+func parseSnapshots(output string) []Snapshot {
+	snapshots := []Snapshot{}
+
+	for _, line := range strings.Split(output, "\n") {
+		name := strings.TrimSpace(line)
+		if !strings.HasPrefix(name, snapshotPrefix) {
+			continue
+		}
+
+		snapshot := Snapshot{Name: name}
+		date := strings.TrimSuffix(strings.TrimPrefix(name, snapshotPrefix), ".local")
+		if t, err := time.ParseInLocation(snapshotDateLayout, date, time.Local); err == nil {
+			snapshot.Date = t
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Date.Before(snapshots[j].Date)
+	})
+
+	return snapshots
+}
@@ -0,0 +1,147 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/hooks"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// FileName is the name of the history file inside the state directory
+const FileName = "history.jsonl"
+
+// Run is a single recorded clean run
+type Run struct {
+	StartedAt   time.Time      `json:"started_at"`
+	Duration    time.Duration  `json:"duration"`
+	Command     string         `json:"command"`     // e.g. "clean", "smart"
+	Interrupted bool           `json:"interrupted"` // Run was stopped before all targets were processed
+	Results     []Result       `json:"results"`
+	Hooks       []hooks.Result `json:"hooks,omitempty"` // Hooks run before and after cleaning, in order
+}
+
+// Result is the outcome of cleaning one target
+type Result struct {
+	Cleaner          string `json:"cleaner"`
+	Path             string `json:"path"`
+	Description      string `json:"description"`
+	BytesFreed       int64  `json:"bytes_freed"`
+	BytesQuarantined int64  `json:"bytes_quarantined,omitempty"` // Moved to the quarantine instead of freed
+	BytesArchived    int64  `json:"bytes_archived,omitempty"`    // Moved to an archive folder instead of freed
+	Success          bool   `json:"success"`
+	Error            string `json:"error,omitempty"`
+}
+
+// NewResult converts a cleaner result into a history result
+func NewResult(cleanerName string, result cleaner.CleanResult) Result {
+	r := Result{
+		Cleaner:          cleanerName,
+		Path:             result.Target.Path,
+		Description:      result.Target.Description,
+		BytesFreed:       result.BytesFreed,
+		BytesQuarantined: result.BytesQuarantined,
+		BytesArchived:    result.BytesArchived,
+		Success:          result.Success,
+	}
+	if result.Error != nil {
+		r.Error = result.Error.Error()
+	}
+	return r
+}
+
+// BytesFreed returns the total bytes freed by the run
+func (r Run) BytesFreed() int64 {
+	var total int64
+	for _, result := range r.Results {
+		if result.Success {
+			total += result.BytesFreed
+		}
+	}
+	return total
+}
+
+// DefaultPath returns the history file in the state directory
+func DefaultPath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Append adds a run to the history file, creating it if needed
+func Append(path string, run Run) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// Load reads all runs from the history file, oldest first. A missing file
+// is an empty history; malformed lines are skipped.
+func Load(path string) ([]Run, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return []Run{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	runs := []Run{}
+	sc := bufio.NewScanner(file)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var run Run
+		if err := json.Unmarshal(sc.Bytes(), &run); err != nil {
+			continue
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, sc.Err()
+}
+
+// Record is the outcome of cleaning one target in a past run
+type Record struct {
+	StartedAt time.Time
+	Command   string
+	Result    Result
+}
+
+// ForPath returns the results of runs that cleaned path, a folder
+// containing it or a folder inside it, newest first
+func ForPath(runs []Run, path string) []Record {
+	records := []Record{}
+	for i := len(runs) - 1; i >= 0; i-- {
+		for _, result := range runs[i].Results {
+			if !filepath.IsAbs(result.Path) {
+				continue
+			}
+			if utils.HasPathPrefix(path, result.Path) || utils.HasPathPrefix(result.Path, path) {
+				records = append(records, Record{StartedAt: runs[i].StartedAt, Command: runs[i].Command, Result: result})
+			}
+		}
+	}
+	return records
+}
@@ -0,0 +1,138 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+)
+
+func TestAppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", FileName)
+
+	runs := []Run{
+		{
+			StartedAt: time.Now().Add(-time.Hour).UTC(),
+			Command:   "clean",
+			Results: []Result{
+				{Cleaner: "Frontend", Path: "/a", BytesFreed: 100, Success: true},
+				{Cleaner: "Frontend", Path: "/b", BytesFreed: 50, Success: false, Error: "permission denied"},
+			},
+		},
+		{
+			StartedAt:   time.Now().UTC(),
+			Command:     "smart",
+			Interrupted: true,
+		},
+	}
+
+	for _, run := range runs {
+		if err := Append(path, run); err != nil {
+			t.Fatalf("Append() returned error: %v", err)
+		}
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	if len(loaded) != 2 {
+		t.Fatalf("Expected 2 runs, got %d", len(loaded))
+	}
+	if loaded[0].Command != "clean" || len(loaded[0].Results) != 2 {
+		t.Errorf("First run not restored: %+v", loaded[0])
+	}
+	if !loaded[1].Interrupted {
+		t.Error("Interrupted flag not restored")
+	}
+	if loaded[0].BytesFreed() != 100 {
+		t.Errorf("BytesFreed() = %d, want 100", loaded[0].BytesFreed())
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	runs, err := Load(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if len(runs) != 0 {
+		t.Errorf("Expected empty history, got %d runs", len(runs))
+	}
+}
+
+func TestLoad_SkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	content := `{"command": "clean"}` + "\nnot json\n" + `{"command": "smart"}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Errorf("Expected 2 valid runs, got %d", len(runs))
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	result := NewResult("Backend", cleaner.CleanResult{
+		Target:     cleaner.CleanTarget{Path: "/x", Description: "pip cache"},
+		BytesFreed: 10,
+		Error:      errors.New("busy"),
+	})
+
+	if result.Cleaner != "Backend" || result.Path != "/x" || result.Error != "busy" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestForPath(t *testing.T) {
+	first := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	runs := []Run{
+		{StartedAt: first, Command: "clean", Results: []Result{
+			{Path: "/work/app/node_modules", BytesFreed: 100, Success: true},
+			{Path: "/work/other/node_modules", BytesFreed: 50, Success: true},
+		}},
+		{StartedAt: second, Command: "smart", Results: []Result{
+			{Path: "/work/app", BytesFreed: 300, Success: true},
+			{Path: "docker:images", BytesFreed: 10, Success: true},
+		}},
+	}
+
+	records := ForPath(runs, "/work/app/node_modules")
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %+v", records)
+	}
+	if records[0].Command != "smart" || records[0].Result.Path != "/work/app" {
+		t.Errorf("Expected the newest run first, with the folder containing the path, got %+v", records[0])
+	}
+	if !records[1].StartedAt.Equal(first) || records[1].Result.BytesFreed != 100 {
+		t.Errorf("Unexpected record %+v", records[1])
+	}
+
+	if records := ForPath(runs, "/work"); len(records) != 3 {
+		t.Errorf("Expected the 3 targets inside /work, got %+v", records)
+	}
+	if records := ForPath(runs, "/elsewhere"); len(records) != 0 {
+		t.Errorf("Expected no record, got %+v", records)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("EPURER_HOME", "/tmp/epurer-test")
+
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath() returned error: %v", err)
+	}
+	if path != filepath.Join("/tmp/epurer-test", FileName) {
+		t.Errorf("DefaultPath() = %v", path)
+	}
+}
@@ -0,0 +1,104 @@
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+)
+
+// Paces telling slow regrowers, not worth cleaning again soon, from chronic
+// ones, worth cleaning first
+const (
+	SlowPace      = 5 * 1000 * 1000   // Bytes per day
+	ChronicPace   = 100 * 1000 * 1000 // Bytes per day
+	ChronicCleans = 3                 // Cleans recorded before a target counts as chronic
+)
+
+// Regrowth is how a target came back after past cleans
+type Regrowth struct {
+	LastCleaned time.Time
+	Cleans      int     // Successful cleans recorded
+	BytesPerDay float64 // Pace it came back at between cleans, 0 after a single clean
+}
+
+// RegrowthByPath returns how the paths runs cleaned successfully came back:
+// what each clean freed, over the time since the previous one
+func RegrowthByPath(runs []Run) map[string]Regrowth {
+	type regrowth struct {
+		Regrowth
+		bytes int64   // Freed by the cleans after the first
+		days  float64 // Between the first clean and the last
+	}
+	paths := make(map[string]*regrowth)
+
+	sorted := append([]Run(nil), runs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartedAt.Before(sorted[j].StartedAt)
+	})
+	for _, run := range sorted {
+		for _, result := range run.Results {
+			if !result.Success {
+				continue
+			}
+			g, ok := paths[result.Path]
+			if !ok {
+				g = &regrowth{}
+				paths[result.Path] = g
+			}
+			if g.Cleans > 0 {
+				g.bytes += result.BytesFreed
+				g.days += run.StartedAt.Sub(g.LastCleaned).Hours() / 24
+			}
+			g.Cleans++
+			g.LastCleaned = run.StartedAt
+		}
+	}
+
+	byPath := make(map[string]Regrowth, len(paths))
+	for path, g := range paths {
+		if g.days > 0 {
+			g.BytesPerDay = float64(g.bytes) / g.days
+		}
+		byPath[path] = g.Regrowth
+	}
+	return byPath
+}
+
+// Chronic reports whether the target keeps coming back fast
+func (g Regrowth) Chronic() bool {
+	return g.Cleans >= ChronicCleans && g.BytesPerDay >= ChronicPace
+}
+
+// SmartSelection is what smart cleaning does with the targets of a scan
+type SmartSelection struct {
+	Targets []cleaner.CleanTarget // To clean, chronic regrowers first
+	Skipped []cleaner.CleanTarget // Cleaned recently and back slowly since
+	Chronic []cleaner.CleanTarget // Chronic regrowers among Targets
+}
+
+// SelectSmart skips the targets cleaned less than recent ago that have come
+// back at less than SlowPace since, and moves the chronic regrowers first,
+// the fastest first
+func SelectSmart(targets []cleaner.CleanTarget, regrowth map[string]Regrowth, recent time.Duration, now time.Time) SmartSelection {
+	selection := SmartSelection{Targets: []cleaner.CleanTarget{}}
+	others := []cleaner.CleanTarget{}
+	for _, target := range targets {
+		g, ok := regrowth[target.Path]
+		since := now.Sub(g.LastCleaned)
+		switch {
+		case ok && since < recent && float64(target.SizeBytes) < SlowPace*max(since.Hours()/24, 1):
+			selection.Skipped = append(selection.Skipped, target)
+		case ok && g.Chronic():
+			selection.Chronic = append(selection.Chronic, target)
+		default:
+			others = append(others, target)
+		}
+	}
+
+	sort.SliceStable(selection.Chronic, func(i, j int) bool {
+		return regrowth[selection.Chronic[i].Path].BytesPerDay > regrowth[selection.Chronic[j].Path].BytesPerDay
+	})
+	selection.Targets = append(append(selection.Targets, selection.Chronic...), others...)
+	return selection
+}
@@ -0,0 +1,82 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+)
+
+func TestRegrowthByPath(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	runs := []Run{
+		{StartedAt: start, Results: []Result{
+			{Path: "/cache/npm", BytesFreed: 5e9, Success: true},
+			{Path: "/logs", BytesFreed: 1e6, Success: true},
+		}},
+		{StartedAt: start.Add(10 * day), Results: []Result{
+			{Path: "/cache/npm", BytesFreed: 2e9, Success: true},
+			{Path: "/logs", BytesFreed: 1e6, Success: false},
+		}},
+		{StartedAt: start.Add(20 * day), Results: []Result{
+			{Path: "/cache/npm", BytesFreed: 3e9, Success: true},
+		}},
+	}
+
+	regrowth := RegrowthByPath(runs)
+
+	npm := regrowth["/cache/npm"]
+	if npm.Cleans != 3 || !npm.LastCleaned.Equal(start.Add(20*day)) {
+		t.Errorf("Unexpected npm cleans %+v", npm)
+	}
+	if npm.BytesPerDay != 250e6 {
+		t.Errorf("Expected npm back at 250 MB a day, got %.0f", npm.BytesPerDay)
+	}
+	if !npm.Chronic() {
+		t.Error("Expected npm to be a chronic regrower")
+	}
+
+	logs := regrowth["/logs"]
+	if logs.Cleans != 1 || logs.BytesPerDay != 0 || logs.Chronic() {
+		t.Errorf("Expected a single clean of the logs, got %+v", logs)
+	}
+}
+
+func TestSelectSmart(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	regrowth := map[string]Regrowth{
+		"/logs":       {LastCleaned: now.Add(-2 * day), Cleans: 4},
+		"/cache/pip":  {LastCleaned: now.Add(-2 * day), Cleans: 1},
+		"/trash":      {LastCleaned: now.Add(-30 * day), Cleans: 1},
+		"/cache/npm":  {LastCleaned: now.Add(-10 * day), Cleans: 5, BytesPerDay: 200e6},
+		"/cache/yarn": {LastCleaned: now.Add(-10 * day), Cleans: 3, BytesPerDay: 400e6},
+	}
+	targets := []cleaner.CleanTarget{
+		{Path: "/logs", SizeBytes: 2e6},        // Slow since cleaned 2 days ago
+		{Path: "/cache/pip", SizeBytes: 900e6}, // Fast since cleaned 2 days ago
+		{Path: "/trash", SizeBytes: 1e6},       // Cleaned long ago
+		{Path: "/cache/npm", SizeBytes: 2e9},   // Chronic
+		{Path: "/tmp/new", SizeBytes: 1e6},     // Never cleaned
+		{Path: "/cache/yarn", SizeBytes: 4e9},  // Chronic, faster
+	}
+
+	selection := SelectSmart(targets, regrowth, 7*day, now)
+
+	if len(selection.Skipped) != 1 || selection.Skipped[0].Path != "/logs" {
+		t.Errorf("Expected only the logs skipped, got %+v", selection.Skipped)
+	}
+	if len(selection.Chronic) != 2 {
+		t.Errorf("Expected 2 chronic regrowers, got %+v", selection.Chronic)
+	}
+	want := []string{"/cache/yarn", "/cache/npm", "/cache/pip", "/trash", "/tmp/new"}
+	if len(selection.Targets) != len(want) {
+		t.Fatalf("Expected %d targets, got %+v", len(want), selection.Targets)
+	}
+	for i, path := range want {
+		if selection.Targets[i].Path != path {
+			t.Errorf("Target %d = %s, want %s", i, selection.Targets[i].Path, path)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+// ScanTimesFileName is the name of the file inside the state directory that
+// keeps how long each cleaner took to scan the last time it ran
+const ScanTimesFileName = "scan_times.json"
+
+// ScanTimes is how long each cleaner took to scan, by cleaner name
+type ScanTimes map[string]time.Duration
+
+// DefaultScanTimesPath returns the scan times file in the state directory
+func DefaultScanTimesPath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ScanTimesFileName), nil
+}
+
+// LoadScanTimes reads the scan times file. A missing or malformed file
+// holds no times: they only make estimates better.
+func LoadScanTimes(path string) ScanTimes {
+	times := ScanTimes{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return times
+	}
+	if err := json.Unmarshal(data, &times); err != nil {
+		return ScanTimes{}
+	}
+	return times
+}
+
+// Save writes the scan times to path, keeping the times of cleaners that
+// are not in times
+func (times ScanTimes) Save(path string) error {
+	merged := LoadScanTimes(path)
+	for name, d := range times {
+		merged[name] = d
+	}
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Expected returns how long each of the named cleaners is expected to take
+// to scan: its last time, or the mean of the known times for a cleaner
+// never timed. Without any known time, they are all expected to take the
+// same.
+func (times ScanTimes) Expected(names []string) []time.Duration {
+	var known time.Duration
+	count := 0
+	for _, name := range names {
+		if d, ok := times[name]; ok {
+			known += d
+			count++
+		}
+	}
+	mean := time.Second
+	if count > 0 {
+		mean = known / time.Duration(count)
+	}
+
+	expected := make([]time.Duration, len(names))
+	for i, name := range names {
+		d, ok := times[name]
+		if !ok {
+			d = mean
+		}
+		// Cleaners with nothing to scan last time still take a moment
+		expected[i] = max(d, time.Millisecond)
+	}
+	return expected
+}
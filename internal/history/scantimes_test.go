@@ -0,0 +1,52 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScanTimes_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", ScanTimesFileName)
+
+	if times := LoadScanTimes(path); len(times) != 0 {
+		t.Errorf("Expected no times without a file, got %v", times)
+	}
+
+	if err := (ScanTimes{"Frontend": 2 * time.Second, "System": time.Second}).Save(path); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	// Cleaners not scanned again keep their time
+	if err := (ScanTimes{"System": 3 * time.Second}).Save(path); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	want := ScanTimes{"Frontend": 2 * time.Second, "System": 3 * time.Second}
+	if got := LoadScanTimes(path); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadScanTimes() = %v, want %v", got, want)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if times := LoadScanTimes(path); len(times) != 0 {
+		t.Errorf("Expected no times from a malformed file, got %v", times)
+	}
+}
+
+func TestScanTimes_Expected(t *testing.T) {
+	times := ScanTimes{"Frontend": 4 * time.Second, "System": 2 * time.Second, "Trash": 0}
+
+	got := times.Expected([]string{"Frontend", "Backend", "System", "Trash"})
+	want := []time.Duration{4 * time.Second, 2 * time.Second, 2 * time.Second, time.Millisecond}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected() = %v, want %v", got, want)
+	}
+
+	got = ScanTimes{}.Expected([]string{"Frontend", "Backend"})
+	if got[0] != got[1] {
+		t.Errorf("Expected the same time for cleaners never timed, got %v", got)
+	}
+}
@@ -0,0 +1,62 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+// maxOutput is how much of the end of a hook's output is kept in the run
+// history
+const maxOutput = 4096
+
+// Result is the outcome of a hook, recorded in the run history
+type Result struct {
+	Name     string        `json:"name"` // e.g. "pre_clean", "pre DevOps"
+	Command  string        `json:"command"`
+	Output   string        `json:"output,omitempty"` // Combined stdout and stderr, possibly truncated
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Failed reports whether the hook could not run or exited with an error
+func (r Result) Failed() bool {
+	return r.Error != ""
+}
+
+// Run runs a hook with sh -c and captures its output. EPURER_HOOK is set to
+// the hook's name and env ("KEY=value") is added to the environment.
+func Run(ctx context.Context, name string, hook config.Hook, env ...string) Result {
+	start := time.Now()
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	cmd.Env = append(append(os.Environ(), "EPURER_HOOK="+name), env...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+
+	result := Result{
+		Name:     name,
+		Command:  hook.Command,
+		Output:   tail(strings.TrimSpace(output.String()), maxOutput),
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// tail returns the last n bytes of s, marked as truncated
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
@@ -0,0 +1,43 @@
+package hooks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestRun(t *testing.T) {
+	hook := config.Hook{Command: `echo "$EPURER_HOOK $EPURER_CLEANER"; echo warning >&2`}
+
+	result := Run(context.Background(), "pre DevOps", hook, "EPURER_CLEANER=DevOps")
+	if result.Failed() {
+		t.Fatalf("Expected the hook to succeed, got %s", result.Error)
+	}
+	if result.Output != "pre DevOps DevOps\nwarning" {
+		t.Errorf("Expected stdout and stderr to be captured, got %q", result.Output)
+	}
+	if result.Name != "pre DevOps" || result.Command != hook.Command {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestRun_Failure(t *testing.T) {
+	result := Run(context.Background(), "post_clean", config.Hook{Command: "echo oops; exit 3"})
+	if !result.Failed() || !strings.Contains(result.Error, "exit status 3") {
+		t.Errorf("Expected exit status 3, got %q", result.Error)
+	}
+	if result.Output != "oops" {
+		t.Errorf("Expected the output of a failed hook, got %q", result.Output)
+	}
+}
+
+func TestTail(t *testing.T) {
+	if got := tail("abcdef", 3); got != "...def" {
+		t.Errorf("Expected the end of the output, got %q", got)
+	}
+	if got := tail("abc", 3); got != "abc" {
+		t.Errorf("Expected short output unchanged, got %q", got)
+	}
+}
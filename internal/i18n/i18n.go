@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Lang is a language the CLI output can be rendered in
+type Lang string
+
+const (
+	English Lang = "en"
+	French  Lang = "fr"
+)
+
+// Langs lists the supported languages
+var Langs = []Lang{English, French}
+
+// Parse converts a --lang value or a locale (e.g. "fr", "fr_FR.UTF-8") to a
+// supported language
+func Parse(s string) (Lang, error) {
+	code := strings.ToLower(s)
+	if i := strings.IndexAny(code, "_-.@"); i >= 0 {
+		code = code[:i]
+	}
+
+	for _, lang := range Langs {
+		if code == string(lang) {
+			return lang, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported language %q (supported: en, fr)", s)
+}
+
+// FromEnv returns the language of the user's locale, from LC_ALL,
+// LC_MESSAGES or LANG in that order, English if it is not supported
+func FromEnv() Lang {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		if lang, err := Parse(value); err == nil {
+			return lang
+		}
+		// The first variable set decides, as for other programs
+		return English
+	}
+	return English
+}
+
+// Resolve returns the language selected by the --lang flag, or by the
+// locale when the flag is empty
+func Resolve(flag string) (Lang, error) {
+	if flag == "" {
+		return FromEnv(), nil
+	}
+	return Parse(flag)
+}
+
+// T returns the message for key in the language, formatted with args.
+// Messages missing from a catalog fall back to English, then to the key.
+func (l Lang) T(key string, args ...any) string {
+	msg, ok := messages[l][key]
+	if !ok {
+		msg, ok = messages[English][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
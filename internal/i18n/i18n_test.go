@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Lang
+		wantErr  bool
+	}{
+		{"en", English, false},
+		{"fr", French, false},
+		{"FR", French, false},
+		{"fr_FR.UTF-8", French, false},
+		{"fr-CA", French, false},
+		{"en_US.UTF-8", English, false},
+		{"de_DE.UTF-8", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		lcAll    string
+		lang     string
+		expected Lang
+	}{
+		{"LANG French", "", "fr_FR.UTF-8", French},
+		{"LANG English", "", "en_GB.UTF-8", English},
+		{"LC_ALL wins over LANG", "en_US.UTF-8", "fr_FR.UTF-8", English},
+		{"Unsupported locale", "", "de_DE.UTF-8", English},
+		{"C locale", "C", "fr_FR.UTF-8", English},
+		{"Unset", "", "", English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", "")
+			t.Setenv("LANG", tt.lang)
+
+			if got := FromEnv(); got != tt.expected {
+				t.Errorf("FromEnv() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "fr_FR.UTF-8")
+
+	if got, _ := Resolve(""); got != French {
+		t.Errorf("Resolve(\"\") = %q, want the locale's language", got)
+	}
+	if got, _ := Resolve("en"); got != English {
+		t.Errorf("Resolve(\"en\") = %q, the flag should win over the locale", got)
+	}
+	if _, err := Resolve("xx"); err == nil {
+		t.Error("Resolve(\"xx\") should fail")
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := French.T("results.done"); got != "Nettoyage terminé !" {
+		t.Errorf("French.T(results.done) = %q", got)
+	}
+	if got := English.T("prompt.proceed", 3); got != "Proceed with cleaning 3 items?" {
+		t.Errorf("English.T(prompt.proceed, 3) = %q", got)
+	}
+	// The zero language is English
+	if got := Lang("").T("results.done"); got != "Cleaning Complete!" {
+		t.Errorf("Lang(\"\").T(results.done) = %q", got)
+	}
+	if got := French.T("no.such.key"); got != "no.such.key" {
+		t.Errorf("Unknown keys should be returned as is, got %q", got)
+	}
+}
+
+func TestCatalogs_Complete(t *testing.T) {
+	for _, lang := range Langs {
+		for key := range messages[English] {
+			if _, ok := messages[lang][key]; !ok {
+				t.Errorf("%s catalog is missing %q", lang, key)
+			}
+		}
+		for key, msg := range messages[lang] {
+			english, ok := messages[English][key]
+			if !ok {
+				t.Errorf("%s catalog has %q, which English does not", lang, key)
+				continue
+			}
+			if strings.Count(msg, "%") != strings.Count(english, "%") {
+				t.Errorf("%s message %q takes other arguments than English", lang, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package i18n
+
+// messages is the message catalog, by language and key. English is the
+// reference: every key must exist there, other languages may lag behind.
+var messages = map[Lang]map[string]string{
+	English: {
+		// Header and detection
+		"header.subtitle": "Intelligent cache cleanup for macOS",
+		"detect.title":    "Detecting development tools...",
+
+		// Detected tools table
+		"detect.tool":        "TOOL",
+		"detect.version":     "VERSION",
+		"detect.footprint":   "ON DISK",
+		"detect.reclaimable": "RECLAIMABLE NOW",
+		"detect.path":        "PATH",
+		"detect.none":        "No development tools detected",
+		"detect.total":       "Reclaimable now from tool caches: %s",
+
+		// Estimation table
+		"estimation.title":   "Cleanup Estimation:",
+		"estimation.domain":  "DOMAIN",
+		"estimation.items":   "ITEMS",
+		"estimation.size":    "SIZE",
+		"estimation.safety":  "SAFETY",
+		"estimation.impact":  "IMPACT",
+		"estimation.total":   "Total",
+		"estimation.partial": "%s: partial scan (timed out)",
+
+		"impact.low":       "Low",
+		"impact.medium":    "Medium",
+		"impact.high":      "High",
+		"impact.very_high": "Very High",
+
+		// Safety levels
+		"safety.safe":      "Safe",
+		"safety.moderate":  "Mod",
+		"safety.dangerous": "Risk",
+		"legend.title":     "Safety Levels:",
+		"legend.safe":      "No risk, easily rebuilt (caches, logs)",
+		"legend.moderate":  "Rebuild needed (dependencies, build outputs)",
+		"legend.dangerous": "Potential data loss (backups, databases)",
+
+		// Clean results
+		"results.dry_run":         "Dry Run Complete - No files were deleted",
+		"results.done":            "Cleaning Complete!",
+		"progress.eta":            "about %s left",
+		"results.space.dry_run":   "Space would be freed",
+		"results.space":           "Space freed",
+		"results.items.dry_run":   "Items would be freed",
+		"results.items":           "Items freed",
+		"results.estimated":       "Estimated: %s (%s actual)",
+		"results.actions.dry_run": "Actions that would run",
+		"results.actions":         "Actions run",
+		"results.files_deleted":   "Files deleted",
+		"results.quarantined":     "Moved to quarantine",
+		"results.archived":        "Moved to archive folders",
+		"results.failures":        "Failures",
+		"results.failed_items":    "Failed Items:",
+		"results.partial_removal": "%s files deleted, %s freed",
+		"results.by_project":      "By Project:",
+		"results.commands":        "Commands that would run:",
+		"results.nothing":         "Nothing to clean!",
+		"results.verified":        "Checked on disk: %s freed as expected, %s partially freed, %s failed",
+		"results.verify_partial":  "%s freed, %s left",
+		"results.verify_failed":   "still there (%s)",
+
+		// Rebuild cost of targets that must be reinstalled
+		"rebuild.download": "Rebuild: ~%s download, ~%s (%s)",
+		"rebuild.compile":  "Rebuild: ~%s (%s)",
+
+		// Maintenance actions, run instead of deleting files
+		"action.label": "action",
+		"action.runs":  "Runs: %s",
+
+		// Contents of targets whose files were counted
+		"target.files": "%s, %s files",
+
+		// Prompts
+		"prompt.yes_no":            "[y/N]",
+		"prompt.target":            "Clean this target? [y/N/a(ll)/q(uit)]",
+		"prompt.proceed":           "Proceed with cleaning %d items?",
+		"prompt.proceed_remaining": "Proceed with cleaning %d remaining items?",
+		"prompt.cancelled":         "Cancelled",
+
+		// Explanations
+		"explain.title":       "What is this?",
+		"explain.targets":     "%d items • %s",
+		"explain.what":        "What it is",
+		"explain.regenerates": "What brings it back",
+		"explain.consequence": "If deleted",
+		"explain.none":        "No explanation for these items yet",
+		"consequences.title":  "After cleaning, you will need to:",
+
+		// Interactive mode
+		"tui.subtitle":     "Interactive cleanup mode",
+		"tui.select_title": "Select domains to clean",
+		"tui.item":         "%s • %d items",
+		"tui.rebuild":      "rebuild ~%s download, ~%s",
+		"tui.selected":     "Selected: %d domains • %s",
+		"tui.help_select":  "↑/↓: navigate • space: toggle • A: all • n: none • s/a/d/r: sort by size/age/domain/safety • /: filter • ?: what is this • enter: confirm • q: quit",
+		"tui.sorted":       "(by %s)",
+		"tui.sort_size":    "size",
+		"tui.sort_age":     "age",
+		"tui.sort_domain":  "domain",
+		"tui.sort_safety":  "safety",
+		"tui.largest":      "Largest in %s:",
+		"tui.help_explain": "Press any key to go back",
+		"tui.confirm":      "Clean %d domains (%s)?",
+		"tui.filtered":     "Only the %d targets matching \"%s\" are cleaned, %d selected domains it hides are left alone",
+		"tui.dry_run_tag":  "(DRY RUN)",
+		"tui.help_confirm": "y: yes • n: no",
+		"tui.cleaning":     "Cleaning...",
+		"tui.cleaned":      "Cleaned: %d/%d items • %s freed",
+		"tui.removing":     "Deleting %s: %s/%s files",
+		"tui.paused":       "Paused, the deletions in progress finish first",
+		"tui.stopping":     "Stopping once the deletions in progress finish...",
+		"tui.help_clean":   "p: pause/resume • x: stop",
+		"tui.log_freed":    "%s: %s freed",
+		"tui.log_moved":    "%s: %s moved to quarantine",
+		"tui.log_archived": "%s: %s moved to %s",
+		"tui.log_dry_run":  "%s: %s would be freed",
+		"tui.log_more":     "... %d more above",
+		"tui.stopped":      "Stopped after %d of %d items",
+		"tui.failed":       "%d items failed:",
+		"tui.dry_run_done": "Dry run complete!",
+		"tui.done":         "Cleaning complete!",
+		"tui.summary":      "💾 Space freed: %s\n📁 Items cleaned: %d",
+		"tui.moved":        "📦 Moved to quarantine: %s, run epurer restore to bring it back",
+		"tui.help_done":    "Press enter or q to exit",
+	},
+
+	French: {
+		"header.subtitle": "Nettoyage intelligent des caches pour macOS",
+		"detect.title":    "Détection des outils de développement...",
+
+		"detect.tool":        "OUTIL",
+		"detect.version":     "VERSION",
+		"detect.footprint":   "SUR DISQUE",
+		"detect.reclaimable": "RÉCUPÉRABLE",
+		"detect.path":        "CHEMIN",
+		"detect.none":        "Aucun outil de développement détecté",
+		"detect.total":       "Récupérable dès maintenant dans les caches des outils : %s",
+
+		"estimation.title":   "Estimation du nettoyage :",
+		"estimation.domain":  "DOMAINE",
+		"estimation.items":   "OBJETS",
+		"estimation.size":    "TAILLE",
+		"estimation.safety":  "SÉCURITÉ",
+		"estimation.impact":  "IMPACT",
+		"estimation.total":   "Total",
+		"estimation.partial": "%s : analyse partielle (délai dépassé)",
+
+		"impact.low":       "Faible",
+		"impact.medium":    "Moyen",
+		"impact.high":      "Élevé",
+		"impact.very_high": "Très élevé",
+
+		"safety.safe":      "Sûr",
+		"safety.moderate":  "Mod",
+		"safety.dangerous": "Risque",
+		"legend.title":     "Niveaux de sécurité :",
+		"legend.safe":      "Aucun risque, facile à reconstruire (caches, journaux)",
+		"legend.moderate":  "À reconstruire (dépendances, résultats de build)",
+		"legend.dangerous": "Perte de données possible (sauvegardes, bases de données)",
+
+		"results.dry_run":         "Simulation terminée - Aucun fichier supprimé",
+		"results.done":            "Nettoyage terminé !",
+		"progress.eta":            "environ %s restant",
+		"results.space.dry_run":   "Espace libérable",
+		"results.space":           "Espace libéré",
+		"results.items.dry_run":   "Éléments à nettoyer",
+		"results.items":           "Éléments nettoyés",
+		"results.estimated":       "Estimé : %s (%s en réalité)",
+		"results.actions.dry_run": "Actions à lancer",
+		"results.actions":         "Actions lancées",
+		"results.files_deleted":   "Fichiers supprimés",
+		"results.quarantined":     "Déplacé en quarantaine",
+		"results.archived":        "Déplacé dans les dossiers d'archive",
+		"results.failures":        "Échecs",
+		"results.failed_items":    "Éléments en échec :",
+		"results.partial_removal": "%s fichiers supprimés, %s libérés",
+		"results.by_project":      "Par projet :",
+		"results.commands":        "Commandes qui seraient lancées :",
+		"results.nothing":         "Rien à nettoyer !",
+		"results.verified":        "Vérifié sur le disque : %s libérés comme prévu, %s en partie, %s en échec",
+		"results.verify_partial":  "%s libérés, %s restants",
+		"results.verify_failed":   "toujours présent (%s)",
+
+		"rebuild.download": "Reconstruction : ~%s à télécharger, ~%s (%s)",
+		"rebuild.compile":  "Reconstruction : ~%s (%s)",
+
+		// Maintenance actions, run instead of deleting files
+		"action.label": "action",
+		"action.runs":  "Lance : %s",
+
+		"target.files": "%s, %s fichiers",
+
+		"prompt.yes_no":            "[o/N]",
+		"prompt.target":            "Nettoyer cette cible ? [o/N/t(out)/q(uitter)]",
+		"prompt.proceed":           "Lancer le nettoyage de %d éléments ?",
+		"prompt.proceed_remaining": "Lancer le nettoyage des %d éléments restants ?",
+		"prompt.cancelled":         "Annulé",
+
+		// Explications
+		"explain.title":       "Qu'est-ce que c'est ?",
+		"explain.targets":     "%d éléments • %s",
+		"explain.what":        "De quoi s'agit-il",
+		"explain.regenerates": "Ce qui le recrée",
+		"explain.consequence": "Si supprimé",
+		"explain.none":        "Pas encore d'explication pour ces éléments",
+		"consequences.title":  "Après le nettoyage, il faudra :",
+
+		"tui.subtitle":     "Mode de nettoyage interactif",
+		"tui.select_title": "Domaines à nettoyer",
+		"tui.item":         "%s • %d éléments",
+		"tui.rebuild":      "reconstruction ~%s à télécharger, ~%s",
+		"tui.selected":     "Sélection : %d domaines • %s",
+		"tui.help_select":  "↑/↓ : naviguer • espace : cocher • A : tout • n : aucun • s/a/d/r : trier par taille/âge/domaine/sûreté • / : filtrer • ? : qu'est-ce que c'est • entrée : valider • q : quitter",
+		"tui.sorted":       "(par %s)",
+		"tui.sort_size":    "taille",
+		"tui.sort_age":     "âge",
+		"tui.sort_domain":  "domaine",
+		"tui.sort_safety":  "sûreté",
+		"tui.largest":      "Les plus gros de %s :",
+		"tui.help_explain": "Appuyez sur une touche pour revenir",
+		"tui.confirm":      "Nettoyer %d domaines (%s) ?",
+		"tui.filtered":     "Seules les %d cibles correspondant à « %s » sont nettoyées, %d domaines sélectionnés qu'il masque sont laissés",
+		"tui.dry_run_tag":  "(SIMULATION)",
+		"tui.help_confirm": "o : oui • n : non",
+		"tui.cleaning":     "Nettoyage...",
+		"tui.cleaned":      "Nettoyés : %d/%d éléments • %s libérés",
+		"tui.removing":     "Suppression de %s : %s/%s fichiers",
+		"tui.paused":       "En pause, les suppressions en cours se terminent d'abord",
+		"tui.stopping":     "Arrêt une fois les suppressions en cours terminées...",
+		"tui.help_clean":   "p : pause/reprise • x : arrêter",
+		"tui.log_freed":    "%s : %s libérés",
+		"tui.log_moved":    "%s : %s déplacés en quarantaine",
+		"tui.log_archived": "%s : %s déplacés dans %s",
+		"tui.log_dry_run":  "%s : %s seraient libérés",
+		"tui.log_more":     "... %d de plus au-dessus",
+		"tui.stopped":      "Arrêté après %d éléments sur %d",
+		"tui.failed":       "%d éléments en échec :",
+		"tui.dry_run_done": "Simulation terminée !",
+		"tui.done":         "Nettoyage terminé !",
+		"tui.summary":      "💾 Espace libéré : %s\n📁 Éléments nettoyés : %d",
+		"tui.moved":        "📦 Déplacé en quarantaine : %s, epurer restore pour le récupérer",
+		"tui.help_done":    "Appuyez sur entrée ou q pour quitter",
+	},
+}
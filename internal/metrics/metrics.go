@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/history"
+)
+
+// Snapshot is what one scan, and the last clean run, tell about a machine
+type Snapshot struct {
+	ScannedAt    time.Time
+	ScanDuration time.Duration
+	Reclaimable  map[string]int64 // Bytes that can be cleaned, by domain key
+	LastRun      *history.Run     // nil if nothing was ever cleaned
+}
+
+// Write writes the snapshot in the Prometheus text format
+func (s Snapshot) Write(w io.Writer) error {
+	var b bytes.Buffer
+
+	gauge := func(name, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+
+	gauge("epurer_reclaimable_bytes", "Space the cleaners can free, by domain.")
+	domains := make([]string, 0, len(s.Reclaimable))
+	for domain := range s.Reclaimable {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	for _, domain := range domains {
+		fmt.Fprintf(&b, "epurer_reclaimable_bytes{domain=%q} %d\n", domain, s.Reclaimable[domain])
+	}
+
+	gauge("epurer_scan_duration_seconds", "How long the last scan took.")
+	fmt.Fprintf(&b, "epurer_scan_duration_seconds %g\n", s.ScanDuration.Seconds())
+	gauge("epurer_scan_timestamp_seconds", "When the last scan ran, as a Unix time.")
+	fmt.Fprintf(&b, "epurer_scan_timestamp_seconds %d\n", s.ScannedAt.Unix())
+
+	if s.LastRun != nil {
+		failed := 0
+		for _, result := range s.LastRun.Results {
+			if !result.Success {
+				failed++
+			}
+		}
+
+		gauge("epurer_last_run_freed_bytes", "Space freed by the last clean run.")
+		fmt.Fprintf(&b, "epurer_last_run_freed_bytes %d\n", s.LastRun.BytesFreed())
+		gauge("epurer_last_run_failed_targets", "Targets the last clean run failed to clean.")
+		fmt.Fprintf(&b, "epurer_last_run_failed_targets %d\n", failed)
+		gauge("epurer_last_run_timestamp_seconds", "When the last clean run started, as a Unix time.")
+		fmt.Fprintf(&b, "epurer_last_run_timestamp_seconds %d\n", s.LastRun.StartedAt.Unix())
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteFile writes the snapshot to path, for the textfile collector of the
+// node exporter. The file is replaced in one step so it is never read half
+// written.
+func WriteFile(path string, s Snapshot) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := s.Write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Exporter serves the latest snapshot over HTTP, for Prometheus to scrape
+type Exporter struct {
+	mu       sync.Mutex
+	snapshot Snapshot
+}
+
+// Set replaces the snapshot served
+func (e *Exporter) Set(s Snapshot) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.snapshot = s
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	snapshot := e.snapshot
+	e.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	snapshot.Write(w)
+}
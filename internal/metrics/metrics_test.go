@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/history"
+)
+
+func testSnapshot() Snapshot {
+	return Snapshot{
+		ScannedAt:    time.Unix(1736931600, 0),
+		ScanDuration: 1500 * time.Millisecond,
+		Reclaimable:  map[string]int64{"system": 300, "frontend": 2000},
+		LastRun: &history.Run{
+			StartedAt: time.Unix(1736900000, 0),
+			Results: []history.Result{
+				{BytesFreed: 500, Success: true},
+				{Error: "permission denied"},
+			},
+		},
+	}
+}
+
+func TestSnapshot_Write(t *testing.T) {
+	var b strings.Builder
+	if err := testSnapshot().Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	output := b.String()
+
+	for _, line := range []string{
+		"# TYPE epurer_reclaimable_bytes gauge",
+		"epurer_reclaimable_bytes{domain=\"frontend\"} 2000\nepurer_reclaimable_bytes{domain=\"system\"} 300\n",
+		"epurer_scan_duration_seconds 1.5\n",
+		"epurer_scan_timestamp_seconds 1736931600\n",
+		"epurer_last_run_freed_bytes 500\n",
+		"epurer_last_run_failed_targets 1\n",
+		"epurer_last_run_timestamp_seconds 1736900000\n",
+	} {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected %q in:\n%s", line, output)
+		}
+	}
+}
+
+func TestSnapshot_Write_NoRun(t *testing.T) {
+	s := testSnapshot()
+	s.LastRun = nil
+
+	var b strings.Builder
+	s.Write(&b)
+	if strings.Contains(b.String(), "epurer_last_run") {
+		t.Error("Expected no last run metrics without a run")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "epurer.prom")
+
+	if err := WriteFile(path, testSnapshot()); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "epurer_reclaimable_bytes") {
+		t.Errorf("Expected metrics in the file, got %q (%v)", data, err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary file left, got %d entries", len(entries))
+	}
+}
+
+func TestExporter(t *testing.T) {
+	e := &Exporter{}
+	e.Set(testSnapshot())
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Unexpected content type %q", w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), "epurer_last_run_freed_bytes 500") {
+		t.Errorf("Expected the snapshot served, got:\n%s", w.Body.String())
+	}
+}
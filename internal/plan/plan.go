@@ -0,0 +1,276 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// ManifestFileName is the name of the run manifest inside the state directory
+const ManifestFileName = "run.json"
+
+// Status is the progress of a single plan item
+type Status string
+
+const (
+	StatusPending Status = "pending" // Not processed yet
+	StatusDone    Status = "done"    // Cleaned successfully
+	StatusFailed  Status = "failed"  // Cleaning was attempted and failed
+)
+
+// Plan is an ordered list of targets to clean. It is written to disk before a
+// clean run starts so an interrupted run can be resumed.
+type Plan struct {
+	CreatedAt time.Time `json:"created_at"`
+	Level     string    `json:"level"` // Clean level the targets were scanned at
+	Items     []Item    `json:"items"`
+}
+
+// Item is a single target in a plan
+type Item struct {
+	Cleaner     string    `json:"cleaner"`
+	Path        string    `json:"path"`
+	Category    string    `json:"category,omitempty"`
+	Description string    `json:"description"`
+	SizeBytes   int64     `json:"size_bytes"`
+	Files       int       `json:"files,omitempty"` // Files inside, if counted
+	Dirs        int       `json:"dirs,omitempty"`  // Folders inside, if counted
+	Safety      string    `json:"safety"`
+	Entries     []string  `json:"entries,omitempty"`
+	Action      string    `json:"action,omitempty"`      // "evict" for cloud-synced targets, "archive" to move, empty to delete
+	ArchiveDir  string    `json:"archive_dir,omitempty"` // Folder archived targets are moved into
+	ModTime     time.Time `json:"mod_time"`              // Modification time of Path when planned
+	Status      Status    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// New builds a plan from scan results, grouped by cleaner name in a stable
+// order. Every item starts as pending.
+func New(targetsByDomain map[string][]cleaner.CleanTarget, level config.CleanLevel) *Plan {
+	names := make([]string, 0, len(targetsByDomain))
+	for name := range targetsByDomain {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p := &Plan{
+		CreatedAt: time.Now(),
+		Level:     level.String(),
+		Items:     []Item{},
+	}
+
+	for _, name := range names {
+		for _, target := range targetsByDomain[name] {
+			item := Item{
+				Cleaner:     name,
+				Path:        target.Path,
+				Category:    target.Category,
+				Description: target.Description,
+				SizeBytes:   target.SizeBytes,
+				Files:       target.Files,
+				Dirs:        target.Dirs,
+				Safety:      strings.ToLower(target.Safety.String()),
+				Entries:     target.Entries,
+				ArchiveDir:  target.ArchiveDir,
+				Status:      StatusPending,
+			}
+			if target.Action != cleaner.ActionDelete {
+				item.Action = target.Action.String()
+			}
+			if info, err := os.Lstat(target.Path); err == nil {
+				item.ModTime = info.ModTime()
+			}
+			p.Items = append(p.Items, item)
+		}
+	}
+
+	return p
+}
+
+// Target converts the item back into a clean target
+func (i Item) Target() cleaner.CleanTarget {
+	safety, err := config.ParseSafetyLevel(i.Safety)
+	if err != nil {
+		// Unknown safety in a hand-edited plan: treat it as the riskiest
+		safety = config.Dangerous
+	}
+
+	action, err := cleaner.ParseAction(i.Action)
+	if err != nil {
+		// Unknown action: evicting never loses data, deleting might
+		action = cleaner.ActionEvict
+	}
+
+	return cleaner.CleanTarget{
+		Path:        i.Path,
+		Category:    i.Category,
+		Description: i.Description,
+		SizeBytes:   i.SizeBytes,
+		Files:       i.Files,
+		Dirs:        i.Dirs,
+		Safety:      safety,
+		Entries:     i.Entries,
+		Action:      action,
+		ArchiveDir:  i.ArchiveDir,
+	}
+}
+
+// Pending returns the indexes of the items not processed yet
+func (p *Plan) Pending() []int {
+	pending := []int{}
+	for i, item := range p.Items {
+		if item.Status == StatusPending {
+			pending = append(pending, i)
+		}
+	}
+	return pending
+}
+
+// Targets returns the pending items as clean targets grouped by cleaner name,
+// the shape scan results are reported in
+func (p *Plan) Targets() map[string][]cleaner.CleanTarget {
+	targets := make(map[string][]cleaner.CleanTarget)
+	for _, i := range p.Pending() {
+		item := p.Items[i]
+		targets[item.Cleaner] = append(targets[item.Cleaner], item.Target())
+	}
+	return targets
+}
+
+// Record stores the outcome of cleaning the item at index i
+func (p *Plan) Record(i int, result cleaner.CleanResult) {
+	if result.Success {
+		p.Items[i].Status = StatusDone
+		p.Items[i].Error = ""
+		return
+	}
+
+	p.Items[i].Status = StatusFailed
+	if result.Error != nil {
+		p.Items[i].Error = result.Error.Error()
+	}
+}
+
+// Tolerance is how much a target may change between planning and applying
+// before the plan is considered stale
+type Tolerance struct {
+	SizePercent float64       // Allowed size change, in percent of the planned size
+	ModTime     time.Duration // Allowed shift of the modification time
+}
+
+// DefaultTolerance accepts small size changes but no modification
+var DefaultTolerance = Tolerance{SizePercent: 10}
+
+// Drift is a pending item that changed on disk since it was planned
+type Drift struct {
+	Item   Item
+	Reason string
+}
+
+// Verify compares every pending item with the disk and returns the ones that
+// changed beyond tol. Items that no longer exist have nothing left to delete
+// and are not reported, nor are non-filesystem targets such as docker:images.
+func (p *Plan) Verify(tol Tolerance) []Drift {
+	drifts := []Drift{}
+	for _, i := range p.Pending() {
+		item := p.Items[i]
+		if reason := item.drift(tol); reason != "" {
+			drifts = append(drifts, Drift{Item: item, Reason: reason})
+		}
+	}
+	return drifts
+}
+
+// drift describes how the item changed beyond tol, or returns "" if it didn't
+func (i Item) drift(tol Tolerance) string {
+	if !filepath.IsAbs(i.Path) {
+		return ""
+	}
+
+	info, err := os.Lstat(i.Path)
+	if err != nil {
+		return ""
+	}
+
+	if !i.ModTime.IsZero() {
+		shift := info.ModTime().Sub(i.ModTime)
+		if shift > tol.ModTime || shift < -tol.ModTime {
+			return fmt.Sprintf("modified at %s", info.ModTime().Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	size := i.currentSize()
+	allowed := int64(float64(i.SizeBytes) * tol.SizePercent / 100)
+	if diff := size - i.SizeBytes; diff > allowed || diff < -allowed {
+		return fmt.Sprintf("size changed from %s to %s", utils.FormatBytes(i.SizeBytes), utils.FormatBytes(size))
+	}
+
+	return ""
+}
+
+// currentSize measures the item on disk, counting only its entries if set
+func (i Item) currentSize() int64 {
+	if len(i.Entries) == 0 {
+		size, _ := utils.GetDirSize(i.Path)
+		return size
+	}
+
+	var total int64
+	for _, entry := range i.Entries {
+		size, _ := utils.GetDirSize(entry)
+		total += size
+	}
+	return total
+}
+
+// ManifestPath returns the run manifest in the state directory
+func ManifestPath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ManifestFileName), nil
+}
+
+// Save writes the plan to path. The file is replaced atomically so a crash
+// never leaves a truncated plan behind.
+func Save(path string, p *Plan) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Load reads a plan from path
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid plan %s: %w", path, err)
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,207 @@
+package plan
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+
+	targets := map[string][]cleaner.CleanTarget{
+		"System":   {{Path: filepath.Join(dir, "logs"), SizeBytes: 10, Safety: config.Safe}},
+		"Frontend": {{Path: dir, Description: "npm cache", SizeBytes: 20, Safety: config.Moderate}},
+	}
+
+	p := New(targets, config.Standard)
+
+	if len(p.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(p.Items))
+	}
+	if p.Items[0].Cleaner != "Frontend" || p.Items[1].Cleaner != "System" {
+		t.Errorf("Items not sorted by cleaner: %s, %s", p.Items[0].Cleaner, p.Items[1].Cleaner)
+	}
+	if p.Items[0].Safety != "moderate" {
+		t.Errorf("Expected safety 'moderate', got %q", p.Items[0].Safety)
+	}
+	if p.Items[0].ModTime.IsZero() {
+		t.Error("Expected ModTime of an existing path to be set")
+	}
+	if !p.Items[1].ModTime.IsZero() {
+		t.Error("Expected zero ModTime for a missing path")
+	}
+	if len(p.Pending()) != 2 {
+		t.Errorf("Expected all items pending, got %d", len(p.Pending()))
+	}
+}
+
+func TestRecord(t *testing.T) {
+	p := &Plan{Items: []Item{
+		{Path: "/a", Status: StatusPending},
+		{Path: "/b", Status: StatusPending},
+		{Path: "/c", Status: StatusPending},
+	}}
+
+	p.Record(0, cleaner.CleanResult{Success: true})
+	p.Record(2, cleaner.CleanResult{Error: errors.New("permission denied")})
+
+	if p.Items[0].Status != StatusDone {
+		t.Errorf("Expected item 0 done, got %s", p.Items[0].Status)
+	}
+	if p.Items[2].Status != StatusFailed || p.Items[2].Error != "permission denied" {
+		t.Errorf("Expected item 2 failed with error, got %+v", p.Items[2])
+	}
+
+	pending := p.Pending()
+	if len(pending) != 1 || pending[0] != 1 {
+		t.Errorf("Expected only item 1 pending, got %v", pending)
+	}
+
+	targets := p.Targets()
+	if len(targets[""]) != 1 || targets[""][0].Path != "/b" {
+		t.Errorf("Expected only /b in targets, got %+v", targets)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", ManifestFileName)
+
+	p := &Plan{
+		Level: "standard",
+		Items: []Item{
+			{Cleaner: "Frontend", Path: "/a", SizeBytes: 100, Safety: "safe", Status: StatusDone},
+			{Cleaner: "Frontend", Path: "/b", Entries: []string{"/b/x"}, Safety: "moderate", Status: StatusPending},
+		},
+	}
+
+	if err := Save(path, p); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Temporary file left behind")
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	if loaded.Level != "standard" || len(loaded.Items) != 2 {
+		t.Fatalf("Plan not restored: %+v", loaded)
+	}
+	if loaded.Items[0].Status != StatusDone || len(loaded.Items[1].Entries) != 1 {
+		t.Errorf("Items not restored: %+v", loaded.Items)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ManifestFileName)
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("Expected error for invalid plan")
+	}
+}
+
+func TestItemTarget(t *testing.T) {
+	target := Item{Path: "/a", SizeBytes: 5, Safety: "safe"}.Target()
+	if target.Safety != config.Safe || target.Path != "/a" || target.SizeBytes != 5 {
+		t.Errorf("Unexpected target: %+v", target)
+	}
+
+	target = Item{Path: "/a", Safety: "bogus"}.Target()
+	if target.Safety != config.Dangerous {
+		t.Errorf("Expected unknown safety to become Dangerous, got %s", target.Safety)
+	}
+	if target.Action != cleaner.ActionDelete {
+		t.Errorf("Expected no action to mean delete, got %s", target.Action)
+	}
+
+	target = Item{Path: "/a", Safety: "safe", Action: "evict"}.Target()
+	if target.Action != cleaner.ActionEvict {
+		t.Errorf("Expected evict action, got %s", target.Action)
+	}
+
+	target = Item{Path: "/a", Safety: "safe", Action: "archive", ArchiveDir: "/archive"}.Target()
+	if target.Action != cleaner.ActionArchive || target.ArchiveDir != "/archive" {
+		t.Errorf("Expected archive action into /archive, got %s into %q", target.Action, target.ArchiveDir)
+	}
+
+	target = Item{Path: "/a", Safety: "safe", Action: "bogus"}.Target()
+	if target.Action != cleaner.ActionEvict {
+		t.Errorf("Expected unknown action to become evict, got %s", target.Action)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+
+	unchanged := filepath.Join(dir, "unchanged")
+	grown := filepath.Join(dir, "grown")
+	touched := filepath.Join(dir, "touched")
+	for _, path := range []string{unchanged, grown, touched} {
+		if err := os.WriteFile(path, make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	targets := map[string][]cleaner.CleanTarget{
+		"Test": {
+			{Path: unchanged, SizeBytes: 100},
+			{Path: grown, SizeBytes: 100},
+			{Path: touched, SizeBytes: 100},
+			{Path: filepath.Join(dir, "gone"), SizeBytes: 100},
+			{Path: "docker:images:dangling", SizeBytes: 100},
+		},
+	}
+	p := New(targets, config.Standard)
+
+	if err := os.WriteFile(grown, make([]byte, 105), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(grown, p.Items[1].ModTime, p.Items[1].ModTime); err != nil {
+		t.Fatal(err)
+	}
+
+	if drifts := p.Verify(DefaultTolerance); len(drifts) != 0 {
+		t.Fatalf("Expected no drift within tolerance, got %+v", drifts)
+	}
+
+	if err := os.WriteFile(grown, make([]byte, 200), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(grown, p.Items[1].ModTime, p.Items[1].ModTime); err != nil {
+		t.Fatal(err)
+	}
+	later := p.Items[2].ModTime.Add(time.Hour)
+	if err := os.Chtimes(touched, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	drifts := p.Verify(DefaultTolerance)
+	if len(drifts) != 2 {
+		t.Fatalf("Expected 2 drifts, got %+v", drifts)
+	}
+	if drifts[0].Item.Path != grown || drifts[1].Item.Path != touched {
+		t.Errorf("Unexpected drifted items: %s, %s", drifts[0].Item.Path, drifts[1].Item.Path)
+	}
+
+	if drifts := p.Verify(Tolerance{SizePercent: 100, ModTime: 2 * time.Hour}); len(drifts) != 0 {
+		t.Errorf("Expected no drift with a loose tolerance, got %+v", drifts)
+	}
+
+	// Processed items are not checked again
+	p.Items[1].Status = StatusDone
+	p.Items[2].Status = StatusDone
+	if drifts := p.Verify(DefaultTolerance); len(drifts) != 0 {
+		t.Errorf("Expected processed items to be skipped, got %+v", drifts)
+	}
+}
@@ -0,0 +1,158 @@
+// Package platform holds what differs between the systems epurer runs on:
+// where the user's caches, data, trash, logs and temporary files live, and
+// whether the macOS-only cleaners apply. macOS keeps them below ~/Library;
+// Linux follows the XDG base directory specification; Windows keeps them in
+// %LOCALAPPDATA% and %APPDATA%.
+package platform
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Name returns the name of the platform, e.g. "macOS" or "Linux"
+func Name() string {
+	return name
+}
+
+// IsMacOS reports whether epurer runs on macOS, where the cleaners of its
+// databases and apps (DNS cache, Launchpad, iOS backups, Mail) apply
+func IsMacOS() bool {
+	return macOS
+}
+
+// CacheDir returns the folder of the user's application caches below home:
+// ~/Library/Caches on macOS, $XDG_CACHE_HOME or ~/.cache on Linux. It is ""
+// on Windows, which keeps caches among application data.
+func CacheDir(home string) string {
+	return cacheDir(home)
+}
+
+// DataDir returns the folder of the user's application data below home:
+// ~/Library/Application Support on macOS, $XDG_DATA_HOME or ~/.local/share
+// on Linux, %LOCALAPPDATA% on Windows
+func DataDir(home string) string {
+	return dataDir(home)
+}
+
+// ConfigDir returns the folder of the user's application settings below
+// home: ~/Library/Application Support on macOS, $XDG_CONFIG_HOME or
+// ~/.config on Linux, %APPDATA% on Windows
+func ConfigDir(home string) string {
+	return configDir(home)
+}
+
+// TrashDir returns the user's trash below home: ~/.Trash on macOS, the
+// Trash folder of the data directory on Linux, "" on Windows, whose
+// Recycle Bin is emptied by the shell
+func TrashDir(home string) string {
+	return trashDir(home)
+}
+
+// VolumeTrashPatterns returns the glob patterns of the trash folders of
+// external volumes
+func VolumeTrashPatterns() []string {
+	return volumeTrashPatterns()
+}
+
+// SystemCacheDir returns the folder of the caches shared by all users, or
+// "" where it is not safe to empty as a whole (/var/cache on Linux holds
+// package manager state)
+func SystemCacheDir() string {
+	return systemCacheDir
+}
+
+// SystemLogDir returns the folder of the system log files, "" if there is
+// none
+func SystemLogDir() string {
+	return systemLogDir
+}
+
+// SystemLogPatterns returns the glob patterns of the log files of
+// SystemLogDir that can be deleted: all of them on macOS, the rotated ones
+// elsewhere
+func SystemLogPatterns() []string {
+	return systemLogPatterns
+}
+
+// TempDirs returns the system folders of temporary files
+func TempDirs() []string {
+	return tempDirs
+}
+
+// WholeTempDirs reports whether the temp folders are emptied as a whole.
+// Elsewhere than macOS they hold the sockets and state of running sessions
+// (X11, ssh-agent, tmux), and only the user's old files are offered.
+func WholeTempDirs() bool {
+	return wholeTempDirs
+}
+
+// Layout is how a system lays out a home directory. It tells where tools
+// keep their caches in the home of this machine, or in another one scanned
+// such as the Windows side of WSL.
+type Layout int
+
+const (
+	MacOSLayout Layout = iota
+	LinuxLayout
+	WindowsLayout
+)
+
+// Current returns the layout of this machine's home directories
+func Current() Layout {
+	return layout
+}
+
+// ToolCacheDir returns the folder cross-platform tools (pip, Go, Yarn)
+// keep their caches in below home: ~/Library/Caches, ~/.cache, or
+// %LOCALAPPDATA% (AppData\Local). For this machine's layout the variables
+// moving it are followed.
+func (l Layout) ToolCacheDir(home string) string {
+	if l == Current() {
+		if l == WindowsLayout {
+			return DataDir(home)
+		}
+		return CacheDir(home)
+	}
+	switch l {
+	case MacOSLayout:
+		return filepath.Join(home, "Library", "Caches")
+	case WindowsLayout:
+		return filepath.Join(home, "AppData", "Local")
+	default:
+		return filepath.Join(home, ".cache")
+	}
+}
+
+// DataDir returns the folder of application data below home: ~/Library/
+// Application Support, ~/.local/share, or %LOCALAPPDATA% (AppData\Local).
+// For this machine's layout the variables moving it are followed.
+func (l Layout) DataDir(home string) string {
+	if l == Current() {
+		return DataDir(home)
+	}
+	switch l {
+	case MacOSLayout:
+		return filepath.Join(home, "Library", "Application Support")
+	case WindowsLayout:
+		return filepath.Join(home, "AppData", "Local")
+	default:
+		return filepath.Join(home, ".local", "share")
+	}
+}
+
+// envDir returns the folder an environment variable names, or the default
+// below home when it is unset or relative
+func envDir(env, home string, defaults ...string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(append([]string{home}, defaults...)...)
+}
+
+// xdgDir returns the folder an XDG base directory variable names, or the
+// default below home when it is unset or relative, which the specification
+// says to ignore
+func xdgDir(env, home string, defaults ...string) string {
+	return envDir(env, home, defaults...)
+}
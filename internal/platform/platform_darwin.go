@@ -0,0 +1,38 @@
+//go:build darwin
+
+package platform
+
+import "path/filepath"
+
+const (
+	name           = "macOS"
+	layout         = MacOSLayout
+	wholeTempDirs  = true
+	macOS          = true
+	systemCacheDir = "/Library/Caches"
+	systemLogDir   = "/private/var/log"
+)
+
+var tempDirs = []string{"/private/var/tmp", "/private/tmp"}
+
+var systemLogPatterns = []string{"*.log"}
+
+func cacheDir(home string) string {
+	return filepath.Join(home, "Library", "Caches")
+}
+
+func dataDir(home string) string {
+	return filepath.Join(home, "Library", "Application Support")
+}
+
+func configDir(home string) string {
+	return filepath.Join(home, "Library", "Application Support")
+}
+
+func trashDir(home string) string {
+	return filepath.Join(home, ".Trash")
+}
+
+func volumeTrashPatterns() []string {
+	return []string{"/Volumes/*/.Trashes"}
+}
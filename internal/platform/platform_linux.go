@@ -0,0 +1,50 @@
+//go:build linux
+
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	name           = "Linux"
+	layout         = LinuxLayout
+	wholeTempDirs  = false
+	macOS          = false
+	systemCacheDir = ""
+	systemLogDir   = "/var/log"
+)
+
+var tempDirs = []string{"/var/tmp", "/tmp"}
+
+// The live logs are held open by rsyslog and dpkg: deleting them frees
+// nothing and loses what is written next, so only rotated logs are offered
+var systemLogPatterns = []string{"*.log.[0-9]*", "*.[0-9]", "*.gz"}
+
+func cacheDir(home string) string {
+	return xdgDir("XDG_CACHE_HOME", home, ".cache")
+}
+
+func dataDir(home string) string {
+	return xdgDir("XDG_DATA_HOME", home, ".local", "share")
+}
+
+func configDir(home string) string {
+	return xdgDir("XDG_CONFIG_HOME", home, ".config")
+}
+
+func trashDir(home string) string {
+	return filepath.Join(dataDir(home), "Trash")
+}
+
+// volumeTrashPatterns matches the .Trash-<uid> folders desktop environments
+// create at the root of the volumes they mount below /media or /run/media
+func volumeTrashPatterns() []string {
+	trash := ".Trash-" + strconv.Itoa(os.Getuid())
+	return []string{
+		filepath.Join("/media", "*", "*", trash),
+		filepath.Join("/run", "media", "*", "*", trash),
+	}
+}
@@ -0,0 +1,30 @@
+//go:build linux
+
+package platform
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLinuxDirs(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "/data")
+
+	if got := CacheDir("/home/me"); got != filepath.Join("/home/me", ".cache") {
+		t.Errorf("CacheDir() = %s", got)
+	}
+	if got := TrashDir("/home/me"); got != "/data/Trash" {
+		t.Errorf("TrashDir() = %s", got)
+	}
+	if IsMacOS() || SystemCacheDir() != "" {
+		t.Error("Expected the macOS cleaners and the system caches to be off on Linux")
+	}
+	for _, name := range []string{"auth.log", "kern.log", "dpkg.log"} {
+		for _, pattern := range SystemLogPatterns() {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				t.Errorf("Expected the live log %s to be kept, matched by %s", name, pattern)
+			}
+		}
+	}
+}
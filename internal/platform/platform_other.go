@@ -0,0 +1,46 @@
+//go:build !darwin && !linux && !windows
+
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Other systems get the Linux layout, which the BSDs follow too
+const (
+	layout         = LinuxLayout
+	wholeTempDirs  = false
+	macOS          = false
+	systemCacheDir = ""
+	systemLogDir   = "/var/log"
+)
+
+var (
+	name     = runtime.GOOS
+	tempDirs = []string{os.TempDir()}
+
+	// Rotated logs only, as on Linux
+	systemLogPatterns = []string{"*.log.[0-9]*", "*.[0-9]", "*.gz"}
+)
+
+func cacheDir(home string) string {
+	return xdgDir("XDG_CACHE_HOME", home, ".cache")
+}
+
+func dataDir(home string) string {
+	return xdgDir("XDG_DATA_HOME", home, ".local", "share")
+}
+
+func configDir(home string) string {
+	return xdgDir("XDG_CONFIG_HOME", home, ".config")
+}
+
+func trashDir(home string) string {
+	return filepath.Join(dataDir(home), "Trash")
+}
+
+func volumeTrashPatterns() []string {
+	return nil
+}
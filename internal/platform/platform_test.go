@@ -0,0 +1,116 @@
+package platform
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestXdgDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/data/cache")
+	if got := xdgDir("XDG_CACHE_HOME", "/home/me", ".cache"); got != "/data/cache" {
+		t.Errorf("Expected the variable's folder, got %s", got)
+	}
+
+	// Relative paths are invalid and ignored
+	t.Setenv("XDG_CACHE_HOME", "cache")
+	if got := xdgDir("XDG_CACHE_HOME", "/home/me", ".cache"); got != filepath.Join("/home/me", ".cache") {
+		t.Errorf("Expected the default folder, got %s", got)
+	}
+
+	t.Setenv("XDG_DATA_HOME", "")
+	if got := xdgDir("XDG_DATA_HOME", "/home/me", ".local", "share"); got != filepath.Join("/home/me", ".local", "share") {
+		t.Errorf("Expected the default folder, got %s", got)
+	}
+}
+
+func TestDirs_BelowHome(t *testing.T) {
+	for _, name := range []string{"XDG_CACHE_HOME", "XDG_DATA_HOME", "XDG_CONFIG_HOME"} {
+		t.Setenv(name, "")
+	}
+
+	home := t.TempDir()
+	for _, dir := range []string{CacheDir(home), DataDir(home), ConfigDir(home), TrashDir(home)} {
+		if dir == "" {
+			// No cache folder nor trash on Windows
+			continue
+		}
+		if rel, err := filepath.Rel(home, dir); err != nil || rel == "." || filepath.IsAbs(rel) || strings.HasPrefix(rel, "..") {
+			t.Errorf("Expected %s to be below %s", dir, home)
+		}
+	}
+}
+
+func TestToolCacheDir(t *testing.T) {
+	home := filepath.Join("/", "home", "me")
+	tests := []struct {
+		layout   Layout
+		expected string
+	}{
+		{MacOSLayout, filepath.Join(home, "Library", "Caches")},
+		{LinuxLayout, filepath.Join(home, ".cache")},
+		{WindowsLayout, filepath.Join(home, "AppData", "Local")},
+	}
+
+	for _, tt := range tests {
+		if tt.layout == Current() {
+			// Follows the variables moving it instead
+			continue
+		}
+		if got := tt.layout.ToolCacheDir(home); got != tt.expected {
+			t.Errorf("ToolCacheDir(%d) = %s, want %s", tt.layout, got, tt.expected)
+		}
+	}
+}
+
+func TestWindowsToWSLPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{`C:\Users\me` + "\r\n", "/mnt/c/Users/me"},
+		{`D:\`, "/mnt/d"},
+		{"%USERPROFILE%", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := windowsToWSLPath(tt.path); got != tt.expected {
+			t.Errorf("windowsToWSLPath(%q) = %q, want %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestWSLDistro(t *testing.T) {
+	home := wslSharePath("Ubuntu-22.04", "/home/me")
+	if home != `\\wsl.localhost\Ubuntu-22.04\home\me` {
+		t.Errorf("wslSharePath() = %q", home)
+	}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{home + `\.cache\go-build`, "Ubuntu-22.04"},
+		{`\\wsl$\Debian\home\me`, "Debian"},
+		{`C:\Users\me\AppData\Local\npm-cache`, ""},
+		{"/mnt/c/Users/me", ""},
+	}
+	for _, tt := range tests {
+		if got := WSLDistro(tt.path); got != tt.expected {
+			t.Errorf("WSLDistro(%q) = %q, want %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestParseWSLList(t *testing.T) {
+	var output []byte
+	for _, r := range "\ufeffUbuntu-22.04\r\ndocker-desktop\r\n\r\n" {
+		output = append(output, byte(r), byte(r>>8))
+	}
+
+	names := parseWSLList(output)
+	if len(names) != 2 || names[0] != "Ubuntu-22.04" || names[1] != "docker-desktop" {
+		t.Errorf("Expected both distributions, got %q", names)
+	}
+}
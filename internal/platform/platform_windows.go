@@ -0,0 +1,38 @@
+//go:build windows
+
+package platform
+
+const (
+	name           = "Windows"
+	layout         = WindowsLayout
+	wholeTempDirs  = false
+	macOS          = false
+	systemCacheDir = ""
+	systemLogDir   = ""
+)
+
+// The user's Temp folder is application data on Windows, nothing to empty
+// as a whole
+var tempDirs []string
+
+var systemLogPatterns []string
+
+func cacheDir(home string) string {
+	return ""
+}
+
+func dataDir(home string) string {
+	return envDir("LOCALAPPDATA", home, "AppData", "Local")
+}
+
+func configDir(home string) string {
+	return envDir("APPDATA", home, "AppData", "Roaming")
+}
+
+func trashDir(home string) string {
+	return ""
+}
+
+func volumeTrashPatterns() []string {
+	return nil
+}
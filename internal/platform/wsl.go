@@ -0,0 +1,100 @@
+package platform
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// WSLHome is a home directory on the other side of WSL: the Windows user
+// profile seen from a Linux distribution, or a distribution's home seen
+// from Windows
+type WSLHome struct {
+	Path   string
+	Layout Layout
+	Side   string // "Windows", or the distribution's name
+}
+
+// WSL reports whether this machine has another side of WSL to scan: it
+// runs in a WSL distribution, or on Windows with WSL installed
+func WSL() bool {
+	return wsl()
+}
+
+// WSLHomes returns the home directories on the other side of WSL, nil if
+// there is none. It runs wsl.exe or cmd.exe to find them.
+func WSLHomes() []WSLHome {
+	return wslHomes()
+}
+
+// wslTimeout bounds the commands run to find the other side's homes, which
+// may have to start a distribution
+const wslTimeout = 15 * time.Second
+
+// wslOutput runs a command to find the other side's homes
+func wslOutput(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), wslTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
+// windowsToWSLPath returns where a Windows path such as C:\Users\me is
+// mounted in a WSL distribution (/mnt/c/Users/me), or "" if it has no
+// drive letter
+func windowsToWSLPath(path string) string {
+	path = strings.TrimSpace(path)
+	if len(path) < 2 || path[1] != ':' {
+		return ""
+	}
+	drive := strings.ToLower(path[:1])
+	if drive < "a" || drive > "z" {
+		return ""
+	}
+	rest := strings.Trim(strings.ReplaceAll(path[2:], `\`, "/"), "/")
+	if rest == "" {
+		return "/mnt/" + drive
+	}
+	return "/mnt/" + drive + "/" + rest
+}
+
+// wslShares are the network shares Windows mounts the WSL distributions
+// on, \\wsl$ being the name of older releases
+var wslShares = []string{`\\wsl.localhost\`, `\\wsl$\`}
+
+// wslSharePath returns where Windows mounts a path of a WSL distribution,
+// e.g. \\wsl.localhost\Ubuntu\home\me for /home/me
+func wslSharePath(distro, path string) string {
+	return wslShares[0] + distro + strings.ReplaceAll(path, "/", `\`)
+}
+
+// WSLDistro returns the WSL distribution a Windows path such as
+// \\wsl.localhost\Ubuntu\home\me lies in, or "" if it is on the Windows side
+func WSLDistro(path string) string {
+	for _, share := range wslShares {
+		if len(path) > len(share) && strings.EqualFold(path[:len(share)], share) {
+			distro, _, _ := strings.Cut(path[len(share):], `\`)
+			return distro
+		}
+	}
+	return ""
+}
+
+// parseWSLList returns the distribution names wsl.exe --list --quiet
+// prints, in UTF-16 (little endian) as Windows consoles do
+func parseWSLList(output []byte) []string {
+	units := make([]uint16, 0, len(output)/2)
+	for i := 0; i+1 < len(output); i += 2 {
+		units = append(units, uint16(output[i])|uint16(output[i+1])<<8)
+	}
+	text := strings.TrimPrefix(string(utf16.Decode(units)), "\ufeff")
+
+	var names []string
+	for _, line := range strings.Split(text, "\n") {
+		if name := strings.TrimSpace(strings.ReplaceAll(line, "\x00", "")); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,39 @@
+//go:build linux
+
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// wsl tells a WSL distribution by the variable WSL sets in its shells or,
+// for processes started otherwise, the interop it registers
+func wsl() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	_, err := os.Stat("/proc/sys/fs/binfmt_misc/WSLInterop")
+	return err == nil
+}
+
+// wslHomes returns the Windows user profile, asked to cmd.exe and found
+// below its drive's mount
+func wslHomes() []WSLHome {
+	if !wsl() {
+		return nil
+	}
+	output, err := wslOutput("cmd.exe", "/c", "echo %USERPROFILE%")
+	if err != nil {
+		return nil
+	}
+	path := windowsToWSLPath(string(output))
+	if path == "" || strings.Contains(path, "%") {
+		return nil
+	}
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return nil
+	}
+	return []WSLHome{{Path: filepath.Clean(path), Layout: WindowsLayout, Side: "Windows"}}
+}
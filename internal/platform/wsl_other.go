@@ -0,0 +1,11 @@
+//go:build !linux && !windows
+
+package platform
+
+func wsl() bool {
+	return false
+}
+
+func wslHomes() []WSLHome {
+	return nil
+}
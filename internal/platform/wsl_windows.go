@@ -0,0 +1,43 @@
+//go:build windows
+
+package platform
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// wsl tells Windows with WSL installed by its command
+func wsl() bool {
+	_, err := exec.LookPath("wsl.exe")
+	return err == nil
+}
+
+// wslHomes returns the home of the default user of each WSL distribution,
+// below the \\wsl.localhost share Windows mounts them on. The other users'
+// homes are theirs to clean.
+func wslHomes() []WSLHome {
+	if !wsl() {
+		return nil
+	}
+	output, err := wslOutput("wsl.exe", "--list", "--quiet")
+	if err != nil {
+		return nil
+	}
+
+	var homes []WSLHome
+	for _, distro := range parseWSLList(output) {
+		home, err := wslOutput("wsl.exe", "-d", distro, "-e", "sh", "-c", "echo $HOME")
+		path := strings.TrimSpace(string(home))
+		if err != nil || !strings.HasPrefix(path, "/") || path == "/" {
+			continue
+		}
+		path = wslSharePath(distro, path)
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			continue
+		}
+		homes = append(homes, WSLHome{Path: path, Layout: LinuxLayout, Side: "WSL " + distro})
+	}
+	return homes
+}
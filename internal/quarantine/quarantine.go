@@ -0,0 +1,422 @@
+// Package quarantine keeps the targets of a clean run aside instead of
+// deleting them, so they can be restored until the quarantine is purged.
+package quarantine
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// DirName is the name of the quarantine inside the state directory
+const DirName = "quarantine"
+
+// ManifestFileName is the name of the manifest inside the quarantine
+const ManifestFileName = "manifest.jsonl"
+
+// The checksum of a quarantined tree covers the path, type and size of every
+// entry, and the first sampleBytes of up to sampleFiles files spread across
+// it: enough to tell the tree changed without reading gigabytes.
+const (
+	sampleFiles = 64
+	sampleBytes = 64 * 1024
+)
+
+// ErrCollision is returned when restoring an entry whose original path is
+// taken again
+var ErrCollision = errors.New("original path exists")
+
+// ErrModified is returned when restoring an entry whose quarantined copy no
+// longer matches its checksum
+var ErrModified = errors.New("quarantined copy was modified")
+
+// Entry is a file or folder kept in the quarantine
+type Entry struct {
+	ID            string    `json:"id"` // Folder of the entry in the quarantine
+	OriginalPath  string    `json:"original_path"`
+	SizeBytes     int64     `json:"size_bytes"`
+	Files         int       `json:"files"`
+	Checksum      string    `json:"checksum"` // See Checksum
+	QuarantinedAt time.Time `json:"quarantined_at"`
+}
+
+// Store is a quarantine folder and its manifest. Its methods may be called
+// from several goroutines.
+type Store struct {
+	Dir string
+
+	mu sync.Mutex
+}
+
+// DefaultDir returns the quarantine in the state directory
+func DefaultDir() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, DirName), nil
+}
+
+// New returns the store of the quarantine at dir
+func New(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// Path returns where the entry is kept in the quarantine
+func (s *Store) Path(e Entry) string {
+	return filepath.Join(s.Dir, e.ID, filepath.Base(e.OriginalPath))
+}
+
+// Move moves path into the quarantine and records it in the manifest. The
+// quarantine must be on the same volume as path: nothing is copied.
+func (s *Store) Move(path string) (Entry, error) {
+	if _, err := os.Lstat(path); err != nil {
+		return Entry{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return Entry{}, err
+	}
+	entry := Entry{OriginalPath: path, QuarantinedAt: time.Now().UTC()}
+	entry.ID = s.newID(entry.QuarantinedAt)
+	if err := os.Mkdir(filepath.Join(s.Dir, entry.ID), 0700); err != nil {
+		return Entry{}, err
+	}
+
+	dest := s.Path(entry)
+	if err := os.Rename(path, dest); err != nil {
+		os.Remove(filepath.Join(s.Dir, entry.ID))
+		if errors.Is(err, syscall.EXDEV) {
+			return Entry{}, fmt.Errorf("cannot quarantine %s: it is on another volume than %s", path, s.Dir)
+		}
+		return Entry{}, err
+	}
+
+	// Measured in the quarantine: the copy is what gets restored. What
+	// can't be recorded is put back.
+	var err error
+	entry.Checksum, entry.SizeBytes, entry.Files, err = Checksum(dest)
+	if err == nil {
+		err = s.append(entry)
+	}
+	if err != nil {
+		if os.Rename(dest, path) == nil {
+			os.Remove(filepath.Join(s.Dir, entry.ID))
+		}
+		return Entry{}, fmt.Errorf("cannot quarantine %s: %w", path, err)
+	}
+	return entry, nil
+}
+
+// newID returns a name for a new entry folder, based on its time
+func (s *Store) newID(at time.Time) string {
+	base := at.Format("20060102-150405")
+	id := base
+	for n := 2; utils.PathExists(filepath.Join(s.Dir, id)); n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	return id
+}
+
+// Entries returns the entries of the manifest, oldest first. A missing
+// manifest is an empty quarantine; malformed lines are skipped.
+func (s *Store) Entries() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Restore moves an entry back to its original path, once its copy is
+// checked against its checksum. Entries whose path is taken again, or whose
+// copy changed, are left in the quarantine (ErrCollision, ErrModified).
+func (s *Store) Restore(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	src := s.Path(e)
+	checksum, _, _, err := Checksum(src)
+	if err != nil {
+		return err
+	}
+	if checksum != e.Checksum {
+		return fmt.Errorf("cannot restore %s: %w", e.OriginalPath, ErrModified)
+	}
+	if _, err := os.Lstat(e.OriginalPath); err == nil {
+		return fmt.Errorf("cannot restore %s: %w", e.OriginalPath, ErrCollision)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(e.OriginalPath), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(src, e.OriginalPath); err != nil {
+		return err
+	}
+	os.Remove(filepath.Join(s.Dir, e.ID))
+	return s.remove(map[string]bool{e.ID: true})
+}
+
+// PurgeReport is the outcome of Purge
+type PurgeReport struct {
+	Entries int   // Entries deleted
+	Bytes   int64 // Their size
+	Errors  []error
+}
+
+// Purge deletes the entries quarantined before cutoff, and only them: the
+// manifest says what the quarantine holds. Entries that can't be fully
+// deleted stay in the manifest.
+func (s *Store) Purge(cutoff time.Time) (PurgeReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return PurgeReport{}, err
+	}
+
+	var expired []Entry
+	for _, e := range entries {
+		if e.QuarantinedAt.Before(cutoff) {
+			expired = append(expired, e)
+		}
+	}
+	return s.purge(expired)
+}
+
+// Limits bound what the quarantine holds. Zero values mean no limit.
+type Limits struct {
+	MaxAge   time.Duration // Entries older than this are purged
+	MaxBytes int64         // The oldest entries are purged until the rest fits
+}
+
+// Expire purges the entries older than limits.MaxAge, then the oldest ones
+// until the quarantine holds no more than limits.MaxBytes
+func (s *Store) Expire(limits Limits) (PurgeReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return PurgeReport{}, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].QuarantinedAt.Before(entries[j].QuarantinedAt)
+	})
+
+	var total int64
+	for _, e := range entries {
+		total += e.SizeBytes
+	}
+	cutoff := time.Now().Add(-limits.MaxAge)
+
+	var expired []Entry
+	for _, e := range entries {
+		tooOld := limits.MaxAge > 0 && e.QuarantinedAt.Before(cutoff)
+		overCap := limits.MaxBytes > 0 && total > limits.MaxBytes
+		if !tooOld && !overCap {
+			break
+		}
+		expired = append(expired, e)
+		total -= e.SizeBytes
+	}
+	return s.purge(expired)
+}
+
+// Status is what the quarantine holds
+type Status struct {
+	Entries int
+	Bytes   int64
+	Oldest  time.Time // Zero when empty
+}
+
+// Status returns what the quarantine holds, from its manifest
+func (s *Store) Status() (Status, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return Status{}, err
+	}
+
+	var status Status
+	for _, e := range entries {
+		status.Entries++
+		status.Bytes += e.SizeBytes
+		if status.Oldest.IsZero() || e.QuarantinedAt.Before(status.Oldest) {
+			status.Oldest = e.QuarantinedAt
+		}
+	}
+	return status, nil
+}
+
+// purge deletes entries and removes them from the manifest. The caller
+// holds the lock.
+func (s *Store) purge(entries []Entry) (PurgeReport, error) {
+	var report PurgeReport
+	purged := make(map[string]bool)
+	for _, e := range entries {
+		if e.ID == "" {
+			continue
+		}
+		removed := utils.RemoveTree(filepath.Join(s.Dir, e.ID))
+		if err := removed.Err(); err != nil {
+			report.Errors = append(report.Errors, err)
+			continue
+		}
+		purged[e.ID] = true
+		report.Entries++
+		report.Bytes += e.SizeBytes
+	}
+
+	return report, s.remove(purged)
+}
+
+// manifestPath returns the manifest of the store
+func (s *Store) manifestPath() string {
+	return filepath.Join(s.Dir, ManifestFileName)
+}
+
+// load reads the manifest. The caller holds the lock.
+func (s *Store) load() ([]Entry, error) {
+	file, err := os.Open(s.manifestPath())
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := []Entry{}
+	sc := bufio.NewScanner(file)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, sc.Err()
+}
+
+// append adds an entry to the manifest. The caller holds the lock.
+func (s *Store) append(e Entry) error {
+	file, err := os.OpenFile(s.manifestPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// remove rewrites the manifest without the entries of ids. The file is
+// replaced atomically. The caller holds the lock.
+func (s *Store) remove(ids map[string]bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	for _, e := range entries {
+		if ids[e.ID] {
+			continue
+		}
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	tmp := s.manifestPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.manifestPath())
+}
+
+// Checksum returns a sampling checksum of a file or folder, with its size
+// and number of files. Any entry added, removed, renamed or resized changes
+// it, and so does a change in the beginning of the sampled files.
+func Checksum(path string) (string, int64, int, error) {
+	type file struct {
+		rel  string
+		size int64
+	}
+
+	h := sha256.New()
+	var size int64
+	files := []file{}
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(path, p)
+		fmt.Fprintf(h, "%s\x00%s\x00%d\n", filepath.ToSlash(rel), info.Mode().Type(), info.Size())
+
+		if info.Mode().IsRegular() {
+			size += info.Size()
+			files = append(files, file{rel: rel, size: info.Size()})
+		} else if info.Mode()&fs.ModeSymlink != 0 {
+			target, _ := os.Readlink(p)
+			fmt.Fprintf(h, "-> %s\n", target)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	// WalkDir is in lexical order already; sorting keeps the sample stable
+	// if that ever changes
+	sort.Slice(files, func(i, j int) bool { return files[i].rel < files[j].rel })
+	step := max(1, (len(files)+sampleFiles-1)/sampleFiles)
+	for i := 0; i < len(files); i += step {
+		if err := sampleFile(h, filepath.Join(path, files[i].rel)); err != nil {
+			return "", 0, 0, err
+		}
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), size, len(files), nil
+}
+
+// sampleFile adds the beginning of a file to a checksum
+func sampleFile(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, io.LimitReader(file, sampleBytes))
+	return err
+}
@@ -0,0 +1,221 @@
+package quarantine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setupTree creates a folder with a few files to quarantine
+func setupTree(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "project", "node_modules")
+	for name, content := range map[string]string{
+		"a/index.js":   "module.exports = 1",
+		"b/index.js":   "module.exports = 2",
+		"package.json": "{}",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestMoveRestore(t *testing.T) {
+	path := setupTree(t)
+	store := New(filepath.Join(t.TempDir(), DirName))
+
+	entry, err := store.Move(path)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Move should take the path away")
+	}
+	if entry.OriginalPath != path || entry.Files != 3 || entry.SizeBytes == 0 || entry.Checksum == "" {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+
+	entries, err := store.Entries()
+	if err != nil || len(entries) != 1 || entries[0].ID != entry.ID {
+		t.Fatalf("Expected the entry in the manifest, got %+v (%v)", entries, err)
+	}
+
+	if err := store.Restore(entries[0]); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, "a", "index.js")); err != nil {
+		t.Errorf("Restore should bring the files back: %v", err)
+	}
+	if entries, _ := store.Entries(); len(entries) != 0 {
+		t.Errorf("Restore should remove the entry from the manifest, got %+v", entries)
+	}
+	if _, err := os.Stat(filepath.Join(store.Dir, entry.ID)); !os.IsNotExist(err) {
+		t.Error("Restore should remove the folder of the entry")
+	}
+}
+
+func TestRestore_Modified(t *testing.T) {
+	path := setupTree(t)
+	store := New(filepath.Join(t.TempDir(), DirName))
+
+	entry, err := store.Move(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(store.Path(entry), "a", "index.js"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Restore(entry); !errors.Is(err, ErrModified) {
+		t.Errorf("Expected ErrModified, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("A modified copy should stay in the quarantine")
+	}
+}
+
+func TestRestore_Collision(t *testing.T) {
+	path := setupTree(t)
+	store := New(filepath.Join(t.TempDir(), DirName))
+
+	entry, err := store.Move(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Restore(entry); !errors.Is(err, ErrCollision) {
+		t.Errorf("Expected ErrCollision, got %v", err)
+	}
+	if entries, _ := store.Entries(); len(entries) != 1 {
+		t.Error("An entry that collides should stay in the manifest")
+	}
+}
+
+func TestPurge_OlderThan(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), DirName))
+
+	old, err := store.Move(setupTree(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recent, err := store.Move(setupTree(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Age the first entry in the manifest
+	old = ageEntry(t, store, old, 10*24*time.Hour)
+	// A folder the manifest doesn't know about is never purged
+	stray := filepath.Join(store.Dir, "stray")
+	if err := os.Mkdir(stray, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := store.Purge(time.Now().Add(-7 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if report.Entries != 1 || report.Bytes != old.SizeBytes {
+		t.Errorf("Expected the old entry purged, got %+v", report)
+	}
+	if _, err := os.Stat(filepath.Join(store.Dir, old.ID)); !os.IsNotExist(err) {
+		t.Error("The old entry should be deleted")
+	}
+	if _, err := os.Stat(store.Path(recent)); err != nil {
+		t.Error("The recent entry should be kept")
+	}
+	if _, err := os.Stat(stray); err != nil {
+		t.Error("Folders outside the manifest should be kept")
+	}
+
+	entries, _ := store.Entries()
+	if len(entries) != 1 || entries[0].ID != recent.ID {
+		t.Errorf("Expected only the recent entry left, got %+v", entries)
+	}
+}
+
+// ageEntry rewrites an entry of the manifest as quarantined age ago
+func ageEntry(t *testing.T, store *Store, e Entry, age time.Duration) Entry {
+	t.Helper()
+	e.QuarantinedAt = time.Now().Add(-age)
+	if err := store.remove(map[string]bool{e.ID: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.append(e); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestExpire(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), DirName))
+
+	var entries []Entry
+	for i := range 3 {
+		e, err := store.Move(setupTree(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, ageEntry(t, store, e, time.Duration(30-10*i)*24*time.Hour))
+	}
+	size := entries[0].SizeBytes
+
+	// Nothing is over the limits
+	report, err := store.Expire(Limits{MaxAge: 40 * 24 * time.Hour, MaxBytes: 3 * size})
+	if err != nil || report.Entries != 0 {
+		t.Fatalf("Expected nothing purged, got %+v (%v)", report, err)
+	}
+
+	// The 30 days old entry expires, then the 20 days old one to fit the cap
+	report, err = store.Expire(Limits{MaxAge: 25 * 24 * time.Hour, MaxBytes: size})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Entries != 2 || report.Bytes != 2*size {
+		t.Errorf("Expected the two oldest entries purged, got %+v", report)
+	}
+
+	status, err := store.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Entries != 1 || status.Bytes != size || !status.Oldest.Equal(entries[2].QuarantinedAt) {
+		t.Errorf("Expected the newest entry left, got %+v", status)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	path := setupTree(t)
+
+	first, size, files, err := Checksum(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len("module.exports = 1")*2+len("{}")) || files != 3 {
+		t.Errorf("Unexpected size %d and files %d", size, files)
+	}
+
+	again, _, _, _ := Checksum(path)
+	if again != first {
+		t.Error("Checksum should be stable")
+	}
+
+	// Same size, different content
+	if err := os.WriteFile(filepath.Join(path, "package.json"), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _, _, _ := Checksum(path); changed == first {
+		t.Error("Checksum should change with the content of sampled files")
+	}
+}
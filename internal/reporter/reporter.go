@@ -2,26 +2,39 @@ package reporter
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 
 	"github.com/0SansNom/epurer/internal/cleaner"
 	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/diagnostics"
+	"github.com/0SansNom/epurer/internal/disk"
+	"github.com/0SansNom/epurer/internal/history"
+	"github.com/0SansNom/epurer/internal/i18n"
+	"github.com/0SansNom/epurer/internal/quarantine"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/internal/theme"
 	"github.com/0SansNom/epurer/pkg/utils"
 )
 
 // Styles using Lip Gloss
 var (
 	// Colors
-	primaryColor   = lipgloss.Color("#7C3AED") // Purple
-	secondaryColor = lipgloss.Color("#06B6D4") // Cyan
-	successColor   = lipgloss.Color("#10B981") // Green
-	warningColor   = lipgloss.Color("#F59E0B") // Amber
-	dangerColor    = lipgloss.Color("#EF4444") // Red
-	mutedColor     = lipgloss.Color("#6B7280") // Gray
+	primaryColor   = theme.Primary
+	secondaryColor = theme.Secondary
+	successColor   = theme.Success
+	warningColor   = theme.Warning
+	dangerColor    = theme.Danger
+	mutedColor     = theme.Muted
 
 	// Text styles
 	titleStyle = lipgloss.NewStyle().
@@ -56,7 +69,7 @@ var (
 	// Table styles
 	tableHeaderStyle = lipgloss.NewStyle().
 				Bold(true).
-				Foreground(lipgloss.Color("#FFFFFF")).
+				Foreground(theme.White).
 				Background(primaryColor).
 				Padding(0, 1)
 
@@ -72,41 +85,93 @@ var (
 // Reporter handles all output formatting and display
 type Reporter struct {
 	verbose  bool
+	out      io.Writer          // Output, with the theme's symbols
+	raw      io.Writer          // Output as set by SetOutput
+	renderer *lipgloss.Renderer // Styles output for out, plain text when it isn't a terminal
+	theme    theme.Theme
+	lang     i18n.Lang
 	progress progress.Model
+	partial  map[string]bool // Cleaners whose scan ran out of time
+
+	progressWidth int // Width of the progress line being updated
 }
 
-// NewReporter creates a new Reporter
+// NewReporter creates a new Reporter writing to stdout
 func NewReporter(verbose bool) *Reporter {
-	p := progress.New(
+	r := &Reporter{
+		verbose: verbose,
+		partial: make(map[string]bool),
+	}
+	r.SetOutput(os.Stdout)
+	return r
+}
+
+// SetOutput makes the reporter write to w. Styling is kept only when w is a
+// terminal and NO_COLOR is not set, so logs and CI output stay plain text.
+func (r *Reporter) SetOutput(w io.Writer) {
+	r.raw = w
+	r.out = r.theme.Writer(w)
+	r.renderer = lipgloss.NewRenderer(w)
+	if r.theme.IsMonochrome() {
+		r.renderer.SetColorProfile(termenv.Ascii)
+	}
+	r.progress = progress.New(
 		progress.WithDefaultGradient(),
 		progress.WithWidth(40),
+		progress.WithColorProfile(r.renderer.ColorProfile()),
 	)
-	return &Reporter{
-		verbose:  verbose,
-		progress: p,
-	}
+}
+
+// SetTheme sets the colors and symbols of the output (the default theme
+// otherwise)
+func (r *Reporter) SetTheme(t theme.Theme) {
+	r.theme = t
+	r.SetOutput(r.raw)
+}
+
+// SetLang sets the language of headers, prompts and summaries (English by
+// default)
+func (r *Reporter) SetLang(lang i18n.Lang) {
+	r.lang = lang
+}
+
+// msg returns a message in the reporter's language
+func (r *Reporter) msg(key string, args ...any) string {
+	return r.lang.T(key, args...)
+}
+
+// style binds a style to the reporter's output
+func (r *Reporter) style(s lipgloss.Style) lipgloss.Style {
+	return r.theme.Style(s).Renderer(r.renderer)
+}
+
+// MarkPartial records that a cleaner's scan timed out, so its results are
+// flagged as incomplete in the estimation
+func (r *Reporter) MarkPartial(name string) {
+	r.partial[name] = true
 }
 
 // PrintHeader prints the application header
 func (r *Reporter) PrintHeader() {
-	title := "🧹 Épurer v1.1"
-	subtitle := "Intelligent cache cleanup for macOS"
+	// Symbols are replaced before boxing, which keeps the box aligned
+	title := r.theme.Text("🧹 Épurer v1.1")
+	subtitle := r.theme.Text(r.msg("header.subtitle"))
 
 	content := lipgloss.JoinVertical(
 		lipgloss.Center,
-		titleStyle.Render(title),
-		subtitleStyle.Render(subtitle),
+		r.style(titleStyle).Render(title),
+		r.style(subtitleStyle).Render(subtitle),
 	)
 
-	box := headerBox.Render(content)
-	fmt.Println()
-	fmt.Println(box)
-	fmt.Println()
+	box := r.style(headerBox).Render(content)
+	fmt.Fprintln(r.out)
+	fmt.Fprintln(r.out, box)
+	fmt.Fprintln(r.out)
 }
 
 // PrintDetection prints the detection results
 func (r *Reporter) PrintDetection(detected map[string]bool) {
-	fmt.Println(warningStyle.Render("\n🔍 Detecting development tools...\n"))
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n🔍 "+r.msg("detect.title")+"\n"))
 
 	detectionMap := map[string]string{
 		"frontend": "Frontend (Node.js, npm, yarn)",
@@ -119,36 +184,93 @@ func (r *Reporter) PrintDetection(detected map[string]bool) {
 
 	for domain, description := range detectionMap {
 		if detected[domain] {
-			fmt.Printf("  %s %s\n", successStyle.Render("✓"), description)
+			fmt.Fprintf(r.out, "  %s %s\n", r.style(successStyle).Render("✓"), description)
 		} else {
 			if r.verbose {
-				fmt.Printf("  %s %s\n", errorStyle.Render("✗"), mutedStyle.Render(description))
+				fmt.Fprintf(r.out, "  %s %s\n", r.style(errorStyle).Render("✗"), r.style(mutedStyle).Render(description))
 			}
 		}
 	}
 
-	fmt.Println()
+	fmt.Fprintln(r.out)
+}
+
+// PrintTools prints the detected tools by category, with their version,
+// path, the space their caches and data take, and how much of it cleaning
+// their caches would free now
+func (r *Reporter) PrintTools(result detector.DetectionResult) {
+	headerStyle := r.style(lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Padding(0, 1))
+	cellStyle := r.renderer.NewStyle().Padding(0, 1)
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	bytes := func(size int64) string {
+		if size == 0 {
+			return "-"
+		}
+		return utils.FormatBytes(size)
+	}
+
+	var reclaimable int64
+	found := false
+	for _, category := range result.Categories() {
+		if len(category.Tools) == 0 {
+			continue
+		}
+		found = true
+
+		fmt.Fprintln(r.out, r.style(titleStyle).Render(category.Label))
+		fmt.Fprintf(r.out, "%s%s%s%s%s\n",
+			headerStyle.Width(14).Render(r.msg("detect.tool")),
+			headerStyle.Width(12).Render(r.msg("detect.version")),
+			headerStyle.Width(12).Align(lipgloss.Right).Render(r.msg("detect.footprint")),
+			headerStyle.Width(18).Align(lipgloss.Right).Render(r.msg("detect.reclaimable")),
+			headerStyle.Render(r.msg("detect.path")),
+		)
+		for _, tool := range category.Tools {
+			reclaimable += tool.Reclaimable
+			fmt.Fprintf(r.out, "%s%s%s%s%s\n",
+				cellStyle.Width(14).Render(tool.Name),
+				cellStyle.Width(12).Render(orDash(tool.Version)),
+				cellStyle.Width(12).Align(lipgloss.Right).Render(bytes(tool.Footprint)),
+				cellStyle.Width(18).Align(lipgloss.Right).Render(r.style(successStyle).Render(bytes(tool.Reclaimable))),
+				r.style(mutedStyle).Padding(0, 1).Render(orDash(tool.Path)),
+			)
+		}
+		fmt.Fprintln(r.out)
+	}
+
+	if !found {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render(r.msg("detect.none")))
+		fmt.Fprintln(r.out)
+		return
+	}
+	fmt.Fprintln(r.out, r.msg("detect.total", r.style(successStyle).Render(utils.FormatBytes(reclaimable))))
+	fmt.Fprintln(r.out)
 }
 
 // PrintEstimation prints a table of estimated cleanup sizes
 func (r *Reporter) PrintEstimation(targetsByDomain map[string][]cleaner.CleanTarget) {
-	fmt.Println(warningStyle.Render("📊 Cleanup Estimation:\n"))
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("📊 "+r.msg("estimation.title")+"\n"))
 
 	totalSize := int64(0)
 	totalItems := 0
 
 	// Collect data first
 	type rowData struct {
-		domain  string
-		items   string
-		size    string
-		safety  string
-		impact  string
+		domain string
+		items  string
+		size   string
+		safety string
+		impact string
 	}
 	var rows []rowData
 
 	// Sort domains for consistent output
-	domains := []string{"Frontend", "Backend", "Mobile", "DevOps", "Data/ML", "System"}
+	domains := sortedDomains(targetsByDomain)
 
 	for _, domain := range domains {
 		targets, exists := targetsByDomain[domain]
@@ -167,68 +289,81 @@ func (r *Reporter) PrintEstimation(targetsByDomain map[string][]cleaner.CleanTar
 		// Build safety string (simple text, no emoji for alignment)
 		safetyStr := ""
 		if safetyIcons[config.Safe] {
-			safetyStr += "Safe "
+			safetyStr += r.msg("safety.safe") + " "
 		}
 		if safetyIcons[config.Moderate] {
-			safetyStr += "Mod "
+			safetyStr += r.msg("safety.moderate") + " "
 		}
 		if safetyIcons[config.Dangerous] {
-			safetyStr += "Risk "
+			safetyStr += r.msg("safety.dangerous") + " "
 		}
 
 		impact := getImpactString(domainSize)
 
+		if r.partial[domain] {
+			domain += "*"
+		}
+
 		rows = append(rows, rowData{
-			domain:  domain,
-			items:   utils.FormatCount(len(targets)),
-			size:    utils.FormatBytes(domainSize),
-			safety:  strings.TrimSpace(safetyStr),
-			impact:  impact,
+			domain: domain,
+			items:  utils.FormatCount(len(targets)),
+			size:   utils.FormatBytes(domainSize),
+			safety: strings.TrimSpace(safetyStr),
+			impact: impact,
 		})
 
 		totalSize += domainSize
 		totalItems += len(targets)
 	}
 
+	// Widen the domain column for longer cleaner names
+	domainWidth := 12
+	for _, row := range rows {
+		if w := lipgloss.Width(row.domain) + 2; w > domainWidth {
+			domainWidth = w
+		}
+	}
+	lineWidth := domainWidth + 38
+
 	// Build table with lipgloss
-	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Padding(0, 1)
-	cellStyle := lipgloss.NewStyle().Padding(0, 1)
+	headerStyle := r.style(lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Padding(0, 1))
+	cellStyle := r.renderer.NewStyle().Padding(0, 1)
 
 	// Print header
-	fmt.Printf("%s%s%s%s%s\n",
-		headerStyle.Width(12).Render("DOMAIN"),
-		headerStyle.Width(8).Align(lipgloss.Right).Render("ITEMS"),
-		headerStyle.Width(10).Align(lipgloss.Right).Render("SIZE"),
-		headerStyle.Width(10).Render("SAFETY"),
-		headerStyle.Width(10).Render("IMPACT"),
+	fmt.Fprintf(r.out, "%s%s%s%s%s\n",
+		headerStyle.Width(domainWidth).Render(r.msg("estimation.domain")),
+		headerStyle.Width(8).Align(lipgloss.Right).Render(r.msg("estimation.items")),
+		headerStyle.Width(10).Align(lipgloss.Right).Render(r.msg("estimation.size")),
+		headerStyle.Width(10).Render(r.msg("estimation.safety")),
+		headerStyle.Width(10).Render(r.msg("estimation.impact")),
 	)
 
 	// Print separator
-	fmt.Println(mutedStyle.Render(strings.Repeat("─", 50)))
+	fmt.Fprintln(r.out, r.style(mutedStyle).Render(strings.Repeat("─", lineWidth)))
 
 	// Print rows
 	for _, row := range rows {
-		impactStyled := row.impact
+		var impactStyled string
 		switch row.impact {
 		case "Very High":
-			impactStyled = errorStyle.Render(row.impact)
+			impactStyled = r.style(errorStyle).Render(r.msg("impact.very_high"))
 		case "High":
-			impactStyled = warningStyle.Render(row.impact)
+			impactStyled = r.style(warningStyle).Render(r.msg("impact.high"))
 		case "Medium":
-			impactStyled = infoStyle.Render(row.impact)
+			impactStyled = r.style(infoStyle).Render(r.msg("impact.medium"))
 		default:
-			impactStyled = mutedStyle.Render(row.impact)
+			impactStyled = r.style(mutedStyle).Render(r.msg("impact.low"))
 		}
 
 		safetyStyled := row.safety
-		if strings.Contains(row.safety, "Safe") {
-			safetyStyled = successStyle.Render(row.safety)
-		} else if strings.Contains(row.safety, "Risk") {
-			safetyStyled = errorStyle.Render(row.safety)
+		if strings.Contains(row.safety, r.msg("safety.safe")) {
+			safetyStyled = r.style(successStyle).Render(row.safety)
+		} else if strings.Contains(row.safety, r.msg("safety.dangerous")) {
+			safetyStyled = r.style(errorStyle).Render(row.safety)
 		}
 
-		fmt.Printf("%s%s%s%s%s\n",
-			cellStyle.Width(12).Render(row.domain),
+		fmt.Fprintf(r.out, "%s%s%s%s%s\n",
+			cellStyle.Width(domainWidth).Render(row.domain),
 			cellStyle.Width(8).Align(lipgloss.Right).Render(row.items),
 			cellStyle.Width(10).Align(lipgloss.Right).Render(row.size),
 			cellStyle.Width(10).Render(safetyStyled),
@@ -237,15 +372,26 @@ func (r *Reporter) PrintEstimation(targetsByDomain map[string][]cleaner.CleanTar
 	}
 
 	// Print footer
-	fmt.Println(mutedStyle.Render(strings.Repeat("─", 50)))
-	fmt.Printf("%s%s%s%s%s\n",
-		titleStyle.Padding(0, 1).Width(12).Render("Total"),
-		successStyle.Padding(0, 1).Width(8).Align(lipgloss.Right).Render(utils.FormatCount(totalItems)),
-		successStyle.Padding(0, 1).Width(10).Align(lipgloss.Right).Render(utils.FormatBytes(totalSize)),
+	fmt.Fprintln(r.out, r.style(mutedStyle).Render(strings.Repeat("─", lineWidth)))
+	fmt.Fprintf(r.out, "%s%s%s%s%s\n",
+		r.style(titleStyle).Padding(0, 1).Width(domainWidth).Render(r.msg("estimation.total")),
+		r.style(successStyle).Padding(0, 1).Width(8).Align(lipgloss.Right).Render(utils.FormatCount(totalItems)),
+		r.style(successStyle).Padding(0, 1).Width(10).Align(lipgloss.Right).Render(utils.FormatBytes(totalSize)),
 		cellStyle.Width(10).Render(""),
 		cellStyle.Width(10).Render(""),
 	)
-	fmt.Println()
+
+	// Flag cleaners that ran out of time, including those that found nothing
+	partial := []string{}
+	for name := range r.partial {
+		partial = append(partial, name)
+	}
+	sort.Strings(partial)
+	for _, name := range partial {
+		fmt.Fprintln(r.out, r.style(warningStyle).Render("  * "+r.msg("estimation.partial", name)))
+	}
+
+	fmt.Fprintln(r.out)
 }
 
 // PrintTargetDetails prints detailed information about targets
@@ -254,135 +400,1102 @@ func (r *Reporter) PrintTargetDetails(targets []cleaner.CleanTarget) {
 		return
 	}
 
-	fmt.Println(warningStyle.Render("\n📋 Detailed Breakdown:\n"))
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n📋 Detailed Breakdown:\n"))
 
 	for _, target := range targets {
 		safetyIcon := target.Safety.Icon()
-		fmt.Printf("  %s %s - %s (%s)\n",
+		description := target.Description
+		if target.Category != "" {
+			// Category is the key to use for overrides in the config file
+			description += " " + r.style(mutedStyle).Render("["+target.Category+"]")
+		}
+		fmt.Fprintf(r.out, "  %s %s - %s (%s)\n",
 			safetyIcon,
-			target.Description,
-			successStyle.Render(utils.FormatBytes(target.SizeBytes)),
-			mutedStyle.Render(target.Path),
+			description,
+			r.targetSize(target),
+			r.style(mutedStyle).Render(target.Path),
 		)
+		if note := r.rebuildNote(target); note != "" {
+			fmt.Fprintf(r.out, "     %s\n", r.style(infoStyle).Render(note))
+		}
+		if note := r.actionNote(target); note != "" {
+			fmt.Fprintf(r.out, "     %s\n", r.style(infoStyle).Render(note))
+		}
+	}
+
+	fmt.Fprintln(r.out)
+}
+
+// rebuildNote describes what rebuilding a target would cost, or returns ""
+// for targets that need no rebuild
+func (r *Reporter) rebuildNote(target cleaner.CleanTarget) string {
+	cost, ok := target.RebuildCost()
+	if !ok {
+		return ""
+	}
+	if cost.Download == 0 {
+		return "↻ " + r.msg("rebuild.compile", utils.FormatDuration(cost.Time), cost.Command)
+	}
+	return "↻ " + r.msg("rebuild.download", utils.FormatBytes(cost.Download), utils.FormatDuration(cost.Time), cost.Command)
+}
+
+// actionNote lists the commands a maintenance action runs, or returns "" for
+// targets that are deleted
+func (r *Reporter) actionNote(target cleaner.CleanTarget) string {
+	commands := target.ActionCommands()
+	if len(commands) == 0 {
+		return ""
 	}
+	return "⚙ " + r.msg("action.runs", strings.Join(commands, " && "))
+}
 
-	fmt.Println()
+// targetSize renders the size of a target, with the number of files in it
+// when they were counted, or marks it as an action when running it frees
+// nothing measurable
+func (r *Reporter) targetSize(target cleaner.CleanTarget) string {
+	if target.Action == cleaner.ActionRun && target.SizeBytes == 0 {
+		return r.style(infoStyle).Render(r.msg("action.label"))
+	}
+	if target.Files > 0 {
+		return r.style(successStyle).Render(r.msg("target.files", utils.FormatBytes(target.SizeBytes), utils.FormatCount(target.Files)))
+	}
+	return r.style(successStyle).Render(utils.FormatBytes(target.SizeBytes))
 }
 
 // PrintProgress prints a progress indicator
 func (r *Reporter) PrintProgress(current, total int, description string) {
+	r.PrintProgressETA(current, total, description, 0)
+}
+
+// PrintProgressETA prints a progress indicator with the time left, if
+// positive. The line is padded to hide the end of a longer one it replaces.
+func (r *Reporter) PrintProgressETA(current, total int, description string, remaining time.Duration) {
 	percent := float64(current) / float64(total)
 	bar := r.progress.ViewAs(percent)
-	fmt.Printf("\r%s %s [%d/%d]", description, bar, current, total)
+	line := fmt.Sprintf("%s %s [%d/%d]", description, bar, current, total)
+	if remaining > 0 && current < total {
+		line += " " + r.msg("progress.eta", utils.FormatDuration(remaining.Round(time.Second)))
+	}
+
+	width := lipgloss.Width(line)
+	if width < r.progressWidth {
+		line += strings.Repeat(" ", r.progressWidth-width)
+	}
+	r.progressWidth = width
+
+	fmt.Fprint(r.out, "\r"+line)
 	if current == total {
-		fmt.Println()
+		fmt.Fprintln(r.out)
+		r.progressWidth = 0
 	}
 }
 
+// EndProgress ends a progress line stopped before reaching its total
+func (r *Reporter) EndProgress() {
+	fmt.Fprintln(r.out)
+	r.progressWidth = 0
+}
+
 // PrintCleanResults prints the results of a cleaning operation
 func (r *Reporter) PrintCleanResults(results []cleaner.CleanResult, dryRun bool) {
 	if dryRun {
-		fmt.Println(infoStyle.Render("\n✨ Dry Run Complete - No files were deleted\n"))
+		fmt.Fprintln(r.out, r.style(infoStyle).Render("\n✨ "+r.msg("results.dry_run")+"\n"))
 	} else {
-		fmt.Println(successStyle.Render("\n✅ Cleaning Complete!\n"))
+		fmt.Fprintln(r.out, r.style(successStyle).Render("\n✅ "+r.msg("results.done")+"\n"))
 	}
 
 	// Calculate statistics
 	totalFreed := int64(0)
+	totalQuarantined := int64(0)
+	totalArchived := int64(0)
+	totalEstimated := int64(0)
 	totalFiles := 0
+	filesDeleted := 0
+	actions := 0
 	failures := 0
 
 	for _, result := range results {
 		totalFreed += result.BytesFreed
-		if result.Success {
-			totalFiles++
-		} else {
+		totalQuarantined += result.BytesQuarantined
+		totalArchived += result.BytesArchived
+		totalEstimated += result.Target.SizeBytes
+		filesDeleted += result.Files
+		switch {
+		case !result.Success:
 			failures++
+		case result.Target.Action == cleaner.ActionRun:
+			actions++
+		default:
+			totalFiles++
 		}
 	}
 
 	// Print summary with styled output
-	actionVerb := getActionVerb(dryRun)
+	spaceLabel, itemsLabel := r.msg("results.space"), r.msg("results.items")
+	if dryRun {
+		spaceLabel, itemsLabel = r.msg("results.space.dry_run"), r.msg("results.items.dry_run")
+	}
 
-	fmt.Printf("  💾 Space %s: %s\n",
-		actionVerb,
-		successStyle.Render(utils.FormatBytes(totalFreed)),
+	fmt.Fprintf(r.out, "  💾 %s: %s\n",
+		spaceLabel,
+		r.style(successStyle).Render(utils.FormatBytes(totalFreed)),
 	)
-	fmt.Printf("  📁 Items %s: %s\n",
-		actionVerb,
-		successStyle.Render(utils.FormatCount(totalFiles)),
+	// Quarantined targets still take their space until the quarantine is purged
+	if totalQuarantined > 0 {
+		fmt.Fprintf(r.out, "  📦 %s: %s\n",
+			r.msg("results.quarantined"),
+			r.style(successStyle).Render(utils.FormatBytes(totalQuarantined)),
+		)
+	}
+	if totalArchived > 0 {
+		fmt.Fprintf(r.out, "  🗂️ %s: %s\n",
+			r.msg("results.archived"),
+			r.style(successStyle).Render(utils.FormatBytes(totalArchived)),
+		)
+	}
+	// Sizes are measured again while deleting: show how far off the scan was
+	if removed := totalFreed + totalQuarantined + totalArchived; !dryRun && totalEstimated != removed {
+		fmt.Fprintf(r.out, "  📐 %s\n", r.msg("results.estimated",
+			utils.FormatBytes(totalEstimated),
+			r.style(mutedStyle).Render(formatSignedBytes(removed-totalEstimated)),
+		))
+	}
+	fmt.Fprintf(r.out, "  📁 %s: %s\n",
+		itemsLabel,
+		r.style(successStyle).Render(utils.FormatCount(totalFiles)),
 	)
 
+	if actions > 0 {
+		actionsLabel := r.msg("results.actions")
+		if dryRun {
+			actionsLabel = r.msg("results.actions.dry_run")
+		}
+		fmt.Fprintf(r.out, "  ⚙️  %s: %s\n",
+			actionsLabel,
+			r.style(successStyle).Render(utils.FormatCount(actions)),
+		)
+	}
+
+	if filesDeleted > 0 {
+		fmt.Fprintf(r.out, "  🗑️  %s: %s\n",
+			r.msg("results.files_deleted"),
+			r.style(successStyle).Render(utils.FormatCount(filesDeleted)),
+		)
+	}
+
 	if failures > 0 {
-		fmt.Printf("  ❌ Failures: %s\n",
-			errorStyle.Render(utils.FormatCount(failures)),
+		fmt.Fprintf(r.out, "  ❌ %s: %s\n",
+			r.msg("results.failures"),
+			r.style(errorStyle).Render(utils.FormatCount(failures)),
 		)
 	}
 
+	// Show where the space comes from before anything is deleted
+	if dryRun {
+		r.printProjectBreakdown(results)
+		r.printCommands(results)
+	}
+
 	// Print failures if any
 	if failures > 0 && r.verbose {
-		fmt.Println(errorStyle.Render("\n❌ Failed Items:\n"))
+		fmt.Fprintln(r.out, r.style(errorStyle).Render("\n❌ "+r.msg("results.failed_items")+"\n"))
 		for _, result := range results {
 			if !result.Success {
-				fmt.Printf("  • %s: %v\n",
-					mutedStyle.Render(result.Target.Path),
-					errorStyle.Render(result.Error.Error()),
+				fmt.Fprintf(r.out, "  • %s: %v\n",
+					r.style(mutedStyle).Render(result.Target.Path),
+					r.style(errorStyle).Render(result.Error.Error()),
 				)
+				if result.Files > 0 {
+					// Partial removal: the rest of the target was deleted
+					fmt.Fprintf(r.out, "    %s\n", r.style(mutedStyle).Render(r.msg("results.partial_removal",
+						utils.FormatCount(result.Files), utils.FormatBytes(result.BytesFreed))))
+				}
 			}
 		}
 	}
 
-	fmt.Println()
+	fmt.Fprintln(r.out)
+}
+
+// PrintVerification prints how many cleaned targets are gone as expected,
+// partially freed or still there, listing the last two with what is left of
+// them. Failed targets that couldn't be checked on disk show their error.
+func (r *Reporter) PrintVerification(verifications []cleaner.Verification) {
+	if len(verifications) == 0 {
+		return
+	}
+	counts := make(map[cleaner.Outcome]int)
+	for _, v := range verifications {
+		counts[v.Outcome]++
+	}
+
+	fmt.Fprintf(r.out, "🔎 %s\n", r.msg("results.verified",
+		r.style(successStyle).Render(utils.FormatCount(counts[cleaner.Freed])),
+		r.style(warningStyle).Render(utils.FormatCount(counts[cleaner.Partial])),
+		r.style(errorStyle).Render(utils.FormatCount(counts[cleaner.Failed])),
+	))
+	for _, v := range verifications {
+		switch v.Outcome {
+		case cleaner.Partial:
+			fmt.Fprintf(r.out, "  • %s: %s\n", v.Result.Target.Path, r.style(warningStyle).Render(
+				r.msg("results.verify_partial", utils.FormatBytes(v.Result.BytesFreed), utils.FormatBytes(v.Remaining))))
+		case cleaner.Failed:
+			detail := r.msg("results.verify_failed", utils.FormatBytes(v.Remaining))
+			if v.Remaining == 0 && v.Result.Error != nil {
+				detail = v.Result.Error.Error()
+			}
+			fmt.Fprintf(r.out, "  • %s: %s\n", v.Result.Target.Path, r.style(errorStyle).Render(detail))
+		}
+	}
+	fmt.Fprintln(r.out)
+}
+
+// printCommands lists the commands a dry run would have run for targets
+// cleaned through other tools (docker, brew, ...)
+func (r *Reporter) printCommands(results []cleaner.CleanResult) {
+	commands := []string{}
+	for _, result := range results {
+		commands = append(commands, result.Commands...)
+	}
+	if len(commands) == 0 {
+		return
+	}
+
+	fmt.Fprintln(r.out, r.style(infoStyle).Render("\n🔧 "+r.msg("results.commands")+"\n"))
+	for _, command := range commands {
+		fmt.Fprintf(r.out, "  $ %s\n", command)
+	}
+}
+
+// projectGroup is the set of targets found inside one project
+type projectGroup struct {
+	root  string
+	items []projectItem
+	total int64
+}
+
+// projectItem is a target inside a project, named relative to the project root
+type projectItem struct {
+	name string
+	size int64
+}
+
+// groupByProject groups results by the project they live in, largest project
+// first. Results outside any project are left out.
+func groupByProject(results []cleaner.CleanResult) []projectGroup {
+	groups := make(map[string]*projectGroup)
+
+	for _, result := range results {
+		if !result.Success {
+			continue
+		}
+
+		root := utils.FindProjectRoot(result.Target.Path)
+		if root == "" {
+			continue
+		}
+
+		group, ok := groups[root]
+		if !ok {
+			group = &projectGroup{root: root}
+			groups[root] = group
+		}
+
+		name, err := filepath.Rel(root, result.Target.Path)
+		if err != nil {
+			name = result.Target.Path
+		}
+		group.items = append(group.items, projectItem{name: name, size: result.BytesFreed})
+		group.total += result.BytesFreed
+	}
+
+	sorted := make([]projectGroup, 0, len(groups))
+	for _, group := range groups {
+		sort.Slice(group.items, func(i, j int) bool {
+			return group.items[i].size > group.items[j].size
+		})
+		sorted = append(sorted, *group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].total != sorted[j].total {
+			return sorted[i].total > sorted[j].total
+		}
+		return sorted[i].root < sorted[j].root
+	})
+
+	return sorted
+}
+
+// printProjectBreakdown prints the targets found inside projects with a
+// subtotal per project
+func (r *Reporter) printProjectBreakdown(results []cleaner.CleanResult) {
+	groups := groupByProject(results)
+	if len(groups) == 0 {
+		return
+	}
+
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n📦 "+r.msg("results.by_project")+"\n"))
+
+	for _, group := range groups {
+		fmt.Fprintf(r.out, "  %s  %s  %s\n",
+			r.style(subtitleStyle).Render(filepath.Base(group.root)),
+			r.style(successStyle).Render(utils.FormatBytes(group.total)),
+			r.style(mutedStyle).Render(group.root),
+		)
+
+		for _, item := range group.items {
+			fmt.Fprintf(r.out, "    %-30s %10s\n", item.name, utils.FormatBytes(item.size))
+		}
+	}
+}
+
+// slowestDirsShown is how many directories the scan profile lists
+const slowestDirsShown = 10
+
+// CleanerTiming is how long one cleaner took to detect and scan
+type CleanerTiming struct {
+	Name     string
+	Duration time.Duration
+	Targets  int  // Targets found
+	Files    int  // Files visited by its project scans
+	Dirs     int  // Directories visited by its project scans
+	TimedOut bool // Scan ran out of time
+}
+
+// PrintScanProfile prints how long each cleaner took, the time spent in each
+// search directory and the slowest folders inside them
+func (r *Reporter) PrintScanProfile(timings []CleanerTiming, dirs []scanner.DirStats) {
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n⏱️  Scan Profile:\n"))
+
+	sorted := append([]CleanerTiming(nil), timings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Duration > sorted[j].Duration
+	})
+
+	fmt.Fprintf(r.out, "  %-22s %9s %8s %12s %10s\n", "Cleaner", "Time", "Targets", "Files", "Dirs")
+	for _, timing := range sorted {
+		name := timing.Name
+		if timing.TimedOut {
+			name += "*"
+		}
+		fmt.Fprintf(r.out, "  %-22s %9s %8s %12s %10s\n",
+			name,
+			utils.FormatDuration(timing.Duration),
+			utils.FormatCount(timing.Targets),
+			utils.FormatCount(timing.Files),
+			utils.FormatCount(timing.Dirs),
+		)
+	}
+
+	roots := []scanner.DirStats{}
+	children := []scanner.DirStats{}
+	for _, stats := range dirs {
+		if stats.Root {
+			roots = append(roots, stats)
+		} else {
+			children = append(children, stats)
+		}
+	}
+
+	if len(roots) > 0 {
+		fmt.Fprintln(r.out, r.style(warningStyle).Render("\n📂 Search Directories:\n"))
+		for _, stats := range roots {
+			r.printDirStats(stats)
+		}
+	}
+
+	if len(children) > 0 {
+		fmt.Fprintln(r.out, r.style(warningStyle).Render("\n🐢 Slowest Directories:\n"))
+		for i, stats := range children {
+			if i == slowestDirsShown {
+				break
+			}
+			r.printDirStats(stats)
+		}
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("\n  Skip slow folders with --exclude or scan less deep with --max-depth"))
+	}
+
+	fmt.Fprintln(r.out)
+}
+
+// printDirStats prints the walk statistics of one directory
+func (r *Reporter) printDirStats(stats scanner.DirStats) {
+	walks := "walked once"
+	if stats.Walks > 1 {
+		walks = fmt.Sprintf("walked %d times", stats.Walks)
+	}
+
+	fmt.Fprintf(r.out, "  %9s  %s  %s\n",
+		r.style(successStyle).Render(utils.FormatDuration(stats.Duration)),
+		r.style(subtitleStyle).Render(stats.Path),
+		r.style(mutedStyle).Render(fmt.Sprintf("(%s, %s files, %s dirs)",
+			walks, utils.FormatCount(stats.Files), utils.FormatCount(stats.Dirs))),
+	)
+}
+
+// ageBarWidth is the width of the bars of size-by-age histograms
+const ageBarWidth = 20
+
+// PrintAgeHistograms prints how the contents of large targets split by age,
+// to show how much space entries unused for a month or more take
+func (r *Reporter) PrintAgeHistograms(ages []cleaner.TargetAge) {
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n📅 Size by Age:\n"))
+
+	if len(ages) == 0 {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("  No cache directory large enough to analyze"))
+		fmt.Fprintln(r.out)
+		return
+	}
+
+	for _, age := range ages {
+		total := age.Total()
+		fmt.Fprintf(r.out, "  %s  %s  %s\n",
+			r.style(subtitleStyle).Render(age.Target.Description),
+			r.style(successStyle).Render(utils.FormatBytes(total)),
+			r.style(mutedStyle).Render(age.Target.Path),
+		)
+
+		for _, bucket := range age.Buckets {
+			filled := 0
+			if total > 0 {
+				filled = int(bucket.Size * ageBarWidth / total)
+			}
+			bar := strings.Repeat("█", filled) + strings.Repeat("░", ageBarWidth-filled)
+			fmt.Fprintf(r.out, "    %-7s %s %10s %6s\n",
+				bucket.Label,
+				r.style(infoStyle).Render(bar),
+				utils.FormatBytes(bucket.Size),
+				utils.FormatPercentage(bucket.Size, total),
+			)
+		}
+
+		old := age.OlderThan(30 * 24 * time.Hour)
+		fmt.Fprintf(r.out, "    %s\n", r.style(mutedStyle).Render(fmt.Sprintf("Not modified for 30 days or more: %s (%s)",
+			utils.FormatBytes(old), utils.FormatPercentage(old, total))))
+		fmt.Fprintln(r.out)
+	}
+}
+
+// PrintSnapshots prints the local Time Machine snapshots of the startup
+// volume and the purgeable space they hold
+func (r *Reporter) PrintSnapshots(snapshots []disk.Snapshot, purgeable int64) {
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n🕰️  Local Time Machine Snapshots:\n"))
+
+	if len(snapshots) == 0 {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("  No local snapshots"))
+		fmt.Fprintln(r.out)
+		return
+	}
+
+	for _, snapshot := range snapshots {
+		date := "unknown date"
+		if !snapshot.Date.IsZero() {
+			date = snapshot.Date.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(r.out, "  %s  %s\n", r.style(subtitleStyle).Render(date), r.style(mutedStyle).Render(snapshot.Name))
+	}
+
+	fmt.Fprintf(r.out, "\n  %d snapshots, about %s purgeable\n", len(snapshots), r.style(successStyle).Render(utils.FormatBytes(purgeable)))
+	fmt.Fprintln(r.out, r.style(mutedStyle).Render("  Space freed by cleaning stays in use until these snapshots are thinned"))
+	fmt.Fprintln(r.out)
+}
+
+// PrintQuarantine prints the entries kept in the quarantine, oldest first
+func (r *Reporter) PrintQuarantine(entries []quarantine.Entry) {
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n🗄️  Quarantine:\n"))
+
+	if len(entries) == 0 {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("  The quarantine is empty"))
+		fmt.Fprintln(r.out)
+		return
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.SizeBytes
+		fmt.Fprintf(r.out, "  %s  %10s  %s\n",
+			r.style(subtitleStyle).Render(entry.QuarantinedAt.Local().Format("2006-01-02 15:04")),
+			utils.FormatBytes(entry.SizeBytes),
+			entry.OriginalPath)
+	}
+
+	fmt.Fprintf(r.out, "\n  %d entries, %s in quarantine\n", len(entries), r.style(successStyle).Render(utils.FormatBytes(total)))
+	fmt.Fprintln(r.out)
+}
+
+// PrintQuarantineStatus prints what the quarantine at dir holds, and the
+// limits past which its oldest targets are purged
+func (r *Reporter) PrintQuarantineStatus(dir string, status quarantine.Status, limits quarantine.Limits, enabled bool) {
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n🗄️  Quarantine:\n"))
+
+	mode := "off (targets are deleted)"
+	if enabled {
+		mode = "on (targets are moved to the quarantine)"
+	}
+	fmt.Fprintf(r.out, "  Mode:       %s\n", mode)
+	fmt.Fprintf(r.out, "  Folder:     %s\n", r.style(mutedStyle).Render(dir))
+	fmt.Fprintf(r.out, "  Holdings:   %d targets, %s\n", status.Entries, r.style(successStyle).Render(utils.FormatBytes(status.Bytes)))
+	if !status.Oldest.IsZero() {
+		fmt.Fprintf(r.out, "  Oldest:     %s\n", status.Oldest.Local().Format("2006-01-02 15:04"))
+	}
+
+	retention := "none"
+	if limits.MaxAge > 0 {
+		retention = fmt.Sprintf("%d days", int(limits.MaxAge.Hours()/24))
+	}
+	sizeCap := "none"
+	if limits.MaxBytes > 0 {
+		sizeCap = utils.FormatBytes(limits.MaxBytes)
+		if status.Bytes > limits.MaxBytes {
+			sizeCap += r.style(warningStyle).Render(" (exceeded, the oldest targets are purged after the next clean)")
+		}
+	}
+	fmt.Fprintf(r.out, "  Retention:  %s\n", retention)
+	fmt.Fprintf(r.out, "  Size cap:   %s\n", sizeCap)
+	fmt.Fprintln(r.out)
+}
+
+// Inspection is what epurer knows about one path (epurer inspect)
+type Inspection struct {
+	Path      string
+	SizeBytes int64
+	Ages      []cleaner.AgeBucket
+	Claims    []cleaner.Claim  // Targets that are the path, contain it or lie inside it
+	Excluded  string           // Why scans skip the path, if they do
+	Protected string           // Why cleaning leaves the path alone, if it does
+	History   []history.Record // Past cleans of the path, newest first
+}
+
+// minimumLevels are the lowest clean levels that clean each safety level
+var minimumLevels = map[config.SafetyLevel]config.CleanLevel{
+	config.Safe:      config.Conservative,
+	config.Moderate:  config.Standard,
+	config.Dangerous: config.Aggressive,
+}
+
+// PrintInspection prints what epurer knows about a path: the targets that
+// claim it, its size by age, whether it is excluded or protected, and when
+// it was cleaned before
+func (r *Reporter) PrintInspection(inspection Inspection) {
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n🔎 "+inspection.Path+"\n"))
+
+	total := int64(0)
+	for _, bucket := range inspection.Ages {
+		total += bucket.Size
+	}
+	fmt.Fprintf(r.out, "  Size: %s\n", r.style(successStyle).Render(utils.FormatBytes(inspection.SizeBytes)))
+	for _, bucket := range inspection.Ages {
+		fmt.Fprintf(r.out, "    %-7s %10s %6s\n", bucket.Label, utils.FormatBytes(bucket.Size), utils.FormatPercentage(bucket.Size, total))
+	}
+
+	fmt.Fprintln(r.out)
+	if inspection.Excluded != "" {
+		fmt.Fprintf(r.out, "  %s %s\n", r.style(warningStyle).Render("Excluded:"), inspection.Excluded)
+	}
+	if inspection.Protected != "" {
+		fmt.Fprintf(r.out, "  %s %s\n", r.style(warningStyle).Render("Protected:"), inspection.Protected)
+	}
+
+	covered := false
+	for _, claim := range inspection.Claims {
+		covered = covered || claim.Covers
+	}
+	switch {
+	case covered:
+		fmt.Fprintln(r.out, "  Claimed by:")
+	case len(inspection.Claims) > 0:
+		fmt.Fprintln(r.out, "  No cleaner claims the path itself; targets inside it:")
+	default:
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("  No cleaner claims this path, nor anything inside it"))
+	}
+	for _, claim := range inspection.Claims {
+		target := claim.Target
+		fmt.Fprintf(r.out, "    %s %s  %s  %s\n",
+			target.Safety.Icon(),
+			r.style(subtitleStyle).Render(target.Description),
+			r.style(successStyle).Render(utils.FormatBytes(target.SizeBytes)),
+			r.style(mutedStyle).Render(fmt.Sprintf("%s, %s, cleaned from --level %s", claim.Cleaner, target.Category, minimumLevels[target.Safety])))
+		if !utils.SamePath(target.Path, inspection.Path) {
+			fmt.Fprintf(r.out, "      %s\n", r.style(mutedStyle).Render(target.Path))
+		}
+		if explanation, ok := target.Explain(); ok && claim.Covers {
+			r.printExplanation(explanation, "      ")
+		}
+	}
+
+	fmt.Fprintln(r.out)
+	if len(inspection.History) == 0 {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("  Never cleaned before"))
+	} else {
+		fmt.Fprintln(r.out, "  Cleaned before:")
+	}
+	for _, record := range inspection.History {
+		outcome := utils.FormatBytes(record.Result.BytesFreed) + " freed"
+		if record.Result.BytesQuarantined > 0 {
+			outcome = utils.FormatBytes(record.Result.BytesQuarantined) + " moved to quarantine"
+		}
+		if record.Result.BytesArchived > 0 {
+			outcome = utils.FormatBytes(record.Result.BytesArchived) + " archived"
+		}
+		if !record.Result.Success {
+			outcome = "failed: " + record.Result.Error
+		}
+		fmt.Fprintf(r.out, "    %s  %-6s %s  %s\n",
+			r.style(subtitleStyle).Render(record.StartedAt.Local().Format("2006-01-02 15:04")),
+			record.Command,
+			outcome,
+			r.style(mutedStyle).Render(record.Result.Path))
+	}
+	fmt.Fprintln(r.out)
+}
+
+// PrintTerraformDuplicates prints the provider releases installed in
+// several .terraform folders and the space a shared plugin cache would save.
+// cacheDir is the plugin cache already configured, if any.
+func (r *Reporter) PrintTerraformDuplicates(duplicates []cleaner.TerraformProviderCopies, cacheDir string) {
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n🧱 Duplicate Terraform Providers:\n"))
+
+	if len(duplicates) == 0 {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("  No provider is installed in more than one .terraform folder"))
+		fmt.Fprintln(r.out)
+		return
+	}
+
+	var savings int64
+	for _, copies := range duplicates {
+		savings += copies.Savings()
+		fmt.Fprintf(r.out, "  %-50s %-10s %-14s %2d copies  %s\n",
+			copies.Source,
+			copies.Version,
+			copies.Platform,
+			len(copies.Paths),
+			r.style(successStyle).Render(utils.FormatBytes(copies.Savings())))
+
+		if r.verbose {
+			for _, path := range copies.Paths {
+				fmt.Fprintf(r.out, "    %s\n", r.style(mutedStyle).Render(path))
+			}
+		}
+	}
+
+	fmt.Fprintf(r.out, "\n  A shared plugin cache would save about %s\n", r.style(successStyle).Render(utils.FormatBytes(savings)))
+	if cacheDir != "" {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("  Plugin cache: "+cacheDir+" (run terraform init again in each project to use it)"))
+	}
+	fmt.Fprintln(r.out)
+}
+
+// nodeDuplicatesShown is how many duplicated npm packages are listed
+// without --verbose
+const nodeDuplicatesShown = 20
+
+// PrintExplanations prints what each kind of target found is, what brings
+// it back and what deleting it costs
+func (r *Reporter) PrintExplanations(categories []cleaner.ExplainedCategory) {
+	fmt.Fprintln(r.out, r.style(subtitleStyle).Render("\n❓ "+r.msg("explain.title")+"\n"))
+
+	if len(categories) == 0 {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("  "+r.msg("explain.none")))
+		fmt.Fprintln(r.out)
+		return
+	}
+
+	for _, category := range categories {
+		fmt.Fprintf(r.out, "  %s  %s\n",
+			r.style(subtitleStyle).Render(category.Example),
+			r.style(mutedStyle).Render(r.msg("explain.targets", category.Targets, utils.FormatBytes(category.SizeBytes))))
+		r.printExplanation(category.Explanation, "    ")
+		fmt.Fprintln(r.out)
+	}
+}
+
+// PrintConsequences prints what cleaning will leave to do, so it can be
+// weighed before confirming. Nothing is printed when there is nothing to do.
+func (r *Reporter) PrintConsequences(consequences []cleaner.Consequence) {
+	if len(consequences) == 0 {
+		return
+	}
+
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n↻ "+r.msg("consequences.title")))
+	for _, consequence := range consequences {
+		fmt.Fprintf(r.out, "  • %s\n", consequence.Summary)
+	}
+	fmt.Fprintln(r.out)
+}
+
+// printExplanation prints the parts of an explanation, indented
+func (r *Reporter) printExplanation(explanation cleaner.Explanation, indent string) {
+	fmt.Fprintf(r.out, "%s%s: %s\n", indent, r.msg("explain.what"), explanation.What)
+	fmt.Fprintf(r.out, "%s%s: %s\n", indent, r.msg("explain.regenerates"), explanation.Regenerates)
+	fmt.Fprintf(r.out, "%s%s: %s\n", indent, r.msg("explain.consequence"), explanation.Consequence)
+}
+
+// PrintNodeDuplicates prints the npm packages installed more than once
+// across node_modules folders, then how pnpm or workspaces would share them
+func (r *Reporter) PrintNodeDuplicates(duplicates []cleaner.NodePackageCopies, workspaces []cleaner.NodeWorkspaceCandidate) {
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n📦 Duplicate npm Packages:\n"))
+
+	if len(duplicates) == 0 {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("  No package release is installed more than once"))
+		fmt.Fprintln(r.out)
+		return
+	}
+
+	var total, savings int64
+	for i, copies := range duplicates {
+		total += copies.Total()
+		savings += copies.Savings()
+		if i >= nodeDuplicatesShown && !r.verbose {
+			continue
+		}
+
+		fmt.Fprintf(r.out, "  %-45s %3d copies  %10s  %s\n",
+			copies.Name+"@"+copies.Version,
+			len(copies.Paths),
+			utils.FormatBytes(copies.Total()),
+			r.style(successStyle).Render(utils.FormatBytes(copies.Savings())))
+
+		if r.verbose {
+			for _, path := range copies.Paths {
+				fmt.Fprintf(r.out, "    %s\n", r.style(mutedStyle).Render(path))
+			}
+		}
+	}
+	if hidden := len(duplicates) - nodeDuplicatesShown; hidden > 0 && !r.verbose {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render(fmt.Sprintf("  ... and %d more (--verbose to list them)", hidden)))
+	}
+
+	fmt.Fprintf(r.out, "\n  %d duplicated releases take %s; installing each once would save about %s\n",
+		len(duplicates), utils.FormatBytes(total), r.style(successStyle).Render(utils.FormatBytes(savings)))
+
+	fmt.Fprintln(r.out, r.style(subtitleStyle).Render("\n  💡 Recommendations:"))
+	fmt.Fprintln(r.out, "  • pnpm keeps one copy of each release in a global store and hard-links it into projects")
+	fmt.Fprintln(r.out, r.style(mutedStyle).Render("    (`pnpm import` converts an existing lockfile)"))
+	for _, workspace := range workspaces {
+		fmt.Fprintf(r.out, "  • The %d projects in %s share packages; a workspace there would save about %s\n",
+			workspace.Projects, workspace.Dir, r.style(successStyle).Render(utils.FormatBytes(workspace.Savings)))
+	}
+	fmt.Fprintln(r.out)
+}
+
+// fileKinds labels the kinds of large files users usually look for, by
+// extension
+var fileKinds = map[string]string{
+	".dmg": "disk image", ".iso": "disk image", ".img": "disk image", ".vmdk": "disk image", ".qcow2": "disk image", ".vdi": "disk image", ".sparseimage": "disk image",
+	".zip": "archive", ".tar": "archive", ".gz": "archive", ".tgz": "archive", ".xz": "archive", ".bz2": "archive", ".7z": "archive", ".rar": "archive", ".xip": "archive", ".pkg": "archive",
+	".mp4": "video", ".mov": "video", ".mkv": "video", ".avi": "video", ".webm": "video", ".m4v": "video",
+	".log": "log",
+	".sql": "database", ".sqlite": "database", ".db": "database", ".dump": "database",
+}
+
+// PrintLargestFiles prints the largest files found, with their kind when
+// it is known
+func (r *Reporter) PrintLargestFiles(files []scanner.ScanResult, minSize int64) {
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n🐘 Largest Files:\n"))
+
+	if len(files) == 0 {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("  No file of "+utils.FormatBytes(minSize)+" or more"))
+		fmt.Fprintln(r.out)
+		return
+	}
+
+	var total int64
+	for _, file := range files {
+		total += file.Size
+		fmt.Fprintf(r.out, "  %s  %-10s  %s\n",
+			r.style(successStyle).Render(fmt.Sprintf("%10s", utils.FormatBytes(file.Size))),
+			fileKinds[strings.ToLower(filepath.Ext(file.Path))],
+			file.Path)
+	}
+
+	fmt.Fprintf(r.out, "\n  %d files take %s\n", len(files), r.style(successStyle).Render(utils.FormatBytes(total)))
+	fmt.Fprintln(r.out)
+}
+
+// duplicateGroupsShown is how many groups of identical files are listed
+// without --verbose
+const duplicateGroupsShown = 20
+
+// PrintDuplicateFiles prints the groups of identical files and the space
+// keeping one copy of each would free
+func (r *Reporter) PrintDuplicateFiles(groups []cleaner.DuplicateGroup) {
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n👯 Duplicate Files:\n"))
+
+	if len(groups) == 0 {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render("  No identical files found"))
+		fmt.Fprintln(r.out)
+		return
+	}
+
+	var savings int64
+	for i, group := range groups {
+		savings += group.Savings()
+		if i >= duplicateGroupsShown && !r.verbose {
+			continue
+		}
+
+		fmt.Fprintf(r.out, "  %d × %s  %s\n",
+			len(group.Paths),
+			utils.FormatBytes(group.Size),
+			r.style(successStyle).Render(utils.FormatBytes(group.Savings())))
+		for _, path := range group.Paths {
+			fmt.Fprintf(r.out, "    %s\n", r.style(mutedStyle).Render(path))
+		}
+	}
+	if hidden := len(groups) - duplicateGroupsShown; hidden > 0 && !r.verbose {
+		fmt.Fprintln(r.out, r.style(mutedStyle).Render(fmt.Sprintf("  ... and %d more groups (--verbose to list them)", hidden)))
+	}
+
+	fmt.Fprintf(r.out, "\n  Keeping one copy of each would save about %s\n", r.style(successStyle).Render(utils.FormatBytes(savings)))
+	fmt.Fprintln(r.out)
+}
+
+// PrintDoctorChecks prints the result of each doctor check, with the fix of
+// those that did not pass
+func (r *Reporter) PrintDoctorChecks(checks []diagnostics.Check) {
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n🩺 Doctor:\n"))
+
+	width := 0
+	for _, check := range checks {
+		width = max(width, len(check.Name))
+	}
+
+	problems := 0
+	for _, check := range checks {
+		fmt.Fprintf(r.out, "  %s %-*s  %s\n", check.Status.Icon(), width, check.Name, check.Detail)
+		if check.Fix != "" {
+			fmt.Fprintf(r.out, "     %s\n", r.style(infoStyle).Render("→ "+check.Fix))
+		}
+		if check.Status == diagnostics.Fail || check.Status == diagnostics.Warn {
+			problems++
+		}
+	}
+
+	fmt.Fprintln(r.out)
+	if problems == 0 {
+		fmt.Fprintln(r.out, r.style(successStyle).Render("  Everything looks good"))
+	} else {
+		fmt.Fprintf(r.out, "  %d checks need attention\n", problems)
+	}
+	fmt.Fprintln(r.out)
+}
+
+// PrintDiskSummary prints the space usage of the startup volume. With after
+// set, it shows before and after columns and compares the space freed by
+// cleaning with the change in available space, which is what Finder shows.
+func (r *Reporter) PrintDiskSummary(before disk.Usage, after *disk.Usage, freed int64) {
+	if after == nil {
+		fmt.Fprintln(r.out, r.style(warningStyle).Render("\n💽 Disk Before Cleaning:\n"))
+	} else {
+		fmt.Fprintln(r.out, r.style(warningStyle).Render("\n💽 Disk Summary:\n"))
+		fmt.Fprintf(r.out, "  %-12s %12s %12s\n", "", "Before", "After")
+	}
+
+	rows := []struct {
+		label  string
+		before int64
+		after  func(disk.Usage) int64
+	}{
+		{"Total", before.Total, func(u disk.Usage) int64 { return u.Total }},
+		{"Used", before.Used, func(u disk.Usage) int64 { return u.Used }},
+		{"Free", before.Free, func(u disk.Usage) int64 { return u.Free }},
+		{"Purgeable", before.Purgeable, func(u disk.Usage) int64 { return u.Purgeable }},
+		{"Available", before.Available(), disk.Usage.Available},
+	}
+
+	for _, row := range rows {
+		if after == nil {
+			fmt.Fprintf(r.out, "  %-12s %12s\n", row.label, utils.FormatBytes(row.before))
+			continue
+		}
+		fmt.Fprintf(r.out, "  %-12s %12s %12s\n", row.label, utils.FormatBytes(row.before), utils.FormatBytes(row.after(*after)))
+	}
+
+	if after != nil {
+		gained := after.Available() - before.Available()
+		fmt.Fprintf(r.out, "\n  Cleaning freed %s, available space changed by %s\n",
+			r.style(successStyle).Render(utils.FormatBytes(freed)),
+			r.style(successStyle).Render(formatSignedBytes(gained)),
+		)
+		if gained < freed {
+			fmt.Fprintln(r.out, r.style(mutedStyle).Render("  The rest is still held by local snapshots or open files, macOS releases it when it needs the space"))
+		}
+	}
+
+	fmt.Fprintln(r.out)
+}
+
+// PrintSmartSelection prints what the history changed in a smart run: the
+// targets skipped since they barely grew back after a recent clean (listed
+// in verbose mode), and the chronic regrowers, which their cleaner cleans
+// first
+func (r *Reporter) PrintSmartSelection(skipped, chronic []cleaner.CleanTarget, regrowth map[string]history.Regrowth, recentDays int) {
+	if len(skipped) > 0 {
+		var size int64
+		for _, target := range skipped {
+			size += target.SizeBytes
+		}
+		r.PrintInfo(fmt.Sprintf("Skipping %d targets (%s) cleaned in the last %d days that have barely grown back since (--skip-recent 0 cleans them)",
+			len(skipped), utils.FormatBytes(size), recentDays))
+		if r.verbose {
+			for _, target := range skipped {
+				fmt.Fprintf(r.out, "  • %s %s\n", target.Path, r.style(mutedStyle).Render(utils.FormatBytes(target.SizeBytes)))
+			}
+		}
+	}
+
+	if len(chronic) > 0 {
+		fmt.Fprintln(r.out, r.style(warningStyle).Render("🔁 Growing back fast after each clean, cleaned first by their cleaner:"))
+		for _, target := range chronic {
+			g := regrowth[target.Path]
+			fmt.Fprintf(r.out, "  • %s %s\n", target.Path, r.style(mutedStyle).Render(
+				fmt.Sprintf("%s a day over %d cleans", utils.FormatBytes(int64(g.BytesPerDay)), g.Cleans)))
+		}
+	}
+	if len(skipped) > 0 || len(chronic) > 0 {
+		fmt.Fprintln(r.out)
+	}
 }
 
 // PrintWarning prints a warning message
 func (r *Reporter) PrintWarning(message string) {
-	fmt.Println(warningStyle.Render("⚠️  " + message))
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("⚠️  "+message))
 }
 
 // PrintError prints an error message
 func (r *Reporter) PrintError(message string) {
-	fmt.Println(errorStyle.Render("❌ " + message))
+	fmt.Fprintln(r.out, r.style(errorStyle).Render("❌ "+message))
 }
 
 // PrintSuccess prints a success message
 func (r *Reporter) PrintSuccess(message string) {
-	fmt.Println(successStyle.Render("✅ " + message))
+	fmt.Fprintln(r.out, r.style(successStyle).Render("✅ "+message))
+}
+
+// PrintVolumeSpace prints the free space of a volume scanned with --volume,
+// and how it changed when after is set
+func (r *Reporter) PrintVolumeSpace(path string, before utils.Volume, after *utils.Volume) {
+	if after == nil {
+		fmt.Fprintf(r.out, "💽 %s (%s): %s free of %s\n", path, before.FSType, utils.FormatBytes(before.Free), utils.FormatBytes(before.Total))
+		return
+	}
+	fmt.Fprintf(r.out, "💽 %s: %s free before cleaning, %s after\n", path, utils.FormatBytes(before.Free), utils.FormatBytes(after.Free))
 }
 
 // PrintInfo prints an info message
 func (r *Reporter) PrintInfo(message string) {
-	fmt.Println(infoStyle.Render("ℹ️  " + message))
+	fmt.Fprintln(r.out, r.style(infoStyle).Render("ℹ️  "+message))
 }
 
 // AskConfirmation asks the user for confirmation
 func (r *Reporter) AskConfirmation(message string) bool {
-	prompt := warningStyle.Render(message + " [y/N]: ")
-	fmt.Printf("\n%s", prompt)
+	fmt.Fprintln(r.out)
+	response := r.ask(message + " " + r.msg("prompt.yes_no") + ": ")
+	return parseTargetAnswer(response) == AnswerYes
+}
+
+// TargetAnswer is the reply to a per-target confirmation
+type TargetAnswer int
+
+const (
+	AnswerNo   TargetAnswer = iota // Skip this target
+	AnswerYes                      // Clean this target
+	AnswerAll                      // Clean this target and every following one without asking
+	AnswerQuit                     // Cancel the whole run
+)
+
+// AskTarget asks whether to clean a single target, showing its path, size
+// and description
+func (r *Reporter) AskTarget(target cleaner.CleanTarget) TargetAnswer {
+	fmt.Fprintf(r.out, "\n  %s %s - %s\n",
+		target.Safety.Icon(),
+		target.Description,
+		r.targetSize(target),
+	)
+	fmt.Fprintf(r.out, "     %s\n", r.style(mutedStyle).Render(target.Path))
+	if note := r.rebuildNote(target); note != "" {
+		fmt.Fprintf(r.out, "     %s\n", r.style(infoStyle).Render(note))
+	}
+	if note := r.actionNote(target); note != "" {
+		fmt.Fprintf(r.out, "     %s\n", r.style(infoStyle).Render(note))
+	}
+
+	return parseTargetAnswer(r.ask("  " + r.msg("prompt.target") + ": "))
+}
+
+// parseTargetAnswer converts a response to AskTarget, in English or French,
+// anything unknown being a no
+func parseTargetAnswer(response string) TargetAnswer {
+	switch response {
+	case "y", "yes", "o", "oui":
+		return AnswerYes
+	case "a", "all", "t", "tout":
+		return AnswerAll
+	case "q", "quit", "quitter":
+		return AnswerQuit
+	default:
+		return AnswerNo
+	}
+}
+
+// ask prints a prompt and returns the user's response in lowercase
+func (r *Reporter) ask(prompt string) string {
+	fmt.Fprint(r.out, r.style(warningStyle).Render(prompt))
 
 	var response string
 	fmt.Scanln(&response)
 
-	response = strings.ToLower(strings.TrimSpace(response))
-	return response == "y" || response == "yes"
+	return strings.ToLower(strings.TrimSpace(response))
 }
 
 // PrintSafetyLegend prints the safety level legend
 func (r *Reporter) PrintSafetyLegend() {
-	fmt.Println(warningStyle.Render("\n🔐 Safety Levels:\n"))
-
-	safeBox := successStyle.Render("Safe")
-	moderateBox := warningStyle.Render("Mod")
-	dangerBox := errorStyle.Render("Risk")
+	fmt.Fprintln(r.out, r.style(warningStyle).Render("\n🔐 "+r.msg("legend.title")+"\n"))
+
+	levels := []struct {
+		label, description string
+		style              lipgloss.Style
+	}{
+		{r.msg("safety.safe"), r.msg("legend.safe"), successStyle},
+		{r.msg("safety.moderate"), r.msg("legend.moderate"), warningStyle},
+		{r.msg("safety.dangerous"), r.msg("legend.dangerous"), errorStyle},
+	}
 
-	fmt.Printf("  %s - No risk, easily rebuilt (caches, logs)\n", safeBox)
-	fmt.Printf("  %s  - Rebuild needed (dependencies, build outputs)\n", moderateBox)
-	fmt.Printf("  %s - Potential data loss (backups, databases)\n", dangerBox)
-	fmt.Println()
+	// Align the descriptions on the longest label
+	width := 0
+	for _, level := range levels {
+		width = max(width, lipgloss.Width(level.label))
+	}
+	for _, level := range levels {
+		padding := strings.Repeat(" ", width-lipgloss.Width(level.label))
+		fmt.Fprintf(r.out, "  %s%s - %s\n", r.style(level.style).Render(level.label), padding, level.description)
+	}
+	fmt.Fprintln(r.out)
 }
 
 // Helper functions
 
+// sortedDomains returns the well-known domains first, in their usual order,
+// followed by any other cleaner names sorted alphabetically
+func sortedDomains(targetsByDomain map[string][]cleaner.CleanTarget) []string {
+	domains := []string{"Frontend", "Backend", "Mobile", "DevOps", "Data/ML", "System"}
+
+	known := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		known[domain] = true
+	}
+
+	others := []string{}
+	for domain := range targetsByDomain {
+		if !known[domain] {
+			others = append(others, domain)
+		}
+	}
+	sort.Strings(others)
+
+	return append(domains, others...)
+}
+
 func getImpactString(size int64) string {
 	const (
 		low    = 500 * 1024 * 1024       // 500 MB
@@ -402,12 +1515,10 @@ func getImpactString(size int64) string {
 	}
 }
 
-func getActionVerb(dryRun bool) string {
-	if dryRun {
-		return "would be freed"
+// formatSignedBytes formats a size change with its sign
+func formatSignedBytes(delta int64) string {
+	if delta < 0 {
+		return "-" + utils.FormatBytes(-delta)
 	}
-	return "freed"
+	return "+" + utils.FormatBytes(delta)
 }
-
-// Ensure Reporter doesn't use os.Stdout directly for tests
-var _ = os.Stdout
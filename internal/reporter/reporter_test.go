@@ -3,28 +3,32 @@ package reporter
 import (
 	"bytes"
 	"errors"
-	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/0SansNom/epurer/internal/cleaner"
 	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/detector"
+	"github.com/0SansNom/epurer/internal/diagnostics"
+	"github.com/0SansNom/epurer/internal/disk"
+	"github.com/0SansNom/epurer/internal/history"
+	"github.com/0SansNom/epurer/internal/i18n"
+	"github.com/0SansNom/epurer/internal/quarantine"
+	"github.com/0SansNom/epurer/internal/scanner"
+	"github.com/0SansNom/epurer/internal/theme"
 )
 
-// captureOutput captures stdout during function execution
-func captureOutput(f func()) string {
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+// captureOutput returns what the reporter prints during function execution
+func captureOutput(r *Reporter, f func()) string {
+	var buf bytes.Buffer
+	r.SetOutput(&buf)
 
 	f()
 
-	w.Close()
-	os.Stdout = old
-
-	var buf bytes.Buffer
-	io.Copy(&buf, r)
 	return buf.String()
 }
 
@@ -52,10 +56,121 @@ func TestNewReporter_Verbose(t *testing.T) {
 	}
 }
 
+func TestSetOutput_PlainText(t *testing.T) {
+	r := NewReporter(false)
+	output := captureOutput(r, func() {
+		r.PrintHeader()
+		r.PrintSuccess("done")
+		r.PrintSafetyLegend()
+	})
+
+	if !strings.Contains(output, "done") {
+		t.Errorf("Expected output in the writer, got %q", output)
+	}
+	if strings.Contains(output, "\x1b[") {
+		t.Errorf("Output to a non-terminal should not be styled, got %q", output)
+	}
+}
+
 // =============================================================================
 // Helper Function Tests
 // =============================================================================
 
+func TestSetTheme_ASCII(t *testing.T) {
+	r := NewReporter(false)
+	r.SetTheme(theme.ASCII)
+
+	output := captureOutput(r, func() {
+		r.PrintHeader()
+		r.PrintSuccess("Done")
+	})
+
+	for _, line := range strings.Split(output, "\n") {
+		for _, c := range strings.ReplaceAll(line, "É", "E") {
+			if c > 127 {
+				t.Fatalf("Expected ASCII output, got %q", line)
+			}
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 3 || utf8.RuneCountInString(lines[0]) != utf8.RuneCountInString(lines[1]) {
+		t.Errorf("Expected the header box to stay aligned, got:\n%s", output)
+	}
+}
+
+func TestPrintTools(t *testing.T) {
+	r := NewReporter(false)
+
+	output := captureOutput(r, func() {
+		r.PrintTools(detector.DetectionResult{
+			Frontend: []detector.Tool{{Name: "npm", Version: "10.2.4", Path: "/usr/local/bin/npm", Footprint: 3e9, Reclaimable: 2e9}},
+			Backend:  []detector.Tool{{Name: "go", Path: "/usr/local/go/bin/go", Reclaimable: 5e8}},
+		})
+	})
+
+	for _, want := range []string{"Frontend", "Backend", "RECLAIMABLE NOW", "10.2.4", "3.0 GB", "/usr/local/go/bin/go", "Reclaimable now from tool caches: 2.5 GB"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Mobile") {
+		t.Error("Categories without tools should not be listed")
+	}
+
+	output = captureOutput(r, func() { r.PrintTools(detector.DetectionResult{}) })
+	if !strings.Contains(output, "No development tools detected") {
+		t.Errorf("Expected no tools to be reported, got:\n%s", output)
+	}
+}
+
+func TestPrintVerification(t *testing.T) {
+	r := NewReporter(false)
+
+	output := captureOutput(r, func() {
+		r.PrintVerification([]cleaner.Verification{
+			{Result: cleaner.CleanResult{Target: cleaner.CleanTarget{Path: "/cache/gone"}, Success: true}, Outcome: cleaner.Freed},
+			{Result: cleaner.CleanResult{Target: cleaner.CleanTarget{Path: "/cache/partial"}, BytesFreed: 3000}, Outcome: cleaner.Partial, Remaining: 1000},
+			{Result: cleaner.CleanResult{Target: cleaner.CleanTarget{Path: "/cache/stuck"}}, Outcome: cleaner.Failed, Remaining: 2000},
+			{Result: cleaner.CleanResult{Target: cleaner.CleanTarget{Path: "dns:cache"}, Error: errors.New("exit status 1")}, Outcome: cleaner.Failed},
+		})
+	})
+
+	for _, want := range []string{
+		"1 freed as expected, 1 partially freed, 2 failed",
+		"/cache/partial: 3.0 kB freed, 1.0 kB left",
+		"/cache/stuck: still there (2.0 kB)",
+		"dns:cache: exit status 1",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "/cache/gone") {
+		t.Error("Targets freed as expected should not be listed")
+	}
+}
+
+func TestPrintSmartSelection(t *testing.T) {
+	r := NewReporter(false)
+	skipped := []cleaner.CleanTarget{{Path: "/logs", SizeBytes: 2000}}
+	chronic := []cleaner.CleanTarget{{Path: "/cache/npm", SizeBytes: 4e9}}
+	regrowth := map[string]history.Regrowth{"/cache/npm": {Cleans: 4, BytesPerDay: 250e6}}
+
+	output := captureOutput(r, func() {
+		r.PrintSmartSelection(skipped, chronic, regrowth, 7)
+	})
+
+	if !strings.Contains(output, "Skipping 1 targets (2.0 kB) cleaned in the last 7 days") {
+		t.Errorf("Expected the skipped targets summed up:\n%s", output)
+	}
+	if strings.Contains(output, "/logs") {
+		t.Error("Skipped targets should only be listed in verbose mode")
+	}
+	if !strings.Contains(output, "/cache/npm") || !strings.Contains(output, "250 MB a day over 4 cleans") {
+		t.Errorf("Expected the chronic regrowers with their pace:\n%s", output)
+	}
+}
+
 func TestGetImpactString(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -83,26 +198,6 @@ func TestGetImpactString(t *testing.T) {
 	}
 }
 
-func TestGetActionVerb(t *testing.T) {
-	tests := []struct {
-		name     string
-		dryRun   bool
-		expected string
-	}{
-		{"Dry run", true, "would be freed"},
-		{"Actual run", false, "freed"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getActionVerb(tt.dryRun)
-			if result != tt.expected {
-				t.Errorf("getActionVerb(%v) = %q, want %q", tt.dryRun, result, tt.expected)
-			}
-		})
-	}
-}
-
 // =============================================================================
 // PrintHeader Tests
 // =============================================================================
@@ -110,7 +205,7 @@ func TestGetActionVerb(t *testing.T) {
 func TestPrintHeader(t *testing.T) {
 	r := NewReporter(false)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintHeader()
 	})
 
@@ -139,7 +234,7 @@ func TestPrintDetection(t *testing.T) {
 		"system":   true,
 	}
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintDetection(detected)
 	})
 
@@ -163,7 +258,7 @@ func TestPrintDetection_Verbose(t *testing.T) {
 		"backend":  false,
 	}
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintDetection(detected)
 	})
 
@@ -176,7 +271,7 @@ func TestPrintDetection_Verbose(t *testing.T) {
 func TestPrintDetection_Empty(t *testing.T) {
 	r := NewReporter(false)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintDetection(map[string]bool{})
 	})
 
@@ -203,7 +298,7 @@ func TestPrintEstimation(t *testing.T) {
 		},
 	}
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintEstimation(targetsByDomain)
 	})
 
@@ -222,7 +317,7 @@ func TestPrintEstimation(t *testing.T) {
 func TestPrintEstimation_Empty(t *testing.T) {
 	r := NewReporter(false)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintEstimation(map[string][]cleaner.CleanTarget{})
 	})
 
@@ -232,6 +327,61 @@ func TestPrintEstimation_Empty(t *testing.T) {
 	}
 }
 
+func TestPrintEstimation_OtherCleaners(t *testing.T) {
+	r := NewReporter(false)
+
+	targetsByDomain := map[string][]cleaner.CleanTarget{
+		"Frontend": {
+			{Path: "/path/1", Description: "npm cache", SizeBytes: 1024, Safety: config.Safe},
+		},
+		"Version Managers": {
+			{Path: "/path/2", Description: "rbenv ruby 2.7.8", SizeBytes: 2048, Safety: config.Moderate},
+		},
+		"Trash": {
+			{Path: "/path/3", Description: "Trash", SizeBytes: 4096, Safety: config.Safe},
+		},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintEstimation(targetsByDomain)
+	})
+
+	for _, name := range []string{"Frontend", "Version Managers", "Trash"} {
+		if !strings.Contains(output, name) {
+			t.Errorf("Output should contain %s", name)
+		}
+	}
+	if strings.Index(output, "Frontend") > strings.Index(output, "Trash") {
+		t.Error("Known domains should be listed before other cleaners")
+	}
+}
+
+func TestPrintEstimation_PartialScan(t *testing.T) {
+	r := NewReporter(false)
+	r.MarkPartial("Backend")
+	r.MarkPartial("DevOps")
+
+	targetsByDomain := map[string][]cleaner.CleanTarget{
+		"Backend": {
+			{Path: "/path/1", Description: "pip cache", SizeBytes: 1024, Safety: config.Safe},
+		},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintEstimation(targetsByDomain)
+	})
+
+	if !strings.Contains(output, "Backend*") {
+		t.Error("Partial domain row should be marked")
+	}
+	if !strings.Contains(output, "Backend: partial scan (timed out)") {
+		t.Error("Output should flag the partial Backend scan")
+	}
+	if !strings.Contains(output, "DevOps: partial scan (timed out)") {
+		t.Error("Timed out cleaners without results should still be flagged")
+	}
+}
+
 func TestPrintEstimation_AllSafetyLevels(t *testing.T) {
 	r := NewReporter(false)
 
@@ -243,7 +393,7 @@ func TestPrintEstimation_AllSafetyLevels(t *testing.T) {
 		},
 	}
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintEstimation(targetsByDomain)
 	})
 
@@ -264,7 +414,7 @@ func TestPrintTargetDetails_NotVerbose(t *testing.T) {
 		{Path: "/path/1", Description: "Test", SizeBytes: 1024, Safety: config.Safe},
 	}
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintTargetDetails(targets)
 	})
 
@@ -282,7 +432,7 @@ func TestPrintTargetDetails_Verbose(t *testing.T) {
 		{Path: "/path/to/modules", Description: "node_modules", SizeBytes: 500 * 1024 * 1024, Safety: config.Moderate},
 	}
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintTargetDetails(targets)
 	})
 
@@ -295,10 +445,49 @@ func TestPrintTargetDetails_Verbose(t *testing.T) {
 	}
 }
 
+func TestPrintTargetDetails_RebuildCost(t *testing.T) {
+	r := NewReporter(true)
+
+	targets := []cleaner.CleanTarget{
+		{Path: "/app/node_modules", Category: "node_modules", Description: "node_modules", SizeBytes: 300_000_000, Safety: config.Moderate},
+		{Path: "/cache/npm", Category: "npm_cache", Description: "npm cache", SizeBytes: 1_000_000, Safety: config.Safe},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintTargetDetails(targets)
+	})
+
+	if !strings.Contains(output, "Rebuild: ~105 MB download, ~20.0s (npm install)") {
+		t.Errorf("Expected the rebuild cost of node_modules, got:\n%s", output)
+	}
+	if strings.Count(output, "Rebuild") != 1 {
+		t.Error("Caches need no rebuild cost")
+	}
+}
+
+func TestPrintTargetDetails_Action(t *testing.T) {
+	r := NewReporter(true)
+
+	targets := []cleaner.CleanTarget{
+		{Path: "system:dns_cache", Category: "dns_cache", Description: "Flush the DNS cache", Safety: config.Safe, Action: cleaner.ActionRun},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintTargetDetails(targets)
+	})
+
+	if !strings.Contains(output, "[dns_cache] - action") {
+		t.Errorf("Expected the action instead of a size, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Runs: dscacheutil -flushcache && killall -HUP mDNSResponder") {
+		t.Errorf("Expected the commands of the action, got:\n%s", output)
+	}
+}
+
 func TestPrintTargetDetails_Empty(t *testing.T) {
 	r := NewReporter(true)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintTargetDetails([]cleaner.CleanTarget{})
 	})
 
@@ -313,7 +502,7 @@ func TestPrintTargetDetails_Empty(t *testing.T) {
 func TestPrintProgress(t *testing.T) {
 	r := NewReporter(false)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintProgress(1, 10, "Processing")
 	})
 
@@ -329,7 +518,7 @@ func TestPrintProgress(t *testing.T) {
 func TestPrintProgress_Complete(t *testing.T) {
 	r := NewReporter(false)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintProgress(10, 10, "Done")
 	})
 
@@ -339,6 +528,75 @@ func TestPrintProgress_Complete(t *testing.T) {
 	}
 }
 
+func TestPrintProgressETA(t *testing.T) {
+	r := NewReporter(false)
+
+	output := captureOutput(r, func() {
+		r.PrintProgressETA(3, 10, "Deleting DerivedData", 95*time.Second)
+		r.PrintProgressETA(4, 10, "Cleaning", 0)
+	})
+
+	if !strings.Contains(output, "about 1.6m left") {
+		t.Errorf("Output should contain the time left, got %q", output)
+	}
+
+	// The shorter line hides the end of the one it replaces
+	lines := strings.Split(strings.TrimPrefix(output, "\r"), "\r")
+	if len(lines) != 2 || len(lines[1]) < len(lines[0]) {
+		t.Errorf("Expected the second line padded to the first, got %q", output)
+	}
+}
+
+func TestPrintExplanations(t *testing.T) {
+	r := NewReporter(false)
+
+	output := captureOutput(r, func() {
+		r.PrintExplanations(cleaner.ExplainCategories([]cleaner.CleanTarget{
+			{Path: "/a/node_modules", Category: "node_modules", Description: "node_modules dependencies", SizeBytes: 100},
+		}))
+	})
+
+	for _, expected := range []string{"What is this?", "node_modules dependencies", "What brings it back: npm", "If deleted:"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain %q, got %q", expected, output)
+		}
+	}
+}
+
+func TestPrintConsequences(t *testing.T) {
+	r := NewReporter(false)
+
+	output := captureOutput(r, func() {
+		r.PrintConsequences(cleaner.Consequences([]cleaner.CleanTarget{
+			{Path: "/a/node_modules", Category: "node_modules", SizeBytes: 100},
+		}))
+	})
+	if !strings.Contains(output, "you will need to:") || !strings.Contains(output, "reinstall node_modules in 1 projects") {
+		t.Errorf("Output should list what is left to do, got %q", output)
+	}
+
+	// Nothing to do, nothing printed
+	output = captureOutput(r, func() {
+		r.PrintConsequences(nil)
+	})
+	if output != "" {
+		t.Errorf("Expected no output, got %q", output)
+	}
+}
+
+func TestEndProgress(t *testing.T) {
+	r := NewReporter(false)
+
+	output := captureOutput(r, func() {
+		r.PrintProgress(3, 10, "Cleaning")
+		r.EndProgress()
+	})
+
+	if !strings.HasSuffix(output, "\n") {
+		t.Error("EndProgress should end the progress line")
+	}
+}
+
 // =============================================================================
 // PrintCleanResults Tests
 // =============================================================================
@@ -351,7 +609,7 @@ func TestPrintCleanResults_DryRun(t *testing.T) {
 		{Target: cleaner.CleanTarget{Path: "/path/2"}, Success: true, BytesFreed: 2048},
 	}
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintCleanResults(results, true)
 	})
 
@@ -364,6 +622,56 @@ func TestPrintCleanResults_DryRun(t *testing.T) {
 	}
 }
 
+func TestPrintCleanResults_DryRunByProject(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	app := filepath.Join(tmpDir, "my-app")
+	os.MkdirAll(app, 0755)
+	os.WriteFile(filepath.Join(app, "package.json"), []byte("{}"), 0644)
+
+	api := filepath.Join(tmpDir, "api")
+	os.MkdirAll(api, 0755)
+	os.WriteFile(filepath.Join(api, "go.mod"), []byte("module api"), 0644)
+
+	results := []cleaner.CleanResult{
+		{Target: cleaner.CleanTarget{Path: filepath.Join(app, "dist")}, Success: true, BytesFreed: 200_000_000},
+		{Target: cleaner.CleanTarget{Path: filepath.Join(app, "node_modules")}, Success: true, BytesFreed: 1_300_000_000},
+		{Target: cleaner.CleanTarget{Path: filepath.Join(api, "vendor")}, Success: true, BytesFreed: 50_000_000},
+		{Target: cleaner.CleanTarget{Path: "docker:buildcache"}, Success: true, BytesFreed: 10_000_000},
+	}
+
+	groups := groupByProject(results)
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 projects, got %d", len(groups))
+	}
+	if groups[0].root != app || groups[0].total != 1_500_000_000 {
+		t.Errorf("Expected my-app first with 1.5 GB, got %s with %d", groups[0].root, groups[0].total)
+	}
+	if groups[0].items[0].name != "node_modules" || groups[0].items[1].name != "dist" {
+		t.Errorf("Expected items sorted by size, got %+v", groups[0].items)
+	}
+
+	r := NewReporter(false)
+	output := captureOutput(r, func() {
+		r.PrintCleanResults(results, true)
+	})
+
+	if !strings.Contains(output, "By Project") || !strings.Contains(output, "my-app") {
+		t.Error("Dry run output should group targets by project")
+	}
+	if !strings.Contains(output, "1.5 GB") {
+		t.Error("Dry run output should show the project subtotal")
+	}
+
+	output = captureOutput(r, func() {
+		r.PrintCleanResults(results, false)
+	})
+
+	if strings.Contains(output, "By Project") {
+		t.Error("Project breakdown should only be shown for dry runs")
+	}
+}
+
 func TestPrintCleanResults_Actual(t *testing.T) {
 	r := NewReporter(false)
 
@@ -372,7 +680,7 @@ func TestPrintCleanResults_Actual(t *testing.T) {
 		{Target: cleaner.CleanTarget{Path: "/path/2"}, Success: true, BytesFreed: 2048 * 1024},
 	}
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintCleanResults(results, false)
 	})
 
@@ -385,6 +693,55 @@ func TestPrintCleanResults_Actual(t *testing.T) {
 	}
 }
 
+func TestPrintCleanResults_EstimateDelta(t *testing.T) {
+	r := NewReporter(false)
+
+	results := []cleaner.CleanResult{
+		{Target: cleaner.CleanTarget{Path: "/path/1", SizeBytes: 2_000_000}, Success: true, BytesFreed: 1_500_000},
+		{Target: cleaner.CleanTarget{Path: "/path/2", SizeBytes: 1_000_000}, Success: true, BytesFreed: 1_000_000},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintCleanResults(results, false)
+	})
+
+	if !strings.Contains(output, "Estimated: 3.0 MB") || !strings.Contains(output, "-500 kB actual") {
+		t.Errorf("Output should compare the estimate with the space freed, got:\n%s", output)
+	}
+
+	// Nothing to compare when the estimate was right, nor in dry runs
+	results[0].BytesFreed = 2_000_000
+	for _, dryRun := range []bool{false, true} {
+		output = captureOutput(r, func() {
+			r.PrintCleanResults(results, dryRun)
+		})
+		if strings.Contains(output, "Estimated") {
+			t.Errorf("Unexpected estimate line (dry run %v):\n%s", dryRun, output)
+		}
+	}
+}
+
+func TestPrintCleanResults_PartialFailure(t *testing.T) {
+	r := NewReporter(true)
+
+	results := []cleaner.CleanResult{
+		{Target: cleaner.CleanTarget{Path: "/path/done"}, Success: true, BytesFreed: 1024, Files: 12},
+		{Target: cleaner.CleanTarget{Path: "/path/partial"}, Success: false, BytesFreed: 2_000_000, Files: 30,
+			Error: errors.New("2 entries could not be removed")},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintCleanResults(results, false)
+	})
+
+	if !strings.Contains(output, "Files deleted: 42") {
+		t.Errorf("Output should count the files deleted, got:\n%s", output)
+	}
+	if !strings.Contains(output, "30 files deleted, 2.0 MB freed") {
+		t.Errorf("Output should show what the partial removal freed, got:\n%s", output)
+	}
+}
+
 func TestPrintCleanResults_WithFailures(t *testing.T) {
 	r := NewReporter(false)
 
@@ -393,7 +750,7 @@ func TestPrintCleanResults_WithFailures(t *testing.T) {
 		{Target: cleaner.CleanTarget{Path: "/path/2"}, Success: false, Error: errors.New("permission denied")},
 	}
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintCleanResults(results, false)
 	})
 
@@ -411,7 +768,7 @@ func TestPrintCleanResults_WithFailures_Verbose(t *testing.T) {
 		{Target: cleaner.CleanTarget{Path: "/path/failed"}, Success: false, Error: errors.New("permission denied")},
 	}
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintCleanResults(results, false)
 	})
 
@@ -427,7 +784,7 @@ func TestPrintCleanResults_WithFailures_Verbose(t *testing.T) {
 func TestPrintCleanResults_Empty(t *testing.T) {
 	r := NewReporter(false)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintCleanResults([]cleaner.CleanResult{}, false)
 	})
 
@@ -437,6 +794,249 @@ func TestPrintCleanResults_Empty(t *testing.T) {
 	}
 }
 
+func TestPrintCleanResults_DryRunCommands(t *testing.T) {
+	r := NewReporter(false)
+
+	results := []cleaner.CleanResult{
+		{
+			Target:     cleaner.CleanTarget{Path: "docker:buildcache", SizeBytes: 1_000_000},
+			Success:    true,
+			BytesFreed: 1_000_000,
+			Commands:   []string{"docker builder prune -f"},
+		},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintCleanResults(results, true)
+	})
+	if !strings.Contains(output, "$ docker builder prune -f") {
+		t.Errorf("Dry run should list the commands that would run, got:\n%s", output)
+	}
+
+	output = captureOutput(r, func() {
+		r.PrintCleanResults(results, false)
+	})
+	if strings.Contains(output, "would run") {
+		t.Errorf("Real runs should not list commands, got:\n%s", output)
+	}
+}
+
+func TestPrintCleanResults_Actions(t *testing.T) {
+	r := NewReporter(false)
+
+	results := []cleaner.CleanResult{
+		{Target: cleaner.CleanTarget{Path: "/cache", SizeBytes: 1000}, Success: true, BytesFreed: 1000},
+		{Target: cleaner.CleanTarget{Path: "system:dns_cache", Category: "dns_cache", Action: cleaner.ActionRun}, Success: true},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintCleanResults(results, false)
+	})
+	if !strings.Contains(output, "Actions run: 1") || !strings.Contains(output, "Items freed: 1") {
+		t.Errorf("Expected actions to be counted apart from items, got:\n%s", output)
+	}
+}
+
+// =============================================================================
+// PrintScanProfile Tests
+// =============================================================================
+
+func TestPrintScanProfile(t *testing.T) {
+	r := NewReporter(false)
+
+	timings := []CleanerTiming{
+		{Name: "Backend", Duration: 2 * time.Second, Targets: 3, Files: 1200, Dirs: 300},
+		{Name: "Frontend", Duration: 5 * time.Second, Targets: 14, Files: 5000, Dirs: 900, TimedOut: true},
+	}
+	dirs := []scanner.DirStats{
+		{Path: "/home/u/Projects", Root: true, Duration: 7 * time.Second, Walks: 15, Files: 6200, Dirs: 1200},
+		{Path: "/home/u/Projects/monorepo", Duration: 6 * time.Second, Walks: 15, Files: 6000},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintScanProfile(timings, dirs)
+	})
+
+	for _, expected := range []string{"Frontend*", "5.0s", "5,000", "Search Directories", "/home/u/Projects", "Slowest Directories", "monorepo", "walked 15 times"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	if strings.Index(output, "Frontend") > strings.Index(output, "Backend") {
+		t.Error("Slowest cleaner should be listed first")
+	}
+}
+
+// =============================================================================
+// PrintSnapshots Tests
+// =============================================================================
+
+func TestPrintSnapshots(t *testing.T) {
+	r := NewReporter(false)
+
+	snapshots := []disk.Snapshot{
+		{Name: "com.apple.TimeMachine.2025-01-15-123456.local", Date: time.Date(2025, 1, 15, 12, 34, 56, 0, time.Local)},
+		{Name: "com.apple.TimeMachine.bogus.local"},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintSnapshots(snapshots, 5_000_000_000)
+	})
+
+	for _, expected := range []string{"2025-01-15 12:34", "unknown date", "2 snapshots", "5.0 GB"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestPrintSnapshots_Empty(t *testing.T) {
+	r := NewReporter(false)
+
+	output := captureOutput(r, func() {
+		r.PrintSnapshots(nil, 0)
+	})
+
+	if !strings.Contains(output, "No local snapshots") {
+		t.Errorf("Output should say there are no snapshots, got:\n%s", output)
+	}
+}
+
+func TestPrintQuarantine(t *testing.T) {
+	r := NewReporter(false)
+
+	entries := []quarantine.Entry{
+		{ID: "a", OriginalPath: "/p/node_modules", SizeBytes: 2_000_000_000, QuarantinedAt: time.Now()},
+		{ID: "b", OriginalPath: "/p/target", SizeBytes: 1_000_000_000, QuarantinedAt: time.Now()},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintQuarantine(entries)
+	})
+
+	for _, expected := range []string{"/p/node_modules", "/p/target", "2 entries", "3.0 GB"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	output = captureOutput(r, func() {
+		r.PrintQuarantine(nil)
+	})
+	if !strings.Contains(output, "empty") {
+		t.Errorf("Output should say the quarantine is empty, got:\n%s", output)
+	}
+}
+
+func TestPrintQuarantineStatus(t *testing.T) {
+	r := NewReporter(false)
+
+	status := quarantine.Status{Entries: 3, Bytes: 3_000_000_000, Oldest: time.Date(2025, 1, 15, 12, 34, 0, 0, time.Local)}
+	limits := quarantine.Limits{MaxAge: 14 * 24 * time.Hour, MaxBytes: 2_000_000_000}
+
+	output := captureOutput(r, func() {
+		r.PrintQuarantineStatus("/state/quarantine", status, limits, true)
+	})
+
+	for _, expected := range []string{"/state/quarantine", "3 targets", "3.0 GB", "2025-01-15 12:34", "14 days", "2.0 GB", "exceeded"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+// =============================================================================
+// PrintDiskSummary Tests
+// =============================================================================
+
+func TestPrintInspection(t *testing.T) {
+	r := NewReporter(false)
+
+	inspection := Inspection{
+		Path:      "/work/app/node_modules",
+		SizeBytes: 3_000_000,
+		Ages: []cleaner.AgeBucket{
+			{Label: "< 7d", Size: 1_000_000},
+			{Label: "> 90d", Size: 2_000_000},
+		},
+		Claims: []cleaner.Claim{{
+			Cleaner: "Frontend",
+			Target:  cleaner.CleanTarget{Path: "/work/app/node_modules", Category: "node_modules", Description: "node_modules", SizeBytes: 3_000_000, Safety: config.Moderate},
+			Covers:  true,
+		}},
+		Protected: "/work/app is open in VS Code",
+		History: []history.Record{{
+			StartedAt: time.Date(2025, 1, 10, 9, 0, 0, 0, time.Local),
+			Command:   "clean",
+			Result:    history.Result{Path: "/work/app/node_modules", BytesFreed: 1_000_000, Success: true},
+		}},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintInspection(inspection)
+	})
+	for _, expected := range []string{"3.0 MB", "66.7%", "Claimed by", "Frontend, node_modules, cleaned from --level standard", "Protected: /work/app is open in VS Code", "2025-01-10 09:00", "1.0 MB freed"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	output = captureOutput(r, func() {
+		r.PrintInspection(Inspection{Path: "/elsewhere"})
+	})
+	for _, expected := range []string{"No cleaner claims this path", "Never cleaned before"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestPrintDiskSummary_Before(t *testing.T) {
+	r := NewReporter(false)
+
+	output := captureOutput(r, func() {
+		r.PrintDiskSummary(disk.Usage{Total: 500_000_000_000, Used: 400_000_000_000, Free: 90_000_000_000, Purgeable: 10_000_000_000}, nil, 0)
+	})
+
+	for _, expected := range []string{"Disk Before Cleaning", "500 GB", "400 GB", "90 GB", "10 GB", "100 GB"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "After") {
+		t.Error("Output should not have an after column")
+	}
+}
+
+func TestPrintDiskSummary_After(t *testing.T) {
+	r := NewReporter(false)
+
+	before := disk.Usage{Total: 500_000_000_000, Used: 400_000_000_000, Free: 90_000_000_000, Purgeable: 10_000_000_000}
+	after := disk.Usage{Total: 500_000_000_000, Used: 395_000_000_000, Free: 92_000_000_000, Purgeable: 13_000_000_000}
+
+	output := captureOutput(r, func() {
+		r.PrintDiskSummary(before, &after, 5_000_000_000)
+	})
+
+	for _, expected := range []string{"Before", "After", "395 GB", "Cleaning freed 5.0 GB", "+5.0 GB"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "still held") {
+		t.Error("Output should not explain a difference when all freed space is available")
+	}
+
+	after.Purgeable = 10_000_000_000
+	output = captureOutput(r, func() {
+		r.PrintDiskSummary(before, &after, 5_000_000_000)
+	})
+	if !strings.Contains(output, "still held by local snapshots") {
+		t.Errorf("Output should explain the missing space, got:\n%s", output)
+	}
+}
+
 // =============================================================================
 // Print Message Tests
 // =============================================================================
@@ -444,7 +1044,7 @@ func TestPrintCleanResults_Empty(t *testing.T) {
 func TestPrintWarning(t *testing.T) {
 	r := NewReporter(false)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintWarning("Test warning message")
 	})
 
@@ -456,7 +1056,7 @@ func TestPrintWarning(t *testing.T) {
 func TestPrintError(t *testing.T) {
 	r := NewReporter(false)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintError("Test error message")
 	})
 
@@ -468,7 +1068,7 @@ func TestPrintError(t *testing.T) {
 func TestPrintSuccess(t *testing.T) {
 	r := NewReporter(false)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintSuccess("Test success message")
 	})
 
@@ -480,7 +1080,7 @@ func TestPrintSuccess(t *testing.T) {
 func TestPrintInfo(t *testing.T) {
 	r := NewReporter(false)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintInfo("Test info message")
 	})
 
@@ -496,7 +1096,7 @@ func TestPrintInfo(t *testing.T) {
 func TestPrintSafetyLegend(t *testing.T) {
 	r := NewReporter(false)
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintSafetyLegend()
 	})
 
@@ -515,6 +1115,293 @@ func TestPrintSafetyLegend(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// AskTarget Tests
+// =============================================================================
+
+func TestParseTargetAnswer(t *testing.T) {
+	tests := []struct {
+		response string
+		expected TargetAnswer
+	}{
+		{"y", AnswerYes},
+		{"yes", AnswerYes},
+		{"a", AnswerAll},
+		{"all", AnswerAll},
+		{"q", AnswerQuit},
+		{"quit", AnswerQuit},
+		{"o", AnswerYes},
+		{"oui", AnswerYes},
+		{"t", AnswerAll},
+		{"tout", AnswerAll},
+		{"quitter", AnswerQuit},
+		{"n", AnswerNo},
+		{"", AnswerNo},
+		{"maybe", AnswerNo},
+	}
+
+	for _, tt := range tests {
+		if got := parseTargetAnswer(tt.response); got != tt.expected {
+			t.Errorf("parseTargetAnswer(%q) = %v, expected %v", tt.response, got, tt.expected)
+		}
+	}
+}
+
+// =============================================================================
+// PrintAgeHistograms Tests
+// =============================================================================
+
+func TestPrintAgeHistograms(t *testing.T) {
+	r := NewReporter(false)
+
+	ages := []cleaner.TargetAge{
+		{
+			Target: cleaner.CleanTarget{Path: "/cache/pip", Description: "pip cache"},
+			Buckets: []cleaner.AgeBucket{
+				{Label: "< 7d", Size: 100_000_000},
+				{Label: "7-30d", Size: 100_000_000},
+				{Label: "30-90d", MinAge: 30 * 24 * time.Hour, Size: 200_000_000},
+				{Label: "> 90d", MinAge: 90 * 24 * time.Hour, Size: 600_000_000},
+			},
+		},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintAgeHistograms(ages)
+	})
+
+	if !strings.Contains(output, "pip cache") || !strings.Contains(output, "1.0 GB") {
+		t.Errorf("Expected the target and its total, got:\n%s", output)
+	}
+	if !strings.Contains(output, "████████████░░░░░░░░") || !strings.Contains(output, "60.0%") {
+		t.Errorf("Expected a bar for the > 90d bucket, got:\n%s", output)
+	}
+	if !strings.Contains(output, "30 days or more: 800 MB (80.0%)") {
+		t.Errorf("Expected the space of entries older than 30 days, got:\n%s", output)
+	}
+}
+
+func TestPrintAgeHistograms_Empty(t *testing.T) {
+	r := NewReporter(false)
+
+	output := captureOutput(r, func() {
+		r.PrintAgeHistograms(nil)
+	})
+
+	if !strings.Contains(output, "No cache directory") {
+		t.Error("Expected a message when nothing was analyzed")
+	}
+}
+
+// =============================================================================
+// Language Tests
+// =============================================================================
+
+func TestPrintCleanResults_French(t *testing.T) {
+	r := NewReporter(false)
+	r.SetLang(i18n.French)
+
+	results := []cleaner.CleanResult{
+		{Target: cleaner.CleanTarget{Path: "/a", SizeBytes: 1_000_000}, Success: true, BytesFreed: 1_000_000},
+		{Target: cleaner.CleanTarget{Path: "/b"}, Success: false, Error: errors.New("permission denied")},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintCleanResults(results, true)
+	})
+
+	for _, want := range []string{"Simulation terminée", "Espace libérable: 1.0 MB", "Éléments à nettoyer: 1", "Échecs: 1"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in French output, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Space") {
+		t.Error("French output should not contain English labels")
+	}
+}
+
+func TestPrintSafetyLegend_French(t *testing.T) {
+	r := NewReporter(false)
+	r.SetLang(i18n.French)
+
+	output := captureOutput(r, func() {
+		r.PrintSafetyLegend()
+	})
+
+	if !strings.Contains(output, "Niveaux de sécurité") {
+		t.Error("Output should contain the French legend title")
+	}
+	// Descriptions are aligned on the longest label
+	if !strings.Contains(output, "  Sûr    - Aucun risque") || !strings.Contains(output, "  Risque - Perte de données") {
+		t.Errorf("Labels should be padded to the same width, got:\n%s", output)
+	}
+}
+
+// =============================================================================
+// PrintTerraformDuplicates Tests
+// =============================================================================
+
+func TestPrintTerraformDuplicates(t *testing.T) {
+	r := NewReporter(false)
+
+	duplicates := []cleaner.TerraformProviderCopies{
+		{
+			Source:   "registry.terraform.io/hashicorp/aws",
+			Version:  "5.31.0",
+			Platform: "darwin_arm64",
+			Size:     400_000_000,
+			Paths:    []string{"/a/.terraform", "/b/.terraform", "/c/.terraform"},
+		},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintTerraformDuplicates(duplicates, "")
+	})
+
+	if !strings.Contains(output, "hashicorp/aws") || !strings.Contains(output, "3 copies") {
+		t.Errorf("Output should list the duplicated provider, got:\n%s", output)
+	}
+	if !strings.Contains(output, "800 MB") {
+		t.Errorf("Output should show the savings of two copies, got:\n%s", output)
+	}
+
+	output = captureOutput(r, func() {
+		r.PrintTerraformDuplicates(nil, "")
+	})
+	if !strings.Contains(output, "No provider") {
+		t.Errorf("Output should say there are no duplicates, got:\n%s", output)
+	}
+}
+
+// =============================================================================
+// PrintNodeDuplicates Tests
+// =============================================================================
+
+func TestPrintNodeDuplicates(t *testing.T) {
+	r := NewReporter(false)
+
+	duplicates := []cleaner.NodePackageCopies{
+		{Name: "typescript", Version: "5.4.2", Size: 20_000_000, Paths: []string{"/a/node_modules/typescript", "/b/node_modules/typescript", "/c/node_modules/typescript"}},
+		{Name: "@babel/core", Version: "7.24.0", Size: 1_000_000, Paths: []string{"/a/node_modules/@babel/core", "/b/node_modules/@babel/core"}},
+	}
+	workspaces := []cleaner.NodeWorkspaceCandidate{{Dir: "/code/acme", Projects: 2, Savings: 21_000_000}}
+
+	output := captureOutput(r, func() {
+		r.PrintNodeDuplicates(duplicates, workspaces)
+	})
+
+	if !strings.Contains(output, "typescript@5.4.2") || !strings.Contains(output, "3 copies") {
+		t.Errorf("Output should list the duplicated packages, got:\n%s", output)
+	}
+	if !strings.Contains(output, "save about 41 MB") {
+		t.Errorf("Output should show the total savings, got:\n%s", output)
+	}
+	if !strings.Contains(output, "pnpm") || !strings.Contains(output, "/code/acme") {
+		t.Errorf("Output should recommend pnpm and a workspace, got:\n%s", output)
+	}
+
+	output = captureOutput(r, func() {
+		r.PrintNodeDuplicates(nil, nil)
+	})
+	if !strings.Contains(output, "No package release") {
+		t.Errorf("Output should say there are no duplicates, got:\n%s", output)
+	}
+}
+
+// =============================================================================
+// PrintLargestFiles Tests
+// =============================================================================
+
+func TestPrintLargestFiles(t *testing.T) {
+	r := NewReporter(false)
+
+	files := []scanner.ScanResult{
+		{Path: "/Users/me/Downloads/Xcode_15.xip", Size: 3_000_000_000},
+		{Path: "/Users/me/Projects/app/server.log", Size: 800_000_000},
+		{Path: "/Users/me/Projects/app/data.bin", Size: 600_000_000},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintLargestFiles(files, 500_000_000)
+	})
+
+	if !strings.Contains(output, "archive") || !strings.Contains(output, "log") {
+		t.Errorf("Output should show the kind of known files, got:\n%s", output)
+	}
+	if !strings.Contains(output, "3 files take 4.4 GB") {
+		t.Errorf("Output should show the total, got:\n%s", output)
+	}
+
+	output = captureOutput(r, func() {
+		r.PrintLargestFiles(nil, 500_000_000)
+	})
+	if !strings.Contains(output, "No file of 500 MB or more") {
+		t.Errorf("Output should say nothing was found, got:\n%s", output)
+	}
+}
+
+// =============================================================================
+// PrintDuplicateFiles Tests
+// =============================================================================
+
+func TestPrintDuplicateFiles(t *testing.T) {
+	r := NewReporter(false)
+
+	groups := []cleaner.DuplicateGroup{
+		{Size: 2_000_000_000, Paths: []string{"/p/demo.mov", "/p/backup/demo.mov"}},
+		{Size: 50_000_000, Paths: []string{"/p/a.zip", "/p/b.zip", "/p/c.zip"}},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintDuplicateFiles(groups)
+	})
+
+	if !strings.Contains(output, "/p/backup/demo.mov") || !strings.Contains(output, "3 × 50 MB") {
+		t.Errorf("Output should list each group, got:\n%s", output)
+	}
+	if !strings.Contains(output, "save about 2.1 GB") {
+		t.Errorf("Output should show the total savings, got:\n%s", output)
+	}
+
+	output = captureOutput(r, func() {
+		r.PrintDuplicateFiles(nil)
+	})
+	if !strings.Contains(output, "No identical files") {
+		t.Errorf("Output should say nothing was found, got:\n%s", output)
+	}
+}
+
+// =============================================================================
+// PrintDoctorChecks Tests
+// =============================================================================
+
+func TestPrintDoctorChecks(t *testing.T) {
+	r := NewReporter(false)
+
+	checks := []diagnostics.Check{
+		{Name: "Full Disk Access", Status: diagnostics.Pass, Detail: "Protected folders can be read"},
+		{Name: "Command docker", Status: diagnostics.Warn, Detail: "Not found", Fix: "Install Docker Desktop"},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintDoctorChecks(checks)
+	})
+
+	if !strings.Contains(output, "Full Disk Access  Protected folders") {
+		t.Errorf("Output should align check names, got:\n%s", output)
+	}
+	if !strings.Contains(output, "→ Install Docker Desktop") || !strings.Contains(output, "1 checks need attention") {
+		t.Errorf("Output should show the fix and count problems, got:\n%s", output)
+	}
+
+	output = captureOutput(r, func() {
+		r.PrintDoctorChecks(checks[:1])
+	})
+	if !strings.Contains(output, "Everything looks good") {
+		t.Errorf("Output should say all checks passed, got:\n%s", output)
+	}
+}
+
 // =============================================================================
 // Style Tests (verify styles are initialized)
 // =============================================================================
@@ -554,7 +1441,7 @@ func TestReporter_FullWorkflow(t *testing.T) {
 	r := NewReporter(true)
 
 	// Simulate a full workflow
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		// 1. Print header
 		r.PrintHeader()
 
@@ -603,7 +1490,7 @@ func TestReporter_LargeNumbers(t *testing.T) {
 		},
 	}
 
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintEstimation(targetsByDomain)
 	})
 
@@ -617,7 +1504,7 @@ func TestReporter_SpecialCharacters(t *testing.T) {
 	r := NewReporter(false)
 
 	// Test with special characters in messages
-	output := captureOutput(func() {
+	output := captureOutput(r, func() {
 		r.PrintWarning("Path with spaces: /Users/test user/Documents")
 		r.PrintError("Error: file not found (╯°□°)╯︵ ┻━┻")
 	})
@@ -627,3 +1514,19 @@ func TestReporter_SpecialCharacters(t *testing.T) {
 		t.Error("Output should contain message with spaces")
 	}
 }
+
+func TestPrintTargetDetails_Files(t *testing.T) {
+	r := NewReporter(true)
+
+	targets := []cleaner.CleanTarget{
+		{Path: "/path/to/modules", Description: "node_modules", SizeBytes: 1024, Files: 84212, Safety: config.Moderate},
+	}
+
+	output := captureOutput(r, func() {
+		r.PrintTargetDetails(targets)
+	})
+
+	if !strings.Contains(output, "84,212 files") {
+		t.Errorf("Verbose output should contain the file count, got %q", output)
+	}
+}
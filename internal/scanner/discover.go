@@ -0,0 +1,107 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// DiscoveryMinProjects is how many projects a folder must hold to be
+// suggested as a search directory
+const DiscoveryMinProjects = 3
+
+// DiscoveryMaxDepth is how many levels below a folder of the home directory
+// discovery looks for projects
+const DiscoveryMaxDepth = 3
+
+// Candidate is a folder of the home directory that holds projects
+type Candidate struct {
+	Path     string
+	Projects int // Git repositories and directories with a project marker
+}
+
+// DiscoverSearchDirs samples the folders of the home directory for projects
+// and returns those holding at least DiscoveryMinProjects of them, the
+// richest first. Folders already searched, hidden and excluded ones are
+// skipped, each folder is looked into DiscoveryMaxDepth levels down, and the
+// folders sampled when ctx is done are all that is returned.
+func (s *Scanner) DiscoverSearchDirs(ctx context.Context) []Candidate {
+	entries, err := os.ReadDir(s.homePath)
+	if err != nil {
+		return nil
+	}
+
+	var candidates []Candidate
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		path := filepath.Join(s.homePath, entry.Name())
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || s.isSearched(path) || s.isExcludedPath(path) || s.isExcludedName(entry.Name()) {
+			continue
+		}
+		// A project at the top of the home directory is not a folder of projects
+		if isProject(path) {
+			continue
+		}
+
+		if projects := s.countProjects(ctx, path, 1); projects >= DiscoveryMinProjects {
+			candidates = append(candidates, Candidate{Path: path, Projects: projects})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Projects > candidates[j].Projects
+	})
+	return candidates
+}
+
+// countProjects counts the projects in dir, depth levels below the home
+// directory. Projects are not looked into: their subfolders are modules of
+// the same project.
+func (s *Scanner) countProjects(ctx context.Context, dir string, depth int) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	projects := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || s.isExcludedPath(path) || s.isExcludedName(entry.Name()) {
+			continue
+		}
+
+		if isProject(path) {
+			projects++
+		} else if depth < DiscoveryMaxDepth {
+			projects += s.countProjects(ctx, path, depth+1)
+		}
+	}
+	return projects
+}
+
+// isSearched checks if path is or lies inside a search directory, whatever
+// the case and Unicode normalization of their names
+func (s *Scanner) isSearched(path string) bool {
+	for _, dir := range s.searchDirs {
+		if utils.HasPathPrefix(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// isProject checks if dir is a git repository or contains a project marker
+func isProject(dir string) bool {
+	return utils.PathExists(filepath.Join(dir, ".git")) || utils.IsProjectRoot(dir)
+}
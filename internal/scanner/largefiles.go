@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// SetSearchDirs replaces the directories to search in
+func (s *Scanner) SetSearchDirs(dirs []string) {
+	s.searchDirs = dirs
+}
+
+// FindLargeFiles searches the search directories for regular files of at
+// least minSize bytes. It follows the same rules as FindByPattern: excluded
+// and protected folders, cloud placeholders and entries past the depth limit
+// are skipped.
+func (s *Scanner) FindLargeFiles(ctx context.Context, minSize int64) <-chan ScanResult {
+	results := make(chan ScanResult, 100)
+
+	go func() {
+		defer close(results)
+
+		var wg sync.WaitGroup
+		semaphore := make(chan struct{}, s.workers)
+
+		for _, dir := range s.searchDirs {
+			if ctx.Err() != nil {
+				break
+			}
+
+			wg.Add(1)
+			semaphore <- struct{}{}
+
+			go func(searchDir string) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+
+				s.walkLargeFiles(ctx, searchDir, minSize, results)
+			}(dir)
+		}
+
+		wg.Wait()
+	}()
+
+	return results
+}
+
+// walkLargeFiles walks a directory tree and sends the files of at least
+// minSize bytes to results
+func (s *Scanner) walkLargeFiles(ctx context.Context, searchDir string, minSize int64, results chan<- ScanResult) {
+	stats := s.newWalkStats(searchDir)
+	defer stats.finish()
+
+	filepath.WalkDir(searchDir, func(path string, d fs.DirEntry, err error) error {
+		utils.Throttle()
+		if ctx.Err() != nil {
+			return filepath.SkipAll
+		}
+
+		if err != nil {
+			return nil
+		}
+
+		// Same skipping rules as walkAndMatch
+		if d.IsDir() && (utils.HasKeepMarker(path) || s.isExcludedPath(path)) {
+			return filepath.SkipDir
+		}
+
+		if utils.IsDatalessEntry(d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		depth := 0
+		if rel, err := filepath.Rel(searchDir, path); err == nil && rel != "." {
+			depth = strings.Count(rel, string(filepath.Separator)) + 1
+		}
+		if s.maxDepth > 0 && depth > s.maxDepth {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		stats.visit(path, depth, d.IsDir())
+
+		if d.IsDir() {
+			if depth > 0 && s.isExcludedName(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Symlinks point to files counted where they live
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil || info.Size() < minSize {
+			return nil
+		}
+
+		select {
+		case results <- ScanResult{Path: path, Size: info.Size()}:
+		case <-ctx.Done():
+			return filepath.SkipAll
+		}
+		return nil
+	})
+}
@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// matcher matches the entries of a walk against a pattern. Patterns without
+// "/" match base names. Patterns with "/" match the whole path below the
+// search directory, one segment at a time: "**" stands for any number of
+// directories, other segments are glob patterns. "**" never crosses an
+// excluded folder such as node_modules, which has to be named in the pattern
+// to be looked into, e.g. "**/node_modules/.cache/webpack".
+type matcher struct {
+	pattern  string
+	segments []string // nil for base name patterns
+}
+
+// newMatcher prepares a pattern for matching
+func newMatcher(pattern string) matcher {
+	m := matcher{pattern: pattern}
+	if strings.Contains(pattern, "/") {
+		m.segments = strings.Split(pattern, "/")
+	}
+	return m
+}
+
+// isPath reports whether the pattern has directory components
+func (m matcher) isPath() bool {
+	return m.segments != nil
+}
+
+// match reports whether an entry matches. segments is its path below the
+// search directory (only needed for path patterns), within whether it lies
+// inside an excluded folder, where base name patterns don't apply.
+func (m matcher) match(s *Scanner, segments []string, baseName string, within bool) bool {
+	if !m.isPath() {
+		matched, err := filepath.Match(m.pattern, baseName)
+		return err == nil && matched && !within
+	}
+	return s.matchSegments(m.segments, segments, false)
+}
+
+// enters reports whether the walk must go into a directory for the pattern
+// to match below it, even though it is or lies inside an excluded folder
+func (m matcher) enters(s *Scanner, segments []string) bool {
+	return m.isPath() && s.matchSegments(m.segments, segments, true)
+}
+
+// matchSegments reports whether path segments match pattern segments. With
+// prefix set, path only has to be the beginning of a match: segments must be
+// left in the pattern for what lies below it.
+func (s *Scanner) matchSegments(pattern, path []string, prefix bool) bool {
+	if len(path) == 0 {
+		if prefix {
+			return len(pattern) > 0
+		}
+		for _, segment := range pattern {
+			if segment != "**" {
+				return false
+			}
+		}
+		return true
+	}
+	if len(pattern) == 0 {
+		return false
+	}
+
+	if pattern[0] == "**" {
+		// "**" matches no directory, or the first one unless it is excluded
+		if s.matchSegments(pattern[1:], path, prefix) {
+			return true
+		}
+		return !s.isExcludedName(path[0]) && s.matchSegments(pattern, path[1:], prefix)
+	}
+
+	matched, err := filepath.Match(pattern[0], path[0])
+	return err == nil && matched && s.matchSegments(pattern[1:], path[1:], prefix)
+}
+
+// relSegments splits a path relative to the search directory into segments,
+// none for the search directory itself
+func relSegments(rel string) []string {
+	if rel == "." || rel == "" {
+		return nil
+	}
+	return strings.Split(filepath.ToSlash(rel), "/")
+}
+
+// isWithinExcluded reports whether one of the directories above an entry,
+// below the search directory, is an excluded folder
+func (s *Scanner) isWithinExcluded(segments []string) bool {
+	if len(segments) < 2 {
+		return false
+	}
+	for _, segment := range segments[:len(segments)-1] {
+		if s.isExcludedName(segment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package scanner
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Profile collects walk statistics from the scanners it is attached to:
+// the time spent in each search directory and in each folder directly below
+// it, and how many entries were visited. It is safe for concurrent use.
+type Profile struct {
+	mu   sync.Mutex
+	dirs map[string]*DirStats
+}
+
+// DirStats is the time spent walking one directory, summed over every walk
+type DirStats struct {
+	Path     string
+	Root     bool          // A search directory rather than a folder inside one
+	Duration time.Duration // Time spent walking the directory's subtree
+	Walks    int           // Number of times the directory was walked
+	Files    int           // Files visited, summed over every walk
+	Dirs     int           // Subdirectories visited, summed over every walk
+}
+
+// NewProfile creates an empty Profile
+func NewProfile() *Profile {
+	return &Profile{dirs: make(map[string]*DirStats)}
+}
+
+// SetProfile attaches a profile to the scanner (nil to stop profiling)
+func (s *Scanner) SetProfile(p *Profile) {
+	s.profile = p
+}
+
+// Dirs returns the statistics of every directory walked, slowest first
+func (p *Profile) Dirs() []DirStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dirs := make([]DirStats, 0, len(p.dirs))
+	for _, stats := range p.dirs {
+		dirs = append(dirs, *stats)
+	}
+
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i].Duration != dirs[j].Duration {
+			return dirs[i].Duration > dirs[j].Duration
+		}
+		return dirs[i].Path < dirs[j].Path
+	})
+
+	return dirs
+}
+
+// Totals returns the number of files and directories visited in all search
+// directories so far
+func (p *Profile) Totals() (files, dirs int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, stats := range p.dirs {
+		if stats.Root {
+			files += stats.Files
+			dirs += stats.Dirs
+		}
+	}
+	return files, dirs
+}
+
+// record adds one walk of a directory to the profile
+func (p *Profile) record(path string, root bool, duration time.Duration, files, dirs int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats, ok := p.dirs[path]
+	if !ok {
+		stats = &DirStats{Path: path, Root: root}
+		p.dirs[path] = stats
+	}
+	stats.Duration += duration
+	stats.Walks++
+	stats.Files += files
+	stats.Dirs += dirs
+}
+
+// walkStats measures a single walk of a search directory. The walk is depth
+// first, so the folders directly below the search directory are timed one
+// after the other.
+type walkStats struct {
+	profile *Profile
+	root    string
+	start   time.Time
+	files   int
+	dirs    int
+
+	child      string // Folder directly below root being walked, if any
+	childStart time.Time
+	childFiles int
+	childDirs  int
+}
+
+// newWalkStats starts measuring a walk of root, or returns nil if the
+// scanner has no profile
+func (s *Scanner) newWalkStats(root string) *walkStats {
+	if s.profile == nil {
+		return nil
+	}
+	return &walkStats{profile: s.profile, root: root, start: time.Now()}
+}
+
+// visit counts an entry found depth levels below the search directory
+func (w *walkStats) visit(path string, depth int, isDir bool) {
+	if w == nil || depth == 0 {
+		return
+	}
+
+	if depth == 1 {
+		w.finishChild()
+		if isDir {
+			w.child = path
+			w.childStart = time.Now()
+		}
+	}
+
+	if isDir {
+		w.dirs++
+	} else {
+		w.files++
+	}
+
+	if w.child != "" && depth > 1 {
+		if isDir {
+			w.childDirs++
+		} else {
+			w.childFiles++
+		}
+	}
+}
+
+// finishChild records the folder below the search directory being walked
+func (w *walkStats) finishChild() {
+	if w.child == "" {
+		return
+	}
+	w.profile.record(w.child, false, time.Since(w.childStart), w.childFiles, w.childDirs)
+	w.child = ""
+	w.childFiles = 0
+	w.childDirs = 0
+}
+
+// finish records the walk in the profile
+func (w *walkStats) finish() {
+	if w == nil {
+		return
+	}
+	w.finishChild()
+	w.profile.record(w.root, true, time.Since(w.start), w.files, w.dirs)
+}
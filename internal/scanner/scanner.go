@@ -5,21 +5,35 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+
+	"github.com/0SansNom/epurer/pkg/utils"
 )
 
+// DefaultMaxDepth is how many directory levels below each search directory
+// the scanner looks by default
+const DefaultMaxDepth = 10
+
 // Scanner scans the filesystem concurrently for patterns
 type Scanner struct {
-	workers    int
-	homePath   string
-	searchDirs []string // Directories to search in (e.g., ~/Projects, ~/Code)
+	workers      int
+	homePath     string
+	searchDirs   []string    // Directories to search in (e.g., ~/Projects, ~/Code)
+	maxDepth     int         // Levels below a search directory to look into (0 = no limit)
+	excludePaths []string    // Directories never walked
+	excludeNames []string    // Directory names (glob patterns) never descended into
+	profile      *Profile    // Collects walk statistics when set
+	shared       *SharedWalk // Serves registered patterns from one walk when set
 }
 
 // ScanResult contains a found path and its size
 type ScanResult struct {
-	Path string
-	Size int64
-	Err  error
+	Path  string
+	Size  int64
+	Files int // Files inside a directory, including those of its subdirectories
+	Dirs  int // Subdirectories of a directory, at any depth
+	Err   error
 }
 
 // NewScanner creates a new Scanner with default configuration
@@ -29,6 +43,19 @@ func NewScanner() (*Scanner, error) {
 		return nil, err
 	}
 
+	s := &Scanner{
+		workers:    4, // Number of concurrent workers
+		homePath:   home,
+		searchDirs: DefaultSearchDirs(home),
+		maxDepth:   DefaultMaxDepth,
+	}
+	s.SetExcludes(nil)
+
+	return s, nil
+}
+
+// DefaultSearchDirs returns the common project locations in home that exist
+func DefaultSearchDirs(home string) []string {
 	// Default search directories - common project locations
 	searchDirs := []string{
 		filepath.Join(home, "Projects"),
@@ -47,11 +74,15 @@ func NewScanner() (*Scanner, error) {
 		}
 	}
 
-	return &Scanner{
-		workers:    4, // Number of concurrent workers
-		homePath:   home,
-		searchDirs: existingDirs,
-	}, nil
+	return existingDirs
+}
+
+// SetHome moves the scanner to another home directory: its search
+// directories become the default ones in home, and the default excludes
+// follow on the next SetExcludes call
+func (s *Scanner) SetHome(home string) {
+	s.homePath = home
+	s.searchDirs = DefaultSearchDirs(home)
 }
 
 // NewScannerWithDirs creates a Scanner with custom search directories
@@ -61,11 +92,28 @@ func NewScannerWithDirs(dirs []string) (*Scanner, error) {
 		return nil, err
 	}
 
-	return &Scanner{
+	s := &Scanner{
 		workers:    4,
 		homePath:   home,
 		searchDirs: dirs,
-	}, nil
+		maxDepth:   DefaultMaxDepth,
+	}
+	s.SetExcludes(nil)
+
+	return s, nil
+}
+
+// DefaultExcludes returns what project scans always skip: the macOS Library
+// folder (including iCloud Drive and other cloud storage mounts), Dropbox,
+// and the inside of node_modules folders
+func DefaultExcludes(home string) []string {
+	return []string{
+		filepath.Join(home, "Library"),
+		filepath.Join(home, "Library", "Mobile Documents"),
+		filepath.Join(home, "Library", "CloudStorage"),
+		filepath.Join(home, "Dropbox"),
+		"node_modules",
+	}
 }
 
 // SetWorkers sets the number of concurrent workers
@@ -75,11 +123,64 @@ func (s *Scanner) SetWorkers(n int) {
 	}
 }
 
+// SetMaxDepth limits how many directory levels below each search directory
+// are scanned (0 = no limit)
+func (s *Scanner) SetMaxDepth(depth int) {
+	if depth >= 0 {
+		s.maxDepth = depth
+	}
+}
+
+// SetExcludes sets the directories to skip on top of DefaultExcludes.
+// Entries starting with "/" or "~" are paths whose whole subtree is skipped;
+// other entries are directory names (glob patterns allowed) that can still
+// be matched themselves but are never descended into.
+func (s *Scanner) SetExcludes(excludes []string) {
+	s.excludePaths = nil
+	s.excludeNames = nil
+
+	for _, exclude := range append(DefaultExcludes(s.homePath), excludes...) {
+		if strings.HasPrefix(exclude, "/") || strings.HasPrefix(exclude, "~") {
+			if path, err := utils.ExpandHome(exclude); err == nil {
+				s.excludePaths = append(s.excludePaths, filepath.Clean(path))
+			}
+			continue
+		}
+		s.excludeNames = append(s.excludeNames, exclude)
+	}
+}
+
+// isExcludedPath checks if path is or lies inside an excluded directory,
+// whatever the case and Unicode normalization of their names
+func (s *Scanner) isExcludedPath(path string) bool {
+	for _, exclude := range s.excludePaths {
+		if utils.HasPathPrefix(path, exclude) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExcludedName checks if a directory name must not be descended into
+func (s *Scanner) isExcludedName(name string) bool {
+	for _, exclude := range s.excludeNames {
+		if matched, _ := filepath.Match(utils.NFC(exclude), utils.NFC(name)); matched {
+			return true
+		}
+	}
+	return false
+}
+
 // FindByPattern searches for all files/directories matching the pattern
 // Pattern can be:
-// - A glob pattern like "node_modules" or "*.log"
-// - A path pattern like "**/node_modules" for recursive search
+// - A glob pattern like "node_modules" or "*.log", matching base names
+// - A path pattern like "**/node_modules/.cache/webpack", matching the path
+// below the search directory (see matcher)
 func (s *Scanner) FindByPattern(ctx context.Context, pattern string) <-chan ScanResult {
+	if s.shared != nil && s.shared.covers(s, pattern) {
+		return s.shared.results(ctx, pattern)
+	}
+
 	results := make(chan ScanResult, 100) // Buffered channel for better performance
 
 	go func() {
@@ -131,23 +232,24 @@ func (s *Scanner) FindExactPath(path string) (ScanResult, error) {
 		return ScanResult{Path: path, Err: err}, err
 	}
 
-	var size int64
+	result := ScanResult{Path: path, Size: info.Size()}
 	if info.IsDir() {
-		size, err = s.calculateDirSize(path)
-	} else {
-		size = info.Size()
+		result, err = s.measureDir(path)
 	}
+	result.Err = err
 
-	return ScanResult{
-		Path: path,
-		Size: size,
-		Err:  err,
-	}, err
+	return result, err
 }
 
 // walkAndMatch walks a directory tree and sends matching paths to results
 func (s *Scanner) walkAndMatch(ctx context.Context, searchDir, pattern string, results chan<- ScanResult) {
+	stats := s.newWalkStats(searchDir)
+	defer stats.finish()
+
+	m := newMatcher(pattern)
+
 	filepath.WalkDir(searchDir, func(path string, d fs.DirEntry, err error) error {
+		utils.Throttle()
 		// Check context cancellation
 		select {
 		case <-ctx.Done():
@@ -161,28 +263,60 @@ func (s *Scanner) walkAndMatch(ctx context.Context, searchDir, pattern string, r
 			return nil
 		}
 
-		// Check if the base name matches the pattern
-		baseName := filepath.Base(path)
-		matched, err := filepath.Match(pattern, baseName)
-		if err != nil {
-			return nil // Invalid pattern, skip
+		// Protected projects, excluded folders and their subtree are never scanned
+		if d.IsDir() && (utils.HasKeepMarker(path) || s.isExcludedPath(path)) {
+			return filepath.SkipDir
+		}
+
+		// Cloud placeholders aren't on the local disk, and walking a dataless
+		// folder would download it
+		if utils.IsDatalessEntry(d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Stay within the depth limit
+		depth := 0
+		rel, err := filepath.Rel(searchDir, path)
+		if err == nil && rel != "." {
+			depth = strings.Count(rel, string(filepath.Separator)) + 1
+		}
+		if s.maxDepth > 0 && depth > s.maxDepth {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		stats.visit(path, depth, d.IsDir())
+
+		// Path patterns look into excluded folders, base name patterns never
+		// get there
+		var segments []string
+		within := false
+		if m.isPath() {
+			segments = relSegments(rel)
+			within = s.isWithinExcluded(segments)
 		}
 
-		if matched {
-			size := int64(0)
+		// Check if the entry matches the pattern
+		baseName := filepath.Base(path)
+		if m.match(s, segments, baseName, within) {
+			result := ScanResult{Path: path}
 
 			// Calculate size
 			if d.IsDir() {
-				size, _ = s.calculateDirSize(path)
+				result, _ = s.measureDir(path)
 			} else {
 				if info, err := d.Info(); err == nil {
-					size = info.Size()
+					result.Size = info.Size()
 				}
 			}
 
 			// Send result
 			select {
-			case results <- ScanResult{Path: path, Size: size}:
+			case results <- result:
 			case <-ctx.Done():
 				return filepath.SkipAll
 			}
@@ -193,12 +327,20 @@ func (s *Scanner) walkAndMatch(ctx context.Context, searchDir, pattern string, r
 			}
 		}
 
+		// Don't descend into excluded directories such as node_modules,
+		// unless a path pattern names what to look for inside
+		if d.IsDir() && depth > 0 && (within || s.isExcludedName(baseName)) && !m.enters(s, segments) {
+			return filepath.SkipDir
+		}
+
 		return nil
 	})
 }
 
-// calculateDirSize calculates the total size of a directory recursively
-func (s *Scanner) calculateDirSize(path string) (int64, error) {
+// measureDir calculates the total size of a directory recursively, and
+// counts the files and subdirectories in it
+func (s *Scanner) measureDir(path string) (ScanResult, error) {
+	result := ScanResult{Path: path}
 	var size int64
 	var mu sync.Mutex
 
@@ -207,12 +349,26 @@ func (s *Scanner) calculateDirSize(path string) (int64, error) {
 	var wg sync.WaitGroup
 
 	err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+		utils.Throttle()
 		if err != nil {
 			// Continue despite errors (permission issues, etc.)
 			return nil
 		}
 
-		if !info.IsDir() {
+		// Cloud placeholders take no local space
+		if utils.IsDataless(info) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			if filePath != path {
+				result.Dirs++
+			}
+		} else {
+			result.Files++
 			wg.Add(1)
 			semaphore <- struct{}{}
 
@@ -230,7 +386,8 @@ func (s *Scanner) calculateDirSize(path string) (int64, error) {
 	})
 
 	wg.Wait()
-	return size, err
+	result.Size = size
+	return result, err
 }
 
 // FindMultiplePatterns searches for multiple patterns concurrently
@@ -249,7 +406,8 @@ func (s *Scanner) GetSearchDirs() []string {
 	return s.searchDirs
 }
 
-// AddSearchDir adds a directory to the search list
+// AddSearchDir adds a directory to the search list, unless it is already
+// searched
 func (s *Scanner) AddSearchDir(dir string) error {
 	info, err := os.Stat(dir)
 	if err != nil {
@@ -260,6 +418,9 @@ func (s *Scanner) AddSearchDir(dir string) error {
 		return fs.ErrInvalid
 	}
 
+	if s.isSearched(dir) {
+		return nil
+	}
 	s.searchDirs = append(s.searchDirs, dir)
 	return nil
 }
@@ -4,6 +4,9 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -102,6 +105,314 @@ func TestFindByPattern(t *testing.T) {
 	}
 }
 
+func TestFindByPattern_SkipsProtectedDirs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Two projects, one protected by a keep marker
+	for _, dir := range []string{
+		filepath.Join(tmpDir, "client-app", "node_modules"),
+		filepath.Join(tmpDir, "side-project", "node_modules"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create test dir: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "client-app", ".epurer-keep"), nil, 0644); err != nil {
+		t.Fatalf("Failed to create keep marker: %v", err)
+	}
+
+	scanner, _ := NewScannerWithDirs([]string{tmpDir})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	found := []string{}
+	for result := range scanner.FindByPattern(ctx, "node_modules") {
+		found = append(found, result.Path)
+	}
+
+	if len(found) != 1 || found[0] != filepath.Join(tmpDir, "side-project", "node_modules") {
+		t.Errorf("Expected only the unprotected node_modules, got %v", found)
+	}
+}
+
+func TestFindByPattern_MaxDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, dir := range []string{
+		filepath.Join(tmpDir, "app", "dist"),
+		filepath.Join(tmpDir, "org", "monorepo", "packages", "web", "dist"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create test dir: %v", err)
+		}
+	}
+
+	tests := []struct {
+		depth    int
+		expected int
+	}{
+		{0, 2}, // No limit
+		{2, 1},
+		{5, 2},
+	}
+
+	for _, tt := range tests {
+		scanner, _ := NewScannerWithDirs([]string{tmpDir})
+		scanner.SetMaxDepth(tt.depth)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		count := 0
+		for range scanner.FindByPattern(ctx, "dist") {
+			count++
+		}
+		cancel()
+
+		if count != tt.expected {
+			t.Errorf("Depth %d: expected %d results, got %d", tt.depth, tt.expected, count)
+		}
+	}
+}
+
+func TestFindByPattern_Excludes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, dir := range []string{
+		filepath.Join(tmpDir, "app", "dist"),
+		filepath.Join(tmpDir, "app", "node_modules", "lib", "dist"),
+		filepath.Join(tmpDir, "Dropbox-like", "shared", "dist"),
+		filepath.Join(tmpDir, "vendored", "dist"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create test dir: %v", err)
+		}
+	}
+
+	scanner, _ := NewScannerWithDirs([]string{tmpDir})
+	scanner.SetExcludes([]string{filepath.Join(tmpDir, "Dropbox-like"), "vendor*"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	found := []string{}
+	for result := range scanner.FindByPattern(ctx, "dist") {
+		found = append(found, result.Path)
+	}
+
+	if len(found) != 1 || found[0] != filepath.Join(tmpDir, "app", "dist") {
+		t.Errorf("Expected only app/dist, got %v", found)
+	}
+
+	// Excluded names can still be matched themselves
+	count := 0
+	for range scanner.FindByPattern(ctx, "node_modules") {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("Expected node_modules to be found, got %d results", count)
+	}
+}
+
+func TestFindLargeFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]int{
+		"app/disk.img":                2000,
+		"app/logs/debug.log":          1500,
+		"app/small.txt":               10,
+		"app/node_modules/lib/big.js": 5000,
+		"archive/keep/old.zip":        3000,
+	}
+	for rel, size := range files {
+		path := filepath.Join(tmpDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanner, _ := NewScannerWithDirs([]string{tmpDir})
+	scanner.SetExcludes([]string{filepath.Join(tmpDir, "archive")})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	found := []string{}
+	for result := range scanner.FindLargeFiles(ctx, 1000) {
+		if result.Size != int64(files[strings.TrimPrefix(result.Path, tmpDir+string(filepath.Separator))]) {
+			t.Errorf("Wrong size %d for %s", result.Size, result.Path)
+		}
+		found = append(found, result.Path)
+	}
+	sort.Strings(found)
+
+	expected := []string{filepath.Join(tmpDir, "app", "disk.img"), filepath.Join(tmpDir, "app", "logs", "debug.log")}
+	if !slices.Equal(found, expected) {
+		t.Errorf("Expected %v, got %v", expected, found)
+	}
+}
+
+func TestFindByPattern_Profile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, file := range []string{
+		filepath.Join(tmpDir, "app", "src", "main.go"),
+		filepath.Join(tmpDir, "app", "README.md"),
+		filepath.Join(tmpDir, "notes.txt"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatalf("Failed to create test dir: %v", err)
+		}
+		if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	profile := NewProfile()
+	scanner, _ := NewScannerWithDirs([]string{tmpDir})
+	scanner.SetProfile(profile)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, pattern := range []string{"dist", "build"} {
+		for range scanner.FindByPattern(ctx, pattern) {
+		}
+	}
+
+	dirs := profile.Dirs()
+	if len(dirs) != 2 {
+		t.Fatalf("Expected the search dir and app, got %+v", dirs)
+	}
+
+	stats := map[string]DirStats{}
+	for _, d := range dirs {
+		stats[d.Path] = d
+	}
+
+	root := stats[tmpDir]
+	if !root.Root || root.Walks != 2 || root.Files != 6 || root.Dirs != 4 {
+		t.Errorf("Unexpected search dir stats: %+v", root)
+	}
+
+	app := stats[filepath.Join(tmpDir, "app")]
+	if app.Root || app.Walks != 2 || app.Files != 4 || app.Dirs != 2 {
+		t.Errorf("Unexpected app stats: %+v", app)
+	}
+
+	files, dirCount := profile.Totals()
+	if files != 6 || dirCount != 4 {
+		t.Errorf("Expected totals of 6 files and 4 dirs, got %d and %d", files, dirCount)
+	}
+}
+
+func TestSharedWalk_MatchesFindByPattern(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, dir := range []string{
+		filepath.Join(tmpDir, "app", "node_modules", "pkg", "node_modules"),
+		filepath.Join(tmpDir, "app", "node_modules", "dist"),
+		filepath.Join(tmpDir, "app", "node_modules", ".cache", "webpack"),
+		filepath.Join(tmpDir, "app", "node_modules", "pkg", "node_modules", ".cache", "webpack"),
+		filepath.Join(tmpDir, "app", "dist", "build"),
+		filepath.Join(tmpDir, "app", "build", "build"),
+		filepath.Join(tmpDir, "lib", "src"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create test dir: %v", err)
+		}
+	}
+	for _, file := range []string{
+		filepath.Join(tmpDir, "app", "dist", "bundle.js"),
+		filepath.Join(tmpDir, "app", "debug.log"),
+		filepath.Join(tmpDir, "app", "node_modules", ".cache", "webpack", "cache.log"),
+		filepath.Join(tmpDir, "lib", "src", "error.log"),
+	} {
+		if err := os.WriteFile(file, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	patterns := []string{"node_modules", "dist", "build", "*.log", "**/node_modules/.cache/webpack"}
+
+	collect := func(s *Scanner, pattern string) []string {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		paths := []string{}
+		for result := range s.FindByPattern(ctx, pattern) {
+			paths = append(paths, result.Path)
+		}
+		sort.Strings(paths)
+		return paths
+	}
+
+	own, _ := NewScannerWithDirs([]string{tmpDir})
+
+	profile := NewProfile()
+	shared, _ := NewScannerWithDirs([]string{tmpDir})
+	shared.SetProfile(profile)
+	walk := shared.NewSharedWalk(context.Background())
+	walk.Register(patterns...)
+	shared.Subscribe(walk)
+
+	for _, pattern := range patterns {
+		expected := collect(own, pattern)
+		got := collect(shared, pattern)
+		if !slices.Equal(expected, got) {
+			t.Errorf("Pattern %q: expected %v, got %v", pattern, expected, got)
+		}
+	}
+
+	// Asking again is served from the same walk
+	collect(shared, "dist")
+	for _, stats := range profile.Dirs() {
+		if stats.Walks != 1 {
+			t.Errorf("Expected %s to be walked once, got %d", stats.Path, stats.Walks)
+		}
+	}
+}
+
+func TestSharedWalk_UnregisteredPattern(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "app", ".next"), 0755); err != nil {
+		t.Fatalf("Failed to create test dir: %v", err)
+	}
+
+	s, _ := NewScannerWithDirs([]string{tmpDir})
+	walk := s.NewSharedWalk(context.Background())
+	walk.Register("dist")
+	s.Subscribe(walk)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count := 0
+	for range s.FindByPattern(ctx, ".next") {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("Expected unregistered pattern to be found by its own walk, got %d results", count)
+	}
+}
+
+func TestDefaultExcludes(t *testing.T) {
+	excludes := DefaultExcludes("/Users/dev")
+
+	expected := map[string]bool{
+		"/Users/dev/Library":                  true,
+		"/Users/dev/Library/Mobile Documents": true,
+		"node_modules":                        true,
+	}
+	for _, exclude := range excludes {
+		delete(expected, exclude)
+	}
+	if len(expected) != 0 {
+		t.Errorf("Missing default excludes: %v", expected)
+	}
+}
+
 func TestAddSearchDir(t *testing.T) {
 	scanner, _ := NewScanner()
 
@@ -122,3 +433,125 @@ func TestAddSearchDir(t *testing.T) {
 		t.Errorf("Expected %d search dirs, got %d", initialCount+1, len(scanner.GetSearchDirs()))
 	}
 }
+
+func TestSetHome(t *testing.T) {
+	home, err := os.MkdirTemp("", "scanner-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(home)
+
+	if err := os.MkdirAll(filepath.Join(home, "Projects"), 0755); err != nil {
+		t.Fatalf("Failed to create Projects: %v", err)
+	}
+
+	scanner, _ := NewScanner()
+	scanner.SetHome(home)
+	scanner.SetExcludes(nil)
+
+	dirs := scanner.GetSearchDirs()
+	if len(dirs) != 1 || dirs[0] != filepath.Join(home, "Projects") {
+		t.Errorf("Expected only %s/Projects as search dir, got %v", home, dirs)
+	}
+	if !scanner.isExcludedPath(filepath.Join(home, "Library", "Caches")) {
+		t.Error("Expected the fake home's Library to be excluded")
+	}
+}
+
+func TestDiscoverSearchDirs(t *testing.T) {
+	home := t.TempDir()
+	for _, marker := range []string{
+		"Projects/a/go.mod",
+		"work/client-a/api/.git/HEAD",
+		"work/client-a/web/package.json",
+		"work/client-b/app/Cargo.toml",
+		"work/client-b/app/crates/core/Cargo.toml",
+		"notes/2024/todo.txt",
+		"notes/old/go.mod",
+		"solo/package.json",
+		"solo/a/package.json",
+		"solo/b/package.json",
+		".config/x/package.json",
+		"deep/1/2/3/a/go.mod",
+		"deep/1/2/3/b/go.mod",
+		"deep/1/2/3/c/go.mod",
+	} {
+		path := filepath.Join(home, marker)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanner, _ := NewScanner()
+	scanner.SetHome(home)
+	scanner.SetExcludes(nil)
+
+	candidates := scanner.DiscoverSearchDirs(context.Background())
+	if len(candidates) != 1 || candidates[0] != (Candidate{Path: filepath.Join(home, "work"), Projects: 3}) {
+		t.Errorf("Expected only work with 3 projects, got %+v", candidates)
+	}
+}
+
+func TestFindByPattern_PathPattern(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{
+		filepath.Join(tmpDir, "web", "node_modules", ".cache", "webpack"),
+		filepath.Join(tmpDir, "web", "node_modules", "pkg", "node_modules", ".cache", "webpack"),
+		filepath.Join(tmpDir, "clients", "a", "node_modules", ".cache", "turbo"),
+		filepath.Join(tmpDir, "notes", ".cache", "webpack"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create test dir: %v", err)
+		}
+	}
+
+	s, _ := NewScannerWithDirs([]string{tmpDir})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	paths := []string{}
+	for result := range s.FindByPattern(ctx, "**/node_modules/.cache/*") {
+		paths = append(paths, result.Path)
+	}
+	sort.Strings(paths)
+
+	expected := []string{
+		filepath.Join(tmpDir, "clients", "a", "node_modules", ".cache", "turbo"),
+		filepath.Join(tmpDir, "web", "node_modules", ".cache", "webpack"),
+	}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+}
+
+func TestFindByPattern_Counts(t *testing.T) {
+	tmpDir := t.TempDir()
+	modules := filepath.Join(tmpDir, "app", "node_modules")
+	if err := os.MkdirAll(filepath.Join(modules, "lodash"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"package.json", filepath.Join("lodash", "index.js")} {
+		if err := os.WriteFile(filepath.Join(modules, file), []byte("test content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanner, _ := NewScannerWithDirs([]string{tmpDir})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var results []ScanResult
+	for result := range scanner.FindByPattern(ctx, "node_modules") {
+		results = append(results, result)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if results[0].Files != 2 || results[0].Dirs != 1 {
+		t.Errorf("Expected 2 files and 1 folder, got %d and %d", results[0].Files, results[0].Dirs)
+	}
+}
@@ -0,0 +1,285 @@
+package scanner
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// SharedWalk walks the search directories once for every registered pattern
+// and hands the matches of each pattern to the scanners subscribed to it.
+// Without it every FindByPattern call walks the whole tree again.
+//
+// The walk starts on the first request for a registered pattern and runs
+// under the context given to NewSharedWalk, so a subscriber that gives up
+// (e.g. when its scan times out) doesn't cut it short for the others.
+// Matches are streamed to subscribers as they are found.
+type SharedWalk struct {
+	scanner  *Scanner // Search directories, limits and profile of the walk
+	ctx      context.Context
+	patterns []string
+
+	start   sync.Once
+	mu      sync.Mutex
+	matches map[string][]ScanResult // Matches found so far, by pattern
+	changed chan struct{}           // Closed and replaced when matches grow or the walk ends
+	done    bool
+}
+
+// NewSharedWalk creates a shared walk of the scanner's search directories,
+// using its depth limit, excludes and profile. Register the patterns before
+// the first FindByPattern call of a subscribed scanner.
+func (s *Scanner) NewSharedWalk(ctx context.Context) *SharedWalk {
+	return &SharedWalk{
+		scanner: s,
+		ctx:     ctx,
+		matches: make(map[string][]ScanResult),
+		changed: make(chan struct{}),
+	}
+}
+
+// Register adds patterns to match during the walk
+func (w *SharedWalk) Register(patterns ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, pattern := range patterns {
+		if !slices.Contains(w.patterns, pattern) {
+			w.patterns = append(w.patterns, pattern)
+		}
+	}
+}
+
+// Subscribe makes FindByPattern serve registered patterns from a shared walk
+// (nil to walk on every call again). Patterns that aren't registered, or a
+// scanner searching other directories than the walk, still walk on their own.
+func (s *Scanner) Subscribe(w *SharedWalk) {
+	s.shared = w
+}
+
+// covers reports whether the walk can answer FindByPattern for s
+func (w *SharedWalk) covers(s *Scanner, pattern string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return slices.Contains(w.patterns, pattern) && slices.Equal(w.scanner.searchDirs, s.searchDirs)
+}
+
+// results streams the matches of a pattern, starting the walk if needed
+func (w *SharedWalk) results(ctx context.Context, pattern string) <-chan ScanResult {
+	w.start.Do(func() {
+		go w.run()
+	})
+
+	results := make(chan ScanResult, 100)
+
+	go func() {
+		defer close(results)
+
+		sent := 0
+		for {
+			w.mu.Lock()
+			pending := w.matches[pattern][sent:]
+			done := w.done
+			changed := w.changed
+			w.mu.Unlock()
+
+			for _, result := range pending {
+				select {
+				case results <- result:
+					sent++
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if done {
+				return
+			}
+
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// run walks every search directory, a few at a time
+func (w *SharedWalk) run() {
+	w.mu.Lock()
+	patterns := slices.Clone(w.patterns)
+	w.mu.Unlock()
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, w.scanner.workers)
+
+	for _, dir := range w.scanner.searchDirs {
+		if w.ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(searchDir string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			w.walk(searchDir, patterns)
+		}(dir)
+	}
+
+	wg.Wait()
+
+	w.mu.Lock()
+	w.done = true
+	close(w.changed)
+	w.mu.Unlock()
+}
+
+// add records a match for the given patterns and wakes up subscribers
+func (w *SharedWalk) add(patterns []string, result ScanResult) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, pattern := range patterns {
+		w.matches[pattern] = append(w.matches[pattern], result)
+	}
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+// walk matches all patterns in one walk of searchDir. It finds the same
+// paths as a walkAndMatch per pattern: a directory matched by a pattern is
+// not searched further for that pattern, but the other patterns still look
+// inside it. The walk only skips a directory once no pattern needs it.
+func (w *SharedWalk) walk(searchDir string, patterns []string) {
+	s := w.scanner
+	stats := s.newWalkStats(searchDir)
+	defer stats.finish()
+
+	// Matched directory each pattern is currently inside of, if any
+	inside := make(map[string]string, len(patterns))
+
+	matchers := make([]matcher, len(patterns))
+	pathPatterns := false
+	for i, pattern := range patterns {
+		matchers[i] = newMatcher(pattern)
+		pathPatterns = pathPatterns || matchers[i].isPath()
+	}
+
+	filepath.WalkDir(searchDir, func(path string, d fs.DirEntry, err error) error {
+		utils.Throttle()
+		if w.ctx.Err() != nil {
+			return filepath.SkipAll
+		}
+
+		if err != nil {
+			return nil
+		}
+
+		// Same skipping rules as walkAndMatch
+		if d.IsDir() && (utils.HasKeepMarker(path) || s.isExcludedPath(path)) {
+			return filepath.SkipDir
+		}
+
+		if utils.IsDatalessEntry(d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		depth := 0
+		rel, err := filepath.Rel(searchDir, path)
+		if err == nil && rel != "." {
+			depth = strings.Count(rel, string(filepath.Separator)) + 1
+		}
+		if s.maxDepth > 0 && depth > s.maxDepth {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		stats.visit(path, depth, d.IsDir())
+
+		// Patterns whose matched directory we have walked out of apply again
+		for pattern, dir := range inside {
+			if !utils.HasPathPrefix(path, dir) {
+				delete(inside, pattern)
+			}
+		}
+
+		// Path patterns look into excluded folders, where base name patterns
+		// don't apply
+		var segments []string
+		within := false
+		if pathPatterns {
+			segments = relSegments(rel)
+			within = s.isWithinExcluded(segments)
+		}
+
+		baseName := filepath.Base(path)
+		matched := []string{}
+		for _, m := range matchers {
+			if _, ok := inside[m.pattern]; ok {
+				continue
+			}
+			if m.match(s, segments, baseName, within) {
+				matched = append(matched, m.pattern)
+			}
+		}
+
+		if len(matched) > 0 {
+			result := ScanResult{Path: path}
+			if d.IsDir() {
+				result, _ = s.measureDir(path)
+			} else if info, err := d.Info(); err == nil {
+				result.Size = info.Size()
+			}
+			w.add(matched, result)
+
+			if d.IsDir() {
+				for _, pattern := range matched {
+					inside[pattern] = path
+				}
+			}
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		// Don't descend into excluded directories such as node_modules,
+		// unless a path pattern names what to look for inside, nor into
+		// directories no pattern is looking inside anymore
+		if depth > 0 && (within || s.isExcludedName(baseName)) && !entersAny(s, matchers, inside, segments) {
+			return filepath.SkipDir
+		}
+		if len(inside) == len(patterns) {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+}
+
+// entersAny reports whether a path pattern not matched above a directory
+// needs the walk to go into it
+func entersAny(s *Scanner, matchers []matcher, inside map[string]string, segments []string) bool {
+	for _, m := range matchers {
+		if _, ok := inside[m.pattern]; !ok && m.enters(s, segments) {
+			return true
+		}
+	}
+	return false
+}
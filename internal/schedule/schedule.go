@@ -0,0 +1,134 @@
+// Package schedule decides whether a scheduled clean run may clean now: on
+// AC power, once the machine has been idle long enough, and outside work
+// hours.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+// probes read the state of the machine the conditions depend on
+type probes struct {
+	onACPower func(ctx context.Context) (bool, error)
+	idleTime  func(ctx context.Context) (time.Duration, error)
+}
+
+// errUnsupported is returned by the probes of a state this system has no
+// way to read, such as the idle time outside macOS
+var errUnsupported = errors.New("not supported on this system")
+
+// Check returns why a scheduled run should be deferred at now, or "" if it
+// may clean. A condition whose state can't be read defers the run, unless
+// the system has no way to read it at all: that condition is ignored.
+func Check(ctx context.Context, s config.Schedule, now time.Time) string {
+	return check(ctx, s, now, probes{onACPower: OnACPower, idleTime: IdleTime})
+}
+
+func check(ctx context.Context, s config.Schedule, now time.Time, p probes) string {
+	if s.WorkHours != nil && s.WorkHours.Contains(now) {
+		return "within work hours"
+	}
+	if s.RequireACPower {
+		onAC, err := p.onACPower(ctx)
+		if errors.Is(err, errUnsupported) {
+			onAC, err = true, nil
+		}
+		if err != nil {
+			return fmt.Sprintf("can't tell the power source: %v", err)
+		}
+		if !onAC {
+			return "running on battery"
+		}
+	}
+	if s.MinIdle > 0 {
+		idle, err := p.idleTime(ctx)
+		if errors.Is(err, errUnsupported) {
+			idle, err = s.MinIdle, nil
+		}
+		if err != nil {
+			return fmt.Sprintf("can't tell the idle time: %v", err)
+		}
+		if idle < s.MinIdle {
+			return fmt.Sprintf("idle for %s only, waiting for %s", idle.Round(time.Second), s.MinIdle)
+		}
+	}
+	return ""
+}
+
+// OnACPower reports whether the machine draws from AC power: from the
+// IOKit power sources pmset reports on macOS, from the power supplies of
+// /sys/class/power_supply on Linux
+func OnACPower(ctx context.Context) (bool, error) {
+	return onACPower(ctx)
+}
+
+// parsePowerSource reads the first line of pmset -g batt, e.g. "Now drawing
+// from 'AC Power'"
+func parsePowerSource(output string) (bool, error) {
+	first, _, _ := strings.Cut(output, "\n")
+	_, source, ok := strings.Cut(first, "drawing from ")
+	if !ok {
+		return false, fmt.Errorf("unexpected pmset output %q", first)
+	}
+	return strings.Trim(strings.TrimSpace(source), "'") == "AC Power", nil
+}
+
+// IdleTime returns how long the machine has gone without keyboard or mouse
+// input, from the HIDIdleTime of the IOHIDSystem on macOS
+func IdleTime(ctx context.Context) (time.Duration, error) {
+	return idleTime(ctx)
+}
+
+var hidIdleTimeRe = regexp.MustCompile(`"HIDIdleTime"\s*=\s*(\d+)`)
+
+// parseHIDIdleTime reads the HIDIdleTime of ioreg output, in nanoseconds
+func parseHIDIdleTime(output string) (time.Duration, error) {
+	match := hidIdleTimeRe.FindStringSubmatch(output)
+	if match == nil {
+		return 0, fmt.Errorf("no HIDIdleTime in ioreg output")
+	}
+	nanoseconds, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(nanoseconds), nil
+}
+
+// powerSupplyOnAC reports whether the power supplies in dir, laid out like
+// /sys/class/power_supply, draw from AC power: a mains adapter is online, or
+// no battery is discharging. A machine without a battery is always on AC.
+func powerSupplyOnAC(dir string) (bool, error) {
+	supplies, err := os.ReadDir(dir)
+	if err != nil {
+		return false, fmt.Errorf("failed to get power source: %w", err)
+	}
+
+	onAC := true
+	for _, supply := range supplies {
+		read := func(name string) string {
+			data, _ := os.ReadFile(filepath.Join(dir, supply.Name(), name))
+			return strings.TrimSpace(string(data))
+		}
+		switch read("type") {
+		case "Mains":
+			if read("online") == "1" {
+				return true, nil
+			}
+		case "Battery":
+			if read("status") == "Discharging" {
+				onAC = false
+			}
+		}
+	}
+	return onAC, nil
+}
@@ -0,0 +1,26 @@
+//go:build darwin
+
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+func onACPower(ctx context.Context) (bool, error) {
+	output, err := exec.CommandContext(ctx, "pmset", "-g", "batt").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to get power source: %w", err)
+	}
+	return parsePowerSource(string(output))
+}
+
+func idleTime(ctx context.Context) (time.Duration, error) {
+	output, err := exec.CommandContext(ctx, "ioreg", "-c", "IOHIDSystem", "-d", "4", "-r", "-k", "HIDIdleTime").Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get idle time: %w", err)
+	}
+	return parseHIDIdleTime(string(output))
+}
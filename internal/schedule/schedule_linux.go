@@ -0,0 +1,18 @@
+//go:build linux
+
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+func onACPower(ctx context.Context) (bool, error) {
+	return powerSupplyOnAC("/sys/class/power_supply")
+}
+
+// The idle time of a Linux session depends on its display server, which
+// a run started by cron can't reach
+func idleTime(ctx context.Context) (time.Duration, error) {
+	return 0, errUnsupported
+}
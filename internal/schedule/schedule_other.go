@@ -0,0 +1,16 @@
+//go:build !darwin && !linux
+
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+func onACPower(ctx context.Context) (bool, error) {
+	return false, errUnsupported
+}
+
+func idleTime(ctx context.Context) (time.Duration, error) {
+	return 0, errUnsupported
+}
@@ -0,0 +1,126 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+func TestParsePowerSource(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"Now drawing from 'AC Power'\n -InternalBattery-0 (id=4653155)\t100%; charged; 0:00 remaining present: true\n", true},
+		{"Now drawing from 'Battery Power'\n -InternalBattery-0 (id=4653155)\t81%; discharging; 5:12 remaining present: true\n", false},
+		{"Now drawing from 'UPS Power'\n", false},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePowerSource(tt.output)
+		if err != nil {
+			t.Fatalf("parsePowerSource() returned error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("parsePowerSource(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+
+	if _, err := parsePowerSource("pmset: unknown option\n"); err == nil {
+		t.Error("Expected error for unexpected output")
+	}
+}
+
+func TestParseHIDIdleTime(t *testing.T) {
+	output := `+-o IOHIDSystem  <class IOHIDSystem, id 0x100000465, registered, matched, active, busy 0 (0 ms), retain 22>
+    {
+      "HIDIdleTime" = 754213958
+    }
+`
+	idle, err := parseHIDIdleTime(output)
+	if err != nil {
+		t.Fatalf("parseHIDIdleTime() returned error: %v", err)
+	}
+	if idle != 754213958*time.Nanosecond {
+		t.Errorf("Expected 754213958ns, got %v", idle)
+	}
+
+	if _, err := parseHIDIdleTime(""); err == nil {
+		t.Error("Expected error without HIDIdleTime")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	// 2025-01-17 is a Friday
+	friday := func(hour int) time.Time {
+		return time.Date(2025, 1, 17, hour, 0, 0, 0, time.Local)
+	}
+	workHours := &config.WorkHours{Start: 9 * time.Hour, End: 18 * time.Hour, Days: []time.Weekday{time.Friday}}
+	machine := func(onAC bool, idle time.Duration, err error) probes {
+		return probes{
+			onACPower: func(context.Context) (bool, error) { return onAC, err },
+			idleTime:  func(context.Context) (time.Duration, error) { return idle, err },
+		}
+	}
+	all := config.Schedule{RequireACPower: true, MinIdle: 15 * time.Minute, WorkHours: workHours}
+
+	tests := []struct {
+		name     string
+		schedule config.Schedule
+		now      time.Time
+		probes   probes
+		want     string
+	}{
+		{"no conditions", config.Schedule{}, friday(10), machine(false, 0, nil), ""},
+		{"all met", all, friday(20), machine(true, time.Hour, nil), ""},
+		{"work hours", all, friday(10), machine(true, time.Hour, nil), "within work hours"},
+		{"battery", all, friday(20), machine(false, time.Hour, nil), "running on battery"},
+		{"busy", all, friday(20), machine(true, 5*time.Minute, nil), "idle for 5m0s only"},
+		{"unknown state", all, friday(20), machine(true, time.Hour, errors.New("pmset not found")), "can't tell the power source"},
+		{"unsupported state", all, friday(20), machine(false, 0, errUnsupported), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := check(context.Background(), tt.schedule, tt.now, tt.probes)
+			if tt.want == "" && got != "" {
+				t.Errorf("Expected the run to go ahead, deferred: %s", got)
+			}
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("Expected deferral %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPowerSupplyOnAC(t *testing.T) {
+	supply := func(dir, name string, files map[string]string) {
+		os.MkdirAll(filepath.Join(dir, name), 0755)
+		for file, content := range files {
+			os.WriteFile(filepath.Join(dir, name, file), []byte(content+"\n"), 0644)
+		}
+	}
+
+	laptop := t.TempDir()
+	supply(laptop, "AC", map[string]string{"type": "Mains", "online": "0"})
+	supply(laptop, "BAT0", map[string]string{"type": "Battery", "status": "Discharging"})
+	if onAC, err := powerSupplyOnAC(laptop); err != nil || onAC {
+		t.Errorf("Expected a laptop on battery, got %v, %v", onAC, err)
+	}
+
+	supply(laptop, "AC", map[string]string{"online": "1"})
+	if onAC, _ := powerSupplyOnAC(laptop); !onAC {
+		t.Error("Expected a laptop plugged in")
+	}
+
+	// No battery at all
+	if onAC, err := powerSupplyOnAC(t.TempDir()); err != nil || !onAC {
+		t.Errorf("Expected a desktop on AC, got %v, %v", onAC, err)
+	}
+}
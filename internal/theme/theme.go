@@ -0,0 +1,206 @@
+// Package theme adapts the colors and symbols of the reporter and the TUI to
+// the terminal and the reader: a palette colorblind readers can tell apart,
+// no colors at all, or plain ASCII for terminals and logs that mangle emoji
+// and box drawing.
+package theme
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Colors of the default palette. Styles are written with them and the
+// theme in use swaps them for its own.
+var (
+	Primary   = lipgloss.Color("#7C3AED") // Purple
+	Secondary = lipgloss.Color("#06B6D4") // Cyan
+	Success   = lipgloss.Color("#10B981") // Green
+	Warning   = lipgloss.Color("#F59E0B") // Amber
+	Danger    = lipgloss.Color("#EF4444") // Red
+	Muted     = lipgloss.Color("#6B7280") // Gray
+	White     = lipgloss.Color("#FFFFFF")
+)
+
+// Theme is how output is colored and which symbols it uses
+type Theme struct {
+	Name       string
+	colors     map[lipgloss.Color]lipgloss.Color // Replacements of palette colors
+	monochrome bool                              // No colors at all
+	symbols    map[rune]string                   // Replacements of symbols
+	ascii      bool                              // Other emoji are dropped, other symbols replaced
+}
+
+// Names are the names of the themes, default first
+var Names = []string{"default", "colorblind", "monochrome", "ascii"}
+
+// Default is the colorful theme with emoji
+var Default = Theme{Name: "default"}
+
+// Colorblind uses the Okabe-Ito palette, which readers with any kind of
+// color blindness can tell apart, and safety levels that differ by shape
+var Colorblind = Theme{
+	Name: "colorblind",
+	colors: map[lipgloss.Color]lipgloss.Color{
+		Primary:   "#CC79A7", // Reddish purple
+		Secondary: "#56B4E9", // Sky blue
+		Success:   "#0072B2", // Blue
+		Warning:   "#E69F00", // Orange
+		Danger:    "#D55E00", // Vermillion
+	},
+	symbols: map[rune]string{'🟢': "🔵", '🟡': "🔶", '🔴': "🔺"},
+}
+
+// Monochrome keeps the symbols but uses no colors, like NO_COLOR asks
+var Monochrome = Theme{Name: "monochrome", monochrome: true}
+
+// ASCII keeps the colors but writes nothing but ASCII symbols
+var ASCII = Theme{
+	Name:  "ascii",
+	ascii: true,
+	symbols: map[rune]string{
+		'✅': "OK", '✓': "*", '✗': "x", '❌': "X", '⚠': "!", 'ℹ': "i",
+		'🟢': "[S]", '🟡': "[M]", '🔴': "[D]", '❓': "?",
+		'•': "*", '→': "->", '↑': "^", '↓': "v", '↻': "~", '×': "x",
+		'█': "#", '░': ".", '▒': ":", '▓': "#",
+	},
+}
+
+// Parse returns the theme named name
+func Parse(name string) (Theme, error) {
+	for _, t := range []Theme{Default, Colorblind, Monochrome, ASCII} {
+		if strings.EqualFold(name, t.Name) {
+			return t, nil
+		}
+	}
+	return Default, fmt.Errorf("invalid theme: %s (must be %s)", name, strings.Join(Names, ", "))
+}
+
+// Resolve returns the theme named name, from the --theme flag or the
+// config file. Without one, NO_COLOR selects the monochrome theme.
+func Resolve(name string) (Theme, error) {
+	if name != "" {
+		return Parse(name)
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return Monochrome, nil
+	}
+	return Default, nil
+}
+
+// IsMonochrome reports whether the theme uses no colors
+func (t Theme) IsMonochrome() bool {
+	return t.monochrome
+}
+
+// color returns the theme's color for a palette color
+func (t Theme) color(c lipgloss.TerminalColor) (lipgloss.TerminalColor, bool) {
+	if _, none := c.(lipgloss.NoColor); none {
+		return c, false
+	}
+	if t.monochrome {
+		return lipgloss.NoColor{}, true
+	}
+	color, ok := c.(lipgloss.Color)
+	if !ok {
+		return c, false
+	}
+	replacement, ok := t.colors[color]
+	return replacement, ok
+}
+
+// Style returns s with the palette colors it uses replaced by the theme's
+func (t Theme) Style(s lipgloss.Style) lipgloss.Style {
+	if c, ok := t.color(s.GetForeground()); ok {
+		s = s.Foreground(c)
+	}
+	if c, ok := t.color(s.GetBackground()); ok {
+		s = s.Background(c)
+	}
+	if c, ok := t.color(s.GetBorderTopForeground()); ok {
+		s = s.BorderForeground(c)
+	}
+	return s
+}
+
+// Text returns s with its symbols replaced by the theme's. The ASCII theme
+// also drops other emoji, with the space after them, and draws boxes and
+// spinners with ASCII characters.
+func (t Theme) Text(s string) string {
+	if len(t.symbols) == 0 && !t.ascii {
+		return s
+	}
+
+	var b strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if replacement, ok := t.symbols[r]; ok {
+			b.WriteString(replacement)
+			continue
+		}
+		if !t.ascii {
+			b.WriteRune(r)
+			continue
+		}
+
+		switch {
+		case r == '️': // Emoji presentation of the previous symbol
+		case isEmoji(r):
+			if i+1 < len(runes) && runes[i+1] == '️' {
+				i++
+			}
+			if i+1 < len(runes) && runes[i+1] == ' ' {
+				i++
+			}
+		case r >= 0x2500 && r <= 0x257F: // Box drawing
+			b.WriteString(boxDrawing(r))
+		case r >= 0x2800 && r <= 0x28FF: // Braille, used by spinners
+			b.WriteByte('*')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isEmoji reports whether r is a pictograph, drawn as an emoji
+func isEmoji(r rune) bool {
+	return r >= 0x1F000 || (r >= 0x2600 && r <= 0x27BF) || (r >= 0x2300 && r <= 0x23FF) || r == 0x2139
+}
+
+// boxDrawing returns the ASCII character drawing like a box drawing one
+func boxDrawing(r rune) string {
+	switch {
+	case strings.ContainsRune("─━═┄┅┈┉╌╍", r):
+		return "-"
+	case strings.ContainsRune("│┃║┆┇┊┋╎╏", r):
+		return "|"
+	default:
+		return "+"
+	}
+}
+
+// writer replaces the symbols of what is written to it
+type writer struct {
+	theme Theme
+	w     io.Writer
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, w.theme.Text(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Writer returns a writer writing to w with the theme's symbols
+func (t Theme) Writer(w io.Writer) io.Writer {
+	if len(t.symbols) == 0 && !t.ascii {
+		return w
+	}
+	return writer{theme: t, w: w}
+}
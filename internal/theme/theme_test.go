@@ -0,0 +1,125 @@
+package theme
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"default", "default", false},
+		{"colorblind", "colorblind", false},
+		{"Monochrome", "monochrome", false},
+		{"ascii", "ascii", false},
+		{"neon", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got.Name != tt.expected {
+			t.Errorf("Parse(%q) = %q, want %q", tt.input, got.Name, tt.expected)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		theme    string
+		noColor  string
+		expected string
+	}{
+		{"default", "", "", "default"},
+		{"NO_COLOR", "", "1", "monochrome"},
+		{"named theme wins over NO_COLOR", "ascii", "1", "ascii"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			got, err := Resolve(tt.theme)
+			if err != nil {
+				t.Fatalf("Resolve(%q) returned error: %v", tt.theme, err)
+			}
+			if got.Name != tt.expected {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.theme, got.Name, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStyle(t *testing.T) {
+	s := lipgloss.NewStyle().Foreground(Success).Background(Primary).BorderForeground(Danger)
+
+	if got := Default.Style(s).GetForeground(); got != Success {
+		t.Errorf("Default theme changed the foreground to %v", got)
+	}
+
+	colorblind := Colorblind.Style(s)
+	if colorblind.GetForeground() == Success || colorblind.GetBackground() == Primary || colorblind.GetBorderTopForeground() == Danger {
+		t.Error("Colorblind theme kept default palette colors")
+	}
+
+	mono := Monochrome.Style(s)
+	for _, c := range []lipgloss.TerminalColor{mono.GetForeground(), mono.GetBackground(), mono.GetBorderTopForeground()} {
+		if _, ok := c.(lipgloss.NoColor); !ok {
+			t.Errorf("Monochrome theme kept color %v", c)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		theme    Theme
+		input    string
+		expected string
+	}{
+		{Default, "✅ Done 🔴", "✅ Done 🔴"},
+		{Monochrome, "✅ Done 🔴", "✅ Done 🔴"},
+		{Colorblind, "🟢 🟡 🔴 node_modules", "🔵 🔶 🔺 node_modules"},
+		{ASCII, "✅ Done", "OK Done"},
+		{ASCII, "🟢 Safe  🔴 Dangerous", "[S] Safe  [D] Dangerous"},
+		{ASCII, "🔍 Scanning... ⚠️  Careful", "Scanning... !  Careful"},
+		{ASCII, "╭──╮\n│ Épurer │\n╰──╯", "+--+\n| Épurer |\n+--+"},
+		{ASCII, "⠋ 42% ███░░", "* 42% ###.."},
+		{ASCII, "a → b • c", "a -> b * c"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.theme.Text(tt.input); got != tt.expected {
+			t.Errorf("%s.Text(%q) = %q, want %q", tt.theme.Name, tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := ASCII.Writer(&buf)
+
+	n, err := fmt.Fprint(w, "🧹 Cleaned ✓")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len("🧹 Cleaned ✓") {
+		t.Errorf("Write() = %d, want the length of its input", n)
+	}
+	if buf.String() != "Cleaned *" {
+		t.Errorf("Expected symbols replaced, got %q", buf.String())
+	}
+
+	if Default.Writer(&buf) != &buf {
+		t.Error("Default theme should write to w unchanged")
+	}
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/config"
+)
+
+// sortKey is the order of the items in the list
+type sortKey int
+
+const (
+	sortBySize   sortKey = iota // Largest first
+	sortByAge                   // Least recently modified first
+	sortByDomain                // Alphabetical
+	sortBySafety                // Safest first, then largest
+)
+
+// sortKeys are the keys choosing the order of the list
+var sortKeys = map[string]sortKey{
+	"s": sortBySize,
+	"a": sortByAge,
+	"d": sortByDomain,
+	"r": sortBySafety,
+}
+
+// name returns the message key naming the order
+func (k sortKey) name() string {
+	switch k {
+	case sortByAge:
+		return "tui.sort_age"
+	case sortByDomain:
+		return "tui.sort_domain"
+	case sortBySafety:
+		return "tui.sort_safety"
+	default:
+		return "tui.sort_size"
+	}
+}
+
+// lastModified returns when the most recently modified of targets was
+// changed, going by the modification time of their paths
+func lastModified(targets []cleaner.CleanTarget) time.Time {
+	var latest time.Time
+	for _, target := range targets {
+		if info, err := os.Stat(target.Path); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// riskiest returns the highest safety level among targets
+func riskiest(targets []cleaner.CleanTarget) config.SafetyLevel {
+	safety := config.Safe
+	for _, target := range targets {
+		safety = max(safety, target.Safety)
+	}
+	return safety
+}
+
+// sortItems orders items by key, ties going to the largest then by domain
+func sortItems(items []CleanItem, key sortKey) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		switch key {
+		case sortByAge:
+			if !a.modified.Equal(b.modified) {
+				return a.modified.Before(b.modified)
+			}
+		case sortByDomain:
+			return a.domain < b.domain
+		case sortBySafety:
+			if a.safety != b.safety {
+				return a.safety < b.safety
+			}
+		}
+		if a.size != b.size {
+			return a.size > b.size
+		}
+		return a.domain < b.domain
+	})
+}
+
+// matchTarget reports whether a target's path, category or description
+// contains filter, ignoring case
+func matchTarget(target cleaner.CleanTarget, filter string) bool {
+	filter = strings.ToLower(filter)
+	for _, field := range []string{target.Path, target.Category, target.Description} {
+		if strings.Contains(strings.ToLower(field), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns the targets of an item the filter matches: all of them
+// when it matches the domain
+func (i CleanItem) matches(filter string) []cleaner.CleanTarget {
+	if filter == "" || strings.Contains(strings.ToLower(i.domain), strings.ToLower(filter)) {
+		return i.targets
+	}
+	matched := []cleaner.CleanTarget{}
+	for _, target := range i.targets {
+		if matchTarget(target, filter) {
+			matched = append(matched, target)
+		}
+	}
+	return matched
+}
+
+// chosen returns the selected items the filter shows, each with only the
+// targets the filter matches: what confirming cleans. Selected items the
+// filter hides are left alone.
+func (m Model) chosen() []CleanItem {
+	items := []CleanItem{}
+	for _, i := range m.visible {
+		item := m.items[i]
+		if !item.selected {
+			continue
+		}
+		item.targets = item.matches(m.filter)
+		item.size = 0
+		for _, target := range item.targets {
+			item.size += target.SizeBytes
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// largestTargets returns the n largest of targets
+func largestTargets(targets []cleaner.CleanTarget, n int) []cleaner.CleanTarget {
+	sorted := append([]cleaner.CleanTarget(nil), targets...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].SizeBytes > sorted[j].SizeBytes
+	})
+	return sorted[:min(n, len(sorted))]
+}
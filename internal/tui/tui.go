@@ -1,28 +1,37 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 
 	"github.com/0SansNom/epurer/internal/cleaner"
+	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/i18n"
+	"github.com/0SansNom/epurer/internal/quarantine"
+	"github.com/0SansNom/epurer/internal/theme"
 	"github.com/0SansNom/epurer/pkg/utils"
 )
 
 // Colors
 var (
-	primaryColor   = lipgloss.Color("#7C3AED")
-	secondaryColor = lipgloss.Color("#06B6D4")
-	successColor   = lipgloss.Color("#10B981")
-	warningColor   = lipgloss.Color("#F59E0B")
-	dangerColor    = lipgloss.Color("#EF4444")
-	mutedColor     = lipgloss.Color("#6B7280")
+	primaryColor   = theme.Primary
+	secondaryColor = theme.Secondary
+	successColor   = theme.Success
+	warningColor   = theme.Warning
+	dangerColor    = theme.Danger
+	mutedColor     = theme.Muted
 )
 
 // Styles
@@ -42,6 +51,9 @@ var (
 	mutedStyle = lipgloss.NewStyle().
 			Foreground(mutedColor)
 
+	errorStyle = lipgloss.NewStyle().
+			Foreground(dangerColor)
+
 	helpStyle = lipgloss.NewStyle().
 			Foreground(mutedColor).
 			MarginTop(1)
@@ -54,7 +66,7 @@ var (
 			MarginBottom(1)
 
 	statusBar = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
+			Foreground(theme.White).
 			Background(primaryColor).
 			Padding(0, 1).
 			MarginTop(1)
@@ -67,6 +79,9 @@ type CleanItem struct {
 	size        int64
 	selected    bool
 	targets     []cleaner.CleanTarget
+	modified    time.Time          // When the most recently modified target was changed
+	safety      config.SafetyLevel // Riskiest safety level of the targets
+	lang        i18n.Lang
 }
 
 func (i CleanItem) Title() string {
@@ -78,7 +93,21 @@ func (i CleanItem) Title() string {
 }
 
 func (i CleanItem) Description() string {
-	return fmt.Sprintf("%s • %d items", utils.FormatBytes(i.size), len(i.targets))
+	desc := i.lang.T("tui.item", utils.FormatBytes(i.size), len(i.targets))
+
+	// What the domain's dependencies and build outputs would cost to get back
+	var download int64
+	var rebuild time.Duration
+	for _, target := range i.targets {
+		if cost, ok := target.RebuildCost(); ok {
+			download += cost.Download
+			rebuild += cost.Time
+		}
+	}
+	if rebuild > 0 {
+		desc += " • " + i.lang.T("tui.rebuild", utils.FormatBytes(download), utils.FormatDuration(rebuild))
+	}
+	return desc
 }
 
 func (i CleanItem) FilterValue() string {
@@ -93,6 +122,7 @@ const (
 	StateConfirm
 	StateCleaning
 	StateDone
+	StateExplain // Explaining the targets of the current item
 )
 
 // Model is the main Bubble Tea model
@@ -100,13 +130,34 @@ type Model struct {
 	state       State
 	list        list.Model
 	items       []CleanItem
+	visible     []int // Items the filter shows, as listed
+	sortBy      sortKey
+	filter      string // Shows only the domains and targets it matches
+	filtering   bool   // Typing the filter
+	filterInput textinput.Model
 	spinner     spinner.Model
 	progress    progress.Model
 	cleaning    bool
 	cleanIndex  int
 	totalItems  int
 	cleanedSize int64
+	movedSize   int64                      // Bytes moved to the quarantine
+	quarantine  *quarantine.Store          // Where deleted targets are moved, if set
+	cleaners    map[string]cleaner.Cleaner // By domain, to clean the selected items
+	workers     int
+	results     chan cleanedMsg
+	removals    chan removingMsg
+	log         []cleaner.CleanResult // Targets cleaned so far, in order
+	pause       *cleaner.Pause
+	cancel      context.CancelFunc     // Stops cleaning
+	aborted     bool                   // Cleaning was stopped before the end
+	removing    cleaner.RemoveProgress // Latest progress of a large target being deleted
+	started     time.Time              // When cleaning started
+	totalSize   int64                  // Estimated size of the targets to clean
+	doneSize    int64                  // Estimated size of the targets cleaned so far
 	dryRun      bool
+	lang        i18n.Lang
+	theme       theme.Theme
 	quitting    bool
 	err         error
 	width       int
@@ -133,22 +184,15 @@ func NewModel(targetsByDomain map[string][]cleaner.CleanTarget, dryRun bool) Mod
 			size:        totalSize,
 			selected:    true, // Selected by default
 			targets:     targets,
+			modified:    lastModified(targets),
+			safety:      riskiest(targets),
 		})
 	}
-
-	// Convert to list.Item slice
-	listItems := make([]list.Item, len(items))
-	for i, item := range items {
-		listItems[i] = item
-	}
+	sortItems(items, sortBySize)
 
 	// Create list
-	delegate := list.NewDefaultDelegate()
-	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Foreground(primaryColor)
-	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Foreground(secondaryColor)
-
-	l := list.New(listItems, delegate, 0, 0)
-	l.Title = "Select domains to clean"
+	l := list.New(nil, newDelegate(theme.Default), 0, 0)
+	l.Title = i18n.English.T("tui.select_title")
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
 	l.Styles.Title = titleStyle
@@ -164,14 +208,136 @@ func NewModel(targetsByDomain map[string][]cleaner.CleanTarget, dryRun bool) Mod
 		progress.WithWidth(40),
 	)
 
-	return Model{
-		state:    StateSelect,
-		list:     l,
-		items:    items,
-		spinner:  s,
-		progress: p,
-		dryRun:   dryRun,
+	// Create filter prompt
+	f := textinput.New()
+	f.Prompt = "/ "
+
+	m := Model{
+		state:       StateSelect,
+		list:        l,
+		items:       items,
+		filterInput: f,
+		spinner:     s,
+		progress:    p,
+		dryRun:      dryRun,
+	}
+	m.setListItems()
+	return m
+}
+
+// WithCleaners returns the model cleaning the selected items with cleaners,
+// up to workers of them at once. Items of a domain with no cleaner are
+// counted as cleaned without freeing anything.
+func (m Model) WithCleaners(cleaners []cleaner.Cleaner, workers int) Model {
+	m.cleaners = make(map[string]cleaner.Cleaner, len(cleaners))
+	for _, c := range cleaners {
+		m.cleaners[c.Name()] = c
+	}
+	m.workers = workers
+	return m
+}
+
+// WithQuarantine returns the model moving the targets it deletes to store
+// instead, when store is set
+func (m Model) WithQuarantine(store *quarantine.Store) Model {
+	m.quarantine = store
+	return m
+}
+
+// WithLang returns the model rendering its text in lang (English by default)
+func (m Model) WithLang(lang i18n.Lang) Model {
+	m.lang = lang
+	for i := range m.items {
+		m.items[i].lang = lang
+	}
+	m.setListItems()
+	return m
+}
+
+// WithTheme returns the model rendered with the colors and symbols of t
+// (the default theme otherwise)
+func (m Model) WithTheme(t theme.Theme) Model {
+	m.theme = t
+	m.list.SetDelegate(newDelegate(t))
+	m.list.Styles.Title = t.Style(titleStyle)
+	m.spinner.Style = t.Style(m.spinner.Style)
+	return m
+}
+
+// newDelegate returns the delegate rendering the list items with the colors
+// of t
+func newDelegate(t theme.Theme) list.DefaultDelegate {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = t.Style(delegate.Styles.SelectedTitle.Foreground(primaryColor))
+	delegate.Styles.SelectedDesc = t.Style(delegate.Styles.SelectedDesc.Foreground(secondaryColor))
+	return delegate
+}
+
+// style returns s with the colors of the model's theme
+func (m Model) style(s lipgloss.Style) lipgloss.Style {
+	return m.theme.Style(s)
+}
+
+// setListItems refreshes the list from the items the filter matches, and
+// its title from the order
+func (m *Model) setListItems() {
+	m.visible = nil
+	listItems := []list.Item{}
+	for j, item := range m.items {
+		if len(item.matches(m.filter)) > 0 {
+			m.visible = append(m.visible, j)
+			listItems = append(listItems, item)
+		}
+	}
+	m.list.SetItems(listItems)
+	m.list.Title = m.lang.T("tui.select_title") + " " + m.lang.T("tui.sorted", m.lang.T(m.sortBy.name()))
+}
+
+// current returns the index in items of the item the cursor is on
+func (m Model) current() (int, bool) {
+	if i := m.list.Index(); i >= 0 && i < len(m.visible) {
+		return m.visible[i], true
+	}
+	return 0, false
+}
+
+// sortList orders the items by key, keeping the cursor on its item
+func (m *Model) sortList(key sortKey) {
+	var domain string
+	if i, ok := m.current(); ok {
+		domain = m.items[i].domain
+	}
+	m.sortBy = key
+	sortItems(m.items, key)
+	m.setListItems()
+	for j, i := range m.visible {
+		if m.items[i].domain == domain {
+			m.list.Select(j)
+		}
+	}
+}
+
+// updateFilter handles a key typed in the filter prompt: enter keeps the
+// filter, esc clears it, and the list follows what is typed
+func (m Model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg.String() {
+	case "enter":
+		m.filtering = false
+		m.filterInput.Blur()
+	case "esc":
+		m.filtering = false
+		m.filterInput.Blur()
+		m.filterInput.SetValue("")
+	default:
+		m.filterInput, cmd = m.filterInput.Update(msg)
 	}
+	if filter := strings.TrimSpace(m.filterInput.Value()); filter != m.filter {
+		m.filter = filter
+		m.setListItems()
+		m.list.Select(0)
+	}
+	return m, cmd
 }
 
 // Init initializes the model
@@ -185,63 +351,78 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch m.state {
 		case StateSelect:
+			if m.filtering {
+				if msg.String() == "ctrl+c" {
+					m.quitting = true
+					return m, tea.Quit
+				}
+				return m.updateFilter(msg)
+			}
+			if key, ok := sortKeys[msg.String()]; ok {
+				m.sortList(key)
+				return m, nil
+			}
 			switch msg.String() {
 			case "q", "ctrl+c":
 				m.quitting = true
 				return m, tea.Quit
+			case "/": // Filter
+				m.filtering = true
+				return m, m.filterInput.Focus()
+			case "esc": // Clear the filter
+				if m.filter != "" {
+					m.filter = ""
+					m.filterInput.SetValue("")
+					m.setListItems()
+				}
+				return m, nil
 			case " ": // Space to toggle selection
-				if i := m.list.Index(); i >= 0 && i < len(m.items) {
+				if i, ok := m.current(); ok {
 					m.items[i].selected = !m.items[i].selected
-					// Update list item
-					listItems := make([]list.Item, len(m.items))
-					for j, item := range m.items {
-						listItems[j] = item
-					}
-					m.list.SetItems(listItems)
+					m.setListItems()
 				}
 			case "enter":
-				// Check if any items are selected
-				hasSelected := false
-				for _, item := range m.items {
-					if item.selected {
-						hasSelected = true
-						break
-					}
-				}
-				if hasSelected {
+				// Only what the filter shows is cleaned
+				if len(m.chosen()) > 0 {
 					m.state = StateConfirm
 				}
-			case "a": // Select all
-				for i := range m.items {
+			case "A": // Select all the filter shows
+				for _, i := range m.visible {
 					m.items[i].selected = true
 				}
-				listItems := make([]list.Item, len(m.items))
-				for j, item := range m.items {
-					listItems[j] = item
-				}
-				m.list.SetItems(listItems)
-			case "n": // Select none
-				for i := range m.items {
+				m.setListItems()
+			case "n": // Select none the filter shows
+				for _, i := range m.visible {
 					m.items[i].selected = false
 				}
-				listItems := make([]list.Item, len(m.items))
-				for j, item := range m.items {
-					listItems[j] = item
+				m.setListItems()
+			case "?": // What is this?
+				if _, ok := m.current(); ok {
+					m.state = StateExplain
+				}
+			}
+		case StateExplain:
+			m.state = StateSelect
+			return m, nil
+		case StateCleaning:
+			switch msg.String() {
+			case "p", " ": // Pause or resume
+				if m.pause != nil {
+					m.pause.Set(!m.pause.Paused())
+				}
+			case "x", "q", "ctrl+c": // Stop, once the deletions in flight finish
+				if m.cancel != nil && !m.aborted {
+					m.aborted = true
+					m.cancel()
 				}
-				m.list.SetItems(listItems)
 			}
+			return m, nil
 		case StateConfirm:
 			switch msg.String() {
-			case "y", "Y":
+			case "y", "Y", "o", "O": // o(ui) in French
 				m.state = StateCleaning
 				m.cleaning = true
-				// Count total items
-				for _, item := range m.items {
-					if item.selected {
-						m.totalItems += len(item.targets)
-					}
-				}
-				return m, m.cleanNext()
+				return m, m.startCleaning()
 			case "n", "N", "q", "ctrl+c":
 				m.state = StateSelect
 			}
@@ -255,7 +436,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.list.SetSize(msg.Width-4, msg.Height-10)
+		m.list.SetSize(msg.Width-4, msg.Height-10-detailsHeight)
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -267,15 +448,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.progress = progressModel.(progress.Model)
 		return m, cmd
 
+	case removingMsg:
+		m.removing = msg.progress
+		return m, m.cleanNext()
+
 	case cleanedMsg:
 		m.cleanedSize += msg.size
+		m.movedSize += msg.result.BytesQuarantined
+		m.doneSize += msg.estimate
 		m.cleanIndex++
+		m.removing = cleaner.RemoveProgress{}
+		if msg.result.Target.Path != "" {
+			m.log = append(m.log, msg.result)
+		}
 		if m.cleanIndex >= m.totalItems {
 			m.state = StateDone
 			m.cleaning = false
 		} else {
 			return m, m.cleanNext()
 		}
+
+	case stoppedMsg:
+		m.state = StateDone
+		m.cleaning = false
+		m.removing = cleaner.RemoveProgress{}
 	}
 
 	// Update list
@@ -284,16 +480,180 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// cleanedMsg reports a target cleaned
 type cleanedMsg struct {
-	size int64
+	size     int64               // Bytes freed
+	estimate int64               // Size the target was estimated at
+	result   cleaner.CleanResult // Unset for targets no cleaner could clean
+}
+
+// stoppedMsg reports that cleaning stopped before every target was cleaned
+type stoppedMsg struct{}
+
+// removingMsg reports how far the removal of a large target has gone
+type removingMsg struct {
+	progress cleaner.RemoveProgress
+}
+
+// startCleaning cleans the targets of the selected items the filter shows,
+// those it matches only, in the background, a cleanedMsg being sent for
+// each one as soon as it is done, and removingMsg as large targets are
+// deleted
+func (m *Model) startCleaning() tea.Cmd {
+	jobs := []cleaner.CleanJob{}
+	skipped := 0
+	for _, item := range m.chosen() {
+		m.totalItems += len(item.targets)
+		c, ok := m.cleaners[item.domain]
+		if !ok {
+			skipped += len(item.targets)
+			continue
+		}
+		for _, target := range item.targets {
+			jobs = append(jobs, cleaner.CleanJob{Cleaner: c, Target: target})
+			m.totalSize += target.SizeBytes
+		}
+	}
+
+	// Buffered for every target, so that cleaning never waits on the UI
+	results := make(chan cleanedMsg, m.totalItems)
+	m.results = results
+	// Progress the UI is not ready for is dropped, the next one will do
+	removals := make(chan removingMsg, 1)
+	m.removals = removals
+	m.pause = &cleaner.Pause{}
+	ctx := cleaner.WithPause(context.Background(), m.pause)
+	if m.quarantine != nil {
+		ctx = cleaner.WithQuarantine(ctx, m.quarantine)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+	go func(workers int, dryRun bool) {
+		defer close(results)
+		for range skipped {
+			results <- cleanedMsg{}
+		}
+		ctx := cleaner.WithRemoveProgress(ctx, func(progress cleaner.RemoveProgress) {
+			select {
+			case removals <- removingMsg{progress: progress}:
+			default:
+			}
+		})
+		cleaner.CleanConcurrently(ctx, jobs, workers, dryRun, func(r cleaner.JobResult) {
+			results <- cleanedMsg{size: r.Result.BytesFreed, estimate: jobs[r.Index].Target.SizeBytes, result: r.Result}
+		})
+	}(m.workers, m.dryRun)
+	m.started = time.Now()
+
+	return m.cleanNext()
 }
 
+// cleanNext waits for the next target to be cleaned, or for progress in the
+// removal of a large one. Once cleaning stops before the end, it reports it.
 func (m Model) cleanNext() tea.Cmd {
+	results, removals := m.results, m.removals
 	return func() tea.Msg {
-		// Simulate cleaning (in real implementation, this would actually clean)
-		// For now, just return a message
-		return cleanedMsg{size: 1024}
+		select {
+		case msg, ok := <-results:
+			if !ok {
+				return stoppedMsg{}
+			}
+			return msg
+		case msg := <-removals:
+			return msg
+		}
+	}
+}
+
+// explainView renders what the kinds of targets of the current item are
+func (m Model) explainView() string {
+	var b strings.Builder
+	i, _ := m.current()
+	item := m.items[i]
+
+	b.WriteString("\n")
+	b.WriteString(m.style(titleStyle).Render(m.lang.T("explain.title") + " " + item.domain))
+	b.WriteString("\n\n")
+
+	categories := cleaner.ExplainCategories(item.targets)
+	if len(categories) == 0 {
+		b.WriteString(m.style(mutedStyle).Render(m.lang.T("explain.none")))
+		b.WriteString("\n\n")
+	}
+	for _, category := range categories {
+		b.WriteString(lipgloss.NewStyle().Bold(true).Render(category.Example))
+		b.WriteString("  ")
+		b.WriteString(m.style(mutedStyle).Render(m.lang.T("explain.targets", category.Targets, utils.FormatBytes(category.SizeBytes))))
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  %s: %s\n", m.lang.T("explain.what"), category.Explanation.What))
+		b.WriteString(fmt.Sprintf("  %s: %s\n", m.lang.T("explain.regenerates"), category.Explanation.Regenerates))
+		b.WriteString(fmt.Sprintf("  %s: %s\n\n", m.lang.T("explain.consequence"), category.Explanation.Consequence))
+	}
+	return b.String()
+}
+
+// detailsHeight is the number of lines of the details pane
+const detailsHeight = maxDetails + 2
+
+// maxDetails is the number of targets the details pane lists
+const maxDetails = 5
+
+// detailsView renders the largest targets of the current item the filter
+// matches
+func (m Model) detailsView() string {
+	i, ok := m.current()
+	if !ok {
+		return ""
+	}
+	item := m.items[i]
+
+	var b strings.Builder
+	b.WriteString(m.style(mutedStyle).Render(m.lang.T("tui.largest", item.domain)))
+	b.WriteString("\n")
+	for _, target := range largestTargets(item.matches(m.filter), maxDetails) {
+		b.WriteString(fmt.Sprintf("  %s %10s  %s\n", target.Safety.Icon(), utils.FormatBytes(target.SizeBytes), target.Path))
+	}
+	return b.String()
+}
+
+// logLines is the number of targets the log pane lists
+const logLines = 8
+
+// logView renders the last targets of results, each with what it freed or
+// why it failed
+func (m Model) logView(results []cleaner.CleanResult) string {
+	var b strings.Builder
+	if hidden := len(results) - logLines; hidden > 0 {
+		b.WriteString(m.style(mutedStyle).Render(m.lang.T("tui.log_more", hidden)))
+		b.WriteString("\n")
+		results = results[hidden:]
+	}
+	for _, result := range results {
+		switch {
+		case !result.Success && result.Error != nil:
+			b.WriteString(m.style(errorStyle).Render("  ✗ ") + result.Target.Path + ": " + result.Error.Error())
+		case !result.Success:
+			b.WriteString(m.style(errorStyle).Render("  ✗ ") + result.Target.Path)
+		case m.dryRun:
+			b.WriteString(m.style(selectedStyle).Render("  ✓ ") + m.lang.T("tui.log_dry_run", result.Target.Path, utils.FormatBytes(result.BytesFreed)))
+		case result.BytesArchived > 0:
+			b.WriteString(m.style(selectedStyle).Render("  ✓ ") + m.lang.T("tui.log_archived", result.Target.Path, utils.FormatBytes(result.BytesArchived), result.Target.ArchiveDir))
+		case result.BytesQuarantined > 0:
+			b.WriteString(m.style(selectedStyle).Render("  ✓ ") + m.lang.T("tui.log_moved", result.Target.Path, utils.FormatBytes(result.BytesQuarantined)))
+		default:
+			b.WriteString(m.style(selectedStyle).Render("  ✓ ") + m.lang.T("tui.log_freed", result.Target.Path, utils.FormatBytes(result.BytesFreed)))
+		}
+		b.WriteString("\n")
 	}
+	return b.String()
+}
+
+// remaining estimates the time cleaning has left at the pace of the bytes
+// cleaned so far, a large target being deleted counting for the share of its
+// files already gone
+func (m Model) remaining() (time.Duration, bool) {
+	done := m.doneSize + int64(m.removing.Fraction()*float64(m.removing.Target.SizeBytes))
+	return utils.EstimateRemaining(time.Since(m.started), done, m.totalSize)
 }
 
 // View renders the model
@@ -305,108 +665,184 @@ func (m Model) View() string {
 	var b strings.Builder
 
 	// Header
+	// Symbols are replaced before boxing, which keeps the box aligned
 	header := lipgloss.JoinVertical(
 		lipgloss.Center,
-		titleStyle.Render("🧹 Épurer"),
-		subtitleStyle.Render("Interactive cleanup mode"),
+		m.style(titleStyle).Render(m.theme.Text("🧹 Épurer")),
+		m.style(subtitleStyle).Render(m.theme.Text(m.lang.T("tui.subtitle"))),
 	)
-	b.WriteString(headerBox.Render(header))
+	b.WriteString(m.style(headerBox).Render(header))
 	b.WriteString("\n")
 
 	switch m.state {
 	case StateSelect:
 		b.WriteString(m.list.View())
 		b.WriteString("\n")
+		if m.filtering || m.filter != "" {
+			b.WriteString(m.filterInput.View())
+			b.WriteString("\n")
+		}
+		b.WriteString(m.detailsView())
 
 		// Calculate total selected size
+		chosen := m.chosen()
 		var totalSize int64
-		var selectedCount int
-		for _, item := range m.items {
-			if item.selected {
-				totalSize += item.size
-				selectedCount++
-			}
+		for _, item := range chosen {
+			totalSize += item.size
 		}
+		selectedCount := len(chosen)
 
 		// Status bar
-		status := fmt.Sprintf(" Selected: %d domains • %s ", selectedCount, utils.FormatBytes(totalSize))
-		b.WriteString(statusBar.Render(status))
+		status := " " + m.lang.T("tui.selected", selectedCount, utils.FormatBytes(totalSize)) + " "
+		b.WriteString(m.style(statusBar).Render(status))
 		b.WriteString("\n")
 
 		// Help
-		help := "↑/↓: navigate • space: toggle • a: all • n: none • enter: confirm • q: quit"
-		b.WriteString(helpStyle.Render(help))
+		help := m.lang.T("tui.help_select")
+		b.WriteString(m.style(helpStyle).Render(help))
 
 	case StateConfirm:
+		chosen := m.chosen()
 		var totalSize int64
-		var selectedCount int
-		for _, item := range m.items {
-			if item.selected {
-				totalSize += item.size
-				selectedCount++
-			}
+		for _, item := range chosen {
+			totalSize += item.size
 		}
+		selectedCount := len(chosen)
 
-		confirmMsg := fmt.Sprintf(
-			"Clean %d domains (%s)?",
-			selectedCount,
-			utils.FormatBytes(totalSize),
-		)
+		confirmMsg := m.lang.T("tui.confirm", selectedCount, utils.FormatBytes(totalSize))
 		if m.dryRun {
-			confirmMsg += " (DRY RUN)"
+			confirmMsg += " " + m.lang.T("tui.dry_run_tag")
 		}
 
 		b.WriteString("\n")
-		b.WriteString(lipgloss.NewStyle().
+		b.WriteString(m.style(lipgloss.NewStyle().
 			Foreground(warningColor).
-			Bold(true).
+			Bold(true)).
 			Render("⚠️  " + confirmMsg))
 		b.WriteString("\n\n")
-		b.WriteString(helpStyle.Render("y: yes • n: no"))
+
+		// Selected items the filter hides, and targets it doesn't match,
+		// stay as they are
+		if m.filter != "" {
+			targetCount, hidden := 0, 0
+			for _, item := range chosen {
+				targetCount += len(item.targets)
+			}
+			for _, item := range m.items {
+				if item.selected && len(item.matches(m.filter)) == 0 {
+					hidden++
+				}
+			}
+			b.WriteString(m.lang.T("tui.filtered", targetCount, m.filter, hidden))
+			b.WriteString("\n\n")
+		}
+
+		// What cleaning the selection leaves to do
+		targets := []cleaner.CleanTarget{}
+		for _, item := range chosen {
+			targets = append(targets, item.targets...)
+		}
+		if consequences := cleaner.Consequences(targets); len(consequences) > 0 {
+			b.WriteString(m.lang.T("consequences.title"))
+			b.WriteString("\n")
+			for _, consequence := range consequences {
+				b.WriteString(m.style(mutedStyle).Render("  • " + consequence.Summary))
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(m.style(helpStyle).Render(m.lang.T("tui.help_confirm")))
+
+	case StateExplain:
+		b.WriteString(m.explainView())
+		b.WriteString(m.style(helpStyle).Render(m.lang.T("tui.help_explain")))
 
 	case StateCleaning:
 		b.WriteString("\n")
-		b.WriteString(m.spinner.View())
-		b.WriteString(" Cleaning...")
+		switch {
+		case m.aborted:
+			b.WriteString(m.spinner.View())
+			b.WriteString(" " + m.lang.T("tui.stopping"))
+		case m.pause != nil && m.pause.Paused():
+			b.WriteString("⏸  " + m.lang.T("tui.paused"))
+		default:
+			b.WriteString(m.spinner.View())
+			b.WriteString(" " + m.lang.T("tui.cleaning"))
+		}
 		b.WriteString("\n\n")
 
-		percent := float64(m.cleanIndex) / float64(m.totalItems)
-		b.WriteString(m.progress.ViewAs(percent))
+		// A large target being deleted moves the bar along before it is done
+		percent := (float64(m.cleanIndex) + m.removing.Fraction()) / float64(m.totalItems)
+		b.WriteString(m.progress.ViewAs(min(1, percent)))
 		b.WriteString("\n")
 
-		status := fmt.Sprintf("Cleaned: %d/%d items • %s freed",
-			m.cleanIndex, m.totalItems, utils.FormatBytes(m.cleanedSize))
-		b.WriteString(mutedStyle.Render(status))
+		status := m.lang.T("tui.cleaned", m.cleanIndex, m.totalItems, utils.FormatBytes(m.cleanedSize))
+		if remaining, ok := m.remaining(); ok && remaining > 0 {
+			status += " • " + m.lang.T("progress.eta", utils.FormatDuration(remaining.Round(time.Second)))
+		}
+		b.WriteString(m.style(mutedStyle).Render(status))
+		if m.removing.Total > 0 {
+			b.WriteString("\n")
+			removing := m.lang.T("tui.removing", filepath.Base(m.removing.Target.Path), utils.FormatCount(m.removing.Files), utils.FormatCount(m.removing.Total))
+			b.WriteString(m.style(mutedStyle).Render(removing))
+		}
+		b.WriteString("\n\n")
+		b.WriteString(m.logView(m.log))
+		b.WriteString(m.style(helpStyle).Render(m.lang.T("tui.help_clean")))
 
 	case StateDone:
 		b.WriteString("\n")
-		if m.dryRun {
-			b.WriteString(lipgloss.NewStyle().
+		switch {
+		case m.aborted:
+			b.WriteString(m.style(lipgloss.NewStyle().
+				Foreground(warningColor).
+				Bold(true)).
+				Render("⚠️  " + m.lang.T("tui.stopped", m.cleanIndex, m.totalItems)))
+		case m.dryRun:
+			b.WriteString(m.style(lipgloss.NewStyle().
 				Foreground(secondaryColor).
-				Bold(true).
-				Render("✨ Dry run complete!"))
-		} else {
-			b.WriteString(lipgloss.NewStyle().
+				Bold(true)).
+				Render("✨ " + m.lang.T("tui.dry_run_done")))
+		default:
+			b.WriteString(m.style(lipgloss.NewStyle().
 				Foreground(successColor).
-				Bold(true).
-				Render("✅ Cleaning complete!"))
+				Bold(true)).
+				Render("✅ " + m.lang.T("tui.done")))
 		}
 		b.WriteString("\n\n")
 
-		summary := fmt.Sprintf("💾 Space freed: %s\n📁 Items cleaned: %d",
-			utils.FormatBytes(m.cleanedSize),
-			m.cleanIndex)
+		summary := m.lang.T("tui.summary", utils.FormatBytes(m.cleanedSize), m.cleanIndex)
+		if m.movedSize > 0 {
+			summary += "\n" + m.lang.T("tui.moved", utils.FormatBytes(m.movedSize))
+		}
 		b.WriteString(summary)
 		b.WriteString("\n\n")
-		b.WriteString(helpStyle.Render("Press enter or q to exit"))
+
+		// What went wrong stays on screen once cleaning is over
+		failed := []cleaner.CleanResult{}
+		for _, result := range m.log {
+			if !result.Success {
+				failed = append(failed, result)
+			}
+		}
+		if len(failed) > 0 {
+			b.WriteString(m.lang.T("tui.failed", len(failed)))
+			b.WriteString("\n")
+			b.WriteString(m.logView(failed))
+		}
+		b.WriteString(m.style(helpStyle).Render(m.lang.T("tui.help_done")))
 	}
 
-	return b.String()
+	return m.theme.Text(b.String())
 }
 
-// Run starts the TUI
-func Run(targetsByDomain map[string][]cleaner.CleanTarget, dryRun bool) error {
-	m := NewModel(targetsByDomain, dryRun)
+// Run starts the TUI, moving the targets it deletes to store if set
+func Run(targetsByDomain map[string][]cleaner.CleanTarget, cleaners []cleaner.Cleaner, workers int, dryRun bool, store *quarantine.Store, lang i18n.Lang, t theme.Theme) error {
+	if t.IsMonochrome() {
+		// Also drops the colors of the list, spinner and progress bar
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	m := NewModel(targetsByDomain, dryRun).WithCleaners(cleaners, workers).WithQuarantine(store).WithLang(lang).WithTheme(t)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
@@ -419,6 +855,8 @@ type keyMap struct {
 	Toggle key.Binding
 	All    key.Binding
 	None   key.Binding
+	Sort   key.Binding
+	Filter key.Binding
 	Enter  key.Binding
 	Quit   key.Binding
 }
@@ -438,13 +876,21 @@ func newKeyMap() keyMap {
 			key.WithHelp("space", "toggle"),
 		),
 		All: key.NewBinding(
-			key.WithKeys("a"),
-			key.WithHelp("a", "select all"),
+			key.WithKeys("A"),
+			key.WithHelp("A", "select all"),
 		),
 		None: key.NewBinding(
 			key.WithKeys("n"),
 			key.WithHelp("n", "select none"),
 		),
+		Sort: key.NewBinding(
+			key.WithKeys("s", "a", "d", "r"),
+			key.WithHelp("s/a/d/r", "sort by size, age, domain, safety"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
 		Enter: key.NewBinding(
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "confirm"),
@@ -1,13 +1,18 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/0SansNom/epurer/internal/cleaner"
 	"github.com/0SansNom/epurer/internal/config"
+	"github.com/0SansNom/epurer/internal/i18n"
 )
 
 // =============================================================================
@@ -65,6 +70,22 @@ func TestCleanItem_Description(t *testing.T) {
 	}
 }
 
+func TestCleanItem_Description_RebuildCost(t *testing.T) {
+	item := CleanItem{
+		domain: "Frontend",
+		size:   310_000_000,
+		targets: []cleaner.CleanTarget{
+			{Path: "/app/node_modules", Category: "node_modules", SizeBytes: 300_000_000},
+			{Path: "/cache/npm", Category: "npm_cache", SizeBytes: 10_000_000},
+		},
+	}
+
+	desc := item.Description()
+	if !strings.Contains(desc, "rebuild ~105 MB download, ~20.0s") {
+		t.Errorf("Description should contain the rebuild cost, got: %s", desc)
+	}
+}
+
 func TestCleanItem_FilterValue(t *testing.T) {
 	item := CleanItem{
 		domain: "DevOps",
@@ -292,13 +313,13 @@ func TestModel_Update_SelectAll(t *testing.T) {
 		model.items[i].selected = false
 	}
 
-	// Press 'a' to select all
-	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	// Press 'A' to select all
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
 	m := newModel.(Model)
 
 	for _, item := range m.items {
 		if !item.selected {
-			t.Errorf("Item %s should be selected after 'a' press", item.domain)
+			t.Errorf("Item %s should be selected after 'A' press", item.domain)
 		}
 	}
 }
@@ -321,6 +342,172 @@ func TestModel_Update_SelectNone(t *testing.T) {
 	}
 }
 
+func TestModel_Update_Explain(t *testing.T) {
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"Frontend": {{Path: "/a/node_modules", Category: "node_modules", Description: "node_modules dependencies", SizeBytes: 1024}},
+	}, false)
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m := newModel.(Model)
+	if m.state != StateExplain {
+		t.Fatalf("State should be StateExplain after '?', got %d", m.state)
+	}
+	if view := m.View(); !strings.Contains(view, "node_modules dependencies") || !strings.Contains(view, "If deleted") {
+		t.Errorf("View should explain the item's targets, got:\n%s", view)
+	}
+
+	// Any key goes back
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if newModel.(Model).state != StateSelect {
+		t.Error("Expected any key to go back to the selection")
+	}
+}
+
+func TestModel_Update_Sort(t *testing.T) {
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"Backend":  {{Path: "/b", SizeBytes: 1024, Safety: config.Dangerous}},
+		"Frontend": {{Path: "/f", SizeBytes: 4096, Safety: config.Moderate}},
+		"System":   {{Path: "/s", SizeBytes: 2048, Safety: config.Safe}},
+	}, false)
+
+	domains := func(m Model) string {
+		names := []string{}
+		for _, i := range m.visible {
+			names = append(names, m.items[i].domain)
+		}
+		return strings.Join(names, ",")
+	}
+
+	if got := domains(model); got != "Frontend,System,Backend" {
+		t.Errorf("Expected the largest first, got %s", got)
+	}
+
+	tests := []struct {
+		key      rune
+		expected string
+	}{
+		{'d', "Backend,Frontend,System"},
+		{'r', "System,Frontend,Backend"},
+		{'s', "Frontend,System,Backend"},
+	}
+	for _, tt := range tests {
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tt.key}})
+		model = newModel.(Model)
+		if got := domains(model); got != tt.expected {
+			t.Errorf("After '%c' expected %s, got %s", tt.key, tt.expected, got)
+		}
+	}
+}
+
+func TestSortItems_Age(t *testing.T) {
+	now := time.Now()
+	items := []CleanItem{
+		{domain: "Recent", size: 1, modified: now},
+		{domain: "Stale", size: 1, modified: now.AddDate(0, -6, 0)},
+	}
+
+	sortItems(items, sortByAge)
+
+	if items[0].domain != "Stale" {
+		t.Errorf("Expected the least recently modified first, got %s", items[0].domain)
+	}
+}
+
+func TestModel_Update_Filter(t *testing.T) {
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"Frontend": {
+			{Path: "/work/app/node_modules", Category: "node_modules", SizeBytes: 4096},
+			{Path: "/cache/yarn", Category: "yarn_cache", SizeBytes: 1024},
+		},
+		"Backend": {{Path: "/cache/pip", Category: "pip_cache", SizeBytes: 2048}},
+	}, false)
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	model = newModel.(Model)
+	if !model.filtering {
+		t.Fatal("Expected '/' to open the filter prompt")
+	}
+	for _, r := range "yarn" {
+		newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = newModel.(Model)
+	}
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = newModel.(Model)
+
+	if model.filtering || model.filter != "yarn" {
+		t.Fatalf("Expected filter yarn to be kept, got %q (filtering %v)", model.filter, model.filtering)
+	}
+	if len(model.visible) != 1 || model.items[model.visible[0]].domain != "Frontend" {
+		t.Fatalf("Expected only Frontend to match, got %v", model.visible)
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "/cache/yarn") || strings.Contains(view, "/work/app/node_modules") {
+		t.Errorf("Expected the details pane to list only the matching targets:\n%s", view)
+	}
+
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = newModel.(Model)
+	if model.filter != "" || len(model.visible) != 2 {
+		t.Errorf("Expected esc to clear the filter, got %q showing %d items", model.filter, len(model.visible))
+	}
+}
+
+func TestModel_Chosen_Filter(t *testing.T) {
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"Frontend": {
+			{Path: "/work/app/node_modules", Category: "node_modules", SizeBytes: 4096},
+			{Path: "/cache/yarn", Category: "yarn_cache", SizeBytes: 1024},
+		},
+		"Backend": {{Path: "/cache/pip", Category: "pip_cache", SizeBytes: 2048}},
+	}, false)
+	model.filter = "yarn"
+	model.setListItems()
+
+	// Both items start selected, but only the matching target is chosen
+	chosen := model.chosen()
+	if len(chosen) != 1 || len(chosen[0].targets) != 1 || chosen[0].targets[0].Path != "/cache/yarn" || chosen[0].size != 1024 {
+		t.Fatalf("Expected only the yarn cache to be chosen, got %+v", chosen)
+	}
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = newModel.(Model)
+	if model.state != StateConfirm {
+		t.Fatal("Expected enter to ask for confirmation")
+	}
+	view := model.View()
+	if !strings.Contains(view, "1.0 kB") || !strings.Contains(view, `"yarn"`) {
+		t.Errorf("Expected the confirmation to cover the filtered targets only:\n%s", view)
+	}
+}
+
+func TestModel_View_Details(t *testing.T) {
+	targets := []cleaner.CleanTarget{}
+	for i := 1; i <= 7; i++ {
+		targets = append(targets, cleaner.CleanTarget{Path: fmt.Sprintf("/cache/%d", i), SizeBytes: int64(i) * 1024})
+	}
+	model := NewModel(map[string][]cleaner.CleanTarget{"System": targets}, false)
+
+	view := model.View()
+	if !strings.Contains(view, "Largest in System") {
+		t.Error("View should contain the details pane")
+	}
+	if !strings.Contains(view, "/cache/7") || !strings.Contains(view, "/cache/3") || strings.Contains(view, "/cache/2") {
+		t.Errorf("Expected the 5 largest targets in the details pane:\n%s", view)
+	}
+}
+
+func TestModel_View_ConfirmConsequences(t *testing.T) {
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"Frontend": {{Path: "/a/node_modules", Category: "node_modules", SizeBytes: 1024}},
+	}, false)
+	model.state = StateConfirm
+
+	if view := model.View(); !strings.Contains(view, "reinstall node_modules in 1 projects") {
+		t.Errorf("Confirmation should list what is left to do, got:\n%s", view)
+	}
+}
+
 func TestModel_Update_EnterWithSelection(t *testing.T) {
 	model := NewModel(map[string][]cleaner.CleanTarget{
 		"Frontend": {{Path: "/test", SizeBytes: 1024}},
@@ -565,6 +752,52 @@ func TestModel_View_StateCleaning(t *testing.T) {
 	}
 }
 
+func TestModel_Update_RemovingMsg(t *testing.T) {
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"Xcode": {{Path: "/DerivedData", SizeBytes: 1024}},
+	}, false)
+	model.state = StateCleaning
+	model.totalItems = 2
+
+	progress := cleaner.RemoveProgress{Target: cleaner.CleanTarget{Path: "/DerivedData"}, Files: 2000, Total: 8000}
+	newModel, _ := model.Update(removingMsg{progress: progress})
+	m := newModel.(Model)
+
+	if m.removing.Fraction() != 0.25 {
+		t.Errorf("Expected a quarter of the target removed, got %v", m.removing.Fraction())
+	}
+	if view := m.View(); !strings.Contains(view, "Deleting DerivedData: 2,000/8,000 files") {
+		t.Errorf("View should show the removal in progress, got:\n%s", view)
+	}
+
+	// The target being done clears its progress
+	newModel, _ = m.Update(cleanedMsg{size: 1024})
+	if m = newModel.(Model); m.removing.Total != 0 {
+		t.Error("Removal progress should be cleared once the target is cleaned")
+	}
+}
+
+func TestModel_View_StateCleaning_ETA(t *testing.T) {
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"Frontend": {{Path: "/test", SizeBytes: 1024}},
+	}, false)
+	model.state = StateCleaning
+	model.totalItems = 4
+	model.cleanIndex = 1
+	model.totalSize = 4000
+	model.doneSize = 1000
+	model.started = time.Now().Add(-time.Minute)
+
+	// A quarter done in a minute leaves about three
+	remaining, ok := model.remaining()
+	if !ok || remaining < 179*time.Second || remaining > 181*time.Second {
+		t.Errorf("Expected about 3 minutes left, got %v (%v)", remaining, ok)
+	}
+	if view := model.View(); !strings.Contains(view, "about 3.0m left") {
+		t.Errorf("View should show the time left, got:\n%s", view)
+	}
+}
+
 func TestModel_View_StateDone(t *testing.T) {
 	model := NewModel(map[string][]cleaner.CleanTarget{}, false)
 	model.state = StateDone
@@ -594,6 +827,31 @@ func TestModel_View_StateDone_DryRun(t *testing.T) {
 	}
 }
 
+func TestModel_WithLang_French(t *testing.T) {
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"Frontend": {{Path: "/test", SizeBytes: 1024}},
+	}, true).WithLang(i18n.French)
+
+	if model.list.Title != "Domaines à nettoyer (par taille)" {
+		t.Errorf("List title = %q, expected the French title", model.list.Title)
+	}
+	if desc := model.items[0].Description(); !strings.Contains(desc, "éléments") {
+		t.Errorf("Item description = %q, expected French", desc)
+	}
+
+	model.state = StateConfirm
+	view := model.View()
+	if !strings.Contains(view, "SIMULATION") || !strings.Contains(view, "o : oui") {
+		t.Errorf("Confirmation should be in French, got:\n%s", view)
+	}
+
+	// o(ui) confirms
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if newModel.(Model).state != StateCleaning {
+		t.Error("Expected 'o' to confirm in French")
+	}
+}
+
 // =============================================================================
 // keyMap Tests
 // =============================================================================
@@ -623,6 +881,161 @@ func TestNewKeyMap(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Cleaning Tests
+// =============================================================================
+
+// fakeCleaner frees the estimated size of every target
+type fakeCleaner struct {
+	name string
+}
+
+func (c fakeCleaner) Name() string                             { return c.name }
+func (c fakeCleaner) Domain() config.Domain                    { return config.DomainSystem }
+func (c fakeCleaner) Detect(ctx context.Context) (bool, error) { return true, nil }
+
+func (c fakeCleaner) Scan(ctx context.Context, cfg *config.Config) ([]cleaner.CleanTarget, error) {
+	return nil, nil
+}
+
+func (c fakeCleaner) Clean(ctx context.Context, targets []cleaner.CleanTarget, dryRun bool) ([]cleaner.CleanResult, error) {
+	results := []cleaner.CleanResult{}
+	for _, t := range targets {
+		results = append(results, cleaner.CleanResult{Target: t, Success: true, BytesFreed: t.SizeBytes})
+	}
+	return results, nil
+}
+
+func TestModel_Cleaning(t *testing.T) {
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"npm":    {{Path: "/a", SizeBytes: 100}, {Path: "/b", SizeBytes: 200}},
+		"Gradle": {{Path: "/c", SizeBytes: 400}},
+		"Other":  {{Path: "/d", SizeBytes: 800}}, // No cleaner
+	}, false).WithCleaners([]cleaner.Cleaner{fakeCleaner{"npm"}, fakeCleaner{"Gradle"}}, 2)
+	model.state = StateConfirm
+
+	next, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	for next.(Model).state == StateCleaning {
+		if cmd == nil {
+			t.Fatal("Cleaning stopped before every target was done")
+		}
+		next, cmd = next.Update(cmd())
+	}
+
+	m := next.(Model)
+	if m.state != StateDone {
+		t.Errorf("State should be StateDone, got %d", m.state)
+	}
+	if m.cleanIndex != 4 {
+		t.Errorf("Expected 4 targets cleaned, got %d", m.cleanIndex)
+	}
+	if m.cleanedSize != 700 {
+		t.Errorf("Expected 700 bytes freed, got %d", m.cleanedSize)
+	}
+}
+
+// failingCleaner fails to clean every target
+type failingCleaner struct{ fakeCleaner }
+
+func (c failingCleaner) Clean(ctx context.Context, targets []cleaner.CleanTarget, dryRun bool) ([]cleaner.CleanResult, error) {
+	return []cleaner.CleanResult{{Target: targets[0], Error: errors.New("permission denied")}}, nil
+}
+
+// blockingCleaner says when it starts cleaning a target, and cleans it once
+// released
+type blockingCleaner struct {
+	fakeCleaner
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c blockingCleaner) Clean(ctx context.Context, targets []cleaner.CleanTarget, dryRun bool) ([]cleaner.CleanResult, error) {
+	c.started <- struct{}{}
+	<-c.release
+	return c.fakeCleaner.Clean(ctx, targets, dryRun)
+}
+
+// runCleaning feeds the model its messages until cleaning is over
+func runCleaning(t *testing.T, next tea.Model, cmd tea.Cmd) Model {
+	t.Helper()
+	for next.(Model).state == StateCleaning {
+		if cmd == nil {
+			t.Fatal("Cleaning stopped before every target was done")
+		}
+		next, cmd = next.Update(cmd())
+	}
+	return next.(Model)
+}
+
+func TestModel_Cleaning_Log(t *testing.T) {
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"npm":    {{Path: "/cache/npm", SizeBytes: 2048}},
+		"Docker": {{Path: "/var/docker", SizeBytes: 1024}},
+	}, false).WithCleaners([]cleaner.Cleaner{fakeCleaner{"npm"}, failingCleaner{fakeCleaner{"Docker"}}}, 1)
+	model.state = StateConfirm
+
+	next, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	next, cmd = next.Update(cmd())
+	m := next.(Model)
+	if m.state != StateCleaning || len(m.log) != 1 {
+		t.Fatalf("Expected one target logged while cleaning, got %d", len(m.log))
+	}
+	if view := m.View(); !strings.Contains(view, "/cache/npm") || !strings.Contains(view, "pause") {
+		t.Errorf("Expected the log pane and its help while cleaning:\n%s", view)
+	}
+
+	m = runCleaning(t, next, cmd)
+	view := m.View()
+	if !strings.Contains(view, "✗ /var/docker: permission denied") || !strings.Contains(view, "1 items failed") {
+		t.Errorf("Expected the failed target once done:\n%s", view)
+	}
+}
+
+func TestModel_Cleaning_Pause(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"npm": {{Path: "/a", SizeBytes: 100}},
+	}, false).WithCleaners([]cleaner.Cleaner{blockingCleaner{fakeCleaner{"npm"}, make(chan struct{}, 1), release}}, 1)
+	model.state = StateConfirm
+
+	next, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	next, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m := next.(Model)
+	if !m.pause.Paused() || !strings.Contains(m.View(), "Paused") {
+		t.Error("Expected 'p' to pause cleaning")
+	}
+
+	next, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if next.(Model).pause.Paused() {
+		t.Error("Expected 'p' again to resume cleaning")
+	}
+}
+
+func TestModel_Cleaning_Abort(t *testing.T) {
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	model := NewModel(map[string][]cleaner.CleanTarget{
+		"npm": {{Path: "/a", SizeBytes: 100}, {Path: "/b", SizeBytes: 200}},
+	}, false).WithCleaners([]cleaner.Cleaner{blockingCleaner{fakeCleaner{"npm"}, started, release}}, 1)
+	model.state = StateConfirm
+
+	next, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	<-started
+	next, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if !strings.Contains(next.(Model).View(), "Stopping") {
+		t.Error("Expected the view to say cleaning is stopping")
+	}
+	close(release)
+
+	m := runCleaning(t, next, cmd)
+	if !m.aborted || m.cleanIndex != 1 || m.cleanedSize != 100 {
+		t.Errorf("Expected the target in flight only, got %d targets and %d bytes", m.cleanIndex, m.cleanedSize)
+	}
+	if view := m.View(); !strings.Contains(view, "Stopped after 1 of 2 items") {
+		t.Errorf("Expected the partial results once done:\n%s", view)
+	}
+}
+
 // =============================================================================
 // cleanedMsg Tests
 // =============================================================================
@@ -705,13 +1118,13 @@ func TestModel_FullSelectWorkflow(t *testing.T) {
 		}
 	}
 
-	// 4. Select all with 'a'
-	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	// 4. Select all with 'A'
+	newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
 	model = newModel.(Model)
 
 	for _, item := range model.items {
 		if !item.selected {
-			t.Errorf("Item %s should be selected after 'a'", item.domain)
+			t.Errorf("Item %s should be selected after 'A'", item.domain)
 		}
 	}
 
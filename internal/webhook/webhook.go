@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/history"
+	"github.com/0SansNom/epurer/pkg/utils"
+)
+
+// timeout is how long posting a summary may take
+const timeout = 10 * time.Second
+
+// maxFailures is how many failures a summary lists
+const maxFailures = 20
+
+// Summary is the JSON summary of a clean run, posted so that runs on many
+// machines can be collected in one place
+type Summary struct {
+	Host            string    `json:"host"`
+	User            string    `json:"user"`
+	Version         string    `json:"version"`
+	Command         string    `json:"command"`
+	StartedAt       time.Time `json:"started_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Interrupted     bool      `json:"interrupted"`
+	BytesFreed      int64     `json:"bytes_freed"`
+	Cleaned         int       `json:"targets_cleaned"`
+	Failed          int       `json:"targets_failed"`
+	Failures        []Failure `json:"failures,omitempty"` // The first maxFailures
+	HooksFailed     int       `json:"hooks_failed"`
+}
+
+// Failure is a target that could not be cleaned
+type Failure struct {
+	Cleaner string `json:"cleaner"`
+	Path    string `json:"path"`
+	Error   string `json:"error"`
+}
+
+// NewSummary summarizes a recorded run
+func NewSummary(run history.Run, host, user, version string) Summary {
+	s := Summary{
+		Host:            host,
+		User:            user,
+		Version:         version,
+		Command:         run.Command,
+		StartedAt:       run.StartedAt,
+		DurationSeconds: run.Duration.Seconds(),
+		Interrupted:     run.Interrupted,
+		BytesFreed:      run.BytesFreed(),
+	}
+
+	for _, result := range run.Results {
+		if result.Success {
+			s.Cleaned++
+			continue
+		}
+		s.Failed++
+		if len(s.Failures) < maxFailures {
+			s.Failures = append(s.Failures, Failure{Cleaner: result.Cleaner, Path: result.Path, Error: result.Error})
+		}
+	}
+	for _, hook := range run.Hooks {
+		if hook.Failed() {
+			s.HooksFailed++
+		}
+	}
+	return s
+}
+
+// Text returns the summary as one line of text, as posted to Slack
+func (s Summary) Text() string {
+	text := fmt.Sprintf("épurer %s on %s freed %s (%d targets cleaned", s.Command, s.Host, utils.FormatBytes(s.BytesFreed), s.Cleaned)
+	if s.Failed > 0 {
+		text += fmt.Sprintf(", %d failed", s.Failed)
+	}
+	text += ")"
+	if s.HooksFailed > 0 {
+		text += fmt.Sprintf(", %d hooks failed", s.HooksFailed)
+	}
+	if s.Interrupted {
+		text += ", interrupted"
+	}
+	return text
+}
+
+// Format returns the payload format for a webhook URL: format if set, or
+// "slack" for Slack incoming webhooks and "json" otherwise
+func Format(rawURL, format string) string {
+	if format != "" {
+		return format
+	}
+	if u, err := url.Parse(rawURL); err == nil && strings.HasSuffix(u.Hostname(), "hooks.slack.com") {
+		return "slack"
+	}
+	return "json"
+}
+
+// Post sends a summary to a webhook, as {"text": ...} in the slack format
+// and as the Summary itself in the json format
+func Post(ctx context.Context, client *http.Client, rawURL, format string, s Summary) error {
+	var payload any = s
+	if Format(rawURL, format) == "slack" {
+		payload = map[string]string{"text": s.Text()}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
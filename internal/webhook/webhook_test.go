@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0SansNom/epurer/internal/history"
+	"github.com/0SansNom/epurer/internal/hooks"
+)
+
+func testRun() history.Run {
+	return history.Run{
+		StartedAt: time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC),
+		Duration:  90 * time.Second,
+		Command:   "smart",
+		Results: []history.Result{
+			{Cleaner: "Frontend", Path: "/p/web/node_modules", BytesFreed: 2_000_000_000, Success: true},
+			{Cleaner: "System Caches", Path: "/c/locked", Error: "permission denied"},
+		},
+		Hooks: []hooks.Result{{Name: "pre_clean", Error: "exit status 1"}},
+	}
+}
+
+func TestNewSummary(t *testing.T) {
+	s := NewSummary(testRun(), "laptop-42", "dev", "1.1.0")
+
+	if s.BytesFreed != 2_000_000_000 || s.Cleaned != 1 || s.Failed != 1 || s.HooksFailed != 1 {
+		t.Errorf("Unexpected totals %+v", s)
+	}
+	if s.DurationSeconds != 90 {
+		t.Errorf("Expected 90 seconds, got %v", s.DurationSeconds)
+	}
+	if len(s.Failures) != 1 || s.Failures[0] != (Failure{Cleaner: "System Caches", Path: "/c/locked", Error: "permission denied"}) {
+		t.Errorf("Unexpected failures %+v", s.Failures)
+	}
+
+	expected := "épurer smart on laptop-42 freed 2.0 GB (1 targets cleaned, 1 failed), 1 hooks failed"
+	if s.Text() != expected {
+		t.Errorf("Expected %q, got %q", expected, s.Text())
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		url, format, expected string
+	}{
+		{"https://hooks.slack.com/services/T/B/X", "", "slack"},
+		{"https://fleet.example.com/epurer", "", "json"},
+		{"https://chat.example.com/hooks/abc", "slack", "slack"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.url, tt.format); got != tt.expected {
+			t.Errorf("Format(%s, %q) = %s, want %s", tt.url, tt.format, got, tt.expected)
+		}
+	}
+}
+
+func TestPost(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	s := NewSummary(testRun(), "laptop-42", "dev", "1.1.0")
+
+	if err := Post(context.Background(), server.Client(), server.URL, "json", s); err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	var posted Summary
+	if err := json.Unmarshal(body, &posted); err != nil || posted.Host != "laptop-42" || posted.Failed != 1 {
+		t.Errorf("Expected the summary as JSON, got %s", body)
+	}
+
+	if err := Post(context.Background(), server.Client(), server.URL, "slack", s); err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	var message map[string]string
+	if err := json.Unmarshal(body, &message); err != nil || message["text"] != s.Text() {
+		t.Errorf("Expected a Slack message, got %s", body)
+	}
+}
+
+func TestPost_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := Post(context.Background(), server.Client(), server.URL, "json", Summary{})
+	if err == nil {
+		t.Errorf("Expected an error for status 403, got %v", err)
+	}
+}
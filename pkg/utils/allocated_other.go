@@ -0,0 +1,11 @@
+//go:build !darwin && !linux
+
+package utils
+
+import "os"
+
+// AllocatedSize returns the file size on platforms where the allocated
+// blocks are not exposed
+func AllocatedSize(info os.FileInfo) int64 {
+	return info.Size()
+}
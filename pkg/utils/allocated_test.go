@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetAllocatedSize_Sparse(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "utils-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// A 1 GB disk image with nothing written takes (almost) no space
+	path := filepath.Join(tmpDir, "disk.raw")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create disk image: %v", err)
+	}
+	if err := f.Truncate(1 << 30); err != nil {
+		f.Close()
+		t.Fatalf("Failed to extend disk image: %v", err)
+	}
+	f.Close()
+
+	apparent, _ := GetDirSize(tmpDir)
+	allocated, err := GetAllocatedSize(tmpDir)
+	if err != nil {
+		t.Fatalf("GetAllocatedSize() error = %v", err)
+	}
+
+	if apparent != 1<<30 {
+		t.Errorf("GetDirSize() = %d, expected %d", apparent, 1<<30)
+	}
+	if allocated >= apparent {
+		t.Errorf("GetAllocatedSize() = %d, expected less than the apparent size %d", allocated, apparent)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build darwin || linux
+
+package utils
+
+import (
+	"os"
+	"syscall"
+)
+
+// AllocatedSize returns the space a file takes on disk, which is less than
+// its size for sparse files such as VM disk images
+func AllocatedSize(info os.FileInfo) int64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int64(stat.Blocks) * 512
+	}
+	return info.Size()
+}
@@ -0,0 +1,14 @@
+//go:build !darwin && !linux
+
+package utils
+
+import (
+	"os"
+	"time"
+)
+
+// AccessTime returns the modification time on platforms where the access
+// time is not exposed
+func AccessTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
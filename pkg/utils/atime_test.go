@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAccessTime(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "utils-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, "file.txt")
+	if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	accessed := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	modified := time.Now().Add(-72 * time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(path, accessed, modified); err != nil {
+		t.Fatalf("Failed to set times: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat: %v", err)
+	}
+
+	got := AccessTime(info)
+	if !got.Equal(accessed) && !got.Equal(modified) {
+		t.Errorf("AccessTime() = %v, want %v (or mtime fallback %v)", got, accessed, modified)
+	}
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ICloudRoots returns the folders synced with iCloud Drive: iCloud Drive
+// itself and, when "Desktop & Documents Folders" is turned on, the Desktop
+// and Documents folders
+func ICloudRoots(home string) []string {
+	cloudDocs := filepath.Join(home, "Library", "Mobile Documents", "com~apple~CloudDocs")
+	roots := []string{filepath.Join(home, "Library", "Mobile Documents")}
+
+	for _, name := range []string{"Desktop", "Documents"} {
+		if PathExists(filepath.Join(cloudDocs, name)) {
+			roots = append(roots, filepath.Join(home, name))
+		}
+	}
+
+	return roots
+}
+
+// IsICloudPath reports whether a path is synced with iCloud Drive, so it can
+// be evicted from the local disk instead of deleted
+func IsICloudPath(path string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+
+	path = filepath.Clean(path)
+	for _, root := range ICloudRoots(home) {
+		if HasPathPrefix(path, root) {
+			return true
+		}
+	}
+	return false
+}
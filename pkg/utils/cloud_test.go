@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// =============================================================================
+// iCloud Tests
+// =============================================================================
+
+func TestICloudRoots(t *testing.T) {
+	home := t.TempDir()
+
+	roots := ICloudRoots(home)
+	if len(roots) != 1 || roots[0] != filepath.Join(home, "Library", "Mobile Documents") {
+		t.Errorf("Expected only iCloud Drive, got %v", roots)
+	}
+
+	// "Desktop & Documents Folders" keeps a Desktop folder in CloudDocs
+	if err := os.MkdirAll(filepath.Join(home, "Library", "Mobile Documents", "com~apple~CloudDocs", "Desktop"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	roots = ICloudRoots(home)
+	if len(roots) != 2 || roots[1] != filepath.Join(home, "Desktop") {
+		t.Errorf("Expected iCloud Drive and Desktop, got %v", roots)
+	}
+}
+
+func TestIsICloudPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{filepath.Join(home, "Library", "Mobile Documents", "com~apple~CloudDocs", "project"), true},
+		{filepath.Join(home, "Library", "Mobile Documents"), true},
+		{filepath.Join(home, "Library", "Mobile Documents Backup"), false},
+		{filepath.Join(home, "Documents", "project"), false},
+		{"relative/path", false},
+		{"docker:images", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsICloudPath(tt.path); got != tt.expected {
+			t.Errorf("IsICloudPath(%q) = %v, expected %v", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestIsDataless_RegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsDataless(info) {
+		t.Error("Expected a local file not to be dataless")
+	}
+}
@@ -0,0 +1,28 @@
+//go:build darwin
+
+package utils
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// sfDataless is the SF_DATALESS file flag set on cloud placeholders whose
+// content has not been downloaded
+const sfDataless = 0x40000000
+
+// IsDataless reports whether a file is a cloud placeholder (iCloud Drive,
+// Dropbox, OneDrive...) that takes no space on the local disk
+func IsDataless(info os.FileInfo) bool {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return stat.Flags&sfDataless != 0
+	}
+	return false
+}
+
+// IsDatalessEntry is IsDataless for a directory entry
+func IsDatalessEntry(d fs.DirEntry) bool {
+	info, err := d.Info()
+	return err == nil && IsDataless(info)
+}
@@ -0,0 +1,19 @@
+//go:build !darwin
+
+package utils
+
+import (
+	"io/fs"
+	"os"
+)
+
+// IsDataless always returns false on platforms without cloud placeholders
+func IsDataless(info os.FileInfo) bool {
+	return false
+}
+
+// IsDatalessEntry always returns false on platforms without cloud
+// placeholders, without reading the entry's metadata
+func IsDatalessEntry(d fs.DirEntry) bool {
+	return false
+}
@@ -0,0 +1,24 @@
+package utils
+
+import "time"
+
+// minETAElapsed is how long a phase must have run before the time it has
+// left is estimated: the first moments of a scan or a deletion say little
+// about its pace
+const minETAElapsed = 2 * time.Second
+
+// EstimateRemaining extrapolates the time a phase has left from the pace it
+// went at so far: done of total units (bytes, files, targets) in elapsed. It
+// returns false until the estimate can be trusted, that is before
+// minETAElapsed or while nothing is done.
+func EstimateRemaining(elapsed time.Duration, done, total int64) (time.Duration, bool) {
+	if elapsed < minETAElapsed || done <= 0 || total <= 0 {
+		return 0, false
+	}
+	if done >= total {
+		return 0, true
+	}
+
+	perUnit := float64(elapsed) / float64(done)
+	return time.Duration(perUnit * float64(total-done)), true
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimateRemaining(t *testing.T) {
+	tests := []struct {
+		name        string
+		elapsed     time.Duration
+		done, total int64
+		want        time.Duration
+		ok          bool
+	}{
+		{"too early", time.Second, 50, 100, 0, false},
+		{"nothing done", time.Minute, 0, 100, 0, false},
+		{"unknown total", time.Minute, 10, 0, 0, false},
+		{"half way", time.Minute, 50, 100, time.Minute, true},
+		{"a quarter", 10 * time.Second, 1 << 30, 4 << 30, 30 * time.Second, true},
+		{"done", time.Minute, 120, 100, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := EstimateRemaining(tt.elapsed, tt.done, tt.total)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("EstimateRemaining() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -7,6 +7,10 @@ import (
 	"strings"
 )
 
+// KeepMarker is the file that protects a directory and its subtree from
+// being scanned or cleaned
+const KeepMarker = ".epurer-keep"
+
 // PathExists checks if a path exists on the filesystem
 func PathExists(path string) bool {
 	_, err := os.Stat(path)
@@ -35,17 +39,61 @@ func ExpandHome(path string) (string, error) {
 	return path, nil
 }
 
-// GetDirSize calculates the total size of a directory recursively
+// GetDirSize calculates the total size of a directory recursively. Cloud
+// placeholders are not counted since they take no space on the local disk.
 func GetDirSize(path string) (int64, error) {
+	size, _, err := GetDirUsage(path)
+	return size, err
+}
+
+// GetDirUsage is GetDirSize also returning the number of files in the
+// directory, counted in the same walk
+func GetDirUsage(path string) (int64, int, error) {
 	var size int64
+	files := 0
 
 	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		Throttle()
 		if err != nil {
 			// Continue on permission errors
 			return nil
 		}
+		if IsDataless(info) {
+			// Listing a dataless folder would download it
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
 		if !info.IsDir() {
 			size += info.Size()
+			files++
+		}
+		return nil
+	})
+
+	return size, files, err
+}
+
+// GetAllocatedSize is GetDirSize counting the space files take on disk
+// rather than their size, so sparse disk images count for what they use.
+// It also accepts a single file.
+func GetAllocatedSize(path string) (int64, error) {
+	var size int64
+
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		Throttle()
+		if err != nil {
+			return nil
+		}
+		if IsDataless(info) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			size += AllocatedSize(info)
 		}
 		return nil
 	})
@@ -53,12 +101,41 @@ func GetDirSize(path string) (int64, error) {
 	return size, err
 }
 
-// SafeRemove removes a path, respecting the dryRun flag
+// SafeRemove removes a path, respecting the dryRun flag. The rest of the
+// tree is still removed when an entry can't be (see RemoveTree).
 func SafeRemove(path string, dryRun bool) error {
 	if dryRun {
 		return nil
 	}
-	return os.RemoveAll(path)
+	return RemoveTree(path).Err()
+}
+
+// HasKeepMarker checks if a directory directly contains a KeepMarker file
+func HasKeepMarker(dir string) bool {
+	_, err := os.Lstat(filepath.Join(dir, KeepMarker))
+	return err == nil
+}
+
+// IsProtected checks if a path or one of its parent directories contains a
+// KeepMarker file. Paths that aren't absolute (e.g. "docker:images") are
+// never protected.
+func IsProtected(path string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+
+	dir := filepath.Clean(path)
+	for {
+		if HasKeepMarker(dir) {
+			return true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
 }
 
 // CommandExists checks if a command is available in PATH
@@ -237,6 +237,47 @@ func TestSafeRemove_NonExistent(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// KeepMarker Tests
+// =============================================================================
+
+func TestIsProtected(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	kept := filepath.Join(tmpDir, "client-app")
+	os.MkdirAll(filepath.Join(kept, "node_modules", "react"), 0755)
+	os.WriteFile(filepath.Join(kept, KeepMarker), []byte{}, 0644)
+	os.WriteFile(filepath.Join(kept, "package.json"), []byte("{}"), 0644)
+
+	other := filepath.Join(tmpDir, "side-project")
+	os.MkdirAll(filepath.Join(other, "node_modules"), 0755)
+
+	tests := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{"marked dir", kept, true},
+		{"subtree dir", filepath.Join(kept, "node_modules", "react"), true},
+		{"file in marked dir", filepath.Join(kept, "package.json"), true},
+		{"unmarked dir", filepath.Join(other, "node_modules"), false},
+		{"parent of marked dir", tmpDir, false},
+		{"non-filesystem path", "docker:images:dangling", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsProtected(tt.path); got != tt.expected {
+				t.Errorf("IsProtected(%s) = %v, want %v", tt.path, got, tt.expected)
+			}
+		})
+	}
+
+	if !HasKeepMarker(kept) || HasKeepMarker(other) {
+		t.Error("HasKeepMarker() should only report the marked directory")
+	}
+}
+
 // =============================================================================
 // CommandExists Tests
 // =============================================================================
@@ -0,0 +1,24 @@
+//go:build darwin
+
+package utils
+
+import (
+	"os"
+	"syscall"
+)
+
+// File flags that prevent an entry from being deleted: UF_IMMUTABLE and
+// UF_APPEND (chflags uchg/uappnd), SF_IMMUTABLE and SF_APPEND (schg/sappnd,
+// only cleared by root)
+const immutableFlags = 0x2 | 0x4 | 0x20000 | 0x40000
+
+// clearImmutable removes the flags that prevent an entry from being deleted.
+// Symlinks are left alone since chflags would change their target.
+func clearImmutable(path string, info os.FileInfo) {
+	if info.Mode()&os.ModeSymlink != 0 {
+		return
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Flags&immutableFlags != 0 {
+		syscall.Chflags(path, int(stat.Flags&^immutableFlags))
+	}
+}
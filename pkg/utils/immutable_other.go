@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package utils
+
+import "os"
+
+// clearImmutable does nothing on platforms without BSD file flags
+func clearImmutable(path string, info os.FileInfo) {}
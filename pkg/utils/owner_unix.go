@@ -0,0 +1,25 @@
+//go:build darwin || linux
+
+package utils
+
+import (
+	"os"
+	"syscall"
+)
+
+// OwnedByUser reports whether a file belongs to the user running the
+// process
+func OwnedByUser(info os.FileInfo) bool {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(stat.Uid) == os.Getuid()
+	}
+	return false
+}
+
+// FileOwner returns the user and group ids owning a file
+func FileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(stat.Uid), int(stat.Gid), true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NFC returns s with its decomposed characters composed, as typed on a
+// keyboard: macOS keeps file names in the form they were created in (HFS+
+// even decomposed them all), so "é" may be one character in a path and two
+// ("e" and a combining accent) on disk
+func NFC(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	return norm.NFC.String(s)
+}
+
+// isASCII checks if s only holds ASCII characters, which need no
+// normalization
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// PathKey returns the form of a path used to compare it with others on the
+// same volume: in NFC, and lowercased if the volume ignores case
+func PathKey(path string, caseInsensitive bool) string {
+	path = NFC(filepath.Clean(path))
+	if caseInsensitive {
+		path = strings.ToLower(path)
+	}
+	return path
+}
+
+// caseInsensitive caches IsCaseInsensitive by directory
+var caseInsensitive sync.Map
+
+// IsCaseInsensitive reports whether the volume path is on ignores case, as
+// APFS and HFS+ do unless formatted otherwise. It is found by looking up the
+// nearest existing folder with its case swapped; without one, macOS volumes
+// are assumed to ignore case and others not to.
+func IsCaseInsensitive(path string) bool {
+	if cached, ok := caseInsensitive.Load(path); ok {
+		return cached.(bool)
+	}
+
+	result := runtime.GOOS == "darwin"
+	for dir := filepath.Clean(path); ; dir = filepath.Dir(dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+
+		info, err := os.Stat(dir)
+		if err != nil {
+			continue
+		}
+		swapped := swapCase(filepath.Base(dir))
+		if swapped == filepath.Base(dir) {
+			continue
+		}
+
+		other, err := os.Stat(filepath.Join(parent, swapped))
+		result = err == nil && os.SameFile(info, other)
+		break
+	}
+
+	caseInsensitive.Store(path, result)
+	return result
+}
+
+// swapCase turns lowercase letters to uppercase and the other way round
+func swapCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return unicode.ToLower(r)
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
+
+// HasPathPrefix reports whether path is dir or lies inside it. The paths are
+// compared as the volume of dir compares names: whatever their Unicode
+// normalization, and ignoring case if the volume does.
+func HasPathPrefix(path, dir string) bool {
+	if hasPathPrefix(path, dir) {
+		return true
+	}
+
+	fold := IsCaseInsensitive(dir)
+	if isASCII(path) && isASCII(dir) {
+		return fold && hasPathPrefixFold(path, dir)
+	}
+	return hasPathPrefix(PathKey(path, fold), PathKey(dir, fold))
+}
+
+// SamePath reports whether two paths name the same file, compared like
+// HasPathPrefix does
+func SamePath(a, b string) bool {
+	if a == b {
+		return true
+	}
+	fold := IsCaseInsensitive(a)
+	return PathKey(a, fold) == PathKey(b, fold)
+}
+
+// hasPathPrefix checks if path is dir or lies inside it, byte for byte
+func hasPathPrefix(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// hasPathPrefixFold checks if path is dir or lies inside it, ignoring case.
+// Both must be ASCII.
+func hasPathPrefixFold(path, dir string) bool {
+	if len(path) < len(dir) || !strings.EqualFold(path[:len(dir)], dir) {
+		return false
+	}
+	return len(path) == len(dir) || path[len(dir)] == filepath.Separator
+}
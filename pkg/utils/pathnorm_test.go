@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNFC(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{"/Users/dev/Projects", "/Users/dev/Projects"},
+		{"/Users/dev/Créations", "/Users/dev/Créations"}, // As HFS+ stores it
+		{"/Users/dev/Créations", "/Users/dev/Créations"},  // Already composed
+		{"Việt", "Việt"},                                // Two marks composed one after the other
+		{"й", "й"},                                       // Cyrillic short i
+		{"が", "が"},                                       // Kana with dakuten
+		{"한", "한"},                                      // Hangul syllable
+		{"q́", "q́"},                                      // No precomposed character
+	}
+
+	for _, tt := range tests {
+		if got := NFC(tt.input); got != tt.expected {
+			t.Errorf("NFC(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestHasPathPrefix_Normalization(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Créations")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	decomposed := filepath.Join(filepath.Dir(dir), "Créations")
+
+	if !HasPathPrefix(filepath.Join(decomposed, "app", "node_modules"), dir) {
+		t.Error("Expected a decomposed path to be inside the composed directory")
+	}
+	if HasPathPrefix(filepath.Join(filepath.Dir(dir), "Creations"), dir) {
+		t.Error("Expected a different name not to match")
+	}
+	if !SamePath(decomposed, dir) {
+		t.Error("Expected both forms to be the same path")
+	}
+}
+
+func TestIsCaseInsensitive(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Projects")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	upper := filepath.Join(filepath.Dir(dir), "PROJECTS")
+
+	// Whether the temporary folder's volume ignores case depends on the machine
+	_, err := os.Stat(upper)
+	expected := err == nil
+
+	if got := IsCaseInsensitive(filepath.Join(dir, "missing")); got != expected {
+		t.Errorf("IsCaseInsensitive() = %v, want %v", got, expected)
+	}
+	if got := HasPathPrefix(filepath.Join(upper, "app"), dir); got != expected {
+		t.Errorf("HasPathPrefix() with another case = %v, want %v", got, expected)
+	}
+}
+
+func TestPathKey(t *testing.T) {
+	if got := PathKey("/Users/Dev/Créations/", true); got != "/users/dev/créations" {
+		t.Errorf("PathKey() = %q", got)
+	}
+	if got := PathKey("/Users/Dev", false); got != "/Users/Dev" {
+		t.Errorf("PathKey() = %q", got)
+	}
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+// ParsePlistDict reads the top-level dictionary of an XML property list.
+// Scalar values are returned as text ("true"/"false" for booleans); nested
+// arrays and dictionaries are skipped.
+func ParsePlistDict(data []byte) (map[string]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	values := make(map[string]string)
+
+	depth := 0 // Element depth below the top-level <dict>
+	inDict := false
+	key := ""
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if inDict {
+				return values, nil
+			}
+			return nil, fmt.Errorf("invalid property list: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !inDict {
+				inDict = t.Name.Local == "dict"
+				continue
+			}
+			depth++
+			if depth > 1 {
+				continue
+			}
+
+			switch t.Name.Local {
+			case "true", "false":
+				if key != "" {
+					values[key] = t.Name.Local
+				}
+				key = ""
+			case "key", "string", "integer", "real", "date":
+				var text string
+				if err := decoder.DecodeElement(&text, &t); err != nil {
+					return nil, fmt.Errorf("invalid property list: %w", err)
+				}
+				depth--
+				if t.Name.Local == "key" {
+					key = text
+				} else if key != "" {
+					values[key] = text
+					key = ""
+				}
+			default:
+				// Nested array or dict: skip its contents
+				key = ""
+			}
+		case xml.EndElement:
+			if !inDict {
+				continue
+			}
+			if depth == 0 {
+				return values, nil
+			}
+			depth--
+		}
+	}
+}
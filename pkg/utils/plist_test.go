@@ -0,0 +1,53 @@
+package utils
+
+import "testing"
+
+func TestParsePlistDict(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>APFSContainerFree</key>
+	<integer>100</integer>
+	<key>APFSPhysicalStores</key>
+	<array>
+		<dict>
+			<key>APFSPhysicalStore</key>
+			<string>disk0s2</string>
+		</dict>
+	</array>
+	<key>Encryption</key>
+	<true/>
+	<key>FreeSpace</key>
+	<integer>250</integer>
+	<key>VolumeName</key>
+	<string>Macintosh HD</string>
+</dict>
+</plist>`)
+
+	values, err := ParsePlistDict(data)
+	if err != nil {
+		t.Fatalf("ParsePlistDict failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"APFSContainerFree": "100",
+		"Encryption":        "true",
+		"FreeSpace":         "250",
+		"VolumeName":        "Macintosh HD",
+	}
+	for key, value := range expected {
+		if values[key] != value {
+			t.Errorf("Expected %s = %q, got %q", key, value, values[key])
+		}
+	}
+	if _, ok := values["APFSPhysicalStore"]; ok {
+		t.Error("Expected nested keys to be skipped")
+	}
+}
+
+func TestParsePlistDict_Invalid(t *testing.T) {
+	if _, err := ParsePlistDict([]byte("not a plist")); err == nil {
+		t.Error("Expected error for invalid property list")
+	}
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// In low priority mode, deletions and directory walks pause for
+// lowPriorityPause after every lowPriorityBatch entries
+const (
+	lowPriorityBatch = 256
+	lowPriorityPause = 20 * time.Millisecond
+)
+
+var (
+	lowPriority atomic.Bool
+	throttled   atomic.Int64 // Entries seen since low priority mode was set
+)
+
+// SetLowPriority lowers the CPU and I/O priority of the process and turns on
+// throttling, so that a background clean leaves the disk to whatever the
+// user is doing (a video call, a build). The error reports a priority that
+// could not be lowered; throttling is on regardless.
+func SetLowPriority() error {
+	lowPriority.Store(true)
+	return lowerPriority()
+}
+
+// Throttle is called for every entry deleted or walked. In low priority mode
+// it pauses after every batch of entries; otherwise it returns at once.
+func Throttle() {
+	if !lowPriority.Load() {
+		return
+	}
+	if throttled.Add(1)%lowPriorityBatch == 0 {
+		time.Sleep(lowPriorityPause)
+	}
+}
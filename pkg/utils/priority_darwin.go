@@ -0,0 +1,17 @@
+//go:build darwin
+
+package utils
+
+import "syscall"
+
+// setpriority arguments putting a process in the background band, as
+// `taskpolicy -b` does: lowest CPU priority and throttled disk I/O
+const (
+	prioDarwinProcess = 4
+	prioDarwinBG      = 0x1000
+)
+
+// lowerPriority moves the process to the background QoS band
+func lowerPriority() error {
+	return syscall.Setpriority(prioDarwinProcess, 0, prioDarwinBG)
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package utils
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// ioprio_set arguments for the idle I/O class, which only gets the disk when
+// no other process wants it
+const (
+	ioprioWhoProcess = 1
+	ioprioClassIdle  = 3 << 13
+)
+
+// lowerPriority sets the lowest nice value and the idle I/O class. Both are
+// per thread on Linux, so every thread of the process is changed; threads
+// started afterwards inherit them.
+func lowerPriority() error {
+	tids := []int{os.Getpid()}
+	if entries, err := os.ReadDir("/proc/self/task"); err == nil {
+		tids = tids[:0]
+		for _, entry := range entries {
+			if tid, err := strconv.Atoi(entry.Name()); err == nil {
+				tids = append(tids, tid)
+			}
+		}
+	}
+
+	var firstErr error
+	for _, tid := range tids {
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, tid, 19); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if _, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), ioprioClassIdle); errno != 0 && firstErr == nil {
+			firstErr = errno
+		}
+	}
+	return firstErr
+}
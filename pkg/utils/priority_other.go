@@ -0,0 +1,9 @@
+//go:build !darwin && !linux
+
+package utils
+
+// lowerPriority does nothing on platforms without a known priority API:
+// only throttling applies
+func lowerPriority() error {
+	return nil
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottle(t *testing.T) {
+	start := time.Now()
+	for i := 0; i < lowPriorityBatch; i++ {
+		Throttle()
+	}
+	if elapsed := time.Since(start); elapsed >= lowPriorityPause {
+		t.Errorf("Throttle paused outside low priority mode (%v)", elapsed)
+	}
+
+	lowPriority.Store(true)
+	defer lowPriority.Store(false)
+	throttled.Store(0)
+
+	start = time.Now()
+	for i := 0; i < lowPriorityBatch; i++ {
+		Throttle()
+	}
+	if elapsed := time.Since(start); elapsed < lowPriorityPause {
+		t.Errorf("Throttle should pause after %d entries, took %v", lowPriorityBatch, elapsed)
+	}
+}
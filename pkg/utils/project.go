@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ProjectMarkers are the files (or folders) whose presence makes a directory
+// a project root. Entries with wildcards are matched as glob patterns.
+var ProjectMarkers = []string{
+	"package.json",
+	"go.mod",
+	"Cargo.toml",
+	"pom.xml",
+	"build.gradle",
+	"build.gradle.kts",
+	"composer.json",
+	"Gemfile",
+	"pyproject.toml",
+	"setup.py",
+	"requirements.txt",
+	"pubspec.yaml",
+	"Package.swift",
+	"Podfile",
+	"*.csproj",
+	"*.fsproj",
+	"*.sln",
+	"*.uproject",
+	"ProjectSettings",
+}
+
+// FindProjectRoot returns the nearest directory above path that contains a
+// project marker, or "" if there is none. The home directory and the
+// filesystem root are never considered projects.
+func FindProjectRoot(path string) string {
+	if !filepath.IsAbs(path) {
+		return ""
+	}
+
+	home, _ := os.UserHomeDir()
+
+	dir := filepath.Dir(filepath.Clean(path))
+	for {
+		parent := filepath.Dir(dir)
+		if dir == home || parent == dir {
+			return ""
+		}
+
+		if IsProjectRoot(dir) {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// ProjectRootOf returns the project a directory belongs to: the directory
+// itself if it contains a project marker, or else FindProjectRoot(dir)
+func ProjectRootOf(dir string) string {
+	if !filepath.IsAbs(dir) {
+		return ""
+	}
+
+	dir = filepath.Clean(dir)
+	home, _ := os.UserHomeDir()
+	if dir != home && filepath.Dir(dir) != dir && IsProjectRoot(dir) {
+		return dir
+	}
+	return FindProjectRoot(dir)
+}
+
+// IsProjectRoot checks if a directory contains one of the ProjectMarkers
+func IsProjectRoot(dir string) bool {
+	for _, marker := range ProjectMarkers {
+		matches, _ := filepath.Glob(filepath.Join(dir, marker))
+		if len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
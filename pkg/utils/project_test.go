@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// =============================================================================
+// FindProjectRoot Tests
+// =============================================================================
+
+func TestFindProjectRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	app := filepath.Join(tmpDir, "my-app")
+	os.MkdirAll(filepath.Join(app, "node_modules", "react"), 0755)
+	os.MkdirAll(filepath.Join(app, "src-tauri", "target"), 0755)
+	os.WriteFile(filepath.Join(app, "package.json"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(app, "src-tauri", "Cargo.toml"), []byte(""), 0644)
+
+	api := filepath.Join(tmpDir, "Api")
+	os.MkdirAll(filepath.Join(api, "obj"), 0755)
+	os.WriteFile(filepath.Join(api, "Api.csproj"), []byte("<Project/>"), 0644)
+
+	loose := filepath.Join(tmpDir, "downloads", "build")
+	os.MkdirAll(loose, 0755)
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"direct child", filepath.Join(app, "node_modules"), app},
+		{"nested", filepath.Join(app, "node_modules", "react"), app},
+		{"nearest manifest wins", filepath.Join(app, "src-tauri", "target"), filepath.Join(app, "src-tauri")},
+		{"glob marker", filepath.Join(api, "obj"), api},
+		{"no project", loose, ""},
+		{"non-filesystem path", "docker:buildcache", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindProjectRoot(tt.path); got != tt.expected {
+				t.Errorf("FindProjectRoot(%s) = %q, want %q", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindProjectRoot_StopsAtHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// A manifest in the home directory doesn't make it a project
+	os.WriteFile(filepath.Join(home, "package.json"), []byte("{}"), 0644)
+	os.MkdirAll(filepath.Join(home, ".npm"), 0755)
+
+	if got := FindProjectRoot(filepath.Join(home, ".npm")); got != "" {
+		t.Errorf("FindProjectRoot(~/.npm) = %q, want \"\"", got)
+	}
+}
+
+func TestProjectRootOf(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	app := filepath.Join(home, "my-app")
+	os.MkdirAll(filepath.Join(app, "src", "components"), 0755)
+	os.WriteFile(filepath.Join(app, "package.json"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(home, "package.json"), []byte("{}"), 0644)
+
+	tests := []struct {
+		name     string
+		dir      string
+		expected string
+	}{
+		{"project root", app, app},
+		{"inside a project", filepath.Join(app, "src", "components"), app},
+		{"home", home, ""},
+		{"relative", "my-app", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProjectRootOf(tt.dir); got != tt.expected {
+				t.Errorf("ProjectRootOf(%s) = %q, want %q", tt.dir, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RemoveBatch is how many files RemoveTreeProgress deletes between two
+// progress reports
+const RemoveBatch = 1000
+
+// RemoveReport is the outcome of RemoveTree
+type RemoveReport struct {
+	Files  int     // Files deleted (directories are not counted)
+	Errors []error // One error per entry that could not be deleted
+}
+
+// Err summarizes the entries that could not be deleted, or returns nil if
+// the whole tree was removed
+func (r RemoveReport) Err() error {
+	switch len(r.Errors) {
+	case 0:
+		return nil
+	case 1:
+		return r.Errors[0]
+	default:
+		return fmt.Errorf("%d entries could not be removed, first: %w", len(r.Errors), r.Errors[0])
+	}
+}
+
+// RemoveTree removes a path and everything below it. Unlike os.RemoveAll it
+// doesn't stop at the first entry it cannot delete: it keeps removing the
+// rest of the tree and reports every failure. Entries locked with the
+// immutable flag (chflags uchg) and read-only directories inside the tree
+// are unlocked and retried once. A path that doesn't exist is not an error.
+func RemoveTree(path string) RemoveReport {
+	return RemoveTreeProgress(path, nil)
+}
+
+// RemoveTreeProgress is RemoveTree calling progress, if set, with the number
+// of files deleted so far after every RemoveBatch of them, so the removal of
+// a large tree can be followed
+func RemoveTreeProgress(path string, progress func(files int)) RemoveReport {
+	var report RemoveReport
+	removeTree(path, path, &report, progress)
+	return report
+}
+
+// removeTree removes path, which is inside root, and reports whether it is
+// gone
+func removeTree(root, path string, report *RemoveReport, progress func(int)) bool {
+	Throttle()
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true
+		}
+		report.Errors = append(report.Errors, err)
+		return false
+	}
+
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil && !os.IsNotExist(err) {
+			unlock(path, info)
+			entries, err = os.ReadDir(path)
+		}
+		if err != nil {
+			if os.IsNotExist(err) {
+				return true
+			}
+			report.Errors = append(report.Errors, err)
+			return false
+		}
+
+		empty := true
+		for _, entry := range entries {
+			if !removeTree(root, filepath.Join(path, entry.Name()), report, progress) {
+				empty = false
+			}
+		}
+		if !empty {
+			// The directory can't go, its failed entries are reported
+			return false
+		}
+	}
+
+	if err := removeEntry(root, path, info); err != nil {
+		report.Errors = append(report.Errors, err)
+		return false
+	}
+	if !info.IsDir() {
+		report.Files++
+		if progress != nil && report.Files%RemoveBatch == 0 {
+			progress(report.Files)
+		}
+	}
+	return true
+}
+
+// removeEntry removes a file or empty directory, unlocking it and its parent
+// directory and trying again if that fails. The parent of root is left
+// untouched: it is not being removed.
+func removeEntry(root, path string, info os.FileInfo) error {
+	err := os.Remove(path)
+	if err == nil || os.IsNotExist(err) {
+		return nil
+	}
+
+	unlock(path, info)
+	if path != root {
+		if parentInfo, statErr := os.Lstat(filepath.Dir(path)); statErr == nil {
+			unlock(filepath.Dir(path), parentInfo)
+		}
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// unlock clears the immutable flags of an entry and gives its owner full
+// access to it if it is a directory, so it can be listed and emptied
+func unlock(path string, info os.FileInfo) {
+	clearImmutable(path, info)
+	if info.IsDir() && info.Mode().Perm()&0700 != 0700 {
+		os.Chmod(path, info.Mode().Perm()|0700)
+	}
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// RemoveTree Tests
+// =============================================================================
+
+func TestRemoveTree(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "target")
+	for _, name := range []string{"a.txt", "sub/b.txt", "sub/deeper/c.txt", "locked/d.txt"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Read-only directories inside the tree are unlocked and emptied
+	if err := os.Chmod(filepath.Join(root, "locked"), 0500); err != nil {
+		t.Fatal(err)
+	}
+
+	report := RemoveTree(root)
+	if err := report.Err(); err != nil {
+		t.Fatalf("RemoveTree() failed: %v", err)
+	}
+	if report.Files != 4 {
+		t.Errorf("Expected 4 files deleted, got %d", report.Files)
+	}
+	if PathExists(root) {
+		t.Error("RemoveTree() left the tree behind")
+	}
+}
+
+func TestRemoveTree_Missing(t *testing.T) {
+	report := RemoveTree(filepath.Join(t.TempDir(), "missing"))
+	if err := report.Err(); err != nil {
+		t.Errorf("Expected no error for a missing path, got %v", err)
+	}
+	if report.Files != 0 {
+		t.Errorf("Expected no files deleted, got %d", report.Files)
+	}
+}
+
+func TestRemoveTreeProgress(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "target")
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := range RemoveBatch*2 + 10 {
+		dir := root
+		if i%2 == 0 {
+			dir = filepath.Join(root, "sub")
+		}
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("%d.txt", i)), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var reported []int
+	report := RemoveTreeProgress(root, func(files int) {
+		reported = append(reported, files)
+	})
+	if err := report.Err(); err != nil {
+		t.Fatalf("RemoveTreeProgress() failed: %v", err)
+	}
+	if len(reported) != 2 || reported[0] != RemoveBatch || reported[1] != RemoveBatch*2 {
+		t.Errorf("Expected progress after each batch, got %v", reported)
+	}
+	if PathExists(root) {
+		t.Error("RemoveTreeProgress() left the tree behind")
+	}
+}
+
+func TestRemoveReport_Err(t *testing.T) {
+	denied := &os.PathError{Op: "remove", Path: "/x/a", Err: os.ErrPermission}
+	busy := &os.PathError{Op: "remove", Path: "/x/b", Err: errors.New("resource busy")}
+
+	if err := (RemoveReport{Files: 3}).Err(); err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+
+	if err := (RemoveReport{Errors: []error{denied}}).Err(); err != denied {
+		t.Errorf("Expected the only error to be returned as is, got %v", err)
+	}
+
+	err := (RemoveReport{Errors: []error{denied, busy}}).Err()
+	if err == nil || !strings.Contains(err.Error(), "2 entries") || !errors.Is(err, os.ErrPermission) {
+		t.Errorf("Expected a summary wrapping the first error, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"path/filepath"
+	"slices"
+)
+
+// Volume is the filesystem a path is on
+type Volume struct {
+	MountPoint string
+	FSType     string // e.g. "apfs", "smbfs"
+	Total      int64
+	Free       int64 // Space available to the user
+}
+
+// networkFSTypes are the filesystems of network mounts, as named by macOS
+// and Linux
+var networkFSTypes = []string{"smbfs", "afpfs", "nfs", "webdav", "ftp", "cifs", "smb2"}
+
+// IsNetwork reports whether the volume is a network mount, where scanning is
+// slow and deleting affects other machines
+func (v Volume) IsNetwork() bool {
+	return slices.Contains(networkFSTypes, v.FSType)
+}
+
+// timeMachineMarkers are the files and folders at the root of a Time
+// Machine backup disk: Backups.backupdb on HFS+ disks, the backup list on
+// APFS ones
+var timeMachineMarkers = []string{
+	"Backups.backupdb",
+	".com.apple.backupd.mvlist.plist",
+}
+
+// IsTimeMachineVolume reports whether the volume mounted at root holds Time
+// Machine backups, which must only be thinned by Time Machine itself
+func IsTimeMachineVolume(root string) bool {
+	for _, marker := range timeMachineMarkers {
+		if PathExists(filepath.Join(root, marker)) {
+			return true
+		}
+	}
+	return false
+}
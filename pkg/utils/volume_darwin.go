@@ -0,0 +1,32 @@
+//go:build darwin
+
+package utils
+
+import "syscall"
+
+// VolumeOf returns the volume path is on
+func VolumeOf(path string) (Volume, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return Volume{}, err
+	}
+
+	return Volume{
+		MountPoint: cString(stat.Mntonname[:]),
+		FSType:     cString(stat.Fstypename[:]),
+		Total:      int64(stat.Blocks) * int64(stat.Bsize),
+		Free:       int64(stat.Bavail) * int64(stat.Bsize),
+	}, nil
+}
+
+// cString converts a NUL-terminated C char array to a string
+func cString(chars []int8) string {
+	b := make([]byte, 0, len(chars))
+	for _, c := range chars {
+		if c == 0 {
+			break
+		}
+		b = append(b, byte(c))
+	}
+	return string(b)
+}
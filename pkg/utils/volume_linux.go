@@ -0,0 +1,85 @@
+//go:build linux
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// linuxFSTypes names the filesystem magic numbers statfs reports
+var linuxFSTypes = map[int64]string{
+	0xEF53:     "ext4",
+	0x9123683E: "btrfs",
+	0x58465342: "xfs",
+	0x01021994: "tmpfs",
+	0x794C7630: "overlay",
+	0x6969:     "nfs",
+	0x517B:     "smbfs",
+	0xFF534D42: "cifs",
+	0xFE534D42: "smb2",
+}
+
+// VolumeOf returns the volume path is on
+func VolumeOf(path string) (Volume, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return Volume{}, err
+	}
+
+	fsType, ok := linuxFSTypes[int64(stat.Type)]
+	if !ok {
+		fsType = fmt.Sprintf("0x%x", stat.Type)
+	}
+
+	mountPoint, err := mountPointOf(path)
+	if err != nil {
+		return Volume{}, err
+	}
+
+	return Volume{
+		MountPoint: mountPoint,
+		FSType:     fsType,
+		Total:      int64(stat.Blocks) * int64(stat.Bsize),
+		Free:       int64(stat.Bavail) * int64(stat.Bsize),
+	}, nil
+}
+
+// mountPointOf returns the top directory of the filesystem path is on: the
+// last one going up before the device changes
+func mountPointOf(path string) (string, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	dev, err := deviceOf(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir, nil
+		}
+		if parentDev, err := deviceOf(parent); err != nil || parentDev != dev {
+			return dir, nil
+		}
+		dir = parent
+	}
+}
+
+// deviceOf returns the device a file is on
+func deviceOf(path string) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("no device for %s", path)
+	}
+	return stat.Dev, nil
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !linux
+
+package utils
+
+import "errors"
+
+// VolumeOf is not supported on this platform
+func VolumeOf(path string) (Volume, error) {
+	return Volume{}, errors.New("volume information is not supported on this platform")
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVolumeOf(t *testing.T) {
+	dir := t.TempDir()
+
+	v, err := VolumeOf(dir)
+	if err != nil {
+		t.Fatalf("VolumeOf() error = %v", err)
+	}
+	if v.MountPoint == "" || v.FSType == "" || v.Total <= 0 || v.Free > v.Total {
+		t.Errorf("Unexpected volume %+v", v)
+	}
+	if rel, err := filepath.Rel(v.MountPoint, dir); err != nil || rel == ".." || filepath.IsAbs(rel) {
+		t.Errorf("Expected %s to be under the mount point %s", dir, v.MountPoint)
+	}
+}
+
+func TestVolume_IsNetwork(t *testing.T) {
+	if !(Volume{FSType: "smbfs"}).IsNetwork() || (Volume{FSType: "apfs"}).IsNetwork() {
+		t.Error("Expected only smbfs to be a network volume")
+	}
+}
+
+func TestIsTimeMachineVolume(t *testing.T) {
+	root := t.TempDir()
+	if IsTimeMachineVolume(root) {
+		t.Error("Expected an empty volume not to be a Time Machine volume")
+	}
+
+	if err := os.Mkdir(filepath.Join(root, "Backups.backupdb"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if !IsTimeMachineVolume(root) {
+		t.Error("Expected a volume with Backups.backupdb to be a Time Machine volume")
+	}
+}